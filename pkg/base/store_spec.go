@@ -179,6 +179,10 @@ type StoreSpec struct {
 	// ExtraOptions is a serialized protobuf set by Go CCL code and passed through
 	// to C CCL code.
 	ExtraOptions []byte
+	// RaftLogPath is the directory of a dedicated storage engine holding the
+	// store's Raft log. If empty, the Raft log is kept in the store's engine
+	// alongside the replicated data.
+	RaftLogPath string
 }
 
 // String returns a fully parsable version of the store spec.
@@ -206,6 +210,9 @@ func (ss StoreSpec) String() string {
 		}
 		fmt.Fprintf(&buffer, ",")
 	}
+	if len(ss.RaftLogPath) != 0 {
+		fmt.Fprintf(&buffer, "raft-log-path=%s,", ss.RaftLogPath)
+	}
 	// Trim the extra comma from the end if it exists.
 	if l := buffer.Len(); l > 0 {
 		buffer.Truncate(l - 1)
@@ -229,7 +236,7 @@ var fractionRegex = regexp.MustCompile(`^([-]?([0-9]+\.[0-9]*|[0-9]*\.[0-9]+|[0-
 
 // NewStoreSpec parses the string passed into a --store flag and returns a
 // StoreSpec if it is correctly parsed.
-// There are four possible fields that can be passed in, comma separated:
+// There are five possible fields that can be passed in, comma separated:
 // - path=xxx The directory in which to the rocks db instance should be
 //   located, required unless using a in memory storage.
 // - type=mem This specifies that the store is an in memory storage instead of
@@ -243,6 +250,8 @@ var fractionRegex = regexp.MustCompile(`^([-]?([0-9]+\.[0-9]*|[0-9]*\.[0-9]+|[0-
 //   - 20%             -> 20% of the available space
 //   - 0.2             -> 20% of the available space
 // - attrs=xxx:yyy:zzz A colon separated list of optional attributes.
+// - raft-log-path=xxx The optional directory in which a dedicated storage
+//   engine holding the store's Raft log should be located.
 // Note that commas are forbidden within any field name or value.
 func NewStoreSpec(value string) (StoreSpec, error) {
 	const pathField = "path"
@@ -318,6 +327,12 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 			}
 		case "rocksdb":
 			ss.RocksDBOptions = value
+		case "raft-log-path":
+			var err error
+			ss.RaftLogPath, err = GetAbsoluteStorePath(field, value)
+			if err != nil {
+				return StoreSpec{}, err
+			}
 		default:
 			return StoreSpec{}, fmt.Errorf("%s is not a valid store field", field)
 		}
@@ -330,8 +345,13 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 		if ss.Size.Percent == 0 && ss.Size.InBytes == 0 {
 			return StoreSpec{}, fmt.Errorf("size must be specified for an in memory store")
 		}
+		if ss.RaftLogPath != "" {
+			return StoreSpec{}, fmt.Errorf("raft-log-path specified for in memory store")
+		}
 	} else if ss.Path == "" {
 		return StoreSpec{}, fmt.Errorf("no path specified")
+	} else if ss.RaftLogPath == ss.Path {
+		return StoreSpec{}, fmt.Errorf("raft-log-path must differ from the store path")
 	}
 	return ss, nil
 }
@@ -121,6 +121,12 @@ func TestNewStoreSpec(t *testing.T) {
 		// RocksDB
 		{"path=/,rocksdb=key1=val1;key2=val2", "", StoreSpec{Path: "/", RocksDBOptions: "key1=val1;key2=val2"}},
 
+		// Raft log
+		{"path=/mnt/hda1,raft-log-path=/mnt/ssd01", "", StoreSpec{Path: "/mnt/hda1", RaftLogPath: "/mnt/ssd01"}},
+		{"path=/mnt/hda1,raft-log-path=", "no value specified for raft-log-path", StoreSpec{}},
+		{"path=/mnt/hda1,raft-log-path=/mnt/hda1", "raft-log-path must differ from the store path", StoreSpec{}},
+		{"type=mem,size=20GiB,raft-log-path=/mnt/ssd01", "raft-log-path specified for in memory store", StoreSpec{}},
+
 		// all together
 		{"path=/mnt/hda1,attrs=hdd:ssd,size=20GiB", "", StoreSpec{
 			Path:       "/mnt/hda1",
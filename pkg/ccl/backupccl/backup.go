@@ -15,7 +15,9 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"net/url"
+	"path"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/build"
@@ -529,9 +531,10 @@ func backupJobDescription(
 	opts map[string]string,
 ) (string, error) {
 	b := &tree.Backup{
-		AsOf:    backup.AsOf,
-		Options: optsToKVOptions(opts),
-		Targets: backup.Targets,
+		AsOf:               backup.AsOf,
+		Options:            optsToKVOptions(opts),
+		Targets:            backup.Targets,
+		DescriptorCoverage: backup.DescriptorCoverage,
 	}
 
 	for _, t := range to {
@@ -1046,6 +1049,36 @@ func backupPlanHook(
 			}
 		}
 
+		if backupStmt.Nested {
+			// BACKUP INTO writes to a subdirectory of the collection named after
+			// the end time of the backup: a new one for a full backup, or one
+			// nested in the latest full backup's when appending an incremental.
+			subdir := endTime.GoTime().Format(collectionFullDirFormat)
+			if backupStmt.AppendToLatest {
+				chain, err := findBackupChain(
+					ctx, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, to, hlc.Timestamp{}, /* asOf */
+				)
+				if err != nil {
+					return err
+				}
+				defaultURI, _, err := getURIsByLocalityKV(to)
+				if err != nil {
+					return err
+				}
+				for _, dir := range chain {
+					prevURI, err := appendPaths([]string{defaultURI}, dir)
+					if err != nil {
+						return err
+					}
+					incrementalFrom = append(incrementalFrom, prevURI[0])
+				}
+				subdir = path.Join(chain[0], endTime.GoTime().Format(collectionIncDirFormat))
+			}
+			if to, err = appendPaths(to, subdir); err != nil {
+				return err
+			}
+		}
+
 		defaultURI, urisByLocalityKV, err := getURIsByLocalityKV(to)
 		if err != nil {
 			return nil
@@ -1066,9 +1099,22 @@ func backupPlanHook(
 			mvccFilter = MVCCFilter_All
 		}
 
-		targetDescs, completeDBs, err := ResolveTargetsToDescriptors(ctx, p, endTime, backupStmt.Targets)
-		if err != nil {
-			return err
+		var targetDescs []sqlbase.Descriptor
+		var completeDBs []sqlbase.ID
+		switch backupStmt.DescriptorCoverage {
+		case tree.RequestedDescriptors:
+			targetDescs, completeDBs, err = ResolveTargetsToDescriptors(ctx, p, endTime, backupStmt.Targets)
+			if err != nil {
+				return err
+			}
+		case tree.AllDescriptors:
+			allDescs, err := loadAllDescs(ctx, p.ExecCfg().DB, endTime)
+			if err != nil {
+				return err
+			}
+			targetDescs, completeDBs = fullClusterTargets(allDescs)
+		default:
+			return errors.AssertionFailedf("unexpected descriptor coverage %v", backupStmt.DescriptorCoverage)
 		}
 
 		statsCache := p.ExecCfg().TableStatsCache
@@ -1122,6 +1168,12 @@ func backupPlanHook(
 				if !desc.ClusterID.Equal(clusterID) {
 					return errors.Newf("previous BACKUP %q belongs to cluster %s", uri, desc.ClusterID.String())
 				}
+				// A full cluster backup needs to be able to restore the whole cluster,
+				// so the backups it builds upon need to cover the whole cluster too.
+				if backupStmt.DescriptorCoverage == tree.AllDescriptors &&
+					desc.DescriptorCoverage != tree.AllDescriptors {
+					return errors.Errorf("previous BACKUP %q is not a full cluster backup", uri)
+				}
 				prevBackups[i] = desc
 			}
 		}
@@ -1224,19 +1276,20 @@ func backupPlanHook(
 		// of requiring full backups after schema changes remains.
 
 		backupDesc := BackupDescriptor{
-			StartTime:         startTime,
-			EndTime:           endTime,
-			MVCCFilter:        mvccFilter,
-			Descriptors:       targetDescs,
-			DescriptorChanges: revs,
-			CompleteDbs:       completeDBs,
-			Spans:             spans,
-			IntroducedSpans:   newSpans,
-			FormatVersion:     BackupFormatDescriptorTrackingVersion,
-			BuildInfo:         build.GetInfo(),
-			NodeID:            p.ExecCfg().NodeID.Get(),
-			ClusterID:         p.ExecCfg().ClusterID(),
-			Statistics:        tableStatistics,
+			StartTime:          startTime,
+			EndTime:            endTime,
+			MVCCFilter:         mvccFilter,
+			Descriptors:        targetDescs,
+			DescriptorChanges:  revs,
+			CompleteDbs:        completeDBs,
+			Spans:              spans,
+			IntroducedSpans:    newSpans,
+			FormatVersion:      BackupFormatDescriptorTrackingVersion,
+			BuildInfo:          build.GetInfo(),
+			NodeID:             p.ExecCfg().NodeID.Get(),
+			ClusterID:          p.ExecCfg().ClusterID(),
+			Statistics:         tableStatistics,
+			DescriptorCoverage: backupStmt.DescriptorCoverage,
 		}
 
 		// Sanity check: re-run the validation that RESTORE will do, but this time
@@ -1558,6 +1611,182 @@ func getURIsByLocalityKV(to []string) (string, map[string]string, error) {
 	return defaultURI, urisByLocalityKV, nil
 }
 
+const (
+	// collectionFullDirFormat is the format of the name of the subdirectory of
+	// a collection that BACKUP INTO writes a full backup to, based on the end
+	// time of the backup.
+	collectionFullDirFormat = "/2006/01/02-150405.00"
+	// collectionFullDirGlob matches the subdirectories of a collection named
+	// after collectionFullDirFormat.
+	collectionFullDirGlob = "/*/*/*"
+	// collectionIncDirFormat is the format of the name of the subdirectory of a
+	// full backup's directory that BACKUP INTO LATEST IN writes an incremental
+	// backup to, based on the end time of the backup.
+	collectionIncDirFormat = "/20060102/150405.00"
+	// collectionIncDirGlob matches the subdirectories of a full backup's
+	// directory named after collectionIncDirFormat.
+	collectionIncDirGlob = "/*/*"
+)
+
+// appendPaths appends subdir to the path of each of the URIs, leaving the rest
+// of them, such as the parameters holding credentials, untouched.
+func appendPaths(uris []string, subdir string) ([]string, error) {
+	res := make([]string, len(uris))
+	for i, uri := range uris {
+		parsedURI, err := url.Parse(uri)
+		if err != nil {
+			return nil, err
+		}
+		parsedURI.Path = path.Join(parsedURI.Path, subdir)
+		res[i] = parsedURI.String()
+	}
+	return res, nil
+}
+
+// findBackupDirs returns the subdirectories of uri that match glob and contain
+// a BACKUP manifest, sorted by name.
+func findBackupDirs(
+	ctx context.Context,
+	makeExternalStorageFromURI cloud.ExternalStorageFromURIFactory,
+	uri string,
+	glob string,
+) ([]string, error) {
+	globURI, err := appendPaths([]string{uri}, path.Join(glob, BackupDescriptorName))
+	if err != nil {
+		return nil, err
+	}
+	store, err := makeExternalStorageFromURI(ctx, globURI[0])
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	files, err := store.ListFiles(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing backups")
+	}
+
+	parsedURI, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.TrimSuffix(parsedURI.Path, "/") + "/"
+	var dirs []string
+	for _, file := range files {
+		parsedFile, err := url.Parse(file)
+		if err != nil {
+			return nil, err
+		}
+		dir := "/" + strings.TrimPrefix(path.Dir(parsedFile.Path), "/")
+		if !strings.HasPrefix(dir, prefix) {
+			continue
+		}
+		dirs = append(dirs, strings.TrimPrefix(dir, prefix[:len(prefix)-1]))
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// backupInDir is a backup found in a subdirectory of a collection.
+type backupInDir struct {
+	dir  string
+	desc BackupDescriptor
+}
+
+// readBackupsInDirs reads the BACKUP manifests in the given subdirectories of
+// uri and returns the backups ordered by their end times.
+func readBackupsInDirs(
+	ctx context.Context,
+	makeExternalStorageFromURI cloud.ExternalStorageFromURIFactory,
+	uri string,
+	dirs []string,
+) ([]backupInDir, error) {
+	backups := make([]backupInDir, len(dirs))
+	for i, dir := range dirs {
+		dirURI, err := appendPaths([]string{uri}, dir)
+		if err != nil {
+			return nil, err
+		}
+		desc, err := ReadBackupDescriptorFromURI(ctx, dirURI[0], makeExternalStorageFromURI)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading backup in %s", dir)
+		}
+		backups[i] = backupInDir{dir: dir, desc: desc}
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].desc.EndTime.Less(backups[j].desc.EndTime)
+	})
+	return backups, nil
+}
+
+// findBackupChain returns the subdirectories of the (possibly partitioned)
+// collection that hold a full backup and the incremental backups appended to
+// it, in order. Without asOf, this is the latest full backup and each of its
+// incremental backups. With asOf, it is the latest full backup that ends at or
+// before asOf, or the earliest one if they all end after it, and its
+// incremental backups up to the first one that ends at or after asOf.
+//
+// The backups are ordered by the end times in their manifests, and each
+// incremental backup must start at the end time of the one before it.
+func findBackupChain(
+	ctx context.Context,
+	makeExternalStorageFromURI cloud.ExternalStorageFromURIFactory,
+	collection []string,
+	asOf hlc.Timestamp,
+) ([]string, error) {
+	defaultURI, _, err := getURIsByLocalityKV(collection)
+	if err != nil {
+		return nil, err
+	}
+	fullDirs, err := findBackupDirs(ctx, makeExternalStorageFromURI, defaultURI, collectionFullDirGlob)
+	if err != nil {
+		return nil, err
+	}
+	if len(fullDirs) == 0 {
+		return nil, errors.New("no full backups found in the collection")
+	}
+	fulls, err := readBackupsInDirs(ctx, makeExternalStorageFromURI, defaultURI, fullDirs)
+	if err != nil {
+		return nil, err
+	}
+	full := fulls[0]
+	for _, b := range fulls[1:] {
+		if !asOf.IsEmpty() && asOf.Less(b.desc.EndTime) {
+			break
+		}
+		full = b
+	}
+
+	fullURI, err := appendPaths([]string{defaultURI}, full.dir)
+	if err != nil {
+		return nil, err
+	}
+	incDirs, err := findBackupDirs(ctx, makeExternalStorageFromURI, fullURI[0], collectionIncDirGlob)
+	if err != nil {
+		return nil, err
+	}
+	incs, err := readBackupsInDirs(ctx, makeExternalStorageFromURI, fullURI[0], incDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := []string{full.dir}
+	prev := full
+	for _, inc := range incs {
+		if !asOf.IsEmpty() && !prev.desc.EndTime.Less(asOf) {
+			break
+		}
+		if inc.desc.StartTime != prev.desc.EndTime {
+			return nil, errors.Errorf(
+				"backup in %s starts at %s, but the backup before it in the chain ends at %s",
+				path.Join(full.dir, inc.dir), inc.desc.StartTime, prev.desc.EndTime,
+			)
+		}
+		chain = append(chain, path.Join(full.dir, inc.dir))
+		prev = inc
+	}
+	return chain, nil
+}
+
 // maybeDowngradeTableDescsInBackupDescriptor returns the backup descriptor
 // with its table descriptors downgraded to the older 19.1-style foreign key
 // representation, if they are not already downgraded, and if the cluster is not
@@ -535,6 +535,122 @@ func TestBackupRestoreSystemTables(t *testing.T) {
 	)
 }
 
+func TestFullClusterBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 10
+	_, _, sqlDB, tempDir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	// Populate the system tables that a full cluster backup covers.
+	sqlDB.Exec(t, `CREATE USER maxroach`)
+	sqlDB.Exec(t, `CREATE ROLE auditors`)
+	sqlDB.Exec(t, `GRANT auditors TO maxroach`)
+	sqlDB.Exec(t, `GRANT SELECT ON data.bank TO auditors`)
+	sqlDB.Exec(t, `ALTER TABLE data.bank CONFIGURE ZONE USING gc.ttlseconds = 3600`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING sql.defaults.distsql = 'on'`)
+	sqlDB.Exec(t, `COMMENT ON TABLE data.bank IS 'accounts'`)
+	sqlDB.Exec(t, `CREATE DATABASE data2`)
+	sqlDB.Exec(t, `CREATE TABLE data2.foo (a INT PRIMARY KEY)`)
+
+	sqlDB.Exec(t, `BACKUP TO $1`, localFoo)
+
+	tcRestore := testcluster.StartTestCluster(t, singleNode, base.TestClusterArgs{
+		ServerArgs: base.TestServerArgs{ExternalIODir: tempDir},
+	})
+	defer tcRestore.Stopper().Stop(context.Background())
+	sqlDBRestore := sqlutils.MakeSQLRunner(tcRestore.Conns[0])
+
+	sqlDBRestore.Exec(t, `RESTORE FROM $1`, localFoo)
+
+	for _, q := range []string{
+		`SELECT * FROM data.bank`,
+		`SELECT * FROM data2.foo`,
+		`SELECT username, "isRole" FROM system.users`,
+		`SELECT role, member FROM system.role_members`,
+		`SHOW GRANTS ON data.bank`,
+		`SHOW ZONE CONFIGURATION FOR TABLE data.bank`,
+		`SELECT value FROM system.settings WHERE name = 'sql.defaults.distsql'`,
+		`SELECT comment FROM system.comments`,
+		`SELECT id FROM system.namespace WHERE name = 'bank'`,
+	} {
+		sqlDBRestore.CheckQueryResults(t, q, sqlDB.QueryStr(t, q))
+	}
+
+	// The system tables are restored through a temporary database, which is
+	// dropped once the restore is complete.
+	sqlDBRestore.CheckQueryResults(t,
+		`SELECT count(*) FROM [SHOW DATABASES] WHERE database_name = 'crdb_temp_system'`,
+		[][]string{{"0"}},
+	)
+
+	// A full cluster restore needs an empty cluster.
+	sqlDBRestore.ExpectErr(t, `full cluster restore can only be run on a cluster with no tables or databases`,
+		`RESTORE FROM $1`, localFoo)
+
+	// A full cluster restore needs a full cluster backup.
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1`, localFoo+"/data")
+	sqlDB.ExpectErr(t, `full cluster RESTORE can only be used on full cluster BACKUP files`,
+		`RESTORE FROM $1`, localFoo+"/data")
+
+	// Incremental full cluster backups need to build on full cluster backups.
+	sqlDB.ExpectErr(t, `is not a full cluster backup`,
+		`BACKUP TO $1 INCREMENTAL FROM $2`, localFoo+"/inc", localFoo+"/data")
+}
+
+func TestBackupRestoreCollection(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 10
+	_, _, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	sqlDB.ExpectErr(t, "no full backups found in the collection",
+		`BACKUP DATABASE data INTO LATEST IN $1`, localFoo)
+	sqlDB.ExpectErr(t, "no full backups found in the collection",
+		`RESTORE data.bank FROM LATEST IN $1`, localFoo)
+
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1 WITH revision_history`, localFoo)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = 1 WHERE id = 1`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1 WITH revision_history`, localFoo)
+
+	var ts string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&ts)
+	expectedAsOf := sqlDB.QueryStr(t, `SELECT * FROM data.bank`)
+
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = 2 WHERE id = 2`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1 WITH revision_history`, localFoo)
+
+	// The incremental backups are nested in the full backup's directory.
+	if backups := sqlDB.QueryStr(t, `SHOW BACKUPS IN $1`, localFoo); len(backups) != 1 {
+		t.Fatalf("expected 1 full backup in the collection, got %v", backups)
+	}
+
+	sqlDB.Exec(t, `CREATE DATABASE latest`)
+	sqlDB.Exec(t, `RESTORE data.bank FROM LATEST IN $1 WITH into_db = 'latest'`, localFoo)
+	sqlDB.CheckQueryResults(t, `SELECT * FROM latest.bank`, sqlDB.QueryStr(t, `SELECT * FROM data.bank`))
+
+	sqlDB.Exec(t, `CREATE DATABASE asof`)
+	sqlDB.Exec(t, fmt.Sprintf(
+		`RESTORE data.bank FROM LATEST IN $1 AS OF SYSTEM TIME %s WITH into_db = 'asof'`, ts,
+	), localFoo)
+	sqlDB.CheckQueryResults(t, `SELECT * FROM asof.bank`, expectedAsOf)
+
+	// A new full backup starts a new chain, which LATEST then refers to.
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, localFoo)
+	if backups := sqlDB.QueryStr(t, `SHOW BACKUPS IN $1`, localFoo); len(backups) != 2 {
+		t.Fatalf("expected 2 full backups in the collection, got %v", backups)
+	}
+
+	// A time before the new full backup is still restored from the chain that
+	// covers it.
+	sqlDB.Exec(t, `CREATE DATABASE asof_older`)
+	sqlDB.Exec(t, fmt.Sprintf(
+		`RESTORE data.bank FROM LATEST IN $1 AS OF SYSTEM TIME %s WITH into_db = 'asof_older'`, ts,
+	), localFoo)
+	sqlDB.CheckQueryResults(t, `SELECT * FROM asof_older.bank`, expectedAsOf)
+}
+
 func TestBackupRestoreSystemJobs(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -11,6 +11,7 @@ package backupccl
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"math"
 	"runtime"
 	"sort"
@@ -33,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
@@ -57,6 +59,11 @@ const (
 	restoreOptSkipMissingFKs       = "skip_missing_foreign_keys"
 	restoreOptSkipMissingSequences = "skip_missing_sequences"
 	restoreOptSkipMissingViews     = "skip_missing_views"
+
+	// restoreTempSystemDB is the name of the temporary database into which a
+	// full cluster restore restores the system tables, before copying their
+	// contents over the real system tables.
+	restoreTempSystemDB = "crdb_temp_system"
 )
 
 var restoreOptionExpectValues = map[string]sql.KVStringOptValidate{
@@ -208,9 +215,19 @@ func selectTargets(
 	p sql.PlanHookState,
 	backupDescs []BackupDescriptor,
 	targets tree.TargetList,
+	descriptorCoverage tree.DescriptorCoverage,
 	asOf hlc.Timestamp,
 ) ([]sqlbase.Descriptor, []*sqlbase.DatabaseDescriptor, error) {
 	allDescs, lastBackupDesc := loadSQLDescsFromBackupsAtTime(backupDescs, asOf)
+
+	if descriptorCoverage == tree.AllDescriptors {
+		if lastBackupDesc.DescriptorCoverage != tree.AllDescriptors {
+			return nil, nil, errors.Errorf(
+				"full cluster RESTORE can only be used on full cluster BACKUP files")
+		}
+		descs, restoreDBs := fullClusterTargetsRestore(allDescs)
+		return descs, restoreDBs, nil
+	}
 	matched, err := descriptorsMatchingTargets(ctx,
 		p.CurrentDatabase(), p.CurrentSearchPath(), allDescs, targets)
 	if err != nil {
@@ -476,6 +493,130 @@ func allocateTableRewrites(
 	return tableRewrites, nil
 }
 
+// allocateFullClusterTableRewrites determines the TableRewrites of a full
+// cluster restore. The databases and tables of the backup keep their IDs, so
+// that the references to them in the restored system tables, such as zone
+// configurations and comments, remain valid. The system tables are restored
+// into a temporary database with new IDs, and their contents are copied over
+// the real system tables once the restore is complete.
+func allocateFullClusterTableRewrites(
+	ctx context.Context,
+	p sql.PlanHookState,
+	databasesByID map[sqlbase.ID]*sql.DatabaseDescriptor,
+	tablesByID map[sqlbase.ID]*sql.TableDescriptor,
+) (TableRewriteMap, error) {
+	tableRewrites := make(TableRewriteMap)
+
+	// Make sure that the IDs generated below for the temporary system database
+	// and tables don't collide with the IDs that are preserved.
+	var maxDescID sqlbase.ID
+	for id := range databasesByID {
+		if id > maxDescID {
+			maxDescID = id
+		}
+	}
+	for id := range tablesByID {
+		if id > maxDescID {
+			maxDescID = id
+		}
+	}
+	if err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		nextID, err := txn.Get(ctx, keys.DescIDGenerator)
+		if err != nil {
+			return err
+		}
+		if nextID.ValueInt() <= int64(maxDescID) {
+			return txn.Put(ctx, keys.DescIDGenerator, int64(maxDescID)+1)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	tempSystemDBID, err := sql.GenerateUniqueDescID(ctx, p.ExecCfg().DB)
+	if err != nil {
+		return nil, err
+	}
+	for id := range databasesByID {
+		if id == keys.SystemDatabaseID {
+			tableRewrites[id] = &jobspb.RestoreDetails_TableRewrite{TableID: tempSystemDBID}
+		} else {
+			tableRewrites[id] = &jobspb.RestoreDetails_TableRewrite{TableID: id}
+		}
+	}
+
+	// NB: As in allocateTableRewrites, the new IDs of the system tables must be
+	// ordered like the old ones.
+	tables := make([]*sqlbase.TableDescriptor, 0, len(tablesByID))
+	for _, table := range tablesByID {
+		tables = append(tables, table)
+	}
+	sort.Sort(sqlbase.TableDescriptors(tables))
+	for _, table := range tables {
+		if table.ParentID != keys.SystemDatabaseID {
+			tableRewrites[table.ID] = &jobspb.RestoreDetails_TableRewrite{
+				TableID: table.ID, ParentID: table.ParentID,
+			}
+			continue
+		}
+		newTableID, err := sql.GenerateUniqueDescID(ctx, p.ExecCfg().DB)
+		if err != nil {
+			return nil, err
+		}
+		tableRewrites[table.ID] = &jobspb.RestoreDetails_TableRewrite{
+			TableID: newTableID, ParentID: tempSystemDBID,
+		}
+	}
+
+	return tableRewrites, nil
+}
+
+// checkClusterEmpty returns an error if the cluster has any databases or
+// tables other than the ones it was created with, since a full cluster restore
+// can only restore into an empty cluster.
+func checkClusterEmpty(ctx context.Context, p sql.PlanHookState) error {
+	allDescs, err := loadAllDescs(ctx, p.ExecCfg().DB, p.ExecCfg().Clock.Now())
+	if err != nil {
+		return err
+	}
+	for _, desc := range allDescs {
+		if desc.GetID() < keys.MinNonPredefinedUserDescID {
+			continue
+		}
+		if tableDesc := desc.Table(hlc.Timestamp{}); tableDesc != nil && tableDesc.Dropped() {
+			continue
+		}
+		return errors.Errorf(
+			"full cluster restore can only be run on a cluster with no tables or databases but found %q descriptor",
+			desc.GetName())
+	}
+	return nil
+}
+
+// dropDefaultUserDBs removes the databases that a cluster is created with so
+// that a full cluster restore can restore the ones of the backed up cluster,
+// which likely have the same IDs. checkClusterEmpty ensures that they don't
+// contain any tables.
+func dropDefaultUserDBs(ctx context.Context, txn *client.Txn) error {
+	b := txn.NewBatch()
+	for _, name := range []string{sessiondata.DefaultDatabaseName, sessiondata.PgDatabaseName} {
+		dKey := sqlbase.NewDatabaseKey(name).Key()
+		existingDatabaseID, err := txn.Get(ctx, dKey)
+		if err != nil {
+			return err
+		}
+		if existingDatabaseID.Value == nil {
+			continue
+		}
+		id, err := existingDatabaseID.Value.GetInt()
+		if err != nil {
+			return err
+		}
+		b.Del(dKey, sqlbase.MakeDescMetadataKey(sqlbase.ID(id)))
+	}
+	return txn.Run(ctx, b)
+}
+
 // CheckTableExists returns an error if a table already exists with given
 // parent and name.
 func CheckTableExists(
@@ -1034,6 +1175,7 @@ func WriteTableDescs(
 	txn *client.Txn,
 	databases []*sqlbase.DatabaseDescriptor,
 	tables []*sqlbase.TableDescriptor,
+	descCoverage tree.DescriptorCoverage,
 	user string,
 	settings *cluster.Settings,
 	extra []roachpb.KeyValue,
@@ -1045,7 +1187,9 @@ func WriteTableDescs(
 		wroteDBs := make(map[sqlbase.ID]*sqlbase.DatabaseDescriptor)
 		for _, desc := range databases {
 			// TODO(dt): support restoring privs.
-			desc.Privileges = sqlbase.NewDefaultPrivilegeDescriptor()
+			if descCoverage == tree.RequestedDescriptors {
+				desc.Privileges = sqlbase.NewDefaultPrivilegeDescriptor()
+			}
 			wroteDBs[desc.ID] = desc
 			if err := sql.WriteNewDescToBatch(ctx, false /* kvTrace */, settings, b, desc.ID, desc); err != nil {
 				return err
@@ -1053,21 +1197,25 @@ func WriteTableDescs(
 			b.CPut(sqlbase.NewDatabaseKey(desc.Name).Key(), desc.ID, nil)
 		}
 		for i := range tables {
-			if wrote, ok := wroteDBs[tables[i].ParentID]; ok {
-				tables[i].Privileges = wrote.GetPrivileges()
-			} else {
-				parentDB, err := sqlbase.GetDatabaseDescFromID(ctx, txn, tables[i].ParentID)
-				if err != nil {
-					return errors.Wrapf(err,
-						"failed to lookup parent DB %d", errors.Safe(tables[i].ParentID))
-				}
-				// TODO(mberhault): CheckPrivilege wants a planner.
-				if err := sql.CheckPrivilegeForUser(ctx, user, parentDB, privilege.CREATE); err != nil {
-					return err
+			// A full cluster restore restores the users along with the tables, so
+			// the privileges of the tables are kept as they were.
+			if descCoverage == tree.RequestedDescriptors {
+				if wrote, ok := wroteDBs[tables[i].ParentID]; ok {
+					tables[i].Privileges = wrote.GetPrivileges()
+				} else {
+					parentDB, err := sqlbase.GetDatabaseDescFromID(ctx, txn, tables[i].ParentID)
+					if err != nil {
+						return errors.Wrapf(err,
+							"failed to lookup parent DB %d", errors.Safe(tables[i].ParentID))
+					}
+					// TODO(mberhault): CheckPrivilege wants a planner.
+					if err := sql.CheckPrivilegeForUser(ctx, user, parentDB, privilege.CREATE); err != nil {
+						return err
+					}
+					// Default is to copy privs from restoring parent db, like CREATE TABLE.
+					// TODO(dt): Make this more configurable.
+					tables[i].Privileges = parentDB.GetPrivileges()
 				}
-				// Default is to copy privs from restoring parent db, like CREATE TABLE.
-				// TODO(dt): Make this more configurable.
-				tables[i].Privileges = parentDB.GetPrivileges()
 			}
 			if err := sql.WriteNewDescToBatch(ctx, false /* kvTrace */, settings, b, tables[i].ID, tables[i]); err != nil {
 				return err
@@ -1099,10 +1247,11 @@ func restoreJobDescription(
 	p sql.PlanHookState, restore *tree.Restore, from [][]string, opts map[string]string,
 ) (string, error) {
 	r := &tree.Restore{
-		AsOf:    restore.AsOf,
-		Options: optsToKVOptions(opts),
-		Targets: restore.Targets,
-		From:    make([]tree.PartitionedBackup, len(restore.From)),
+		AsOf:               restore.AsOf,
+		Options:            optsToKVOptions(opts),
+		Targets:            restore.Targets,
+		DescriptorCoverage: restore.DescriptorCoverage,
+		From:               make([]tree.PartitionedBackup, len(restore.From)),
 	}
 
 	for i, backup := range from {
@@ -1431,6 +1580,24 @@ func restorePlanHook(
 				return err
 			}
 		}
+		if restoreStmt.FromLatest {
+			// RESTORE FROM LATEST IN restores the chain of backups in the
+			// collection that covers the requested time, which is the latest one
+			// if no time is requested.
+			collection := from[0]
+			chain, err := findBackupChain(
+				ctx, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, collection, endTime,
+			)
+			if err != nil {
+				return err
+			}
+			from = make([][]string, len(chain))
+			for i, dir := range chain {
+				if from[i], err = appendPaths(collection, dir); err != nil {
+					return err
+				}
+			}
+		}
 
 		opts, err := optsFn()
 		if err != nil {
@@ -1501,10 +1668,17 @@ func doRestorePlan(
 		}
 	}
 
-	sqlDescs, restoreDBs, err := selectTargets(ctx, p, mainBackupDescs, restoreStmt.Targets, endTime)
+	sqlDescs, restoreDBs, err := selectTargets(
+		ctx, p, mainBackupDescs, restoreStmt.Targets, restoreStmt.DescriptorCoverage, endTime,
+	)
 	if err != nil {
 		return err
 	}
+	if restoreStmt.DescriptorCoverage == tree.AllDescriptors {
+		if err := checkClusterEmpty(ctx, p); err != nil {
+			return err
+		}
+	}
 
 	databasesByID := make(map[sqlbase.ID]*sqlbase.DatabaseDescriptor)
 	tablesByID := make(map[sqlbase.ID]*sqlbase.TableDescriptor)
@@ -1519,7 +1693,12 @@ func doRestorePlan(
 	if err != nil {
 		return err
 	}
-	tableRewrites, err := allocateTableRewrites(ctx, p, databasesByID, filteredTablesByID, restoreDBs, opts)
+	var tableRewrites TableRewriteMap
+	if restoreStmt.DescriptorCoverage == tree.AllDescriptors {
+		tableRewrites, err = allocateFullClusterTableRewrites(ctx, p, databasesByID, filteredTablesByID)
+	} else {
+		tableRewrites, err = allocateTableRewrites(ctx, p, databasesByID, filteredTablesByID, restoreDBs, opts)
+	}
 	if err != nil {
 		return err
 	}
@@ -1565,6 +1744,7 @@ func doRestorePlan(
 			BackupLocalityInfo: localityInfo,
 			TableDescs:         tables,
 			OverrideDB:         opts[restoreOptIntoDB],
+			DescriptorCoverage: restoreStmt.DescriptorCoverage,
 		},
 		Progress: jobspb.RestoreProgress{},
 	})
@@ -1668,6 +1848,12 @@ func createImportingTables(
 		}
 		if dbDesc := desc.GetDatabase(); dbDesc != nil {
 			if rewrite, ok := details.TableRewrites[dbDesc.ID]; ok {
+				if details.DescriptorCoverage == tree.AllDescriptors && dbDesc.ID == keys.SystemDatabaseID {
+					// The system tables of a full cluster backup are restored into a
+					// temporary database, see restoreSystemTables.
+					dbDesc.Name = restoreTempSystemDB
+					dbDesc.Privileges = sqlbase.NewDefaultPrivilegeDescriptor()
+				}
 				dbDesc.ID = rewrite.TableID
 				databases = append(databases, dbDesc)
 			}
@@ -1690,12 +1876,22 @@ func createImportingTables(
 		desc.Version++
 		desc.State = sqlbase.TableDescriptor_OFFLINE
 		desc.OfflineReason = "restoring"
+		if details.DescriptorCoverage == tree.AllDescriptors && isTempSystemTable(details, desc) {
+			desc.Privileges = sqlbase.NewDefaultPrivilegeDescriptor()
+		}
 	}
 
 	if !details.PrepareCompleted {
 		err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			if details.DescriptorCoverage == tree.AllDescriptors {
+				if err := dropDefaultUserDBs(ctx, txn); err != nil {
+					return err
+				}
+			}
 			// Write the new TableDescriptors which are set in the OFFLINE state.
-			if err := WriteTableDescs(ctx, txn, databases, tables, r.job.Payload().Username, r.settings, nil); err != nil {
+			if err := WriteTableDescs(
+				ctx, txn, databases, tables, details.DescriptorCoverage, r.job.Payload().Username, r.settings, nil,
+			); err != nil {
 				return errors.Wrapf(err, "restoring %d TableDescriptors", len(r.tables))
 			}
 
@@ -1747,6 +1943,13 @@ func (r *restoreResumer) Resume(
 		return nil
 	}
 
+	if details.DescriptorCoverage == tree.AllDescriptors && details.SystemTablesRestored {
+		// The job was resumed after the system tables were restored, so all the
+		// data has already been imported and the temporary system tables may
+		// already have been dropped.
+		return r.restoreSystemTables(ctx, p.ExecCfg().DB)
+	}
+
 	res, err := restore(
 		ctx,
 		p.ExecCfg().DB,
@@ -1761,7 +1964,105 @@ func (r *restoreResumer) Resume(
 		r.job,
 	)
 	r.res = res
-	return err
+	if err != nil {
+		return err
+	}
+
+	if details.DescriptorCoverage == tree.AllDescriptors {
+		if err := r.restoreSystemTables(ctx, p.ExecCfg().DB); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isTempSystemTable returns whether the table is one of the system tables that
+// a full cluster restore restores into the temporary system database.
+func isTempSystemTable(details jobspb.RestoreDetails, table *sqlbase.TableDescriptor) bool {
+	rewrite, ok := details.TableRewrites[keys.SystemDatabaseID]
+	return ok && table.ParentID == rewrite.TableID
+}
+
+// systemTableRestoreFilters restrict the rows of the system tables that a full
+// cluster restore replaces. The rows that don't match the filter of their table
+// are neither removed from the restoring cluster nor copied from the backup.
+var systemTableRestoreFilters = map[string]string{
+	// The cluster version is managed by the restoring cluster.
+	sqlbase.SettingsTable.Name: `name != 'version'`,
+	// Only the history of the jobs is restored. The jobs that were running when
+	// the backup was taken, such as the backup itself, must not be resumed by
+	// the restoring cluster.
+	sqlbase.JobsTable.Name: fmt.Sprintf(`status IN ('%s', '%s', '%s')`,
+		jobs.StatusSucceeded, jobs.StatusFailed, jobs.StatusCanceled),
+}
+
+// restoreSystemTables copies the contents of the system tables restored into
+// the temporary system database over the real system tables, and then drops
+// the temporary database. The job records once the contents have been copied,
+// so that a resumed job only drops the temporary database.
+func (r *restoreResumer) restoreSystemTables(ctx context.Context, db *client.DB) error {
+	details := r.job.Details().(jobspb.RestoreDetails)
+
+	var tempSystemTables, tables []*sqlbase.TableDescriptor
+	for _, table := range r.tables {
+		if isTempSystemTable(details, table) {
+			tempSystemTables = append(tempSystemTables, table)
+		} else {
+			tables = append(tables, table)
+		}
+	}
+
+	if !details.SystemTablesRestored {
+		if err := r.copySystemTables(ctx, db, tempSystemTables); err != nil {
+			return err
+		}
+	}
+
+	dropQuery := fmt.Sprintf(`DROP DATABASE IF EXISTS %s CASCADE`, restoreTempSystemDB)
+	if _, err := r.exec.Exec(ctx, "restore-drop-temp-system-db", nil /* txn */, dropQuery); err != nil {
+		return errors.Wrap(err, "dropping temporary system database")
+	}
+
+	// The temporary system tables are gone, so they must not be published once
+	// the job succeeds.
+	r.tables = tables
+	return nil
+}
+
+// copySystemTables replaces the contents of the real system tables with those
+// of the given temporary system tables, and marks the system tables as
+// restored in the job details in the same transaction.
+func (r *restoreResumer) copySystemTables(
+	ctx context.Context, db *client.DB, tempSystemTables []*sqlbase.TableDescriptor,
+) error {
+	// The temporary system tables need to be public to be read by SQL.
+	if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		return publishTables(ctx, txn, tempSystemTables)
+	}); err != nil {
+		return err
+	}
+
+	return db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		for _, table := range tempSystemTables {
+			filter := `true`
+			if f, ok := systemTableRestoreFilters[table.Name]; ok {
+				filter = f
+			}
+			deleteQuery := fmt.Sprintf(`DELETE FROM system.%s WHERE %s`,
+				tree.NameString(table.Name), filter)
+			if _, err := r.exec.Exec(ctx, "restore-delete-system-table", txn, deleteQuery); err != nil {
+				return errors.Wrapf(err, "deleting data from system.%s", table.Name)
+			}
+			restoreQuery := fmt.Sprintf(`UPSERT INTO system.%s (SELECT * FROM %s.%s WHERE %s)`,
+				tree.NameString(table.Name), restoreTempSystemDB, tree.NameString(table.Name), filter)
+			if _, err := r.exec.Exec(ctx, "restore-system-table", txn, restoreQuery); err != nil {
+				return errors.Wrapf(err, "restoring system.%s", table.Name)
+			}
+		}
+		details := r.job.Details().(jobspb.RestoreDetails)
+		details.SystemTablesRestored = true
+		return r.job.WithTxn(txn).SetDetails(ctx, details)
+	})
 }
 
 // OnFailOrCancel is part of the jobs.Resumer interface. Removes KV data that
@@ -1783,6 +2084,10 @@ func (r *restoreResumer) OnFailOrCancel(ctx context.Context, txn *client.Txn) er
 	}
 	b := txn.NewBatch()
 	for _, tbl := range details.TableDescs {
+		if details.SystemTablesRestored && isTempSystemTable(details, tbl) {
+			// The temporary system tables have already been dropped.
+			continue
+		}
 		tableDesc := *tbl
 		tableDesc.Version++
 		tableDesc.State = sqlbase.TableDescriptor_DROP
@@ -1820,8 +2125,24 @@ func (r *restoreResumer) OnSuccess(ctx context.Context, txn *client.Txn) error {
 
 	// Write the new TableDescriptors and flip state over to public so they can be
 	// accessed.
+	if err := publishTables(ctx, txn, r.tables); err != nil {
+		return err
+	}
+
+	// Initiate a run of CREATE STATISTICS. We don't know the actual number of
+	// rows affected per table, so we use a large number because we want to make
+	// sure that stats always get created/refreshed here.
+	for i := range r.tables {
+		r.statsRefresher.NotifyMutation(r.tables[i].ID, math.MaxInt32 /* rowsAffected */)
+	}
+
+	return nil
+}
+
+// publishTables flips the state of the restored tables from OFFLINE to PUBLIC.
+func publishTables(ctx context.Context, txn *client.Txn, tables []*sqlbase.TableDescriptor) error {
 	b := txn.NewBatch()
-	for _, tbl := range r.tables {
+	for _, tbl := range tables {
 		tableDesc := *tbl
 		tableDesc.Version++
 		tableDesc.State = sqlbase.TableDescriptor_PUBLIC
@@ -1838,14 +2159,6 @@ func (r *restoreResumer) OnSuccess(ctx context.Context, txn *client.Txn) error {
 	if err := txn.Run(ctx, b); err != nil {
 		return errors.Wrap(err, "publishing tables")
 	}
-
-	// Initiate a run of CREATE STATISTICS. We don't know the actual number of
-	// rows affected per table, so we use a large number because we want to make
-	// sure that stats always get created/refreshed here.
-	for i := range r.tables {
-		r.statsRefresher.NotifyMutation(r.tables[i].ID, math.MaxInt32 /* rowsAffected */)
-	}
-
 	return nil
 }
 
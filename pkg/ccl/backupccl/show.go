@@ -48,6 +48,10 @@ func showBackupPlanHook(
 		return nil, nil, nil, false, err
 	}
 
+	if backup.InCollection {
+		return showBackupsInCollectionPlanHook(ctx, backup, p, toFn)
+	}
+
 	var shower backupShower
 	switch backup.Details {
 	case tree.BackupRangeDetails:
@@ -92,6 +96,37 @@ func showBackupPlanHook(
 	return fn, shower.header, nil, false, nil
 }
 
+// showBackupsInCollectionPlanHook implements SHOW BACKUPS IN, listing the
+// subdirectories of a collection that hold full backups.
+func showBackupsInCollectionPlanHook(
+	ctx context.Context, backup *tree.ShowBackup, p sql.PlanHookState, collectionFn func() (string, error),
+) (sql.PlanHookRowFn, sqlbase.ResultColumns, []sql.PlanNode, bool, error) {
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, backup.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		collection, err := collectionFn()
+		if err != nil {
+			return err
+		}
+		dirs, err := findBackupDirs(
+			ctx, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, collection, collectionFullDirGlob,
+		)
+		if err != nil {
+			return err
+		}
+		for _, dir := range dirs {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case resultsCh <- tree.Datums{tree.NewDString(dir)}:
+			}
+		}
+		return nil
+	}
+	return fn, sqlbase.ResultColumns{{Name: "path", Typ: types.String}}, nil, false, nil
+}
+
 type backupShower struct {
 	header sqlbase.ResultColumns
 	fn     func(BackupDescriptor) []tree.Datums
@@ -11,6 +11,7 @@ package backupccl
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -283,3 +284,62 @@ func descriptorsMatchingTargets(
 
 	return ret, nil
 }
+
+// fullClusterSystemTables are the system tables that are included in a full
+// cluster backup, and whose contents are restored by a full cluster restore.
+var fullClusterSystemTables = []string{
+	sqlbase.UsersTable.Name,
+	sqlbase.RoleMembersTable.Name,
+	sqlbase.ZonesTable.Name,
+	sqlbase.SettingsTable.Name,
+	sqlbase.CommentsTable.Name,
+	sqlbase.JobsTable.Name,
+}
+
+// fullClusterTargets returns the descriptors covered by a full cluster backup:
+// every database, every table in a valid state and the system tables listed in
+// fullClusterSystemTables. It also returns the IDs of the databases whose
+// tables are all included, which is every database but the system database.
+func fullClusterTargets(allDescs []sqlbase.Descriptor) ([]sqlbase.Descriptor, []sqlbase.ID) {
+	var fullClusterDescs []sqlbase.Descriptor
+	var fullClusterDBs []sqlbase.ID
+
+	systemTablesToBackup := make(map[string]struct{}, len(fullClusterSystemTables))
+	for _, tableName := range fullClusterSystemTables {
+		systemTablesToBackup[tableName] = struct{}{}
+	}
+
+	for _, desc := range allDescs {
+		if dbDesc := desc.GetDatabase(); dbDesc != nil {
+			fullClusterDescs = append(fullClusterDescs, desc)
+			if dbDesc.ID != keys.SystemDatabaseID {
+				fullClusterDBs = append(fullClusterDBs, dbDesc.ID)
+			}
+		}
+		if tableDesc := desc.Table(hlc.Timestamp{}); tableDesc != nil {
+			if tableDesc.ParentID == keys.SystemDatabaseID {
+				if _, ok := systemTablesToBackup[tableDesc.Name]; ok {
+					fullClusterDescs = append(fullClusterDescs, desc)
+				}
+			} else if err := sql.FilterTableState(tableDesc); err == nil {
+				fullClusterDescs = append(fullClusterDescs, desc)
+			}
+		}
+	}
+	return fullClusterDescs, fullClusterDBs
+}
+
+// fullClusterTargetsRestore returns the descriptors of a full cluster backup
+// that a full cluster restore restores, along with the databases it creates.
+func fullClusterTargetsRestore(
+	allDescs []sqlbase.Descriptor,
+) ([]sqlbase.Descriptor, []*sqlbase.DatabaseDescriptor) {
+	fullClusterDescs, _ := fullClusterTargets(allDescs)
+	var restoreDBs []*sqlbase.DatabaseDescriptor
+	for _, desc := range fullClusterDescs {
+		if dbDesc := desc.GetDatabase(); dbDesc != nil && dbDesc.ID != keys.SystemDatabaseID {
+			restoreDBs = append(restoreDBs, dbDesc)
+		}
+	}
+	return fullClusterDescs, restoreDBs
+}
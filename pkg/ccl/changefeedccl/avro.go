@@ -126,6 +126,9 @@ type avroMetadata map[string]interface{}
 type avroEnvelopeOpts struct {
 	beforeField, afterField     bool
 	updatedField, resolvedField bool
+	// debeziumFields adds the `op`, `ts_ms` and `source` fields of the debezium
+	// envelope.
+	debeziumFields bool
 }
 
 // avroEnvelopeRecord is an `avroRecord` that wraps a changed SQL row and some
@@ -135,6 +138,8 @@ type avroEnvelopeRecord struct {
 
 	opts          avroEnvelopeOpts
 	before, after *avroDataRecord
+	// source is the schema of the `source` field of the debezium envelope.
+	source *avroRecord
 }
 
 // columnDescToAvroSchema converts a column descriptor into its corresponding
@@ -307,6 +312,38 @@ func columnDescToAvroSchema(colDesc *sqlbase.ColumnDescriptor) (*avroSchemaField
 		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.ParseDJSON(x.(string))
 		}
+	case types.TSVectorFamily:
+		avroType = avroSchemaString
+		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return d.(*tree.DTSVector).TSVector.String(), nil
+		}
+		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+			return tree.ParseDTSVector(x.(string))
+		}
+	case types.TSQueryFamily:
+		avroType = avroSchemaString
+		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return d.(*tree.DTSQuery).TSQuery.String(), nil
+		}
+		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+			return tree.ParseDTSQuery(x.(string))
+		}
+	case types.GeometryFamily:
+		avroType = avroSchemaString
+		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return d.(*tree.DGeometry).EWKT(), nil
+		}
+		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+			return tree.ParseDGeometry(x.(string))
+		}
+	case types.GeographyFamily:
+		avroType = avroSchemaString
+		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return d.(*tree.DGeography).EWKT(), nil
+		}
+		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+			return tree.ParseDGeography(x.(string))
+		}
 	default:
 		return nil, errors.Errorf(`column %s: type %s not yet supported with avro`,
 			colDesc.Name, colDesc.Type.SQLString())
@@ -510,6 +547,32 @@ func (r *avroDataRecord) rowFromNative(native interface{}) (sqlbase.EncDatumRow,
 	return row, nil
 }
 
+// debeziumSourceToAvroSchema creates the avro record schema of the `source`
+// field of the debezium envelope, as returned by debeziumSource.
+func debeziumSourceToAvroSchema(topic string) *avroRecord {
+	schema := &avroRecord{
+		Name:       SQLNameToAvroName(topic) + `_source`,
+		SchemaType: `record`,
+	}
+	for _, f := range []struct {
+		name string
+		typ  avroSchemaType
+	}{
+		{`connector`, avroSchemaString},
+		{`table`, avroSchemaString},
+		{`ts_ms`, avroSchemaLong},
+		{`ts_hlc`, avroSchemaString},
+		{`snapshot`, avroSchemaBoolean},
+	} {
+		schema.Fields = append(schema.Fields, &avroSchemaField{
+			SchemaType: []avroSchemaType{avroSchemaNull, f.typ},
+			Name:       f.name,
+			Default:    nil,
+		})
+	}
+	return schema
+}
+
 // envelopeToAvroSchema creates an avro record schema for an envelope containing
 // before and after versions of a row change and metadata about that row change.
 func envelopeToAvroSchema(
@@ -557,6 +620,26 @@ func envelopeToAvroSchema(
 		}
 		schema.Fields = append(schema.Fields, resolvedField)
 	}
+	if opts.debeziumFields {
+		schema.source = debeziumSourceToAvroSchema(topic)
+		schema.Fields = append(schema.Fields,
+			&avroSchemaField{
+				SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaString},
+				Name:       `op`,
+				Default:    nil,
+			},
+			&avroSchemaField{
+				SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaLong},
+				Name:       `ts_ms`,
+				Default:    nil,
+			},
+			&avroSchemaField{
+				SchemaType: []avroSchemaType{avroSchemaNull, schema.source},
+				Name:       `source`,
+				Default:    nil,
+			},
+		)
+	}
 
 	schemaJSON, err := json.Marshal(schema)
 	if err != nil {
@@ -619,6 +702,37 @@ func (r *avroEnvelopeRecord) BinaryFromRow(
 			native[`resolved`] = goavro.Union(avroUnionKey(avroSchemaString), ts.AsOfSystemTime())
 		}
 	}
+	if r.opts.debeziumFields {
+		native[`op`], native[`ts_ms`], native[`source`] = nil, nil, nil
+		if op, ok := meta[`op`]; ok {
+			delete(meta, `op`)
+			native[`op`] = goavro.Union(avroUnionKey(avroSchemaString), op)
+		}
+		if ts, ok := meta[`ts_ms`]; ok {
+			delete(meta, `ts_ms`)
+			native[`ts_ms`] = goavro.Union(avroUnionKey(avroSchemaLong), ts)
+		}
+		if s, ok := meta[`source`]; ok {
+			delete(meta, `source`)
+			source, ok := s.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf(`unknown metadata source type: %T`, s)
+			}
+			sourceNative := make(map[string]interface{}, len(r.source.Fields))
+			for _, field := range r.source.Fields {
+				sourceNative[field.Name] = nil
+				switch v := source[field.Name].(type) {
+				case string:
+					sourceNative[field.Name] = goavro.Union(avroUnionKey(avroSchemaString), v)
+				case int64:
+					sourceNative[field.Name] = goavro.Union(avroUnionKey(avroSchemaLong), v)
+				case bool:
+					sourceNative[field.Name] = goavro.Union(avroUnionKey(avroSchemaBoolean), v)
+				}
+			}
+			native[`source`] = goavro.Union(avroUnionKey(r.source), sourceNative)
+		}
+	}
 	for k := range meta {
 		return nil, errors.AssertionFailedf(`unhandled meta key: %s`, k)
 	}
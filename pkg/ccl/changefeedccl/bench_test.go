@@ -206,8 +206,8 @@ func createBenchmarkChangefeed(
 		nil /* curCount */, nil /* maxHist */, math.MaxInt64, settings,
 	)
 	poller := makePoller(
-		settings, s.DB(), feedClock, s.GossipI().(*gossip.Gossip), spans, details, initialHighWater, buf,
-		leaseMgr, metrics, &mm,
+		settings, s.DB(), feedClock, s.GossipI().(*gossip.Gossip), spans, details,
+		needsPrevRows(details, nil /* query */), initialHighWater, buf, leaseMgr, metrics, &mm,
 	)
 
 	th := makeTableHistory(func(context.Context, *sqlbase.TableDescriptor) error { return nil }, initialHighWater)
@@ -217,7 +217,8 @@ func createBenchmarkChangefeed(
 		targets:  details.Targets,
 		m:        th,
 	}
-	rowsFn := kvsToRows(s.LeaseManager().(*sql.LeaseManager), details, buf.Get)
+	rowsFn := kvsToRows(s.DB(), s.LeaseManager().(*sql.LeaseManager), details,
+		needsPrevRows(details, nil /* query */), buf.GetBatch)
 	sf := makeSpanFrontier(spans...)
 	tickFn := emitEntries(
 		s.ClusterSettings(), details, sf, encoder, sink, rowsFn, TestingKnobs{}, metrics)
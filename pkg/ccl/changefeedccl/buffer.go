@@ -38,6 +38,9 @@ type bufferEntry struct {
 	// If unset (zero-valued), the KV's timestamp will be used to interpret both
 	// of the current and previous values instead.
 	backfillTimestamp hlc.Timestamp
+	// initialScan is set if the KV was emitted by the initial scan of the
+	// changefeed, rather than because it changed.
+	initialScan bool
 	// bufferGetTimestamp is the time this entry came out of the buffer.
 	bufferGetTimestamp time.Time
 }
@@ -46,21 +49,33 @@ type bufferEntry struct {
 // changefeed pipeline (which is backpressured all the way to the sink).
 type buffer struct {
 	entriesCh chan bufferEntry
+	// batch is reused by GetBatch to save allocations.
+	batch []bufferEntry
 }
 
+// maxBufferBatchSize is the maximum number of entries returned by GetBatch,
+// which is also the number of entries that can be added to the buffer before
+// it blocks.
+const maxBufferBatchSize = 128
+
 func makeBuffer() *buffer {
-	return &buffer{entriesCh: make(chan bufferEntry)}
+	return &buffer{entriesCh: make(chan bufferEntry, maxBufferBatchSize)}
 }
 
 // AddKV inserts a changed kv into the buffer. Individual keys must be added in
-// increasing mvcc order.
+// increasing mvcc order. initialScan is set for the kvs of the initial scan.
 func (b *buffer) AddKV(
-	ctx context.Context, kv roachpb.KeyValue, prevVal roachpb.Value, backfillTimestamp hlc.Timestamp,
+	ctx context.Context,
+	kv roachpb.KeyValue,
+	prevVal roachpb.Value,
+	backfillTimestamp hlc.Timestamp,
+	initialScan bool,
 ) error {
 	return b.addEntry(ctx, bufferEntry{
 		kv:                kv,
 		prevVal:           prevVal,
 		backfillTimestamp: backfillTimestamp,
+		initialScan:       initialScan,
 	})
 }
 
@@ -69,6 +84,16 @@ func (b *buffer) AddResolved(ctx context.Context, span roachpb.Span, ts hlc.Time
 	return b.addEntry(ctx, bufferEntry{resolved: &jobspb.ResolvedSpan{Span: span, Timestamp: ts}})
 }
 
+// AddSchemaChangeStop inserts a resolved timestamp notification in the buffer,
+// after which the changefeed has to stop because of a schema change.
+func (b *buffer) AddSchemaChangeStop(
+	ctx context.Context, span roachpb.Span, ts hlc.Timestamp,
+) error {
+	return b.addEntry(ctx, bufferEntry{
+		resolved: &jobspb.ResolvedSpan{Span: span, Timestamp: ts, SchemaChangeStop: true},
+	})
+}
+
 func (b *buffer) addEntry(ctx context.Context, e bufferEntry) error {
 	select {
 	case <-ctx.Done():
@@ -90,6 +115,28 @@ func (b *buffer) Get(ctx context.Context) (bufferEntry, error) {
 	}
 }
 
+// GetBatch returns the entries of the buffer that are available without
+// waiting, blocking only until the first one is. The entries are handed out in
+// the same order as by Get. The returned slice is only valid until the next
+// call to GetBatch.
+func (b *buffer) GetBatch(ctx context.Context) ([]bufferEntry, error) {
+	e, err := b.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.batch = append(b.batch[:0], e)
+	for len(b.batch) < maxBufferBatchSize {
+		select {
+		case e := <-b.entriesCh:
+			e.bufferGetTimestamp = timeutil.Now()
+			b.batch = append(b.batch, e)
+		default:
+			return b.batch, nil
+		}
+	}
+	return b.batch, nil
+}
+
 // memBufferDefaultCapacity is the default capacity for a memBuffer for a single
 // changefeed.
 //
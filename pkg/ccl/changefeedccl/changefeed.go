@@ -12,8 +12,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -21,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -57,122 +60,272 @@ type emitEntry struct {
 // kvsToRows gets changed kvs from a closure and converts them into sql rows. It
 // returns a closure that may be repeatedly called to advance the changefeed.
 // The returned closure is not threadsafe.
+//
+// A changed kv only contains one column family of a row. Unless the families
+// are emitted separately (optSplitColumnFamilies), the other families of the
+// row are read from db to reassemble the full row. The rows of all the kvs
+// returned by one call to inputFn are read with one request per timestamp.
+//
+// If withDiff is set, the previous values of the rows are decoded too.
 func kvsToRows(
+	db *client.DB,
 	leaseMgr *sql.LeaseManager,
 	details jobspb.ChangefeedDetails,
-	inputFn func(context.Context) (bufferEntry, error),
+	withDiff bool,
+	inputFn func(context.Context) ([]bufferEntry, error),
 ) func(context.Context) ([]emitEntry, error) {
-	_, withDiff := details.Opts[optDiff]
+	_, splitFamilies := details.Opts[optSplitColumnFamilies]
 	rfCache := newRowFetcherCache(leaseMgr)
 
-	var kvs row.SpanKVFetcher
-	appendEmitEntryForKV := func(
-		ctx context.Context,
-		output []emitEntry,
-		kv roachpb.KeyValue,
-		prevVal roachpb.Value,
-		schemaTimestamp hlc.Timestamp,
-		prevSchemaTimestamp hlc.Timestamp,
-		bufferGetTimestamp time.Time,
-	) ([]emitEntry, error) {
+	// emittedRows contains the keys of the rows of tables with multiple column
+	// families that have been emitted as full rows, along with the timestamp
+	// they were emitted at. A transaction that changes several families of a
+	// row results in one kv per family, which aren't necessarily adjacent, but
+	// the row should only be emitted once. A row is forgotten once a resolved
+	// timestamp covering it is received, since no more kvs of the row can be
+	// received at that timestamp.
+	emittedRows := make(map[string]hlc.Timestamp)
+
+	// changed contains the entries of the current batch of inputs, and reads
+	// contains the reads of the rows of their kvs.
+	var changed []changedKV
+	var reads []rowRead
+	// addRead adds a read of the row with the given key at ts to reads and
+	// returns its index.
+	addRead := func(rowKey roachpb.Key, ts hlc.Timestamp, sentinel bool) int {
+		reads = append(reads, rowRead{rowKey: rowKey, ts: ts, sentinel: sentinel})
+		return len(reads) - 1
+	}
+	// rowExists returns whether the given read found the row, or false if there
+	// was no read.
+	rowExists := func(readIdx int) bool {
+		return readIdx != -1 && len(reads[readIdx].kvs) > 0
+	}
 
-		desc, err := rfCache.TableDescForKey(ctx, kv.Key, schemaTimestamp)
+	// prepareEntry adds input to changed along with the reads of the row of its
+	// kv, if any, that are needed to decode it.
+	prepareEntry := func(ctx context.Context, input bufferEntry) error {
+		c := changedKV{bufferEntry: input, rowRead: -1, prevRowRead: -1}
+		if input.resolved != nil {
+			// No more kvs of the rows of the span can be received at the resolved
+			// timestamp or earlier.
+			for rowKey, ts := range emittedRows {
+				if !input.resolved.Timestamp.Less(ts) && input.resolved.Span.ContainsKey(roachpb.Key(rowKey)) {
+					delete(emittedRows, rowKey)
+				}
+			}
+		}
+		if input.kv.Key == nil {
+			changed = append(changed, c)
+			return nil
+		}
+		if log.V(3) {
+			log.Infof(ctx, "changed key %s %s", input.kv.Key, input.kv.Value.Timestamp)
+		}
+		c.schemaTimestamp = input.kv.Value.Timestamp
+		c.prevSchemaTimestamp = c.schemaTimestamp
+		if input.backfillTimestamp != (hlc.Timestamp{}) {
+			c.schemaTimestamp = input.backfillTimestamp
+			c.prevSchemaTimestamp = c.schemaTimestamp.Prev()
+		}
+
+		desc, err := rfCache.TableDescForKey(ctx, input.kv.Key, c.schemaTimestamp)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if _, ok := details.Targets[desc.ID]; !ok {
 			// This kv is for an interleaved table that we're not watching.
 			if log.V(3) {
-				log.Infof(ctx, `skipping key from unwatched table %s: %s`, desc.Name, kv.Key)
+				log.Infof(ctx, `skipping key from unwatched table %s: %s`, desc.Name, input.kv.Key)
 			}
-			return nil, nil
+			changed = append(changed, c)
+			return nil
+		}
+		c.desc = desc
+		if len(desc.Families) == 1 {
+			changed = append(changed, c)
+			return nil
 		}
 
-		rf, err := rfCache.RowFetcherForTableDesc(desc)
+		rowKey, err := keys.EnsureSafeSplitKey(input.kv.Key)
 		if err != nil {
-			return nil, err
+			return err
 		}
-
-		// Get new value.
-		var r emitEntry
-		r.bufferGetTimestamp = bufferGetTimestamp
-		{
-			// TODO(dan): Handle tables with multiple column families.
-			// Reuse kvs to save allocations.
-			kvs.KVs = kvs.KVs[:0]
-			kvs.KVs = append(kvs.KVs, kv)
-			if err := rf.StartScanFrom(ctx, &kvs); err != nil {
-				return nil, err
+		if !splitFamilies {
+			c.fullRow = true
+			if ts, ok := emittedRows[string(rowKey)]; ok && ts == c.schemaTimestamp {
+				c.desc = nil
+				changed = append(changed, c)
+				return nil
 			}
-
-			r.row.datums, r.row.tableDesc, _, err = rf.NextRow(ctx)
-			if err != nil {
-				return nil, err
+			emittedRows[string(rowKey)] = c.schemaTimestamp
+			c.rowRead = addRead(rowKey, c.schemaTimestamp, false /* sentinel */)
+			if withDiff {
+				c.prevRowRead = addRead(rowKey, c.schemaTimestamp.Prev(), false /* sentinel */)
 			}
-			if r.row.datums == nil {
-				return nil, errors.AssertionFailedf("unexpected empty datums")
+			changed = append(changed, c)
+			return nil
+		}
+
+		c.split = true
+		familyID, err := familyIDForKey(input.kv.Key)
+		if err != nil {
+			return err
+		}
+		if familyID != 0 {
+			// A family other than the first one has no kv when all of its columns
+			// are NULL, so the deletion of its kv only means that the row was
+			// deleted if the kv of the first family, which always exists, is gone
+			// too.
+			if len(input.kv.Value.RawBytes) == 0 {
+				c.rowRead = addRead(rowKey, c.schemaTimestamp, true /* sentinel */)
 			}
-			r.row.datums = append(sqlbase.EncDatumRow(nil), r.row.datums...)
-			r.row.deleted = rf.RowIsDeleted()
-			r.row.updated = schemaTimestamp
-
-			// Assert that we don't get a second row from the row.Fetcher. We
-			// fed it a single KV, so that would be surprising.
-			var nextRow emitEntry
-			nextRow.row.datums, nextRow.row.tableDesc, _, err = rf.NextRow(ctx)
-			if err != nil {
-				return nil, err
+			if withDiff && len(input.prevVal.RawBytes) == 0 {
+				c.prevRowRead = addRead(rowKey, c.schemaTimestamp.Prev(), true /* sentinel */)
 			}
-			if nextRow.row.datums != nil {
-				return nil, errors.AssertionFailedf("unexpected non-empty datums")
+		}
+		changed = append(changed, c)
+		return nil
+	}
+
+	var kvs row.SpanKVFetcher
+	// setKVs sets kvs to the kvs to decode the row of kv from. These are the kvs
+	// of the given read of the row if the full row is reassembled, and kv
+	// otherwise. A full row that doesn't exist at the timestamp of the read is
+	// decoded from kv, which is then expected to be a deletion.
+	setKVs := func(kv roachpb.KeyValue, fullRow bool, readIdx int) {
+		// Reuse kvs to save allocations.
+		kvs.KVs = kvs.KVs[:0]
+		if fullRow && rowExists(readIdx) {
+			kvs.KVs = append(kvs.KVs, reads[readIdx].kvs...)
+		} else {
+			kvs.KVs = append(kvs.KVs, kv)
+		}
+	}
+	// fetchRow decodes the row made of the given kvs with rf. If family is
+	// non-nil, the row only contains the columns of family.
+	fetchRow := func(
+		ctx context.Context, rf *row.Fetcher, family *familyDesc,
+	) (datums sqlbase.EncDatumRow, tableDesc *sqlbase.TableDescriptor, deleted bool, err error) {
+		if err := rf.StartScanFrom(ctx, &kvs); err != nil {
+			return nil, nil, false, err
+		}
+		datums, tableDesc, _, err = rf.NextRow(ctx)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if datums == nil {
+			return nil, nil, false, errors.AssertionFailedf("unexpected empty datums")
+		}
+		if family != nil {
+			familyDatums := make(sqlbase.EncDatumRow, len(family.colIdxs))
+			for i, colIdx := range family.colIdxs {
+				familyDatums[i] = datums[colIdx]
 			}
+			datums, tableDesc = familyDatums, family.tableDesc
+		} else {
+			datums = append(sqlbase.EncDatumRow(nil), datums...)
+		}
+		deleted = rf.RowIsDeleted()
+
+		// Assert that we don't get a second row from the row.Fetcher. We
+		// fed it the KVs of a single row, so that would be surprising.
+		nextDatums, _, _, err := rf.NextRow(ctx)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if nextDatums != nil {
+			return nil, nil, false, errors.AssertionFailedf("unexpected non-empty datums")
+		}
+		return datums, tableDesc, deleted, nil
+	}
+	// rowFetcher returns the row.Fetcher and, if the column families are emitted
+	// separately, the familyDesc to use for kv.
+	rowFetcher := func(
+		desc *sqlbase.ImmutableTableDescriptor, kv roachpb.KeyValue, split bool,
+	) (*row.Fetcher, *familyDesc, error) {
+		if !split {
+			rf, err := rfCache.RowFetcherForTableDesc(desc)
+			return rf, nil, err
+		}
+		familyID, err := familyIDForKey(kv.Key)
+		if err != nil {
+			return nil, nil, err
+		}
+		family, err := rfCache.FamilyDesc(desc, familyID)
+		if err != nil {
+			return nil, nil, err
+		}
+		rf, err := rfCache.RowFetcherForColumnFamily(desc, familyID)
+		return rf, family, err
+	}
+
+	// appendEmitEntryForKV decodes the row of the kv of c, whose reads have
+	// been done, and appends it to output.
+	appendEmitEntryForKV := func(
+		ctx context.Context, output []emitEntry, c *changedKV,
+	) ([]emitEntry, error) {
+		rf, family, err := rowFetcher(c.desc, c.kv, c.split)
+		if err != nil {
+			return nil, err
+		}
+
+		// Get new value.
+		var r emitEntry
+		r.bufferGetTimestamp = c.bufferGetTimestamp
+		setKVs(c.kv, c.fullRow, c.rowRead)
+		r.row.datums, r.row.tableDesc, r.row.deleted, err = fetchRow(ctx, rf, family)
+		if err != nil {
+			return nil, err
+		}
+		if c.split && rowExists(c.rowRead) {
+			// Only the columns of the family were set to NULL.
+			r.row.deleted = false
+		}
+		r.row.updated = c.schemaTimestamp
+		r.row.initialScan = c.initialScan
+		if family != nil {
+			r.row.familyID = family.tableDesc.Families[0].ID
 		}
 
 		// Get prev value, if necessary.
 		if withDiff {
-			prevRF := rf
-			if prevSchemaTimestamp != schemaTimestamp {
+			prevRF, prevFamily := rf, family
+			if c.prevSchemaTimestamp != c.schemaTimestamp {
 				// If the previous value is being interpreted under a different
 				// version of the schema, fetch the correct table descriptor and
 				// create a new row.Fetcher with it.
-				prevDesc, err := rfCache.TableDescForKey(ctx, kv.Key, prevSchemaTimestamp)
+				prevDesc, err := rfCache.TableDescForKey(ctx, c.kv.Key, c.prevSchemaTimestamp)
 				if err != nil {
 					return nil, err
 				}
+				if c.split {
+					if _, err := prevDesc.FindFamilyByID(r.row.familyID); err != nil {
+						// The family was added by the schema change, so it has no
+						// previous value.
+						r.row.prevDeleted = true
+						output = append(output, r)
+						return output, nil
+					}
+				}
 
-				prevRF, err = rfCache.RowFetcherForTableDesc(prevDesc)
+				prevRF, prevFamily, err = rowFetcher(prevDesc, c.kv, c.split)
 				if err != nil {
 					return nil, err
 				}
 			}
 
-			prevKV := roachpb.KeyValue{Key: kv.Key, Value: prevVal}
-			// TODO(dan): Handle tables with multiple column families.
-			// Reuse kvs to save allocations.
-			kvs.KVs = kvs.KVs[:0]
-			kvs.KVs = append(kvs.KVs, prevKV)
-			if err := prevRF.StartScanFrom(ctx, &kvs); err != nil {
-				return nil, err
-			}
-			r.row.prevDatums, r.row.prevTableDesc, _, err = prevRF.NextRow(ctx)
+			prevKV := roachpb.KeyValue{Key: c.kv.Key, Value: c.prevVal}
+			setKVs(prevKV, c.fullRow, c.prevRowRead)
+			r.row.prevDatums, r.row.prevTableDesc, r.row.prevDeleted, err = fetchRow(
+				ctx, prevRF, prevFamily,
+			)
 			if err != nil {
 				return nil, err
 			}
-			if r.row.prevDatums == nil {
-				return nil, errors.AssertionFailedf("unexpected empty datums")
-			}
-			r.row.prevDatums = append(sqlbase.EncDatumRow(nil), r.row.prevDatums...)
-			r.row.prevDeleted = prevRF.RowIsDeleted()
-
-			// Assert that we don't get a second row from the row.Fetcher. We
-			// fed it a single KV, so that would be surprising.
-			var nextRow emitEntry
-			nextRow.row.prevDatums, nextRow.row.prevTableDesc, _, err = prevRF.NextRow(ctx)
-			if err != nil {
-				return nil, err
-			}
-			if nextRow.row.prevDatums != nil {
-				return nil, errors.AssertionFailedf("unexpected non-empty datums")
+			if c.split && rowExists(c.prevRowRead) {
+				// The columns of the family were all NULL.
+				r.row.prevDeleted = false
 			}
 		}
 
@@ -185,33 +338,34 @@ func kvsToRows(
 		// Reuse output to save allocations.
 		output = output[:0]
 		for {
-			input, err := inputFn(ctx)
+			inputs, err := inputFn(ctx)
 			if err != nil {
 				return nil, err
 			}
-			if input.kv.Key != nil {
-				if log.V(3) {
-					log.Infof(ctx, "changed key %s %s", input.kv.Key, input.kv.Value.Timestamp)
-				}
-				schemaTimestamp := input.kv.Value.Timestamp
-				prevSchemaTimestamp := schemaTimestamp
-				if input.backfillTimestamp != (hlc.Timestamp{}) {
-					schemaTimestamp = input.backfillTimestamp
-					prevSchemaTimestamp = schemaTimestamp.Prev()
-				}
-				output, err = appendEmitEntryForKV(
-					ctx, output, input.kv, input.prevVal,
-					schemaTimestamp, prevSchemaTimestamp,
-					input.bufferGetTimestamp)
-				if err != nil {
+			// Reuse changed and reads to save allocations.
+			changed, reads = changed[:0], reads[:0]
+			for _, input := range inputs {
+				if err := prepareEntry(ctx, input); err != nil {
 					return nil, err
 				}
 			}
-			if input.resolved != nil {
-				output = append(output, emitEntry{
-					resolved:           input.resolved,
-					bufferGetTimestamp: input.bufferGetTimestamp,
-				})
+			if err := readRows(ctx, db, reads); err != nil {
+				return nil, err
+			}
+			for i := range changed {
+				c := &changed[i]
+				if c.desc != nil {
+					output, err = appendEmitEntryForKV(ctx, output, c)
+					if err != nil {
+						return nil, err
+					}
+				}
+				if c.resolved != nil {
+					output = append(output, emitEntry{
+						resolved:           c.resolved,
+						bufferGetTimestamp: c.bufferGetTimestamp,
+					})
+				}
 			}
 			if output != nil {
 				return output, nil
@@ -220,6 +374,92 @@ func kvsToRows(
 	}
 }
 
+// changedKV is an entry of the buffer, along with the state needed to decode
+// the row of its kv once the rows of the kvs of its batch have been read.
+type changedKV struct {
+	bufferEntry
+	// desc is the descriptor of the table of kv, or nil if kv has no row to
+	// emit.
+	desc                *sqlbase.ImmutableTableDescriptor
+	schemaTimestamp     hlc.Timestamp
+	prevSchemaTimestamp hlc.Timestamp
+	// fullRow is set if the column families of the row are reassembled, and
+	// split if they are emitted separately.
+	fullRow, split bool
+	// rowRead and prevRowRead are the indexes of the reads of the row of kv at
+	// schemaTimestamp and at the previous timestamp, or -1 if the row isn't
+	// read.
+	rowRead, prevRowRead int
+}
+
+// rowRead is a read of the kvs of a row at a given timestamp. If sentinel is
+// set, only the kv of the first column family, which exists as long as the row
+// does, is read.
+type rowRead struct {
+	rowKey   roachpb.Key
+	ts       hlc.Timestamp
+	sentinel bool
+	kvs      []roachpb.KeyValue
+}
+
+// readRows does the given reads with one batch per distinct timestamp.
+func readRows(ctx context.Context, db *client.DB, reads []rowRead) error {
+	if len(reads) == 0 {
+		return nil
+	}
+	readsByTimestamp := make(map[hlc.Timestamp][]int)
+	for i := range reads {
+		readsByTimestamp[reads[i].ts] = append(readsByTimestamp[reads[i].ts], i)
+	}
+	for ts, readIdxs := range readsByTimestamp {
+		if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			txn.SetFixedTimestamp(ctx, ts)
+			b := txn.NewBatch()
+			for _, readIdx := range readIdxs {
+				read := &reads[readIdx]
+				if read.sentinel {
+					// MakeFamilyKey appends to its argument, which must not be
+					// modified.
+					familyKey := keys.MakeFamilyKey(append(roachpb.Key(nil), read.rowKey...), 0 /* famID */)
+					b.Get(familyKey)
+				} else {
+					b.Scan(read.rowKey, read.rowKey.PrefixEnd())
+				}
+			}
+			if err := txn.Run(ctx, b); err != nil {
+				return err
+			}
+			for i, readIdx := range readIdxs {
+				read := &reads[readIdx]
+				read.kvs = read.kvs[:0]
+				for _, r := range b.Results[i].Rows {
+					if r.Value == nil {
+						// The sentinel kv doesn't exist.
+						continue
+					}
+					// The span of the row also contains the rows of the tables that are
+					// interleaved into it.
+					if rKey, err := keys.EnsureSafeSplitKey(r.Key); err != nil {
+						return err
+					} else if !rKey.Equal(read.rowKey) {
+						continue
+					}
+					read.kvs = append(read.kvs, roachpb.KeyValue{Key: r.Key, Value: *r.Value})
+				}
+			}
+			return nil
+		}); err != nil {
+			if isTransientKVError(err) {
+				// Reading the rows is expected to succeed once the cluster is
+				// healthy again.
+				return MarkRetryableError(err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
 // emitEntries connects to a sink, receives rows from a closure, and repeatedly
 // emits them to the sink. It returns a closure that may be repeatedly called to
 // advance the changefeed and which returns span-level resolved timestamp
@@ -237,6 +477,7 @@ func emitEntries(
 	metrics *Metrics,
 ) func(context.Context) ([]jobspb.ResolvedSpan, error) {
 	var scratch bufalloc.ByteAllocator
+	_, tombstones := details.Opts[optTombstones]
 	emitRowFn := func(ctx context.Context, row encodeRow) error {
 		// Ensure that row updates are strictly newer than the least resolved timestamp
 		// being tracked by the local span frontier. The poller should not be forwarding
@@ -275,6 +516,17 @@ func emitEntries(
 		if log.V(3) {
 			log.Infof(ctx, `row %s: %s -> %s`, row.tableDesc.Name, keyCopy, valueCopy)
 		}
+		if tombstones && row.deleted {
+			// Follow the delete event with a tombstone, a message with the same
+			// key and an empty value, which lets kafka compact away every
+			// message of the row.
+			var noValue []byte
+			if err := sink.EmitRow(
+				ctx, row.tableDesc, keyCopy, noValue, row.updated,
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	}
 
@@ -335,7 +587,12 @@ func emitEntries(
 		} else {
 			timeBetweenFlushes = changefeedPollInterval.Get(&settings.SV) / 5
 		}
-		if len(resolvedSpans) == 0 || timeutil.Since(lastFlush) < timeBetweenFlushes {
+		// A changefeed that only emits its initial scan completes once the scan
+		// is resolved, so the resolved spans have to be returned right away.
+		initialScanDone := initialScanTypeFromOpts(details.Opts) == optInitialScanOnly &&
+			!sf.Frontier().Less(details.StatementTime)
+		if len(resolvedSpans) == 0 ||
+			(timeutil.Since(lastFlush) < timeBetweenFlushes && !initialScanDone) {
 			return nil, nil
 		}
 
@@ -410,3 +667,16 @@ func emitResolvedTimestamp(
 	}
 	return nil
 }
+
+// familyIDForKey returns the ID of the column family of the given row key.
+func familyIDForKey(key roachpb.Key) (sqlbase.FamilyID, error) {
+	n, err := keys.GetRowPrefixLength(key)
+	if err != nil {
+		return 0, err
+	}
+	_, familyID, err := encoding.DecodeUvarintAscending(key[n:])
+	if err != nil {
+		return 0, err
+	}
+	return sqlbase.FamilyID(familyID), nil
+}
@@ -66,6 +66,9 @@ type changeAggregator struct {
 	// resolvedSpanBuf contains resolved span updates to send to changeFrontier.
 	// If sink is a bufferSink, it must be emptied before these are sent.
 	resolvedSpanBuf encDatumRowBuffer
+	// sf tracks the resolved timestamps of the spans watched by this
+	// changeAggregator.
+	sf *spanFrontier
 }
 
 type timestampLowerBoundOracle interface {
@@ -168,6 +171,7 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 	for _, watch := range ca.spec.Watches {
 		sf.Forward(watch.Span, watch.InitialResolved)
 	}
+	ca.sf = sf
 	timestampOracle := &changeAggregatorLowerBoundOracle{sf: sf, initialInclusiveLowerBound: ca.spec.Feed.StatementTime}
 	nodeID := ca.flowCtx.EvalCtx.NodeID
 	var err error
@@ -212,13 +216,28 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 	pollerMemMon.Start(ctx, nil /* pool */, mon.MakeStandaloneBudget(pollerMemMonCapacity))
 	ca.pollerMemMon = &pollerMemMon
 
+	var query *changefeedQuery
+	if ca.spec.Feed.Select != `` {
+		if query, err = parseChangefeedQuery(ca.spec.Feed.Select); err != nil {
+			ca.MoveToDraining(err)
+			ca.cancel()
+			return ctx
+		}
+	}
+	withDiff := needsPrevRows(ca.spec.Feed, query)
+
 	buf := makeBuffer()
 	leaseMgr := ca.flowCtx.Cfg.LeaseManager.(*sql.LeaseManager)
 	ca.poller = makePoller(
 		ca.flowCtx.Cfg.Settings, ca.flowCtx.Cfg.DB, ca.flowCtx.Cfg.DB.Clock(), ca.flowCtx.Cfg.Gossip,
-		spans, ca.spec.Feed, initialHighWater, buf, leaseMgr, metrics, ca.pollerMemMon,
+		spans, ca.spec.Feed, withDiff, initialHighWater, buf, leaseMgr, metrics, ca.pollerMemMon,
 	)
-	rowsFn := kvsToRows(leaseMgr, ca.spec.Feed, buf.Get)
+	rowsFn := kvsToRows(ca.flowCtx.Cfg.DB, leaseMgr, ca.spec.Feed, withDiff, buf.GetBatch)
+	if query != nil {
+		// Filter and project the changed rows with the query of the changefeed
+		// before they are encoded.
+		rowsFn = query.evalRows(ca.flowCtx.NewEvalCtx(), rowsFn)
+	}
 
 	ca.tickFn = emitEntries(
 		ca.flowCtx.Cfg.Settings, ca.spec.Feed, sf, ca.encoder, ca.sink, rowsFn, knobs, metrics)
@@ -285,6 +304,12 @@ func (ca *changeAggregator) Next() (sqlbase.EncDatumRow, *execinfrapb.ProducerMe
 			return ca.resolvedSpanBuf.Pop(), nil
 		}
 
+		if ca.initialScanDone() {
+			// Everything there is to emit has been emitted.
+			ca.MoveToDraining(nil /* err */)
+			break
+		}
+
 		if err := ca.tick(); err != nil {
 			select {
 			// If the poller errored first, that's the
@@ -302,6 +327,16 @@ func (ca *changeAggregator) Next() (sqlbase.EncDatumRow, *execinfrapb.ProducerMe
 	return nil, ca.DrainHelper()
 }
 
+// initialScanDone returns whether this changeAggregator only emits the initial
+// scan (initial_scan='only') and all its spans have been resolved at the
+// statement time, at which the initial scan is done.
+func (ca *changeAggregator) initialScanDone() bool {
+	if initialScanTypeFromOpts(ca.spec.Feed.Opts) != optInitialScanOnly {
+		return false
+	}
+	return !ca.sf.Frontier().Less(ca.spec.Feed.StatementTime)
+}
+
 func (ca *changeAggregator) tick() error {
 	resolvedSpans, err := ca.tickFn(ca.Ctx)
 	if err != nil {
@@ -632,6 +667,19 @@ func (cf *changeFrontier) noteResolvedSpan(d sqlbase.EncDatum) error {
 		}
 	}
 
+	if resolved.SchemaChangeStop && !cf.sf.Frontier().Less(resolved.Timestamp) {
+		// Everything before the schema change has been emitted and checkpointed,
+		// so the changefeed can pick up from there. The job is paused by its
+		// resumer, while a sinkless changefeed has to be recreated.
+		if cf.spec.JobID != 0 {
+			return errors.Errorf(`%s %s; resume the job to continue`,
+				schemaChangeStopErrorString, resolved.Timestamp.Next().GoTime())
+		}
+		return errors.Errorf(`%s %s; create a new changefeed with %s='%s' to resume`,
+			schemaChangeStopErrorString, resolved.Timestamp.Next().GoTime(),
+			optCursor, resolved.Timestamp.AsOfSystemTime())
+	}
+
 	// Potentially log the most behind span in the frontier for debugging. These
 	// two cluster setting values represent the target responsiveness of poller
 	// and range feed. The cluster setting for switching between poller and
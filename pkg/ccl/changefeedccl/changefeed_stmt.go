@@ -48,6 +48,9 @@ func init() {
 
 type envelopeType string
 type formatType string
+type schemaChangeEventClass string
+type schemaChangePolicy string
+type initialScanType string
 
 const (
 	optConfluentSchemaRegistry = `confluent_schema_registry`
@@ -58,11 +61,46 @@ const (
 	optResolvedTimestamps      = `resolved`
 	optUpdatedTimestamps       = `updated`
 	optDiff                    = `diff`
+	optSplitColumnFamilies     = `split_column_families`
+	optTombstones              = `tombstones`
+	optSchemaChangeEvents      = `schema_change_events`
+	optSchemaChangePolicy      = `schema_change_policy`
+	optInitialScan             = `initial_scan`
+
+	// optSchemaChangeEventClassDefault only considers the schema changes that
+	// rewrite the rows of the table: adding a column that needs a backfill and
+	// dropping a column.
+	optSchemaChangeEventClassDefault schemaChangeEventClass = `default`
+	// optSchemaChangeEventClassColumnChange also considers the addition of
+	// columns that don't need a backfill.
+	optSchemaChangeEventClassColumnChange schemaChangeEventClass = `column_changes`
+
+	// optSchemaChangePolicyBackfill re-emits every row of the table at the time
+	// of the schema change.
+	optSchemaChangePolicyBackfill schemaChangePolicy = `backfill`
+	// optSchemaChangePolicyNoBackfill doesn't re-emit the table at schema
+	// changes.
+	optSchemaChangePolicyNoBackfill schemaChangePolicy = `nobackfill`
+	// optSchemaChangePolicyStop stops the changefeed at the time of the schema
+	// change, after every change before it has been emitted. A changefeed job
+	// is paused, and continues past the schema change once it's resumed.
+	optSchemaChangePolicyStop schemaChangePolicy = `stop`
+
+	// optInitialScanYes emits every row of the targets at the statement time
+	// before the changes. It's the default without a cursor.
+	optInitialScanYes initialScanType = `yes`
+	// optInitialScanNo only emits the changes after the statement time. It's
+	// the default with a cursor.
+	optInitialScanNo initialScanType = `no`
+	// optInitialScanOnly emits every row of the targets at the statement time
+	// and then completes the changefeed.
+	optInitialScanOnly initialScanType = `only`
 
 	optEnvelopeKeyOnly       envelopeType = `key_only`
 	optEnvelopeRow           envelopeType = `row`
 	optEnvelopeDeprecatedRow envelopeType = `deprecated_row`
 	optEnvelopeWrapped       envelopeType = `wrapped`
+	optEnvelopeDebezium      envelopeType = `debezium`
 
 	optFormatJSON formatType = `json`
 	optFormatAvro formatType = `experimental_avro`
@@ -92,6 +130,23 @@ var changefeedOptionExpectValues = map[string]sql.KVStringOptValidate{
 	optResolvedTimestamps:      sql.KVStringOptAny,
 	optUpdatedTimestamps:       sql.KVStringOptRequireNoValue,
 	optDiff:                    sql.KVStringOptRequireNoValue,
+	optSplitColumnFamilies:     sql.KVStringOptRequireNoValue,
+	optTombstones:              sql.KVStringOptRequireNoValue,
+	optSchemaChangeEvents:      sql.KVStringOptRequireValue,
+	optSchemaChangePolicy:      sql.KVStringOptRequireValue,
+	optInitialScan:             sql.KVStringOptRequireValue,
+}
+
+// initialScanTypeFromOpts returns the value of the initial_scan option, which
+// defaults to no initial scan if the changefeed starts from a cursor.
+func initialScanTypeFromOpts(opts map[string]string) initialScanType {
+	if t, ok := opts[optInitialScan]; ok {
+		return initialScanType(t)
+	}
+	if _, ok := opts[optCursor]; ok {
+		return optInitialScanNo
+	}
+	return optInitialScanYes
 }
 
 // changefeedPlanHook implements sql.PlanHookFn.
@@ -178,15 +233,34 @@ func changefeedPlanHook(
 			}
 			statementTime = initialHighWater
 		}
+		// Without an initial scan, the changefeed starts from the statement
+		// time. Otherwise, the initial scan is done at the statement time before
+		// the high-water is set.
+		if initialScanTypeFromOpts(opts) == optInitialScanNo {
+			initialHighWater = statementTime
+		} else {
+			initialHighWater = hlc.Timestamp{}
+		}
+
+		// The target of a changefeed defined by a query is the table of its FROM
+		// clause.
+		targetList := changefeedStmt.Targets
+		var query *changefeedQuery
+		if changefeedStmt.Select != nil {
+			if query, err = newChangefeedQuery(changefeedStmt.Select); err != nil {
+				return err
+			}
+			targetList = tree.TargetList{Tables: tree.TablePatterns{query.table}}
+		}
 
 		// For now, disallow targeting a database or wildcard table selection.
 		// Getting it right as tables enter and leave the set over time is
 		// tricky.
-		if len(changefeedStmt.Targets.Databases) > 0 {
+		if len(targetList.Databases) > 0 {
 			return errors.Errorf(`CHANGEFEED cannot target %s`,
-				tree.AsString(&changefeedStmt.Targets))
+				tree.AsString(&targetList))
 		}
-		for _, t := range changefeedStmt.Targets.Tables {
+		for _, t := range targetList.Tables {
 			p, err := t.NormalizeTablePattern()
 			if err != nil {
 				return err
@@ -198,7 +272,7 @@ func changefeedPlanHook(
 
 		// This grabs table descriptors once to get their ids.
 		targetDescs, _, err := backupccl.ResolveTargetsToDescriptors(
-			ctx, p, statementTime, changefeedStmt.Targets)
+			ctx, p, statementTime, targetList)
 		if err != nil {
 			return err
 		}
@@ -211,6 +285,13 @@ func changefeedPlanHook(
 				if err := validateChangefeedTable(targets, tableDesc); err != nil {
 					return err
 				}
+				if query != nil {
+					// Check the query against the table, so that errors are returned
+					// to the user rather than when the changefeed runs.
+					if _, err := query.plan(&p.ExtendedEvalContext().EvalContext, tableDesc); err != nil {
+						return err
+					}
+				}
 			}
 		}
 
@@ -220,6 +301,9 @@ func changefeedPlanHook(
 			SinkURI:       sinkURI,
 			StatementTime: statementTime,
 		}
+		if changefeedStmt.Select != nil {
+			details.Select = tree.AsString(changefeedStmt.Select)
+		}
 		progress := jobspb.Progress{
 			Progress: &jobspb.Progress_HighWater{HighWater: &initialHighWater},
 			Details: &jobspb.Progress_Changefeed{
@@ -259,6 +343,11 @@ func changefeedPlanHook(
 		if details, err = validateDetails(details); err != nil {
 			return err
 		}
+		if _, ok := details.Opts[optTombstones]; ok && parsedSink.Scheme != sinkSchemeKafka {
+			// Only kafka treats a message without a value as a tombstone. The
+			// other sinks would emit an empty message.
+			return errors.Errorf(`%s is only supported by kafka sinks`, optTombstones)
+		}
 
 		if _, err := getEncoder(details.Opts); err != nil {
 			return err
@@ -354,6 +443,7 @@ func changefeedJobDescription(
 	c := &tree.CreateChangefeed{
 		Targets: changefeed.Targets,
 		SinkURI: tree.NewDString(cleanedSinkURI),
+		Select:  changefeed.Select,
 	}
 	for k, v := range opts {
 		opt := tree.KVOption{Key: tree.Name(k)}
@@ -392,10 +482,45 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 		details.Opts[optEnvelope] = string(optEnvelopeKeyOnly)
 	case ``, optEnvelopeWrapped:
 		details.Opts[optEnvelope] = string(optEnvelopeWrapped)
+	case optEnvelopeDebezium:
+		details.Opts[optEnvelope] = string(optEnvelopeDebezium)
 	default:
 		return jobspb.ChangefeedDetails{}, errors.Errorf(
 			`unknown %s: %s`, optEnvelope, details.Opts[optEnvelope])
 	}
+	if _, ok := details.Opts[optTombstones]; ok &&
+		envelopeType(details.Opts[optEnvelope]) != optEnvelopeDebezium {
+		return jobspb.ChangefeedDetails{}, errors.Errorf(
+			`%s is only usable with %s=%s`, optTombstones, optEnvelope, optEnvelopeDebezium)
+	}
+
+	switch t := initialScanTypeFromOpts(details.Opts); t {
+	case optInitialScanYes, optInitialScanNo, optInitialScanOnly:
+		details.Opts[optInitialScan] = string(t)
+	default:
+		return jobspb.ChangefeedDetails{}, errors.Errorf(
+			`unknown %s: %s`, optInitialScan, details.Opts[optInitialScan])
+	}
+
+	switch schemaChangeEventClass(details.Opts[optSchemaChangeEvents]) {
+	case ``, optSchemaChangeEventClassDefault:
+		details.Opts[optSchemaChangeEvents] = string(optSchemaChangeEventClassDefault)
+	case optSchemaChangeEventClassColumnChange:
+		// No-op.
+	default:
+		return jobspb.ChangefeedDetails{}, errors.Errorf(
+			`unknown %s: %s`, optSchemaChangeEvents, details.Opts[optSchemaChangeEvents])
+	}
+
+	switch schemaChangePolicy(details.Opts[optSchemaChangePolicy]) {
+	case ``, optSchemaChangePolicyBackfill:
+		details.Opts[optSchemaChangePolicy] = string(optSchemaChangePolicyBackfill)
+	case optSchemaChangePolicyNoBackfill, optSchemaChangePolicyStop:
+		// No-op.
+	default:
+		return jobspb.ChangefeedDetails{}, errors.Errorf(
+			`unknown %s: %s`, optSchemaChangePolicy, details.Opts[optSchemaChangePolicy])
+	}
 
 	switch formatType(details.Opts[optFormat]) {
 	case ``, optFormatJSON:
@@ -407,6 +532,22 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 			`unknown %s: %s`, optFormat, details.Opts[optFormat])
 	}
 
+	if details.Select != `` {
+		// The query is evaluated on full rows, and the previous values of the
+		// rows are only available to it through cdc_prev.
+		for _, opt := range []string{optDiff, optSplitColumnFamilies} {
+			if _, ok := details.Opts[opt]; ok {
+				return jobspb.ChangefeedDetails{}, errors.Errorf(
+					`%s is not supported by changefeeds defined by a query`, opt)
+			}
+		}
+		if envelopeType(details.Opts[optEnvelope]) == optEnvelopeDebezium {
+			return jobspb.ChangefeedDetails{}, errors.Errorf(
+				`%s=%s is not supported by changefeeds defined by a query`,
+				optEnvelope, optEnvelopeDebezium)
+		}
+	}
+
 	return details, nil
 }
 
@@ -435,12 +576,6 @@ func validateChangefeedTable(
 	if tableDesc.IsSequence() {
 		return errors.Errorf(`CHANGEFEED cannot target sequences: %s`, tableDesc.Name)
 	}
-	if len(tableDesc.Families) != 1 {
-		return errors.Errorf(
-			`CHANGEFEEDs are currently supported on tables with exactly 1 column family: %s has %d`,
-			tableDesc.Name, len(tableDesc.Families))
-	}
-
 	if tableDesc.State == sqlbase.TableDescriptor_DROP {
 		return errors.Errorf(`"%s" was dropped or truncated`, t.StatementTimeName)
 	}
@@ -502,7 +637,7 @@ func (b *changefeedResumer) Resume(
 	// progress high-water when creating a job (currently only the progress
 	// details can be set). I didn't want to pick off the refactor to get this
 	// fix in, but it'd be nice to remove this hack.
-	if _, ok := details.Opts[optCursor]; ok {
+	if initialScanTypeFromOpts(details.Opts) == optInitialScanNo {
 		if h := progress.GetHighWater(); h == nil || *h == (hlc.Timestamp{}) {
 			progress.Progress = &jobspb.Progress_HighWater{HighWater: &details.StatementTime}
 		}
@@ -522,6 +657,10 @@ func (b *changefeedResumer) Resume(
 		if err = distChangefeedFlow(ctx, phs, jobID, details, progress, startedCh); err == nil {
 			return nil
 		}
+		if isSchemaChangeStopError(err) {
+			log.Infof(ctx, `CHANGEFEED job %d pausing: %v`, jobID, err)
+			return b.pauseAtSchemaChange(ctx, execCfg.JobRegistry, err)
+		}
 		if !IsRetryableError(err) {
 			log.Warningf(ctx, `CHANGEFEED job %d returning with error: %+v`, jobID, err)
 			return err
@@ -553,6 +692,26 @@ func (b *changefeedResumer) Resume(
 	return errors.Wrap(err, `ran out of retries`)
 }
 
+// pauseAtSchemaChange pauses the job of a changefeed that stopped at a schema
+// change because of its schema_change_policy, instead of failing it, so that it
+// continues past the schema change once it's resumed. The error is kept as the
+// running status of the job.
+func (b *changefeedResumer) pauseAtSchemaChange(
+	ctx context.Context, registry *jobs.Registry, stopErr error,
+) error {
+	if err := b.job.RunningStatus(ctx, func(context.Context, jobspb.Details) (jobs.RunningStatus, error) {
+		return jobs.RunningStatus(stopErr.Error()), nil
+	}); err != nil {
+		return err
+	}
+	if err := registry.Pause(ctx, nil /* txn */, *b.job.ID()); err != nil {
+		return err
+	}
+	// The registry leaves a job paused instead of failing it if its resumer
+	// returns the error of an operation that found it paused.
+	return b.job.CheckStatus(ctx)
+}
+
 // OnFailOrCancel is part of the jobs.Resumer interface.
 func (b *changefeedResumer) OnFailOrCancel(context.Context, *client.Txn) error { return nil }
 
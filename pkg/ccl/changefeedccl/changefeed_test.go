@@ -181,6 +181,48 @@ func TestChangefeedEnvelope(t *testing.T) {
 	t.Run(`enterprise`, enterpriseTest(testFn))
 }
 
+func TestChangefeedDebeziumEnvelope(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH envelope='debezium'`)
+		defer closeFeed(t, foo)
+		assertPayloadsStripTs(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}, "before": null, "op": "r", ` +
+				`"source": {"connector": "cockroachdb", "snapshot": true, "table": "foo"}}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'b')`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'c' WHERE a = 1`)
+		assertPayloadsStripTs(t, foo, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "b"}, "before": null, "op": "c", ` +
+				`"source": {"connector": "cockroachdb", "snapshot": false, "table": "foo"}}`,
+			`foo: [1]->{"after": {"a": 1, "b": "c"}, "before": {"a": 1, "b": "a"}, "op": "u", ` +
+				`"source": {"connector": "cockroachdb", "snapshot": false, "table": "foo"}}`,
+		})
+
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		assertPayloadsStripTs(t, foo, []string{
+			`foo: [1]->{"after": null, "before": {"a": 1, "b": "c"}, "op": "d", ` +
+				`"source": {"connector": "cockroachdb", "snapshot": false, "table": "foo"}}`,
+		})
+
+		sqlDB.ExpectErr(t, `tombstones is only usable with envelope=debezium`,
+			`CREATE CHANGEFEED FOR foo WITH tombstones`)
+		sqlDB.ExpectErr(t, `tombstones is only supported by kafka sinks`,
+			`CREATE CHANGEFEED FOR foo WITH envelope='debezium', tombstones`)
+		sqlDB.ExpectErr(t, `updated is not supported with envelope=debezium`,
+			`CREATE CHANGEFEED FOR foo WITH envelope='debezium', updated`)
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 func TestChangefeedMultiTable(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -794,6 +836,205 @@ func TestChangefeedSchemaChangeAllowBackfill(t *testing.T) {
 	}
 }
 
+func TestChangefeedSchemaChangePolicy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	scope := log.Scope(t)
+	defer scope.Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+
+		t.Run(`nobackfill`, func(t *testing.T) {
+			sqlDB.Exec(t, `CREATE TABLE no_backfill (a INT PRIMARY KEY)`)
+			sqlDB.Exec(t, `INSERT INTO no_backfill VALUES (1), (2)`)
+			noBackfill := feed(t, f, `CREATE CHANGEFEED FOR no_backfill `+
+				`WITH schema_change_policy='nobackfill'`)
+			defer closeFeed(t, noBackfill)
+			assertPayloads(t, noBackfill, []string{
+				`no_backfill: [1]->{"after": {"a": 1}}`,
+				`no_backfill: [2]->{"after": {"a": 2}}`,
+			})
+			sqlDB.Exec(t, `ALTER TABLE no_backfill ADD COLUMN b STRING DEFAULT 'd'`)
+			// Schema change backfill
+			assertPayloads(t, noBackfill, []string{
+				`no_backfill: [1]->{"after": {"a": 1}}`,
+				`no_backfill: [2]->{"after": {"a": 2}}`,
+			})
+			// No changefeed level backfill.
+			sqlDB.Exec(t, `INSERT INTO no_backfill VALUES (3)`)
+			assertPayloads(t, noBackfill, []string{
+				`no_backfill: [3]->{"after": {"a": 3, "b": "d"}}`,
+			})
+		})
+
+		t.Run(`nobackfill column_changes`, func(t *testing.T) {
+			sqlDB.Exec(t, `CREATE TABLE no_backfill_drop (a INT PRIMARY KEY, b STRING)`)
+			sqlDB.Exec(t, `INSERT INTO no_backfill_drop VALUES (1, 'one'), (2, 'two')`)
+			noBackfillDrop := feed(t, f, `CREATE CHANGEFEED FOR no_backfill_drop `+
+				`WITH schema_change_policy='nobackfill', schema_change_events='column_changes'`)
+			defer closeFeed(t, noBackfillDrop)
+			assertPayloads(t, noBackfillDrop, []string{
+				`no_backfill_drop: [1]->{"after": {"a": 1, "b": "one"}}`,
+				`no_backfill_drop: [2]->{"after": {"a": 2, "b": "two"}}`,
+			})
+			// Dropping the column is still a schema change event, but only the
+			// schema change backfill and the new row are emitted.
+			sqlDB.Exec(t, `ALTER TABLE no_backfill_drop DROP COLUMN b`)
+			sqlDB.Exec(t, `INSERT INTO no_backfill_drop VALUES (3)`)
+			assertPayloads(t, noBackfillDrop, []string{
+				`no_backfill_drop: [1]->{"after": {"a": 1}}`,
+				`no_backfill_drop: [2]->{"after": {"a": 2}}`,
+				`no_backfill_drop: [3]->{"after": {"a": 3}}`,
+			})
+		})
+
+		t.Run(`stop`, func(t *testing.T) {
+			sqlDB.Exec(t, `CREATE TABLE stop_policy (a INT PRIMARY KEY)`)
+			sqlDB.Exec(t, `INSERT INTO stop_policy VALUES (1), (2)`)
+			stop := feed(t, f, `CREATE CHANGEFEED FOR stop_policy WITH schema_change_policy='stop'`)
+			defer closeFeed(t, stop)
+			assertPayloads(t, stop, []string{
+				`stop_policy: [1]->{"after": {"a": 1}}`,
+				`stop_policy: [2]->{"after": {"a": 2}}`,
+			})
+			sqlDB.Exec(t, `ALTER TABLE stop_policy ADD COLUMN b STRING DEFAULT 'd'`)
+			// Schema change backfill
+			assertPayloads(t, stop, []string{
+				`stop_policy: [1]->{"after": {"a": 1}}`,
+				`stop_policy: [2]->{"after": {"a": 2}}`,
+			})
+			e, ok := stop.(*cdctest.TableFeed)
+			if !ok {
+				if _, err := stop.Next(); !testutils.IsError(err, `schema change occurred at`) {
+					t.Fatalf(`expected "schema change occurred at" error got: %+v`, err)
+				}
+				return
+			}
+			// A changefeed job is paused instead, and continues after the schema
+			// change once it's resumed, without a changefeed level backfill.
+			testutils.SucceedsSoon(t, func() error {
+				var status string
+				var runningStatus gosql.NullString
+				sqlDB.QueryRow(t, `SELECT status, running_status FROM [SHOW JOBS] WHERE job_id=$1`,
+					e.JobID).Scan(&status, &runningStatus)
+				if status != string(jobs.StatusPaused) {
+					return errors.Errorf(`expected job to be paused, got %s`, status)
+				}
+				if !strings.Contains(runningStatus.String, `schema change occurred at`) {
+					t.Fatalf(`expected "schema change occurred at" running status got: %s`, runningStatus.String)
+				}
+				return nil
+			})
+			require.NoError(t, stop.Resume())
+			sqlDB.Exec(t, `INSERT INTO stop_policy VALUES (3)`)
+			assertPayloads(t, stop, []string{
+				`stop_policy: [3]->{"after": {"a": 3, "b": "d"}}`,
+			})
+		})
+
+		t.Run(`column_changes`, func(t *testing.T) {
+			sqlDB.Exec(t, `CREATE TABLE column_changes (a INT PRIMARY KEY)`)
+			sqlDB.Exec(t, `INSERT INTO column_changes VALUES (1)`)
+			columnChanges := feed(t, f, `CREATE CHANGEFEED FOR column_changes `+
+				`WITH schema_change_events='column_changes'`)
+			defer closeFeed(t, columnChanges)
+			assertPayloads(t, columnChanges, []string{
+				`column_changes: [1]->{"after": {"a": 1}}`,
+			})
+			// Adding a nullable column doesn't need a backfill, but it's still a
+			// schema change event.
+			sqlDB.Exec(t, `ALTER TABLE column_changes ADD COLUMN b STRING`)
+			assertPayloads(t, columnChanges, []string{
+				`column_changes: [1]->{"after": {"a": 1, "b": null}}`,
+			})
+		})
+
+		sqlDB.ExpectErr(t, `unknown schema_change_policy: foo`,
+			`CREATE CHANGEFEED FOR no_backfill WITH schema_change_policy='foo'`)
+		sqlDB.ExpectErr(t, `unknown schema_change_events: foo`,
+			`CREATE CHANGEFEED FOR no_backfill WITH schema_change_events='foo'`)
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
+func TestChangefeedInitialScan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+
+		t.Run(`no`, func(t *testing.T) {
+			sqlDB.Exec(t, `CREATE TABLE initial_scan_no (a INT PRIMARY KEY)`)
+			sqlDB.Exec(t, `INSERT INTO initial_scan_no VALUES (1)`)
+			noScan := feed(t, f, `CREATE CHANGEFEED FOR initial_scan_no WITH initial_scan='no'`)
+			defer closeFeed(t, noScan)
+			sqlDB.Exec(t, `INSERT INTO initial_scan_no VALUES (2)`)
+			assertPayloads(t, noScan, []string{
+				`initial_scan_no: [2]->{"after": {"a": 2}}`,
+			})
+		})
+
+		t.Run(`yes with cursor`, func(t *testing.T) {
+			sqlDB.Exec(t, `CREATE TABLE initial_scan_yes (a INT PRIMARY KEY)`)
+			sqlDB.Exec(t, `INSERT INTO initial_scan_yes VALUES (1)`)
+			var tsLogical string
+			sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&tsLogical)
+			sqlDB.Exec(t, `INSERT INTO initial_scan_yes VALUES (2)`)
+			yesScan := feed(t, f, `CREATE CHANGEFEED FOR initial_scan_yes `+
+				`WITH cursor=$1, initial_scan='yes'`, tsLogical)
+			defer closeFeed(t, yesScan)
+			assertPayloads(t, yesScan, []string{
+				`initial_scan_yes: [1]->{"after": {"a": 1}}`,
+				`initial_scan_yes: [2]->{"after": {"a": 2}}`,
+			})
+		})
+
+		t.Run(`only`, func(t *testing.T) {
+			sqlDB.Exec(t, `CREATE TABLE initial_scan_only (a INT PRIMARY KEY)`)
+			sqlDB.Exec(t, `INSERT INTO initial_scan_only VALUES (1), (2)`)
+			onlyScan := feed(t, f, `CREATE CHANGEFEED FOR initial_scan_only WITH initial_scan='only'`)
+			defer closeFeed(t, onlyScan)
+			assertPayloads(t, onlyScan, []string{
+				`initial_scan_only: [1]->{"after": {"a": 1}}`,
+				`initial_scan_only: [2]->{"after": {"a": 2}}`,
+			})
+
+			// Once the scan is done, the changefeed finishes on its own: sinkless
+			// feeds run out of rows and jobs succeed.
+			if e, ok := onlyScan.(*cdctest.TableFeed); ok {
+				testutils.SucceedsSoon(t, func() error {
+					var status string
+					sqlDB.QueryRow(t, `SELECT status FROM [SHOW JOBS] WHERE job_id=$1`,
+						e.JobID).Scan(&status)
+					if status != string(jobs.StatusSucceeded) {
+						return errors.Errorf(`expected job to succeed, got %s`, status)
+					}
+					return nil
+				})
+			} else {
+				for {
+					m, err := onlyScan.Next()
+					require.NoError(t, err)
+					if m == nil {
+						break
+					}
+					if len(m.Key) > 0 || len(m.Value) > 0 {
+						t.Fatalf(`unexpected row after the initial scan: %s->%s`, m.Key, m.Value)
+					}
+				}
+			}
+		})
+
+		sqlDB.ExpectErr(t, `unknown initial_scan: foo`,
+			`CREATE CHANGEFEED FOR initial_scan_no WITH initial_scan='foo'`)
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 // fetchDescVersionModificationTime fetches the `ModificationTime` of the specified
 // `version` of `tableName`'s table descriptor.
 func fetchDescVersionModificationTime(
@@ -951,12 +1192,57 @@ func TestChangefeedColumnFamily(t *testing.T) {
 		sqlDB := sqlutils.MakeSQLRunner(db)
 
 		// Table with 2 column families.
-		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, FAMILY (a), FAMILY (b))`)
-		if strings.Contains(t.Name(), `enterprise`) {
-			sqlDB.ExpectErr(t, `exactly 1 column family`, `CREATE CHANGEFEED FOR foo`)
-		} else {
-			sqlDB.ExpectErr(t, `exactly 1 column family`, `EXPERIMENTAL CHANGEFEED FOR foo`)
-		}
+		sqlDB.Exec(t, `CREATE TABLE foo (
+			a INT PRIMARY KEY, b STRING, c STRING, FAMILY f_a (a, b), FAMILY f_c (c)
+		)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'dog', 'cat')`)
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "dog", "c": "cat"}}`,
+		})
+		// A change to a single family emits the full row.
+		sqlDB.Exec(t, `UPDATE foo SET c = 'lion' WHERE a = 0`)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "dog", "c": "lion"}}`,
+		})
+		sqlDB.Exec(t, `UPDATE foo SET c = NULL WHERE a = 0`)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "dog", "c": null}}`,
+		})
+		// A transaction that changes several families of a row emits it once.
+		sqlDB.Exec(t, `BEGIN; UPDATE foo SET c = 'tiger' WHERE a = 0; UPDATE foo SET b = 'wolf' WHERE a = 0; COMMIT`)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "wolf", "c": "tiger"}}`,
+		})
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 0`)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": null}`,
+		})
+
+		// Each family is emitted separately with split_column_families.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'dog', 'cat')`)
+		fooSplit := feed(t, f, `CREATE CHANGEFEED FOR foo WITH split_column_families`)
+		defer closeFeed(t, fooSplit)
+		assertPayloads(t, fooSplit, []string{
+			`foo.f_a: [1]->{"after": {"a": 1, "b": "dog"}}`,
+			`foo.f_c: [1]->{"after": {"a": 1, "c": "cat"}}`,
+		})
+		sqlDB.Exec(t, `UPDATE foo SET c = 'lion' WHERE a = 1`)
+		assertPayloads(t, fooSplit, []string{
+			`foo.f_c: [1]->{"after": {"a": 1, "c": "lion"}}`,
+		})
+		// Setting all the columns of a family to NULL deletes its kv, but the
+		// row still exists.
+		sqlDB.Exec(t, `UPDATE foo SET c = NULL WHERE a = 1`)
+		assertPayloads(t, fooSplit, []string{
+			`foo.f_c: [1]->{"after": {"a": 1, "c": null}}`,
+		})
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		assertPayloads(t, fooSplit, []string{
+			`foo.f_a: [1]->{"after": null}`,
+			`foo.f_c: [1]->{"after": null}`,
+		})
 
 		// Table with a second column family added after the changefeed starts.
 		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY, FAMILY f_a (a))`)
@@ -967,10 +1253,75 @@ func TestChangefeedColumnFamily(t *testing.T) {
 			`bar: [0]->{"after": {"a": 0}}`,
 		})
 		sqlDB.Exec(t, `ALTER TABLE bar ADD COLUMN b STRING CREATE FAMILY f_b`)
-		sqlDB.Exec(t, `INSERT INTO bar VALUES (1)`)
-		if _, err := bar.Next(); !testutils.IsError(err, `exactly 1 column family`) {
-			t.Errorf(`expected "exactly 1 column family" error got: %+v`, err)
-		}
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (1, 'dog')`)
+		assertPayloads(t, bar, []string{
+			`bar: [1]->{"after": {"a": 1, "b": "dog"}}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
+func TestChangefeedQuery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'dog', 10), (2, 'cat', 20)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED AS SELECT upper(b) AS b, c + 1 FROM foo WHERE c > 10`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"after": {"?column?": 21, "b": "CAT"}}`,
+		})
+		sqlDB.Exec(t, `UPDATE foo SET c = 30 WHERE a = 1`)
+		sqlDB.Exec(t, `UPDATE foo SET c = 0 WHERE a = 2`)
+		sqlDB.Exec(t, `UPDATE foo SET c = 40 WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"?column?": 31, "b": "DOG"}}`,
+			`foo: [1]->{"after": {"?column?": 41, "b": "DOG"}}`,
+		})
+		// Deletions are only emitted if the previous value of the row satisfies
+		// the WHERE clause.
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (4, 'fox', 50)`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 4`)
+		assertPayloads(t, foo, []string{
+			`foo: [4]->{"after": {"?column?": 51, "b": "FOX"}}`,
+			`foo: [4]->{"after": null}`,
+		})
+
+		// The previous value of a row is accessible through cdc_prev.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'bird', 30)`)
+		fooPrev := feed(t, f, `CREATE CHANGEFEED AS SELECT f.*, cdc_prev->'c' AS prev_c FROM foo AS f
+			WHERE cdc_prev IS NULL OR (cdc_prev->>'c')::INT < c`)
+		defer closeFeed(t, fooPrev)
+		assertPayloads(t, fooPrev, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "dog", "c": 40, "prev_c": null}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "bird", "c": 30, "prev_c": null}}`,
+		})
+		sqlDB.Exec(t, `UPDATE foo SET c = 20 WHERE a = 1`)
+		sqlDB.Exec(t, `UPDATE foo SET c = 50 WHERE a = 3`)
+		assertPayloads(t, fooPrev, []string{
+			`foo: [3]->{"after": {"a": 3, "b": "bird", "c": 50, "prev_c": 30}}`,
+		})
+
+		sqlDB.ExpectErr(t, `impure functions are not allowed in SELECT`,
+			`CREATE CHANGEFEED AS SELECT a, now() FROM foo`)
+		sqlDB.ExpectErr(t, `aggregate functions are not allowed in SELECT`,
+			`CREATE CHANGEFEED AS SELECT count(*) FROM foo`)
+		sqlDB.ExpectErr(t, `changefeed query must select from exactly one table`,
+			`CREATE CHANGEFEED AS SELECT * FROM foo, foo AS bar`)
+		sqlDB.ExpectErr(t, `do not support WITH, ORDER BY, LIMIT or locking clauses`,
+			`CREATE CHANGEFEED AS SELECT * FROM foo LIMIT 1`)
+		sqlDB.ExpectErr(t, `diff is not supported by changefeeds defined by a query`,
+			`CREATE CHANGEFEED WITH diff AS SELECT * FROM foo`)
+		sqlDB.ExpectErr(t, `column "d" does not exist`,
+			`CREATE CHANGEFEED AS SELECT d FROM foo`)
+		sqlDB.ExpectErr(t, `changefeed query has duplicate column name`,
+			`CREATE CHANGEFEED AS SELECT a + 1, c + 1 FROM foo`)
 	}
 
 	t.Run(`sinkless`, sinklessTest(testFn))
@@ -16,6 +16,7 @@ import (
 	"io/ioutil"
 	"net/url"
 	"path/filepath"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -23,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/pkg/errors"
 )
 
@@ -44,9 +46,22 @@ type encodeRow struct {
 	// deleted is true if row is a deletion. In this case, only the primary
 	// key columns are guaranteed to be set in `datums`.
 	deleted bool
+	// initialScan is true if the row was emitted by the initial scan of the
+	// changefeed, rather than because it changed.
+	initialScan bool
 	// tableDesc is a TableDescriptor for the table containing `datums`.
 	// It's valid for interpreting the row at `updated`.
 	tableDesc *sqlbase.TableDescriptor
+	// familyID is the column family whose columns are in `datums` if the
+	// column families of the table are emitted separately, in which case
+	// `tableDesc` only has the primary key columns and the columns of the
+	// family.
+	familyID sqlbase.FamilyID
+	// numValueCols, if non-zero, is the number of leading columns of
+	// `tableDesc` that make up the value of the row, the other columns only
+	// being used to encode its key. It is set for the rows emitted by the
+	// query of a changefeed, which don't necessarily contain the primary key.
+	numValueCols int
 	// prevDatums is the old value of a changed table row. The field is set
 	// to nil if the before value for changes was not requested (optDiff).
 	prevDatums sqlbase.EncDatumRow
@@ -57,6 +72,15 @@ type encodeRow struct {
 	prevTableDesc *sqlbase.TableDescriptor
 }
 
+// valueColumns returns the columns of `tableDesc` that make up the value of the
+// row.
+func (r encodeRow) valueColumns() []sqlbase.ColumnDescriptor {
+	if r.numValueCols > 0 {
+		return r.tableDesc.Columns[:r.numValueCols]
+	}
+	return r.tableDesc.Columns
+}
+
 // Encoder turns a row into a serialized changefeed key, value, or resolved
 // timestamp. It represents one of the `format=` changefeed options.
 type Encoder interface {
@@ -87,12 +111,52 @@ func getEncoder(opts map[string]string) (Encoder, error) {
 	}
 }
 
+// debeziumConnector is the name under which changefeeds identify themselves in
+// the `source` field of the debezium envelope.
+const debeziumConnector = `cockroachdb`
+
+// debeziumOp returns the debezium operation of a row change: `r` for the rows
+// of the initial scan, `c` for inserts, `u` for updates and `d` for deletes.
+func debeziumOp(row encodeRow) string {
+	switch {
+	case row.deleted:
+		return `d`
+	case row.initialScan:
+		return `r`
+	case row.prevDeleted:
+		return `c`
+	default:
+		return `u`
+	}
+}
+
+// debeziumTimestamp converts a wall time in nanoseconds to the milliseconds
+// used by the `ts_ms` fields of the debezium envelope.
+func debeziumTimestamp(wallTime int64) int64 {
+	return wallTime / int64(time.Millisecond)
+}
+
+// debeziumSource returns the `source` field of the debezium envelope, which
+// describes where and when a row change happened. `ts_ms` is the time of the
+// change, `ts_hlc` is the same time with the full precision of the `updated`
+// option.
+func debeziumSource(row encodeRow) map[string]interface{} {
+	return map[string]interface{}{
+		`connector`: debeziumConnector,
+		`table`:     row.tableDesc.Name,
+		`ts_ms`:     debeziumTimestamp(row.updated.WallTime),
+		`ts_hlc`:    row.updated.AsOfSystemTime(),
+		`snapshot`:  row.initialScan,
+	}
+}
+
 // jsonEncoder encodes changefeed entries as JSON. Keys are the primary key
 // columns in a JSON array. Values are a JSON object mapping every column name
 // to its value. Updated timestamps in rows and resolved timestamp payloads are
 // stored in a sub-object under the `__crdb__` key in the top-level JSON object.
 type jsonEncoder struct {
 	updatedField, beforeField, wrapped, keyOnly, keyInValue bool
+	debezium                                                bool
 
 	alloc sqlbase.DatumAlloc
 	buf   bytes.Buffer
@@ -102,10 +166,15 @@ var _ Encoder = &jsonEncoder{}
 
 func makeJSONEncoder(opts map[string]string) (*jsonEncoder, error) {
 	e := &jsonEncoder{
-		keyOnly: envelopeType(opts[optEnvelope]) == optEnvelopeKeyOnly,
-		wrapped: envelopeType(opts[optEnvelope]) == optEnvelopeWrapped,
+		keyOnly:  envelopeType(opts[optEnvelope]) == optEnvelopeKeyOnly,
+		wrapped:  envelopeType(opts[optEnvelope]) == optEnvelopeWrapped,
+		debezium: envelopeType(opts[optEnvelope]) == optEnvelopeDebezium,
 	}
 	_, e.updatedField = opts[optUpdatedTimestamps]
+	if e.updatedField && e.debezium {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			optUpdatedTimestamps, optEnvelope, optEnvelopeDebezium)
+	}
 	_, e.beforeField = opts[optDiff]
 	if e.beforeField && !e.wrapped {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
@@ -157,13 +226,13 @@ func (e *jsonEncoder) encodeKeyRaw(row encodeRow) ([]interface{}, error) {
 
 // EncodeValue implements the Encoder interface.
 func (e *jsonEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, error) {
-	if e.keyOnly || (!e.wrapped && row.deleted) {
+	if e.keyOnly || (!e.wrapped && !e.debezium && row.deleted) {
 		return nil, nil
 	}
 
 	var after map[string]interface{}
 	if !row.deleted {
-		columns := row.tableDesc.Columns
+		columns := row.valueColumns()
 		after = make(map[string]interface{}, len(columns))
 		for i := range columns {
 			col := &columns[i]
@@ -198,7 +267,23 @@ func (e *jsonEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, err
 	}
 
 	var jsonEntries map[string]interface{}
-	if e.wrapped {
+	if e.debezium {
+		jsonEntries = map[string]interface{}{
+			`op`:     debeziumOp(row),
+			`ts_ms`:  debeziumTimestamp(timeutil.Now().UnixNano()),
+			`source`: debeziumSource(row),
+		}
+		if after != nil {
+			jsonEntries[`after`] = after
+		} else {
+			jsonEntries[`after`] = nil
+		}
+		if before != nil {
+			jsonEntries[`before`] = before
+		} else {
+			jsonEntries[`before`] = nil
+		}
+	} else if e.wrapped {
 		if after != nil {
 			jsonEntries = map[string]interface{}{`after`: after}
 		} else {
@@ -264,19 +349,27 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(
 // JSON format. Keys are the primary key columns in a record. Values are all
 // columns in a record.
 type confluentAvroEncoder struct {
-	registryURL                        string
-	updatedField, beforeField, keyOnly bool
+	registryURL                                  string
+	updatedField, beforeField, keyOnly, debezium bool
 
 	keyCache      map[tableIDAndVersion]confluentRegisteredKeySchema
 	valueCache    map[tableIDAndVersionPair]confluentRegisteredEnvelopeSchema
 	resolvedCache map[string]confluentRegisteredEnvelopeSchema
 }
 
-type tableIDAndVersion uint64
+type tableIDAndVersion struct {
+	id      sqlbase.ID
+	version sqlbase.DescriptorVersion
+	// familyID is only set if the column families of the table are emitted
+	// separately.
+	familyID sqlbase.FamilyID
+}
 type tableIDAndVersionPair [2]tableIDAndVersion // [before, after]
 
-func makeTableIDAndVersion(id sqlbase.ID, version sqlbase.DescriptorVersion) tableIDAndVersion {
-	return tableIDAndVersion(id)<<32 + tableIDAndVersion(version)
+func makeTableIDAndVersion(
+	id sqlbase.ID, version sqlbase.DescriptorVersion, familyID sqlbase.FamilyID,
+) tableIDAndVersion {
+	return tableIDAndVersion{id: id, version: version, familyID: familyID}
 }
 
 type confluentRegisteredKeySchema struct {
@@ -298,6 +391,8 @@ func newConfluentAvroEncoder(opts map[string]string) (*confluentAvroEncoder, err
 	case string(optEnvelopeKeyOnly):
 		e.keyOnly = true
 	case string(optEnvelopeWrapped):
+	case string(optEnvelopeDebezium):
+		e.debezium = true
 	default:
 		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
 			optEnvelope, opts[optEnvelope], optFormat, optFormatAvro)
@@ -307,11 +402,17 @@ func newConfluentAvroEncoder(opts map[string]string) (*confluentAvroEncoder, err
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			optUpdatedTimestamps, optEnvelope, optEnvelopeWrapped)
 	}
+	if e.updatedField && e.debezium {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			optUpdatedTimestamps, optEnvelope, optEnvelopeDebezium)
+	}
 	_, e.beforeField = opts[optDiff]
-	if e.beforeField && e.keyOnly {
+	if e.beforeField && (e.keyOnly || e.debezium) {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			optDiff, optEnvelope, optEnvelopeWrapped)
 	}
+	// The debezium envelope always has the before value of the row.
+	e.beforeField = e.beforeField || e.debezium
 
 	if _, ok := opts[optKeyInValue]; ok {
 		return nil, errors.Errorf(`%s is not supported with %s=%s`,
@@ -331,7 +432,7 @@ func newConfluentAvroEncoder(opts map[string]string) (*confluentAvroEncoder, err
 
 // EncodeKey implements the Encoder interface.
 func (e *confluentAvroEncoder) EncodeKey(ctx context.Context, row encodeRow) ([]byte, error) {
-	cacheKey := makeTableIDAndVersion(row.tableDesc.ID, row.tableDesc.Version)
+	cacheKey := makeTableIDAndVersion(row.tableDesc.ID, row.tableDesc.Version, row.familyID)
 	registered, ok := e.keyCache[cacheKey]
 	if !ok {
 		var err error
@@ -368,9 +469,11 @@ func (e *confluentAvroEncoder) EncodeValue(ctx context.Context, row encodeRow) (
 
 	var cacheKey tableIDAndVersionPair
 	if e.beforeField && row.prevTableDesc != nil {
-		cacheKey[0] = makeTableIDAndVersion(row.prevTableDesc.ID, row.prevTableDesc.Version)
+		cacheKey[0] = makeTableIDAndVersion(
+			row.prevTableDesc.ID, row.prevTableDesc.Version, row.familyID,
+		)
 	}
-	cacheKey[1] = makeTableIDAndVersion(row.tableDesc.ID, row.tableDesc.Version)
+	cacheKey[1] = makeTableIDAndVersion(row.tableDesc.ID, row.tableDesc.Version, row.familyID)
 	registered, ok := e.valueCache[cacheKey]
 	if !ok {
 		var beforeDataSchema *avroDataRecord
@@ -382,12 +485,23 @@ func (e *confluentAvroEncoder) EncodeValue(ctx context.Context, row encodeRow) (
 			}
 		}
 
-		afterDataSchema, err := tableToAvroSchema(row.tableDesc, avroSchemaNoSuffix)
+		afterDesc := row.tableDesc
+		if row.numValueCols > 0 {
+			valueDesc := *row.tableDesc
+			valueDesc.Columns = row.valueColumns()
+			afterDesc = &valueDesc
+		}
+		afterDataSchema, err := tableToAvroSchema(afterDesc, avroSchemaNoSuffix)
 		if err != nil {
 			return nil, err
 		}
 
-		opts := avroEnvelopeOpts{afterField: true, beforeField: e.beforeField, updatedField: e.updatedField}
+		opts := avroEnvelopeOpts{
+			afterField:     true,
+			beforeField:    e.beforeField,
+			updatedField:   e.updatedField,
+			debeziumFields: e.debezium,
+		}
 		registered.schema, err = envelopeToAvroSchema(row.tableDesc.Name, opts, beforeDataSchema, afterDataSchema)
 		if err != nil {
 			return nil, err
@@ -409,6 +523,13 @@ func (e *confluentAvroEncoder) EncodeValue(ctx context.Context, row encodeRow) (
 			`updated`: row.updated,
 		}
 	}
+	if registered.schema.opts.debeziumFields {
+		meta = map[string]interface{}{
+			`op`:     debeziumOp(row),
+			`ts_ms`:  debeziumTimestamp(timeutil.Now().UnixNano()),
+			`source`: debeziumSource(row),
+		}
+	}
 	var beforeDatums, afterDatums sqlbase.EncDatumRow
 	if row.prevDatums != nil && !row.prevDeleted {
 		beforeDatums = row.prevDatums
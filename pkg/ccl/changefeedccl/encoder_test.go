@@ -384,6 +384,45 @@ func TestAvroEncoder(t *testing.T) {
 	t.Run(`enterprise`, enterpriseTest(testFn))
 }
 
+func TestAvroDebeziumEnvelope(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		reg := makeTestSchemaRegistry()
+		defer reg.Close()
+
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'bar')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo `+
+			`WITH format=$1, confluent_schema_registry=$2, envelope='debezium'`,
+			optFormatAvro, reg.server.URL)
+		defer closeFeed(t, foo)
+		m, err := foo.Next()
+		require.NoError(t, err)
+		var value map[string]interface{}
+		require.NoError(t, gojson.Unmarshal(avroToJSON(t, reg, m.Value), &value))
+		require.Equal(t, map[string]interface{}{`string`: `r`}, value[`op`])
+		require.Nil(t, value[`before`])
+		require.Equal(t, map[string]interface{}{
+			`foo`: map[string]interface{}{
+				`a`: map[string]interface{}{`long`: float64(1)},
+				`b`: map[string]interface{}{`string`: `bar`},
+			},
+		}, value[`after`])
+		source := value[`source`].(map[string]interface{})[`foo_source`].(map[string]interface{})
+		require.Equal(t, map[string]interface{}{`string`: `cockroachdb`}, source[`connector`])
+		require.Equal(t, map[string]interface{}{`string`: `foo`}, source[`table`])
+		require.Equal(t, map[string]interface{}{`boolean`: true}, source[`snapshot`])
+		require.NotNil(t, value[`ts_ms`])
+		require.NotNil(t, source[`ts_ms`])
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 func TestAvroMigrateToUnsupportedColumn(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
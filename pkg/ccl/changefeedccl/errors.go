@@ -11,6 +11,9 @@ package changefeedccl
 import (
 	"fmt"
 	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/errors"
 )
 
 const retryableErrorString = "retryable changefeed error"
@@ -68,6 +71,18 @@ func IsRetryableError(err error) bool {
 	}
 }
 
+// schemaChangeStopErrorString is the prefix of the error returned by a
+// changefeed with schema_change_policy=stop once it reaches a schema change.
+const schemaChangeStopErrorString = "schema change occurred at"
+
+// isSchemaChangeStopError returns true if the supplied error is the one
+// returned by a changefeed that stopped at a schema change. Like retryable
+// errors, it may have been serialized by DistSQL, so this relies on the error
+// string.
+func isSchemaChangeStopError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), schemaChangeStopErrorString)
+}
+
 // MaybeStripRetryableErrorMarker performs some minimal attempt to clean the
 // RetryableError marker out. This won't do anything if the RetryableError
 // itself has been wrapped, but that's okay, we'll just have an uglier string.
@@ -77,3 +92,17 @@ func MaybeStripRetryableErrorMarker(err error) error {
 	}
 	return err
 }
+
+// isTransientKVError returns whether err, returned by a KV request, is expected
+// to go away once the cluster is healthy again, such as when a node is
+// unavailable. Errors that retrying can't fix, like the
+// BatchTimestampBeforeGCError of a read below the GC threshold, are not
+// transient.
+func isTransientKVError(err error) bool {
+	switch errors.UnwrapAll(err).(type) {
+	case *roachpb.SendError, *roachpb.NodeUnavailableError, *roachpb.AmbiguousResultError,
+		*roachpb.RangeNotFoundError, *roachpb.UnhandledRetryableError:
+		return true
+	}
+	return false
+}
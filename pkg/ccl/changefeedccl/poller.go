@@ -53,6 +53,15 @@ type poller struct {
 	leaseMgr  *sql.LeaseManager
 	metrics   *Metrics
 	mm        *mon.BytesMonitor
+	// withDiff is set if the previous value of each update is requested from
+	// RangeFeed, because the `diff` option is specified or the query of the
+	// changefeed needs it.
+	withDiff bool
+	// resumedHighWater is the high-water the changefeed was resumed from, if
+	// any. A changefeed with schema_change_policy=stop resolves the timestamp
+	// right before the schema change it stops at, so once it's resumed it
+	// continues past that schema change instead of stopping at it again.
+	resumedHighWater hlc.Timestamp
 
 	mu struct {
 		syncutil.Mutex
@@ -65,6 +74,9 @@ type poller struct {
 		// a backfilling schema change is marked as completed. This collection must
 		// be kept in sorted order (by timestamp ascending).
 		scanBoundaries []hlc.Timestamp
+		// initialScanPending is set until the scan at the first scan boundary is
+		// done if that scan is the initial scan of the changefeed.
+		initialScanPending bool
 		// previousTableVersion is a map from tableID to the most recent version
 		// of the table descriptor seen by the poller. This is needed to determine
 		// when a backilling mutation has successfully completed - this can only
@@ -80,6 +92,7 @@ func makePoller(
 	gossip *gossip.Gossip,
 	spans []roachpb.Span,
 	details jobspb.ChangefeedDetails,
+	withDiff bool,
 	highWater hlc.Timestamp,
 	buf *buffer,
 	leaseMgr *sql.LeaseManager,
@@ -98,6 +111,9 @@ func makePoller(
 		leaseMgr: leaseMgr,
 		metrics:  metrics,
 		mm:       mm,
+		withDiff: withDiff,
+
+		resumedHighWater: highWater,
 	}
 	p.mu.previousTableVersion = make(map[sqlbase.ID]*sqlbase.TableDescriptor)
 	// If no highWater is specified, set the highwater to the statement time
@@ -106,6 +122,7 @@ func makePoller(
 	if highWater == (hlc.Timestamp{}) {
 		p.mu.highWater = details.StatementTime
 		p.mu.scanBoundaries = append(p.mu.scanBoundaries, details.StatementTime)
+		p.mu.initialScanPending = true
 	} else {
 		p.mu.highWater = highWater
 	}
@@ -170,9 +187,7 @@ func (p *poller) rangefeedImpl(ctx context.Context) error {
 }
 
 func (p *poller) rangefeedImplIter(ctx context.Context, i int) error {
-	// Determine whether to request the previous value of each update from
-	// RangeFeed based on whether the `diff` option is specified.
-	_, withDiff := p.details.Opts[optDiff]
+	withDiff := p.withDiff
 
 	p.mu.Lock()
 	lastHighwater := p.mu.highWater
@@ -191,21 +206,43 @@ func (p *poller) rangefeedImplIter(ctx context.Context, i int) error {
 	initialScan := i == 0
 	backfillWithDiff := !initialScan && withDiff
 	var scanTime hlc.Timestamp
+	var scanIsInitial bool
 	p.mu.Lock()
 	if len(p.mu.scanBoundaries) > 0 && p.mu.scanBoundaries[0].Equal(p.mu.highWater) {
 		// Perform a full scan of the latest value of all keys as of the
 		// boundary timestamp and consume the boundary.
 		scanTime = p.mu.scanBoundaries[0]
 		p.mu.scanBoundaries = p.mu.scanBoundaries[1:]
+		scanIsInitial = p.mu.initialScanPending
+		p.mu.initialScanPending = false
 	}
 	p.mu.Unlock()
+	// Every boundary after the statement time is a schema change. Stopping at
+	// it makes sure that all the changes before it are emitted before any change
+	// after it, but only the backfill policy re-emits the table there.
+	if scanTime != (hlc.Timestamp{}) && p.details.StatementTime.Less(scanTime) {
+		switch schemaChangePolicy(p.details.Opts[optSchemaChangePolicy]) {
+		case optSchemaChangePolicyStop:
+			if !scanTime.Equal(p.resumedHighWater.Next()) {
+				return p.stopAtSchemaChange(ctx, scanTime)
+			}
+			scanTime = hlc.Timestamp{}
+		case optSchemaChangePolicyNoBackfill:
+			scanTime = hlc.Timestamp{}
+		}
+	}
 	if scanTime != (hlc.Timestamp{}) {
 		// TODO(dan): Now that we no longer have the poller, we should stop using
 		// ExportRequest and start using normal Scans.
-		if err := p.exportSpansParallel(ctx, spans, scanTime, backfillWithDiff); err != nil {
+		if err := p.exportSpansParallel(
+			ctx, spans, scanTime, backfillWithDiff, scanIsInitial,
+		); err != nil {
 			return err
 		}
 	}
+	if initialScanTypeFromOpts(p.details.Opts) == optInitialScanOnly {
+		return p.stopAfterInitialScan(ctx)
+	}
 
 	// Start rangefeeds, exit polling if we hit a resolved timestamp beyond
 	// the next scan boundary.
@@ -224,8 +261,8 @@ func (p *poller) rangefeedImplIter(ctx context.Context, i int) error {
 	//
 	// TODO(dan): Right now, there are two buffers in the changefeed flow when
 	// using RangeFeeds, one here and the usual one between the poller and the
-	// rest of the changefeed (he latter of which is implemented with an
-	// unbuffered channel, and so doesn't actually buffer). Ideally, we'd have
+	// rest of the changefeed (the latter of which is implemented with a small
+	// channel that only buffers a batch of entries). Ideally, we'd have
 	// one, but the structure of the poller code right now makes this hard.
 	// Specifically, when a schema change happens, we need a barrier where we
 	// flush out every change before the schema change timestamp before we start
@@ -309,7 +346,9 @@ func (p *poller) rangefeedImplIter(ctx context.Context, i int) error {
 				if pastBoundary {
 					continue
 				}
-				if err := p.buf.AddKV(ctx, e.kv, e.prevVal, e.backfillTimestamp); err != nil {
+				if err := p.buf.AddKV(
+					ctx, e.kv, e.prevVal, e.backfillTimestamp, false, /* initialScan */
+				); err != nil {
 					return err
 				}
 			} else if e.resolved != nil {
@@ -361,6 +400,35 @@ func (p *poller) rangefeedImplIter(ctx context.Context, i int) error {
 	return nil
 }
 
+// stopAtSchemaChange resolves the watched spans up to the timestamp just before
+// the schema change at the given timestamp, marking them so that the changefeed
+// stops once they're all resolved. It then waits for the changefeed to shut
+// down, since it must not emit anything after the schema change. A changefeed
+// job is paused rather than failed, so that resuming it picks up right before
+// the schema change.
+func (p *poller) stopAtSchemaChange(ctx context.Context, schemaChangeTS hlc.Timestamp) error {
+	for _, span := range p.spans {
+		if err := p.buf.AddSchemaChangeStop(ctx, span, schemaChangeTS.Prev()); err != nil {
+			return err
+		}
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// stopAfterInitialScan resolves the watched spans at the statement time, which
+// is when the initial scan is done, and then waits for the changefeed to shut
+// down, since it only emits the initial scan.
+func (p *poller) stopAfterInitialScan(ctx context.Context) error {
+	for _, span := range p.spans {
+		if err := p.buf.AddResolved(ctx, span, p.details.StatementTime); err != nil {
+			return err
+		}
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
 func getSpansToProcess(
 	ctx context.Context, db *client.DB, targetSpans []roachpb.Span,
 ) ([]roachpb.Span, error) {
@@ -409,7 +477,7 @@ func getSpansToProcess(
 }
 
 func (p *poller) exportSpansParallel(
-	ctx context.Context, spans []roachpb.Span, ts hlc.Timestamp, withDiff bool,
+	ctx context.Context, spans []roachpb.Span, ts hlc.Timestamp, withDiff, initialScan bool,
 ) error {
 	// Export requests for the various watched spans are executed in parallel,
 	// with a semaphore-enforced limit based on a cluster setting.
@@ -434,7 +502,7 @@ func (p *poller) exportSpansParallel(
 		g.GoCtx(func(ctx context.Context) error {
 			defer func() { <-exportsSem }()
 
-			err := p.exportSpan(ctx, span, ts, withDiff)
+			err := p.exportSpan(ctx, span, ts, withDiff, initialScan)
 			finished := atomic.AddInt64(&atomicFinished, 1)
 			if log.V(2) {
 				log.Infof(ctx, `exported %d of %d: %v`, finished, len(spans), err)
@@ -449,7 +517,7 @@ func (p *poller) exportSpansParallel(
 }
 
 func (p *poller) exportSpan(
-	ctx context.Context, span roachpb.Span, ts hlc.Timestamp, withDiff bool,
+	ctx context.Context, span roachpb.Span, ts hlc.Timestamp, withDiff, initialScan bool,
 ) error {
 	sender := p.db.NonTransactionalSender()
 	if log.V(2) {
@@ -488,7 +556,7 @@ func (p *poller) exportSpan(
 	schemaTimestamp := ts
 	stopwatchStart = timeutil.Now()
 	for _, file := range exported.(*roachpb.ExportResponse).Files {
-		if err := p.slurpSST(ctx, file.SST, schemaTimestamp, withDiff); err != nil {
+		if err := p.slurpSST(ctx, file.SST, schemaTimestamp, withDiff, initialScan); err != nil {
 			return err
 		}
 	}
@@ -529,9 +597,9 @@ func (p *poller) pollTableHistory(ctx context.Context) error {
 }
 
 // slurpSST iterates an encoded sst and inserts the contained kvs into the
-// buffer.
+// buffer. initialScan is set if the sst is part of the initial scan.
 func (p *poller) slurpSST(
-	ctx context.Context, sst []byte, schemaTimestamp hlc.Timestamp, withDiff bool,
+	ctx context.Context, sst []byte, schemaTimestamp hlc.Timestamp, withDiff, initialScan bool,
 ) error {
 	var previousKey roachpb.Key
 	var kvs []roachpb.KeyValue
@@ -546,7 +614,7 @@ func (p *poller) slurpSST(
 				// change. This is handled in kvsToRows.
 				prevVal = kv.Value
 			}
-			if err := p.buf.AddKV(ctx, kv, prevVal, schemaTimestamp); err != nil {
+			if err := p.buf.AddKV(ctx, kv, prevVal, schemaTimestamp, initialScan); err != nil {
 				return err
 			}
 		}
@@ -638,7 +706,7 @@ func (p *poller) validateTable(ctx context.Context, desc *sqlbase.TableDescripto
 		if desc.ModificationTime.Less(lastVersion.ModificationTime) {
 			return nil
 		}
-		if shouldAddScanBoundary(lastVersion, desc) {
+		if shouldAddScanBoundary(p.details, lastVersion, desc) {
 			boundaryTime := desc.GetModificationTime()
 			// Only mutations that happened after the changefeed started are
 			// interesting here.
@@ -673,11 +741,22 @@ func (p *poller) validateTable(ctx context.Context, desc *sqlbase.TableDescripto
 	return nil
 }
 
+// shouldAddScanBoundary returns whether the change from lastVersion to desc is
+// a schema change event of the class selected by the changefeed's options. The
+// schema change policy decides what the changefeed does at the boundary.
 func shouldAddScanBoundary(
-	lastVersion *sqlbase.TableDescriptor, desc *sqlbase.TableDescriptor,
+	details jobspb.ChangefeedDetails,
+	lastVersion *sqlbase.TableDescriptor,
+	desc *sqlbase.TableDescriptor,
 ) (res bool) {
-	return newColumnBackfillComplete(lastVersion, desc) ||
-		hasNewColumnDropBackfillMutation(lastVersion, desc)
+	switch schemaChangeEventClass(details.Opts[optSchemaChangeEvents]) {
+	case optSchemaChangeEventClassColumnChange:
+		return newColumnComplete(lastVersion, desc) ||
+			hasNewColumnDropBackfillMutation(lastVersion, desc)
+	default:
+		return newColumnBackfillComplete(lastVersion, desc) ||
+			hasNewColumnDropBackfillMutation(lastVersion, desc)
+	}
 }
 
 func hasNewColumnDropBackfillMutation(oldDesc, newDesc *sqlbase.TableDescriptor) (res bool) {
@@ -700,6 +779,12 @@ func newColumnBackfillComplete(oldDesc, newDesc *sqlbase.TableDescriptor) (res b
 		oldDesc.HasColumnBackfillMutation() && !newDesc.HasColumnBackfillMutation()
 }
 
+// newColumnComplete returns whether a column was added to the table, whether or
+// not it needed a backfill.
+func newColumnComplete(oldDesc, newDesc *sqlbase.TableDescriptor) (res bool) {
+	return len(oldDesc.Columns) < len(newDesc.Columns)
+}
+
 func fetchSpansForTargets(
 	ctx context.Context, db *client.DB, targets jobspb.ChangefeedTargets, ts hlc.Timestamp,
 ) ([]roachpb.Span, error) {
@@ -0,0 +1,484 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+// cdcPrevColumnName is the name of the pseudo-column through which the query
+// of a changefeed accesses the previous value of a changed row. Its value is a
+// JSONB object mapping the column names of the row to their previous values,
+// or NULL if the row didn't exist before the change.
+const cdcPrevColumnName = `cdc_prev`
+
+// changefeedQuerySemaRejectFlags restricts the functions allowed in the query
+// of a changefeed to the ones that can be evaluated on each row independently.
+// Functions that are not supported by DistSQL are also rejected, see
+// checkChangefeedQueryFuncs.
+const changefeedQuerySemaRejectFlags = tree.RejectAggregates | tree.RejectWindowApplications |
+	tree.RejectGenerators | tree.RejectImpureFunctions | tree.RejectSubqueries
+
+// changefeedQuery is the query of a changefeed created with `CREATE CHANGEFEED
+// ... AS SELECT`. Only changed rows that satisfy its WHERE clause are emitted,
+// and their values are made of the expressions of its SELECT clause. The key
+// of an emitted row is still the primary key of the table.
+//
+// A deletion has no columns to evaluate the query on, so it is emitted if the
+// previous value of the row satisfies the WHERE clause.
+type changefeedQuery struct {
+	clause *tree.SelectClause
+	// table is the table of the FROM clause, and source is the name through
+	// which its columns are referenced in the query.
+	table  *tree.TableName
+	source tree.TableName
+	// needsPrev is set if the query references cdc_prev or has a WHERE clause,
+	// in which case the previous values of the changed rows are needed.
+	needsPrev bool
+}
+
+// parseChangefeedQuery parses the query of a changefeed, as stored in the
+// Select field of its ChangefeedDetails.
+func parseChangefeedQuery(sql string) (*changefeedQuery, error) {
+	stmt, err := parser.ParseOne(sql)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return nil, errors.Errorf(`changefeed query must be a SELECT: %s`, sql)
+	}
+	return newChangefeedQuery(sel)
+}
+
+// newChangefeedQuery checks that the given query is supported by changefeeds,
+// that is that it only selects expressions from a single table, optionally
+// with a WHERE clause.
+func newChangefeedQuery(sel *tree.Select) (*changefeedQuery, error) {
+	if sel.With != nil || sel.OrderBy != nil || sel.Limit != nil ||
+		sel.ForLocked.Strength != tree.ForNone {
+		return nil, errors.Errorf(
+			`changefeed queries do not support WITH, ORDER BY, LIMIT or locking clauses`)
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok || clause.TableSelect {
+		return nil, errors.Errorf(`changefeed query must be a simple SELECT: %s`, tree.AsString(sel))
+	}
+	if clause.Distinct || clause.GroupBy != nil || clause.Having != nil || clause.Window != nil {
+		return nil, errors.Errorf(
+			`changefeed queries do not support DISTINCT, GROUP BY, HAVING or WINDOW clauses`)
+	}
+	if len(clause.From.Tables) != 1 || clause.From.AsOf.Expr != nil {
+		return nil, errors.Errorf(`changefeed query must select from exactly one table`)
+	}
+	tableExpr, ok := clause.From.Tables[0].(*tree.AliasedTableExpr)
+	if !ok || tableExpr.IndexFlags != nil || tableExpr.Ordinality || tableExpr.Lateral ||
+		len(tableExpr.As.Cols) > 0 {
+		return nil, errors.Errorf(
+			`changefeed query cannot select from %s`, tree.AsString(clause.From.Tables[0]))
+	}
+	table, ok := tableExpr.Expr.(*tree.TableName)
+	if !ok {
+		return nil, errors.Errorf(`changefeed query cannot select from %s`, tree.AsString(tableExpr))
+	}
+
+	q := &changefeedQuery{clause: clause, table: table, source: *table}
+	if tableExpr.As.Alias != `` {
+		q.source = tree.MakeUnqualifiedTableName(tableExpr.As.Alias)
+	}
+	findPrev := func(expr tree.Expr) (bool, tree.Expr, error) {
+		if n, ok := expr.(*tree.UnresolvedName); ok && !n.Star && n.NumParts == 1 &&
+			n.Parts[0] == cdcPrevColumnName {
+			q.needsPrev = true
+		}
+		return true, expr, nil
+	}
+	for _, e := range clause.Exprs {
+		if _, err := tree.SimpleVisit(e.Expr, findPrev); err != nil {
+			return nil, err
+		}
+	}
+	if clause.Where != nil {
+		// The WHERE clause is evaluated on the previous value of deleted rows.
+		q.needsPrev = true
+	}
+	return q, nil
+}
+
+// needsPrevRows returns whether the previous values of the changed rows are
+// needed, either to be emitted (optDiff, optEnvelopeDebezium) or for the query
+// of the changefeed, which is nil if the changefeed isn't defined by a query.
+func needsPrevRows(details jobspb.ChangefeedDetails, query *changefeedQuery) bool {
+	if _, ok := details.Opts[optDiff]; ok {
+		return true
+	}
+	if envelopeType(details.Opts[optEnvelope]) == optEnvelopeDebezium {
+		return true
+	}
+	return query != nil && query.needsPrev
+}
+
+// changefeedQueryPlan is the query of a changefeed, resolved against a version
+// of the descriptor of its table.
+type changefeedQueryPlan struct {
+	ivars  *changefeedQueryIVarContainer
+	filter tree.TypedExpr
+	exprs  []tree.TypedExpr
+	// tableDesc describes the emitted rows. Its columns are the expressions of
+	// the SELECT clause followed by the primary key columns of the table,
+	// which are only used to encode the key (see encodeRow.numValueCols).
+	tableDesc *sqlbase.TableDescriptor
+	// pkColIdxs are the indexes of the primary key columns in the rows of the
+	// table.
+	pkColIdxs []int
+}
+
+// changefeedQueryIVarContainer is the tree.IndexedVarContainer through which
+// the expressions of a changefeed query access the columns of a changed row.
+// The last variable is cdc_prev.
+type changefeedQueryIVarContainer struct {
+	cols []sqlbase.ColumnDescriptor
+	row  tree.Datums
+}
+
+var _ tree.IndexedVarContainer = &changefeedQueryIVarContainer{}
+
+// IndexedVarEval implements the tree.IndexedVarContainer interface.
+func (c *changefeedQueryIVarContainer) IndexedVarEval(
+	idx int, _ *tree.EvalContext,
+) (tree.Datum, error) {
+	return c.row[idx], nil
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (c *changefeedQueryIVarContainer) IndexedVarResolvedType(idx int) *types.T {
+	if idx == len(c.cols) {
+		return types.Jsonb
+	}
+	return &c.cols[idx].Type
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (c *changefeedQueryIVarContainer) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name(cdcPrevColumnName)
+	if idx < len(c.cols) {
+		n = tree.Name(c.cols[idx].Name)
+	}
+	return &n
+}
+
+// plan resolves the query against the given descriptor of its table.
+func (q *changefeedQuery) plan(
+	evalCtx *tree.EvalContext, tableDesc *sqlbase.TableDescriptor,
+) (*changefeedQueryPlan, error) {
+	p := &changefeedQueryPlan{
+		ivars: &changefeedQueryIVarContainer{
+			cols: tableDesc.Columns,
+			row:  make(tree.Datums, len(tableDesc.Columns)+1),
+		},
+	}
+	sourceCols := append(
+		sqlbase.ResultColumnsFromColDescs(tableDesc.Columns),
+		sqlbase.ResultColumn{Name: cdcPrevColumnName, Typ: types.Jsonb},
+	)
+	sources := sqlbase.MakeMultiSourceInfo(sqlbase.NewSourceInfoForSingleTable(q.source, sourceCols))
+	ivarHelper := tree.MakeIndexedVarHelper(p.ivars, len(sourceCols))
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.IVarContainer = p.ivars
+	searchPath := evalCtx.SessionData.SearchPath
+
+	typeCheck := func(expr tree.Expr, required *types.T, context string) (tree.TypedExpr, error) {
+		expr, _, _, err := sqlbase.ResolveNames(expr, sources, ivarHelper, searchPath)
+		if err != nil {
+			return nil, err
+		}
+		semaCtx.Properties.Require(context, changefeedQuerySemaRejectFlags)
+		typedExpr, err := tree.TypeCheckAndRequire(expr, &semaCtx, required, context)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkChangefeedQueryFuncs(typedExpr); err != nil {
+			return nil, err
+		}
+		return typedExpr, nil
+	}
+
+	if q.clause.Where != nil {
+		var err error
+		if p.filter, err = typeCheck(q.clause.Where.Expr, types.Bool, `WHERE`); err != nil {
+			return nil, err
+		}
+	}
+
+	// The columns of the emitted rows must not conflict with the primary key
+	// columns, which keep their IDs.
+	valueColID := tableDesc.NextColumnID
+	var valueCols []sqlbase.ColumnDescriptor
+	addValueCol := func(name string, typ *types.T) {
+		valueCols = append(valueCols, sqlbase.ColumnDescriptor{
+			Name: name, ID: valueColID, Type: *typ, Nullable: true,
+		})
+		valueColID++
+	}
+	for _, target := range q.clause.Exprs {
+		if isStar(target.Expr) {
+			for i := range tableDesc.Columns {
+				if col := &tableDesc.Columns[i]; !col.Hidden {
+					p.exprs = append(p.exprs, ivarHelper.IndexedVar(i))
+					addValueCol(col.Name, &col.Type)
+				}
+			}
+			continue
+		}
+		name, err := tree.GetRenderColName(searchPath, target)
+		if err != nil {
+			return nil, err
+		}
+		typedExpr, err := typeCheck(target.Expr, types.Any, `SELECT`)
+		if err != nil {
+			return nil, err
+		}
+		p.exprs = append(p.exprs, typedExpr)
+		addValueCol(name, typedExpr.ResolvedType())
+	}
+	names := make(map[string]struct{}, len(valueCols))
+	for i := range valueCols {
+		if _, ok := names[valueCols[i].Name]; ok {
+			return nil, errors.WithHint(
+				errors.Errorf(`changefeed query has duplicate column name %q`, valueCols[i].Name),
+				`use AS to give the expressions of the SELECT clause distinct names`,
+			)
+		}
+		names[valueCols[i].Name] = struct{}{}
+	}
+
+	p.tableDesc = new(sqlbase.TableDescriptor)
+	*p.tableDesc = *tableDesc
+	p.tableDesc.Columns = valueCols
+	colIdxByID := tableDesc.ColumnIdxMap()
+	for _, colID := range tableDesc.PrimaryIndex.ColumnIDs {
+		colIdx, ok := colIdxByID[colID]
+		if !ok {
+			return nil, errors.AssertionFailedf(`unknown column id: %d`, colID)
+		}
+		p.tableDesc.Columns = append(p.tableDesc.Columns, tableDesc.Columns[colIdx])
+		p.pkColIdxs = append(p.pkColIdxs, colIdx)
+	}
+	return p, nil
+}
+
+// isStar returns whether the expression of a SELECT clause selects all the
+// columns of the table.
+func isStar(expr tree.Expr) bool {
+	switch t := expr.(type) {
+	case tree.UnqualifiedStar:
+		return true
+	case *tree.UnresolvedName:
+		return t.Star
+	}
+	return false
+}
+
+// checkChangefeedQueryFuncs returns an error if the expression uses functions
+// that can't be evaluated by the changefeed processors, which don't run in a
+// transaction.
+func checkChangefeedQueryFuncs(expr tree.TypedExpr) error {
+	_, err := tree.SimpleVisit(expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+		if f, ok := expr.(*tree.FuncExpr); ok && f.IsDistSQLBlacklist() {
+			return false, expr, errors.Errorf(
+				`function %s is not supported in changefeed queries`, &f.Func)
+		}
+		return true, expr, nil
+	})
+	return err
+}
+
+// evalRow evaluates the query on the given row and returns the row to emit,
+// or false if the row doesn't satisfy the query.
+func (p *changefeedQueryPlan) evalRow(
+	evalCtx *tree.EvalContext, alloc *sqlbase.DatumAlloc, row encodeRow,
+) (encodeRow, bool, error) {
+	cols := p.ivars.cols
+	for i := range cols {
+		if err := row.datums[i].EnsureDecoded(&cols[i].Type, alloc); err != nil {
+			return encodeRow{}, false, err
+		}
+		p.ivars.row[i] = row.datums[i].Datum
+	}
+
+	out := encodeRow{
+		datums:       make(sqlbase.EncDatumRow, len(p.tableDesc.Columns)),
+		updated:      row.updated,
+		deleted:      row.deleted,
+		tableDesc:    p.tableDesc,
+		numValueCols: len(p.exprs),
+	}
+	for i, colIdx := range p.pkColIdxs {
+		out.datums[len(p.exprs)+i] = row.datums[colIdx]
+	}
+	prev, err := prevRowJSON(alloc, row)
+	if err != nil {
+		return encodeRow{}, false, err
+	}
+	p.ivars.row[len(cols)] = prev
+
+	if row.deleted {
+		for i := range p.exprs {
+			out.datums[i] = sqlbase.DatumToEncDatum(&p.tableDesc.Columns[i].Type, tree.DNull)
+		}
+		if p.filter == nil || row.prevDatums == nil {
+			return out, true, nil
+		}
+		if row.prevDeleted {
+			// The row didn't exist, so there is nothing to delete.
+			return encodeRow{}, false, nil
+		}
+		// The deletion is emitted if the previous value of the row satisfies the
+		// WHERE clause.
+		if err := p.setPrevRow(alloc, row); err != nil {
+			return encodeRow{}, false, err
+		}
+		ok, err := p.evalFilter(evalCtx)
+		if err != nil || !ok {
+			return encodeRow{}, false, err
+		}
+		return out, true, nil
+	}
+
+	if ok, err := p.evalFilter(evalCtx); err != nil || !ok {
+		return encodeRow{}, false, err
+	}
+	for i, expr := range p.exprs {
+		d, err := expr.Eval(evalCtx)
+		if err != nil {
+			return encodeRow{}, false, err
+		}
+		out.datums[i] = sqlbase.DatumToEncDatum(&p.tableDesc.Columns[i].Type, d)
+	}
+	return out, true, nil
+}
+
+// evalFilter returns whether the row set in p.ivars satisfies the WHERE clause
+// of the query, if any.
+func (p *changefeedQueryPlan) evalFilter(evalCtx *tree.EvalContext) (bool, error) {
+	if p.filter == nil {
+		return true, nil
+	}
+	d, err := p.filter.Eval(evalCtx)
+	if err != nil {
+		return false, err
+	}
+	return d == tree.DBoolTrue, nil
+}
+
+// setPrevRow sets the columns of p.ivars to the previous value of the given
+// row. The columns that didn't exist in the previous version of the table are
+// set to NULL.
+func (p *changefeedQueryPlan) setPrevRow(alloc *sqlbase.DatumAlloc, row encodeRow) error {
+	prevCols := row.prevTableDesc.Columns
+	prevColIdxs := row.prevTableDesc.ColumnIdxMap()
+	for i := range p.ivars.cols {
+		prevColIdx, ok := prevColIdxs[p.ivars.cols[i].ID]
+		if !ok {
+			p.ivars.row[i] = tree.DNull
+			continue
+		}
+		datum := row.prevDatums[prevColIdx]
+		if err := datum.EnsureDecoded(&prevCols[prevColIdx].Type, alloc); err != nil {
+			return err
+		}
+		p.ivars.row[i] = datum.Datum
+	}
+	return nil
+}
+
+// prevRowJSON returns the value of cdc_prev for the given row.
+func prevRowJSON(alloc *sqlbase.DatumAlloc, row encodeRow) (tree.Datum, error) {
+	if row.prevDatums == nil || row.prevDeleted {
+		return tree.DNull, nil
+	}
+	cols := row.prevTableDesc.Columns
+	entries := make(map[string]interface{}, len(cols))
+	for i := range cols {
+		datum := row.prevDatums[i]
+		if err := datum.EnsureDecoded(&cols[i].Type, alloc); err != nil {
+			return nil, err
+		}
+		var err error
+		if entries[cols[i].Name], err = tree.AsJSON(datum.Datum); err != nil {
+			return nil, err
+		}
+	}
+	j, err := json.MakeJSON(entries)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDJSON(j), nil
+}
+
+// evalRows returns a closure that evaluates the query on the rows returned by
+// inputFn. The returned closure is not threadsafe.
+func (q *changefeedQuery) evalRows(
+	evalCtx *tree.EvalContext, inputFn func(context.Context) ([]emitEntry, error),
+) func(context.Context) ([]emitEntry, error) {
+	plans := make(map[tableIDAndVersion]*changefeedQueryPlan)
+	var alloc sqlbase.DatumAlloc
+	var output []emitEntry
+	return func(ctx context.Context) ([]emitEntry, error) {
+		for {
+			inputs, err := inputFn(ctx)
+			if err != nil {
+				return nil, err
+			}
+			// Reuse output to save allocations.
+			output = output[:0]
+			for _, input := range inputs {
+				if input.row.datums == nil {
+					output = append(output, input)
+					continue
+				}
+				cacheKey := makeTableIDAndVersion(
+					input.row.tableDesc.ID, input.row.tableDesc.Version, input.row.familyID,
+				)
+				p, ok := plans[cacheKey]
+				if !ok {
+					if p, err = q.plan(evalCtx, input.row.tableDesc); err != nil {
+						return nil, err
+					}
+					plans[cacheKey] = p
+				}
+				row, ok, err := p.evalRow(evalCtx, &alloc, input.row)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					// The row is filtered out, but its resolved timestamp, if
+					// any, still has to be emitted.
+					if input.resolved == nil {
+						continue
+					}
+					row = encodeRow{}
+				}
+				input.row = row
+				output = append(output, input)
+			}
+			if len(output) > 0 {
+				return output, nil
+			}
+		}
+	}
+}
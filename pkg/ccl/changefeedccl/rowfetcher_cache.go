@@ -26,16 +26,36 @@ import (
 // StartScanFrom can be used to turn that key (or all the keys making up the
 // column families of one row) into a row.
 type rowFetcherCache struct {
-	leaseMgr *sql.LeaseManager
-	fetchers map[*sqlbase.ImmutableTableDescriptor]*row.Fetcher
+	leaseMgr       *sql.LeaseManager
+	fetchers       map[*sqlbase.ImmutableTableDescriptor]*row.Fetcher
+	familyFetchers map[tableDescAndFamily]*row.Fetcher
+	families       map[tableDescAndFamily]*familyDesc
 
 	a sqlbase.DatumAlloc
 }
 
+type tableDescAndFamily struct {
+	tableDesc *sqlbase.ImmutableTableDescriptor
+	familyID  sqlbase.FamilyID
+}
+
+// familyDesc describes a column family of a table whose changes are emitted
+// separately from the other families of the table (see optSplitColumnFamilies).
+type familyDesc struct {
+	// tableDesc is a copy of the descriptor of the table named
+	// `<table>.<family>`, which only has the primary key columns and the
+	// columns of the family.
+	tableDesc *sqlbase.TableDescriptor
+	// colIdxs maps the columns of tableDesc to the columns of the table.
+	colIdxs []int
+}
+
 func newRowFetcherCache(leaseMgr *sql.LeaseManager) *rowFetcherCache {
 	return &rowFetcherCache{
-		leaseMgr: leaseMgr,
-		fetchers: make(map[*sqlbase.ImmutableTableDescriptor]*row.Fetcher),
+		leaseMgr:       leaseMgr,
+		fetchers:       make(map[*sqlbase.ImmutableTableDescriptor]*row.Fetcher),
+		familyFetchers: make(map[tableDescAndFamily]*row.Fetcher),
+		families:       make(map[tableDescAndFamily]*familyDesc),
 	}
 }
 
@@ -88,12 +108,91 @@ func (c *rowFetcherCache) RowFetcherForTableDesc(
 		return rf, nil
 	}
 
-	// TODO(dan): Allow for decoding a subset of the columns.
-	colIdxMap := make(map[sqlbase.ColumnID]int)
 	var valNeededForCol util.FastIntSet
+	valNeededForCol.AddRange(0, len(tableDesc.Columns)-1)
+	rf, err := c.makeRowFetcher(tableDesc, valNeededForCol)
+	if err != nil {
+		return nil, err
+	}
+	// TODO(dan): Bound the size of the cache. Resolved notifications will let
+	// us evict anything for timestamps entirely before the notification. Then
+	// probably an LRU just in case?
+	c.fetchers[tableDesc] = rf
+	return rf, nil
+}
+
+// RowFetcherForColumnFamily returns a Fetcher that only decodes the primary
+// key columns and the columns of the given family. Unlike the Fetcher returned
+// by RowFetcherForTableDesc, it can be fed the KV of a single family of a row
+// without the other families.
+func (c *rowFetcherCache) RowFetcherForColumnFamily(
+	tableDesc *sqlbase.ImmutableTableDescriptor, familyID sqlbase.FamilyID,
+) (*row.Fetcher, error) {
+	key := tableDescAndFamily{tableDesc: tableDesc, familyID: familyID}
+	if rf, ok := c.familyFetchers[key]; ok {
+		return rf, nil
+	}
+
+	family, err := c.FamilyDesc(tableDesc, familyID)
+	if err != nil {
+		return nil, err
+	}
+	var valNeededForCol util.FastIntSet
+	for _, colIdx := range family.colIdxs {
+		valNeededForCol.Add(colIdx)
+	}
+	rf, err := c.makeRowFetcher(tableDesc, valNeededForCol)
+	if err != nil {
+		return nil, err
+	}
+	// TODO(dan): Bound the size of the cache, see RowFetcherForTableDesc.
+	c.familyFetchers[key] = rf
+	return rf, nil
+}
+
+// FamilyDesc returns the familyDesc of the given column family of the table.
+func (c *rowFetcherCache) FamilyDesc(
+	tableDesc *sqlbase.ImmutableTableDescriptor, familyID sqlbase.FamilyID,
+) (*familyDesc, error) {
+	key := tableDescAndFamily{tableDesc: tableDesc, familyID: familyID}
+	if f, ok := c.families[key]; ok {
+		return f, nil
+	}
+
+	family, err := tableDesc.FindFamilyByID(familyID)
+	if err != nil {
+		return nil, err
+	}
+	var neededColIDs util.FastIntSet
+	for _, colID := range tableDesc.PrimaryIndex.ColumnIDs {
+		neededColIDs.Add(int(colID))
+	}
+	for _, colID := range family.ColumnIDs {
+		neededColIDs.Add(int(colID))
+	}
+
+	f := &familyDesc{tableDesc: new(sqlbase.TableDescriptor)}
+	*f.tableDesc = tableDesc.TableDescriptor
+	f.tableDesc.Name = tableDesc.Name + `.` + family.Name
+	f.tableDesc.Columns = nil
+	for colIdx := range tableDesc.Columns {
+		if neededColIDs.Contains(int(tableDesc.Columns[colIdx].ID)) {
+			f.tableDesc.Columns = append(f.tableDesc.Columns, tableDesc.Columns[colIdx])
+			f.colIdxs = append(f.colIdxs, colIdx)
+		}
+	}
+	f.tableDesc.Families = []sqlbase.ColumnFamilyDescriptor{*family}
+	// TODO(dan): Bound the size of the cache, see RowFetcherForTableDesc.
+	c.families[key] = f
+	return f, nil
+}
+
+func (c *rowFetcherCache) makeRowFetcher(
+	tableDesc *sqlbase.ImmutableTableDescriptor, valNeededForCol util.FastIntSet,
+) (*row.Fetcher, error) {
+	colIdxMap := make(map[sqlbase.ColumnID]int)
 	for colIdx := range tableDesc.Columns {
 		colIdxMap[tableDesc.Columns[colIdx].ID] = colIdx
-		valNeededForCol.Add(colIdx)
 	}
 
 	var rf row.Fetcher
@@ -111,9 +210,5 @@ func (c *rowFetcherCache) RowFetcherForTableDesc(
 	); err != nil {
 		return nil, err
 	}
-	// TODO(dan): Bound the size of the cache. Resolved notifications will let
-	// us evict anything for timestamps entirely before the notification. Then
-	// probably an LRU just in case?
-	c.fetchers[tableDesc] = &rf
 	return &rf, nil
 }
@@ -80,6 +80,7 @@ func getSink(
 		return nil, err
 	}
 	q := u.Query()
+	_, splitColumnFamilies := opts[optSplitColumnFamilies]
 
 	// Use a function here to delay creation of the sink until after we've done
 	// all the parameter verification.
@@ -89,6 +90,7 @@ func getSink(
 		makeSink = func() (Sink, error) { return &bufferSink{}, nil }
 	case u.Scheme == sinkSchemeKafka:
 		var cfg kafkaSinkConfig
+		cfg.splitColumnFamilies = splitColumnFamilies
 		cfg.kafkaTopicPrefix = q.Get(sinkParamTopicPrefix)
 		q.Del(sinkParamTopicPrefix)
 		if schemaTopic := q.Get(sinkParamSchemaTopic); schemaTopic != `` {
@@ -200,7 +202,12 @@ func getSink(
 		// something.
 		tableName := `sqlsink`
 		makeSink = func() (Sink, error) {
-			return makeSQLSink(u.String(), tableName, targets)
+			s, err := makeSQLSink(u.String(), tableName, targets)
+			if err != nil {
+				return nil, err
+			}
+			s.splitColumnFamilies = splitColumnFamilies
+			return s, nil
 		}
 		// Remove parameters we know about for the unknown parameter check.
 		q.Del(`sslcert`)
@@ -222,6 +229,18 @@ func getSink(
 	return s, nil
 }
 
+// isColumnFamilyTopic returns whether topic is the topic of a column family of
+// one of the tables with the given topics. When the column families of a table
+// are emitted separately, the topic of a family is `<table topic>.<family>`.
+func isColumnFamilyTopic(tableTopics map[string]struct{}, topic string) bool {
+	for tableTopic := range tableTopics {
+		if strings.HasPrefix(topic, tableTopic+`.`) {
+			return true
+		}
+	}
+	return false
+}
+
 // errorWrapperSink delegates to another sink and marks all returned errors as
 // retryable. During changefeed setup, we use the sink once without this to
 // verify configuration, but in the steady state, no sink error should be
@@ -296,6 +315,9 @@ type kafkaSinkConfig struct {
 	saslHandshake    bool
 	saslUser         string
 	saslPassword     string
+	// splitColumnFamilies is set if the column families of the tables are
+	// emitted separately, in which case each family has its own topic.
+	splitColumnFamilies bool
 }
 
 // kafkaSink emits to Kafka asynchronously. It is not concurrency-safe; all
@@ -455,7 +477,12 @@ func (s *kafkaSink) EmitRow(
 ) error {
 	topic := s.cfg.kafkaTopicPrefix + SQLNameToKafkaName(table.Name)
 	if _, ok := s.topics[topic]; !ok {
-		return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+		if !s.cfg.splitColumnFamilies || !isColumnFamilyTopic(s.topics, topic) {
+			return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+		}
+		// Resolved timestamps are emitted to the topics of the column families
+		// once they are known.
+		s.topics[topic] = struct{}{}
 	}
 
 	msg := &sarama.ProducerMessage{
@@ -651,6 +678,9 @@ type sqlSink struct {
 	tableName string
 	topics    map[string]struct{}
 	hasher    hash.Hash32
+	// splitColumnFamilies is set if the column families of the tables are
+	// emitted separately, in which case each family has its own topic.
+	splitColumnFamilies bool
 
 	rowBuf  []interface{}
 	scratch bufalloc.ByteAllocator
@@ -689,7 +719,10 @@ func (s *sqlSink) EmitRow(
 ) error {
 	topic := table.Name
 	if _, ok := s.topics[topic]; !ok {
-		return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+		if !s.splitColumnFamilies || !isColumnFamilyTopic(s.topics, topic) {
+			return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+		}
+		s.topics[topic] = struct{}{}
 	}
 
 	// Hashing logic copied from sarama.HashPartitioner.
@@ -102,12 +102,29 @@ func canUseFollowerRead(clusterID uuid.UUID, st *cluster.Settings, ts hlc.Timest
 	return checkEnterpriseEnabled(clusterID, st) == nil
 }
 
+// canUseGlobalReadsFollowerRead determines if a query against a range
+// configured for global reads can be sent to a follower. Followers of such
+// ranges serve reads at present time (see closedts.GlobalReadsLead), so the
+// timestamp of the query doesn't matter.
+func canUseGlobalReadsFollowerRead(clusterID uuid.UUID, st *cluster.Settings) bool {
+	if !storage.FollowerReadsEnabled.Get(&st.SV) {
+		return false
+	}
+	return checkEnterpriseEnabled(clusterID, st) == nil
+}
+
 // canSendToFollower implements the logic for checking whether a batch request
 // may be sent to a follower.
-func canSendToFollower(clusterID uuid.UUID, st *cluster.Settings, ba roachpb.BatchRequest) bool {
-	return batchCanBeEvaluatedOnFollower(ba) &&
-		txnCanPerformFollowerRead(ba.Txn) &&
-		canUseFollowerRead(clusterID, st, forward(ba.Txn.ReadTimestamp, ba.Txn.MaxTimestamp))
+func canSendToFollower(
+	clusterID uuid.UUID, st *cluster.Settings, ba roachpb.BatchRequest, globalReads bool,
+) bool {
+	if !batchCanBeEvaluatedOnFollower(ba) || !txnCanPerformFollowerRead(ba.Txn) {
+		return false
+	}
+	if globalReads {
+		return canUseGlobalReadsFollowerRead(clusterID, st)
+	}
+	return canUseFollowerRead(clusterID, st, forward(ba.Txn.ReadTimestamp, ba.Txn.MaxTimestamp))
 }
 
 func forward(ts hlc.Timestamp, to hlc.Timestamp) hlc.Timestamp {
@@ -14,15 +14,20 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan/replicaoracle"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -30,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/pkg/errors"
 )
 
 const expectedFollowerReadOffset = -1 * (30 * (1 + .2*3)) * time.Second
@@ -67,22 +73,22 @@ func TestCanSendToFollower(t *testing.T) {
 	}}
 	rw := roachpb.BatchRequest{Header: oldHeader}
 	rw.Add(&roachpb.PutRequest{})
-	if canSendToFollower(uuid.MakeV4(), st, rw) {
+	if canSendToFollower(uuid.MakeV4(), st, rw, false /* globalReads */) {
 		t.Fatalf("should not be able to send a rw request to a follower")
 	}
 	roNonTxn := roachpb.BatchRequest{Header: oldHeader}
 	roNonTxn.Add(&roachpb.QueryTxnRequest{})
-	if canSendToFollower(uuid.MakeV4(), st, roNonTxn) {
+	if canSendToFollower(uuid.MakeV4(), st, roNonTxn, false /* globalReads */) {
 		t.Fatalf("should not be able to send a non-transactional ro request to a follower")
 	}
 	roNoTxn := roachpb.BatchRequest{}
 	roNoTxn.Add(&roachpb.GetRequest{})
-	if canSendToFollower(uuid.MakeV4(), st, roNoTxn) {
+	if canSendToFollower(uuid.MakeV4(), st, roNoTxn, false /* globalReads */) {
 		t.Fatalf("should not be able to send a batch with no txn to a follower")
 	}
 	roOld := roachpb.BatchRequest{Header: oldHeader}
 	roOld.Add(&roachpb.GetRequest{})
-	if !canSendToFollower(uuid.MakeV4(), st, roOld) {
+	if !canSendToFollower(uuid.MakeV4(), st, roOld, false /* globalReads */) {
 		t.Fatalf("should be able to send an old ro batch to a follower")
 	}
 	roRWTxnOld := roachpb.BatchRequest{Header: roachpb.Header{
@@ -92,11 +98,11 @@ func TestCanSendToFollower(t *testing.T) {
 		},
 	}}
 	roRWTxnOld.Add(&roachpb.GetRequest{})
-	if canSendToFollower(uuid.MakeV4(), st, roRWTxnOld) {
+	if canSendToFollower(uuid.MakeV4(), st, roRWTxnOld, false /* globalReads */) {
 		t.Fatalf("should not be able to send a ro request from a rw txn to a follower")
 	}
 	storage.FollowerReadsEnabled.Override(&st.SV, false)
-	if canSendToFollower(uuid.MakeV4(), st, roOld) {
+	if canSendToFollower(uuid.MakeV4(), st, roOld, false /* globalReads */) {
 		t.Fatalf("should not be able to send an old ro batch to a follower when follower reads are disabled")
 	}
 	storage.FollowerReadsEnabled.Override(&st.SV, true)
@@ -105,7 +111,7 @@ func TestCanSendToFollower(t *testing.T) {
 			ReadTimestamp: hlc.Timestamp{WallTime: timeutil.Now().UnixNano()},
 		},
 	}}
-	if canSendToFollower(uuid.MakeV4(), st, roNew) {
+	if canSendToFollower(uuid.MakeV4(), st, roNew, false /* globalReads */) {
 		t.Fatalf("should not be able to send a new ro batch to a follower")
 	}
 	roOldWithNewMax := roachpb.BatchRequest{Header: roachpb.Header{
@@ -114,13 +120,99 @@ func TestCanSendToFollower(t *testing.T) {
 		},
 	}}
 	roOldWithNewMax.Add(&roachpb.GetRequest{})
-	if canSendToFollower(uuid.MakeV4(), st, roNew) {
+	if canSendToFollower(uuid.MakeV4(), st, roNew, false /* globalReads */) {
 		t.Fatalf("should not be able to send a ro batch with new MaxTimestamp to a follower")
 	}
+	roNew.Add(&roachpb.GetRequest{})
+	if !canSendToFollower(uuid.MakeV4(), st, roNew, true /* globalReads */) {
+		t.Fatalf("should be able to send a new ro batch to a follower of a global reads range")
+	}
+	if canSendToFollower(uuid.MakeV4(), st, rw, true /* globalReads */) {
+		t.Fatalf("should not be able to send a rw request to a follower of a global reads range")
+	}
+	if canSendToFollower(uuid.MakeV4(), st, roRWTxnOld, true /* globalReads */) {
+		t.Fatalf("should not be able to send a ro request from a rw txn to a follower of a global reads range")
+	}
+	storage.FollowerReadsEnabled.Override(&st.SV, false)
+	if canSendToFollower(uuid.MakeV4(), st, roNew, true /* globalReads */) {
+		t.Fatalf("should not be able to send a new ro batch to a follower of a global reads range when follower reads are disabled")
+	}
+	storage.FollowerReadsEnabled.Override(&st.SV, true)
 	disableEnterprise()
-	if canSendToFollower(uuid.MakeV4(), st, roOld) {
+	if canSendToFollower(uuid.MakeV4(), st, roOld, false /* globalReads */) {
 		t.Fatalf("should not be able to send an old ro batch to a follower without enterprise enabled")
 	}
+	if canSendToFollower(uuid.MakeV4(), st, roNew, true /* globalReads */) {
+		t.Fatalf("should not be able to send a new ro batch to a follower of a global reads range without enterprise enabled")
+	}
+}
+
+// TestFollowerReadsGlobalReads verifies that the DistSender of a node that
+// doesn't hold the lease for a range configured for global reads sends reads
+// at present time to its local replica once a response has told it about the
+// configuration.
+func TestFollowerReadsGlobalReads(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer utilccl.TestingEnableEnterprise()()
+
+	ctx := context.Background()
+	const numNodes = 3
+	tc := testcluster.StartTestCluster(t, numNodes, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+
+	db0 := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+	// Speed up the closed timestamp updates that the range needs to be
+	// published as configured for global reads.
+	db0.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.target_duration = '300ms'`)
+	db0.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.close_fraction = 0.333`)
+	db0.Exec(t, `CREATE DATABASE t`)
+	db0.Exec(t, `CREATE TABLE t.kv (k INT PRIMARY KEY, v STRING)`)
+	db0.Exec(t, `ALTER TABLE t.kv CONFIGURE ZONE USING num_replicas = 3, global_reads = true`)
+	db0.Exec(t, `INSERT INTO t.kv VALUES (1, 'foo')`)
+
+	tableDesc := sqlbase.GetTableDescriptor(tc.Server(0).DB(), "t", "kv")
+	startKey := roachpb.Key(keys.MakeTablePrefix(uint32(tableDesc.ID)))
+	var desc roachpb.RangeDescriptor
+	testutils.SucceedsSoon(t, func() error {
+		var err error
+		if desc, err = tc.LookupRange(startKey); err != nil {
+			return err
+		}
+		if !desc.StartKey.AsRawKey().Equal(startKey) {
+			return errors.Errorf("table not split off yet: %s", desc)
+		}
+		if n := len(desc.Replicas().Voters()); n != numNodes {
+			return errors.Errorf("expected %d replicas, found %d", numNodes, n)
+		}
+		return nil
+	})
+	lh, err := tc.FindRangeLeaseHolder(desc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var follower int
+	for i := 0; i < numNodes; i++ {
+		if tc.Target(i) != lh {
+			follower = i
+			break
+		}
+	}
+
+	kvDB := tc.Server(follower).DB()
+	testutils.SucceedsSoon(t, func() error {
+		readCtx, getRec, cancel := tracing.ContextWithRecordingSpan(ctx, "global-read")
+		defer cancel()
+		if err := kvDB.Txn(readCtx, func(ctx context.Context, txn *client.Txn) error {
+			_, err := txn.Scan(ctx, desc.StartKey.AsRawKey(), desc.EndKey.AsRawKey(), 0)
+			return err
+		}); err != nil {
+			return err
+		}
+		if tracing.FindMsgInRecording(getRec(), "serving via follower read") < 0 {
+			return errors.New("read was not served by a follower")
+		}
+		return nil
+	})
 }
 
 func TestFollowerReadMultipleValidation(t *testing.T) {
@@ -667,7 +667,9 @@ func prepareNewTableDescsForIngestion(
 	// Write the new TableDescriptors and flip the namespace entries over to
 	// them. After this call, any queries on a table will be served by the newly
 	// imported data.
-	if err := backupccl.WriteTableDescs(ctx, txn, nil, tableDescs, p.User(), p.ExecCfg().Settings, seqValKVs); err != nil {
+	if err := backupccl.WriteTableDescs(
+		ctx, txn, nil, tableDescs, tree.RequestedDescriptors, p.User(), p.ExecCfg().Settings, seqValKVs,
+	); err != nil {
 		return nil, errors.Wrapf(err, "creating tables")
 	}
 
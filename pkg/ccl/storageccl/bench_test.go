@@ -76,7 +76,7 @@ func BenchmarkAddSSTable(b *testing.B) {
 				totalLen += int64(len(data))
 
 				b.StartTimer()
-				if err := kvDB.AddSSTable(
+				if _, err := kvDB.AddSSTable(
 					ctx, span.Key, span.EndKey, data, false /* disallowShadowing */, nil /* stats */, false, /* ingestAsWrites */
 				); err != nil {
 					b.Fatalf("%+v", err)
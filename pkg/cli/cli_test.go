@@ -2115,6 +2115,7 @@ SELECT concat('crdb_internal.', table_name) as name FROM [ SHOW TABLES FROM crdb
 'builtin_functions',
 'create_statements',
 'forward_dependencies',
+'hot_keys',
 'index_columns',
 'table_columns',
 'table_indexes',
@@ -2179,6 +2180,7 @@ writing ` + os.DevNull + `
   debug/system.namespace.txt
   debug/crdb_internal.kv_node_status.txt
   debug/crdb_internal.kv_store_status.txt
+  debug/crdb_internal.hot_ranges.txt
   debug/crdb_internal.schema_changes.txt
   debug/crdb_internal.partitions.txt
   debug/crdb_internal.zones.txt
@@ -2242,6 +2244,10 @@ writing ` + os.DevNull + `
   debug/schema/system/reports_meta.json
   debug/schema/system/role_members.json
   debug/schema/system/settings.json
+  debug/schema/system/statement_bundle_chunks.json
+  debug/schema/system/statement_diagnostics.json
+  debug/schema/system/statement_diagnostics_requests.json
+  debug/schema/system/statement_hints.json
   debug/schema/system/table_statistics.json
   debug/schema/system/ui.json
   debug/schema/system/users.json
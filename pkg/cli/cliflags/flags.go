@@ -678,6 +678,19 @@ for example:
   --store=path=/mnt/ssd01,size=.2              -> 20% of available space
 
 </PRE>
+The "raft-log-path" field places the store's Raft log in a separate storage
+engine in the given directory, which can be located on a different (typically
+faster) device than the rest of the store. An existing store is migrated when
+the field is first added, for example:
+<PRE>
+
+  --store=path=/mnt/hda1,raft-log-path=/mnt/ssd01/raftlog
+
+</PRE>
+Once set, the field must not be removed from the store's definition, and the
+directory must not be changed. To move the Raft log back into the store, stop
+the node and run "cockroach debug merge-raft-log" before removing the field.
+
 For an in-memory store, the "type" and "size" fields are required, and the
 "path" field is forbidden. The "type" field must be set to "mem", and the
 "size" field must be set to the true maximum bytes or percentage of available
@@ -815,6 +828,13 @@ File containing the JSON output from a node's /_status/gossip/ endpoint.
 If specified, takes priority over host/port flags.`,
 	}
 
+	RaftLogPath = FlagInfo{
+		Name: "raft-log-path",
+		Description: `
+Directory of the separate storage engine holding the store's Raft log, for a
+store whose definition includes the raft-log-path field.`,
+	}
+
 	PrintSystemConfig = FlagInfo{
 		Name: "print-system-config",
 		Description: `
@@ -101,6 +101,7 @@ func initCLIDefaults() {
 	debugCtx.printSystemConfig = false
 	debugCtx.maxResults = 1000
 	debugCtx.ballastSize = base.SizeSpec{InBytes: 1000000000}
+	debugCtx.raftLogPath = ""
 
 	serverCfg.ReadyFn = nil
 	serverCfg.DelayedBootstrapFn = nil
@@ -271,6 +272,7 @@ var debugCtx struct {
 	ballastSize       base.SizeSpec
 	printSystemConfig bool
 	maxResults        int64
+	raftLogPath       string
 }
 
 // startCtx captures the command-line arguments for the `start` command.
@@ -444,7 +444,9 @@ var debugRaftLogCmd = &cobra.Command{
 	Use:   "raft-log <directory> <range id>",
 	Short: "print the raft log for a range",
 	Long: `
-Prints all log entries in a store for the given range.
+Prints all log entries in a store for the given range. For a store which keeps
+its Raft log in a separate engine, the engine's directory must be passed using
+--raft-log-path.
 `,
 	Args: cobra.ExactArgs(2),
 	RunE: MaybeDecorateGRPCError(runDebugRaftLog),
@@ -464,6 +466,20 @@ func runDebugRaftLog(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	separated, err := storage.RaftLogSeparated(context.Background(), db)
+	if err != nil {
+		return err
+	}
+	if separated {
+		if debugCtx.raftLogPath == "" {
+			return errors.New("the store keeps its Raft log in a separate engine; " +
+				"pass its directory using --raft-log-path")
+		}
+		if db, err = OpenExistingStore(debugCtx.raftLogPath, stopper, true /* readOnly */); err != nil {
+			return err
+		}
+	}
+
 	start := keys.RaftLogPrefix(rangeID)
 	end := keys.RaftLogPrefix(rangeID).PrefixEnd()
 	fmt.Printf("Printing keys %s -> %s (RocksDB keys: %#x - %#x )\n",
@@ -477,6 +493,33 @@ func runDebugRaftLog(cmd *cobra.Command, args []string) error {
 	})
 }
 
+var debugMergeRaftLogCmd = &cobra.Command{
+	Use:   "merge-raft-log <directory> <raft log directory>",
+	Short: "move a store's Raft log back from a separate engine",
+	Long: `
+Moves the Raft log of a store which keeps it in a separate storage engine (see
+the raft-log-path field of --store) back into the store, after which the node
+can be restarted without the raft-log-path field. The node must be stopped.
+`,
+	Args: cobra.ExactArgs(2),
+	RunE: MaybeDecorateGRPCError(runDebugMergeRaftLog),
+}
+
+func runDebugMergeRaftLog(cmd *cobra.Command, args []string) error {
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.Background())
+
+	db, err := OpenExistingStore(args[0], stopper, false /* readOnly */)
+	if err != nil {
+		return err
+	}
+	raftDB, err := OpenExistingStore(args[1], stopper, false /* readOnly */)
+	if err != nil {
+		return err
+	}
+	return storage.MergeRaftLogEngine(context.Background(), db, raftDB)
+}
+
 var debugGCCmd = &cobra.Command{
 	Use:   "estimate-gc <directory> [range id] [ttl-in-seconds]",
 	Short: "find out what a GC run would do",
@@ -1194,6 +1237,7 @@ var DebugCmdsForRocksDB = []*cobra.Command{
 	debugCompactCmd,
 	debugGCCmd,
 	debugKeysCmd,
+	debugMergeRaftLogCmd,
 	debugRaftLogCmd,
 	debugRangeDataCmd,
 	debugRangeDescriptorsCmd,
@@ -626,6 +626,10 @@ func init() {
 		f := debugRangeDataCmd.Flags()
 		BoolFlag(f, &debugCtx.replicated, cliflags.Replicated, debugCtx.replicated)
 	}
+	{
+		f := debugRaftLogCmd.Flags()
+		StringFlag(f, &debugCtx.raftLogPath, cliflags.RaftLogPath, debugCtx.raftLogPath)
+	}
 	{
 		f := debugGossipValuesCmd.Flags()
 		StringFlag(f, &debugCtx.inputFile, cliflags.GossipInputFile, debugCtx.inputFile)
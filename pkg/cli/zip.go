@@ -63,6 +63,7 @@ var debugZipTablesPerCluster = []string{
 
 	"crdb_internal.kv_node_status",
 	"crdb_internal.kv_store_status",
+	"crdb_internal.hot_ranges",
 
 	"crdb_internal.schema_changes",
 	"crdb_internal.partitions",
@@ -1036,7 +1036,7 @@ var specs = []stmtSpec{
 	},
 	{
 		name:    "simple_select_clause",
-		inline:  []string{"opt_all_clause", "distinct_clause", "distinct_on_clause", "opt_as_of_clause", "as_of_clause", "expr_list", "target_list", "from_clause", "opt_where_clause", "where_clause", "group_clause", "having_clause", "window_clause", "from_list"},
+		inline:  []string{"opt_all_clause", "distinct_clause", "distinct_on_clause", "opt_as_of_clause", "as_of_clause", "expr_list", "target_list", "from_clause", "opt_where_clause", "where_clause", "group_clause", "group_by_list", "having_clause", "window_clause", "from_list"},
 		unlink:  []string{"index_name"},
 		nosplit: true,
 	},
@@ -1141,7 +1141,7 @@ var specs = []stmtSpec{
 	{
 		name:    "show_backup",
 		stmt:    "show_backup_stmt",
-		match:   []*regexp.Regexp{regexp.MustCompile("'SHOW' 'BACKUP'")},
+		match:   []*regexp.Regexp{regexp.MustCompile("'SHOW' 'BACKUPS?'")},
 		replace: map[string]string{"string_or_placeholder": "location"},
 		unlink:  []string{"location"},
 	},
@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 )
 
 // column is an interface that represents a raw array of a Go native type.
@@ -79,6 +80,13 @@ type Vec interface {
 	Decimal() []apd.Decimal
 	// Timestamp returns a time.Time slice.
 	Timestamp() []time.Time
+	// Interval returns a duration.Duration slice.
+	Interval() []duration.Duration
+	// TimestampTZ returns a time.Time slice that holds TIMESTAMPTZ values.
+	TimestampTZ() []time.Time
+	// Datum returns an interface{} slice. The elements are tree.Datums that
+	// are only interpreted outside of this package.
+	Datum() []interface{}
 
 	// Col returns the raw, typeless backing storage for this Vec.
 	Col() interface{}
@@ -169,6 +177,12 @@ func NewMemColumn(t coltypes.T, n int) Vec {
 		return &memColumn{t: t, col: make([]apd.Decimal, n), nulls: nulls}
 	case coltypes.Timestamp:
 		return &memColumn{t: t, col: make([]time.Time, n), nulls: nulls}
+	case coltypes.Interval:
+		return &memColumn{t: t, col: make([]duration.Duration, n), nulls: nulls}
+	case coltypes.TimestampTZ:
+		return &memColumn{t: t, col: make([]time.Time, n), nulls: nulls}
+	case coltypes.Datum:
+		return &memColumn{t: t, col: make([]interface{}, n), nulls: nulls}
 	default:
 		panic(fmt.Sprintf("unhandled type %s", t))
 	}
@@ -214,6 +228,18 @@ func (m *memColumn) Timestamp() []time.Time {
 	return m.col.([]time.Time)
 }
 
+func (m *memColumn) Interval() []duration.Duration {
+	return m.col.([]duration.Duration)
+}
+
+func (m *memColumn) TimestampTZ() []time.Time {
+	return m.col.([]time.Time)
+}
+
+func (m *memColumn) Datum() []interface{} {
+	return m.col.([]interface{})
+}
+
 func (m *memColumn) Col() interface{} {
 	return m.col
 }
@@ -250,8 +276,12 @@ func (m *memColumn) Length() int {
 		return len(m.col.([]float64))
 	case coltypes.Decimal:
 		return len(m.col.([]apd.Decimal))
-	case coltypes.Timestamp:
+	case coltypes.Timestamp, coltypes.TimestampTZ:
 		return len(m.col.([]time.Time))
+	case coltypes.Interval:
+		return len(m.col.([]duration.Duration))
+	case coltypes.Datum:
+		return len(m.col.([]interface{}))
 	default:
 		panic(fmt.Sprintf("unhandled type %s", m.t))
 	}
@@ -273,8 +303,12 @@ func (m *memColumn) SetLength(l int) {
 		m.col = m.col.([]float64)[:l]
 	case coltypes.Decimal:
 		m.col = m.col.([]apd.Decimal)[:l]
-	case coltypes.Timestamp:
+	case coltypes.Timestamp, coltypes.TimestampTZ:
 		m.col = m.col.([]time.Time)[:l]
+	case coltypes.Interval:
+		m.col = m.col.([]duration.Duration)[:l]
+	case coltypes.Datum:
+		m.col = m.col.([]interface{})[:l]
 	default:
 		panic(fmt.Sprintf("unhandled type %s", m.t))
 	}
@@ -296,8 +330,12 @@ func (m *memColumn) Capacity() int {
 		return cap(m.col.([]float64))
 	case coltypes.Decimal:
 		return cap(m.col.([]apd.Decimal))
-	case coltypes.Timestamp:
+	case coltypes.Timestamp, coltypes.TimestampTZ:
 		return cap(m.col.([]time.Time))
+	case coltypes.Interval:
+		return cap(m.col.([]duration.Duration))
+	case coltypes.Datum:
+		return cap(m.col.([]interface{}))
 	default:
 		panic(fmt.Sprintf("unhandled type %s", m.t))
 	}
@@ -32,6 +32,7 @@ import (
 	// block. This was picked because it sorts after "pkg/sql/exec/execgen" and
 	// has no deps.
 	_ "github.com/cockroachdb/cockroach/pkg/util/bufalloc"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 )
 
 // {{/*
@@ -49,6 +50,9 @@ var _ apd.Decimal
 // Dummy import to pull in "time" package.
 var _ time.Time
 
+// Dummy import to pull in "duration" package.
+var _ duration.Duration
+
 // */}}
 
 func (m *memColumn) Append(args SliceArgs) {
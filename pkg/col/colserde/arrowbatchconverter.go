@@ -11,8 +11,10 @@
 package colserde
 
 import (
+	"encoding/binary"
 	"fmt"
 	"reflect"
+	"time"
 	"unsafe"
 
 	"github.com/apache/arrow/go/arrow"
@@ -20,6 +22,7 @@ import (
 	"github.com/apache/arrow/go/arrow/memory"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/errors"
 )
 
@@ -77,6 +80,9 @@ const (
 	sizeOfInt32   = int(unsafe.Sizeof(int32(0)))
 	sizeOfInt64   = int(unsafe.Sizeof(int64(0)))
 	sizeOfFloat64 = int(unsafe.Sizeof(float64(0)))
+	// sizeOfInterval is the size of the serialized representation of an
+	// interval, which consists of its months, days and nanos.
+	sizeOfInterval = 3 * sizeOfInt64
 )
 
 var supportedTypes = func() map[coltypes.T]struct{} {
@@ -89,6 +95,8 @@ var supportedTypes = func() map[coltypes.T]struct{} {
 		coltypes.Int64,
 		coltypes.Float64,
 		coltypes.Timestamp,
+		coltypes.Interval,
+		coltypes.TimestampTZ,
 	} {
 		typs[t] = struct{}{}
 	}
@@ -115,17 +123,23 @@ func (c *ArrowBatchConverter) BatchToArrow(batch coldata.Batch) ([]*array.Data,
 			arrowBitmap = n.NullBitmap()
 		}
 
-		if typ == coltypes.Bool || typ == coltypes.Timestamp {
-			// Bools and Timestamps are handled differently from other coltypes.
-			// Refer to the comment on ArrowBatchConverter.builders for more
-			// information.
+		if typ == coltypes.Bool || typ == coltypes.Timestamp || typ == coltypes.Interval ||
+			typ == coltypes.TimestampTZ {
+			// Bools, Timestamps and Intervals are handled differently from other
+			// coltypes. Refer to the comment on ArrowBatchConverter.builders for
+			// more information.
 			var data *array.Data
 			switch typ {
 			case coltypes.Bool:
 				c.builders.boolBuilder.AppendValues(vec.Bool()[:n], nil /* valid */)
 				data = c.builders.boolBuilder.NewBooleanArray().Data()
-			case coltypes.Timestamp:
-				timestamps := vec.Timestamp()[:n]
+			case coltypes.Timestamp, coltypes.TimestampTZ:
+				var timestamps []time.Time
+				if typ == coltypes.Timestamp {
+					timestamps = vec.Timestamp()[:n]
+				} else {
+					timestamps = vec.TimestampTZ()[:n]
+				}
 				for _, ts := range timestamps {
 					marshaled, err := ts.MarshalBinary()
 					if err != nil {
@@ -134,6 +148,16 @@ func (c *ArrowBatchConverter) BatchToArrow(batch coldata.Batch) ([]*array.Data,
 					c.builders.binaryBuilder.Append(marshaled)
 				}
 				data = c.builders.binaryBuilder.NewBinaryArray().Data()
+			case coltypes.Interval:
+				intervals := vec.Interval()[:n]
+				var marshaled [sizeOfInterval]byte
+				for _, d := range intervals {
+					binary.LittleEndian.PutUint64(marshaled[0:], uint64(d.Months))
+					binary.LittleEndian.PutUint64(marshaled[sizeOfInt64:], uint64(d.Days))
+					binary.LittleEndian.PutUint64(marshaled[2*sizeOfInt64:], uint64(d.Nanos()))
+					c.builders.binaryBuilder.Append(marshaled[:])
+				}
+				data = c.builders.binaryBuilder.NewBinaryArray().Data()
 			default:
 				panic(fmt.Sprintf("unexpected type %s", typ))
 			}
@@ -246,7 +270,8 @@ func (c *ArrowBatchConverter) ArrowToBatch(data []*array.Data, b coldata.Batch)
 		d := data[i]
 
 		var arr array.Interface
-		if typ == coltypes.Bool || typ == coltypes.Bytes || typ == coltypes.Timestamp {
+		if typ == coltypes.Bool || typ == coltypes.Bytes || typ == coltypes.Timestamp ||
+			typ == coltypes.Interval || typ == coltypes.TimestampTZ {
 			switch typ {
 			case coltypes.Bool:
 				boolArr := array.NewBooleanData(d)
@@ -266,7 +291,7 @@ func (c *ArrowBatchConverter) ArrowToBatch(data []*array.Data, b coldata.Batch)
 				}
 				coldata.BytesFromArrowSerializationFormat(vec.Bytes(), bytes, bytesArr.ValueOffsets())
 				arr = bytesArr
-			case coltypes.Timestamp:
+			case coltypes.Timestamp, coltypes.TimestampTZ:
 				// TODO(yuzefovich): this serialization is quite inefficient - improve
 				// it.
 				bytesArr := array.NewBinaryData(d)
@@ -278,13 +303,31 @@ func (c *ArrowBatchConverter) ArrowToBatch(data []*array.Data, b coldata.Batch)
 					bytes = make([]byte, 0)
 				}
 				offsets := bytesArr.ValueOffsets()
-				vecArr := vec.Timestamp()
+				var vecArr []time.Time
+				if typ == coltypes.Timestamp {
+					vecArr = vec.Timestamp()
+				} else {
+					vecArr = vec.TimestampTZ()
+				}
 				for i := 0; i < len(offsets)-1; i++ {
 					if err := vecArr[i].UnmarshalBinary(bytes[offsets[i]:offsets[i+1]]); err != nil {
 						return err
 					}
 				}
 				arr = bytesArr
+			case coltypes.Interval:
+				bytesArr := array.NewBinaryData(d)
+				bytes := bytesArr.ValueBytes()
+				vecArr := vec.Interval()
+				for i := 0; i < bytesArr.Len(); i++ {
+					marshaled := bytes[i*sizeOfInterval : (i+1)*sizeOfInterval]
+					vecArr[i] = duration.DecodeDuration(
+						int64(binary.LittleEndian.Uint64(marshaled[0:])),
+						int64(binary.LittleEndian.Uint64(marshaled[sizeOfInt64:])),
+						int64(binary.LittleEndian.Uint64(marshaled[2*sizeOfInt64:])),
+					)
+				}
+				arr = bytesArr
 			default:
 				panic(fmt.Sprintf("unexpected type %s", typ))
 			}
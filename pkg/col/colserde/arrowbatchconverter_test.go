@@ -32,7 +32,9 @@ func randomBatch(allocator *colexec.Allocator) ([]coltypes.T, coldata.Batch) {
 	availableTyps := make([]coltypes.T, 0, len(coltypes.AllTypes))
 	for _, typ := range coltypes.AllTypes {
 		// TODO(asubiotto,jordan): We do not support decimal, timestamp conversion yet.
-		if typ == coltypes.Decimal || typ == coltypes.Timestamp {
+		// Datum columns can't be serialized at all.
+		if typ == coltypes.Decimal || typ == coltypes.Timestamp || typ == coltypes.TimestampTZ ||
+			typ == coltypes.Datum {
 			continue
 		}
 		availableTyps = append(availableTyps, typ)
@@ -331,6 +331,11 @@ func (w *countingWriter) Write(buf []byte) (int, error) {
 	return n, err
 }
 
+// timestampTZTimezone is the time zone of the arrow type of the TIMESTAMPTZ
+// columns. The time zone of the values is serialized with them, so it's only
+// used to distinguish these columns from the TIMESTAMP ones.
+const timestampTZTimezone = "UTC"
+
 func schema(fb *flatbuffers.Builder, typs []coltypes.T) flatbuffers.UOffsetT {
 	fieldOffsets := make([]flatbuffers.UOffsetT, len(typs))
 	for idx, typ := range typs {
@@ -368,6 +373,30 @@ func schema(fb *flatbuffers.Builder, typs []coltypes.T) flatbuffers.UOffsetT {
 			arrowserde.FloatingPointAddPrecision(fb, arrowserde.PrecisionDOUBLE)
 			fbTypOffset = arrowserde.FloatingPointEnd(fb)
 			fbTyp = arrowserde.TypeFloatingPoint
+		case coltypes.Timestamp:
+			// Timestamps are serialized as their binary marshaled representation
+			// (see ArrowBatchConverter), so the unit is only used to distinguish
+			// them from the other types.
+			arrowserde.TimestampStart(fb)
+			arrowserde.TimestampAddUnit(fb, arrowserde.TimeUnitNANOSECOND)
+			fbTypOffset = arrowserde.TimestampEnd(fb)
+			fbTyp = arrowserde.TypeTimestamp
+		case coltypes.TimestampTZ:
+			// TIMESTAMPTZ values are serialized like the timestamps, and the time
+			// zone of the arrow type tells the two apart.
+			timezone := fb.CreateString(timestampTZTimezone)
+			arrowserde.TimestampStart(fb)
+			arrowserde.TimestampAddUnit(fb, arrowserde.TimeUnitNANOSECOND)
+			arrowserde.TimestampAddTimezone(fb, timezone)
+			fbTypOffset = arrowserde.TimestampEnd(fb)
+			fbTyp = arrowserde.TypeTimestamp
+		case coltypes.Interval:
+			// Similarly to timestamps, intervals are serialized as their months,
+			// days and nanos.
+			arrowserde.IntervalStart(fb)
+			arrowserde.IntervalAddUnit(fb, arrowserde.IntervalUnitDAY_TIME)
+			fbTypOffset = arrowserde.IntervalEnd(fb)
+			fbTyp = arrowserde.TypeInterval
 		default:
 			panic(errors.Errorf(`don't know how to map %s`, typ))
 		}
@@ -451,6 +480,15 @@ func typeFromField(field *arrowserde.Field) (coltypes.T, error) {
 		default:
 			return coltypes.Unhandled, errors.Errorf(`unhandled float precision %d`, floatType.Precision())
 		}
+	case arrowserde.TypeTimestamp:
+		var timestampType arrowserde.Timestamp
+		timestampType.Init(typeTab.Bytes, typeTab.Pos)
+		if len(timestampType.Timezone()) > 0 {
+			return coltypes.TimestampTZ, nil
+		}
+		return coltypes.Timestamp, nil
+	case arrowserde.TypeInterval:
+		return coltypes.Interval, nil
 	}
 	// It'd be nice if this error could include more details, but flatbuffers
 	// doesn't make a String method or anything like that.
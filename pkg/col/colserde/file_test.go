@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -126,3 +127,36 @@ func TestFileIndexing(t *testing.T) {
 		require.Equal(t, int64(batchIdx), b.ColVec(0).Int64()[0])
 	}
 }
+
+func TestFileTimestamps(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// TIMESTAMPTZ columns are serialized like the TIMESTAMP ones, but their
+	// type must survive the roundtrip.
+	typs := []coltypes.T{coltypes.Timestamp, coltypes.TimestampTZ}
+	ts := timeutil.Unix(1234567890, 123456789)
+
+	var buf bytes.Buffer
+	s, err := colserde.NewFileSerializer(&buf, typs)
+	require.NoError(t, err)
+	b := coldata.NewMemBatchWithSize(typs, 2)
+	b.SetLength(2)
+	b.ColVec(0).Timestamp()[0] = ts
+	b.ColVec(0).Nulls().SetNull(1)
+	b.ColVec(1).TimestampTZ()[1] = ts
+	b.ColVec(1).Nulls().SetNull(0)
+	require.NoError(t, s.AppendBatch(b))
+	require.NoError(t, s.Finish())
+
+	d, err := colserde.NewFileDeserializerFromBytes(buf.Bytes())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, d.Close()) }()
+	require.Equal(t, typs, d.Typs())
+	roundtrip := coldata.NewMemBatchWithSize(nil, 0)
+	require.NoError(t, d.GetBatch(0, roundtrip))
+	require.Equal(t, uint16(2), roundtrip.Length())
+	require.True(t, ts.Equal(roundtrip.ColVec(0).Timestamp()[0]))
+	require.True(t, roundtrip.ColVec(0).Nulls().NullAt(1))
+	require.True(t, ts.Equal(roundtrip.ColVec(1).TimestampTZ()[1]))
+	require.True(t, roundtrip.ColVec(1).Nulls().NullAt(0))
+}
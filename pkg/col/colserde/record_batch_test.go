@@ -12,6 +12,7 @@ package colserde_test
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"math"
 	"math/rand"
@@ -134,7 +135,7 @@ func randomDataFromType(rng *rand.Rand, t coltypes.T, n int, nullProbability flo
 			}
 			builder.(*array.FixedSizeBinaryBuilder).AppendValues(data, valid)
 		}
-	case coltypes.Timestamp:
+	case coltypes.Timestamp, coltypes.TimestampTZ:
 		var err error
 		now := timeutil.Now()
 		builder = array.NewBinaryBuilder(memory.DefaultAllocator, arrow.BinaryTypes.Binary)
@@ -148,6 +149,17 @@ func randomDataFromType(rng *rand.Rand, t coltypes.T, n int, nullProbability flo
 			}
 		}
 		builder.(*array.BinaryBuilder).AppendValues(data, valid)
+	case coltypes.Interval:
+		builder = array.NewBinaryBuilder(memory.DefaultAllocator, arrow.BinaryTypes.Binary)
+		data := make([][]byte, n)
+		for i := range data {
+			// Intervals are serialized as their months, days and nanos.
+			data[i] = make([]byte, 24)
+			for j := 0; j < 3; j++ {
+				binary.LittleEndian.PutUint64(data[i][j*8:], uint64(rng.Int63()))
+			}
+		}
+		builder.(*array.BinaryBuilder).AppendValues(data, valid)
 	default:
 		panic(fmt.Sprintf("unsupported type %s", t))
 	}
@@ -196,9 +208,9 @@ func TestRecordBatchSerializerSerializeDeserializeRandom(t *testing.T) {
 		buf             = bytes.Buffer{}
 	)
 
-	// We do not support decimals.
+	// We do not support decimals and datums.
 	for _, t := range coltypes.AllTypes {
-		if t == coltypes.Decimal {
+		if t == coltypes.Decimal || t == coltypes.Datum {
 			continue
 		}
 		supportedTypes = append(supportedTypes, t)
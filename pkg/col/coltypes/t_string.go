@@ -16,12 +16,15 @@ func _() {
 	_ = x[Int64-5]
 	_ = x[Float64-6]
 	_ = x[Timestamp-7]
-	_ = x[Unhandled-8]
+	_ = x[Interval-8]
+	_ = x[TimestampTZ-9]
+	_ = x[Datum-10]
+	_ = x[Unhandled-11]
 }
 
-const _T_name = "BoolBytesDecimalInt16Int32Int64Float64TimestampUnhandled"
+const _T_name = "BoolBytesDecimalInt16Int32Int64Float64TimestampIntervalTimestampTZDatumUnhandled"
 
-var _T_index = [...]uint8{0, 4, 9, 16, 21, 26, 31, 38, 47, 56}
+var _T_index = [...]uint8{0, 4, 9, 16, 21, 26, 31, 38, 47, 55, 66, 71, 80}
 
 func (i T) String() string {
 	if i < 0 || i >= T(len(_T_index)-1) {
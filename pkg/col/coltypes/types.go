@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 )
 
 // T represents an exec physical type - a bytes representation of a particular
@@ -41,6 +42,18 @@ const (
 	Float64
 	// Timestamp is a column of type time.Time
 	Timestamp
+	// Interval is a column of type duration.Duration
+	Interval
+	// TimestampTZ is a column of type time.Time. It is used for TIMESTAMPTZ
+	// columns, which are kept apart from the TIMESTAMP ones since the
+	// expressions that mix the two types depend on the session time zone.
+	TimestampTZ
+	// Datum is a column of type interface{}. It is used for the SQL types that
+	// don't have a native representation (JSONB and arrays), whose values are
+	// stored as tree.Datums. The operators compare and hash these values with
+	// the functions of the tree package and evaluate the other expressions
+	// over them row by row.
+	Datum
 
 	// Unhandled is a temporary value that represents an unhandled type.
 	// TODO(jordan): this should be replaced by a panic once all types are
@@ -78,6 +91,9 @@ func init() {
 	CompatibleTypes[Int64] = append(CompatibleTypes[Int64], NumberTypes...)
 	CompatibleTypes[Float64] = append(CompatibleTypes[Float64], NumberTypes...)
 	CompatibleTypes[Timestamp] = append(CompatibleTypes[Timestamp], Timestamp)
+	CompatibleTypes[Interval] = append(CompatibleTypes[Interval], Interval)
+	CompatibleTypes[TimestampTZ] = append(CompatibleTypes[TimestampTZ], TimestampTZ)
+	CompatibleTypes[Datum] = append(CompatibleTypes[Datum], Datum)
 }
 
 // FromGoType returns the type for a Go value, if applicable. Shouldn't be used at
@@ -102,6 +118,8 @@ func FromGoType(v interface{}) T {
 		return Decimal
 	case time.Time:
 		return Timestamp
+	case duration.Duration:
+		return Interval
 	default:
 		panic(fmt.Sprintf("type %T not supported yet", t))
 	}
@@ -124,8 +142,12 @@ func (t T) GoTypeName() string {
 		return "int64"
 	case Float64:
 		return "float64"
-	case Timestamp:
+	case Timestamp, TimestampTZ:
 		return "time.Time"
+	case Interval:
+		return "duration.Duration"
+	case Datum:
+		return "interface{}"
 	default:
 		panic(fmt.Sprintf("unhandled type %d", t))
 	}
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -771,6 +772,47 @@ func TestConstraintsListYAML(t *testing.T) {
 	}
 }
 
+func TestZoneConfigGlobalReads(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var zone ZoneConfig
+	if err := yaml.UnmarshalStrict([]byte("global_reads: true"), &zone); err != nil {
+		t.Fatal(err)
+	}
+	if zone.GlobalReads == nil || !*zone.GlobalReads {
+		t.Fatalf("expected global_reads to be set, got %+v", zone)
+	}
+	body, err := yaml.Marshal(zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "global_reads: true\n"; !strings.Contains(string(body), expected) {
+		t.Errorf("expected %q in marshaled zone config, got:\n%s", expected, body)
+	}
+
+	// Unset zones inherit the setting from their parent, but an explicit
+	// value on the child wins.
+	child := NewZoneConfig()
+	child.InheritFromParent(&zone)
+	if child.GlobalReads == nil || !*child.GlobalReads {
+		t.Errorf("expected child to inherit global_reads, got %+v", child)
+	}
+	child = NewZoneConfig()
+	child.GlobalReads = proto.Bool(false)
+	child.InheritFromParent(&zone)
+	if *child.GlobalReads {
+		t.Errorf("expected child to keep global_reads = false, got %+v", child)
+	}
+
+	// global_reads can be copied from another zone, which is what COPY FROM
+	// PARENT does.
+	child = NewZoneConfig()
+	child.CopyFromZone(zone, []tree.Name{"global_reads"})
+	if child.GlobalReads == nil || !*child.GlobalReads {
+		t.Errorf("expected global_reads to be copied, got %+v", child)
+	}
+}
+
 func TestMarshalableZoneConfigRoundTrip(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -644,6 +645,9 @@ func (db *DB) WriteBatch(ctx context.Context, begin, end interface{}, data []byt
 
 // AddSSTable links a file into the RocksDB log-structured merge-tree. Existing
 // data in the range is cleared.
+//
+// The returned duration is the time for which the store that ingested the
+// file asked the caller to hold off on further ingestions.
 func (db *DB) AddSSTable(
 	ctx context.Context,
 	begin, end interface{},
@@ -651,10 +655,14 @@ func (db *DB) AddSSTable(
 	disallowShadowing bool,
 	stats *enginepb.MVCCStats,
 	ingestAsWrites bool,
-) error {
+) (backpressure time.Duration, _ error) {
 	b := &Batch{}
 	b.addSSTable(begin, end, data, disallowShadowing, stats, ingestAsWrites)
-	return getOneErr(db.Run(ctx, b), b)
+	if err := getOneErr(db.Run(ctx, b), b); err != nil {
+		return 0, err
+	}
+	resp := b.RawResponse().Responses[0].GetAddSstable()
+	return time.Duration(resp.BackpressureDelayNanos), nil
 }
 
 // sendAndFill is a helper which sends the given batch and fills its results,
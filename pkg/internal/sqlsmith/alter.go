@@ -242,13 +242,20 @@ func makeCreateIndex(s *Smither) (tree.Statement, bool) {
 			continue
 		}
 		seen[col.Name] = true
-		// If this is the first column and it's invertable (i.e., JSONB), make an inverted index.
-		if len(cols) == 0 && sqlbase.ColumnTypeIsInvertedIndexable(col.Type) {
+		// If this is the first column and it's invertable (i.e., JSONB), make an
+		// inverted index. Strings are also invertable, but only sometimes get a
+		// trigram index so that they are still used in regular indexes.
+		if len(cols) == 0 && sqlbase.ColumnTypeIsInvertedIndexable(col.Type) &&
+			(col.Type.Family() != types.StringFamily || s.coin()) {
 			inverted = true
 			unique = false
-			cols = append(cols, tree.IndexElem{
+			elem := tree.IndexElem{
 				Column: col.Name,
-			})
+			}
+			if col.Type.Family() == types.StringFamily {
+				elem.OpClass = sqlbase.TrigramOpClass
+			}
+			cols = append(cols, elem)
 			break
 		}
 		if sqlbase.ColumnTypeIsIndexable(col.Type) {
@@ -72,6 +72,9 @@ var (
 	// localStoreSuggestedCompactionSuffix stores suggested compactions to
 	// be aggregated and processed on the store.
 	localStoreSuggestedCompactionSuffix = []byte("comp")
+	// localStoreRaftLogEngineSuffix marks a store whose Raft log has been
+	// migrated into a separate storage engine.
+	localStoreRaftLogEngineSuffix = []byte("rlge")
 
 	// localRemovedLeakedRaftEntriesSuffix is DEPRECATED and remains to prevent reuse.
 	localRemovedLeakedRaftEntriesSuffix = []byte("dlre")
@@ -330,6 +333,10 @@ const (
 	ReplicationCriticalLocalitiesTableID = 26
 	ReplicationStatsTableID              = 27
 	ReportsMetaTableID                   = 28
+	StatementBundleChunksTableID         = 29
+	StatementDiagnosticsRequestsTableID  = 30
+	StatementDiagnosticsTableID          = 31
+	StatementHintsTableID                = 32
 
 	// CommentType is type for system.comments
 	DatabaseCommentType = 0
@@ -65,6 +65,12 @@ func StoreClusterVersionKey() roachpb.Key {
 	return MakeStoreKey(localStoreClusterVersionSuffix, nil)
 }
 
+// StoreRaftLogEngineKey returns a store-local key marking that the store's
+// Raft log is kept in a separate storage engine.
+func StoreRaftLogEngineKey() roachpb.Key {
+	return MakeStoreKey(localStoreRaftLogEngineSuffix, nil)
+}
+
 // StoreLastUpKey returns the key for the store's "last up" timestamp.
 func StoreLastUpKey() roachpb.Key {
 	return MakeStoreKey(localStoreLastUpSuffix, nil)
@@ -187,6 +187,7 @@ var constSubKeyDict = []struct {
 	{"/gossipBootstrap", localStoreGossipSuffix},
 	{"/clusterVersion", localStoreClusterVersionSuffix},
 	{"/suggestedCompaction", localStoreSuggestedCompactionSuffix},
+	{"/raftLogEngine", localStoreRaftLogEngineSuffix},
 }
 
 func suggestedCompactionKeyPrint(key roachpb.Key) string {
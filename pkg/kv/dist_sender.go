@@ -108,9 +108,11 @@ var (
 // CanSendToFollower is used by the DistSender to determine if it needs to look
 // up the current lease holder for a request. It is used by the
 // followerreadsccl code to inject logic to check if follower reads are enabled.
-// By default, without CCL code, this function returns false.
+// globalReads is whether the range was last seen to be configured for global
+// reads, in which case its followers can serve reads at present time. By
+// default, without CCL code, this function returns false.
 var CanSendToFollower = func(
-	clusterID uuid.UUID, st *cluster.Settings, ba roachpb.BatchRequest,
+	clusterID uuid.UUID, st *cluster.Settings, ba roachpb.BatchRequest, globalReads bool,
 ) bool {
 	return false
 }
@@ -504,7 +506,8 @@ func (ds *DistSender) sendSingleRange(
 	// it to the front.
 	var cachedLeaseHolder roachpb.ReplicaDescriptor
 	canSendToFollower := ds.clusterID != nil &&
-		CanSendToFollower(ds.clusterID.Get(), ds.st, ba)
+		CanSendToFollower(ds.clusterID.Get(), ds.st, ba,
+			ds.leaseHolderCache.LookupGlobalReads(ctx, desc.RangeID))
 	if !canSendToFollower && ba.RequiresLeaseHolder() {
 		if storeID, ok := ds.leaseHolderCache.Lookup(ctx, desc.RangeID); ok {
 			if i := replicas.FindReplica(storeID); i >= 0 {
@@ -535,6 +538,9 @@ func (ds *DistSender) sendSingleRange(
 	// Untangle the error from the received response.
 	pErr := br.Error
 	br.Error = nil // scrub the response error
+	if pErr == nil {
+		ds.leaseHolderCache.UpdateGlobalReads(ctx, desc.RangeID, br.GlobalReads)
+	}
 	return br, pErr
 }
 
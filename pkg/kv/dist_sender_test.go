@@ -2943,7 +2943,9 @@ func TestCanSendToFollower(t *testing.T) {
 	old := CanSendToFollower
 	defer func() { CanSendToFollower = old }()
 	canSend := true
-	CanSendToFollower = func(_ uuid.UUID, _ *cluster.Settings, ba roachpb.BatchRequest) bool {
+	CanSendToFollower = func(
+		_ uuid.UUID, _ *cluster.Settings, ba roachpb.BatchRequest, _ bool,
+	) bool {
 		return ba.IsReadOnly() && canSend
 	}
 
@@ -3036,6 +3038,99 @@ func TestCanSendToFollower(t *testing.T) {
 	}
 }
 
+// TestCanSendToFollowerGlobalReads tests that the DistSender caches whether
+// ranges are configured for global reads from their responses and passes
+// that to CanSendToFollower.
+func TestCanSendToFollowerGlobalReads(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+
+	old := CanSendToFollower
+	defer func() { CanSendToFollower = old }()
+	var sawGlobalReads bool
+	CanSendToFollower = func(
+		_ uuid.UUID, _ *cluster.Settings, ba roachpb.BatchRequest, globalReads bool,
+	) bool {
+		sawGlobalReads = globalReads
+		return ba.IsReadOnly() && globalReads
+	}
+
+	clock := hlc.NewClock(hlc.UnixNano, time.Nanosecond)
+	rpcContext := rpc.NewInsecureTestingContext(clock, stopper)
+	g := makeGossip(t, stopper, rpcContext)
+	for _, n := range testUserRangeDescriptor3Replicas.InternalReplicas {
+		if err := g.AddInfoProto(
+			gossip.MakeNodeIDKey(n.NodeID),
+			newNodeDesc(n.NodeID),
+			gossip.NodeDescriptorTTL,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var sentTo ReplicaInfo
+	globalReads := true
+	var testFn simpleSendFn = func(
+		_ context.Context,
+		_ SendOptions,
+		r ReplicaSlice,
+		args roachpb.BatchRequest,
+	) (*roachpb.BatchResponse, error) {
+		sentTo = r[0]
+		reply := args.CreateReply()
+		reply.GlobalReads = globalReads
+		return reply, nil
+	}
+	cfg := DistSenderConfig{
+		AmbientCtx: log.AmbientContext{Tracer: tracing.NewTracer()},
+		Clock:      clock,
+		RPCContext: rpcContext,
+		TestingKnobs: ClientTestingKnobs{
+			TransportFactory: adaptSimpleTransport(testFn),
+		},
+		RangeDescriptorDB: threeReplicaMockRangeDescriptorDB,
+		NodeDialer:        nodedialer.New(rpcContext, gossip.AddressResolver(g)),
+	}
+	ds := NewDistSender(cfg, g)
+	ds.clusterID = &base.ClusterIDContainer{}
+	header := roachpb.Header{Txn: &roachpb.Transaction{}}
+
+	for i, c := range []struct {
+		globalReads          bool
+		expectGlobalReads    bool
+		expectedNode         roachpb.NodeID
+		expectedCachedResult bool
+	}{
+		// The range isn't known to be configured for global reads, so the
+		// read goes to the leaseholder, whose response says it is.
+		{true, false, 2, true},
+		// Now the read goes to the nearest replica, whose response says the
+		// range no longer is.
+		{false, true, 1, false},
+		// And so the next read goes to the leaseholder again.
+		{false, false, 2, false},
+	} {
+		globalReads = c.globalReads
+		sentTo = ReplicaInfo{}
+		// Set 2 to be the leaseholder. This retains the cached global reads bit.
+		ds.LeaseHolderCache().Update(context.TODO(), 2, 2)
+		if _, pErr := client.SendWrappedWith(
+			context.Background(), ds, header, roachpb.NewGet(roachpb.Key("a")),
+		); pErr != nil {
+			t.Fatalf("%d: unexpected error: %v", i, pErr)
+		}
+		if sawGlobalReads != c.expectGlobalReads {
+			t.Fatalf("%d: expected CanSendToFollower to see global reads %t", i, c.expectGlobalReads)
+		}
+		if sentTo.NodeID != c.expectedNode {
+			t.Fatalf("%d: unexpected replica: %v != %v", i, sentTo.NodeID, c.expectedNode)
+		}
+		if cached := ds.LeaseHolderCache().LookupGlobalReads(context.TODO(), 2); cached != c.expectedCachedResult {
+			t.Fatalf("%d: expected cached global reads %t", i, c.expectedCachedResult)
+		}
+	}
+}
+
 // TestEvictMetaRange tests that a query on a stale meta2 range should evict it
 // from the cache.
 func TestEvictMetaRange(t *testing.T) {
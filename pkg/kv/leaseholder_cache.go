@@ -25,6 +25,8 @@ var (
 )
 
 // A LeaseHolderCache is a cache of replica descriptors keyed by range ID.
+// Along with the leaseholder, it caches whether the range is configured for
+// global reads.
 type LeaseHolderCache struct {
 	shards []LeaseHolderCacheShard
 }
@@ -55,6 +57,13 @@ func NewLeaseHolderCache(size func() int64) *LeaseHolderCache {
 	return leaseholderCache
 }
 
+// leaseHolderCacheEntry is the value type of the LeaseHolderCache. Either of
+// the fields may be unknown (i.e. zero) while the other one is not.
+type leaseHolderCacheEntry struct {
+	storeID     roachpb.StoreID
+	globalReads bool
+}
+
 // Lookup returns the cached leader of the given range ID.
 func (lc *LeaseHolderCache) Lookup(
 	ctx context.Context, rangeID roachpb.RangeID,
@@ -63,10 +72,12 @@ func (lc *LeaseHolderCache) Lookup(
 	ld.mu.Lock()
 	defer ld.mu.Unlock()
 	if v, ok := ld.cache.Get(rangeID); ok {
-		if log.V(2) {
-			log.Infof(ctx, "r%d: lookup leaseholder: %s", rangeID, v)
+		if storeID := v.(*leaseHolderCacheEntry).storeID; storeID != 0 {
+			if log.V(2) {
+				log.Infof(ctx, "r%d: lookup leaseholder: %s", rangeID, storeID)
+			}
+			return storeID, true
 		}
-		return v.(roachpb.StoreID), true
 	}
 	if log.V(2) {
 		log.Infof(ctx, "r%d: lookup leaseholder: not found", rangeID)
@@ -74,6 +85,18 @@ func (lc *LeaseHolderCache) Lookup(
 	return 0, false
 }
 
+// LookupGlobalReads returns whether the given range ID was last seen to be
+// configured for global reads.
+func (lc *LeaseHolderCache) LookupGlobalReads(ctx context.Context, rangeID roachpb.RangeID) bool {
+	ld := &lc.shards[int(rangeID)%len(lc.shards)]
+	ld.mu.Lock()
+	defer ld.mu.Unlock()
+	if v, ok := ld.cache.Get(rangeID); ok {
+		return v.(*leaseHolderCacheEntry).globalReads
+	}
+	return false
+}
+
 // Update invalidates the cached leader for the given range ID. If an empty
 // replica descriptor is passed, the cached leader is evicted. Otherwise, the
 // passed-in replica descriptor is cached.
@@ -92,6 +115,35 @@ func (lc *LeaseHolderCache) Update(
 		if log.V(2) {
 			log.Infof(ctx, "r%d: updating leaseholder: %d", rangeID, storeID)
 		}
-		ld.cache.Add(rangeID, storeID)
+		ld.getOrAddEntry(rangeID).storeID = storeID
+	}
+}
+
+// UpdateGlobalReads caches whether the given range ID is configured for
+// global reads. Evicting the cached leader also evicts this information.
+func (lc *LeaseHolderCache) UpdateGlobalReads(
+	ctx context.Context, rangeID roachpb.RangeID, globalReads bool,
+) {
+	ld := &lc.shards[int(rangeID)%len(lc.shards)]
+	ld.mu.Lock()
+	defer ld.mu.Unlock()
+	if v, ok := ld.cache.Get(rangeID); ok {
+		v.(*leaseHolderCacheEntry).globalReads = globalReads
+	} else if globalReads {
+		if log.V(2) {
+			log.Infof(ctx, "r%d: caching global reads", rangeID)
+		}
+		ld.getOrAddEntry(rangeID).globalReads = true
+	}
+}
+
+// getOrAddEntry returns the entry for the given range ID, adding an empty one
+// if there is none. The shard's mutex must be held.
+func (ld *LeaseHolderCacheShard) getOrAddEntry(rangeID roachpb.RangeID) *leaseHolderCacheEntry {
+	if v, ok := ld.cache.Get(rangeID); ok {
+		return v.(*leaseHolderCacheEntry)
 	}
+	e := &leaseHolderCacheEntry{}
+	ld.cache.Add(rangeID, e)
+	return e
 }
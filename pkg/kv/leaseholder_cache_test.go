@@ -63,6 +63,43 @@ func TestLeaseHolderCache(t *testing.T) {
 	}
 }
 
+func TestLeaseHolderCacheGlobalReads(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.TODO()
+	lc := NewLeaseHolderCache(staticSize(int64(defaultShards)))
+	rangeID := roachpb.RangeID(5)
+	if lc.LookupGlobalReads(ctx, rangeID) {
+		t.Fatal("lookup of missing key returned global reads")
+	}
+	// The bit can be cached before the leaseholder is known, which doesn't
+	// make the leaseholder known.
+	lc.UpdateGlobalReads(ctx, rangeID, true)
+	if !lc.LookupGlobalReads(ctx, rangeID) {
+		t.Fatal("expected global reads")
+	}
+	if repStoreID, ok := lc.Lookup(ctx, rangeID); ok {
+		t.Fatalf("lookup of unknown leaseholder returned: %d", repStoreID)
+	}
+	// Updating the leaseholder retains the bit.
+	lc.Update(ctx, rangeID, roachpb.StoreID(1))
+	if !lc.LookupGlobalReads(ctx, rangeID) {
+		t.Fatal("expected global reads after leaseholder update")
+	}
+	lc.UpdateGlobalReads(ctx, rangeID, false)
+	if lc.LookupGlobalReads(ctx, rangeID) {
+		t.Fatal("expected no global reads")
+	}
+	if repStoreID, ok := lc.Lookup(ctx, rangeID); !ok || repStoreID != 1 {
+		t.Fatalf("expected StoreID 1, got %d", repStoreID)
+	}
+	// Evicting the leaseholder evicts the bit.
+	lc.UpdateGlobalReads(ctx, rangeID, true)
+	lc.Update(ctx, rangeID, roachpb.StoreID(0))
+	if lc.LookupGlobalReads(ctx, rangeID) {
+		t.Fatal("lookup of evicted key returned global reads")
+	}
+}
+
 func BenchmarkLeaseHolderCacheParallel(b *testing.B) {
 	defer leaktest.AfterTest(b)()
 	ctx := context.TODO()
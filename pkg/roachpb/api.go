@@ -400,6 +400,7 @@ func (h *BatchResponse_Header) combine(o BatchResponse_Header) error {
 	}
 	h.Now.Forward(o.Now)
 	h.CollectedSpans = append(h.CollectedSpans, o.CollectedSpans...)
+	h.GlobalReads = h.GlobalReads && o.GlobalReads
 	return nil
 }
 
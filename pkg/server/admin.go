@@ -15,6 +15,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"sort"
 	"strconv"
@@ -42,6 +44,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ts/catalog"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -2134,3 +2137,70 @@ func (s *adminServer) dialNode(
 	}
 	return serverpb.NewAdminClient(conn), nil
 }
+
+// stmtBundlePrefix is the prefix of the endpoint that serves statement
+// diagnostics bundles (see sql/explain_bundle.go) as zip files. The bundle ID
+// follows the prefix.
+const stmtBundlePrefix = adminPrefix + "stmtbundle/"
+
+// handleStmtBundle serves the statement diagnostics bundle with the ID given
+// in the URL path. The bundle is assembled from its chunks in
+// system.statement_bundle_chunks.
+func (s *adminServer) handleStmtBundle(w http.ResponseWriter, r *http.Request) {
+	ctx := s.server.AnnotateCtx(r.Context())
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, stmtBundlePrefix), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid bundle id", http.StatusBadRequest)
+		return
+	}
+	userName, ok := ctx.Value(webSessionUserKey{}).(string)
+	if !ok {
+		if !s.server.cfg.Insecure {
+			http.Error(w, "a valid authentication cookie is required", http.StatusUnauthorized)
+			return
+		}
+		// Insecure clusters don't have web sessions, and every client acts as
+		// root.
+		userName = security.RootUser
+	}
+
+	// Fetch all the chunks of the bundle, in order, with a single query. A
+	// chunk which is missing from system.statement_bundle_chunks results in a
+	// NULL row.
+	rows, _ /* cols */, err := s.server.internalExecutor.QueryWithUser(
+		ctx, "admin-stmt-bundle", nil /* txn */, userName,
+		`SELECT c.data
+		   FROM unnest(
+		          (SELECT bundle_chunks FROM system.statement_diagnostics WHERE id = $1)
+		        ) WITH ORDINALITY AS b (chunk_id, ord)
+		   LEFT JOIN system.statement_bundle_chunks AS c ON c.id = b.chunk_id
+		  ORDER BY b.ord`,
+		id,
+	)
+	if err != nil {
+		log.Error(ctx, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(rows) == 0 {
+		http.Error(w, "no such bundle", http.StatusNotFound)
+		return
+	}
+
+	// Put together the entire bundle. Ideally we would stream it in chunks, but
+	// it's hard to return errors once we start.
+	var bundle bytes.Buffer
+	for _, row := range rows {
+		if row[0] == tree.DNull {
+			http.Error(w, "no such bundle chunk", http.StatusNotFound)
+			return
+		}
+		bundle.WriteString(string(*row[0].(*tree.DBytes)))
+	}
+
+	w.Header().Set(httputil.ContentTypeHeader, "application/zip")
+	w.Header().Set(
+		"Content-Disposition", fmt.Sprintf("attachment; filename=stmt-bundle-%d.zip", id),
+	)
+	_, _ = io.Copy(w, &bundle)
+}
@@ -1717,3 +1717,68 @@ func TestStatsforSpanOnLocalMax(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestAdminAPIStmtBundle(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+	db := sqlutils.MakeSQLRunner(sqlDB)
+
+	var link string
+	rows := db.Query(t, "EXPLAIN ANALYZE (DEBUG) SELECT 1")
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasPrefix(line, "Direct link: ") {
+			link = strings.TrimPrefix(line, "Direct link: ")
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if link == "" {
+		t.Fatal("no link to the bundle in the output of EXPLAIN ANALYZE (DEBUG)")
+	}
+	bundleURL := s.AdminURL() + link
+
+	get := func(client http.Client, path string) (*http.Response, []byte) {
+		resp, err := client.Get(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, body
+	}
+
+	// The bundle can only be fetched with a web session.
+	anonClient, err := s.GetHTTPClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, _ := get(anonClient, bundleURL); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected status %d without a web session, got %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	client, err := s.GetAuthenticatedHTTPClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, body := get(client, bundleURL)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, resp.StatusCode, body)
+	}
+	if !bytes.HasPrefix(body, []byte("PK")) {
+		t.Fatalf("expected a zip file, got %q", body)
+	}
+
+	if resp, _ := get(client, s.AdminURL()+adminPrefix+"stmtbundle/12345"); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status %d for a missing bundle, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
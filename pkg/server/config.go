@@ -276,6 +276,9 @@ type Config struct {
 	EnableWebSessionAuthentication bool
 
 	enginesCreated bool
+	// raftEngines maps the engines created by CreateEngines to the separate
+	// engines holding their Raft log, for the stores which have one.
+	raftEngines map[engine.Engine]engine.Engine
 }
 
 // HistogramWindowInterval is used to determine the approximate length of time
@@ -427,6 +430,9 @@ func (e *Engines) Close() {
 func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 	engines := Engines(nil)
 	defer engines.Close()
+	raftEngines := Engines(nil)
+	defer raftEngines.Close()
+	raftEngineFor := make(map[engine.Engine]engine.Engine)
 
 	if cfg.enginesCreated {
 		return Engines{}, errors.Errorf("engines already created")
@@ -505,8 +511,57 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 			details = append(details, fmt.Sprintf("store %d: RocksDB, max size %s, max open file limit %d",
 				i, humanizeutil.IBytes(sizeInBytes), openFileLimitPerStore))
 
-			var eng engine.Engine
-			var err error
+			newEngine := func(storageConfig base.StorageConfig) (engine.Engine, error) {
+				switch cfg.StorageEngine {
+				case enginepb.EngineTypePebble:
+					// TODO(itsbilal): Tune these options, and allow them to be overridden
+					// in the spec (similar to the existing spec.RocksDBOptions and others).
+					pebbleConfig := engine.PebbleConfig{
+						StorageConfig: storageConfig,
+						Opts:          engine.DefaultPebbleOptions(),
+					}
+					pebbleConfig.Opts.Cache = pebbleCache
+					pebbleConfig.Opts.MaxOpenFiles = int(openFileLimitPerStore)
+					return engine.NewPebble(ctx, pebbleConfig)
+				case enginepb.EngineTypeRocksDB:
+					rocksDBConfig := engine.RocksDBConfig{
+						StorageConfig:           storageConfig,
+						MaxOpenFiles:            openFileLimitPerStore,
+						WarnLargeBatchThreshold: 500 * time.Millisecond,
+						RocksDBOptions:          spec.RocksDBOptions,
+					}
+
+					return engine.NewRocksDB(rocksDBConfig, cache)
+				default:
+					// cfg.StorageEngine == enginepb.EngineTypeTeePebbleRocksDB
+					pebbleConfig := engine.PebbleConfig{
+						StorageConfig: storageConfig,
+						Opts:          engine.DefaultPebbleOptions(),
+					}
+					pebbleConfig.Dir = filepath.Join(pebbleConfig.Dir, "pebble")
+					pebbleConfig.Opts.Cache = pebbleCache
+					pebbleConfig.Opts.MaxOpenFiles = int(openFileLimitPerStore)
+					pebbleEng, err := engine.NewPebble(ctx, pebbleConfig)
+					if err != nil {
+						return nil, err
+					}
+
+					rocksDBConfig := engine.RocksDBConfig{
+						StorageConfig:           storageConfig,
+						MaxOpenFiles:            openFileLimitPerStore,
+						WarnLargeBatchThreshold: 500 * time.Millisecond,
+						RocksDBOptions:          spec.RocksDBOptions,
+					}
+					rocksDBConfig.Dir = filepath.Join(rocksDBConfig.Dir, "rocksdb")
+
+					rocksdbEng, err := engine.NewRocksDB(rocksDBConfig, cache)
+					if err != nil {
+						return nil, err
+					}
+
+					return engine.NewTee(ctx, rocksdbEng, pebbleEng), nil
+				}
+			}
 			storageConfig := base.StorageConfig{
 				Attrs:           spec.Attributes,
 				Dir:             spec.Path,
@@ -515,58 +570,24 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 				UseFileRegistry: spec.UseFileRegistry,
 				ExtraOptions:    spec.ExtraOptions,
 			}
-			if cfg.StorageEngine == enginepb.EngineTypePebble {
-				// TODO(itsbilal): Tune these options, and allow them to be overridden
-				// in the spec (similar to the existing spec.RocksDBOptions and others).
-				pebbleConfig := engine.PebbleConfig{
-					StorageConfig: storageConfig,
-					Opts:          engine.DefaultPebbleOptions(),
-				}
-				pebbleConfig.Opts.Cache = pebbleCache
-				pebbleConfig.Opts.MaxOpenFiles = int(openFileLimitPerStore)
-				eng, err = engine.NewPebble(ctx, pebbleConfig)
-			} else if cfg.StorageEngine == enginepb.EngineTypeRocksDB {
-				rocksDBConfig := engine.RocksDBConfig{
-					StorageConfig:           storageConfig,
-					MaxOpenFiles:            openFileLimitPerStore,
-					WarnLargeBatchThreshold: 500 * time.Millisecond,
-					RocksDBOptions:          spec.RocksDBOptions,
-				}
-
-				eng, err = engine.NewRocksDB(rocksDBConfig, cache)
-			} else {
-				// cfg.StorageEngine == enginepb.EngineTypeTeePebbleRocksDB
-				pebbleConfig := engine.PebbleConfig{
-					StorageConfig: storageConfig,
-					Opts:          engine.DefaultPebbleOptions(),
-				}
-				pebbleConfig.Dir = filepath.Join(pebbleConfig.Dir, "pebble")
-				pebbleConfig.Opts.Cache = pebbleCache
-				pebbleConfig.Opts.MaxOpenFiles = int(openFileLimitPerStore)
-				pebbleEng, err := engine.NewPebble(ctx, pebbleConfig)
-				if err != nil {
-					return nil, err
-				}
-
-				rocksDBConfig := engine.RocksDBConfig{
-					StorageConfig:           storageConfig,
-					MaxOpenFiles:            openFileLimitPerStore,
-					WarnLargeBatchThreshold: 500 * time.Millisecond,
-					RocksDBOptions:          spec.RocksDBOptions,
-				}
-				rocksDBConfig.Dir = filepath.Join(rocksDBConfig.Dir, "rocksdb")
-
-				rocksdbEng, err := engine.NewRocksDB(rocksDBConfig, cache)
-				if err != nil {
-					return nil, err
-				}
-
-				eng = engine.NewTee(ctx, rocksdbEng, pebbleEng)
-			}
+			eng, err := newEngine(storageConfig)
 			if err != nil {
 				return Engines{}, err
 			}
 			engines = append(engines, eng)
+
+			if spec.RaftLogPath != "" {
+				details = append(details, fmt.Sprintf("store %d: Raft log at %s", i, spec.RaftLogPath))
+				raftStorageConfig := storageConfig
+				raftStorageConfig.Dir = spec.RaftLogPath
+				raftStorageConfig.MaxSize = 0
+				raftEng, err := newEngine(raftStorageConfig)
+				if err != nil {
+					return Engines{}, err
+				}
+				raftEngines = append(raftEngines, raftEng)
+				raftEngineFor[eng] = raftEng
+			}
 		}
 	}
 
@@ -575,8 +596,10 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 	for _, s := range details {
 		log.Info(ctx, s)
 	}
+	cfg.raftEngines = raftEngineFor
 	enginesCopy := engines
 	engines = nil
+	raftEngines = nil
 	return enginesCopy, nil
 }
 
@@ -163,10 +163,23 @@ type Node struct {
 	lastUp      int64
 	initialBoot bool // True if this is the first time this node has started.
 	txnMetrics  kv.TxnMetrics
+	// Separate engines holding the Raft log of the stores, keyed by the
+	// stores' engines.
+	raftEngines map[engine.Engine]engine.Engine
 
 	perReplicaServer storage.Server
 }
 
+// newStore creates a store using the given engine and, if the store keeps its
+// Raft log separately, the engine holding it.
+func (n *Node) newStore(ctx context.Context, eng engine.Engine) *storage.Store {
+	raftEng, ok := n.raftEngines[eng]
+	if !ok {
+		raftEng = eng
+	}
+	return storage.NewStoreWithRaftEngine(ctx, n.storeCfg, eng, raftEng, &n.Descriptor)
+}
+
 // allocateNodeID increments the node id generator key to allocate
 // a new, unique node id.
 func allocateNodeID(ctx context.Context, db *client.DB) (roachpb.NodeID, error) {
@@ -419,7 +432,7 @@ func (n *Node) start(
 
 	// Create stores from the engines that were already bootstrapped.
 	for _, e := range initializedEngines {
-		s := storage.NewStore(ctx, n.storeCfg, e, &n.Descriptor)
+		s := n.newStore(ctx, e)
 		if err := s.Start(ctx, n.stopper); err != nil {
 			return errors.Errorf("failed to start store: %s", err)
 		}
@@ -617,7 +630,7 @@ func (n *Node) bootstrapStores(
 				return err
 			}
 
-			s := storage.NewStore(ctx, n.storeCfg, eng, &n.Descriptor)
+			s := n.newStore(ctx, eng)
 			if err := s.Start(ctx, stopper); err != nil {
 				return err
 			}
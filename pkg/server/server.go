@@ -57,6 +57,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmthints"
 	"github.com/cockroachdb/cockroach/pkg/sqlmigrations"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/bulk"
@@ -518,6 +520,15 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 				}
 			},
 			Dialer: s.nodeDialer.CTDialer(),
+			GlobalReads: func(closed, globalReadsClosed hlc.Timestamp) []roachpb.RangeID {
+				var rangeIDs []roachpb.RangeID
+				_ = s.node.stores.VisitStores(func(store *storage.Store) error {
+					rangeIDs = append(rangeIDs, store.GlobalReadsRanges(closed, globalReadsClosed)...)
+					return nil
+				})
+				return rangeIDs
+			},
+			MaxOffset: s.clock.MaxOffset(),
 		}),
 
 		EnableEpochRangeLeases: true,
@@ -619,6 +630,9 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		ExternalStorage:        externalStorage,
 		ExternalStorageFromURI: externalStorageFromURI,
 	}
+	if !s.cfg.TempStorageConfig.InMemory {
+		distSQLCfg.TempStoragePath = s.cfg.TempStorageConfig.Path
+	}
 	if distSQLTestingKnobs := s.cfg.TestingKnobs.DistSQL; distSQLTestingKnobs != nil {
 		distSQLCfg.TestingKnobs = *distSQLTestingKnobs.(*execinfra.TestingKnobs)
 	}
@@ -735,6 +749,9 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		),
 
 		QueryCache: querycache.New(s.cfg.SQLQueryCacheSize),
+
+		StmtDiagnosticsRecorder: stmtdiagnostics.NewRegistry(internalExecutor, s.db, s.st),
+		StmtHintsRegistry:       stmthints.NewRegistry(internalExecutor, s.db, s.st),
 	}
 
 	if sqlSchemaChangerTestingKnobs := s.cfg.TestingKnobs.SQLSchemaChanger; sqlSchemaChangerTestingKnobs != nil {
@@ -1313,6 +1330,10 @@ func (s *Server) Start(ctx context.Context) error {
 		return errors.Wrap(err, "failed to create engines")
 	}
 	s.stopper.AddCloser(&s.engines)
+	for _, raftEng := range s.cfg.raftEngines {
+		s.stopper.AddCloser(raftEng)
+	}
+	s.node.raftEngines = s.cfg.raftEngines
 
 	s.node.startAssertEngineHealth(ctx, s.engines)
 
@@ -1602,6 +1623,12 @@ func (s *Server) Start(ctx context.Context) error {
 		return err
 	}
 
+	// Start the background thread for polling statement diagnostics requests.
+	s.execCfg.StmtDiagnosticsRecorder.Start(ctx, s.stopper)
+
+	// Start the background thread for polling the statement hints.
+	s.execCfg.StmtHintsRegistry.Start(ctx, s.stopper)
+
 	// Before serving SQL requests, we have to make sure the database is
 	// in an acceptable form for this version of the software.
 	// We have to do this after actually starting up the server to be able to
@@ -1668,6 +1695,14 @@ func (s *Server) Start(ctx context.Context) error {
 	}
 
 	s.mux.Handle(adminPrefix, authHandler)
+	// Statement bundles are read on behalf of the logged-in user, so a web
+	// session is required in secure clusters even if it isn't required for the
+	// rest of the admin UI.
+	var stmtBundleHandler http.Handler = http.HandlerFunc(s.admin.handleStmtBundle)
+	if !s.cfg.Insecure {
+		stmtBundleHandler = newAuthenticationMux(s.authentication, stmtBundleHandler)
+	}
+	s.mux.Handle(stmtBundlePrefix, stmtBundleHandler)
 	// Exempt the health check endpoint from authentication.
 	s.mux.Handle("/_admin/v1/health", gwMux)
 	s.mux.Handle(ts.URLPrefix, authHandler)
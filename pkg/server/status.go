@@ -1413,6 +1413,105 @@ func (s *statusServer) localHotRanges(ctx context.Context) serverpb.HotRangesRes
 	return resp
 }
 
+// HotKeys returns the hottest ranges on each store on the requested node(s)
+// along with their most frequently accessed keys.
+func (s *statusServer) HotKeys(
+	ctx context.Context, req *serverpb.HotKeysRequest,
+) (*serverpb.HotKeysResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	response := &serverpb.HotKeysResponse{
+		NodeID:          s.gossip.NodeID.Get(),
+		HotKeysByNodeID: make(map[roachpb.NodeID]serverpb.HotKeysResponse_NodeResponse),
+	}
+
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+
+		// Only hot keys from the local node.
+		if local {
+			response.HotKeysByNodeID[requestedNodeID] = s.localHotKeys(ctx)
+			return response, nil
+		}
+
+		// Only hot keys from one non-local node.
+		status, err := s.dialNode(ctx, requestedNodeID)
+		if err != nil {
+			return nil, err
+		}
+		return status.HotKeys(ctx, req)
+	}
+
+	// Hot keys from all nodes.
+	dialFn := func(ctx context.Context, nodeID roachpb.NodeID) (interface{}, error) {
+		client, err := s.dialNode(ctx, nodeID)
+		return client, err
+	}
+	remoteRequest := serverpb.HotKeysRequest{NodeID: "local"}
+	nodeFn := func(ctx context.Context, client interface{}, _ roachpb.NodeID) (interface{}, error) {
+		status := client.(serverpb.StatusClient)
+		return status.HotKeys(ctx, &remoteRequest)
+	}
+	responseFn := func(nodeID roachpb.NodeID, resp interface{}) {
+		hotKeysResp := resp.(*serverpb.HotKeysResponse)
+		response.HotKeysByNodeID[nodeID] = hotKeysResp.HotKeysByNodeID[nodeID]
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		response.HotKeysByNodeID[nodeID] = serverpb.HotKeysResponse_NodeResponse{
+			ErrorMessage: err.Error(),
+		}
+	}
+
+	if err := s.iterateNodes(ctx, "hot keys", dialFn, nodeFn, responseFn, errorFn); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *statusServer) localHotKeys(ctx context.Context) serverpb.HotKeysResponse_NodeResponse {
+	var resp serverpb.HotKeysResponse_NodeResponse
+	// Unlike hot ranges, hot keys are of no use without the keys themselves,
+	// so they are omitted entirely when raw keys can't be returned.
+	includeRawKeys := debug.GatewayRemoteAllowed(ctx, s.st)
+	err := s.stores.VisitStores(func(store *storage.Store) error {
+		ranges := store.HottestReplicas()
+		storeResp := &serverpb.HotKeysResponse_StoreResponse{
+			StoreID:   store.StoreID(),
+			HotRanges: make([]serverpb.HotKeysResponse_HotRange, len(ranges)),
+		}
+		for i, r := range ranges {
+			hr := &storeResp.HotRanges[i]
+			hr.Desc = *r.Desc
+			hr.QueriesPerSecond = r.QPS
+			if !includeRawKeys {
+				hr.Desc.StartKey = nil
+				hr.Desc.EndKey = nil
+				continue
+			}
+			hr.LoadSplitKey = r.LoadSplitKey
+			hr.HotKeys = make([]serverpb.HotKeysResponse_HotKey, len(r.HotKeys))
+			for j, k := range r.HotKeys {
+				hr.HotKeys[j] = serverpb.HotKeysResponse_HotKey{
+					Key:              k.Key,
+					QueriesPerSecond: k.QPS,
+					Fraction:         k.Fraction,
+				}
+			}
+		}
+		resp.Stores = append(resp.Stores, storeResp)
+		return nil
+	})
+	if err != nil {
+		return serverpb.HotKeysResponse_NodeResponse{ErrorMessage: err.Error()}
+	}
+	return resp
+}
+
 // Range returns rangeInfos for all nodes in the cluster about a specific
 // range. It also returns the range history for that range as well.
 func (s *statusServer) Range(
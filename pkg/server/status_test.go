@@ -879,6 +879,53 @@ func TestHotRangesResponse(t *testing.T) {
 	}
 }
 
+func TestHotKeysResponse(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ts := startServer(t)
+	defer ts.Stopper().Stop(context.TODO())
+
+	var hotKeysResp serverpb.HotKeysResponse
+	if err := getStatusJSONProto(ts, "hotkeys", &hotKeysResp); err != nil {
+		t.Fatal(err)
+	}
+	if len(hotKeysResp.HotKeysByNodeID) == 0 {
+		t.Fatalf("didn't get hot key responses from any nodes")
+	}
+
+	for nodeID, nodeResp := range hotKeysResp.HotKeysByNodeID {
+		if len(nodeResp.Stores) == 0 {
+			t.Errorf("didn't get any stores in hot key response from n%d: %v",
+				nodeID, nodeResp.ErrorMessage)
+		}
+		for _, storeResp := range nodeResp.Stores {
+			// Only the first store will actually have any ranges on it.
+			if storeResp.StoreID != roachpb.StoreID(1) {
+				continue
+			}
+			if len(storeResp.HotRanges) == 0 {
+				t.Errorf("didn't get any hot ranges in response from n%d,s%d: %v",
+					nodeID, storeResp.StoreID, nodeResp.ErrorMessage)
+			}
+			for _, r := range storeResp.HotRanges {
+				if r.Desc.RangeID == 0 || (len(r.Desc.StartKey) == 0 && len(r.Desc.EndKey) == 0) {
+					t.Errorf("unexpected empty/unpopulated range descriptor: %+v", r.Desc)
+				}
+				lastQPS := math.MaxFloat64
+				for _, k := range r.HotKeys {
+					if !r.Desc.ContainsKey(roachpb.RKey(k.Key)) {
+						t.Errorf("hot key %s outside of range %v", k.Key, r.Desc)
+					}
+					if k.QueriesPerSecond > lastQPS {
+						t.Errorf("unexpected increase in qps between keys; prev=%.2f, current=%.2f, key=%s",
+							lastQPS, k.QueriesPerSecond, k.Key)
+					}
+					lastQPS = k.QueriesPerSecond
+				}
+			}
+		}
+	}
+}
+
 func TestRangesResponse(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer storage.EnableLeaseHistory(100)()
@@ -48,6 +48,8 @@ const (
 	VersionStart20_1
 	VersionContainsEstimatesCounter
 	VersionChangeReplicasDemotion
+	VersionStatementDiagnosticsSystemTables
+	VersionStatementHintsSystemTable
 
 	// Add new versions here (step one of two).
 
@@ -320,6 +322,18 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     VersionChangeReplicasDemotion,
 		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 3},
 	},
+	{
+		// VersionStatementDiagnosticsSystemTables introduces the system tables
+		// for storing statement diagnostics bundles and the requests for them.
+		Key:     VersionStatementDiagnosticsSystemTables,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 4},
+	},
+	{
+		// VersionStatementHintsSystemTable introduces the system table storing
+		// the plan hints attached to statement fingerprints.
+		Key:     VersionStatementHintsSystemTable,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 5},
+	},
 	// Add new versions here (step two of two).
 
 })
@@ -245,6 +245,9 @@ func (a *applyJoinNode) Next(params runParams) (bool, error) {
 		// anti).
 
 		a.optimizer.Init(params.p.EvalContext())
+		// Re-planning the right side must honor the NO_RULE hints of the
+		// statement, like the planning of the statement itself.
+		disableHintedRules(&a.optimizer, params.p.optPlanningCtx.hints)
 
 		bindings := make(map[opt.ColumnID]tree.Datum, a.leftBoundColMap.Len())
 		a.leftBoundColMap.ForEach(func(k, v int) {
@@ -1144,7 +1144,7 @@ func (sc *SchemaChanger) validateInvertedIndexes(
 				return err
 			}
 			expectedCount[i] = int64(tree.MustBeDInt(row[0]))
-			log.Infof(ctx, "JSON column %s/%s expected inverted index count = %d, took %s",
+			log.Infof(ctx, "inverted column %s/%s expected inverted index count = %d, took %s",
 				tableDesc.Name, col, expectedCount[i], timeutil.Since(start))
 			return nil
 		})
@@ -16,9 +16,11 @@ import (
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -235,6 +237,22 @@ func decodeTableKeyToCol(
 			rkey, t, err = encoding.DecodeTimeDescending(key)
 		}
 		vec.Timestamp()[idx] = t
+	case types.TimestampTZFamily:
+		var t time.Time
+		if dir == sqlbase.IndexDescriptor_ASC {
+			rkey, t, err = encoding.DecodeTimeAscending(key)
+		} else {
+			rkey, t, err = encoding.DecodeTimeDescending(key)
+		}
+		vec.TimestampTZ()[idx] = t
+	case types.IntervalFamily:
+		var d duration.Duration
+		if dir == sqlbase.IndexDescriptor_ASC {
+			rkey, d, err = encoding.DecodeDurationAscending(key)
+		} else {
+			rkey, d, err = encoding.DecodeDurationDescending(key)
+		}
+		vec.Interval()[idx] = d
 	default:
 		return rkey, errors.AssertionFailedf("unsupported type %+v", log.Safe(valType))
 	}
@@ -282,6 +300,18 @@ func skipTableKey(
 		} else {
 			rkey, _, err = encoding.DecodeDecimalDescending(key, nil)
 		}
+	case types.TimestampFamily, types.TimestampTZFamily:
+		if dir == sqlbase.IndexDescriptor_ASC {
+			rkey, _, err = encoding.DecodeTimeAscending(key)
+		} else {
+			rkey, _, err = encoding.DecodeTimeDescending(key)
+		}
+	case types.IntervalFamily:
+		if dir == sqlbase.IndexDescriptor_ASC {
+			rkey, _, err = encoding.DecodeDurationAscending(key)
+		} else {
+			rkey, _, err = encoding.DecodeDurationDescending(key)
+		}
 	default:
 		return key, errors.AssertionFailedf("unsupported type %+v", log.Safe(valType))
 	}
@@ -294,10 +324,11 @@ func skipTableKey(
 // UnmarshalColumnValueToCol decodes the value from a roachpb.Value using the
 // type expected by the column, writing into the input Vec at the given row
 // idx. An error is returned if the value's type does
-// not match the column's type.
+// not match the column's type. da is used to allocate the datums of the types
+// that are stored as coltypes.Datum.
 // See the analog, UnmarshalColumnValue, in sqlbase/column_type_encoding.go
 func UnmarshalColumnValueToCol(
-	vec coldata.Vec, idx uint16, typ *types.T, value roachpb.Value,
+	da *sqlbase.DatumAlloc, vec coldata.Vec, idx uint16, typ *types.T, value roachpb.Value,
 ) error {
 	if value.RawBytes == nil {
 		vec.Nulls().SetNull(idx)
@@ -340,6 +371,18 @@ func UnmarshalColumnValueToCol(
 		var v time.Time
 		v, err = value.GetTime()
 		vec.Timestamp()[idx] = v
+	case types.TimestampTZFamily:
+		var v time.Time
+		v, err = value.GetTime()
+		vec.TimestampTZ()[idx] = v
+	case types.IntervalFamily:
+		var v duration.Duration
+		v, err = value.GetDuration()
+		vec.Interval()[idx] = v
+	case types.JsonFamily, types.ArrayFamily:
+		var v tree.Datum
+		v, err = sqlbase.UnmarshalColumnValue(da, typ, value)
+		vec.Datum()[idx] = v
 	default:
 		return errors.AssertionFailedf("unsupported column type: %s", log.Safe(typ.Family()))
 	}
@@ -0,0 +1,817 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// bufferedWindowOpState represents the state of the bufferedWindowOp.
+type bufferedWindowOpState int
+
+const (
+	// bufferedWindowBuffering is the initial state of the bufferedWindowOp, in
+	// which it buffers the tuples of the current partition.
+	bufferedWindowBuffering bufferedWindowOpState = iota
+	// bufferedWindowEmitting is the state in which the bufferedWindowOp emits
+	// the tuples of the current partition along with the results of the window
+	// function.
+	bufferedWindowEmitting
+	// bufferedWindowFinished is the state in which the bufferedWindowOp has
+	// emitted all of its input.
+	bufferedWindowFinished
+)
+
+// NewBufferedWindowOperator returns an Operator that computes the window
+// function wf over its input, which must be ordered by the PARTITION BY and
+// the ORDER BY clauses of wf. If the input has a PARTITION BY clause,
+// partitionColIdx is the index of the boolean column that is true for the
+// first tuple of every partition, and -1 otherwise. The result of the window
+// function is put in the outputColIdx'th column, which is appended to the
+// batches.
+//
+// The partitions are buffered in memory by allocator. If its memory limit is
+// reached, the operator can be replaced by one that buffers the partitions in
+// the temporary storage (see newDiskBackedWindowOperator). unlimitedAllocator
+// is used for the output batches.
+func NewBufferedWindowOperator(
+	allocator *Allocator,
+	unlimitedAllocator *Allocator,
+	evalCtx *tree.EvalContext,
+	input Operator,
+	inputTypes []types.T,
+	wf *execinfrapb.WindowerSpec_WindowFn,
+	partitionColIdx int,
+	outputColIdx int,
+) (Operator, error) {
+	bufferTypes, err := windowBufferTypes(inputTypes, partitionColIdx)
+	if err != nil {
+		return nil, err
+	}
+	return newBufferedWindowOp(
+		unlimitedAllocator, evalCtx, input, inputTypes, wf, partitionColIdx, outputColIdx,
+		&inMemoryWindowPartition{allocator: allocator, typs: bufferTypes},
+	)
+}
+
+// newDiskBackedWindowOperator returns an Operator that computes the window
+// function wf like the one returned by NewBufferedWindowOperator, except that
+// every partition is buffered in a file of tempDir, which is accounted for by
+// diskAcc.
+func newDiskBackedWindowOperator(
+	unlimitedAllocator *Allocator,
+	evalCtx *tree.EvalContext,
+	input Operator,
+	inputTypes []types.T,
+	wf *execinfrapb.WindowerSpec_WindowFn,
+	partitionColIdx int,
+	outputColIdx int,
+	tempDir string,
+	diskAcc *mon.BoundAccount,
+) (Operator, error) {
+	bufferTypes, err := windowBufferTypes(inputTypes, partitionColIdx)
+	if err != nil {
+		return nil, err
+	}
+	return newBufferedWindowOp(
+		unlimitedAllocator, evalCtx, input, inputTypes, wf, partitionColIdx, outputColIdx,
+		&diskWindowPartition{
+			allocator: unlimitedAllocator,
+			typs:      bufferTypes,
+			tempDir:   tempDir,
+			diskAcc:   diskAcc,
+		},
+	)
+}
+
+// windowBufferTypes returns the physical types of the tuples that are buffered
+// by a bufferedWindowOp, which include the partition column if there is one.
+func windowBufferTypes(inputTypes []types.T, partitionColIdx int) ([]coltypes.T, error) {
+	bufferTypes, err := typeconv.FromColumnTypes(inputTypes)
+	if err != nil {
+		return nil, err
+	}
+	if partitionColIdx != -1 {
+		bufferTypes = append(bufferTypes, coltypes.Bool)
+	}
+	return bufferTypes, nil
+}
+
+func newBufferedWindowOp(
+	allocator *Allocator,
+	evalCtx *tree.EvalContext,
+	input Operator,
+	inputTypes []types.T,
+	wf *execinfrapb.WindowerSpec_WindowFn,
+	partitionColIdx int,
+	outputColIdx int,
+	partition windowPartitionStorage,
+) (*bufferedWindowOp, error) {
+	argTypes := make([]types.T, len(wf.ArgsIdxs))
+	for i, argIdx := range wf.ArgsIdxs {
+		argTypes[i] = inputTypes[argIdx]
+	}
+	windowConstructor, outputType, err := execinfrapb.GetWindowFunctionInfo(wf.Func, argTypes...)
+	if err != nil {
+		return nil, err
+	}
+	outputPhysType := typeconv.FromColumnType(outputType)
+	if outputPhysType == coltypes.Unhandled {
+		return nil, errors.Errorf(
+			"window function %s with output type %s is not supported", wf.Func.String(), outputType,
+		)
+	}
+	bufferTypes := partition.types()
+	if outputColIdx != len(bufferTypes) {
+		return nil, errors.AssertionFailedf(
+			"unexpected output column index %d for a window function over %d columns",
+			outputColIdx, len(bufferTypes),
+		)
+	}
+
+	o := &bufferedWindowOp{
+		OneInputNode:    NewOneInputNode(input),
+		allocator:       allocator,
+		evalCtx:         evalCtx,
+		bufferTypes:     bufferTypes,
+		windowFn:        windowConstructor(evalCtx),
+		ordering:        wf.Ordering,
+		partitionColIdx: partitionColIdx,
+		outputColIdx:    outputColIdx,
+		outputPhysType:  outputPhysType,
+		converter:       typeconv.GetDatumToPhysicalFn(outputType),
+		partition:       partition,
+		rows: windowPartitionRows{
+			partition:  partition,
+			inputTypes: inputTypes,
+		},
+		frameRun: tree.WindowFrameRun{
+			ArgsIdxs:     wf.ArgsIdxs,
+			FilterColIdx: int(wf.FilterColIdx),
+		},
+	}
+	if wf.Frame != nil {
+		if err := wf.Frame.InitWindowFrameRun(
+			&o.frameRun, wf.Ordering, inputTypes, &o.da,
+		); err != nil {
+			return nil, err
+		}
+	}
+	if !o.frameRun.IsDefaultFrame() {
+		// With a custom frame, an aggregate function has to be computed from
+		// scratch for every row, rather than only being updated with the rows
+		// that have entered the frame.
+		builtins.ShouldReset(o.windowFn)
+	}
+	return o, nil
+}
+
+// bufferedWindowOp is an Operator that computes a window function that needs
+// the whole partition of a tuple, such as lag, nth_value or an aggregate over
+// a sliding frame. The tuples of every partition are buffered in columnar form
+// by a windowPartitionStorage, either in memory or in the temporary storage,
+// and the row-by-row implementation of the window function, which supports all
+// of the frame modes, bounds and exclusions, accesses them as datums. The
+// tuples of the partition are then emitted with the results of the window
+// function appended to them.
+//
+// The in-memory operator implements bufferingInMemoryOperator, so that the
+// partition being buffered when the memory limit is reached, along with the
+// rest of the input, can be handed over to a disk-backed operator.
+type bufferedWindowOp struct {
+	OneInputNode
+
+	// allocator is used for the output batches.
+	allocator *Allocator
+	evalCtx   *tree.EvalContext
+	// bufferTypes are the physical types of the columns of the input batches,
+	// including the partition column if there is one.
+	bufferTypes     []coltypes.T
+	windowFn        tree.WindowFunc
+	frameRun        tree.WindowFrameRun
+	ordering        execinfrapb.Ordering
+	partitionColIdx int
+	outputColIdx    int
+	outputPhysType  coltypes.T
+	converter       func(tree.Datum) (interface{}, error)
+
+	// partition contains the tuples of the current partition.
+	partition windowPartitionStorage
+	// rows gives the window function access to the tuples of partition.
+	rows windowPartitionRows
+	// emitCache is used to read the tuples of partition that are emitted.
+	emitCache partitionBatchCache
+
+	state bufferedWindowOpState
+	// batch is the input batch whose tuples are being buffered, and batchIdx
+	// is the index (into the selection vector, if there is one) of its first
+	// tuple that hasn't been buffered yet.
+	batch    coldata.Batch
+	batchIdx uint16
+	// emitted is the number of tuples of the current partition that have been
+	// emitted.
+	emitted int
+	output  coldata.Batch
+	closed  bool
+
+	da sqlbase.DatumAlloc
+}
+
+var _ Operator = &bufferedWindowOp{}
+var _ bufferingInMemoryOperator = &bufferedWindowOp{}
+var _ Closer = &bufferedWindowOp{}
+
+func (o *bufferedWindowOp) Init() {
+	o.input.Init()
+	outputTypes := make([]coltypes.T, len(o.bufferTypes), len(o.bufferTypes)+1)
+	copy(outputTypes, o.bufferTypes)
+	o.output = o.allocator.NewMemBatch(append(outputTypes, o.outputPhysType))
+}
+
+func (o *bufferedWindowOp) Next(ctx context.Context) coldata.Batch {
+	for {
+		switch o.state {
+		case bufferedWindowBuffering:
+			if o.batch == nil || o.batchIdx == o.batch.Length() {
+				o.batch, o.batchIdx = o.input.Next(ctx), 0
+				if o.batch.Length() == 0 {
+					// The input has been exhausted, so the last partition is
+					// complete.
+					if o.partition.numTuples() == 0 {
+						o.state = bufferedWindowFinished
+					} else {
+						o.startPartition(ctx)
+					}
+					continue
+				}
+			}
+			endIdx, partitionDone := o.findPartitionEnd()
+			for o.batchIdx < endIdx {
+				o.batchIdx += o.partition.append(ctx, o.batch, o.batchIdx, endIdx)
+			}
+			if partitionDone {
+				o.startPartition(ctx)
+			}
+		case bufferedWindowEmitting:
+			if batch := o.emit(ctx); batch.Length() > 0 {
+				return batch
+			}
+			o.resetPartition(ctx)
+			if o.batch.Length() == 0 {
+				o.state = bufferedWindowFinished
+			} else {
+				o.state = bufferedWindowBuffering
+			}
+		case bufferedWindowFinished:
+			return zeroBatch
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unexpected bufferedWindowOpState %d", o.state))
+			// This code is unreachable, but the compiler cannot infer that.
+			return nil
+		}
+	}
+}
+
+// findPartitionEnd returns the index (into the selection vector, if there is
+// one) of the first tuple of o.batch, starting from o.batchIdx, that doesn't
+// belong to the current partition, along with whether such a tuple was found.
+func (o *bufferedWindowOp) findPartitionEnd() (uint16, bool) {
+	n := o.batch.Length()
+	if o.partitionColIdx == -1 {
+		return n, false
+	}
+	sel := o.batch.Selection()
+	partitionCol := o.batch.ColVec(o.partitionColIdx).Bool()
+	for i := o.batchIdx; i < n; i++ {
+		rowIdx := i
+		if sel != nil {
+			rowIdx = sel[i]
+		}
+		// The first tuple of the current partition also has the partition
+		// column set, so it only marks a new partition once some tuples have
+		// been buffered.
+		if partitionCol[rowIdx] && (o.partition.numTuples() > 0 || i > o.batchIdx) {
+			return i, true
+		}
+	}
+	return n, false
+}
+
+// startPartition prepares the computation of the window function over the
+// buffered tuples of the current partition, which is complete.
+func (o *bufferedWindowOp) startPartition(ctx context.Context) {
+	o.partition.finish(ctx)
+	o.windowFn.Reset(ctx)
+	var peerGrouper tree.PeerGroupChecker = allPeers{}
+	if len(o.ordering.Columns) > 0 {
+		peerGrouper = &windowPeerGrouper{
+			ctx:      ctx,
+			evalCtx:  o.evalCtx,
+			rows:     &o.rows,
+			ordering: o.ordering,
+			scratch:  make(tree.Datums, len(o.ordering.Columns)),
+		}
+	}
+	frameRun := &o.frameRun
+	frameRun.Rows = &o.rows
+	frameRun.RowIdx = 0
+	frameRun.CurRowPeerGroupNum = 0
+	if err := frameRun.PeerHelper.Init(frameRun, peerGrouper); err != nil {
+		execerror.NonVectorizedPanic(err)
+	}
+	o.state = bufferedWindowEmitting
+}
+
+// emit returns the next batch of the tuples of the current partition, along
+// with the results of the window function. A zero-length batch is returned
+// once all of them have been emitted.
+func (o *bufferedWindowOp) emit(ctx context.Context) coldata.Batch {
+	o.output.ResetInternalBatch()
+	n := o.partition.numTuples() - o.emitted
+	if n > int(coldata.BatchSize()) {
+		n = int(coldata.BatchSize())
+	}
+	if n == 0 {
+		o.output.SetLength(0)
+		return o.output
+	}
+	o.allocator.performOperation(
+		o.output.ColVecs(),
+		func() {
+			for i := 0; i < n; {
+				b, offset := o.partition.getBatch(o.emitted+i, &o.emitCache)
+				startIdx := o.emitted + i - offset
+				endIdx := int(b.Length())
+				if endIdx-startIdx > n-i {
+					endIdx = startIdx + n - i
+				}
+				for j, typ := range o.bufferTypes {
+					o.output.ColVec(j).Append(
+						coldata.SliceArgs{
+							ColType:     typ,
+							Src:         b.ColVec(j),
+							DestIdx:     uint64(i),
+							SrcStartIdx: uint64(startIdx),
+							SrcEndIdx:   uint64(endIdx),
+						},
+					)
+				}
+				i += endIdx - startIdx
+			}
+
+			outputVec := o.output.ColVec(o.outputColIdx)
+			for i := 0; i < n; i++ {
+				res := o.computeNext(ctx)
+				if res == tree.DNull {
+					outputVec.Nulls().SetNull(uint16(i))
+					continue
+				}
+				converted, err := o.converter(res)
+				if err != nil {
+					execerror.VectorizedInternalPanic(err)
+				}
+				coldata.SetValueAt(outputVec, converted, uint16(i), o.outputPhysType)
+			}
+		},
+	)
+	o.output.SetLength(uint16(n))
+	o.emitted += n
+	return o.output
+}
+
+// computeNext computes the window function for the next tuple of the current
+// partition.
+func (o *bufferedWindowOp) computeNext(ctx context.Context) tree.Datum {
+	frameRun := &o.frameRun
+	res, err := o.windowFn.Compute(ctx, o.evalCtx, frameRun)
+	if err != nil {
+		execerror.NonVectorizedPanic(err)
+	}
+	frameRun.RowIdx++
+	peerGroupEndIdx := frameRun.PeerHelper.GetFirstPeerIdx(frameRun.CurRowPeerGroupNum) +
+		frameRun.PeerHelper.GetRowCount(frameRun.CurRowPeerGroupNum)
+	if frameRun.RowIdx == peerGroupEndIdx {
+		if err := frameRun.PeerHelper.Update(frameRun); err != nil {
+			execerror.NonVectorizedPanic(err)
+		}
+		frameRun.CurRowPeerGroupNum++
+	}
+	return res
+}
+
+// resetPartition releases the tuples of the current partition so that the
+// next partition can be buffered.
+func (o *bufferedWindowOp) resetPartition(ctx context.Context) {
+	o.partition.reset(ctx)
+	o.rows.cache.invalidate()
+	o.emitCache.invalidate()
+	o.emitted = 0
+}
+
+// buffering implements the bufferingInMemoryOperator interface.
+func (o *bufferedWindowOp) buffering() bool {
+	return o.state == bufferedWindowBuffering
+}
+
+// exportBuffered implements the bufferingInMemoryOperator interface. The
+// tuples of the current partition that have been buffered are exported first,
+// followed by the tuples of the input batch that haven't been buffered yet.
+func (o *bufferedWindowOp) exportBuffered(Operator) coldata.Batch {
+	if batch := o.partition.(*inMemoryWindowPartition).exportBuffered(); batch.Length() > 0 {
+		return batch
+	}
+	if o.batch == nil || o.batchIdx == o.batch.Length() {
+		return zeroBatch
+	}
+	batch := o.batch
+	o.batch = nil
+	// Only the tuples starting from batchIdx are exported.
+	n := batch.Length()
+	if sel := batch.Selection(); sel != nil {
+		copy(sel, sel[o.batchIdx:n])
+	} else {
+		batch.SetSelection(true)
+		sel = batch.Selection()
+		for i := range sel[:n-o.batchIdx] {
+			sel[i] = o.batchIdx + uint16(i)
+		}
+	}
+	batch.SetLength(n - o.batchIdx)
+	return batch
+}
+
+// Close implements the Closer interface.
+func (o *bufferedWindowOp) Close(ctx context.Context) error {
+	if o.closed {
+		return nil
+	}
+	o.closed = true
+	o.windowFn.Close(ctx, o.evalCtx)
+	return o.partition.close(ctx)
+}
+
+// windowPartitionStorage stores the tuples of the current partition of a
+// bufferedWindowOp and gives random access to them.
+type windowPartitionStorage interface {
+	// types returns the physical types of the tuples.
+	types() []coltypes.T
+	// append adds the tuples of the batch at the indices [startIdx, endIdx)
+	// (into its selection vector, if there is one) to the partition. It returns
+	// the number of the tuples that were added, which can be less than
+	// endIdx-startIdx, in which case the rest of them must be appended by
+	// another call.
+	append(ctx context.Context, batch coldata.Batch, startIdx, endIdx uint16) uint16
+	// finish must be called once all the tuples of the partition have been
+	// appended, before any of them is accessed.
+	finish(ctx context.Context)
+	// numTuples returns the number of tuples of the partition.
+	numTuples() int
+	// getBatch returns a batch that contains the idx'th tuple of the partition,
+	// along with the index in the partition of the first tuple of the batch.
+	// The batch is read into cache if needed, and it stays valid until the next
+	// call with the same cache.
+	getBatch(idx int, cache *partitionBatchCache) (coldata.Batch, int)
+	// reset removes all the tuples so that another partition can be buffered.
+	reset(ctx context.Context)
+	// close releases the resources of the storage.
+	close(ctx context.Context) error
+}
+
+// partitionBatchCache holds the batch of a windowPartitionStorage that was
+// read last.
+type partitionBatchCache struct {
+	batch coldata.Batch
+	// offset is the index in the partition of the first tuple of batch.
+	offset int
+	valid  bool
+}
+
+// contains returns whether the cache holds the idx'th tuple of the partition.
+func (c *partitionBatchCache) contains(idx int) bool {
+	return c.valid && idx >= c.offset && idx < c.offset+int(c.batch.Length())
+}
+
+// invalidate marks the cache as empty, which must be done when the partition
+// changes.
+func (c *partitionBatchCache) invalidate() {
+	c.valid = false
+}
+
+// inMemoryWindowPartition is a windowPartitionStorage that stores the tuples
+// in memory. The batches are reused for the following partitions.
+type inMemoryWindowPartition struct {
+	allocator *Allocator
+	typs      []coltypes.T
+	// batches contain the tuples of the partition. All of them, except for the
+	// last one that is used, are full.
+	batches []coldata.Batch
+	n       int
+	// exported is the number of batches that have been exported.
+	exported int
+}
+
+var _ windowPartitionStorage = &inMemoryWindowPartition{}
+
+func (p *inMemoryWindowPartition) types() []coltypes.T {
+	return p.typs
+}
+
+func (p *inMemoryWindowPartition) append(
+	_ context.Context, batch coldata.Batch, startIdx, endIdx uint16,
+) uint16 {
+	batchSize := int(coldata.BatchSize())
+	destBatchIdx, destIdx := p.n/batchSize, p.n%batchSize
+	if destBatchIdx == len(p.batches) {
+		p.batches = append(p.batches, p.allocator.NewMemBatch(p.typs))
+	}
+	dest := p.batches[destBatchIdx]
+	toAppend := endIdx - startIdx
+	if room := uint16(batchSize - destIdx); toAppend > room {
+		toAppend = room
+	}
+	p.allocator.performOperation(
+		dest.ColVecs(),
+		func() {
+			for i, typ := range p.typs {
+				dest.ColVec(i).Append(
+					coldata.SliceArgs{
+						ColType:     typ,
+						Src:         batch.ColVec(i),
+						Sel:         batch.Selection(),
+						DestIdx:     uint64(destIdx),
+						SrcStartIdx: uint64(startIdx),
+						SrcEndIdx:   uint64(startIdx + toAppend),
+					},
+				)
+			}
+		},
+	)
+	// The tuples are only added once the memory has been accounted for, so
+	// that a memory error leaves the partition unchanged.
+	dest.SetLength(uint16(destIdx) + toAppend)
+	p.n += int(toAppend)
+	return toAppend
+}
+
+func (p *inMemoryWindowPartition) finish(context.Context) {}
+
+func (p *inMemoryWindowPartition) numTuples() int {
+	return p.n
+}
+
+func (p *inMemoryWindowPartition) getBatch(idx int, _ *partitionBatchCache) (coldata.Batch, int) {
+	batchSize := int(coldata.BatchSize())
+	return p.batches[idx/batchSize], idx - idx%batchSize
+}
+
+// exportBuffered returns the next batch of the tuples of the partition, or a
+// zero-length batch once all of them have been exported.
+func (p *inMemoryWindowPartition) exportBuffered() coldata.Batch {
+	if p.exported*int(coldata.BatchSize()) >= p.n {
+		return zeroBatch
+	}
+	batch := p.batches[p.exported]
+	p.exported++
+	return batch
+}
+
+func (p *inMemoryWindowPartition) reset(context.Context) {
+	// Appending to the batches only sets the nulls, so they have to be reset
+	// before they are reused.
+	batchSize := int(coldata.BatchSize())
+	for _, batch := range p.batches[:(p.n+batchSize-1)/batchSize] {
+		batch.ResetInternalBatch()
+	}
+	p.n = 0
+}
+
+func (p *inMemoryWindowPartition) close(context.Context) error {
+	p.batches = nil
+	return nil
+}
+
+// diskWindowPartition is a windowPartitionStorage that stores the tuples in a
+// diskQueue.
+type diskWindowPartition struct {
+	allocator *Allocator
+	typs      []coltypes.T
+	tempDir   string
+	diskAcc   *mon.BoundAccount
+
+	q *diskQueue
+	// ends contains, for every batch of q, the number of tuples of the
+	// partition up to the end of that batch.
+	ends []int
+	// sel is used to append the tuples of the batches without a selection
+	// vector.
+	sel []uint16
+}
+
+var _ windowPartitionStorage = &diskWindowPartition{}
+
+func (p *diskWindowPartition) types() []coltypes.T {
+	return p.typs
+}
+
+func (p *diskWindowPartition) append(
+	ctx context.Context, batch coldata.Batch, startIdx, endIdx uint16,
+) uint16 {
+	if p.q == nil {
+		p.q = newDiskQueue(p.allocator, p.typs, p.tempDir, p.diskAcc)
+	}
+	sel := batch.Selection()
+	if sel == nil {
+		if p.sel == nil {
+			p.sel = make([]uint16, coldata.BatchSize())
+			for i := range p.sel {
+				p.sel[i] = uint16(i)
+			}
+		}
+		sel = p.sel
+	}
+	if err := p.q.enqueueSel(ctx, batch, sel[startIdx:endIdx]); err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+	p.ends = append(p.ends, p.numTuples()+int(endIdx-startIdx))
+	return endIdx - startIdx
+}
+
+func (p *diskWindowPartition) finish(ctx context.Context) {
+	if err := p.q.finishEnqueueing(ctx); err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+}
+
+func (p *diskWindowPartition) numTuples() int {
+	if len(p.ends) == 0 {
+		return 0
+	}
+	return p.ends[len(p.ends)-1]
+}
+
+func (p *diskWindowPartition) getBatch(idx int, cache *partitionBatchCache) (coldata.Batch, int) {
+	if cache.contains(idx) {
+		return cache.batch, cache.offset
+	}
+	if cache.batch == nil {
+		// The batch is filled in by the deserializer, which replaces its columns
+		// with the data of the file, so it doesn't need any columns of its own.
+		cache.batch = coldata.NewMemBatchWithSize(nil /* types */, 0 /* size */)
+	}
+	i := sort.SearchInts(p.ends, idx+1)
+	if err := p.q.get(i, cache.batch); err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+	cache.offset, cache.valid = 0, true
+	if i > 0 {
+		cache.offset = p.ends[i-1]
+	}
+	return cache.batch, cache.offset
+}
+
+func (p *diskWindowPartition) reset(ctx context.Context) {
+	if err := p.close(ctx); err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+	p.ends = p.ends[:0]
+}
+
+func (p *diskWindowPartition) close(ctx context.Context) error {
+	if p.q == nil {
+		return nil
+	}
+	err := p.q.close(ctx)
+	p.q = nil
+	return err
+}
+
+// windowPartitionRows is a tree.IndexedRows that contains the tuples of the
+// current partition of a bufferedWindowOp.
+type windowPartitionRows struct {
+	partition  windowPartitionStorage
+	inputTypes []types.T
+	cache      partitionBatchCache
+	da         sqlbase.DatumAlloc
+}
+
+var _ tree.IndexedRows = &windowPartitionRows{}
+
+// Len implements the tree.IndexedRows interface.
+func (r *windowPartitionRows) Len() int {
+	return r.partition.numTuples()
+}
+
+// GetRow implements the tree.IndexedRows interface. The row is only valid until
+// the next call.
+func (r *windowPartitionRows) GetRow(_ context.Context, idx int) (tree.IndexedRow, error) {
+	batch, offset := r.partition.getBatch(idx, &r.cache)
+	return windowPartitionRow{rows: r, idx: idx, batch: batch, rowIdx: uint16(idx - offset)}, nil
+}
+
+// windowPartitionRow is a tree.IndexedRow of windowPartitionRows.
+type windowPartitionRow struct {
+	rows  *windowPartitionRows
+	idx   int
+	batch coldata.Batch
+	// rowIdx is the index of the tuple in batch.
+	rowIdx uint16
+}
+
+var _ tree.IndexedRow = windowPartitionRow{}
+
+// GetIdx implements the tree.IndexedRow interface.
+func (r windowPartitionRow) GetIdx() int {
+	return r.idx
+}
+
+// GetDatum implements the tree.IndexedRow interface.
+func (r windowPartitionRow) GetDatum(colIdx int) (tree.Datum, error) {
+	return PhysicalTypeColElemToDatum(
+		r.batch.ColVec(colIdx), r.rowIdx, r.rows.da, &r.rows.inputTypes[colIdx],
+	), nil
+}
+
+// GetDatums implements the tree.IndexedRow interface.
+func (r windowPartitionRow) GetDatums(startColIdx, endColIdx int) (tree.Datums, error) {
+	datums := make(tree.Datums, 0, endColIdx-startColIdx)
+	for colIdx := startColIdx; colIdx < endColIdx; colIdx++ {
+		d, err := r.GetDatum(colIdx)
+		if err != nil {
+			return nil, err
+		}
+		datums = append(datums, d)
+	}
+	return datums, nil
+}
+
+// windowPeerGrouper is a tree.PeerGroupChecker that considers two rows of a
+// partition to be peers if they are equal on all of the ordering columns.
+type windowPeerGrouper struct {
+	ctx      context.Context
+	evalCtx  *tree.EvalContext
+	rows     tree.IndexedRows
+	ordering execinfrapb.Ordering
+	// scratch contains the values of the ordering columns of the first row,
+	// since getting the second row might invalidate the first one.
+	scratch tree.Datums
+}
+
+var _ tree.PeerGroupChecker = &windowPeerGrouper{}
+
+// InSameGroup implements the tree.PeerGroupChecker interface.
+func (g *windowPeerGrouper) InSameGroup(i, j int) (bool, error) {
+	rowI, err := g.rows.GetRow(g.ctx, i)
+	if err != nil {
+		return false, err
+	}
+	for k, col := range g.ordering.Columns {
+		if g.scratch[k], err = rowI.GetDatum(int(col.ColIdx)); err != nil {
+			return false, err
+		}
+	}
+	rowJ, err := g.rows.GetRow(g.ctx, j)
+	if err != nil {
+		return false, err
+	}
+	for k, col := range g.ordering.Columns {
+		d, err := rowJ.GetDatum(int(col.ColIdx))
+		if err != nil {
+			return false, err
+		}
+		if g.scratch[k].Compare(g.evalCtx, d) != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// allPeers is a tree.PeerGroupChecker that considers all rows of a partition to
+// be peers, which is the case when there is no ORDER BY clause.
+type allPeers struct{}
+
+var _ tree.PeerGroupChecker = allPeers{}
+
+// InSameGroup implements the tree.PeerGroupChecker interface.
+func (allPeers) InSameGroup(i, j int) (bool, error) { return true, nil }
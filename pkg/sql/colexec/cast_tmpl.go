@@ -32,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	semtypes "github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/pkg/errors"
 )
 
@@ -44,6 +45,7 @@ type _GOTYPE interface{}
 type _FROMTYPE interface{}
 
 var _ apd.Decimal
+var _ duration.Duration
 var _ = math.MaxInt8
 var _ tree.Datum
 
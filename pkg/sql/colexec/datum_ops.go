@@ -0,0 +1,214 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// datumCmpCtx is the evaluation context used to compare the values stored as
+// coltypes.Datum. The templated operators don't have access to the evaluation
+// context of the flow, but the comparison of these values doesn't depend on
+// the session: typeconv doesn't store the arrays of TIMESTAMPTZ, which are
+// compared in the session time zone, as coltypes.Datum.
+var datumCmpCtx = &tree.EvalContext{SessionData: &sessiondata.SessionData{}}
+
+// asDatum returns the tree.Datum stored in an element of a coltypes.Datum
+// column. The elements that were never set are nil, and they are treated as
+// NULLs.
+func asDatum(v interface{}) tree.Datum {
+	if v == nil {
+		return tree.DNull
+	}
+	return v.(tree.Datum)
+}
+
+// compareDatums compares two elements of coltypes.Datum columns. It is used by
+// the templated comparison operators.
+func compareDatums(l, r interface{}) int {
+	return asDatum(l).Compare(datumCmpCtx, asDatum(r))
+}
+
+// hashDatum combines the hash of an element of a coltypes.Datum column with
+// the hash h. The elements are hashed by their key encoding, so the elements
+// that are equal according to compareDatums have the same hash. Like in the
+// row engine, hashing the types that don't have a key encoding (JSONB) is an
+// error.
+func hashDatum(v interface{}, h uintptr) uintptr {
+	b, err := sqlbase.EncodeTableKey(nil, asDatum(v), encoding.Ascending)
+	if err != nil {
+		execerror.NonVectorizedPanic(err)
+	}
+	sh := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	return memhash(unsafe.Pointer(sh.Data), h, uintptr(len(b)))
+}
+
+// datumExprProjOp is a projection operator that evaluates a binary, comparison
+// or cast expression row by row on datums. The arguments of the expression
+// that aren't constants are read from the columns of the input batch. It is
+// used for the expressions that the templated operators don't implement (see
+// needsDatumExprProjection).
+type datumExprProjOp struct {
+	OneInputNode
+	allocator      *Allocator
+	evalCtx        *tree.EvalContext
+	expr           tree.TypedExpr
+	columnTypes    []types.T
+	argumentCols   []int
+	outputIdx      int
+	outputPhysType coltypes.T
+	converter      func(tree.Datum) (interface{}, error)
+
+	row tree.Datums
+	da  sqlbase.DatumAlloc
+}
+
+var _ Operator = &datumExprProjOp{}
+var _ tree.IndexedVarContainer = &datumExprProjOp{}
+
+// newDatumExprProjOp returns a datumExprProjOp that evaluates expr and writes
+// the result to the column outputIdx. argumentCols has an element for each
+// argument of expr: the index of the column that holds its values, or -1 if
+// the argument is a constant.
+func newDatumExprProjOp(
+	allocator *Allocator,
+	evalCtx *tree.EvalContext,
+	expr tree.TypedExpr,
+	columnTypes []types.T,
+	argumentCols []int,
+	outputIdx int,
+	input Operator,
+) (Operator, error) {
+	outputType := expr.ResolvedType()
+	op := &datumExprProjOp{
+		OneInputNode:   NewOneInputNode(input),
+		allocator:      allocator,
+		evalCtx:        evalCtx,
+		columnTypes:    columnTypes,
+		argumentCols:   argumentCols,
+		outputIdx:      outputIdx,
+		outputPhysType: typeconv.FromColumnType(outputType),
+		converter:      typeconv.GetDatumToPhysicalFn(outputType),
+		row:            make(tree.Datums, len(argumentCols)),
+	}
+	if op.outputPhysType == coltypes.Unhandled {
+		return nil, fmt.Errorf("unsupported output type %s", outputType)
+	}
+	// The arguments that aren't constants are replaced by ordinal references
+	// into op.row, which is filled in for every tuple.
+	h := tree.MakeIndexedVarHelper(op, len(argumentCols))
+	arg := func(i int, e tree.Expr) tree.Expr {
+		if argumentCols[i] < 0 {
+			return e
+		}
+		return h.IndexedVar(i)
+	}
+	switch t := expr.(type) {
+	case *tree.BinaryExpr:
+		e := *t
+		e.Left, e.Right = arg(0, t.Left), arg(1, t.Right)
+		op.expr = &e
+	case *tree.ComparisonExpr:
+		e := *t
+		e.Left, e.Right = arg(0, t.Left), arg(1, t.Right)
+		op.expr = &e
+	case *tree.CastExpr:
+		e := *t
+		e.Expr = arg(0, t.Expr)
+		op.expr = &e
+	default:
+		return nil, fmt.Errorf("unhandled datum expression type: %s", reflect.TypeOf(t))
+	}
+	return op, nil
+}
+
+// IndexedVarEval is part of the tree.IndexedVarContainer interface.
+func (p *datumExprProjOp) IndexedVarEval(idx int, _ *tree.EvalContext) (tree.Datum, error) {
+	return p.row[idx], nil
+}
+
+// IndexedVarResolvedType is part of the tree.IndexedVarContainer interface.
+func (p *datumExprProjOp) IndexedVarResolvedType(idx int) *types.T {
+	return &p.columnTypes[p.argumentCols[idx]]
+}
+
+// IndexedVarNodeFormatter is part of the tree.IndexedVarContainer interface.
+func (p *datumExprProjOp) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	return nil
+}
+
+func (p *datumExprProjOp) Init() {
+	p.input.Init()
+}
+
+func (p *datumExprProjOp) Next(ctx context.Context) coldata.Batch {
+	batch := p.input.Next(ctx)
+	n := batch.Length()
+	if p.outputIdx == batch.Width() {
+		p.allocator.AppendColumn(batch, p.outputPhysType)
+	}
+	if n == 0 {
+		return batch
+	}
+
+	sel := batch.Selection()
+	output := batch.ColVec(p.outputIdx)
+	p.allocator.performOperation(
+		[]coldata.Vec{output},
+		func() {
+			for i := uint16(0); i < n; i++ {
+				rowIdx := i
+				if sel != nil {
+					rowIdx = sel[i]
+				}
+
+				for j, colIdx := range p.argumentCols {
+					if colIdx < 0 {
+						continue
+					}
+					p.row[j] = PhysicalTypeColElemToDatum(
+						batch.ColVec(colIdx), rowIdx, p.da, &p.columnTypes[colIdx],
+					)
+				}
+
+				res, err := p.expr.Eval(p.evalCtx)
+				if err != nil {
+					execerror.NonVectorizedPanic(err)
+				}
+
+				// Convert the datum into a physical type and write it out.
+				if res == tree.DNull {
+					output.Nulls().SetNull(rowIdx)
+				} else {
+					converted, err := p.converter(res)
+					if err != nil {
+						execerror.VectorizedInternalPanic(err)
+					}
+					coldata.SetValueAt(output, converted, rowIdx, p.outputPhysType)
+				}
+			}
+		},
+	)
+	return batch
+}
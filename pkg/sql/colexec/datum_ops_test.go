@@ -0,0 +1,53 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestCompareAndHashDatums(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	makeArray := func(vals ...int) tree.Datum {
+		a := tree.NewDArray(types.Int)
+		for _, v := range vals {
+			if err := a.Append(tree.NewDInt(tree.DInt(v))); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return a
+	}
+
+	testCases := []struct {
+		l, r     interface{}
+		expected int
+	}{
+		{l: makeArray(1, 2), r: makeArray(1, 2), expected: 0},
+		{l: makeArray(1, 2), r: makeArray(1, 3), expected: -1},
+		{l: makeArray(3), r: makeArray(1, 2), expected: 1},
+		{l: makeArray(1), r: makeArray(1, 2), expected: -1},
+		{l: nil, r: makeArray(), expected: -1},
+		{l: nil, r: tree.DNull, expected: 0},
+	}
+	for _, tc := range testCases {
+		if res := compareDatums(tc.l, tc.r); res != tc.expected {
+			t.Errorf("comparing %v and %v: expected %d, got %d", tc.l, tc.r, tc.expected, res)
+		}
+		if tc.expected == 0 && hashDatum(tc.l, 1) != hashDatum(tc.r, 1) {
+			t.Errorf("equal datums %v and %v have different hashes", tc.l, tc.r)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/colserde"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// canSpillToDisk returns whether batches of the given types can be written to
+// the temporary storage by a diskQueue.
+func canSpillToDisk(typs []coltypes.T) bool {
+	if len(typs) == 0 {
+		// The record batch format doesn't support batches without columns.
+		return false
+	}
+	_, err := colserde.NewArrowBatchConverter(typs)
+	return err == nil
+}
+
+// diskQueue is a FIFO queue of batches that are stored in a file of the
+// temporary storage directory, using the arrow file format. All the batches
+// must be enqueued before any batch is dequeued.
+type diskQueue struct {
+	allocator *Allocator
+	typs      []coltypes.T
+	dir       string
+	// diskAcc accounts for the size of the file.
+	diskAcc *mon.BoundAccount
+
+	// file is created lazily when the first batch is enqueued, since a lot of
+	// queues (for example, the partitions of a small input) remain empty.
+	file       *os.File
+	w          *countingFileWriter
+	serializer *colserde.FileSerializer
+	// scratch is used to remove the selection vectors of the enqueued batches,
+	// since the serializer expects the tuples to be contiguous.
+	scratch coldata.Batch
+
+	deserializer *colserde.FileDeserializer
+	// dequeued is the number of batches dequeued so far.
+	dequeued int
+	// finished indicates whether finishEnqueueing has been called.
+	finished bool
+}
+
+// newDiskQueue returns a queue of batches of the given types, whose file is
+// created in dir. The allocator is only used for the batches that the queue
+// uses internally.
+func newDiskQueue(
+	allocator *Allocator, typs []coltypes.T, dir string, diskAcc *mon.BoundAccount,
+) *diskQueue {
+	return &diskQueue{allocator: allocator, typs: typs, dir: dir, diskAcc: diskAcc}
+}
+
+// enqueue adds the tuples of the batch to the queue.
+func (q *diskQueue) enqueue(ctx context.Context, batch coldata.Batch) error {
+	n := batch.Length()
+	if n == 0 {
+		return nil
+	}
+	if sel := batch.Selection(); sel != nil {
+		return q.enqueueSel(ctx, batch, sel[:n])
+	}
+	return q.write(ctx, batch)
+}
+
+// enqueueSel adds the tuples of the batch at the given indices to the queue.
+// The indices must already take into account the selection vector of the
+// batch, if any.
+func (q *diskQueue) enqueueSel(ctx context.Context, batch coldata.Batch, sel []uint16) error {
+	if len(sel) == 0 {
+		return nil
+	}
+	if q.scratch == nil {
+		q.scratch = q.allocator.NewMemBatch(q.typs)
+	}
+	q.scratch.ResetInternalBatch()
+	for i, typ := range q.typs {
+		q.allocator.Copy(
+			q.scratch.ColVec(i),
+			coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					ColType:   typ,
+					Src:       batch.ColVec(i),
+					Sel:       sel,
+					SrcEndIdx: uint64(len(sel)),
+				},
+			},
+		)
+	}
+	q.scratch.SetLength(uint16(len(sel)))
+	return q.write(ctx, q.scratch)
+}
+
+// write appends a batch without a selection vector to the file.
+func (q *diskQueue) write(ctx context.Context, batch coldata.Batch) error {
+	if q.finished {
+		return errors.AssertionFailedf("enqueue called after finishEnqueueing")
+	}
+	if q.file == nil {
+		f, err := ioutil.TempFile(q.dir, "vectorized-spill")
+		if err != nil {
+			return pgerror.Wrapf(err, pgcode.Io, "creating a file in %s", q.dir)
+		}
+		q.file = f
+		q.w = &countingFileWriter{f: f}
+		if q.serializer, err = colserde.NewFileSerializer(q.w, q.typs); err != nil {
+			return err
+		}
+	}
+	before := q.w.written
+	if err := q.serializer.AppendBatch(batch); err != nil {
+		return pgerror.Wrapf(err, pgcode.Io, "writing to %s", q.file.Name())
+	}
+	return q.diskAcc.Grow(ctx, q.w.written-before)
+}
+
+// finishEnqueueing must be called after all the batches have been enqueued,
+// and before any batch is dequeued.
+func (q *diskQueue) finishEnqueueing(ctx context.Context) error {
+	q.finished = true
+	if q.file == nil {
+		return nil
+	}
+	before := q.w.written
+	if err := q.serializer.Finish(); err != nil {
+		return pgerror.Wrapf(err, pgcode.Io, "writing to %s", q.file.Name())
+	}
+	if err := q.file.Close(); err != nil {
+		return pgerror.Wrapf(err, pgcode.Io, "closing %s", q.file.Name())
+	}
+	if err := q.diskAcc.Grow(ctx, q.w.written-before); err != nil {
+		return err
+	}
+	var err error
+	q.deserializer, err = colserde.NewFileDeserializerFromPath(q.file.Name())
+	return err
+}
+
+// empty returns whether no batch was enqueued.
+func (q *diskQueue) empty() bool {
+	return q.file == nil
+}
+
+// dequeue fills in the given batch with the next batch of the queue. It
+// returns false once all the batches have been dequeued. The batch stays valid
+// until the next call to dequeue or close.
+func (q *diskQueue) dequeue(batch coldata.Batch) (bool, error) {
+	if !q.finished {
+		return false, errors.AssertionFailedf("dequeue called before finishEnqueueing")
+	}
+	if q.deserializer == nil || q.dequeued >= q.deserializer.NumBatches() {
+		return false, nil
+	}
+	if err := q.deserializer.GetBatch(q.dequeued, batch); err != nil {
+		return false, err
+	}
+	q.dequeued++
+	return true, nil
+}
+
+// numBatches returns the number of batches in the queue. It may only be called
+// after finishEnqueueing.
+func (q *diskQueue) numBatches() int {
+	if q.deserializer == nil {
+		return 0
+	}
+	return q.deserializer.NumBatches()
+}
+
+// get fills in the given batch with the i'th batch of the queue, regardless of
+// the batches that have been dequeued. It may only be called after
+// finishEnqueueing, and the batch stays valid until the next call to get,
+// dequeue or close.
+func (q *diskQueue) get(i int, batch coldata.Batch) error {
+	if !q.finished {
+		return errors.AssertionFailedf("get called before finishEnqueueing")
+	}
+	if i < 0 || i >= q.numBatches() {
+		return errors.AssertionFailedf("batch %d out of range [0, %d)", i, q.numBatches())
+	}
+	return q.deserializer.GetBatch(i, batch)
+}
+
+// close removes the file of the queue. It can be called multiple times.
+func (q *diskQueue) close(ctx context.Context) error {
+	if q.file == nil {
+		return nil
+	}
+	var err error
+	if q.deserializer != nil {
+		err = q.deserializer.Close()
+		q.deserializer = nil
+	} else if !q.finished {
+		// The file hasn't been closed yet.
+		err = q.file.Close()
+	}
+	if rmErr := os.Remove(q.file.Name()); rmErr != nil && err == nil {
+		err = pgerror.Wrapf(rmErr, pgcode.Io, "removing %s", q.file.Name())
+	}
+	q.diskAcc.Shrink(ctx, q.w.written)
+	q.file = nil
+	return err
+}
+
+// countingFileWriter counts the number of bytes written to a file.
+type countingFileWriter struct {
+	f       *os.File
+	written int64
+}
+
+var _ io.Writer = &countingFileWriter{}
+
+func (w *countingFileWriter) Write(buf []byte) (int, error) {
+	n, err := w.f.Write(buf)
+	w.written += int64(n)
+	return n, err
+}
+
+// diskQueueReader is an Operator that emits the batches of a diskQueue.
+type diskQueueReader struct {
+	ZeroInputNode
+	NonExplainable
+
+	q     *diskQueue
+	batch coldata.Batch
+}
+
+var _ Operator = &diskQueueReader{}
+
+func newDiskQueueReader(q *diskQueue) *diskQueueReader {
+	return &diskQueueReader{q: q}
+}
+
+func (r *diskQueueReader) Init() {
+	// The batch is filled in by the deserializer, which replaces its columns
+	// with the data of the file, so it doesn't need any columns of its own.
+	r.batch = coldata.NewMemBatchWithSize(nil /* types */, 0 /* size */)
+}
+
+func (r *diskQueueReader) Next(ctx context.Context) coldata.Batch {
+	ok, err := r.q.dequeue(r.batch)
+	if err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+	if !ok {
+		r.batch.SetLength(0)
+	}
+	return r.batch
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+func TestDiskQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	diskMonitor := execinfra.NewTestDiskMonitor(ctx, cluster.MakeTestingClusterSettings())
+	defer diskMonitor.Stop(ctx)
+	diskAcc := diskMonitor.MakeBoundAccount()
+	defer diskAcc.Close(ctx)
+
+	rng, _ := randutil.NewPseudoRand()
+	typs := []coltypes.T{coltypes.Int64, coltypes.Bytes, coltypes.Bool}
+	tups := make(tuples, 3000)
+	for i := range tups {
+		tups[i] = tuple{i, string(randutil.RandBytes(rng, rng.Intn(16))), i%2 == 0}
+		if i%7 == 0 {
+			tups[i][1] = nil
+		}
+	}
+
+	for _, useSel := range []bool{false, true} {
+		q := newDiskQueue(testAllocator, typs, dir, &diskAcc)
+		var input Operator
+		if useSel {
+			input = newOpTestSelInput(rng, 100 /* batchSize */, tups, typs)
+		} else {
+			input = newOpTestInput(100 /* batchSize */, tups, typs)
+		}
+		input.Init()
+		for b := input.Next(ctx); b.Length() > 0; b = input.Next(ctx) {
+			if err := q.enqueue(ctx, b); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := q.finishEnqueueing(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if diskAcc.Used() == 0 {
+			t.Fatal("expected the queue to account for its file")
+		}
+
+		out := newOpTestOutput(newDiskQueueReader(q), tups)
+		if err := out.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		if err := q.close(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if diskAcc.Used() != 0 {
+			t.Fatalf("expected no disk usage after closing the queue, found %d bytes", diskAcc.Used())
+		}
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 0 {
+			t.Fatalf("expected the file of the queue to be removed, found %d files", len(files))
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+// Closer is an object that holds resources, such as files in the temporary
+// storage, that must be released once the flow is done, whether or not it ran
+// to completion.
+type Closer interface {
+	// Close releases the resources. It can be called multiple times.
+	Close(ctx context.Context) error
+}
+
+// bufferingInMemoryOperator is an Operator that buffers its input in memory
+// before it emits any output. If the memory limit is reached while it is
+// buffering, the tuples that it has buffered so far can be exported, so that
+// a disk-backed operator can take over the processing of the input.
+type bufferingInMemoryOperator interface {
+	Operator
+
+	// buffering returns whether the operator is buffering its input. If the
+	// operator panicked with a memory error while buffering, the tuples that it
+	// buffered can be exported, and the output of the disk-backed operator
+	// that processes them, followed by the rest of the inputs, continues the
+	// output that the in-memory operator emitted so far (if any).
+	buffering() bool
+
+	// exportBuffered returns the next batch of the tuples that the operator
+	// buffered from the given input, including the batch that was being
+	// buffered when the memory limit was reached. A zero-length batch is
+	// returned once all of them have been exported. It may only be called once
+	// the operator has panicked while buffering, and the returned batch is only
+	// valid until the next call.
+	exportBuffered(input Operator) coldata.Batch
+}
+
+// columnExporter copies the tuples that an in-memory operator buffered in
+// columns into batches, so that they can be exported one batch at a time.
+type columnExporter struct {
+	typs  []coltypes.T
+	batch coldata.Batch
+	// exported is the number of tuples exported so far.
+	exported uint64
+}
+
+// next returns the next batch of the first n tuples of the given columns, or a
+// zero-length batch once all of them have been exported.
+func (e *columnExporter) next(cols []coldata.Vec, n uint64) coldata.Batch {
+	if e.exported >= n {
+		return zeroBatch
+	}
+	if e.batch == nil {
+		// The batch is not accounted for, since the memory limit has already
+		// been reached, and the memory of the buffered tuples is released as soon
+		// as they have all been exported.
+		e.batch = coldata.NewMemBatch(e.typs)
+	}
+	e.batch.ResetInternalBatch()
+	end := e.exported + uint64(coldata.BatchSize())
+	if end > n {
+		end = n
+	}
+	for i, typ := range e.typs {
+		e.batch.ColVec(i).Copy(
+			coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					ColType:     typ,
+					Src:         cols[i],
+					SrcStartIdx: e.exported,
+					SrcEndIdx:   end,
+				},
+			},
+		)
+	}
+	e.batch.SetLength(uint16(end - e.exported))
+	e.exported = end
+	return e.batch
+}
+
+// reset prepares the exporter for another export.
+func (e *columnExporter) reset() {
+	e.exported = 0
+}
+
+// diskSpiller is an Operator that runs an in-memory buffering operator until
+// its memory limit is reached. At that point, the tuples buffered by the
+// in-memory operator, followed by the rest of the inputs, are handed over to a
+// disk-backed operator that produces the same output, and the in-memory
+// operator is discarded.
+type diskSpiller struct {
+	NonExplainable
+
+	inputs       []Operator
+	inMemoryOp   bufferingInMemoryOperator
+	inMemoryAcc  *mon.BoundAccount
+	diskBackedOp Operator
+	// diskAcc, if set, is the account used by the disk-backed operator for the
+	// temporary storage. It is cleared when the spiller is closed.
+	diskAcc *mon.BoundAccount
+	// newDiskBackedOp creates the disk-backed operator, given the inputs from
+	// which it must read.
+	newDiskBackedOp func(inputs []Operator) Operator
+	spilled         bool
+}
+
+var _ Operator = &diskSpiller{}
+var _ Closer = &diskSpiller{}
+
+// newDiskSpiller returns a diskSpiller. The in-memory operator must use the
+// given memory account, whose memory is released once the buffered tuples have
+// been handed over to the disk-backed operator. diskAcc can be nil if the disk
+// account is owned by another component.
+func newDiskSpiller(
+	inputs []Operator,
+	inMemoryOp bufferingInMemoryOperator,
+	inMemoryAcc *mon.BoundAccount,
+	diskAcc *mon.BoundAccount,
+	newDiskBackedOp func(inputs []Operator) Operator,
+) *diskSpiller {
+	return &diskSpiller{
+		inputs:          inputs,
+		inMemoryOp:      inMemoryOp,
+		inMemoryAcc:     inMemoryAcc,
+		diskAcc:         diskAcc,
+		newDiskBackedOp: newDiskBackedOp,
+	}
+}
+
+func (d *diskSpiller) Init() {
+	// The inputs are initialized by the in-memory operator. The disk-backed
+	// operator is only created and initialized if needed.
+	d.inMemoryOp.Init()
+}
+
+func (d *diskSpiller) Next(ctx context.Context) coldata.Batch {
+	if d.spilled {
+		return d.diskBackedOp.Next(ctx)
+	}
+	// The errors are caught even if the in-memory operator is done buffering,
+	// since some operators (such as the chunk sorter) alternate between
+	// buffering and emitting.
+	var batch coldata.Batch
+	if err := execerror.CatchVectorizedRuntimeError(func() {
+		batch = d.inMemoryOp.Next(ctx)
+	}); err != nil {
+		if !sqlbase.IsOutOfMemoryError(err) || !d.inMemoryOp.buffering() {
+			execerror.VectorizedInternalPanic(err)
+		}
+		d.spill(ctx)
+		return d.diskBackedOp.Next(ctx)
+	}
+	return batch
+}
+
+// spill creates the disk-backed operator, which reads the tuples exported by
+// the in-memory operator before reading from the inputs.
+func (d *diskSpiller) spill(ctx context.Context) {
+	d.spilled = true
+	exporters := make([]Operator, len(d.inputs))
+	exhausted := 0
+	for i, input := range d.inputs {
+		exporters[i] = &bufferExportingOperator{
+			OneInputNode: NewOneInputNode(input),
+			source:       d.inMemoryOp,
+			exhaustedFn: func() {
+				exhausted++
+				if exhausted == len(d.inputs) {
+					// The in-memory operator won't be used anymore.
+					d.inMemoryOp = nil
+					d.inMemoryAcc.Clear(ctx)
+				}
+			},
+		}
+	}
+	d.diskBackedOp = d.newDiskBackedOp(exporters)
+	d.diskBackedOp.Init()
+}
+
+// Close implements the Closer interface.
+func (d *diskSpiller) Close(ctx context.Context) error {
+	var err error
+	if c, ok := d.diskBackedOp.(Closer); ok {
+		err = c.Close(ctx)
+	}
+	if d.diskAcc != nil {
+		d.diskAcc.Clear(ctx)
+	}
+	return err
+}
+
+// ChildCount implements the execinfra.OpNode interface.
+func (d *diskSpiller) ChildCount() int {
+	return 1
+}
+
+// Child implements the execinfra.OpNode interface.
+func (d *diskSpiller) Child(nth int) execinfra.OpNode {
+	if nth == 0 {
+		if d.spilled {
+			return d.diskBackedOp
+		}
+		return d.inMemoryOp
+	}
+	execerror.VectorizedInternalPanic(fmt.Sprintf("invalid index %d", nth))
+	// This code is unreachable, but the compiler cannot infer that.
+	return nil
+}
+
+// bufferExportingOperator is an Operator that emits the tuples that an
+// in-memory operator buffered from its input, followed by the rest of the
+// input.
+type bufferExportingOperator struct {
+	OneInputNode
+	NonExplainable
+
+	source bufferingInMemoryOperator
+	// exhaustedFn is called once all the buffered tuples have been emitted.
+	exhaustedFn func()
+}
+
+var _ Operator = &bufferExportingOperator{}
+
+func (b *bufferExportingOperator) Init() {
+	// The input has already been initialized by the in-memory operator.
+}
+
+func (b *bufferExportingOperator) Next(ctx context.Context) coldata.Batch {
+	if b.source != nil {
+		if batch := b.source.exportBuffered(b.input); batch.Length() > 0 {
+			return batch
+		}
+		b.source = nil
+		b.exhaustedFn()
+	}
+	return b.input.Next(ctx)
+}
@@ -0,0 +1,248 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+// diskSpillingTestMemoryLimit is the memory limit of the buffering operators
+// in the disk spilling tests. It is large enough for the operators to be
+// initialized, but much smaller than their inputs.
+const diskSpillingTestMemoryLimit = 128 << 10 /* 128 KiB */
+
+// runDiskSpillingTest plans the given spec with a small memory limit, verifies
+// that the operator spilled to disk and produced the expected output, and that
+// all its resources are released once it is closed.
+func runDiskSpillingTest(
+	t *testing.T,
+	spec *execinfrapb.ProcessorSpec,
+	inputs []tuples,
+	inputTypes [][]coltypes.T,
+	expected tuples,
+	anyOrder bool,
+) {
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	diskMonitor := execinfra.NewTestDiskMonitor(ctx, st)
+	defer diskMonitor.Stop(ctx)
+	flowCtx := &execinfra.FlowCtx{
+		EvalCtx: &evalCtx,
+		Cfg: &execinfra.ServerConfig{
+			Settings:        st,
+			TempStoragePath: dir,
+			DiskMonitor:     diskMonitor,
+			TestingKnobs: execinfra.TestingKnobs{
+				MemoryLimitBytes: diskSpillingTestMemoryLimit,
+			},
+		},
+	}
+
+	sources := make([]Operator, len(inputs))
+	for i := range inputs {
+		sources[i] = newOpTestInput(1024 /* batchSize */, inputs[i], inputTypes[i])
+	}
+	result, err := NewColOperator(
+		ctx, flowCtx, spec, sources, testMemAcc,
+		false, /* useStreamingMemAccountForBuffering */
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.SpillsToDisk {
+		t.Fatal("expected the operator to be able to spill to disk")
+	}
+	out := newOpTestOutput(result.Op, expected)
+	if anyOrder {
+		err = out.VerifyAnyOrder()
+	} else {
+		err = out.Verify()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var spilled bool
+	for _, c := range result.ToClose {
+		if s, ok := c.(*diskSpiller); ok && s.spilled {
+			spilled = true
+		}
+		if err := c.Close(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !spilled {
+		t.Fatal("expected the operator to spill to disk")
+	}
+	for _, acc := range result.BufferingOpMemAccounts {
+		acc.Close(ctx)
+	}
+	for _, memMonitor := range result.BufferingOpMemMonitors {
+		memMonitor.Stop(ctx)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("expected all the spilled files to be removed, found %d files", len(files))
+	}
+}
+
+func TestExternalSort(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewPseudoRand()
+	// The input is large enough for the external sorter to merge its sorted
+	// runs in several steps.
+	const nTuples = 200000
+	input := make(tuples, nTuples)
+	expected := make(tuples, nTuples)
+	for i := range input {
+		input[i] = tuple{i}
+		expected[i] = tuple{i}
+	}
+	rng.Shuffle(nTuples, func(i, j int) { input[i], input[j] = input[j], input[i] })
+
+	spec := &execinfrapb.ProcessorSpec{
+		Input: []execinfrapb.InputSyncSpec{{ColumnTypes: []types.T{*types.Int}}},
+		Core: execinfrapb.ProcessorCoreUnion{
+			Sorter: &execinfrapb.SorterSpec{
+				OutputOrdering: execinfrapb.Ordering{
+					Columns: []execinfrapb.Ordering_Column{{ColIdx: 0}},
+				},
+			},
+		},
+	}
+	runDiskSpillingTest(
+		t, spec, []tuples{input}, [][]coltypes.T{{coltypes.Int64}}, expected, false, /* anyOrder */
+	)
+}
+
+func TestGraceHashJoin(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const nTuples = 20000
+	left := make(tuples, nTuples)
+	right := make(tuples, nTuples)
+	expected := make(tuples, 0, nTuples)
+	for i := 0; i < nTuples; i++ {
+		left[i] = tuple{i, i}
+		right[i] = tuple{i % (nTuples / 2), i}
+		if i < nTuples/2 {
+			expected = append(expected, tuple{i, i, i, i}, tuple{i, i, i, i + nTuples/2})
+		}
+	}
+
+	typs := []types.T{*types.Int, *types.Int}
+	spec := &execinfrapb.ProcessorSpec{
+		Input: []execinfrapb.InputSyncSpec{{ColumnTypes: typs}, {ColumnTypes: typs}},
+		Core: execinfrapb.ProcessorCoreUnion{
+			HashJoiner: &execinfrapb.HashJoinerSpec{
+				LeftEqColumns:  []uint32{0},
+				RightEqColumns: []uint32{0},
+				Type:           sqlbase.JoinType_INNER,
+			},
+		},
+	}
+	colTypes := []coltypes.T{coltypes.Int64, coltypes.Int64}
+	runDiskSpillingTest(
+		t, spec, []tuples{left, right}, [][]coltypes.T{colTypes, colTypes}, expected, true, /* anyOrder */
+	)
+}
+
+func TestSpillingHashAggregator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const nTuples, nGroups = 20000, 1000
+	input := make(tuples, nTuples)
+	for i := range input {
+		input[i] = tuple{i % nGroups, 1}
+	}
+	expected := make(tuples, nGroups)
+	for i := range expected {
+		expected[i] = tuple{i, nTuples / nGroups}
+	}
+
+	spec := &execinfrapb.ProcessorSpec{
+		Input: []execinfrapb.InputSyncSpec{{ColumnTypes: []types.T{*types.Int, *types.Int}}},
+		Core: execinfrapb.ProcessorCoreUnion{
+			Aggregator: &execinfrapb.AggregatorSpec{
+				GroupCols: []uint32{0},
+				Aggregations: []execinfrapb.AggregatorSpec_Aggregation{
+					{Func: execinfrapb.AggregatorSpec_ANY_NOT_NULL, ColIdx: []uint32{0}},
+					{Func: execinfrapb.AggregatorSpec_SUM_INT, ColIdx: []uint32{1}},
+				},
+			},
+		},
+	}
+	colTypes := []coltypes.T{coltypes.Int64, coltypes.Int64}
+	runDiskSpillingTest(
+		t, spec, []tuples{input}, [][]coltypes.T{colTypes}, expected, true, /* anyOrder */
+	)
+}
+
+func TestSpillingBufferedWindow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// Every partition is much larger than the memory limit, so it is buffered
+	// in the temporary storage.
+	const nTuples, nPartitions = 50000, 2
+	input := make(tuples, nTuples)
+	expected := make(tuples, nTuples)
+	for i := range input {
+		input[i] = tuple{i % nPartitions, i}
+		expected[i] = tuple{i % nPartitions, i, nil}
+		if i >= nPartitions {
+			expected[i][2] = i - nPartitions
+		}
+	}
+
+	lagFn := execinfrapb.WindowerSpec_LAG
+	spec := &execinfrapb.ProcessorSpec{
+		Input: []execinfrapb.InputSyncSpec{{ColumnTypes: []types.T{*types.Int, *types.Int}}},
+		Core: execinfrapb.ProcessorCoreUnion{
+			Windower: &execinfrapb.WindowerSpec{
+				PartitionBy: []uint32{0},
+				WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+					{
+						Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &lagFn},
+						ArgsIdxs:     []uint32{1},
+						Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+						FilterColIdx: -1,
+						OutputColIdx: 2,
+					},
+				},
+			},
+		},
+	}
+	colTypes := []coltypes.T{coltypes.Int64, coltypes.Int64}
+	runDiskSpillingTest(
+		t, spec, []tuples{input}, [][]coltypes.T{colTypes}, expected, true, /* anyOrder */
+	)
+}
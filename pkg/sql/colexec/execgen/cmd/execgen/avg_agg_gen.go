@@ -44,6 +44,8 @@ if _, err := tree.DecimalCtx.Quo(&%s, &%s, &%s); err != nil {
 		)
 	case coltypes.Float64:
 		return fmt.Sprintf("%s = %s / float64(%s)", target, l, r)
+	case coltypes.Interval:
+		return fmt.Sprintf("%s = %s.Div(%s)", target, l, r)
 	default:
 		execerror.VectorizedInternalPanic("unsupported avg agg type")
 		// This code is unreachable, but the compiler cannot infer that.
@@ -84,7 +86,7 @@ func genAvgAgg(wr io.Writer) error {
 	}
 
 	// TODO(asubiotto): Support more coltypes.
-	supportedTypes := []coltypes.T{coltypes.Decimal, coltypes.Float64}
+	supportedTypes := []coltypes.T{coltypes.Decimal, coltypes.Float64, coltypes.Interval}
 	spm := make(map[coltypes.T]int)
 	for i, typ := range supportedTypes {
 		spm[typ] = i
@@ -242,38 +242,42 @@ func init() {
 	anyTypeBinaryOpToOverloads = make(map[tree.BinaryOperator][]*overload, len(binaryOpName))
 	sameTypeComparisonOpToOverloads = make(map[tree.ComparisonOperator][]*overload, len(comparisonOpName))
 	anyTypeComparisonOpToOverloads = make(map[tree.ComparisonOperator][]*overload, len(comparisonOpName))
+	addBinOpOverloads := func(leftType, rightType coltypes.T) {
+		customizer := typeCustomizers[coltypePair{leftType, rightType}]
+		for _, op := range binOps {
+			// Skip types that don't have associated binary ops.
+			retType, ok := binOpOutputTypes[op][coltypePair{leftType, rightType}]
+			if !ok {
+				continue
+			}
+			ov := &overload{
+				Name:      binaryOpName[op],
+				BinOp:     op,
+				IsBinOp:   true,
+				OpStr:     binaryOpInfix[op],
+				LTyp:      leftType,
+				RTyp:      rightType,
+				LGoType:   leftType.GoTypeName(),
+				RGoType:   rightType.GoTypeName(),
+				RetTyp:    retType,
+				RetGoType: retType.GoTypeName(),
+			}
+			if customizer != nil {
+				if b, ok := customizer.(binOpTypeCustomizer); ok {
+					ov.AssignFunc = b.getBinOpAssignFunc()
+				}
+			}
+			binaryOpOverloads = append(binaryOpOverloads, ov)
+			anyTypeBinaryOpToOverloads[op] = append(anyTypeBinaryOpToOverloads[op], ov)
+			if leftType == rightType {
+				sameTypeBinaryOpToOverloads[op] = append(sameTypeBinaryOpToOverloads[op], ov)
+			}
+		}
+	}
 	for _, leftType := range inputTypes {
 		for _, rightType := range coltypes.CompatibleTypes[leftType] {
 			customizer := typeCustomizers[coltypePair{leftType, rightType}]
-			for _, op := range binOps {
-				// Skip types that don't have associated binary ops.
-				retType, ok := binOpOutputTypes[op][coltypePair{leftType, rightType}]
-				if !ok {
-					continue
-				}
-				ov := &overload{
-					Name:      binaryOpName[op],
-					BinOp:     op,
-					IsBinOp:   true,
-					OpStr:     binaryOpInfix[op],
-					LTyp:      leftType,
-					RTyp:      rightType,
-					LGoType:   leftType.GoTypeName(),
-					RGoType:   rightType.GoTypeName(),
-					RetTyp:    retType,
-					RetGoType: retType.GoTypeName(),
-				}
-				if customizer != nil {
-					if b, ok := customizer.(binOpTypeCustomizer); ok {
-						ov.AssignFunc = b.getBinOpAssignFunc()
-					}
-				}
-				binaryOpOverloads = append(binaryOpOverloads, ov)
-				anyTypeBinaryOpToOverloads[op] = append(anyTypeBinaryOpToOverloads[op], ov)
-				if leftType == rightType {
-					sameTypeBinaryOpToOverloads[op] = append(sameTypeBinaryOpToOverloads[op], ov)
-				}
-			}
+			addBinOpOverloads(leftType, rightType)
 			for _, op := range cmpOps {
 				opStr := comparisonOpInfix[op]
 				ov := &overload{
@@ -332,6 +336,10 @@ func init() {
 		}
 		hashOverloads = append(hashOverloads, ov)
 	}
+	// Intervals can be added to and subtracted from timestamps, but the two
+	// types can't be compared, so they aren't compatible types.
+	addBinOpOverloads(coltypes.Timestamp, coltypes.Interval)
+	addBinOpOverloads(coltypes.Interval, coltypes.Timestamp)
 
 	// Build cast overloads. We omit cases of type casts that we do not support.
 	castOverloads = make(map[coltypes.T][]castOverload)
@@ -449,6 +457,15 @@ func init() {
 				}
 				castOverloads[from] = append(castOverloads[from], ov)
 			}
+		case coltypes.Interval:
+			for _, to := range inputTypes {
+				ov := castOverload{FromTyp: from, ToTyp: to, ToGoTyp: to.GoTypeName()}
+				switch to {
+				case coltypes.Interval:
+					ov.AssignFunc = castIdentity
+				}
+				castOverloads[from] = append(castOverloads[from], ov)
+			}
 		}
 	}
 }
@@ -540,6 +557,23 @@ type intFloatCustomizer struct{}
 // timestampCustomizer is necessary since time.Time doesn't have infix operators.
 type timestampCustomizer struct{}
 
+// intervalCustomizer is necessary since duration.Duration doesn't have infix
+// operators.
+type intervalCustomizer struct{}
+
+// timestampIntervalCustomizer supports mixed type expressions with a timestamp
+// left-hand side and an interval right-hand side.
+type timestampIntervalCustomizer struct{}
+
+// datumCustomizer is necessary since the values stored as coltypes.Datum are
+// tree.Datums, which can only be compared and hashed by the functions of the
+// tree and sqlbase packages.
+type datumCustomizer struct{}
+
+// intervalTimestampCustomizer supports mixed type expressions with an interval
+// left-hand side and a timestamp right-hand side.
+type intervalTimestampCustomizer struct{}
+
 func (boolCustomizer) getCmpOpCompareFunc() compareFunc {
 	return func(target, l, r string) string {
 		args := map[string]string{"Target": target, "Left": l, "Right": r}
@@ -1030,12 +1064,114 @@ func (timestampCustomizer) getHashAssignFunc() assignFunc {
 	}
 }
 
+func (timestampCustomizer) getBinOpAssignFunc() assignFunc {
+	return func(op overload, target, l, r string) string {
+		switch op.BinOp {
+		case tree.Minus:
+			return fmt.Sprintf(`
+			{
+				nanos := %[2]s.Sub(%[3]s).Nanoseconds()
+				%[1]s = duration.MakeDuration(nanos, 0, 0)
+			}
+			`, target, l, r)
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unhandled binary operator %s", op.BinOp.String()))
+		}
+		// This code is unreachable, but the compiler cannot infer that.
+		return ""
+	}
+}
+
+func (intervalCustomizer) getCmpOpCompareFunc() compareFunc {
+	return func(target, l, r string) string {
+		return fmt.Sprintf("%s = %s.Compare(%s)", target, l, r)
+	}
+}
+
+func (intervalCustomizer) getHashAssignFunc() assignFunc {
+	return func(op overload, target, v, _ string) string {
+		// Intervals that are equal according to Compare have the same total
+		// number of nanoseconds, so that's what we hash. Encode returns zero in
+		// case of an overflow, which only results in a collision.
+		return fmt.Sprintf(`
+		  s, _, _, _ := %[2]s.Encode()
+		  %[1]s = memhash64(noescape(unsafe.Pointer(&s)), %[1]s)
+		`, target, v)
+	}
+}
+
+func (intervalCustomizer) getBinOpAssignFunc() assignFunc {
+	return func(op overload, target, l, r string) string {
+		switch op.BinOp {
+		case tree.Plus:
+			return fmt.Sprintf(`%[1]s = %[2]s.Add(%[3]s)`, target, l, r)
+		case tree.Minus:
+			return fmt.Sprintf(`%[1]s = %[2]s.Sub(%[3]s)`, target, l, r)
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unhandled binary operator %s", op.BinOp.String()))
+		}
+		// This code is unreachable, but the compiler cannot infer that.
+		return ""
+	}
+}
+
+// timestampIntervalAddFmt is the format of the addition of an interval to a
+// timestamp. Like tree.MakeDTimestamp, the result is rounded to microseconds.
+// The operators use the compatible addition mode, and the planning falls back
+// to the row engine when the session uses another one.
+const timestampIntervalAddFmt = `%[1]s = duration.Add(duration.AdditionModeCompatible, %[2]s, %[3]s).Round(time.Microsecond)`
+
+func (timestampIntervalCustomizer) getBinOpAssignFunc() assignFunc {
+	return func(op overload, target, l, r string) string {
+		switch op.BinOp {
+		case tree.Plus:
+			return fmt.Sprintf(timestampIntervalAddFmt, target, l, r)
+		case tree.Minus:
+			return fmt.Sprintf(timestampIntervalAddFmt, target, l, r+".Mul(-1)")
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unhandled binary operator %s", op.BinOp.String()))
+		}
+		// This code is unreachable, but the compiler cannot infer that.
+		return ""
+	}
+}
+
+func (intervalTimestampCustomizer) getBinOpAssignFunc() assignFunc {
+	return func(op overload, target, l, r string) string {
+		switch op.BinOp {
+		case tree.Plus:
+			return fmt.Sprintf(timestampIntervalAddFmt, target, r, l)
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unhandled binary operator %s", op.BinOp.String()))
+		}
+		// This code is unreachable, but the compiler cannot infer that.
+		return ""
+	}
+}
+
+func (datumCustomizer) getCmpOpCompareFunc() compareFunc {
+	return func(target, l, r string) string {
+		return fmt.Sprintf("%s = compareDatums(%s, %s)", target, l, r)
+	}
+}
+
+func (datumCustomizer) getHashAssignFunc() assignFunc {
+	return func(op overload, target, v, _ string) string {
+		return fmt.Sprintf("%[1]s = hashDatum(%[2]s, %[1]s)", target, v)
+	}
+}
+
 func registerTypeCustomizers() {
 	typeCustomizers = make(map[coltypePair]typeCustomizer)
 	registerTypeCustomizer(coltypePair{coltypes.Bool, coltypes.Bool}, boolCustomizer{})
 	registerTypeCustomizer(coltypePair{coltypes.Bytes, coltypes.Bytes}, bytesCustomizer{})
 	registerTypeCustomizer(coltypePair{coltypes.Decimal, coltypes.Decimal}, decimalCustomizer{})
 	registerTypeCustomizer(coltypePair{coltypes.Timestamp, coltypes.Timestamp}, timestampCustomizer{})
+	registerTypeCustomizer(coltypePair{coltypes.TimestampTZ, coltypes.TimestampTZ}, timestampCustomizer{})
+	registerTypeCustomizer(coltypePair{coltypes.Datum, coltypes.Datum}, datumCustomizer{})
+	registerTypeCustomizer(coltypePair{coltypes.Interval, coltypes.Interval}, intervalCustomizer{})
+	registerTypeCustomizer(coltypePair{coltypes.Timestamp, coltypes.Interval}, timestampIntervalCustomizer{})
+	registerTypeCustomizer(coltypePair{coltypes.Interval, coltypes.Timestamp}, intervalTimestampCustomizer{})
 	for _, leftFloatType := range coltypes.FloatTypes {
 		for _, rightFloatType := range coltypes.FloatTypes {
 			registerTypeCustomizer(coltypePair{leftFloatType, rightFloatType}, floatCustomizer{width: 64})
@@ -1102,6 +1238,14 @@ func registerBinOpOutputTypes() {
 		}
 	}
 
+	binOpOutputTypes[tree.Minus][coltypePair{coltypes.Timestamp, coltypes.Timestamp}] = coltypes.Interval
+	binOpOutputTypes[tree.Minus][coltypePair{coltypes.TimestampTZ, coltypes.TimestampTZ}] = coltypes.Interval
+	binOpOutputTypes[tree.Plus][coltypePair{coltypes.Interval, coltypes.Interval}] = coltypes.Interval
+	binOpOutputTypes[tree.Minus][coltypePair{coltypes.Interval, coltypes.Interval}] = coltypes.Interval
+	binOpOutputTypes[tree.Plus][coltypePair{coltypes.Timestamp, coltypes.Interval}] = coltypes.Timestamp
+	binOpOutputTypes[tree.Minus][coltypePair{coltypes.Timestamp, coltypes.Interval}] = coltypes.Timestamp
+	binOpOutputTypes[tree.Plus][coltypePair{coltypes.Interval, coltypes.Timestamp}] = coltypes.Timestamp
+
 	// There is a special case for division with integers; it should have a
 	// decimal result.
 	for _, leftIntType := range coltypes.IntTypes {
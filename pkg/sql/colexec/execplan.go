@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/errors"
@@ -87,13 +88,22 @@ func wrapRowSource(
 // NewColOperatorResult is a helper struct that encompasses all of the return
 // values of NewColOperator call.
 type NewColOperatorResult struct {
-	Op                    Operator
-	ColumnTypes           []types.T
-	InternalMemUsage      int
-	MetadataSources       []execinfrapb.MetadataSource
-	IsStreaming           bool
-	BufferingOpMemMonitor *mon.BytesMonitor
-	BufferingOpMemAccount *mon.BoundAccount
+	Op               Operator
+	ColumnTypes      []types.T
+	InternalMemUsage int
+	MetadataSources  []execinfrapb.MetadataSource
+	IsStreaming      bool
+	// BufferingOpMemMonitors and BufferingOpMemAccounts contain the limited
+	// memory monitors and accounts of the buffering operators, one of each per
+	// operator, which must be released once the flow is done.
+	BufferingOpMemMonitors []*mon.BytesMonitor
+	BufferingOpMemAccounts []*mon.BoundAccount
+	// SpillsToDisk indicates whether the buffering operator falls back to the
+	// temporary storage once its memory limit is reached.
+	SpillsToDisk bool
+	// ToClose contains the components that hold resources, such as files in
+	// the temporary storage, which must be released once the flow is done.
+	ToClose []Closer
 }
 
 // joinerPlanningState is a helper struct used when creating a hash or merge
@@ -390,13 +400,21 @@ func NewColOperator(
 		if needHash {
 			hashAggregatorMemAccount := streamingMemAccount
 			if !useStreamingMemAccountForBuffering {
-				result.createBufferingMemAccount(ctx, flowCtx, "hash-aggregator-limited")
-				hashAggregatorMemAccount = result.BufferingOpMemAccount
+				hashAggregatorMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "hash-aggregator-limited")
+			}
+			newHashAggregator := func(allocator *Allocator, inputs []Operator) (Operator, error) {
+				return NewHashAggregator(
+					allocator, inputs[0], typs, aggFns,
+					aggSpec.GroupCols, aggCols, execinfrapb.IsScalarAggregate(aggSpec),
+				)
+			}
+			result.Op, err = newHashAggregator(NewAllocator(ctx, hashAggregatorMemAccount), inputs)
+			if err == nil && !useStreamingMemAccountForBuffering {
+				result.maybeWrapWithHashBasedDiskSpiller(
+					ctx, flowCtx, inputs, [][]coltypes.T{typs}, [][]uint32{aggSpec.GroupCols},
+					streamingMemAccount, newHashAggregator,
+				)
 			}
-			result.Op, err = NewHashAggregator(
-				NewAllocator(ctx, hashAggregatorMemAccount), inputs[0], typs, aggFns,
-				aggSpec.GroupCols, aggCols, execinfrapb.IsScalarAggregate(aggSpec),
-			)
 		} else {
 			result.Op, err = NewOrderedAggregator(
 				NewAllocator(ctx, streamingMemAccount), inputs[0], typs, aggFns,
@@ -467,23 +485,33 @@ func NewColOperator(
 
 			hashJoinerMemAccount := streamingMemAccount
 			if !useStreamingMemAccountForBuffering {
-				result.createBufferingMemAccount(ctx, flowCtx, "hash-joiner-limited")
-				hashJoinerMemAccount = result.BufferingOpMemAccount
+				hashJoinerMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "hash-joiner-limited")
+			}
+			newHashJoiner := func(allocator *Allocator, inputs []Operator) (Operator, error) {
+				return NewEqHashJoinerOp(
+					allocator,
+					inputs[0],
+					inputs[1],
+					core.HashJoiner.LeftEqColumns,
+					core.HashJoiner.RightEqColumns,
+					leftOutCols,
+					rightOutCols,
+					leftTypes,
+					rightTypes,
+					core.HashJoiner.RightEqColumnsAreKey,
+					core.HashJoiner.LeftEqColumnsAreKey || core.HashJoiner.RightEqColumnsAreKey,
+					core.HashJoiner.Type,
+				)
+			}
+			result.Op, err = newHashJoiner(NewAllocator(ctx, hashJoinerMemAccount), inputs)
+			if err == nil && !useStreamingMemAccountForBuffering &&
+				execinfra.SettingUseTempStorageJoins.Get(&flowCtx.Cfg.Settings.SV) {
+				result.maybeWrapWithHashBasedDiskSpiller(
+					ctx, flowCtx, inputs, [][]coltypes.T{leftTypes, rightTypes},
+					[][]uint32{core.HashJoiner.LeftEqColumns, core.HashJoiner.RightEqColumns},
+					streamingMemAccount, newHashJoiner,
+				)
 			}
-			result.Op, err = NewEqHashJoinerOp(
-				NewAllocator(ctx, hashJoinerMemAccount),
-				inputs[0],
-				inputs[1],
-				core.HashJoiner.LeftEqColumns,
-				core.HashJoiner.RightEqColumns,
-				leftOutCols,
-				rightOutCols,
-				leftTypes,
-				rightTypes,
-				core.HashJoiner.RightEqColumnsAreKey,
-				core.HashJoiner.LeftEqColumnsAreKey || core.HashJoiner.RightEqColumnsAreKey,
-				core.HashJoiner.Type,
-			)
 			return onExpr, onExprPlanning, leftOutCols, rightOutCols, err
 		}
 
@@ -551,8 +579,7 @@ func NewColOperator(
 			mergeJoinerMemAccount := streamingMemAccount
 			if !result.IsStreaming && !useStreamingMemAccountForBuffering {
 				// Whether the merge joiner is streaming is already set above.
-				result.createBufferingMemAccount(ctx, flowCtx, "merge-joiner-limited")
-				mergeJoinerMemAccount = result.BufferingOpMemAccount
+				mergeJoinerMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "merge-joiner-limited")
 			}
 			result.Op, err = NewMergeJoinOp(
 				NewAllocator(ctx, mergeJoinerMemAccount),
@@ -636,35 +663,60 @@ func NewColOperator(
 			if useStreamingMemAccountForBuffering {
 				sortChunksMemAccount = streamingMemAccount
 			} else {
-				result.createBufferingMemAccount(ctx, flowCtx, "sort-chunks-limited")
-				sortChunksMemAccount = result.BufferingOpMemAccount
+				sortChunksMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "sort-chunks-limited")
 			}
 			result.Op, err = NewSortChunks(
 				NewAllocator(ctx, sortChunksMemAccount), input, inputTypes,
 				orderingCols, int(matchLen),
 			)
+			if _, ok := result.Op.(bufferingInMemoryOperator); ok && err == nil &&
+				!useStreamingMemAccountForBuffering {
+				// A chunk that doesn't fit in memory is sorted, along with the rest
+				// of the input, by an external sorter.
+				result.Op = result.maybeWrapWithExternalSorter(
+					ctx, flowCtx, result.Op, input, inputTypes, orderingCols, 0 /* k */, sortChunksMemAccount,
+				)
+			}
 		} else if post.Limit != 0 && post.Filter.Empty() && post.Limit+post.Offset < math.MaxUint16 {
 			// There is a limit specified with no post-process filter, so we know
 			// exactly how many rows the sorter should output. Choose a top K sorter,
 			// which uses a heap to avoid storing more rows than necessary.
+			var topKMemAccount *mon.BoundAccount
+			if useStreamingMemAccountForBuffering {
+				topKMemAccount = streamingMemAccount
+			} else {
+				topKMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "topk-sort-limited")
+			}
 			k := uint16(post.Limit + post.Offset)
 			result.Op = NewTopKSorter(
-				NewAllocator(ctx, streamingMemAccount), input, inputTypes,
+				NewAllocator(ctx, topKMemAccount), input, inputTypes,
 				orderingCols, k,
 			)
+			// The memory used by the top K sorter is bounded by K.
 			result.IsStreaming = true
+			if !useStreamingMemAccountForBuffering {
+				// If K rows don't fit in memory, the input is sorted by an external
+				// sorter that only emits the first K rows.
+				result.Op = result.maybeWrapWithExternalSorter(
+					ctx, flowCtx, result.Op, input, inputTypes, orderingCols, uint64(k), topKMemAccount,
+				)
+			}
 		} else {
 			// No optimizations possible. Default to the standard sort operator.
 			var sorterMemAccount *mon.BoundAccount
 			if useStreamingMemAccountForBuffering {
 				sorterMemAccount = streamingMemAccount
 			} else {
-				result.createBufferingMemAccount(ctx, flowCtx, "sort-all-limited")
-				sorterMemAccount = result.BufferingOpMemAccount
+				sorterMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "sort-all-limited")
 			}
 			result.Op, err = NewSorter(
 				NewAllocator(ctx, sorterMemAccount), input, inputTypes, orderingCols,
 			)
+			if err == nil && !useStreamingMemAccountForBuffering {
+				result.Op = result.maybeWrapWithExternalSorter(
+					ctx, flowCtx, result.Op, input, inputTypes, orderingCols, 0 /* k */, sorterMemAccount,
+				)
+			}
 		}
 		result.ColumnTypes = spec.Input[0].ColumnTypes
 
@@ -672,87 +724,135 @@ func NewColOperator(
 		if err := checkNumIn(inputs, 1); err != nil {
 			return result, err
 		}
-		if len(core.Windower.WindowFns) != 1 {
-			return result, errors.Newf("only a single window function is currently supported")
-		}
-		wf := core.Windower.WindowFns[0]
-		if wf.Frame != nil &&
-			(wf.Frame.Mode != execinfrapb.WindowerSpec_Frame_RANGE ||
-				wf.Frame.Bounds.Start.BoundType != execinfrapb.WindowerSpec_Frame_UNBOUNDED_PRECEDING ||
-				(wf.Frame.Bounds.End != nil && wf.Frame.Bounds.End.BoundType != execinfrapb.WindowerSpec_Frame_CURRENT_ROW)) {
-			return result, errors.Newf("window functions with non-default window frames are not supported")
-		}
-		if wf.Func.AggregateFunc != nil {
-			return result, errors.Newf("aggregate functions used as window functions are not supported")
+		// Every buffering operator of the windower has its own limited memory
+		// account so that it is only constrained by its own memory usage.
+		windowerMemAccount := func(name string) *mon.BoundAccount {
+			if useStreamingMemAccountForBuffering {
+				return streamingMemAccount
+			}
+			return result.createBufferingMemAccount(ctx, flowCtx, name)
 		}
-
 		input := inputs[0]
-		var typs []coltypes.T
-		typs, err = typeconv.FromColumnTypes(spec.Input[0].ColumnTypes)
-		if err != nil {
-			return result, err
-		}
-		tempPartitionColOffset, partitionColIdx := 0, -1
-		if len(core.Windower.PartitionBy) > 0 {
-			// TODO(yuzefovich): add support for hashing partitioner (probably by
-			// leveraging hash routers once we can distribute). The decision about
-			// which kind of partitioner to use should come from the optimizer.
-			windowSortingPartitionerMemAccount := streamingMemAccount
-			if !useStreamingMemAccountForBuffering {
-				result.createBufferingMemAccount(ctx, flowCtx, "window-sorting-partitioner-limited")
-				windowSortingPartitionerMemAccount = result.BufferingOpMemAccount
+		columnTypes := make([]types.T, len(spec.Input[0].ColumnTypes), len(spec.Input[0].ColumnTypes)+len(core.Windower.WindowFns))
+		copy(columnTypes, spec.Input[0].ColumnTypes)
+		// Every window function is computed by its own chain of operators,
+		// each of which appends the output column of its window function.
+		for wfIdx := range core.Windower.WindowFns {
+			wf := &core.Windower.WindowFns[wfIdx]
+			if int(wf.OutputColIdx) != len(columnTypes) {
+				return result, errors.AssertionFailedf(
+					"unexpected output column index %d of window function %s", wf.OutputColIdx, wf.Func.String(),
+				)
 			}
-			input, err = NewWindowSortingPartitioner(
-				NewAllocator(ctx, windowSortingPartitionerMemAccount), input, typs,
-				core.Windower.PartitionBy, wf.Ordering.Columns, int(wf.OutputColIdx),
-			)
-			tempPartitionColOffset, partitionColIdx = 1, int(wf.OutputColIdx)
-		} else {
-			if len(wf.Ordering.Columns) > 0 {
-				windowSorterMemAccount := streamingMemAccount
+			var typs []coltypes.T
+			typs, err = typeconv.FromColumnTypes(columnTypes)
+			if err != nil {
+				return result, err
+			}
+			// The input is sorted on the PARTITION BY clause first and on the
+			// ORDER BY clause second, so that every partition is contiguous and
+			// ordered.
+			sortCols := make([]execinfrapb.Ordering_Column, 0, len(core.Windower.PartitionBy)+len(wf.Ordering.Columns))
+			for _, idx := range core.Windower.PartitionBy {
+				sortCols = append(sortCols, execinfrapb.Ordering_Column{ColIdx: idx})
+			}
+			sortCols = append(sortCols, wf.Ordering.Columns...)
+			// TODO(yuzefovich): when both PARTITION BY and ORDER BY clauses are
+			// omitted, the window function operator is actually streaming.
+			if len(sortCols) > 0 {
+				sorterMemAccount := windowerMemAccount("window-sorter-limited")
+				var sorter Operator
+				sorter, err = NewSorter(NewAllocator(ctx, sorterMemAccount), input, typs, sortCols)
+				if err != nil {
+					return result, err
+				}
 				if !useStreamingMemAccountForBuffering {
-					result.createBufferingMemAccount(ctx, flowCtx, "window-sorter-limited")
-					windowSorterMemAccount = result.BufferingOpMemAccount
+					sorter = result.maybeWrapWithExternalSorter(
+						ctx, flowCtx, sorter, input, typs, sortCols, 0 /* k */, sorterMemAccount,
+					)
 				}
-				input, err = NewSorter(
-					NewAllocator(ctx, windowSorterMemAccount), input, typs,
-					wf.Ordering.Columns,
+				input = sorter
+			}
+			tempPartitionColOffset, partitionColIdx := 0, -1
+			if len(core.Windower.PartitionBy) > 0 {
+				// TODO(yuzefovich): add support for hashing partitioner (probably by
+				// leveraging hash routers once we can distribute). The decision about
+				// which kind of partitioner to use should come from the optimizer.
+				input, err = NewWindowPartitioner(
+					NewAllocator(ctx, streamingMemAccount), input, typs,
+					core.Windower.PartitionBy, int(wf.OutputColIdx),
 				)
+				if err != nil {
+					return result, err
+				}
+				tempPartitionColOffset, partitionColIdx = 1, int(wf.OutputColIdx)
 			}
-			// TODO(yuzefovich): when both PARTITION BY and ORDER BY clauses are
-			// omitted, the window function operator is actually streaming.
-		}
-		if err != nil {
-			return result, err
-		}
 
-		orderingCols := make([]uint32, len(wf.Ordering.Columns))
-		for i, col := range wf.Ordering.Columns {
-			orderingCols[i] = col.ColIdx
-		}
-		switch *wf.Func.WindowFunc {
-		case execinfrapb.WindowerSpec_ROW_NUMBER:
-			result.Op = NewRowNumberOperator(NewAllocator(ctx, streamingMemAccount), input, int(wf.OutputColIdx)+tempPartitionColOffset, partitionColIdx)
-		case execinfrapb.WindowerSpec_RANK:
-			result.Op, err = NewRankOperator(NewAllocator(ctx, streamingMemAccount), input, typs, false /* dense */, orderingCols, int(wf.OutputColIdx)+tempPartitionColOffset, partitionColIdx)
-		case execinfrapb.WindowerSpec_DENSE_RANK:
-			result.Op, err = NewRankOperator(NewAllocator(ctx, streamingMemAccount), input, typs, true /* dense */, orderingCols, int(wf.OutputColIdx)+tempPartitionColOffset, partitionColIdx)
-		default:
-			return result, errors.Newf("window function %s is not supported", wf.String())
-		}
+			orderingCols := make([]uint32, len(wf.Ordering.Columns))
+			for i, col := range wf.Ordering.Columns {
+				orderingCols[i] = col.ColIdx
+			}
+			outputColIdx := int(wf.OutputColIdx) + tempPartitionColOffset
+			outputType := types.Int
+			// The ranking functions don't depend on the window frame and are
+			// computed in a streaming fashion. All of the other window functions
+			// buffer the partitions.
+			switch {
+			case wf.Func.WindowFunc != nil && *wf.Func.WindowFunc == execinfrapb.WindowerSpec_ROW_NUMBER:
+				input = NewRowNumberOperator(NewAllocator(ctx, streamingMemAccount), input, outputColIdx, partitionColIdx)
+			case wf.Func.WindowFunc != nil && *wf.Func.WindowFunc == execinfrapb.WindowerSpec_RANK:
+				input, err = NewRankOperator(NewAllocator(ctx, streamingMemAccount), input, typs, false /* dense */, orderingCols, outputColIdx, partitionColIdx)
+			case wf.Func.WindowFunc != nil && *wf.Func.WindowFunc == execinfrapb.WindowerSpec_DENSE_RANK:
+				input, err = NewRankOperator(NewAllocator(ctx, streamingMemAccount), input, typs, true /* dense */, orderingCols, outputColIdx, partitionColIdx)
+			default:
+				argTypes := make([]types.T, len(wf.ArgsIdxs))
+				for i, argIdx := range wf.ArgsIdxs {
+					argTypes[i] = columnTypes[argIdx]
+				}
+				_, outputType, err = execinfrapb.GetWindowFunctionInfo(wf.Func, argTypes...)
+				if err != nil {
+					return result, err
+				}
+				bufferedWindowMemAccount := windowerMemAccount("buffered-window-limited")
+				var bufferedWindow Operator
+				bufferedWindow, err = NewBufferedWindowOperator(
+					NewAllocator(ctx, bufferedWindowMemAccount), NewAllocator(ctx, streamingMemAccount),
+					flowCtx.NewEvalCtx(), input, columnTypes, wf, partitionColIdx, outputColIdx,
+				)
+				if err != nil {
+					return result, err
+				}
+				// The window function might have to release the memory of its
+				// aggregate.
+				result.ToClose = append(result.ToClose, bufferedWindow.(Closer))
+				if !useStreamingMemAccountForBuffering {
+					bufferedWindow, err = result.maybeWrapWithWindowDiskSpiller(
+						ctx, flowCtx, bufferedWindow, input, columnTypes, wf, partitionColIdx,
+						outputColIdx, streamingMemAccount, bufferedWindowMemAccount,
+					)
+					if err != nil {
+						return result, err
+					}
+				}
+				input = bufferedWindow
+			}
+			if err != nil {
+				return result, err
+			}
 
-		if partitionColIdx != -1 {
-			// Window partitioner will append a temporary column to the batch which
-			// we want to project out.
-			projection := make([]uint32, 0, wf.OutputColIdx+1)
-			for i := uint32(0); i < wf.OutputColIdx; i++ {
-				projection = append(projection, i)
+			if partitionColIdx != -1 {
+				// Window partitioner will append a temporary column to the batch which
+				// we want to project out.
+				projection := make([]uint32, 0, wf.OutputColIdx+1)
+				for i := uint32(0); i < wf.OutputColIdx; i++ {
+					projection = append(projection, i)
+				}
+				projection = append(projection, wf.OutputColIdx+1)
+				input = NewSimpleProjectOp(input, int(wf.OutputColIdx+1), projection)
 			}
-			projection = append(projection, wf.OutputColIdx+1)
-			result.Op = NewSimpleProjectOp(result.Op, int(wf.OutputColIdx+1), projection)
+			columnTypes = append(columnTypes, *outputType)
 		}
-
-		result.ColumnTypes = append(spec.Input[0].ColumnTypes, *types.Int)
+		result.Op, result.ColumnTypes = input, columnTypes
 
 	default:
 		return result, errors.Newf("unsupported processor core %q", core)
@@ -972,16 +1072,163 @@ func (p *filterPlanningState) projectOutExtraCols(result *NewColOperatorResult)
 }
 
 // createBufferingMemAccount instantiates a memory monitor and a memory account
-// to be used with a buffering Operator. The receiver is updated to have
-// references to both objects.
+// to be used with a buffering Operator and returns the account. The receiver
+// is updated to have references to both objects.
 func (r *NewColOperatorResult) createBufferingMemAccount(
 	ctx context.Context, flowCtx *execinfra.FlowCtx, name string,
-) {
-	r.BufferingOpMemMonitor = execinfra.NewLimitedMonitor(
+) *mon.BoundAccount {
+	bufferingOpMemMonitor := execinfra.NewLimitedMonitor(
 		ctx, flowCtx.EvalCtx.Mon, flowCtx.Cfg, name,
 	)
-	bufferingMemAccount := r.BufferingOpMemMonitor.MakeBoundAccount()
-	r.BufferingOpMemAccount = &bufferingMemAccount
+	r.BufferingOpMemMonitors = append(r.BufferingOpMemMonitors, bufferingOpMemMonitor)
+	bufferingMemAccount := bufferingOpMemMonitor.MakeBoundAccount()
+	r.BufferingOpMemAccounts = append(r.BufferingOpMemAccounts, &bufferingMemAccount)
+	return &bufferingMemAccount
+}
+
+// diskSpillingEnabled returns whether the buffering operators can spill batches
+// of the given types to the temporary storage.
+func diskSpillingEnabled(flowCtx *execinfra.FlowCtx, typs ...[]coltypes.T) bool {
+	if flowCtx.Cfg == nil || flowCtx.Cfg.TempStoragePath == "" || flowCtx.Cfg.DiskMonitor == nil {
+		return false
+	}
+	for _, t := range typs {
+		if !canSpillToDisk(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// maybeWrapWithExternalSorter wraps inMemoryOp, an in-memory sorter of input
+// that uses inMemoryAcc, with a diskSpiller that hands the input over to an
+// external sorter if the memory limit is reached, and returns the spiller. If k
+// is non-zero, only the first k tuples are emitted by the external sorter.
+// inMemoryOp is returned unchanged if the input can't be spilled to disk.
+func (r *NewColOperatorResult) maybeWrapWithExternalSorter(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	inMemoryOp Operator,
+	input Operator,
+	inputTypes []coltypes.T,
+	orderingCols []execinfrapb.Ordering_Column,
+	k uint64,
+	inMemoryAcc *mon.BoundAccount,
+) Operator {
+	if !diskSpillingEnabled(flowCtx, inputTypes) ||
+		!execinfra.SettingUseTempStorageSorts.Get(&flowCtx.Cfg.Settings.SV) {
+		return inMemoryOp
+	}
+	diskAcc := flowCtx.Cfg.DiskMonitor.MakeBoundAccount()
+	memoryLimit := execinfra.GetWorkMemLimit(flowCtx.Cfg)
+	spiller := newDiskSpiller(
+		[]Operator{input}, inMemoryOp.(bufferingInMemoryOperator), inMemoryAcc, &diskAcc,
+		func(inputs []Operator) Operator {
+			// The external sorter has its own limited monitor, which it stops when
+			// it is closed.
+			memMonitor := execinfra.NewLimitedMonitor(
+				ctx, flowCtx.EvalCtx.Mon, flowCtx.Cfg, "external-sorter-limited",
+			)
+			externalSorter, err := newExternalSorter(
+				ctx, memMonitor, inputs[0], inputTypes, orderingCols, k,
+				memoryLimit, flowCtx.Cfg.TempStoragePath, &diskAcc,
+			)
+			if err != nil {
+				memMonitor.Stop(ctx)
+				execerror.VectorizedInternalPanic(err)
+			}
+			return externalSorter
+		},
+	)
+	r.SpillsToDisk = true
+	r.ToClose = append(r.ToClose, spiller)
+	return spiller
+}
+
+// maybeWrapWithWindowDiskSpiller wraps inMemoryOp, a buffered window operator
+// of input that uses inMemoryAcc, with a diskSpiller that hands the partition
+// being buffered, along with the rest of the input, over to a window operator
+// that buffers its partitions in the temporary storage if the memory limit is
+// reached, and returns the spiller. inMemoryOp is returned unchanged if the
+// input can't be spilled to disk.
+func (r *NewColOperatorResult) maybeWrapWithWindowDiskSpiller(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	inMemoryOp Operator,
+	input Operator,
+	inputTypes []types.T,
+	wf *execinfrapb.WindowerSpec_WindowFn,
+	partitionColIdx int,
+	outputColIdx int,
+	streamingMemAccount *mon.BoundAccount,
+	inMemoryAcc *mon.BoundAccount,
+) (Operator, error) {
+	bufferTypes, err := windowBufferTypes(inputTypes, partitionColIdx)
+	if err != nil {
+		return nil, err
+	}
+	if !diskSpillingEnabled(flowCtx, bufferTypes) {
+		return inMemoryOp, nil
+	}
+	diskAcc := flowCtx.Cfg.DiskMonitor.MakeBoundAccount()
+	spiller := newDiskSpiller(
+		[]Operator{input}, inMemoryOp.(bufferingInMemoryOperator), inMemoryAcc, &diskAcc,
+		func(inputs []Operator) Operator {
+			diskBackedOp, err := newDiskBackedWindowOperator(
+				NewAllocator(ctx, streamingMemAccount), flowCtx.NewEvalCtx(), inputs[0], inputTypes,
+				wf, partitionColIdx, outputColIdx, flowCtx.Cfg.TempStoragePath, &diskAcc,
+			)
+			if err != nil {
+				execerror.VectorizedInternalPanic(err)
+			}
+			return diskBackedOp
+		},
+	)
+	r.SpillsToDisk = true
+	r.ToClose = append(r.ToClose, spiller)
+	return spiller, nil
+}
+
+// maybeWrapWithHashBasedDiskSpiller wraps r.Op, which must have been created
+// by newInMemoryOp with the most recently created buffering memory account,
+// with a diskSpiller that
+// partitions the inputs on hashCols if the memory limit is reached. r.Op is
+// left unchanged if the inputs can't be spilled to disk.
+func (r *NewColOperatorResult) maybeWrapWithHashBasedDiskSpiller(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	inputs []Operator,
+	inputTypes [][]coltypes.T,
+	hashCols [][]uint32,
+	streamingMemAccount *mon.BoundAccount,
+	newInMemoryOp func(allocator *Allocator, inputs []Operator) (Operator, error),
+) {
+	if !diskSpillingEnabled(flowCtx, inputTypes...) {
+		return
+	}
+	memMonitor := r.BufferingOpMemMonitors[len(r.BufferingOpMemMonitors)-1]
+	inMemoryAcc := r.BufferingOpMemAccounts[len(r.BufferingOpMemAccounts)-1]
+	diskAcc := flowCtx.Cfg.DiskMonitor.MakeBoundAccount()
+	args := &hashBasedSpillingArgs{
+		unlimitedAllocator: NewAllocator(ctx, streamingMemAccount),
+		inputTypes:         inputTypes,
+		hashCols:           hashCols,
+		memMonitor:         memMonitor,
+		tempDir:            flowCtx.Cfg.TempStoragePath,
+		diskAcc:            &diskAcc,
+		newInMemoryOp: func(allocator *Allocator, inputs []Operator) (bufferingInMemoryOperator, error) {
+			op, err := newInMemoryOp(allocator, inputs)
+			if err != nil {
+				return nil, err
+			}
+			return op.(bufferingInMemoryOperator), nil
+		},
+	}
+	spiller := newHashBasedDiskSpiller(
+		args, inputs, r.Op.(bufferingInMemoryOperator), inMemoryAcc, &diskAcc, 0, /* level */
+	)
+	r.Op, r.SpillsToDisk = spiller, true
+	r.ToClose = append(r.ToClose, spiller)
 }
 
 // setProjectedByJoinerColumnTypes sets column types on r according to a
@@ -1112,6 +1359,19 @@ func planSelectionOperators(
 		op = NewBoolVecToSelOp(op, resultIdx)
 		return op, resultIdx, ct, internalMemUsed, err
 	case *tree.ComparisonExpr:
+		if needsDatumExprProjection(evalCtx, t) {
+			// The comparisons that are evaluated on datums don't have a selection
+			// form, so we plan a projection and then convert the resulting boolean
+			// to a selection vector.
+			op, resultIdx, ct, internalMemUsed, err = planDatumExprProjection(
+				ctx, evalCtx, t, columnTypes, input, acc,
+			)
+			if err != nil {
+				return nil, resultIdx, ct, internalMemUsed, err
+			}
+			op = NewBoolVecToSelOp(op, resultIdx)
+			return op, resultIdx, ct, internalMemUsed, err
+		}
 		cmpOp := t.Operator
 		leftOp, leftIdx, ct, internalMemUsedLeft, err := planProjectionOperators(
 			ctx, evalCtx, t.TypedLeft(), columnTypes, input, acc,
@@ -1202,10 +1462,19 @@ func planProjectionOperators(
 	case *tree.IndexedVar:
 		return input, t.Idx, columnTypes, internalMemUsed, nil
 	case *tree.ComparisonExpr:
+		if needsDatumExprProjection(evalCtx, t) {
+			return planDatumExprProjection(ctx, evalCtx, t, columnTypes, input, acc)
+		}
 		return planProjectionExpr(ctx, evalCtx, t.Operator, t.ResolvedType(), t.TypedLeft(), t.TypedRight(), columnTypes, input, acc)
 	case *tree.BinaryExpr:
+		if needsDatumExprProjection(evalCtx, t) {
+			return planDatumExprProjection(ctx, evalCtx, t, columnTypes, input, acc)
+		}
 		return planProjectionExpr(ctx, evalCtx, t.Operator, t.ResolvedType(), t.TypedLeft(), t.TypedRight(), columnTypes, input, acc)
 	case *tree.CastExpr:
+		if needsDatumExprProjection(evalCtx, t) {
+			return planDatumExprProjection(ctx, evalCtx, t, columnTypes, input, acc)
+		}
 		expr := t.Expr.(tree.TypedExpr)
 		// If the expression is NULL, we use planTypedMaybeNullProjectionOperators instead of planProjectionOperators
 		// because we can say that the type of the NULL is the type that we are casting to, rather than unknown.
@@ -1447,6 +1716,125 @@ func planProjectionExpr(
 	return op, resultIdx, ct, internalMemUsed, err
 }
 
+// needsDatumExprProjection returns whether expr, which is a binary, comparison
+// or cast expression, has to be evaluated row by row on datums. This is the
+// case when there are no templated operators for the types of its arguments
+// (JSONB and arrays are only supported by the templated comparisons), as well
+// as when its result depends on the session, which the templated operators
+// don't take into account.
+func needsDatumExprProjection(evalCtx *tree.EvalContext, expr tree.TypedExpr) bool {
+	physType := func(e tree.Expr) coltypes.T {
+		return typeconv.FromColumnType(e.(tree.TypedExpr).ResolvedType())
+	}
+	switch t := expr.(type) {
+	case *tree.CastExpr:
+		if t.Expr.(tree.TypedExpr).ResolvedType().Family() == types.UnknownFamily {
+			return false
+		}
+		// There are no templated casts from timestamps, nor from or to the types
+		// that are stored as datums or depend on the session time zone.
+		from, to := physType(t.Expr), typeconv.FromColumnType(t.Type)
+		return from == coltypes.Timestamp || from == coltypes.TimestampTZ || from == coltypes.Datum ||
+			to == coltypes.TimestampTZ || to == coltypes.Datum
+	case *tree.BinaryExpr:
+		left, right := physType(t.Left), physType(t.Right)
+		if left == coltypes.Datum || right == coltypes.Datum || physType(t) == coltypes.Datum {
+			return true
+		}
+		return timestampExprNeedsSession(evalCtx, left, right)
+	case *tree.ComparisonExpr:
+		left, right := physType(t.Left), physType(t.Right)
+		if left == coltypes.Datum || right == coltypes.Datum {
+			switch t.Operator {
+			case tree.IsDistinctFrom, tree.IsNotDistinctFrom:
+				return t.Right != tree.DNull
+			case tree.EQ, tree.NE, tree.LT, tree.LE, tree.GT, tree.GE:
+				return left != right ||
+					!t.TypedLeft().ResolvedType().Equivalent(t.TypedRight().ResolvedType())
+			}
+			return true
+		}
+		return timestampExprNeedsSession(evalCtx, left, right)
+	}
+	return false
+}
+
+// timestampExprNeedsSession returns whether the result of a binary or
+// comparison expression with arguments of the given types depends on the
+// session. TIMESTAMP values are converted to TIMESTAMPTZ in the session time
+// zone, the INTERVAL arithmetic on TIMESTAMPTZ values is done in the session
+// time zone, and the vectorized operators only implement the compatible
+// duration addition mode.
+func timestampExprNeedsSession(evalCtx *tree.EvalContext, left, right coltypes.T) bool {
+	isTimestamp := func(t coltypes.T) bool {
+		return t == coltypes.Timestamp || t == coltypes.TimestampTZ
+	}
+	if isTimestamp(left) && isTimestamp(right) {
+		return left != right
+	}
+	if left == coltypes.Interval {
+		left, right = right, left
+	}
+	if right != coltypes.Interval {
+		return false
+	}
+	switch left {
+	case coltypes.TimestampTZ:
+		return true
+	case coltypes.Timestamp:
+		return evalCtx.GetAdditionMode() != duration.AdditionModeCompatible
+	}
+	return false
+}
+
+// planDatumExprProjection plans a projection that evaluates expr, which is a
+// binary, comparison or cast expression, row by row on datums (see
+// needsDatumExprProjection).
+func planDatumExprProjection(
+	ctx context.Context,
+	evalCtx *tree.EvalContext,
+	expr tree.TypedExpr,
+	columnTypes []types.T,
+	input Operator,
+	acc *mon.BoundAccount,
+) (op Operator, resultIdx int, ct []types.T, internalMemUsed int, err error) {
+	resultIdx = -1
+	var args []tree.TypedExpr
+	switch t := expr.(type) {
+	case *tree.BinaryExpr:
+		args = []tree.TypedExpr{t.TypedLeft(), t.TypedRight()}
+	case *tree.ComparisonExpr:
+		args = []tree.TypedExpr{t.TypedLeft(), t.TypedRight()}
+	case *tree.CastExpr:
+		args = []tree.TypedExpr{t.Expr.(tree.TypedExpr)}
+	default:
+		return nil, resultIdx, nil, internalMemUsed, errors.Errorf("unhandled datum expression type: %s", reflect.TypeOf(t))
+	}
+	ct = columnTypes
+	op = input
+	// The constant arguments are evaluated by the expression itself, so they
+	// don't need a column.
+	argumentCols := make([]int, len(args))
+	for i, arg := range args {
+		if _, ok := arg.(tree.Datum); ok {
+			argumentCols[i] = -1
+			continue
+		}
+		var argInternalMem int
+		op, argumentCols[i], ct, argInternalMem, err = planProjectionOperators(
+			ctx, evalCtx, arg, ct, op, acc,
+		)
+		if err != nil {
+			return nil, resultIdx, nil, internalMemUsed, err
+		}
+		internalMemUsed += argInternalMem
+	}
+	resultIdx = len(ct)
+	ct = append(ct, *expr.ResolvedType())
+	op, err = newDatumExprProjOp(NewAllocator(ctx, acc), evalCtx, expr, ct, argumentCols, resultIdx, op)
+	return op, resultIdx, ct, internalMemUsed, err
+}
+
 // planLogicalProjectionOp plans all the needed operators for a projection of
 // a logical operation (either AND or OR).
 func planLogicalProjectionOp(
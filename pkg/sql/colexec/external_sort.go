@@ -0,0 +1,338 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+// externalSorterMaxMergeFanIn is the maximum number of sorted runs that the
+// external sorter merges at once. If there are more runs, they are first
+// merged into bigger runs, since every run being merged needs a batch in
+// memory.
+const externalSorterMaxMergeFanIn = 16
+
+// externalSorterState represents the state of the external sorter.
+type externalSorterState int
+
+const (
+	// externalSorterSpooling is the initial state of the external sorter, in
+	// which it sorts chunks of its input that fit in memory and writes them to
+	// the temporary storage as sorted runs.
+	externalSorterSpooling externalSorterState = iota
+	// externalSorterMerging is the state in which the external sorter merges the
+	// sorted runs until there are few enough of them to be merged at once.
+	externalSorterMerging
+	// externalSorterEmitting is the state in which the external sorter emits the
+	// result of the final merge.
+	externalSorterEmitting
+)
+
+// externalSorter is an Operator that sorts an input that doesn't fit in memory.
+// The input is divided into chunks whose size is about half of the memory
+// limit. Every chunk is sorted in memory and written to a diskQueue, and the
+// resulting sorted runs are then merged with an OrderedSynchronizer.
+//
+// The memory used by the sorter is accounted for by its own limited monitor:
+// the in-memory sorter of the chunks uses one account, which is released once
+// all the chunks have been sorted, and the merges use another one.
+type externalSorter struct {
+	OneInputNode
+	NonExplainable
+
+	typs         []coltypes.T
+	orderingCols []execinfrapb.Ordering_Column
+	// k, if non-zero, is the number of tuples to emit (this is used when a top K
+	// sorter spills to disk).
+	k       uint64
+	emitted uint64
+	tempDir string
+	diskAcc *mon.BoundAccount
+
+	memMonitor *mon.BytesMonitor
+	sortAcc    mon.BoundAccount
+	mergeAcc   mon.BoundAccount
+	// mergeAllocator is used by the merges and the queues.
+	mergeAllocator *Allocator
+	// maxMergeFanIn is the maximum number of runs that are merged at once.
+	maxMergeFanIn int
+
+	inputPartitioner *inputPartitioningOperator
+	sorter           resettableOperator
+
+	state externalSorterState
+	// runs are the sorted runs that haven't been merged yet.
+	runs   []*diskQueue
+	merger Operator
+	// closed contains the queues that are being read by the merger, which are
+	// closed once the merger is exhausted or when the sorter is closed.
+	closed []*diskQueue
+	// done is set once the sorter is closed.
+	done bool
+}
+
+var _ Operator = &externalSorter{}
+var _ Closer = &externalSorter{}
+
+// newExternalSorter returns a disk-backed sorter. Its memory is accounted for
+// by memMonitor, which must be limited to memoryLimit, and which is stopped
+// when the sorter is closed. The sorted runs are stored in files of tempDir,
+// which are accounted for by diskAcc. If k is non-zero, only the first k
+// tuples of the sorted input are emitted.
+func newExternalSorter(
+	ctx context.Context,
+	memMonitor *mon.BytesMonitor,
+	input Operator,
+	inputTypes []coltypes.T,
+	orderingCols []execinfrapb.Ordering_Column,
+	k uint64,
+	memoryLimit int64,
+	tempDir string,
+	diskAcc *mon.BoundAccount,
+) (Operator, error) {
+	s := &externalSorter{
+		OneInputNode: NewOneInputNode(input),
+		typs:         inputTypes,
+		orderingCols: orderingCols,
+		k:            k,
+		tempDir:      tempDir,
+		diskAcc:      diskAcc,
+		memMonitor:   memMonitor,
+		sortAcc:      memMonitor.MakeBoundAccount(),
+		mergeAcc:     memMonitor.MakeBoundAccount(),
+	}
+	s.mergeAllocator = NewAllocator(ctx, &s.mergeAcc)
+	// Every run being merged needs a batch in memory, in addition to the
+	// output batch of the merge, and they must fit in the half of the memory
+	// that isn't used by the in-memory sorter.
+	batchSize := int64(estimateBatchSizeBytes(inputTypes, int(coldata.BatchSize())))
+	s.maxMergeFanIn = int(memoryLimit/2/batchSize) - 1
+	if s.maxMergeFanIn < 2 {
+		s.maxMergeFanIn = 2
+	} else if s.maxMergeFanIn > externalSorterMaxMergeFanIn {
+		s.maxMergeFanIn = externalSorterMaxMergeFanIn
+	}
+	s.inputPartitioner = newInputPartitioningOperator(input, inputTypes, memoryLimit/2)
+	sortAllocator := NewAllocator(ctx, &s.sortAcc)
+	sorter, err := newSorter(
+		sortAllocator, newAllSpooler(sortAllocator, s.inputPartitioner, inputTypes), inputTypes,
+		orderingCols,
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.sorter = sorter
+	return s, nil
+}
+
+func (s *externalSorter) Init() {
+	s.sorter.Init()
+}
+
+func (s *externalSorter) Next(ctx context.Context) coldata.Batch {
+	for {
+		switch s.state {
+		case externalSorterSpooling:
+			s.spoolRuns(ctx)
+			// The in-memory sorter isn't needed anymore.
+			s.sorter = nil
+			s.sortAcc.Close(ctx)
+			s.state = externalSorterMerging
+		case externalSorterMerging:
+			if len(s.runs) <= s.maxMergeFanIn {
+				s.merger = s.newMerger(s.runs)
+				s.runs = nil
+				s.state = externalSorterEmitting
+				continue
+			}
+			merged := s.newQueue()
+			s.writeRun(ctx, merged, s.newMerger(s.runs[:s.maxMergeFanIn]))
+			s.closeQueues(ctx)
+			s.runs = append(s.runs[s.maxMergeFanIn:], merged)
+		case externalSorterEmitting:
+			if s.k > 0 && s.emitted >= s.k {
+				s.closeQueues(ctx)
+				return zeroBatch
+			}
+			batch := s.merger.Next(ctx)
+			if s.k > 0 && s.emitted+uint64(batch.Length()) > s.k {
+				batch.SetLength(uint16(s.k - s.emitted))
+			}
+			s.emitted += uint64(batch.Length())
+			if batch.Length() == 0 {
+				s.closeQueues(ctx)
+			}
+			return batch
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unexpected externalSorterState %d", s.state))
+			// This code is unreachable, but the compiler cannot infer that.
+			return nil
+		}
+	}
+}
+
+// spoolRuns sorts the chunks of the input in memory and writes them as sorted
+// runs.
+func (s *externalSorter) spoolRuns(ctx context.Context) {
+	for {
+		run := s.newQueue()
+		s.writeRun(ctx, run, s.sorter)
+		if run.empty() {
+			s.closeQueue(ctx, run)
+		} else {
+			s.runs = append(s.runs, run)
+		}
+		if s.inputPartitioner.inputDone {
+			return
+		}
+		// This also resets the input partitioner.
+		s.sorter.reset()
+	}
+}
+
+func (s *externalSorter) newQueue() *diskQueue {
+	return newDiskQueue(s.mergeAllocator, s.typs, s.tempDir, s.diskAcc)
+}
+
+// writeRun writes all the batches of the given operator to the queue.
+func (s *externalSorter) writeRun(ctx context.Context, q *diskQueue, op Operator) {
+	for batch := op.Next(ctx); batch.Length() > 0; batch = op.Next(ctx) {
+		if err := q.enqueue(ctx, batch); err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+	}
+	if err := q.finishEnqueueing(ctx); err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+}
+
+// newMerger returns an initialized Operator that merges the given runs. The
+// runs are closed by closeQueues once they have been merged.
+func (s *externalSorter) newMerger(runs []*diskQueue) Operator {
+	readers := make([]Operator, len(runs))
+	for i, run := range runs {
+		readers[i] = newDiskQueueReader(run)
+	}
+	s.closed = append(s.closed, runs...)
+	merger := NewOrderedSynchronizer(
+		s.mergeAllocator, readers, s.typs,
+		execinfrapb.ConvertToColumnOrdering(execinfrapb.Ordering{Columns: s.orderingCols}),
+	)
+	merger.Init()
+	return merger
+}
+
+// closeQueues closes the runs that have been merged.
+func (s *externalSorter) closeQueues(ctx context.Context) {
+	for _, q := range s.closed {
+		s.closeQueue(ctx, q)
+	}
+	s.closed = s.closed[:0]
+}
+
+func (s *externalSorter) closeQueue(ctx context.Context, q *diskQueue) {
+	if err := q.close(ctx); err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+}
+
+// Close implements the Closer interface.
+func (s *externalSorter) Close(ctx context.Context) error {
+	if s.done {
+		return nil
+	}
+	s.done = true
+	var retErr error
+	for _, queues := range [][]*diskQueue{s.runs, s.closed} {
+		for _, q := range queues {
+			if err := q.close(ctx); err != nil && retErr == nil {
+				retErr = err
+			}
+		}
+	}
+	s.runs = nil
+	s.closed = nil
+	s.sortAcc.Close(ctx)
+	s.mergeAcc.Close(ctx)
+	s.memMonitor.Stop(ctx)
+	return retErr
+}
+
+// inputPartitioningOperator is an Operator that passes through the batches of
+// its input until their total size reaches a limit, at which point it emits a
+// zero-length batch. Once reset, it passes through the next batches of the
+// input. This way, an operator that buffers its whole input can be made to
+// process chunks of the input that fit in memory.
+type inputPartitioningOperator struct {
+	OneInputNode
+	NonExplainable
+
+	typs []coltypes.T
+	// limit is the size of the batches after which a partition ends.
+	limit int64
+	// used is the size of the batches emitted in the current partition.
+	used int64
+	// inputDone indicates whether the input has been exhausted.
+	inputDone bool
+}
+
+var _ resettableOperator = &inputPartitioningOperator{}
+
+func newInputPartitioningOperator(
+	input Operator, typs []coltypes.T, limit int64,
+) *inputPartitioningOperator {
+	return &inputPartitioningOperator{
+		OneInputNode: NewOneInputNode(input),
+		typs:         typs,
+		limit:        limit,
+	}
+}
+
+func (o *inputPartitioningOperator) Init() {
+	o.input.Init()
+}
+
+func (o *inputPartitioningOperator) Next(ctx context.Context) coldata.Batch {
+	if o.inputDone || o.used >= o.limit {
+		return zeroBatch
+	}
+	batch := o.input.Next(ctx)
+	if batch.Length() == 0 {
+		o.inputDone = true
+		return batch
+	}
+	o.used += batchSizeBytes(o.typs, batch)
+	return batch
+}
+
+func (o *inputPartitioningOperator) reset() {
+	o.used = 0
+}
+
+// batchSizeBytes returns an estimate of the memory needed to buffer the tuples
+// of the batch.
+func batchSizeBytes(typs []coltypes.T, batch coldata.Batch) int64 {
+	size := int64(estimateBatchSizeBytes(typs, int(batch.Length())))
+	for i, typ := range typs {
+		if typ == coltypes.Bytes {
+			// The estimate doesn't account for the actual size of the values.
+			size += int64(batch.ColVec(i).Bytes().Size())
+		}
+	}
+	return size
+}
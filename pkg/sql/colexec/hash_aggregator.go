@@ -196,8 +196,8 @@ func (op *hashGrouper) Next(ctx context.Context) coldata.Batch {
 	op.batch.ResetInternalBatch()
 	// First, build the hash table.
 	if !op.buildFinished {
-		op.buildFinished = true
 		op.builder.exec(ctx)
+		op.buildFinished = true
 	}
 
 	// The selection vector needs to be populated before any batching can be
@@ -266,6 +266,22 @@ func (op *hashGrouper) Next(ctx context.Context) coldata.Batch {
 	return op.batch
 }
 
+var _ bufferingInMemoryOperator = &orderedAggregator{}
+
+// buffering implements the bufferingInMemoryOperator interface for the
+// orderedAggregator returned by NewHashAggregator, whose input is a
+// hashGrouper. Any other orderedAggregator doesn't buffer its input.
+func (a *orderedAggregator) buffering() bool {
+	g, ok := a.input.(*hashGrouper)
+	return ok && !g.buildFinished
+}
+
+// exportBuffered implements the bufferingInMemoryOperator interface. The
+// hashGrouper has a single input, so the input argument is ignored.
+func (a *orderedAggregator) exportBuffered(Operator) coldata.Batch {
+	return a.input.(*hashGrouper).builder.exportBuffered()
+}
+
 // Reset resets the hashGrouper for another run. Primarily used for
 // benchmarks.
 func (op *hashGrouper) reset() {
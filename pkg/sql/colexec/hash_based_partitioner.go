@@ -0,0 +1,279 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+const (
+	// hashBasedPartitionerNumPartitions is the number of partitions into which
+	// the hashBasedPartitioner divides its inputs.
+	hashBasedPartitionerNumPartitions = 16
+	// hashBasedPartitionerMaxLevel is the maximum number of times that a
+	// partition can be repartitioned because it doesn't fit in memory. A
+	// partition that still doesn't fit in memory at that level, for example
+	// because all of its tuples have the same key, results in an out of memory
+	// error.
+	hashBasedPartitionerMaxLevel = 4
+)
+
+// hashBasedSpillingArgs contains the arguments that are shared by the
+// hashBasedPartitioners of an operator that spills to disk, at every level.
+type hashBasedSpillingArgs struct {
+	// unlimitedAllocator is used for the batches of the partitioner itself.
+	unlimitedAllocator *Allocator
+	inputTypes         [][]coltypes.T
+	// hashCols are the columns of each input on which the tuples are
+	// partitioned. Tuples that the in-memory operator might process together
+	// must have the same values in these columns.
+	hashCols [][]uint32
+	// memMonitor is the limited monitor from which the memory accounts of the
+	// in-memory operators of the partitions are created.
+	memMonitor *mon.BytesMonitor
+	tempDir    string
+	diskAcc    *mon.BoundAccount
+	// newInMemoryOp creates the in-memory operator that processes a partition
+	// of the inputs.
+	newInMemoryOp func(allocator *Allocator, inputs []Operator) (bufferingInMemoryOperator, error)
+}
+
+// newHashBasedDiskSpiller returns a diskSpiller that runs the given in-memory
+// operator and, if its memory limit is reached, divides the inputs into
+// partitions that are processed one at a time by new in-memory operators.
+func newHashBasedDiskSpiller(
+	args *hashBasedSpillingArgs,
+	inputs []Operator,
+	inMemoryOp bufferingInMemoryOperator,
+	inMemoryAcc *mon.BoundAccount,
+	diskAcc *mon.BoundAccount,
+	level int,
+) *diskSpiller {
+	return newDiskSpiller(
+		inputs, inMemoryOp, inMemoryAcc, diskAcc,
+		func(inputs []Operator) Operator {
+			return newHashBasedPartitioner(args, inputs, level)
+		},
+	)
+}
+
+// hashBasedPartitioner is an Operator that divides its inputs into partitions
+// stored in the temporary storage, according to the hash of the key columns of
+// their tuples, and then processes the partitions one at a time with an
+// in-memory operator. This is how the grace hash join and the disk-backed hash
+// aggregation are implemented. The in-memory operator of a partition can itself
+// spill to disk, in which case the partition is repartitioned with a different
+// hash function.
+type hashBasedPartitioner struct {
+	NonExplainable
+
+	args   *hashBasedSpillingArgs
+	inputs []Operator
+	// level is the number of times the inputs have already been partitioned
+	// before they reached this partitioner. It is used to seed the hash
+	// function, so that the tuples of a partition are spread among all the
+	// partitions of the next level.
+	level int
+
+	// ht is only used to hash the key columns.
+	ht         hashTable
+	buckets    []uint64
+	selections [][]uint16
+	// partitions contains the partitions of every input.
+	partitions  [][]*diskQueue
+	partitioned bool
+
+	// partitionIdx is the index of the next partition to process.
+	partitionIdx int
+	// partitionOp is the operator that processes the current partition, and
+	// partitionMemAcc is the memory account of its in-memory operator. The
+	// account is closed along with the partition, so that the memory monitor
+	// can be stopped once the flow is done.
+	partitionOp     Operator
+	partitionMemAcc mon.BoundAccount
+}
+
+var _ Operator = &hashBasedPartitioner{}
+var _ Closer = &hashBasedPartitioner{}
+
+func newHashBasedPartitioner(
+	args *hashBasedSpillingArgs, inputs []Operator, level int,
+) *hashBasedPartitioner {
+	return &hashBasedPartitioner{
+		args:   args,
+		inputs: inputs,
+		level:  level,
+	}
+}
+
+func (p *hashBasedPartitioner) Init() {
+	for _, input := range p.inputs {
+		input.Init()
+	}
+	p.buckets = make([]uint64, coldata.BatchSize())
+	p.selections = make([][]uint16, hashBasedPartitionerNumPartitions)
+	p.partitions = make([][]*diskQueue, len(p.inputs))
+	for i := range p.inputs {
+		p.partitions[i] = make([]*diskQueue, hashBasedPartitionerNumPartitions)
+		for j := range p.partitions[i] {
+			p.partitions[i][j] = newDiskQueue(
+				p.args.unlimitedAllocator, p.args.inputTypes[i], p.args.tempDir, p.args.diskAcc,
+			)
+		}
+	}
+}
+
+func (p *hashBasedPartitioner) Next(ctx context.Context) coldata.Batch {
+	if !p.partitioned {
+		for i := range p.inputs {
+			p.partitionInput(ctx, i)
+		}
+		p.partitioned = true
+	}
+	for {
+		if p.partitionOp == nil && !p.nextPartition(ctx) {
+			return zeroBatch
+		}
+		if batch := p.partitionOp.Next(ctx); batch.Length() > 0 {
+			return batch
+		}
+		p.closePartition(ctx)
+	}
+}
+
+// partitionInput writes the tuples of the ith input to its partitions.
+func (p *hashBasedPartitioner) partitionInput(ctx context.Context, i int) {
+	input, typs, partitions := p.inputs[i], p.args.inputTypes[i], p.partitions[i]
+	for batch := input.Next(ctx); batch.Length() > 0; batch = input.Next(ctx) {
+		n := batch.Length()
+		sel := batch.Selection()
+		// The hash is seeded with the level, so that the partitions of different
+		// levels are independent.
+		for j := uint16(0); j < n; j++ {
+			p.buckets[j] = uint64(p.level) + 2
+		}
+		for keyIdx, colIdx := range p.args.hashCols[i] {
+			p.ht.rehash(ctx, p.buckets, keyIdx, typs[colIdx], batch.ColVec(int(colIdx)), uint64(n), sel)
+		}
+		for j := range p.selections {
+			p.selections[j] = p.selections[j][:0]
+		}
+		for j := uint16(0); j < n; j++ {
+			rowIdx := j
+			if sel != nil {
+				rowIdx = sel[j]
+			}
+			partitionIdx := p.buckets[j] % hashBasedPartitionerNumPartitions
+			p.selections[partitionIdx] = append(p.selections[partitionIdx], rowIdx)
+		}
+		for j, q := range partitions {
+			if err := q.enqueueSel(ctx, batch, p.selections[j]); err != nil {
+				execerror.VectorizedInternalPanic(err)
+			}
+		}
+	}
+	for _, q := range partitions {
+		if err := q.finishEnqueueing(ctx); err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+	}
+}
+
+// nextPartition sets up the operator that processes the next partition whose
+// inputs aren't all empty. It returns false if there are no partitions left.
+func (p *hashBasedPartitioner) nextPartition(ctx context.Context) bool {
+	for ; p.partitionIdx < hashBasedPartitionerNumPartitions; p.partitionIdx++ {
+		readers := make([]Operator, len(p.inputs))
+		empty := true
+		for i := range p.inputs {
+			q := p.partitions[i][p.partitionIdx]
+			empty = empty && q.empty()
+			readers[i] = newDiskQueueReader(q)
+		}
+		if empty {
+			continue
+		}
+		p.partitionMemAcc = p.args.memMonitor.MakeBoundAccount()
+		inMemoryOp, err := p.args.newInMemoryOp(NewAllocator(ctx, &p.partitionMemAcc), readers)
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		if p.level+1 < hashBasedPartitionerMaxLevel {
+			p.partitionOp = newHashBasedDiskSpiller(
+				p.args, readers, inMemoryOp, &p.partitionMemAcc, nil /* diskAcc */, p.level+1,
+			)
+		} else {
+			p.partitionOp = inMemoryOp
+		}
+		p.partitionOp.Init()
+		p.partitionIdx++
+		return true
+	}
+	return false
+}
+
+// closePartition releases the resources of the current partition.
+func (p *hashBasedPartitioner) closePartition(ctx context.Context) {
+	if c, ok := p.partitionOp.(Closer); ok {
+		if err := c.Close(ctx); err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+	}
+	p.partitionOp = nil
+	p.partitionMemAcc.Close(ctx)
+	for i := range p.inputs {
+		if err := p.partitions[i][p.partitionIdx-1].close(ctx); err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+	}
+}
+
+// Close implements the Closer interface.
+func (p *hashBasedPartitioner) Close(ctx context.Context) error {
+	var retErr error
+	if c, ok := p.partitionOp.(Closer); ok {
+		retErr = c.Close(ctx)
+	}
+	if p.partitionOp != nil {
+		p.partitionOp = nil
+		p.partitionMemAcc.Close(ctx)
+	}
+	for i := range p.partitions {
+		for _, q := range p.partitions[i] {
+			if err := q.close(ctx); err != nil && retErr == nil {
+				retErr = err
+			}
+		}
+	}
+	return retErr
+}
+
+// ChildCount implements the execinfra.OpNode interface.
+func (p *hashBasedPartitioner) ChildCount() int {
+	return len(p.inputs)
+}
+
+// Child implements the execinfra.OpNode interface.
+func (p *hashBasedPartitioner) Child(nth int) execinfra.OpNode {
+	if nth < len(p.inputs) {
+		return p.inputs[nth]
+	}
+	execerror.VectorizedInternalPanic(fmt.Sprintf("invalid index %d", nth))
+	// This code is unreachable, but the compiler cannot infer that.
+	return nil
+}
@@ -215,6 +215,7 @@ func (hj *hashJoinEqOp) Child(nth int) execinfra.OpNode {
 }
 
 var _ Operator = &hashJoinEqOp{}
+var _ bufferingInMemoryOperator = &hashJoinEqOp{}
 
 func (hj *hashJoinEqOp) Init() {
 	hj.spec.left.source.Init()
@@ -297,6 +298,18 @@ func (hj *hashJoinEqOp) build(ctx context.Context) {
 	hj.runningState = hjProbing
 }
 
+func (hj *hashJoinEqOp) buffering() bool {
+	return hj.runningState == hjBuilding
+}
+
+func (hj *hashJoinEqOp) exportBuffered(input Operator) coldata.Batch {
+	if input == hj.builder.spec.source {
+		return hj.builder.exportBuffered()
+	}
+	// Only the build side is buffered before the memory limit can be reached.
+	return zeroBatch
+}
+
 func (hj *hashJoinEqOp) emitUnmatched() {
 	// Set all elements in the probe columns of the output batch to null.
 	for i := range hj.prober.spec.outCols {
@@ -615,6 +628,15 @@ type hashJoinBuilder struct {
 	// spec holds the specifications for the source operator used in the build
 	// phase.
 	spec hashJoinerSourceSpec
+
+	// inFlight is the batch that is being loaded into the hashTable. If the
+	// memory limit is reached while loading it, only some of the columns contain
+	// its tuples, so it is exported on its own.
+	inFlight coldata.Batch
+	// exported is the number of loaded tuples exported so far, and exportBatch
+	// is the batch into which they are copied.
+	exported    uint64
+	exportBatch coldata.Batch
 }
 
 func makeHashJoinBuilder(ht *hashTable, spec hashJoinerSourceSpec) *hashJoinBuilder {
@@ -685,8 +707,10 @@ func (builder *hashJoinBuilder) distinctExec(ctx context.Context) {
 			break
 		}
 
+		builder.inFlight = batch
 		builder.ht.loadBatch(batch)
 	}
+	builder.inFlight = nil
 
 	nKeyCols := len(builder.spec.eqCols)
 	keyCols := make([]coldata.Vec, nKeyCols)
@@ -700,6 +724,55 @@ func (builder *hashJoinBuilder) distinctExec(ctx context.Context) {
 	builder.ht.buildNextChains(ctx)
 }
 
+// exportBuffered returns the next batch of the tuples loaded into the
+// hashTable, followed by the batch that was being loaded when the build phase
+// panicked, if any. The batches have the schema of the source, but the columns
+// that the hashTable doesn't store only contain nulls.
+func (builder *hashJoinBuilder) exportBuffered() coldata.Batch {
+	ht := builder.ht
+	if builder.exported < ht.size {
+		if builder.exportBatch == nil {
+			// The export batch is not accounted for, since the memory limit has
+			// already been reached, and the memory of the hashTable is released as
+			// soon as all of its tuples have been exported.
+			builder.exportBatch = coldata.NewMemBatch(builder.spec.sourceTypes)
+		}
+		builder.exportBatch.ResetInternalBatch()
+		end := builder.exported + uint64(coldata.BatchSize())
+		if end > ht.size {
+			end = ht.size
+		}
+		stored := make([]bool, len(builder.spec.sourceTypes))
+		for i, colIdx := range ht.valCols {
+			stored[colIdx] = true
+			builder.exportBatch.ColVec(int(colIdx)).Copy(
+				coldata.CopySliceArgs{
+					SliceArgs: coldata.SliceArgs{
+						ColType:     ht.valTypes[i],
+						Src:         ht.vals[i],
+						SrcStartIdx: builder.exported,
+						SrcEndIdx:   end,
+					},
+				},
+			)
+		}
+		for colIdx := range stored {
+			if !stored[colIdx] {
+				builder.exportBatch.ColVec(colIdx).Nulls().SetNulls()
+			}
+		}
+		builder.exportBatch.SetLength(uint16(end - builder.exported))
+		builder.exported = end
+		return builder.exportBatch
+	}
+	if builder.inFlight != nil {
+		batch := builder.inFlight
+		builder.inFlight = nil
+		return batch
+	}
+	return zeroBatch
+}
+
 // hashJoinProber is used by the hashJoinEqOp during the probe phase. It
 // operates on a single batch of obtained from the probe relation and probes the
 // hashTable to construct the resulting output batch.
@@ -13,10 +13,12 @@ package colexec
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/stretchr/testify/require"
 )
@@ -246,3 +248,20 @@ func TestIntComparison(t *testing.T) {
 	require.Equal(t, true, performGEInt64Int32(i, 2))
 	require.Equal(t, false, performGEInt64Int64(i, 3))
 }
+
+func TestTimestampIntervalArithmetic(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ts := time.Date(2020, time.January, 31, 0, 0, 0, 0, time.UTC)
+	month := duration.MakeDuration(0, 0, 1)
+	// Adding a month to the last day of a month clamps the result to the last
+	// day of the next month, as in the compatible addition mode.
+	require.Equal(t, time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC), performPlusTimestampInterval(ts, month))
+	require.Equal(t, time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC), performPlusIntervalTimestamp(month, ts))
+	march := time.Date(2020, time.March, 31, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC), performMinusTimestampInterval(march, month))
+
+	// The result is rounded to microseconds.
+	nanos := duration.MakeDuration(1600, 0, 0)
+	require.Equal(t, ts.Add(2*time.Microsecond), performPlusTimestampInterval(ts, nanos))
+	require.Equal(t, ts.Add(-2*time.Microsecond), performMinusTimestampInterval(ts, nanos))
+}
@@ -16,32 +16,21 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
-	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 )
 
-// NewWindowSortingPartitioner creates a new exec.Operator that orders input
-// first based on the partitionIdxs columns and second on ordCols (i.e. it
-// handles both PARTITION BY and ORDER BY clauses of a window function) and
-// puts true in partitionColIdx'th column (which is appended if needed) for
-// every tuple that is the first within its partition.
-func NewWindowSortingPartitioner(
+// NewWindowPartitioner creates a new exec.Operator that puts true in
+// partitionColIdx'th column (which is appended if needed) for every tuple that
+// is the first within its partition. The input must be ordered on the
+// partitionIdxs columns (i.e. the PARTITION BY clause of a window function),
+// which is usually done by a sorter that also orders the tuples of every
+// partition according to the ORDER BY clause.
+func NewWindowPartitioner(
 	allocator *Allocator,
 	input Operator,
 	inputTyps []coltypes.T,
 	partitionIdxs []uint32,
-	ordCols []execinfrapb.Ordering_Column,
 	partitionColIdx int,
 ) (op Operator, err error) {
-	partitionAndOrderingCols := make([]execinfrapb.Ordering_Column, len(partitionIdxs)+len(ordCols))
-	for i, idx := range partitionIdxs {
-		partitionAndOrderingCols[i] = execinfrapb.Ordering_Column{ColIdx: idx}
-	}
-	copy(partitionAndOrderingCols[len(partitionIdxs):], ordCols)
-	input, err = NewSorter(allocator, input, inputTyps, partitionAndOrderingCols)
-	if err != nil {
-		return nil, err
-	}
-
 	var distinctCol []bool
 	input, distinctCol, err = OrderedDistinctColsToOperators(input, partitionIdxs, inputTyps)
 	if err != nil {
@@ -19,14 +19,28 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
 // maxVarLen specifies a length limit for variable length types (e.g. byte slices).
 const maxVarLen = 64
 
+// randomTypesPool contains all types for which random values can be generated.
+// coltypes.Datum is omitted since its values are opaque tree.Datums whose SQL
+// type is unknown.
+var randomTypesPool = func() []coltypes.T {
+	typs := make([]coltypes.T, 0, len(coltypes.AllTypes))
+	for _, t := range coltypes.AllTypes {
+		if t != coltypes.Datum {
+			typs = append(typs, t)
+		}
+	}
+	return typs
+}()
+
 func randomType(rng *rand.Rand) coltypes.T {
-	return coltypes.AllTypes[rng.Intn(len(coltypes.AllTypes))]
+	return randomTypesPool[rng.Intn(len(randomTypesPool))]
 }
 
 // randomTypes returns an n-length slice of random coltypes.T.
@@ -119,13 +133,21 @@ func RandomVec(
 		for i := 0; i < n; i++ {
 			floats[i] = rng.Float64()
 		}
-	case coltypes.Timestamp:
+	case coltypes.Timestamp, coltypes.TimestampTZ:
 		timestamps := vec.Timestamp()
+		if typ == coltypes.TimestampTZ {
+			timestamps = vec.TimestampTZ()
+		}
 		for i := 0; i < n; i++ {
 			timestamps[i] = timeutil.Unix(rng.Int63n(1000000), rng.Int63n(1000000))
 			loc := locations[rng.Intn(len(locations))]
 			timestamps[i] = timestamps[i].In(loc)
 		}
+	case coltypes.Interval:
+		intervals := vec.Interval()
+		for i := 0; i < n; i++ {
+			intervals[i] = duration.MakeDuration(rng.Int63(), rng.Int63n(1000), rng.Int63n(1000))
+		}
 	default:
 		execerror.VectorizedInternalPanic(fmt.Sprintf("unhandled type %s", typ))
 	}
@@ -217,8 +239,8 @@ const (
 
 // RandomDataOpArgs are arguments passed in to RandomDataOp. All arguments are
 // optional (refer to the constants above this struct definition for the
-// defaults). Bools are false by default and AvailableTyps defaults to
-// coltypes.AllTypes.
+// defaults). Bools are false by default and AvailableTyps defaults to all
+// types except coltypes.Datum.
 type RandomDataOpArgs struct {
 	// DeterministicTyps, if set, overrides AvailableTyps and MaxSchemaLength,
 	// forcing the RandomDataOp to use this schema.
@@ -261,7 +283,7 @@ type RandomDataOp struct {
 // NewRandomDataOp creates a new RandomDataOp.
 func NewRandomDataOp(allocator *Allocator, rng *rand.Rand, args RandomDataOpArgs) *RandomDataOp {
 	var (
-		availableTyps   = coltypes.AllTypes
+		availableTyps   = randomTypesPool
 		maxSchemaLength = defaultMaxSchemaLength
 		batchSize       = int(coldata.BatchSize())
 		numBatches      = defaultNumBatches
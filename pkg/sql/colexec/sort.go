@@ -101,6 +101,13 @@ type allSpooler struct {
 	spooledTuples uint64
 	// spooled indicates whether spool() has already been called.
 	spooled bool
+
+	// inFlight is the batch that is being appended to values. If the memory
+	// limit is reached while appending it, only some of the columns contain its
+	// tuples, so it is exported on its own.
+	inFlight coldata.Batch
+	// exporter exports the spooled tuples.
+	exporter columnExporter
 }
 
 var _ spooler = &allSpooler{}
@@ -110,6 +117,7 @@ func newAllSpooler(allocator *Allocator, input Operator, inputTypes []coltypes.T
 		OneInputNode: NewOneInputNode(input),
 		allocator:    allocator,
 		inputTypes:   inputTypes,
+		exporter:     columnExporter{typs: inputTypes},
 	}
 }
 
@@ -129,6 +137,7 @@ func (p *allSpooler) spool(ctx context.Context) {
 	batch := p.input.Next(ctx)
 	var nTuples uint64
 	for ; batch.Length() != 0; batch = p.input.Next(ctx) {
+		p.inFlight = batch
 		for i := 0; i < len(p.values); i++ {
 			p.allocator.Append(
 				p.values[i],
@@ -142,8 +151,9 @@ func (p *allSpooler) spool(ctx context.Context) {
 			)
 		}
 		nTuples += uint64(batch.Length())
+		p.spooledTuples = nTuples
 	}
-	p.spooledTuples = nTuples
+	p.inFlight = nil
 }
 
 func (p *allSpooler) getValues(i int) coldata.Vec {
@@ -167,8 +177,25 @@ func (p *allSpooler) getPartitionsCol() []bool {
 	return nil
 }
 
+// exportBuffered returns the next batch of the spooled tuples, followed by the
+// batch that was being spooled when spool() panicked, if any.
+func (p *allSpooler) exportBuffered() coldata.Batch {
+	if batch := p.exporter.next(p.values, p.spooledTuples); batch.Length() > 0 {
+		return batch
+	}
+	if p.inFlight != nil {
+		batch := p.inFlight
+		p.inFlight = nil
+		return batch
+	}
+	return zeroBatch
+}
+
 func (p *allSpooler) reset() {
 	p.spooledTuples = 0
+	p.spooled = false
+	p.inFlight = nil
+	p.exporter.reset()
 	if r, ok := p.input.(resetter); ok {
 		r.reset()
 	}
@@ -206,6 +233,7 @@ type sortOp struct {
 }
 
 var _ Operator = &sortOp{}
+var _ bufferingInMemoryOperator = &sortOp{}
 
 // colSorter is a single-column sorter, specialized on a particular type.
 type colSorter interface {
@@ -380,6 +408,14 @@ func (p *sortOp) sort(ctx context.Context) {
 	}
 }
 
+func (p *sortOp) buffering() bool {
+	return p.state == sortSpooling
+}
+
+func (p *sortOp) exportBuffered(Operator) coldata.Batch {
+	return p.input.(*allSpooler).exportBuffered()
+}
+
 func (p *sortOp) reset() {
 	if r, ok := p.input.(resetter); ok {
 		r.reset()
@@ -53,11 +53,18 @@ func NewSortChunks(
 	return &sortChunksOp{input: chunker, sorter: sorter}, nil
 }
 
+// sortChunksOp sorts the chunks of its input one at a time. The memory that it
+// needs is only bounded by the size of the largest chunk; if its memory limit is
+// reached, the chunk being buffered and the rest of the input can be sorted by
+// a disk-backed sorter, since all of these tuples come after the ones that have
+// already been emitted.
 type sortChunksOp struct {
 	input  *chunker
 	sorter resettableOperator
 }
 
+var _ bufferingInMemoryOperator = &sortChunksOp{}
+
 func (c *sortChunksOp) ChildCount() int {
 	return 0
 }
@@ -78,6 +85,18 @@ func (c *sortChunksOp) Init() {
 	c.sorter.Init()
 }
 
+// buffering implements the bufferingInMemoryOperator interface. The chunk
+// sorter can only be taken over by a disk-backed sorter if the memory limit was
+// reached while buffering a chunk, before any of its tuples were emitted.
+func (c *sortChunksOp) buffering() bool {
+	return c.input.buffering
+}
+
+// exportBuffered implements the bufferingInMemoryOperator interface.
+func (c *sortChunksOp) exportBuffered(Operator) coldata.Batch {
+	return c.input.exportBuffered()
+}
+
 func (c *sortChunksOp) Next(ctx context.Context) coldata.Batch {
 	for {
 		batch := c.sorter.Next(ctx)
@@ -197,6 +216,14 @@ type chunker struct {
 
 	readFrom chunkerReadingState
 	state    chunkerState
+
+	// buffering is set while tuples of s.batch are appended to the buffer, and
+	// bufferingFrom is the index of the first of them. If the memory limit is
+	// reached while buffering, the buffered tuples and the tuples of s.batch
+	// from bufferingFrom on haven't been processed yet, so they are exported.
+	buffering     bool
+	bufferingFrom uint16
+	exporter      columnExporter
 }
 
 var _ spooler = &chunker{}
@@ -223,6 +250,7 @@ func newChunker(
 		alreadySortedCols: alreadySortedCols,
 		partitioners:      partitioners,
 		state:             chunkerReading,
+		exporter:          columnExporter{typs: inputTypes},
 	}, nil
 }
 
@@ -370,6 +398,7 @@ func (s *chunker) prepareNextChunks(ctx context.Context) chunkerReadingState {
 // buffer appends all tuples in range [start,end) from s.batch to already
 // buffered tuples.
 func (s *chunker) buffer(start uint16, end uint16) {
+	s.buffering, s.bufferingFrom = true, start
 	for i := 0; i < len(s.bufferedColumns); i++ {
 		s.allocator.Append(
 			s.bufferedColumns[i],
@@ -383,6 +412,31 @@ func (s *chunker) buffer(start uint16, end uint16) {
 		)
 	}
 	s.buffered += uint64(end - start)
+	s.buffering = false
+}
+
+// exportBuffered returns the next batch of the tuples that haven't been
+// processed when buffer() panicked: the buffered tuples, followed by the rest
+// of s.batch.
+func (s *chunker) exportBuffered() coldata.Batch {
+	if batch := s.exporter.next(s.bufferedColumns, s.buffered); batch.Length() > 0 {
+		return batch
+	}
+	if s.batch != nil && s.bufferingFrom < s.batch.Length() {
+		// s.batch never has a selection vector, so one can be set to skip the
+		// tuples that have already been processed.
+		n := s.batch.Length() - s.bufferingFrom
+		s.batch.SetSelection(true)
+		sel := s.batch.Selection()
+		for i := uint16(0); i < n; i++ {
+			sel[i] = s.bufferingFrom + i
+		}
+		s.batch.SetLength(n)
+		batch := s.batch
+		s.batch = nil
+		return batch
+	}
+	return zeroBatch
 }
 
 func (s *chunker) spool(ctx context.Context) {
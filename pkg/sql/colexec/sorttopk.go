@@ -42,10 +42,12 @@ func NewTopKSorter(
 		inputTypes:   inputTypes,
 		orderingCols: orderingCols,
 		k:            k,
+		exporter:     columnExporter{typs: inputTypes},
 	}
 }
 
 var _ Operator = &topKSorter{}
+var _ bufferingInMemoryOperator = &topKSorter{}
 
 // topKSortState represents the state of the sort operator.
 type topKSortState int
@@ -80,11 +82,20 @@ type topKSorter struct {
 	// emitted is the count of rows which have been emitted so far.
 	emitted uint16
 	output  coldata.Batch
+
+	// spooled is the number of rows of topK that have been fully spooled, and
+	// inFlight is the input batch whose rows are being appended to topK while
+	// it is filled up. If the memory limit is reached while filling up topK,
+	// these rows and the rest of the input are exported. Once topK is full,
+	// every input batch is fully processed before the memory account is grown,
+	// so only the rows of topK need to be exported.
+	spooled  uint16
+	inFlight coldata.Batch
+	exporter columnExporter
 }
 
 func (t *topKSorter) Init() {
 	t.input.Init()
-	t.topK = t.allocator.NewMemBatchWithSize(t.inputTypes, int(t.k))
 	t.comparators = make([]vecComparator, len(t.inputTypes))
 	for i := range t.inputTypes {
 		typ := t.inputTypes[i]
@@ -116,6 +127,10 @@ func (t *topKSorter) Next(ctx context.Context) coldata.Batch {
 // determine the final output ordering. This is used in emit() to output the rows
 // in sorted order.
 func (t *topKSorter) spool(ctx context.Context) {
+	// t.topK is allocated here rather than in Init, so that running out of
+	// memory can be handled by spilling to disk.
+	t.topK = t.allocator.NewMemBatchWithSize(t.inputTypes, int(t.k))
+
 	// Fill up t.topK by spooling up to K rows from the input.
 	inputBatch := t.input.Next(ctx)
 	inputBatchIdx := uint16(0)
@@ -129,6 +144,7 @@ func (t *topKSorter) spool(ctx context.Context) {
 			fromLength = remainingRows
 			inputBatchIdx = fromLength
 		}
+		t.inFlight = inputBatch
 		for i := range t.inputTypes {
 			destVec := t.topK.ColVec(i)
 			vec := inputBatch.ColVec(i)
@@ -145,6 +161,8 @@ func (t *topKSorter) spool(ctx context.Context) {
 			)
 		}
 		spooledRows += fromLength
+		t.spooled = spooledRows
+		t.inFlight = nil
 		remainingRows -= fromLength
 		if fromLength == inputBatch.Length() {
 			inputBatch = t.input.Next(ctx)
@@ -197,6 +215,29 @@ func (t *topKSorter) spool(ctx context.Context) {
 	}
 }
 
+// buffering implements the bufferingInMemoryOperator interface.
+func (t *topKSorter) buffering() bool {
+	return t.state == topKSortSpooling
+}
+
+// exportBuffered implements the bufferingInMemoryOperator interface. The rows
+// of the input that were discarded because they are not in the top K rows
+// seen so far are not exported, since they can't be in the top K rows of the
+// whole input.
+func (t *topKSorter) exportBuffered(Operator) coldata.Batch {
+	if t.topK != nil {
+		if batch := t.exporter.next(t.topK.ColVecs(), uint64(t.spooled)); batch.Length() > 0 {
+			return batch
+		}
+	}
+	if t.inFlight != nil {
+		batch := t.inFlight
+		t.inFlight = nil
+		return batch
+	}
+	return zeroBatch
+}
+
 func (t *topKSorter) emit() coldata.Batch {
 	t.output.ResetInternalBatch()
 	toEmit := t.topK.Length() - t.emitted
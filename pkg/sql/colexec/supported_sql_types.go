@@ -28,4 +28,9 @@ var allSupportedSQLTypes = []types.T{
 	*types.String,
 	*types.Uuid,
 	*types.Timestamp,
+	*types.TimestampTZ,
+	*types.Interval,
+	*types.Jsonb,
+	*types.IntArray,
+	*types.StringArray,
 }
@@ -49,6 +49,20 @@ func FromColumnType(ct *types.T) coltypes.T {
 		return coltypes.Float64
 	case types.TimestampFamily:
 		return coltypes.Timestamp
+	case types.TimestampTZFamily:
+		return coltypes.TimestampTZ
+	case types.IntervalFamily:
+		return coltypes.Interval
+	case types.JsonFamily:
+		return coltypes.Datum
+	case types.ArrayFamily:
+		// The values stored as coltypes.Datum are compared without the session
+		// data, but the comparison of TIMESTAMPTZ values as array elements
+		// depends on the session time zone.
+		if ct.ArrayContents().Family() == types.TimestampTZFamily {
+			return coltypes.Unhandled
+		}
+		return coltypes.Datum
 	}
 	return coltypes.Unhandled
 }
@@ -86,6 +100,14 @@ func ToColumnType(t coltypes.T) *types.T {
 		return types.Int
 	case coltypes.Float64:
 		return types.Float
+	case coltypes.Timestamp:
+		return types.Timestamp
+	case coltypes.Interval:
+		return types.Interval
+	case coltypes.TimestampTZ:
+		return types.TimestampTZ
+	case coltypes.Datum:
+		return types.Jsonb
 	}
 	execerror.VectorizedInternalPanic(fmt.Sprintf("unexpected coltype %s", t.String()))
 	return nil
@@ -220,6 +242,28 @@ func GetDatumToPhysicalFn(ct *types.T) func(tree.Datum) (interface{}, error) {
 			}
 			return d.Time, nil
 		}
+	case types.TimestampTZFamily:
+		return func(datum tree.Datum) (interface{}, error) {
+			d, ok := datum.(*tree.DTimestampTZ)
+			if !ok {
+				return nil, errors.Errorf("expected *tree.DTimestampTZ, found %s", reflect.TypeOf(datum))
+			}
+			return d.Time, nil
+		}
+	case types.IntervalFamily:
+		return func(datum tree.Datum) (interface{}, error) {
+			d, ok := datum.(*tree.DInterval)
+			if !ok {
+				return nil, errors.Errorf("expected *tree.DInterval, found %s", reflect.TypeOf(datum))
+			}
+			return d.Duration, nil
+		}
+	case types.JsonFamily, types.ArrayFamily:
+		// The datums of these types don't have a native representation, so they
+		// are stored as is.
+		return func(datum tree.Datum) (interface{}, error) {
+			return datum, nil
+		}
 	}
 	// It would probably be more correct to return an error here, rather than a
 	// function which always returns an error. But since the function tends to be
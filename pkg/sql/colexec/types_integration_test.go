@@ -18,6 +18,7 @@ import (
 	"github.com/apache/arrow/go/arrow/array"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/colserde"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
@@ -37,7 +38,8 @@ import (
 // of rows consisting of a single datum (possibly null), converts them into
 // column batches, serializes and then deserializes these batches, and finally
 // converts the deserialized batches back to rows which are compared with the
-// original rows.
+// original rows. The types that are stored as opaque datums can't be
+// serialized, so their batches are converted back to rows directly.
 func TestSupportedSQLTypesIntegration(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -84,17 +86,20 @@ func TestSupportedSQLTypesIntegration(t *testing.T) {
 
 			coltyps, err := typeconv.FromColumnTypes(typs)
 			require.NoError(t, err)
-			c, err := colserde.NewArrowBatchConverter(coltyps)
-			require.NoError(t, err)
-			r, err := colserde.NewRecordBatchSerializer(coltyps)
-			require.NoError(t, err)
-			arrowOp := newArrowTestOperator(columnarizer, c, r)
+			var input Operator = columnarizer
+			if coltyps[0] != coltypes.Datum {
+				c, err := colserde.NewArrowBatchConverter(coltyps)
+				require.NoError(t, err)
+				r, err := colserde.NewRecordBatchSerializer(coltyps)
+				require.NoError(t, err)
+				input = newArrowTestOperator(columnarizer, c, r)
+			}
 
 			output := distsqlutils.NewRowBuffer(typs, nil /* rows */, distsqlutils.RowBufferArgs{})
 			materializer, err := NewMaterializer(
 				flowCtx,
 				1, /* processorID */
-				arrowOp,
+				input,
 				typs,
 				&execinfrapb.PostProcessSpec{},
 				output,
@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 )
@@ -221,3 +222,316 @@ func TestRowNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestBufferedWindowFunctions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	flowCtx := &execinfra.FlowCtx{
+		EvalCtx: &evalCtx,
+		Cfg: &execinfra.ServerConfig{
+			Settings: st,
+		},
+	}
+
+	// The offsets of the bounds of RANGE frames are encoded datums.
+	var da sqlbase.DatumAlloc
+	rangeOffset, err := sqlbase.DatumToEncDatum(types.Int, tree.NewDInt(1)).Encode(
+		types.Int, &da, sqlbase.DatumEncoding_VALUE, nil, /* appendTo */
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rangeOffsetType := execinfrapb.DatumInfo{Encoding: sqlbase.DatumEncoding_VALUE, Type: *types.Int}
+
+	lagFn := execinfrapb.WindowerSpec_LAG
+	leadFn := execinfrapb.WindowerSpec_LEAD
+	ntileFn := execinfrapb.WindowerSpec_NTILE
+	cumeDistFn := execinfrapb.WindowerSpec_CUME_DIST
+	firstValueFn := execinfrapb.WindowerSpec_FIRST_VALUE
+	lastValueFn := execinfrapb.WindowerSpec_LAST_VALUE
+	nthValueFn := execinfrapb.WindowerSpec_NTH_VALUE
+	maxFn := execinfrapb.AggregatorSpec_MAX
+	minFn := execinfrapb.AggregatorSpec_MIN
+	countFn := execinfrapb.AggregatorSpec_COUNT
+	orderByFirstCol := execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 0}}}
+	for _, tc := range []windowFnTestCase{
+		// lag with both PARTITION BY and ORDER BY.
+		{
+			tuples:   tuples{{1, 3}, {0, 1}, {1, 1}, {0, 2}, {nil, 5}, {1, 2}},
+			expected: tuples{{nil, 5, nil}, {0, 1, nil}, {0, 2, 1}, {1, 1, nil}, {1, 2, 1}, {1, 3, 2}},
+			windowerSpec: execinfrapb.WindowerSpec{
+				PartitionBy: []uint32{0},
+				WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+					{
+						Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &lagFn},
+						ArgsIdxs:     []uint32{1},
+						Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+						FilterColIdx: -1,
+						OutputColIdx: 2,
+					},
+				},
+			},
+		},
+		// lead with ORDER BY, no PARTITION BY.
+		{
+			tuples:   tuples{{3}, {1}, {6}, {2}, {5}, {2}},
+			expected: tuples{{1, 2}, {2, 2}, {2, 3}, {3, 5}, {5, 6}, {6, nil}},
+			windowerSpec: execinfrapb.WindowerSpec{
+				WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+					{
+						Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &leadFn},
+						ArgsIdxs:     []uint32{0},
+						Ordering:     orderByFirstCol,
+						FilterColIdx: -1,
+						OutputColIdx: 1,
+					},
+				},
+			},
+		},
+		// ntile and cume_dist.
+		{
+			tuples:   tuples{{3, 2}, {1, 2}, {2, 2}, {2, 2}},
+			expected: tuples{{1, 2, 1, 0.25}, {2, 2, 1, 0.75}, {2, 2, 2, 0.75}, {3, 2, 2, 1.0}},
+			windowerSpec: execinfrapb.WindowerSpec{
+				WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+					{
+						Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &ntileFn},
+						ArgsIdxs:     []uint32{1},
+						Ordering:     orderByFirstCol,
+						FilterColIdx: -1,
+						OutputColIdx: 2,
+					},
+					{
+						Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &cumeDistFn},
+						Ordering:     orderByFirstCol,
+						FilterColIdx: -1,
+						OutputColIdx: 3,
+					},
+				},
+			},
+		},
+		// first_value, last_value and nth_value over the default frame, which
+		// ends with the last peer of the current row.
+		{
+			tuples:   tuples{{3, 2}, {1, 2}, {2, 2}, {2, 2}},
+			expected: tuples{{1, 2, 1, 1, nil}, {2, 2, 1, 2, 2}, {2, 2, 1, 2, 2}, {3, 2, 1, 3, 2}},
+			windowerSpec: execinfrapb.WindowerSpec{
+				WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+					{
+						Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &firstValueFn},
+						ArgsIdxs:     []uint32{0},
+						Ordering:     orderByFirstCol,
+						FilterColIdx: -1,
+						OutputColIdx: 2,
+					},
+					{
+						Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &lastValueFn},
+						ArgsIdxs:     []uint32{0},
+						Ordering:     orderByFirstCol,
+						FilterColIdx: -1,
+						OutputColIdx: 3,
+					},
+					{
+						Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &nthValueFn},
+						ArgsIdxs:     []uint32{0, 1},
+						Ordering:     orderByFirstCol,
+						FilterColIdx: -1,
+						OutputColIdx: 4,
+					},
+				},
+			},
+		},
+		// max over a sliding ROWS frame, no PARTITION BY.
+		{
+			tuples:   tuples{{3}, {1}, {4}, {2}, {5}},
+			expected: tuples{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 5}},
+			windowerSpec: execinfrapb.WindowerSpec{
+				WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+					{
+						Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &maxFn},
+						ArgsIdxs: []uint32{0},
+						Ordering: orderByFirstCol,
+						Frame: &execinfrapb.WindowerSpec_Frame{
+							Mode: execinfrapb.WindowerSpec_Frame_ROWS,
+							Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+								Start: execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING,
+									IntOffset: 1,
+								},
+								End: &execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING,
+									IntOffset: 1,
+								},
+							},
+						},
+						FilterColIdx: -1,
+						OutputColIdx: 1,
+					},
+				},
+			},
+		},
+		// count over a ROWS frame that excludes the peers of the current row.
+		{
+			tuples:   tuples{{3}, {1}, {6}, {2}, {5}, {2}},
+			expected: tuples{{1, 6}, {2, 5}, {2, 5}, {3, 6}, {5, 6}, {6, 6}},
+			windowerSpec: execinfrapb.WindowerSpec{
+				WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+					{
+						Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &countFn},
+						ArgsIdxs: []uint32{0},
+						Ordering: orderByFirstCol,
+						Frame: &execinfrapb.WindowerSpec_Frame{
+							Mode: execinfrapb.WindowerSpec_Frame_ROWS,
+							Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+								Start: execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_UNBOUNDED_PRECEDING,
+								},
+								End: &execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_UNBOUNDED_FOLLOWING,
+								},
+							},
+							Exclusion: execinfrapb.WindowerSpec_Frame_EXCLUDE_TIES,
+						},
+						FilterColIdx: -1,
+						OutputColIdx: 1,
+					},
+				},
+			},
+		},
+		// min over a RANGE frame with an offset.
+		{
+			tuples:   tuples{{3}, {1}, {6}, {2}, {5}, {2}},
+			expected: tuples{{1, 1}, {2, 1}, {2, 1}, {3, 2}, {5, 5}, {6, 5}},
+			windowerSpec: execinfrapb.WindowerSpec{
+				WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+					{
+						Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &minFn},
+						ArgsIdxs: []uint32{0},
+						Ordering: orderByFirstCol,
+						Frame: &execinfrapb.WindowerSpec_Frame{
+							Mode: execinfrapb.WindowerSpec_Frame_RANGE,
+							Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+								Start: execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType:   execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING,
+									TypedOffset: rangeOffset,
+									OffsetType:  rangeOffsetType,
+								},
+								End: &execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_CURRENT_ROW,
+								},
+							},
+						},
+						FilterColIdx: -1,
+						OutputColIdx: 1,
+					},
+				},
+			},
+		},
+		// count over a RANGE frame that excludes the peer group of the current
+		// row.
+		{
+			tuples:   tuples{{3}, {1}, {6}, {2}, {5}, {2}},
+			expected: tuples{{1, 0}, {2, 1}, {2, 1}, {3, 3}, {5, 4}, {6, 5}},
+			windowerSpec: execinfrapb.WindowerSpec{
+				WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+					{
+						Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &countFn},
+						ArgsIdxs: []uint32{0},
+						Ordering: orderByFirstCol,
+						Frame: &execinfrapb.WindowerSpec_Frame{
+							Mode: execinfrapb.WindowerSpec_Frame_RANGE,
+							Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+								Start: execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_UNBOUNDED_PRECEDING,
+								},
+								End: &execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_CURRENT_ROW,
+								},
+							},
+							Exclusion: execinfrapb.WindowerSpec_Frame_EXCLUDE_GROUP,
+						},
+						FilterColIdx: -1,
+						OutputColIdx: 1,
+					},
+				},
+			},
+		},
+		// count over a GROUPS frame that excludes the current row.
+		{
+			tuples:   tuples{{3}, {1}, {6}, {2}, {5}, {2}},
+			expected: tuples{{1, 2}, {2, 3}, {2, 3}, {3, 3}, {5, 2}, {6, 1}},
+			windowerSpec: execinfrapb.WindowerSpec{
+				WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+					{
+						Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &countFn},
+						ArgsIdxs: []uint32{0},
+						Ordering: orderByFirstCol,
+						Frame: &execinfrapb.WindowerSpec_Frame{
+							Mode: execinfrapb.WindowerSpec_Frame_GROUPS,
+							Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+								Start: execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING,
+									IntOffset: 1,
+								},
+								End: &execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING,
+									IntOffset: 1,
+								},
+							},
+							Exclusion: execinfrapb.WindowerSpec_Frame_EXCLUDE_CURRENT_ROW,
+						},
+						FilterColIdx: -1,
+						OutputColIdx: 1,
+					},
+				},
+			},
+		},
+		// Two window functions with different orderings.
+		{
+			tuples:   tuples{{1, 3}, {2, 2}, {3, 1}},
+			expected: tuples{{1, 3, nil, 2}, {2, 2, 1, 1}, {3, 1, 2, nil}},
+			windowerSpec: execinfrapb.WindowerSpec{
+				WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+					{
+						Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &lagFn},
+						ArgsIdxs:     []uint32{0},
+						Ordering:     orderByFirstCol,
+						FilterColIdx: -1,
+						OutputColIdx: 2,
+					},
+					{
+						Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &lagFn},
+						ArgsIdxs:     []uint32{1},
+						Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+						FilterColIdx: -1,
+						OutputColIdx: 3,
+					},
+				},
+			},
+		},
+	} {
+		runTests(t, []tuples{tc.tuples}, tc.expected, unorderedVerifier, func(inputs []Operator) (Operator, error) {
+			ct := make([]types.T, len(tc.tuples[0]))
+			for i := range ct {
+				ct[i] = *types.Int
+			}
+			spec := &execinfrapb.ProcessorSpec{
+				Input: []execinfrapb.InputSyncSpec{{ColumnTypes: ct}},
+				Core: execinfrapb.ProcessorCoreUnion{
+					Windower: &tc.windowerSpec,
+				},
+			}
+			result, err := NewColOperator(
+				ctx, flowCtx, spec, inputs, testMemAcc,
+				true, /* useStreamingMemAccountForBuffering */
+			)
+			if err != nil {
+				return nil, err
+			}
+			return result.Op, nil
+		})
+	}
+}
@@ -39,6 +39,9 @@ type vectorizedFlow struct {
 	*flowinfra.FlowBase
 	// operatorConcurrency is set if any operators are executed in parallel.
 	operatorConcurrency bool
+	// toClose contains the components that must be closed once the flow is
+	// done, such as the operators that spill to disk.
+	toClose []colexec.Closer
 }
 
 var _ flowinfra.Flow = &vectorizedFlow{}
@@ -75,6 +78,7 @@ func (f *vectorizedFlow) Setup(
 		f.operatorConcurrency = creator.operatorConcurrency
 		f.VectorizedBufferingMemMonitors = append(f.VectorizedBufferingMemMonitors, creator.bufferingMemMonitors...)
 		f.VectorizedBufferingMemAccounts = append(f.VectorizedBufferingMemAccounts, creator.bufferingMemAccounts...)
+		f.toClose = append(f.toClose, creator.toClose...)
 		log.VEventf(ctx, 1, "vectorized flow setup succeeded")
 		return nil
 	}
@@ -82,6 +86,18 @@ func (f *vectorizedFlow) Setup(
 	return err
 }
 
+// Cleanup is part of the flowinfra.Flow interface.
+func (f *vectorizedFlow) Cleanup(ctx context.Context) {
+	// The components are closed before the memory accounts of the flow, which
+	// they might use, are closed by the FlowBase.
+	for _, c := range f.toClose {
+		if err := c.Close(ctx); err != nil {
+			log.Warningf(ctx, "error closing vectorized component: %v", err)
+		}
+	}
+	f.FlowBase.Cleanup(ctx)
+}
+
 // ConcurrentExecution is part of the Flow interface.
 func (f *vectorizedFlow) ConcurrentExecution() bool {
 	return f.operatorConcurrency || f.FlowBase.ConcurrentExecution()
@@ -233,6 +249,9 @@ type vectorizedFlowCreator struct {
 	// bufferingMemAccounts contains all memory accounts of the buffering
 	// components in the vectorized flow.
 	bufferingMemAccounts []*mon.BoundAccount
+	// toClose contains all the components of the vectorized flow that must be
+	// closed once the flow is done.
+	toClose []colexec.Closer
 }
 
 func newVectorizedFlowCreator(
@@ -644,10 +663,9 @@ func (s *vectorizedFlowCreator) setupFlow(
 		// Even when err is non-nil, it is possible that the buffering memory
 		// monitor and account have been created, so we always want to accumulate
 		// them for a proper cleanup.
-		if result.BufferingOpMemMonitor != nil {
-			s.bufferingMemMonitors = append(s.bufferingMemMonitors, result.BufferingOpMemMonitor)
-			s.bufferingMemAccounts = append(s.bufferingMemAccounts, result.BufferingOpMemAccount)
-		}
+		s.bufferingMemMonitors = append(s.bufferingMemMonitors, result.BufferingOpMemMonitors...)
+		s.bufferingMemAccounts = append(s.bufferingMemAccounts, result.BufferingOpMemAccounts...)
+		s.toClose = append(s.toClose, result.ToClose...)
 		if err != nil {
 			return nil, errors.Wrapf(err, "unable to vectorize execution plan")
 		}
@@ -655,7 +673,7 @@ func (s *vectorizedFlowCreator) setupFlow(
 			result.Op = colexec.NewInvariantsChecker(result.Op, len(result.ColumnTypes))
 		}
 		if flowCtx.EvalCtx.SessionData.VectorizeMode == sessiondata.VectorizeAuto &&
-			!result.IsStreaming {
+			!result.IsStreaming && !result.SpillsToDisk {
 			return nil, errors.Errorf("non-streaming operator encountered when vectorize=auto")
 		}
 		if err = streamingMemAccount.Grow(ctx, int64(result.InternalMemUsage)); err != nil {
@@ -873,6 +891,11 @@ func SupportsVectorized(
 	acc := memoryMonitor.MakeBoundAccount()
 	defer acc.Close(ctx)
 	defer func() {
+		for _, c := range creator.toClose {
+			if err := c.Close(ctx); err != nil {
+				log.Warningf(ctx, "error closing vectorized component: %v", err)
+			}
+		}
 		for _, memAcc := range creator.bufferingMemAccounts {
 			memAcc.Close(ctx)
 		}
@@ -290,11 +290,13 @@ func NewServer(cfg *ExecutorConfig, pool *mon.BytesMonitor) *Server {
 func makeMetrics(internal bool) Metrics {
 	return Metrics{
 		EngineMetrics: EngineMetrics{
-			DistSQLSelectCount:    metric.NewCounter(getMetricMeta(MetaDistSQLSelect, internal)),
-			SQLOptCount:           metric.NewCounter(getMetricMeta(MetaSQLOpt, internal)),
-			SQLOptFallbackCount:   metric.NewCounter(getMetricMeta(MetaSQLOptFallback, internal)),
-			SQLOptPlanCacheHits:   metric.NewCounter(getMetricMeta(MetaSQLOptPlanCacheHits, internal)),
-			SQLOptPlanCacheMisses: metric.NewCounter(getMetricMeta(MetaSQLOptPlanCacheMisses, internal)),
+			DistSQLSelectCount:      metric.NewCounter(getMetricMeta(MetaDistSQLSelect, internal)),
+			SQLOptCount:             metric.NewCounter(getMetricMeta(MetaSQLOpt, internal)),
+			SQLOptFallbackCount:     metric.NewCounter(getMetricMeta(MetaSQLOptFallback, internal)),
+			SQLOptPlanCacheHits:     metric.NewCounter(getMetricMeta(MetaSQLOptPlanCacheHits, internal)),
+			SQLOptPlanCacheMisses:   metric.NewCounter(getMetricMeta(MetaSQLOptPlanCacheMisses, internal)),
+			SQLOptGenericPlanHits:   metric.NewCounter(getMetricMeta(MetaSQLOptGenericPlanHits, internal)),
+			SQLOptGenericPlanMisses: metric.NewCounter(getMetricMeta(MetaSQLOptGenericPlanMisses, internal)),
 
 			// TODO(mrtracy): See HistogramWindowInterval in server/config.go for the 6x factor.
 			DistSQLExecLatency: metric.NewLatency(getMetricMeta(MetaDistSQLExecLatency, internal),
@@ -1870,6 +1872,15 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 		schemaAccessors:   scInterface,
 		sqlStatsCollector: ex.statsCollector,
 	}
+	if recorder := ex.server.cfg.StmtDiagnosticsRecorder; recorder != nil {
+		evalCtx.StmtDiagnosticsRequestInserter = func(ctx context.Context, fingerprint string) error {
+			_, err := recorder.InsertRequest(ctx, fingerprint)
+			return err
+		}
+	}
+	if r := ex.server.cfg.StmtHintsRegistry; r != nil {
+		evalCtx.StmtHintsSetter = r.SetHints
+	}
 }
 
 // resetEvalCtx initializes the fields of evalCtx that can change
@@ -20,11 +20,15 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/fsm"
@@ -34,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/errors"
+	opentracing "github.com/opentracing/opentracing-go"
 )
 
 // RestartSavepointName is the only savepoint ident that we accept.
@@ -219,6 +224,8 @@ func (ex *connExecutor) execStmtInOpenState(
 	}
 
 	var discardRows bool
+	// explainBundle is set if the statement is an EXPLAIN ANALYZE (DEBUG).
+	var explainBundle *tree.Explain
 	switch s := stmt.AST.(type) {
 	case *tree.BeginTransaction:
 		// BEGIN is always an error when in the Open state. It's legitimate only in
@@ -356,12 +363,77 @@ func (ex *connExecutor) execStmtInOpenState(
 		res.ResetStmtType(ps.AST)
 
 		discardRows = s.DiscardRows
+
+	case *tree.Explain:
+		if s.IsAnalyzeDebug() {
+			// EXPLAIN ANALYZE (DEBUG) runs the statement with a diagnostics
+			// bundle being collected; the rows produced by the statement are
+			// discarded and the result is replaced with information about the
+			// bundle (see setExplainBundleResult).
+			if ex.server.cfg.StmtDiagnosticsRecorder == nil {
+				return makeErrEvent(errors.New("statement diagnostics are not supported"))
+			}
+			telemetry.Inc(sqltelemetry.ExplainAnalyzeDebugUseCounter)
+			explainBundle = s
+			stmt.AST = s.Statement
+			stmt.Prepared = nil
+			stmt.ExpectedTypes = nil
+			discardRows = true
+		}
 	}
 
 	// For regular statements (the ones that get to this point), we don't return
 	// any event unless an an error happens.
 
 	p := &ex.planner
+
+	// Check whether a diagnostics bundle should be collected for this
+	// statement, either because of an EXPLAIN ANALYZE (DEBUG) or because of an
+	// outstanding statement diagnostics request.
+	recorder := ex.server.cfg.StmtDiagnosticsRecorder
+	var diagRequestID stmtdiagnostics.RequestID
+	p.collectBundle = false
+	p.explainBundle = explainBundle != nil
+	if explainBundle != nil {
+		p.collectBundle = true
+	} else if recorder != nil {
+		p.collectBundle, diagRequestID = recorder.ShouldCollectDiagnostics(stmt.AST)
+	}
+	if p.collectBundle {
+		// Make sure that we don't build the bundle from the plan of a previous
+		// statement if we fail before planning. The AST is set once planning
+		// starts (see makeExecPlan).
+		p.curPlan = planTop{}
+		origCtx := ctx
+		var sp opentracing.Span
+		ctx, sp, _ = tracing.StartSnowballTrace(ctx, ex.server.cfg.AmbientCtx.Tracer, "traced statement")
+		// Note that this defer runs before the auto-commit below, so the commit
+		// is not part of the trace.
+		defer func() {
+			// The context containing the span must not be used after the span is
+			// finished; restore the original context for the remaining defers.
+			ctx = origCtx
+			sp.Finish()
+			trace := tracing.GetRecording(sp)
+			var plan *planTop
+			if p.curPlan.AST != nil {
+				plan = &p.curPlan
+			}
+			bundle := buildStatementBundle(origCtx, p, plan, trace)
+			diagID, diagErr := bundle.insert(origCtx, recorder, diagRequestID, stmt.AST)
+			if explainBundle == nil {
+				if diagErr != nil {
+					log.Warningf(origCtx, "failed to record statement diagnostics: %v", diagErr)
+				}
+				recorder.RemoveOngoing(diagRequestID)
+				return
+			}
+			if retErr == nil {
+				retErr = setExplainBundleResult(origCtx, res, explainBundle, bundle, diagID, diagErr)
+			}
+		}()
+	}
+
 	stmtTS := ex.server.cfg.Clock.PhysicalTime()
 	ex.statsCollector.reset(&ex.server.sqlStats, ex.appStats, &ex.phaseTimes)
 	ex.resetPlanner(ctx, p, ex.state.mu.txn, stmtTS, stmt.NumAnnotations)
@@ -665,13 +737,17 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 		return nil
 	}
 
-	var cols sqlbase.ResultColumns
-	if stmt.AST.StatementType() == tree.Rows {
-		cols = planColumns(planner.curPlan.plan)
-	}
-	if err := ex.initStatementResult(ctx, res, stmt, cols); err != nil {
-		res.SetError(err)
-		return nil
+	// For EXPLAIN ANALYZE (DEBUG), the result columns are set once the
+	// statement has run (see setExplainBundleResult).
+	if !planner.explainBundle {
+		var cols sqlbase.ResultColumns
+		if stmt.AST.StatementType() == tree.Rows {
+			cols = planColumns(planner.curPlan.plan)
+		}
+		if err := ex.initStatementResult(ctx, res, stmt, cols); err != nil {
+			res.SetError(err)
+			return nil
+		}
 	}
 
 	ex.sessionTracing.TracePlanCheckStart(ctx)
@@ -799,6 +875,11 @@ func (ex *connExecutor) execWithDistSQLEngine(
 	planCtx.isLocal = !distribute
 	planCtx.planner = planner
 	planCtx.stmtType = recv.stmtType
+	if planner.collectBundle {
+		planCtx.saveDiagram = func(diagram execinfrapb.FlowDiagram) {
+			planner.curPlan.distSQLDiagram = diagram
+		}
+	}
 
 	var evalCtxFactory func() *extendedEvalContext
 	if len(planner.curPlan.subqueryPlans) != 0 || len(planner.curPlan.postqueryPlans) != 0 {
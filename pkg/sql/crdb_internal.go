@@ -76,6 +76,8 @@ var crdbInternal = virtualSchema{
 		sqlbase.CrdbInternalGossipAlertsTableID:         crdbInternalGossipAlertsTable,
 		sqlbase.CrdbInternalGossipLivenessTableID:       crdbInternalGossipLivenessTable,
 		sqlbase.CrdbInternalGossipNetworkTableID:        crdbInternalGossipNetworkTable,
+		sqlbase.CrdbInternalHotKeysTableID:              crdbInternalHotKeysTable,
+		sqlbase.CrdbInternalHotRangesTableID:            crdbInternalHotRangesTable,
 		sqlbase.CrdbInternalIndexColumnsTableID:         crdbInternalIndexColumnsTable,
 		sqlbase.CrdbInternalJobsTableID:                 crdbInternalJobsTable,
 		sqlbase.CrdbInternalKVNodeStatusTableID:         crdbInternalKVNodeStatusTable,
@@ -2067,6 +2069,198 @@ CREATE TABLE crdb_internal.ranges_no_leases (
 	},
 }
 
+// keyNamer resolves keys to the names of the database, table and index that
+// they belong to.
+type keyNamer struct {
+	dbNames    map[uint64]string
+	tableNames map[uint64]string
+	indexNames map[uint64]map[sqlbase.IndexID]string
+	parents    map[uint64]uint64
+}
+
+func makeKeyNamer(ctx context.Context, p *planner) (keyNamer, error) {
+	n := keyNamer{
+		dbNames:    make(map[uint64]string),
+		tableNames: make(map[uint64]string),
+		indexNames: make(map[uint64]map[sqlbase.IndexID]string),
+		parents:    make(map[uint64]uint64),
+	}
+	descs, err := p.Tables().getAllDescriptors(ctx, p.txn)
+	if err != nil {
+		return n, err
+	}
+	for _, desc := range descs {
+		id := uint64(desc.GetID())
+		switch desc := desc.(type) {
+		case *sqlbase.TableDescriptor:
+			n.parents[id] = uint64(desc.ParentID)
+			n.tableNames[id] = desc.GetName()
+			n.indexNames[id] = make(map[sqlbase.IndexID]string)
+			for _, idx := range desc.AllNonDropIndexes() {
+				n.indexNames[id][idx.ID] = idx.Name
+			}
+		case *sqlbase.DatabaseDescriptor:
+			n.dbNames[id] = desc.GetName()
+		}
+	}
+	return n, nil
+}
+
+// names returns the names of the database, table and index that the key
+// belongs to. Names that don't apply to the key are left empty.
+func (n *keyNamer) names(key roachpb.Key) (dbName, tableName, indexName string) {
+	if _, id, err := keys.DecodeTablePrefix(key); err == nil {
+		parent := n.parents[id]
+		if parent != 0 {
+			tableName = n.tableNames[id]
+			dbName = n.dbNames[parent]
+			if _, _, idxID, err := sqlbase.DecodeTableIDIndexID(key); err == nil {
+				indexName = n.indexNames[id][idxID]
+			}
+		} else {
+			dbName = n.dbNames[id]
+		}
+	}
+	return dbName, tableName, indexName
+}
+
+// visitHotRanges calls the visitor for each hot range reported by the nodes
+// in the cluster.
+func visitHotRanges(
+	ctx context.Context,
+	p *planner,
+	visitor func(roachpb.NodeID, roachpb.StoreID, *serverpb.HotKeysResponse_HotRange) error,
+) error {
+	response, err := p.ExecCfg().StatusServer.HotKeys(ctx, &serverpb.HotKeysRequest{})
+	if err != nil {
+		return err
+	}
+	nodeIDs := make([]roachpb.NodeID, 0, len(response.HotKeysByNodeID))
+	for nodeID := range response.HotKeysByNodeID {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Slice(nodeIDs, func(i, j int) bool { return nodeIDs[i] < nodeIDs[j] })
+	for _, nodeID := range nodeIDs {
+		nodeResp := response.HotKeysByNodeID[nodeID]
+		if nodeResp.ErrorMessage != "" {
+			// Skip nodes that couldn't be reached rather than failing the
+			// whole query.
+			log.Warningf(ctx, "unable to retrieve hot ranges from n%d: %s", nodeID, nodeResp.ErrorMessage)
+			continue
+		}
+		for _, storeResp := range nodeResp.Stores {
+			for i := range storeResp.HotRanges {
+				if err := visitor(nodeID, storeResp.StoreID, &storeResp.HotRanges[i]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// crdbInternalHotRangesTable exposes the ranges with the highest QPS on each
+// store in the cluster.
+var crdbInternalHotRangesTable = virtualSchemaTable{
+	comment: `ranges with the highest QPS on each store (cluster RPC; expensive!)`,
+	schema: `
+CREATE TABLE crdb_internal.hot_ranges (
+  node_id            INT NOT NULL,
+  store_id           INT NOT NULL,
+  range_id           INT NOT NULL,
+  queries_per_second FLOAT NOT NULL,
+  start_pretty       STRING NOT NULL,
+  end_pretty         STRING NOT NULL,
+  database_name      STRING NOT NULL,
+  table_name         STRING NOT NULL,
+  index_name         STRING NOT NULL,
+  load_split_key     STRING
+)
+`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.hot_ranges"); err != nil {
+			return err
+		}
+		namer, err := makeKeyNamer(ctx, p)
+		if err != nil {
+			return err
+		}
+		return visitHotRanges(ctx, p, func(
+			nodeID roachpb.NodeID, storeID roachpb.StoreID, r *serverpb.HotKeysResponse_HotRange,
+		) error {
+			dbName, tableName, indexName := namer.names(r.Desc.StartKey.AsRawKey())
+			loadSplitKey := tree.DNull
+			if len(r.LoadSplitKey) > 0 {
+				loadSplitKey = tree.NewDString(keys.PrettyPrint(nil /* valDirs */, r.LoadSplitKey))
+			}
+			return addRow(
+				tree.NewDInt(tree.DInt(nodeID)),
+				tree.NewDInt(tree.DInt(storeID)),
+				tree.NewDInt(tree.DInt(r.Desc.RangeID)),
+				tree.NewDFloat(tree.DFloat(r.QueriesPerSecond)),
+				tree.NewDString(keys.PrettyPrint(nil /* valDirs */, r.Desc.StartKey.AsRawKey())),
+				tree.NewDString(keys.PrettyPrint(nil /* valDirs */, r.Desc.EndKey.AsRawKey())),
+				tree.NewDString(dbName),
+				tree.NewDString(tableName),
+				tree.NewDString(indexName),
+				loadSplitKey,
+			)
+		})
+	},
+}
+
+// crdbInternalHotKeysTable exposes the most frequently accessed keys in the
+// hot ranges of each store in the cluster, as sampled over the last few
+// seconds.
+var crdbInternalHotKeysTable = virtualSchemaTable{
+	comment: `most frequently accessed keys in hot ranges (cluster RPC; expensive!)`,
+	schema: `
+CREATE TABLE crdb_internal.hot_keys (
+  node_id            INT NOT NULL,
+  store_id           INT NOT NULL,
+  range_id           INT NOT NULL,
+  key                BYTES NOT NULL,
+  key_pretty         STRING NOT NULL,
+  database_name      STRING NOT NULL,
+  table_name         STRING NOT NULL,
+  index_name         STRING NOT NULL,
+  queries_per_second FLOAT NOT NULL,
+  fraction           FLOAT NOT NULL
+)
+`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.hot_keys"); err != nil {
+			return err
+		}
+		namer, err := makeKeyNamer(ctx, p)
+		if err != nil {
+			return err
+		}
+		return visitHotRanges(ctx, p, func(
+			nodeID roachpb.NodeID, storeID roachpb.StoreID, r *serverpb.HotKeysResponse_HotRange,
+		) error {
+			for _, k := range r.HotKeys {
+				dbName, tableName, indexName := namer.names(k.Key)
+				if err := addRow(
+					tree.NewDInt(tree.DInt(nodeID)),
+					tree.NewDInt(tree.DInt(storeID)),
+					tree.NewDInt(tree.DInt(r.Desc.RangeID)),
+					tree.NewDBytes(tree.DBytes(k.Key)),
+					tree.NewDString(keys.PrettyPrint(nil /* valDirs */, k.Key)),
+					tree.NewDString(dbName),
+					tree.NewDString(tableName),
+					tree.NewDString(indexName),
+					tree.NewDFloat(tree.DFloat(k.QueriesPerSecond)),
+					tree.NewDFloat(tree.DFloat(k.Fraction)),
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	},
+}
+
 type namespaceKey struct {
 	parentID sqlbase.ID
 	name     string
@@ -46,6 +46,14 @@ var createStatsPostEvents = settings.RegisterBoolSetting(
 	false,
 )
 
+// multiColumnStatisticsClusterMode controls the cluster setting for enabling
+// automatic collection of multi-column statistics on index prefixes.
+var multiColumnStatisticsClusterMode = settings.RegisterBoolSetting(
+	"sql.stats.multi_column_collection.enabled",
+	"multi-column statistics collection mode",
+	true,
+)
+
 func (p *planner) CreateStatistics(ctx context.Context, n *tree.CreateStats) (planNode, error) {
 	return &createStatsNode{
 		CreateStats: *n,
@@ -185,7 +193,9 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 	// Identify which columns we should create statistics for.
 	var colStats []jobspb.CreateStatsDetails_ColStat
 	if len(n.ColumnNames) == 0 {
-		if colStats, err = createStatsDefaultColumns(tableDesc); err != nil {
+		if colStats, err = createStatsDefaultColumns(
+			tableDesc, multiColumnStatisticsClusterMode.Get(&n.p.ExecCfg().Settings.SV),
+		); err != nil {
 			return nil, err
 		}
 	} else {
@@ -196,9 +206,16 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 
 		columnIDs := make([]sqlbase.ColumnID, len(columns))
 		for i := range columns {
-			if columns[i].Type.Family() == types.JsonFamily {
+			switch columns[i].Type.Family() {
+			case types.JsonFamily:
 				return nil, unimplemented.NewWithIssuef(35844,
 					"CREATE STATISTICS is not supported for JSON columns")
+			case types.TSVectorFamily, types.TSQueryFamily:
+				return nil, unimplemented.NewWithIssuef(7821,
+					"CREATE STATISTICS is not supported for %s columns", columns[i].Type)
+			case types.GeometryFamily, types.GeographyFamily:
+				return nil, unimplemented.NewWithIssuef(19953,
+					"CREATE STATISTICS is not supported for %s columns", columns[i].Type)
 			}
 			columnIDs[i] = columns[i].ID
 		}
@@ -267,30 +284,23 @@ const maxNonIndexCols = 100
 // In addition to the index columns, we collect stats on up to maxNonIndexCols
 // other columns from the table. We only collect histograms for index columns.
 //
-// TODO(rytaft): This currently only generates one single-column stat per
-// index. Add code to collect multi-column stats once they are supported.
+// If multiColEnabled is false, only single-column statistics are collected.
 func createStatsDefaultColumns(
-	desc *ImmutableTableDescriptor,
+	desc *ImmutableTableDescriptor, multiColEnabled bool,
 ) ([]jobspb.CreateStatsDetails_ColStat, error) {
 	colStats := make([]jobspb.CreateStatsDetails_ColStat, 0, len(desc.Indexes)+1)
 
 	var requestedCols util.FastIntSet
 
-	// Add a column for the primary key.
-	pkCol := desc.PrimaryIndex.ColumnIDs[0]
-	colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
-		ColumnIDs:    []sqlbase.ColumnID{pkCol},
-		HasHistogram: true,
-	})
-	requestedCols.Add(int(pkCol))
+	// Keep track of the multi-column stats that have already been requested so
+	// that index prefixes shared by several indexes are only collected once.
+	requestedStats := make(map[string]struct{})
 
-	// Add columns for each secondary index.
-	for i := range desc.Indexes {
-		if desc.Indexes[i].Type == sqlbase.IndexDescriptor_INVERTED {
-			// We don't yet support stats on inverted indexes.
-			continue
-		}
-		idxCol := desc.Indexes[i].ColumnIDs[0]
+	// addIndexColumnStats adds statistics for the given index: a single-column
+	// stat (with a histogram) on the first column, and if multiColEnabled is
+	// true, multi-column stats on each prefix of length two or more.
+	addIndexColumnStats := func(idx *sqlbase.IndexDescriptor) {
+		idxCol := idx.ColumnIDs[0]
 		if !requestedCols.Contains(int(idxCol)) {
 			colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
 				ColumnIDs:    []sqlbase.ColumnID{idxCol},
@@ -298,13 +308,55 @@ func createStatsDefaultColumns(
 			})
 			requestedCols.Add(int(idxCol))
 		}
+		if !multiColEnabled {
+			return
+		}
+
+		for i := 2; i <= len(idx.ColumnIDs); i++ {
+			colIDs := idx.ColumnIDs[:i]
+
+			// Check for existing stats and remember the requested stats. The
+			// column set is independent of the order of the columns.
+			var colSet util.FastIntSet
+			for _, c := range colIDs {
+				colSet.Add(int(c))
+			}
+			key := colSet.String()
+			if _, ok := requestedStats[key]; ok {
+				continue
+			}
+			requestedStats[key] = struct{}{}
+
+			colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
+				ColumnIDs:    append([]sqlbase.ColumnID(nil), colIDs...),
+				HasHistogram: false,
+			})
+		}
+	}
+
+	// Add column stats for the primary key.
+	addIndexColumnStats(&desc.PrimaryIndex)
+
+	// Add column stats for each secondary index.
+	for i := range desc.Indexes {
+		if desc.Indexes[i].Type == sqlbase.IndexDescriptor_INVERTED {
+			// We don't yet support stats on inverted indexes.
+			continue
+		}
+		addIndexColumnStats(&desc.Indexes[i])
 	}
 
-	// Add all remaining non-json columns in the table, up to maxNonIndexCols.
+	// Add all remaining non-json, non-text search and non-spatial columns in the
+	// table, up to maxNonIndexCols.
 	nonIdxCols := 0
 	for i := 0; i < len(desc.Columns) && nonIdxCols < maxNonIndexCols; i++ {
 		col := &desc.Columns[i]
-		if col.Type.Family() != types.JsonFamily && !requestedCols.Contains(int(col.ID)) {
+		switch col.Type.Family() {
+		case types.JsonFamily, types.TSVectorFamily, types.TSQueryFamily,
+			types.GeometryFamily, types.GeographyFamily:
+			continue
+		}
+		if !requestedCols.Contains(int(col.ID)) {
 			colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
 				ColumnIDs:    []sqlbase.ColumnID{col.ID},
 				HasHistogram: false,
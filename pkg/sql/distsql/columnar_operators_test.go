@@ -543,7 +543,13 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 		execinfrapb.WindowerSpec_ROW_NUMBER,
 		execinfrapb.WindowerSpec_RANK,
 		execinfrapb.WindowerSpec_DENSE_RANK,
+		execinfrapb.WindowerSpec_PERCENT_RANK,
+		execinfrapb.WindowerSpec_CUME_DIST,
 	} {
+		outputType := types.Int
+		if windowFn == execinfrapb.WindowerSpec_PERCENT_RANK || windowFn == execinfrapb.WindowerSpec_CUME_DIST {
+			outputType = types.Float
+		}
 		for _, partitionBy := range [][]uint32{
 			{},     // No PARTITION BY clause.
 			{0},    // Partitioning on the first input column.
@@ -583,7 +589,7 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 						Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
 						Core:  execinfrapb.ProcessorCoreUnion{Windower: windowerSpec},
 					}
-					if err := verifyColOperator(true /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, append(inputTypes, *types.Int), pspec); err != nil {
+					if err := verifyColOperator(true /* anyOrder */, [][]types.T{inputTypes}, []sqlbase.EncDatumRows{rows}, append(inputTypes, *outputType), pspec); err != nil {
 						t.Fatal(err)
 					}
 				}
@@ -275,6 +275,7 @@ func (ds *ServerImpl) setupFlow(
 				BytesEncodeFormat: be,
 				ExtraFloatDigits:  int(req.EvalContext.ExtraFloatDigits),
 			},
+			SimilarityThreshold: req.EvalContext.SimilarityThreshold,
 		}
 		// Enable better compatibility with PostgreSQL date math.
 		if req.Version >= 22 {
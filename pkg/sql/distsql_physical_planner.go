@@ -528,6 +528,10 @@ type PlanningCtx struct {
 	// noEvalSubqueries indicates that the plan expects any subqueries to not
 	// be replaced by evaluation. Should only be set by EXPLAIN.
 	noEvalSubqueries bool
+
+	// If set, the diagram for the plan of the main query is generated and passed
+	// to this function. Used when collecting statement diagnostics bundles.
+	saveDiagram func(execinfrapb.FlowDiagram)
 }
 
 var _ physicalplan.ExprContext = &PlanningCtx{}
@@ -1004,6 +1004,15 @@ func (dsp *DistSQLPlanner) PlanAndRun(
 		return func() {}
 	}
 	dsp.FinalizePlan(planCtx, &physPlan)
+	if planCtx.saveDiagram != nil {
+		flows := physPlan.GenerateFlowSpecs(evalCtx.NodeID)
+		diagram, err := execinfrapb.GeneratePlanDiagram(planCtx.planner.stmt.String(), flows)
+		if err != nil {
+			log.Warningf(ctx, "unable to generate plan diagram: %v", err)
+		} else {
+			planCtx.saveDiagram(diagram)
+		}
+	}
 	return dsp.Run(planCtx, txn, &physPlan, recv, evalCtx, nil /* finishedSetupFn */)
 }
 
@@ -50,6 +50,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmthints"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
@@ -245,6 +247,18 @@ var (
 		Measurement: "SQL Statements",
 		Unit:        metric.Unit_COUNT,
 	}
+	MetaSQLOptGenericPlanHits = metric.Metadata{
+		Name:        "sql.optimizer.generic_plan.hits",
+		Help:        "Number of prepared statement executions for which a cached generic plan was used",
+		Measurement: "SQL Statements",
+		Unit:        metric.Unit_COUNT,
+	}
+	MetaSQLOptGenericPlanMisses = metric.Metadata{
+		Name:        "sql.optimizer.generic_plan.misses",
+		Help:        "Number of prepared statement executions for which a generic plan was built",
+		Measurement: "SQL Statements",
+		Unit:        metric.Unit_COUNT,
+	}
 	MetaDistSQLSelect = metric.Metadata{
 		Name:        "sql.distsql.select.count",
 		Help:        "Number of DistSQL SELECT statements",
@@ -509,6 +523,15 @@ type ExecutorConfig struct {
 	InternalExecutor  *InternalExecutor
 	QueryCache        *querycache.C
 
+	// StmtDiagnosticsRecorder deals with recording statement diagnostics
+	// bundles, both for requests made through the registry and for
+	// EXPLAIN ANALYZE (DEBUG).
+	StmtDiagnosticsRecorder *stmtdiagnostics.Registry
+
+	// StmtHintsRegistry contains the plan hints attached to statement
+	// fingerprints, which are applied by the optimizer.
+	StmtHintsRegistry *stmthints.Registry
+
 	TestingKnobs              ExecutorTestingKnobs
 	PGWireTestingKnobs        *PGWireTestingKnobs
 	SchemaChangerTestingKnobs *SchemaChangerTestingKnobs
@@ -773,6 +796,10 @@ func checkResultType(typ *types.T) error {
 	case types.TimestampTZFamily:
 	case types.IntervalFamily:
 	case types.JsonFamily:
+	case types.TSVectorFamily:
+	case types.TSQueryFamily:
+	case types.GeometryFamily:
+	case types.GeographyFamily:
 	case types.UuidFamily:
 	case types.INetFamily:
 	case types.OidFamily:
@@ -1853,6 +1880,14 @@ func (m *sessionDataMutator) SetTempTablesEnabled(val bool) {
 	m.data.TempTablesEnabled = val
 }
 
+func (m *sessionDataMutator) SetSimilarityThreshold(val float64) {
+	m.data.SimilarityThreshold = val
+}
+
+func (m *sessionDataMutator) SetPlanCacheMode(val sessiondata.PlanCacheMode) {
+	m.data.PlanCacheMode = val
+}
+
 // RecordLatestSequenceValue records that value to which the session incremented
 // a sequence.
 func (m *sessionDataMutator) RecordLatestSequenceVal(seqID uint32, val int64) {
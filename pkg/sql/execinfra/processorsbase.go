@@ -859,13 +859,20 @@ func NewMonitor(ctx context.Context, parent *mon.BytesMonitor, name string) *mon
 func NewLimitedMonitor(
 	ctx context.Context, parent *mon.BytesMonitor, config *ServerConfig, name string,
 ) *mon.BytesMonitor {
+	limit := GetWorkMemLimit(config)
+	limitedMon := mon.MakeMonitorInheritWithLimit(name, limit, parent)
+	limitedMon.Start(ctx, parent, mon.BoundAccount{})
+	return &limitedMon
+}
+
+// GetWorkMemLimit returns the number of bytes determining the amount of RAM
+// available to a single processor or operator.
+func GetWorkMemLimit(config *ServerConfig) int64 {
 	limit := config.TestingKnobs.MemoryLimitBytes
 	if limit <= 0 {
 		limit = SettingWorkMemBytes.Get(&config.Settings.SV)
 	}
-	limitedMon := mon.MakeMonitorInheritWithLimit(name, limit, parent)
-	limitedMon.Start(ctx, parent, mon.BoundAccount{})
-	return &limitedMon
+	return limit
 }
 
 // GetInputStats is a utility function to check whether the given input is
@@ -122,6 +122,12 @@ type ServerConfig struct {
 	// working set is larger than can be stored in memory.
 	TempStorage diskmap.Factory
 
+	// TempStoragePath is the directory of the temporary storage, in which the
+	// vectorized engine writes the files of the operators that spill to disk.
+	// It is empty if the temporary storage is in memory, in which case the
+	// vectorized operators don't spill to disk.
+	TempStoragePath string
+
 	// BulkAdder is used by some processors to bulk-ingest data as SSTs.
 	BulkAdder storagebase.BulkAdderFactory
 
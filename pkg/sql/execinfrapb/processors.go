@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/errors"
 )
 
@@ -76,6 +77,63 @@ func GetAggregateInfo(
 	)
 }
 
+// GetWindowFunctionInfo returns windowFunc constructor and the return type
+// when given fn is applied to given inputTypes.
+func GetWindowFunctionInfo(
+	fn WindowerSpec_Func, inputTypes ...types.T,
+) (windowConstructor func(*tree.EvalContext) tree.WindowFunc, returnType *types.T, err error) {
+	if fn.AggregateFunc != nil && *fn.AggregateFunc == AggregatorSpec_ANY_NOT_NULL {
+		// The ANY_NOT_NULL builtin does not have a fixed return type;
+		// handle it separately.
+		if len(inputTypes) != 1 {
+			return nil, nil, errors.Errorf("any_not_null aggregate needs 1 input")
+		}
+		return builtins.NewAggregateWindowFunc(builtins.NewAnyNotNullAggregate), &inputTypes[0], nil
+	}
+	datumTypes := make([]*types.T, len(inputTypes))
+	for i := range inputTypes {
+		datumTypes[i] = &inputTypes[i]
+	}
+
+	var funcStr string
+	if fn.AggregateFunc != nil {
+		funcStr = fn.AggregateFunc.String()
+	} else if fn.WindowFunc != nil {
+		funcStr = fn.WindowFunc.String()
+	} else {
+		return nil, nil, errors.Errorf(
+			"function is neither an aggregate nor a window function",
+		)
+	}
+	props, builtins := builtins.GetBuiltinProperties(strings.ToLower(funcStr))
+	for _, b := range builtins {
+		typs := b.Types.Types()
+		if len(typs) != len(inputTypes) {
+			continue
+		}
+		match := true
+		for i, t := range typs {
+			if !datumTypes[i].Equivalent(t) {
+				if props.NullableArgs && datumTypes[i].IsAmbiguous() {
+					continue
+				}
+				match = false
+				break
+			}
+		}
+		if match {
+			// Found!
+			constructAgg := func(evalCtx *tree.EvalContext) tree.WindowFunc {
+				return b.WindowFunc(datumTypes, evalCtx)
+			}
+			return constructAgg, b.FixedReturnType(), nil
+		}
+	}
+	return nil, nil, errors.Errorf(
+		"no builtin aggregate/window function for %s on %v", funcStr, inputTypes,
+	)
+}
+
 // Equals returns true if two aggregation specifiers are identical (and thus
 // will always yield the same result).
 func (a AggregatorSpec_Aggregation) Equals(b AggregatorSpec_Aggregation) bool {
@@ -380,3 +438,76 @@ func (spec *WindowerSpec_Frame) ConvertToAST() (*tree.WindowFrame, error) {
 		Exclusion: exclusion,
 	}, nil
 }
+
+// InitWindowFrameRun sets up the frame of frameRun according to the spec,
+// decoding the offsets of its bounds. ordering is the ORDER BY clause of the
+// window function, and inputTypes are the types of the rows of the partition.
+func (spec *WindowerSpec_Frame) InitWindowFrameRun(
+	frameRun *tree.WindowFrameRun,
+	ordering Ordering,
+	inputTypes []types.T,
+	datumAlloc *sqlbase.DatumAlloc,
+) error {
+	var err error
+	if frameRun.Frame, err = spec.ConvertToAST(); err != nil {
+		return err
+	}
+	if frameRun.StartBoundOffset, err = spec.Bounds.Start.decodeOffset(spec.Mode, datumAlloc); err != nil {
+		return err
+	}
+	if spec.Bounds.End != nil {
+		if frameRun.EndBoundOffset, err = spec.Bounds.End.decodeOffset(spec.Mode, datumAlloc); err != nil {
+			return err
+		}
+	}
+	if frameRun.RangeModeWithOffsets() {
+		ordCol := ordering.Columns[0]
+		frameRun.OrdColIdx = int(ordCol.ColIdx)
+		// We need this +1 because encoding.Direction has extra value "_"
+		// as zeroth "entry" which its proto equivalent doesn't have.
+		frameRun.OrdDirection = encoding.Direction(ordCol.Direction + 1)
+
+		colTyp := &inputTypes[ordCol.ColIdx]
+		// Type of offset depends on the ordering column's type.
+		offsetTyp := colTyp
+		if types.IsDateTimeType(colTyp) {
+			// For datetime related ordering columns, offset must be an Interval.
+			offsetTyp = types.Interval
+		}
+		plusOp, minusOp, found := tree.WindowFrameRangeOps{}.LookupImpl(colTyp, offsetTyp)
+		if !found {
+			return pgerror.Newf(pgcode.Windowing,
+				"given logical offset cannot be combined with ordering column")
+		}
+		frameRun.PlusOp, frameRun.MinusOp = plusOp, minusOp
+	}
+	return nil
+}
+
+// decodeOffset returns the offset of the bound, or nil if the bound doesn't
+// have an offset.
+func (spec *WindowerSpec_Frame_Bound) decodeOffset(
+	mode WindowerSpec_Frame_Mode, datumAlloc *sqlbase.DatumAlloc,
+) (tree.Datum, error) {
+	if spec.BoundType != WindowerSpec_Frame_OFFSET_PRECEDING &&
+		spec.BoundType != WindowerSpec_Frame_OFFSET_FOLLOWING {
+		return nil, nil
+	}
+	switch mode {
+	case WindowerSpec_Frame_ROWS, WindowerSpec_Frame_GROUPS:
+		return tree.NewDInt(tree.DInt(int(spec.IntOffset))), nil
+	case WindowerSpec_Frame_RANGE:
+		datum, rem, err := sqlbase.DecodeTableValue(datumAlloc, &spec.OffsetType.Type, spec.TypedOffset)
+		if err != nil {
+			return nil, errors.NewAssertionErrorWithWrappedErrf(err,
+				"error decoding %d bytes", errors.Safe(len(spec.TypedOffset)))
+		}
+		if len(rem) != 0 {
+			return nil, errors.AssertionFailedf(
+				"%d trailing bytes in encoded value", errors.Safe(len(rem)))
+		}
+		return datum, nil
+	default:
+		return nil, errors.AssertionFailedf("unexpected WindowFrameMode: %d", errors.Safe(mode))
+	}
+}
@@ -74,6 +74,10 @@ type EngineMetrics struct {
 	SQLOptFallbackCount   *metric.Counter
 	SQLOptPlanCacheHits   *metric.Counter
 	SQLOptPlanCacheMisses *metric.Counter
+	// The subset of prepared statement executions which used a generic plan,
+	// either cached (hits) or built for the execution (misses).
+	SQLOptGenericPlanHits   *metric.Counter
+	SQLOptGenericPlanMisses *metric.Counter
 
 	DistSQLExecLatency    *metric.Histogram
 	SQLExecLatency        *metric.Histogram
@@ -207,4 +211,10 @@ func (ex *connExecutor) updateOptCounters(planFlags planFlags) {
 	} else if planFlags.IsSet(planFlagOptCacheMiss) {
 		m.SQLOptPlanCacheMisses.Inc(1)
 	}
+
+	if planFlags.IsSet(planFlagOptGenericPlanHit) {
+		m.SQLOptGenericPlanHits.Inc(1)
+	} else if planFlags.IsSet(planFlagOptGenericPlanMiss) {
+		m.SQLOptGenericPlanMisses.Inc(1)
+	}
 }
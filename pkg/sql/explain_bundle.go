@@ -0,0 +1,450 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// setExplainBundleResult creates the diagnostics and returns the bundle
+// information for an EXPLAIN ANALYZE (DEBUG) statement.
+//
+// Returns an error if information rows couldn't be added to the result.
+func setExplainBundleResult(
+	ctx context.Context,
+	res RestrictedCommandResult,
+	ast tree.Statement,
+	bundle diagnosticsBundle,
+	diagID stmtdiagnostics.CollectedInstanceID,
+	diagErr error,
+) error {
+	var text []string
+	switch {
+	case bundle.collectionErr != nil:
+		// We cannot simply set an error on the result here without
+		// changing the executor logic (e.g. an implicit transaction could have
+		// committed already). Just show the error in the result.
+		text = []string{fmt.Sprintf("Error generating bundle: %v", bundle.collectionErr)}
+	case diagErr != nil:
+		text = []string{fmt.Sprintf("Error recording bundle: %v", diagErr)}
+	default:
+		text = []string{
+			"Statement diagnostics bundle generated. Download from the Admin UI via the",
+			"link below (the bundle can also be retrieved from the",
+			"system.statement_diagnostics and system.statement_bundle_chunks tables).",
+			fmt.Sprintf("Direct link: %s%d", stmtBundleURLPath, diagID),
+		}
+	}
+
+	if err := res.Err(); err != nil {
+		// Add the bundle information as a detail to the query error.
+		res.SetError(errors.WithDetail(err, strings.Join(text, "\n")))
+		return nil
+	}
+
+	res.ResetStmtType(ast)
+	res.SetColumns(ctx, sqlbase.ExplainAnalyzeDebugColumns)
+	for _, line := range text {
+		if err := res.AddRow(ctx, tree.Datums{tree.NewDString(line)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stmtBundleURLPath is the path (relative to the Admin UI URL) under which
+// statement diagnostics bundles can be downloaded; the bundle ID is appended.
+const stmtBundleURLPath = "/_admin/v1/stmtbundle/"
+
+// diagnosticsBundle contains diagnostics information collected for a
+// statement.
+type diagnosticsBundle struct {
+	// zip contains the contents of the zip file.
+	zip []byte
+	// traceJSON is the trace of the statement, in the JSON format used by
+	// Jaeger.
+	traceJSON string
+	// collectionErr is set if there was an error building the bundle.
+	collectionErr error
+}
+
+// buildStatementBundle collects metadata related to the planning and
+// execution of the statement, and generates a diagnostics bundle. It is used
+// both for EXPLAIN ANALYZE (DEBUG) and for statement diagnostics requests.
+// The plan is nil if the statement failed before it was planned, in which case
+// only the error is recorded.
+//
+// The bundle is a zip file containing:
+//  - statement.txt: the statement.
+//  - opt.txt, opt-v.txt, opt-vv.txt: the optimizer plan with varying levels
+//    of detail.
+//  - distsql.json, distsql.txt: the physical plan diagram (as JSON and as a
+//    URL).
+//  - trace.txt, trace.json: the trace of the statement execution, in text
+//    form and in a JSON format that can be imported into Jaeger.
+//  - schema.sql: the CREATE statements for the objects used by the query.
+//  - stats-<table>.sql: the statistics for each table used by the query, as
+//    ALTER TABLE ... INJECT STATISTICS statements.
+//  - env.sql: the version and the session settings that affect planning.
+func buildStatementBundle(
+	ctx context.Context, p *planner, plan *planTop, trace tracing.Recording,
+) diagnosticsBundle {
+	if plan == nil {
+		return diagnosticsBundle{collectionErr: errors.New("execution terminated before planning")}
+	}
+	b := makeStmtBundleBuilder(p, plan, trace)
+
+	b.addStatement()
+	b.addOptPlans()
+	b.addDistSQLDiagram()
+	traceJSON := b.addTrace()
+	b.addEnv(ctx)
+
+	buf, err := b.finalize()
+	if err != nil {
+		return diagnosticsBundle{collectionErr: err}
+	}
+	return diagnosticsBundle{traceJSON: traceJSON, zip: buf.Bytes()}
+}
+
+// insert the bundle in statement diagnostics, returning the ID of the
+// inserted row.
+func (bundle *diagnosticsBundle) insert(
+	ctx context.Context,
+	recorder *stmtdiagnostics.Registry,
+	requestID stmtdiagnostics.RequestID,
+	ast tree.Statement,
+) (stmtdiagnostics.CollectedInstanceID, error) {
+	return recorder.InsertStatementDiagnostics(
+		ctx,
+		requestID,
+		anonymizeStmt(ast),
+		tree.AsString(ast),
+		bundle.traceJSON,
+		bundle.zip,
+		bundle.collectionErr,
+	)
+}
+
+// stmtBundleBuilder is a helper for building a statement bundle.
+type stmtBundleBuilder struct {
+	p     *planner
+	plan  *planTop
+	trace tracing.Recording
+
+	z memZipper
+}
+
+func makeStmtBundleBuilder(p *planner, plan *planTop, trace tracing.Recording) stmtBundleBuilder {
+	b := stmtBundleBuilder{p: p, plan: plan, trace: trace}
+	b.z.Init()
+	return b
+}
+
+// addStatement adds the pretty-printed statement as statement.txt.
+func (b *stmtBundleBuilder) addStatement() {
+	b.z.AddFile("statement.txt", tree.Pretty(b.plan.AST))
+}
+
+// addOptPlans adds the EXPLAIN (OPT) variants as files opt.txt, opt-v.txt,
+// opt-vv.txt.
+func (b *stmtBundleBuilder) addOptPlans() {
+	if b.plan.mem == nil || b.plan.mem.RootExpr() == nil {
+		// No optimizer plans; an example is a query that is fully handled
+		// through the heuristic planner.
+		return
+	}
+
+	formatOptPlan := func(flags memo.ExprFmtFlags) string {
+		f := memo.MakeExprFmtCtx(flags, b.plan.mem, b.plan.catalog)
+		f.FormatExpr(b.plan.mem.RootExpr())
+		return f.Buffer.String()
+	}
+
+	b.z.AddFile("opt.txt", formatOptPlan(memo.ExprFmtHideAll))
+	b.z.AddFile("opt-v.txt", formatOptPlan(
+		memo.ExprFmtHideQualifications|memo.ExprFmtHideScalars|memo.ExprFmtHideTypes,
+	))
+	b.z.AddFile("opt-vv.txt", formatOptPlan(memo.ExprFmtHideQualifications))
+}
+
+// addDistSQLDiagram adds the physical plan diagram as distsql.json and
+// distsql.txt (the latter containing a URL for viewing the diagram).
+func (b *stmtBundleBuilder) addDistSQLDiagram() {
+	if b.plan.distSQLDiagram == nil {
+		return
+	}
+	b.plan.distSQLDiagram.AddSpans(b.trace)
+	planJSON, planURL, err := b.plan.distSQLDiagram.ToURL()
+	if err != nil {
+		b.z.AddFile("distsql.error", err.Error())
+		return
+	}
+	b.z.AddFile("distsql.json", planJSON)
+	b.z.AddFile("distsql.txt", planURL.String())
+}
+
+// addTrace adds the trace as trace.txt and trace.json, and returns the JSON.
+func (b *stmtBundleBuilder) addTrace() string {
+	b.z.AddFile("trace.txt", b.trace.String())
+
+	traceJSON, err := b.trace.ToJaegerJSON(tree.AsString(b.plan.AST))
+	if err != nil {
+		b.z.AddFile("trace.json.error", err.Error())
+		return ""
+	}
+	b.z.AddFile("trace.json", traceJSON)
+	return traceJSON
+}
+
+// addEnv adds the version, the session settings that affect planning, the
+// schema of all the data sources used by the query and their statistics.
+func (b *stmtBundleBuilder) addEnv(ctx context.Context) {
+	c := makeStmtEnvCollector(ctx, b.p.execCfg.InternalExecutor)
+
+	var buf bytes.Buffer
+	if err := c.PrintVersion(&buf); err != nil {
+		fmt.Fprintf(&buf, "-- error getting version: %v\n", err)
+	}
+	fmt.Fprintf(&buf, "\n")
+	b.printSessionSettings(&buf)
+	b.z.AddFile("env.sql", buf.String())
+
+	if b.plan.mem == nil {
+		// No optimizer plans; we don't know which data sources the query used.
+		return
+	}
+	md := b.plan.mem.Metadata()
+	var tables, sequences, views []tree.TableName
+	seen := make(map[tree.TableName]bool)
+	addDS := func(list []tree.TableName, ds cat.DataSource) []tree.TableName {
+		tn, err := b.plan.catalog.FullyQualifiedName(ctx, ds)
+		if err != nil {
+			return list
+		}
+		if !seen[tn] {
+			seen[tn] = true
+			list = append(list, tn)
+		}
+		return list
+	}
+	for _, t := range md.AllTables() {
+		tables = addDS(tables, t.Table)
+	}
+	for _, s := range md.AllSequences() {
+		sequences = addDS(sequences, s)
+	}
+	for _, v := range md.AllViews() {
+		views = addDS(views, v)
+	}
+
+	buf.Reset()
+	for i := range sequences {
+		if err := c.PrintCreateSequence(&buf, &sequences[i]); err != nil {
+			fmt.Fprintf(&buf, "-- error getting schema for sequence %s: %v\n", sequences[i].String(), err)
+		}
+	}
+	for i := range tables {
+		if err := c.PrintCreateTable(&buf, &tables[i]); err != nil {
+			fmt.Fprintf(&buf, "-- error getting schema for table %s: %v\n", tables[i].String(), err)
+		}
+	}
+	for i := range views {
+		if err := c.PrintCreateView(&buf, &views[i]); err != nil {
+			fmt.Fprintf(&buf, "-- error getting schema for view %s: %v\n", views[i].String(), err)
+		}
+	}
+	b.z.AddFile("schema.sql", buf.String())
+
+	for i := range tables {
+		buf.Reset()
+		if err := c.PrintTableStats(&buf, &tables[i]); err != nil {
+			fmt.Fprintf(&buf, "-- error getting statistics for table %s: %v\n", tables[i].String(), err)
+		}
+		b.z.AddFile(fmt.Sprintf("stats-%s.sql", tables[i].String()), buf.String())
+	}
+}
+
+// printSessionSettings prints SET statements for the session variables of the
+// session that ran the statement which affect planning and that don't have
+// their default values.
+func (b *stmtBundleBuilder) printSessionSettings(buf *bytes.Buffer) {
+	for _, param := range []string{
+		"reorder_joins_limit",
+		"enable_zigzag_join",
+		"experimental_optimizer_foreign_keys",
+		"pg_trgm.similarity_threshold",
+		"vectorize",
+		"distsql",
+	} {
+		v, ok := varGen[param]
+		if !ok || v.Get == nil {
+			continue
+		}
+		value := v.Get(&b.p.extendedEvalCtx)
+		if v.GlobalDefault == nil || value != v.GlobalDefault(&b.p.execCfg.Settings.SV) {
+			fmt.Fprintf(buf, "SET %s = %s;\n", param, value)
+		}
+	}
+}
+
+// finalize generates the zipped bundle and returns it as a buffer.
+func (b *stmtBundleBuilder) finalize() (*bytes.Buffer, error) {
+	return b.z.Finalize()
+}
+
+// memZipper builds a zip file into an in-memory buffer.
+type memZipper struct {
+	buf *bytes.Buffer
+	z   *zip.Writer
+	err error
+}
+
+func (z *memZipper) Init() {
+	z.buf = &bytes.Buffer{}
+	z.z = zip.NewWriter(z.buf)
+}
+
+// AddFile adds a file to the zip; errors are deferred until Finalize.
+func (z *memZipper) AddFile(name string, contents string) {
+	if z.err != nil {
+		return
+	}
+	w, err := z.z.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		z.err = err
+		return
+	}
+	_, z.err = w.Write([]byte(contents))
+}
+
+// Finalize finishes the zip file and returns the buffer containing it.
+func (z *memZipper) Finalize() (*bytes.Buffer, error) {
+	if z.err != nil {
+		return nil, z.err
+	}
+	if err := z.z.Close(); err != nil {
+		return nil, err
+	}
+	buf := z.buf
+	*z = memZipper{}
+	return buf, nil
+}
+
+// stmtEnvCollector helps with gathering information about the "environment"
+// in which a statement was planned or run: version, settings, schema.
+type stmtEnvCollector struct {
+	ctx context.Context
+	ie  *InternalExecutor
+}
+
+func makeStmtEnvCollector(ctx context.Context, ie *InternalExecutor) stmtEnvCollector {
+	return stmtEnvCollector{ctx: ctx, ie: ie}
+}
+
+// query is a helper to run a query that returns a single string value.
+func (c *stmtEnvCollector) query(query string) (string, error) {
+	row, err := c.ie.QueryRow(c.ctx, "stmtEnvCollector", nil /* txn */, query)
+	if err != nil {
+		return "", err
+	}
+	if len(row) != 1 {
+		return "", errors.AssertionFailedf(
+			"expected env query %q to return a single column, returned %d",
+			query, len(row),
+		)
+	}
+	s, ok := row[0].(*tree.DString)
+	if !ok {
+		return "", errors.AssertionFailedf(
+			"expected env query %q to return a DString, returned %T",
+			query, row[0],
+		)
+	}
+	return string(*s), nil
+}
+
+func (c *stmtEnvCollector) PrintVersion(w *bytes.Buffer) error {
+	version, err := c.query("SELECT version()")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "-- Version: %s\n", version)
+	return nil
+}
+
+func (c *stmtEnvCollector) PrintCreateTable(w *bytes.Buffer, tn *tree.TableName) error {
+	createStatement, err := c.query(
+		fmt.Sprintf("SELECT create_statement FROM [SHOW CREATE TABLE %s]", tn.String()),
+	)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s;\n", createStatement)
+	return nil
+}
+
+func (c *stmtEnvCollector) PrintCreateSequence(w *bytes.Buffer, tn *tree.TableName) error {
+	createStatement, err := c.query(fmt.Sprintf(
+		"SELECT create_statement FROM [SHOW CREATE SEQUENCE %s]", tn.String(),
+	))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s;\n", createStatement)
+	return nil
+}
+
+func (c *stmtEnvCollector) PrintCreateView(w *bytes.Buffer, tn *tree.TableName) error {
+	createStatement, err := c.query(fmt.Sprintf(
+		"SELECT create_statement FROM [SHOW CREATE VIEW %s]", tn.String(),
+	))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s;\n", createStatement)
+	return nil
+}
+
+func (c *stmtEnvCollector) PrintTableStats(w *bytes.Buffer, tn *tree.TableName) error {
+	stats, err := c.query(fmt.Sprintf(
+		`SELECT jsonb_pretty(COALESCE(json_agg(stat), '[]'))
+		 FROM (
+			 SELECT json_array_elements(statistics) AS stat
+			 FROM [SHOW STATISTICS USING JSON FOR TABLE %s]
+		 )`,
+		tn.String(),
+	))
+	if err != nil {
+		return err
+	}
+
+	stats = strings.Replace(stats, "'", "''", -1)
+	fmt.Fprintf(w, "ALTER TABLE %s INJECT STATISTICS '%s';\n", tn.String(), stats)
+	return nil
+}
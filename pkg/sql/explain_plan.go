@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/colflow"
@@ -83,6 +84,10 @@ func (p *planner) makeExplainPlanNodeWithPlan(
 	columns = append(sqlbase.ResultColumns(nil), columns...)
 
 	e := explainer{explainFlags: flags}
+	if h := p.optPlanningCtx.hints; h != nil {
+		e.hints = h.Text
+		e.ignoredHints = p.optPlanningCtx.ignoredHints
+	}
 
 	noPlaceholderFlags := tree.FmtExpr(
 		tree.FmtSymbolicSubqueries, flags.showTypes, flags.symbolicVars, flags.qualifyNames,
@@ -184,6 +189,14 @@ type explainer struct {
 	// Meant for use with FmtCtx.WithPlaceholderFormat().
 	showPlaceholderValues func(ctx *tree.FmtCtx, placeholder *tree.Placeholder)
 
+	// hints is the textual representation of the plan hints attached to the
+	// fingerprint of the explained statement, if any.
+	hints string
+
+	// ignoredHints describes the hints that could not be applied (see
+	// optbuilder.Builder.IgnoredHints).
+	ignoredHints []string
+
 	// level is the current depth in the tree of planNodes.
 	level int
 
@@ -244,7 +257,9 @@ func (p *planner) populateExplain(
 		}
 	}
 
-	if err := appendExecutionDetails(ctx, v, e.showMetadata, isDistSQL, isVec); err != nil {
+	if err := appendExecutionDetails(
+		ctx, v, e.showMetadata, isDistSQL, isVec, e.hints, e.ignoredHints,
+	); err != nil {
 		return err
 	}
 
@@ -297,50 +312,51 @@ func (p *planner) populateExplain(
 }
 
 func appendExecutionDetails(
-	ctx context.Context, v *valuesNode, showMetadata, isDistSQL, isVec bool,
+	ctx context.Context,
+	v *valuesNode,
+	showMetadata, isDistSQL, isVec bool,
+	hints string,
+	ignoredHints []string,
 ) error {
-	var distSQLRow, vecRow tree.Datums
-	distSQLFieldName := tree.NewDString("distributed")
-	distSQLValue := tree.NewDString(fmt.Sprintf("%t", isDistSQL))
-	vecFieldName := tree.NewDString("vectorized")
-	vecValue := tree.NewDString(fmt.Sprintf("%t", isVec))
-	if !showMetadata {
-		distSQLRow = tree.Datums{
-			emptyString,      // Tree
-			distSQLFieldName, // Field
-			distSQLValue,     // Description
-		}
-		vecRow = tree.Datums{
-			emptyString,  // Tree
-			vecFieldName, // Field
-			vecValue,     // Description
-		}
-	} else {
-		distSQLRow = tree.Datums{
-			emptyString,      // Tree
-			tree.NewDInt(0),  // Level
-			emptyString,      // Type
-			distSQLFieldName, // Field
-			distSQLValue,     // Description
-			emptyString,      // Columns
-			emptyString,      // Ordering
-		}
-		vecRow = tree.Datums{
-			emptyString,     // Tree
-			tree.NewDInt(0), // Level
-			emptyString,     // Type
-			vecFieldName,    // Field
-			vecValue,        // Description
-			emptyString,     // Columns
-			emptyString,     // Ordering
+	addRow := func(field, value string) error {
+		var row tree.Datums
+		if !showMetadata {
+			row = tree.Datums{
+				emptyString,            // Tree
+				tree.NewDString(field), // Field
+				tree.NewDString(value), // Description
+			}
+		} else {
+			row = tree.Datums{
+				emptyString,            // Tree
+				tree.NewDInt(0),        // Level
+				emptyString,            // Type
+				tree.NewDString(field), // Field
+				tree.NewDString(value), // Description
+				emptyString,            // Columns
+				emptyString,            // Ordering
+			}
 		}
+		_, err := v.rows.AddRow(ctx, row)
+		return err
 	}
-	if _, err := v.rows.AddRow(ctx, distSQLRow); err != nil {
+	if err := addRow("distributed", fmt.Sprintf("%t", isDistSQL)); err != nil {
 		return err
 	}
-	if _, err := v.rows.AddRow(ctx, vecRow); err != nil {
+	if err := addRow("vectorized", fmt.Sprintf("%t", isVec)); err != nil {
 		return err
 	}
+	if hints != "" {
+		// Show the plan hints attached to the statement fingerprint, if any.
+		if err := addRow("statement hints", hints); err != nil {
+			return err
+		}
+	}
+	if len(ignoredHints) > 0 {
+		if err := addRow("ignored hints", strings.Join(ignoredHints, "; ")); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
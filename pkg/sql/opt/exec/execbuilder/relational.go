@@ -968,7 +968,7 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 // expression.
 func (b *Builder) extractAggregateConstArgs(agg opt.ScalarExpr) tree.Datums {
 	switch agg.Op() {
-	case opt.StringAggOp:
+	case opt.StringAggOp, opt.PercentileDiscOp, opt.PercentileContOp:
 		return tree.Datums{memo.ExtractConstDatum(agg.Child(1))}
 	default:
 		return nil
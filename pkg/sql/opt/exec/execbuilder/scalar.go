@@ -653,10 +653,10 @@ func (v *fastIsConstVisitor) run(expr tree.Expr) bool {
 // execution.
 func isVar(expr tree.Expr) bool {
 	switch expr.(type) {
-	case tree.VariableExpr:
+	case tree.VariableExpr, *tree.Placeholder:
+		// Placeholders remain in the generic plans of prepared statements, and
+		// their values can change with each execution.
 		return true
-	case *tree.Placeholder:
-		panic(errors.AssertionFailedf("placeholder should have been replaced"))
 	}
 	return false
 }
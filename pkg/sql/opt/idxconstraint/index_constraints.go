@@ -21,8 +21,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/geo/geoindex"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/errors"
 )
 
@@ -857,6 +860,128 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 			return true, append(constraints, out)
 		}
 
+	case opt.TSMatchesOp:
+		// The tsvector can be on either side of the @@ operator.
+		vec, query := nd.Child(0), nd.Child(1)
+		if !c.isIndexColumn(vec, 0 /* index */) {
+			vec, query = query, vec
+		}
+		// A string can also be matched with a tsquery, but the trigram index of
+		// a string column cannot be used for that.
+		if !c.isIndexColumn(vec, 0 /* index */) || c.colType(0).Family() != types.TSVectorFamily ||
+			!opt.IsConstValueOp(query) {
+			c.unconstrained(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+
+		queryDatum := memo.ExtractConstDatum(query)
+		if queryDatum == tree.DNull {
+			c.contradiction(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+		q := tree.MustBeDTSQuery(queryDatum).TSQuery
+		if q.Root == nil {
+			// An empty query doesn't match any document.
+			c.contradiction(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+
+		// The index contains one key per lexeme of each document, so we can
+		// only constrain the scan on the lexemes that every matching document
+		// must contain. The span is tight if the query is a single lexeme
+		// without weights, since the weights are not indexed.
+		tight = q.Root.Op == tsearch.Term && q.Root.Weights == 0
+		for _, term := range requiredTSQueryTerms(q.Root, nil /* terms */) {
+			lexeme := tsearch.TSVector{{Word: term.Lexeme}}
+			c.eqSpan(0 /* offset */, tree.NewDTSVector(lexeme), out)
+			constraints = append(constraints, out)
+			constrained = true
+			if !allPaths {
+				return tight, constraints
+			}
+			// Reset out for next iteration
+			out = &constraint.Constraint{}
+		}
+		if constrained {
+			return tight, constraints
+		}
+
+	case opt.LikeOp, opt.ILikeOp, opt.RegMatchOp, opt.RegIMatchOp:
+		lhs, rhs := nd.Child(0), nd.Child(1)
+		if !c.isIndexColumn(lhs, 0 /* index */) || c.colType(0).Family() != types.StringFamily ||
+			!opt.IsConstValueOp(rhs) {
+			c.unconstrained(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+
+		patternDatum := memo.ExtractConstDatum(rhs)
+		if patternDatum == tree.DNull {
+			c.contradiction(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+		pattern, ok := patternDatum.(*tree.DString)
+		if !ok {
+			break
+		}
+		var trigrams []string
+		if nd.Op() == opt.LikeOp || nd.Op() == opt.ILikeOp {
+			trigrams = trigram.LikeTrigrams(string(*pattern))
+		} else {
+			var err error
+			trigrams, err = trigram.RegexpTrigrams(string(*pattern))
+			if err != nil {
+				// The invalid pattern is reported when the filter is evaluated.
+				break
+			}
+		}
+
+		// The index contains one key per trigram of each string, so we can only
+		// constrain the scan on the trigrams that every matching string must
+		// contain. The spans are never tight, since a string can contain all
+		// the trigrams of a pattern without matching it.
+		for _, t := range trigrams {
+			c.eqSpan(0 /* offset */, tree.NewDString(t), out)
+			constraints = append(constraints, out)
+			constrained = true
+			if !allPaths {
+				return false, constraints
+			}
+			// Reset out for next iteration
+			out = &constraint.Constraint{}
+		}
+		if constrained {
+			return false, constraints
+		}
+
+	case opt.ModOp:
+		// The % operator of strings matches the strings whose similarity with
+		// the other string is at least pg_trgm.similarity_threshold.
+		if c.evalCtx.SessionData.SimilarityThreshold > 0 &&
+			c.makeTrigramSimilaritySpans(nd.Child(0), nd.Child(1), out) {
+			return false, append(constraints, out)
+		}
+
+	case opt.GtOp, opt.GeOp:
+		// A similarity(s, 'const') > threshold condition constrains the scan if
+		// the threshold is high enough that the similar strings must share at
+		// least one trigram with the constant.
+		fn, ok := nd.Child(0).(*memo.FunctionExpr)
+		if !ok || fn.Name != "similarity" || !opt.IsConstValueOp(nd.Child(1)) {
+			break
+		}
+		threshold, ok := floatFromDatum(memo.ExtractConstDatum(nd.Child(1)))
+		if !ok || threshold < 0 || (threshold == 0 && nd.Op() == opt.GeOp) {
+			break
+		}
+		if c.makeTrigramSimilaritySpans(fn.Args[0], fn.Args[1], out) {
+			return false, append(constraints, out)
+		}
+
+	case opt.FunctionOp:
+		if c.makeSpatialSpans(nd.(*memo.FunctionExpr), out) {
+			return false, append(constraints, out)
+		}
+
 	case opt.AndOp, opt.FiltersOp:
 		for i, n := 0, nd.ChildCount(); i < n; i++ {
 			tight, constraints = c.makeInvertedIndexSpansForExpr(
@@ -898,6 +1023,171 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 	return false, constraints
 }
 
+// makeTrigramSimilaritySpans builds the spans of a trigram index that contain
+// the strings which are similar to a constant string, given the two operands
+// of a similarity comparison. Since the similarity of two strings is only
+// greater than 0 if they share a trigram, the spans are the union of the spans
+// of the trigrams of the constant. Returns false if the operands are not the
+// index column and a constant string.
+func (c *indexConstraintCtx) makeTrigramSimilaritySpans(
+	left, right opt.Expr, out *constraint.Constraint,
+) bool {
+	// The similarity is symmetric, so the column can be on either side.
+	if !c.isIndexColumn(left, 0 /* index */) {
+		left, right = right, left
+	}
+	if !c.isIndexColumn(left, 0 /* index */) || c.colType(0).Family() != types.StringFamily ||
+		!opt.IsConstValueOp(right) {
+		return false
+	}
+
+	datum := memo.ExtractConstDatum(right)
+	if datum == tree.DNull {
+		c.contradiction(0 /* offset */, out)
+		return true
+	}
+	str, ok := datum.(*tree.DString)
+	if !ok {
+		return false
+	}
+	trigrams := trigram.MakeTrigrams(string(*str))
+	if len(trigrams) == 0 {
+		// The similarity with a string without trigrams is always 0.
+		c.contradiction(0 /* offset */, out)
+		return true
+	}
+	c.eqSpan(0 /* offset */, tree.NewDString(trigrams[0]), out)
+	for _, t := range trigrams[1:] {
+		var other constraint.Constraint
+		c.eqSpan(0 /* offset */, tree.NewDString(t), &other)
+		out.UnionWith(c.evalCtx, &other)
+	}
+	return true
+}
+
+// makeSpatialSpans builds the spans of a spatial index that contain the shapes
+// which can satisfy a spatial predicate with a constant shape. These are the
+// shapes whose coverings contain a cell of the covering of the constant (or of
+// the area within the distance of the constant, for st_dwithin), or an
+// ancestor or descendant of such a cell. The spans are never tight, since two
+// shapes with related coverings don't necessarily satisfy the predicate.
+// Returns false if the function is not a spatial predicate of the index column
+// and a constant.
+func (c *indexConstraintCtx) makeSpatialSpans(
+	fn *memo.FunctionExpr, out *constraint.Constraint,
+) bool {
+	var distance tree.Datum
+	switch fn.Name {
+	case "st_intersects", "st_covers", "st_contains":
+		if len(fn.Args) != 2 {
+			return false
+		}
+	case "st_dwithin":
+		if len(fn.Args) != 3 || !opt.IsConstValueOp(fn.Args[2]) {
+			return false
+		}
+		distance = memo.ExtractConstDatum(fn.Args[2])
+	default:
+		return false
+	}
+
+	// The index column can be either of the shapes.
+	col, shape := fn.Args[0], fn.Args[1]
+	if !c.isIndexColumn(col, 0 /* index */) {
+		col, shape = shape, col
+	}
+	family := c.colType(0).Family()
+	if !c.isIndexColumn(col, 0 /* index */) || !opt.IsConstValueOp(shape) ||
+		(family != types.GeometryFamily && family != types.GeographyFamily) {
+		return false
+	}
+
+	datum := memo.ExtractConstDatum(shape)
+	if datum == tree.DNull || distance == tree.DNull {
+		c.contradiction(0 /* offset */, out)
+		return true
+	}
+	var d float64
+	if distance != nil {
+		var ok bool
+		if d, ok = floatFromDatum(distance); !ok || d < 0 {
+			// A negative distance is reported when the filter is evaluated.
+			return false
+		}
+	}
+	var covering []geoindex.CellID
+	switch t := datum.(type) {
+	case *tree.DGeometry:
+		if distance != nil {
+			covering = geoindex.GeometryDWithinCovering(t.Geometry, d)
+		} else {
+			covering = geoindex.GeometryCovering(t.Geometry)
+		}
+	case *tree.DGeography:
+		if distance != nil {
+			covering = geoindex.GeographyDWithinCovering(t.Geography, d)
+		} else {
+			covering = geoindex.GeographyCovering(t.Geography)
+		}
+	default:
+		return false
+	}
+	if len(covering) == 0 {
+		// An empty shape doesn't satisfy any spatial predicate.
+		c.contradiction(0 /* offset */, out)
+		return true
+	}
+
+	// The index is keyed by the integer IDs of the cells.
+	for i, s := range geoindex.QuerySpans(covering) {
+		var span constraint.Span
+		span.Init(
+			constraint.MakeKey(tree.NewDInt(tree.DInt(s.Start))), includeBoundary,
+			constraint.MakeKey(tree.NewDInt(tree.DInt(s.End))), includeBoundary,
+		)
+		if i == 0 {
+			out.InitSingleSpan(&c.keyCtx[0], &span)
+			continue
+		}
+		var other constraint.Constraint
+		other.InitSingleSpan(&c.keyCtx[0], &span)
+		out.UnionWith(c.evalCtx, &other)
+	}
+	return true
+}
+
+// floatFromDatum returns the value of a numeric constant as a float.
+func floatFromDatum(d tree.Datum) (float64, bool) {
+	switch t := d.(type) {
+	case *tree.DFloat:
+		return float64(*t), true
+	case *tree.DDecimal:
+		f, err := t.Float64()
+		return f, err == nil
+	case *tree.DInt:
+		return float64(*t), true
+	}
+	return 0, false
+}
+
+// requiredTSQueryTerms appends to terms the Term nodes of a tsquery whose
+// lexemes must be contained in every document that matches the query; these
+// are the terms of the top-level conjunction. Prefix terms are skipped because
+// they can match several lexemes of the same document, which would produce
+// duplicate rows when scanning the index.
+func requiredTSQueryTerms(n *tsearch.Node, terms []*tsearch.Node) []*tsearch.Node {
+	switch n.Op {
+	case tsearch.Term:
+		if !n.Prefix {
+			terms = append(terms, n)
+		}
+	case tsearch.And:
+		terms = requiredTSQueryTerms(n.Left, terms)
+		terms = requiredTSQueryTerms(n.Right, terms)
+	}
+	return terms
+}
+
 // getMaxSimplifyPrefix finds the longest prefix (maxSimplifyPrefix) such that
 // every span has the same first maxSimplifyPrefix values for the start and end
 // key. For example, for:
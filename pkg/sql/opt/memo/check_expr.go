@@ -205,11 +205,14 @@ func (m *Memo) checkExpr(e opt.Expr) {
 			}
 		}
 
-		if e.Op() == opt.StringAggOp && !CanExtractConstDatum(e.Child(1)) {
-			panic(errors.AssertionFailedf(
-				"second argument to StringAggOp must always be constant, but got %s",
-				log.Safe(e.Child(1).Op()),
-			))
+		switch e.Op() {
+		case opt.StringAggOp, opt.PercentileDiscOp, opt.PercentileContOp:
+			if !CanExtractConstDatum(e.Child(1)) {
+				panic(errors.AssertionFailedf(
+					"second argument to %s must always be constant, but got %s",
+					log.Safe(e.Op()), log.Safe(e.Child(1).Op()),
+				))
+			}
 		}
 
 		if opt.IsJoinOp(e) {
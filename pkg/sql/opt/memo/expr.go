@@ -361,6 +361,31 @@ func (sf *ScanFlags) Empty() bool {
 	return !sf.NoIndexJoin && !sf.ForceIndex
 }
 
+// MayReturnDuplicates returns true if the scan can return the same row more
+// than once. This is the case of a scan over the trigram inverted index of a
+// string column that is constrained by more than one span: every string has
+// many trigrams, so a row is returned for each span that contains one of its
+// trigrams. It is also the case of any constrained scan over the inverted
+// index of a spatial column, since a shape is indexed by up to 4 cells, which
+// can all be in the same span. The rows of such a scan must be deduplicated
+// before they are joined to the primary index.
+func (s *ScanPrivate) MayReturnDuplicates(md *opt.Metadata) bool {
+	if s.Constraint == nil {
+		return false
+	}
+	index := md.Table(s.Table).Index(s.Index)
+	if !index.IsInverted() {
+		return false
+	}
+	switch index.Column(0).DatumType().Family() {
+	case types.StringFamily:
+		return s.Constraint.Spans.Count() > 1
+	case types.GeometryFamily, types.GeographyFamily:
+		return true
+	}
+	return false
+}
+
 // JoinFlags stores restrictions on the join execution method, derived from
 // hints for a join specified in the query (see tree.JoinTableExpr).
 type JoinFlags struct {
@@ -520,7 +545,7 @@ func ExprIsNeverNull(e opt.ScalarExpr, notNullCols opt.ColSet) bool {
 	case *AndExpr, *OrExpr, *GeExpr, *GtExpr, *NeExpr, *EqExpr, *LeExpr, *LtExpr, *LikeExpr,
 		*NotLikeExpr, *ILikeExpr, *NotILikeExpr, *SimilarToExpr, *NotSimilarToExpr, *RegMatchExpr,
 		*NotRegMatchExpr, *RegIMatchExpr, *NotRegIMatchExpr, *ContainsExpr, *JsonExistsExpr,
-		*JsonAllExistsExpr, *JsonSomeExistsExpr, *AnyScalarExpr, *BitandExpr, *BitorExpr, *BitxorExpr,
+		*JsonAllExistsExpr, *JsonSomeExistsExpr, *TSMatchesExpr, *AnyScalarExpr, *BitandExpr, *BitorExpr, *BitxorExpr,
 		*PlusExpr, *MinusExpr, *MultExpr, *DivExpr, *FloorDivExpr, *ModExpr, *PowExpr, *ConcatExpr,
 		*LShiftExpr, *RShiftExpr, *WhenExpr:
 		return ExprIsNeverNull(t.Child(0).(opt.ScalarExpr), notNullCols) &&
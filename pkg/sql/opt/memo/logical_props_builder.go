@@ -87,6 +87,14 @@ func (b *logicalPropsBuilder) buildScanProps(scan *ScanExpr, rel *props.Relation
 	// that def.HardLimit = 0 indicates there is no known limit.
 	if hardLimit == 1 {
 		rel.FuncDeps.MakeMax1Row(rel.OutputCols)
+	} else if scan.MayReturnDuplicates(md) {
+		// The scan can return the same row more than once, so the table key is
+		// not a key of the scan; only the constant columns are known.
+		if scan.Constraint != nil {
+			rel.FuncDeps.AddConstants(scan.Constraint.ExtractConstCols(b.evalCtx))
+		}
+		rel.FuncDeps.MakeNotNull(rel.NotNullCols)
+		rel.FuncDeps.ProjectCols(rel.OutputCols)
 	} else {
 		// Initialize key FD's from the table schema, including constant columns from
 		// the constraint, minus any columns that are not projected by the Scan
@@ -132,12 +132,13 @@ type Memo struct {
 
 	// The following are selected fields from SessionData which can affect
 	// planning. We need to cross-check these before reusing a cached memo.
-	dataConversion    sessiondata.DataConversionConfig
-	reorderJoinsLimit int
-	zigzagJoinEnabled bool
-	optimizerFKs      bool
-	safeUpdates       bool
-	saveTablesPrefix  string
+	dataConversion      sessiondata.DataConversionConfig
+	reorderJoinsLimit   int
+	zigzagJoinEnabled   bool
+	optimizerFKs        bool
+	safeUpdates         bool
+	saveTablesPrefix    string
+	similarityThreshold float64
 
 	// curID is the highest currently in-use scalar expression ID.
 	curID opt.ScalarID
@@ -170,6 +171,7 @@ func (m *Memo) Init(evalCtx *tree.EvalContext) {
 	m.optimizerFKs = evalCtx.SessionData.OptimizerFKs
 	m.safeUpdates = evalCtx.SessionData.SafeUpdates
 	m.saveTablesPrefix = evalCtx.SessionData.SaveTablesPrefix
+	m.similarityThreshold = evalCtx.SessionData.SimilarityThreshold
 
 	m.curID = 0
 	m.curWithID = 0
@@ -277,7 +279,8 @@ func (m *Memo) IsStale(
 		m.zigzagJoinEnabled != evalCtx.SessionData.ZigzagJoinEnabled ||
 		m.optimizerFKs != evalCtx.SessionData.OptimizerFKs ||
 		m.safeUpdates != evalCtx.SessionData.SafeUpdates ||
-		m.saveTablesPrefix != evalCtx.SessionData.SaveTablesPrefix {
+		m.saveTablesPrefix != evalCtx.SessionData.SaveTablesPrefix ||
+		m.similarityThreshold != evalCtx.SessionData.SimilarityThreshold {
 		return true, nil
 	}
 
@@ -179,6 +179,12 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData.SafeUpdates = false
 	notStale()
 
+	// Stale similarity threshold.
+	evalCtx.SessionData.SimilarityThreshold = 0.5
+	stale()
+	evalCtx.SessionData.SimilarityThreshold = 0
+	notStale()
+
 	// Stale data sources and schema. Create new catalog so that data sources are
 	// recreated and can be modified independently.
 	catalog = testcat.New()
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -462,7 +463,28 @@ func (sb *statisticsBuilder) colStatLeaf(
 	} else {
 		distinctCount := 1.0
 		nullCount := 0.0
-		colSet.ForEach(func(i opt.ColumnID) {
+
+		// If there is a multi-column statistic on a subset of colSet (e.g., one
+		// collected on an index prefix), start from that statistic, since it
+		// accounts for correlation between its columns. Assume independence for
+		// the remaining columns.
+		var subset opt.ColSet
+		for i, n := 0, s.ColStats.Count(); i < n; i++ {
+			cols := s.ColStats.Get(i).Cols
+			if cols.Len() > 1 && cols.Len() > subset.Len() &&
+				cols.SubsetOf(colSet) && !cols.Equals(colSet) {
+				subset = cols
+			}
+		}
+		remaining := colSet
+		if !subset.Empty() {
+			subsetStat, _ := s.ColStats.Lookup(subset)
+			distinctCount = subsetStat.DistinctCount
+			nullCount = subsetStat.NullCount
+			remaining = colSet.Difference(subset)
+		}
+
+		remaining.ForEach(func(i opt.ColumnID) {
 			colStatLeaf := sb.colStatLeaf(opt.MakeColSet(i), s, fd, notNullCols)
 			distinctCount *= colStatLeaf.DistinctCount
 			if nullCount < s.RowCount {
@@ -591,6 +613,7 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 		// -----------------------------------
 		s.ApplySelectivity(sb.selectivityFromHistograms(histCols, scan, s))
 		s.ApplySelectivity(sb.selectivityFromDistinctCounts(constrainedCols.Difference(histCols), scan, s))
+		s.ApplySelectivity(sb.correlationFromMultiColDistinctCounts(constrainedCols.Difference(histCols), scan, s))
 		s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(numUnappliedConjuncts))
 		s.ApplySelectivity(sb.selectivityFromNullsRemoved(scan, relProps, constrainedCols))
 	}
@@ -689,6 +712,7 @@ func (sb *statisticsBuilder) buildSelect(sel *SelectExpr, relProps *props.Relati
 	s.RowCount = inputStats.RowCount
 	s.ApplySelectivity(sb.selectivityFromHistograms(histCols, sel, s))
 	s.ApplySelectivity(sb.selectivityFromDistinctCounts(constrainedCols.Difference(histCols), sel, s))
+	s.ApplySelectivity(sb.correlationFromMultiColDistinctCounts(constrainedCols.Difference(histCols), sel, s))
 	s.ApplySelectivity(sb.selectivityFromEquivalencies(equivReps, &relProps.FuncDeps, sel, s))
 	s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(numUnappliedConjuncts))
 	s.ApplySelectivity(sb.selectivityFromNullsRemoved(sel, relProps, constrainedCols))
@@ -941,6 +965,7 @@ func (sb *statisticsBuilder) buildJoin(
 		s.ApplySelectivity(sb.selectivityFromEquivalencies(equivReps, &h.filtersFD, join, s))
 	}
 
+	s.ApplySelectivity(sb.correlationFromMultiColDistinctCounts(constrainedCols, join, s))
 	s.ApplySelectivity(sb.selectivityFromDistinctCounts(constrainedCols, join, s))
 	s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(numUnappliedConjuncts))
 	s.ApplySelectivity(sb.selectivityFromNullsRemoved(join, relProps, constrainedCols))
@@ -1447,6 +1472,7 @@ func (sb *statisticsBuilder) buildZigzagJoin(
 
 	// Calculate selectivity and row count
 	// -----------------------------------
+	s.ApplySelectivity(sb.correlationFromMultiColDistinctCounts(constrainedCols, zigzag, s))
 	s.ApplySelectivity(sb.selectivityFromDistinctCounts(constrainedCols, zigzag, s))
 	s.ApplySelectivity(sb.selectivityFromEquivalencies(equivReps, &relProps.FuncDeps, zigzag, s))
 	s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(numUnappliedConjuncts))
@@ -2971,6 +2997,113 @@ func (sb *statisticsBuilder) selectivityFromDistinctCounts(
 	return selectivity
 }
 
+// correlationFromMultiColDistinctCounts returns a factor by which to scale
+// the selectivity calculated by selectivityFromDistinctCounts, which assumes
+// that the constrained columns are completely independent. If there is a
+// multi-column table statistic on a subset of the constrained columns, the
+// selectivity of the predicate on those columns is instead estimated as:
+//
+//                      new distinct(multi-column subset)
+//   selectivity(m) =  -----------------------------------
+//                      old distinct(multi-column subset)
+//
+// where the new distinct count is the product of the new single-column
+// distinct counts. Since the distinct counts are only estimates, selectivity(m)
+// is bounded below by the product of the single-column selectivities (complete
+// independence) and above by the minimum single-column selectivity (complete
+// correlation). The returned factor is selectivity(m) divided by the product
+// of the single-column selectivities, so it is always >= 1.
+//
+// For example, if a table has columns city and zip with 100 and 1000 distinct
+// values respectively, but only 1000 distinct (city, zip) pairs, the predicate
+// city = 'x' AND zip = 'y' has an independent selectivity of 1/100000, but a
+// correlated selectivity of 1/1000. The returned factor is 100.
+//
+// Columns whose selectivity was calculated by selectivityFromHistograms must
+// not be passed in cols. Histograms already capture the selectivity of each
+// column more precisely than the distinct counts, and the factor is derived
+// from the distinct counts, so applying it on top of the histogram
+// selectivity could count the correlation twice.
+func (sb *statisticsBuilder) correlationFromMultiColDistinctCounts(
+	cols opt.ColSet, e RelExpr, s *props.Statistics,
+) float64 {
+	multiCols := sb.multiColStatCols(cols, s)
+	if multiCols.Empty() {
+		return 1
+	}
+
+	newDistinct := 1.0
+	indepSelectivity, minSelectivity := 1.0, 1.0
+	for col, ok := multiCols.Next(0); ok; col, ok = multiCols.Next(col + 1) {
+		colStat, _ := s.ColStats.Lookup(opt.MakeColSet(col))
+		inputColStat, _ := sb.colStatFromInput(colStat.Cols, e)
+		colNewDistinct := nonNullDistinctCount(colStat)
+		colSelectivity := fraction(colNewDistinct, nonNullDistinctCount(inputColStat))
+		newDistinct *= colNewDistinct
+		indepSelectivity *= colSelectivity
+		minSelectivity = min(minSelectivity, colSelectivity)
+	}
+	if indepSelectivity == 0 {
+		return 1
+	}
+
+	inputColStat, _ := sb.colStatFromInput(multiCols, e)
+	selectivity := fraction(newDistinct, nonNullDistinctCount(inputColStat))
+	selectivity = max(indepSelectivity, min(selectivity, minSelectivity))
+	return selectivity / indepSelectivity
+}
+
+// multiColStatCols returns the largest subset of the given columns that is
+// covered by a multi-column table statistic, and for which each column has a
+// column statistic in s. If there is no such subset, multiColStatCols returns
+// the empty set.
+func (sb *statisticsBuilder) multiColStatCols(
+	cols opt.ColSet, s *props.Statistics,
+) (multiCols opt.ColSet) {
+	var available opt.ColSet
+	var tables util.FastIntSet
+	for col, ok := cols.Next(0); ok; col, ok = cols.Next(col + 1) {
+		if _, ok := s.ColStats.Lookup(opt.MakeColSet(col)); !ok {
+			continue
+		}
+		available.Add(col)
+		if tabID := sb.md.ColumnMeta(col).Table; tabID != 0 {
+			tables.Add(int(tabID))
+		}
+	}
+	if available.Len() < 2 {
+		return opt.ColSet{}
+	}
+
+	tables.ForEach(func(i int) {
+		tabID := opt.TableID(i)
+		tab := sb.md.Table(tabID)
+		for j := 0; j < tab.StatisticCount(); j++ {
+			stat := tab.Statistic(j)
+			if stat.ColumnCount() < 2 || stat.ColumnCount() <= multiCols.Len() {
+				continue
+			}
+			var statCols opt.ColSet
+			for k := 0; k < stat.ColumnCount(); k++ {
+				statCols.Add(tabID.ColumnID(stat.ColumnOrdinal(k)))
+			}
+			if statCols.SubsetOf(available) {
+				multiCols = statCols
+			}
+		}
+	})
+	return multiCols
+}
+
+// nonNullDistinctCount returns the distinct count of the given column
+// statistic, excluding the null value.
+func nonNullDistinctCount(colStat *props.ColumnStatistic) float64 {
+	if colStat.NullCount > 0 {
+		return max(colStat.DistinctCount-1, 0)
+	}
+	return colStat.DistinctCount
+}
+
 // selectivityFromHistograms is similar to selectivityFromDistinctCounts, in
 // that it calculates the selectivity of a filter by taking the product of
 // selectivities of each constrained column.
@@ -177,6 +177,9 @@ func init() {
 	// a large number of possible overloads or where ReturnType depends on
 	// argument types.
 	typingFuncMap[opt.ArrayAggOp] = typeArrayAgg
+	typingFuncMap[opt.PercentileDiscOp] = typePercentile
+	typingFuncMap[opt.PercentileContOp] = typePercentileCont
+	typingFuncMap[opt.ModeOp] = typeAsFirstArg
 	typingFuncMap[opt.MaxOp] = typeAsFirstArg
 	typingFuncMap[opt.MinOp] = typeAsFirstArg
 	typingFuncMap[opt.ConstAggOp] = typeAsFirstArg
@@ -235,6 +238,27 @@ func typeArrayAgg(e opt.ScalarExpr) *types.T {
 	return types.MakeArray(typ)
 }
 
+// typePercentile returns the type of a percentile_disc or percentile_cont
+// aggregate, which is the type of its input, or an array of that type if the
+// fraction argument is an array.
+func typePercentile(e opt.ScalarExpr) *types.T {
+	typ := e.Child(0).(opt.ScalarExpr).DataType()
+	if e.Child(1).(opt.ScalarExpr).DataType().Family() == types.ArrayFamily {
+		return types.MakeArray(typ)
+	}
+	return typ
+}
+
+// typePercentileCont returns the type of a percentile_cont aggregate, which
+// interpolates integer and decimal input values as floats.
+func typePercentileCont(e opt.ScalarExpr) *types.T {
+	typ := builtins.PercentileContReturnType(e.Child(0).(opt.ScalarExpr).DataType())
+	if e.Child(1).(opt.ScalarExpr).DataType().Family() == types.ArrayFamily {
+		return types.MakeArray(typ)
+	}
+	return typ
+}
+
 // typeIndirection returns the type of the element of the array.
 func typeIndirection(e opt.ScalarExpr) *types.T {
 	return e.Child(0).(opt.ScalarExpr).DataType().ArrayContents()
@@ -137,6 +137,7 @@ var ComparisonOpReverseMap = map[Operator]tree.ComparisonOperator{
 	JsonSomeExistsOp: tree.JSONSomeExists,
 	JsonAllExistsOp:  tree.JSONAllExists,
 	OverlapsOp:       tree.Overlaps,
+	TSMatchesOp:      tree.TSMatches,
 }
 
 // BinaryOpReverseMap maps from an optimizer operator type to a semantic tree
@@ -191,6 +192,9 @@ var AggregateOpReverseMap = map[Operator]string{
 	JsonAggOp:         "json_agg",
 	JsonbAggOp:        "jsonb_agg",
 	StringAggOp:       "string_agg",
+	PercentileDiscOp:  "percentile_disc",
+	PercentileContOp:  "percentile_cont",
+	ModeOp:            "mode",
 	ConstAggOp:        "any_not_null",
 	ConstNotNullAggOp: "any_not_null",
 	AnyNotNullAggOp:   "any_not_null",
@@ -258,7 +262,7 @@ func AggregateIgnoresNulls(op Operator) bool {
 	switch op {
 	case AvgOp, BitAndAggOp, BitOrAggOp, BoolAndOp, BoolOrOp, CountOp, MaxOp, MinOp,
 		SumIntOp, SumOp, SqrDiffOp, VarianceOp, StdDevOp, XorAggOp, ConstNotNullAggOp,
-		AnyNotNullAggOp, StringAggOp:
+		AnyNotNullAggOp, StringAggOp, PercentileDiscOp, PercentileContOp, ModeOp:
 		return true
 	}
 	return false
@@ -273,7 +277,8 @@ func AggregateIsNullOnEmpty(op Operator) bool {
 	switch op {
 	case AvgOp, BitAndAggOp, BitOrAggOp, BoolAndOp, BoolOrOp, MaxOp, MinOp, SumIntOp,
 		SumOp, SqrDiffOp, VarianceOp, StdDevOp, XorAggOp, ConstAggOp, ConstNotNullAggOp, ArrayAggOp,
-		ConcatAggOp, JsonAggOp, JsonbAggOp, AnyNotNullAggOp, StringAggOp:
+		ConcatAggOp, JsonAggOp, JsonbAggOp, AnyNotNullAggOp, StringAggOp, PercentileDiscOp,
+		PercentileContOp, ModeOp:
 		return true
 	}
 	return false
@@ -70,6 +70,12 @@ type Builder struct {
 	// This is used when re-preparing invalidated queries.
 	KeepPlaceholders bool
 
+	// IndexHints, if set, contains index flags to apply to the table data
+	// sources (keyed by unqualified table name) that don't have an inline index
+	// hint. This is used to apply the plan hints attached to a statement
+	// fingerprint (see sql/stmthints).
+	IndexHints map[tree.Name]*tree.IndexFlags
+
 	// -- Results --
 	//
 	// These fields are set during the building process and can be used after
@@ -84,6 +90,12 @@ type Builder struct {
 	// statements.
 	DisableMemoReuse bool
 
+	// IgnoredHints contains a description of each index hint in IndexHints
+	// that could not be applied because it refers to an index that doesn't
+	// exist (for example, because the index was dropped after the hint was
+	// set).
+	IgnoredHints []string
+
 	factory *norm.Factory
 	stmt    tree.Statement
 
@@ -70,6 +70,13 @@ func (b *Builder) buildExplain(explain *tree.Explain, inScope *scope) (outScope
 		telemetry.Inc(sqltelemetry.ExplainVecUseCounter)
 		cols = sqlbase.ExplainVecColumns
 
+	case tree.ExplainDebug:
+		// A top-level EXPLAIN ANALYZE (DEBUG) is intercepted by the executor,
+		// which runs the inner statement directly; we only get here when the
+		// statement is prepared or nested inside another statement. The latter
+		// case is rejected by the exec factory.
+		cols = sqlbase.ExplainAnalyzeDebugColumns
+
 	default:
 		panic(pgerror.Newf(pgcode.FeatureNotSupported,
 			"EXPLAIN ANALYZE does not support RETURNING NOTHING statements"))
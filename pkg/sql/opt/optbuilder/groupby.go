@@ -98,6 +98,21 @@ type groupby struct {
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
 	buildingGroupingCols bool
+
+	// groupingSets contains the grouping sets of a GROUP BY with ROLLUP, CUBE
+	// or GROUPING SETS, as sets of grouping columns in aggOutScope. It is nil
+	// for a GROUP BY without grouping sets. See grouping_sets.go.
+	groupingSets []opt.ColSet
+
+	// groupingSetOutCols contains the columns in aggOutScope which hold the
+	// grouping columns of an aggregation with grouping sets, in the same order
+	// as groupingCols. It is only set if groupingSets is non-nil.
+	groupingSetOutCols opt.ColList
+
+	// groupingSetCol is the column in aggOutScope which identifies the grouping
+	// set of each row produced by the aggregation, as an index into
+	// groupingSets. It is only set if groupingSets is non-nil.
+	groupingSetCol opt.ColumnID
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
@@ -234,7 +249,8 @@ func (a *aggregateInfo) TypeCheck(ctx *tree.SemaContext, desired *types.T) (tree
 // values are fed to it.
 func (a aggregateInfo) isOrderingSensitive() bool {
 	switch a.def.Name {
-	case "array_agg", "concat_agg", "string_agg", "json_agg", "jsonb_agg":
+	case "array_agg", "concat_agg", "string_agg", "json_agg", "jsonb_agg",
+		"percentile_disc", "percentile_cont", "mode":
 		return true
 	default:
 		return false
@@ -311,7 +327,11 @@ func (b *Builder) buildGroupingColumns(sel *tree.SelectClause, projectionsScope,
 	g := fromScope.groupby
 
 	// The "from" columns are visible to any grouping expressions.
-	b.buildGroupingList(sel.GroupBy, sel.Exprs, projectionsScope, fromScope)
+	sets := b.buildGroupingList(sel.GroupBy, sel.Exprs, projectionsScope, fromScope)
+	if sets != nil {
+		b.buildGroupingSetColumns(sets, g)
+		return
+	}
 
 	// Copy the grouping columns to the aggOutScope.
 	g.aggOutScope.appendColumns(g.groupingCols())
@@ -333,6 +353,9 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// If there are any aggregates that are ordering sensitive, build the aggregations
 	// as window functions over each group.
 	if g.hasNonCommutativeAggregates() {
+		if g.groupingSets != nil {
+			panic(g.orderedAggregateWithGroupingSetsError())
+		}
 		return b.buildAggregationAsWindow(groupingColSet, having, fromScope)
 	}
 
@@ -400,12 +423,16 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// aggregate arguments, as well as any additional order by columns.
 	b.constructProjectForScope(fromScope, g.aggInScope)
 
-	g.aggOutScope.expr = b.constructGroupBy(
-		g.aggInScope.expr.(memo.RelExpr),
-		groupingColSet,
-		aggCols,
-		g.aggInScope.ordering,
-	)
+	if g.groupingSets != nil {
+		g.aggOutScope.expr = b.constructGroupingSets(g, aggCols)
+	} else {
+		g.aggOutScope.expr = b.constructGroupBy(
+			g.aggInScope.expr.(memo.RelExpr),
+			groupingColSet,
+			aggCols,
+			g.aggInScope.ordering,
+		)
+	}
 
 	// Wrap with having filter if it exists.
 	if having != nil {
@@ -449,7 +476,9 @@ func (b *Builder) buildHaving(having tree.TypedExpr, fromScope *scope) opt.Scala
 
 // buildGroupingList builds a set of memo groups that represent a list of
 // GROUP BY expressions, adding the group-by expressions as columns to
-// aggInScope and populating groupStrs.
+// aggInScope and populating groupStrs. If the list contains ROLLUP, CUBE or
+// GROUPING SETS, buildGroupingList returns the grouping sets it expands to, as
+// sets of grouping columns in aggInScope. Otherwise, it returns nil.
 //
 // groupBy   The given GROUP BY expressions.
 // selects   The select expressions are needed in case one of the GROUP BY
@@ -459,7 +488,7 @@ func (b *Builder) buildHaving(having tree.TypedExpr, fromScope *scope) opt.Scala
 // fromScope The scope for the input to the aggregation (the FROM clause).
 func (b *Builder) buildGroupingList(
 	groupBy tree.GroupBy, selects tree.SelectExprs, projectionsScope *scope, fromScope *scope,
-) {
+) (groupingSets []opt.ColSet) {
 	g := fromScope.groupby
 	g.groupStrs = make(groupByStrSet, len(groupBy))
 	if g.aggInScope.cols == nil {
@@ -475,15 +504,34 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
+	defer func() { g.buildingGroupingCols = false }()
+
+	if !hasGroupingSets(groupBy) {
+		for _, e := range groupBy {
+			b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		}
+		return nil
+	}
+
+	// The grouping sets of the GROUP BY are the cross product of the grouping
+	// sets of each item.
+	groupingSets = []opt.ColSet{{}}
 	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		itemSets := b.buildGroupingSets(e, selects, projectionsScope, fromScope, g.aggInScope)
+		groupingSets = crossGroupingSets(groupingSets, itemSets)
+	}
+	if len(groupingSets) == 1 {
+		// A single grouping set contains all the grouping columns, so this is an
+		// ordinary GROUP BY.
+		return nil
 	}
-	g.buildingGroupingCols = false
+	return groupingSets
 }
 
 // buildGrouping builds a set of memo groups that represent a GROUP BY
 // expression. The expression (or expressions, if we have a star) is added to
-// groupStrs and to the aggInScope.
+// groupStrs and to the aggInScope. buildGrouping returns the IDs of the
+// grouping columns for the expression.
 //
 //
 // groupBy          The given GROUP BY expression.
@@ -497,7 +545,7 @@ func (b *Builder) buildGroupingList(
 //                  as the aggregate function arguments.
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
-) {
+) (cols opt.ColSet) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
 	alias := ""
@@ -595,7 +643,8 @@ func (b *Builder) buildGrouping(
 		// If a grouping column has already been added, don't add it again.
 		// GROUP BY a, a is semantically equivalent to GROUP BY a.
 		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+		if col, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+			cols.Add(col.id)
 			continue
 		}
 
@@ -605,7 +654,9 @@ func (b *Builder) buildGrouping(
 		col := b.addColumn(aggInScope, alias, e)
 		b.buildScalar(e, fromScope, aggInScope, col, nil)
 		fromScope.groupby.groupStrs[exprStr] = col
+		cols.Add(col.id)
 	}
+	return cols
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -784,6 +835,20 @@ func (b *Builder) constructAggregate(name string, args []opt.ScalarExpr) opt.Sca
 				"aggregate functions with multiple non-constant expressions are not supported"))
 		}
 		return b.factory.ConstructStringAgg(args[0], args[1])
+	case "percentile_disc":
+		if !memo.CanExtractConstDatum(args[1]) {
+			panic(pgerror.New(pgcode.FeatureNotSupported,
+				"percentile_disc with a non-constant fraction is not supported"))
+		}
+		return b.factory.ConstructPercentileDisc(args[0], args[1])
+	case "percentile_cont":
+		if !memo.CanExtractConstDatum(args[1]) {
+			panic(pgerror.New(pgcode.FeatureNotSupported,
+				"percentile_cont with a non-constant fraction is not supported"))
+		}
+		return b.factory.ConstructPercentileCont(args[0], args[1])
+	case "mode":
+		return b.factory.ConstructMode(args[0])
 	}
 	panic(errors.AssertionFailedf("unhandled aggregate: %s", name))
 }
@@ -792,6 +857,30 @@ func isAggregate(def *tree.FunctionDefinition) bool {
 	return def.Class == tree.AggregateClass
 }
 
+// isOrderedSetAggregate returns true if the given function is an ordered-set
+// aggregate, which must be called with a WITHIN GROUP clause.
+func isOrderedSetAggregate(def *tree.FunctionDefinition) bool {
+	switch def.Name {
+	case "percentile_disc", "percentile_cont", "mode":
+		return true
+	default:
+		return false
+	}
+}
+
+// checkOrderedSetAggregate panics if a WITHIN GROUP clause is missing from a
+// call to an ordered-set aggregate, or is used with any other function.
+func checkOrderedSetAggregate(f *tree.FuncExpr, def *tree.FunctionDefinition) {
+	if f.AggType == tree.OrderedSetAgg && !isOrderedSetAggregate(def) {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"%s is not an ordered-set aggregate, so it cannot have WITHIN GROUP", def.Name))
+	}
+	if f.AggType != tree.OrderedSetAgg && isOrderedSetAggregate(def) {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"WITHIN GROUP is required for ordered-set aggregate %s", def.Name))
+	}
+}
+
 func isWindow(def *tree.FunctionDefinition) bool {
 	return def.Class == tree.WindowClass
 }
@@ -812,6 +901,11 @@ func newGroupingError(name *tree.Name) error {
 // table. In that case, we can allow col as an "implicit" grouping column, even
 // if it is not specified in the query.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.groupingSets != nil {
+		// The column would be NULL in the grouping sets which don't include the
+		// PK.
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
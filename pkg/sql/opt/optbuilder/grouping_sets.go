@@ -0,0 +1,418 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+// This file has builder code specific to aggregations with grouping sets
+// (queries with ROLLUP, CUBE or GROUPING SETS in the GROUP BY).
+//
+// All of the grouping sets are computed by a single GroupByOp, so the input
+// is only read once:
+//
+//  - the pre-projection, which renders the grouping columns and the
+//    arguments to aggregation functions, is cross joined with a VALUES
+//    operator which numbers the grouping sets. This produces a copy of each
+//    input row for each grouping set.
+//
+//  - a projection replaces each grouping column with a new column, which is
+//    NULL in the rows for grouping sets that don't include the column.
+//
+//  - the aggregation groups by the new grouping columns and the grouping set
+//    number.
+//
+// For example:
+//   SELECT a, b, sum(c) FROM t GROUP BY ROLLUP (a, b)
+//
+//   pre-projection:  a, b, c
+//   cross join:      a, b, c, set (VALUES (0), (1), (2))
+//   projection:      CASE set WHEN 0 THEN a WHEN 1 THEN a ELSE NULL END (as a'),
+//                    CASE set WHEN 0 THEN b ELSE NULL END (as b'), c, set
+//   aggregation:     group by a', b', set, calculate sum(c)
+//
+// Like an aggregation without GROUP BY, an empty grouping set produces a row
+// even if the input has no rows. To account for this, the aggregation is
+// full outer joined with a VALUES operator which produces the numbers of the
+// empty grouping sets, and the results of COUNT are replaced with zero in
+// the rows which have no match in the aggregation.
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// maxGroupingSets is the maximum number of grouping sets a GROUP BY can
+// expand to.
+const maxGroupingSets = 4096
+
+// maxCubeElements is the maximum number of elements in a CUBE, which expands
+// to a grouping set for each subset of its elements.
+const maxCubeElements = 12
+
+// maxGroupingArgs is the maximum number of arguments to grouping(), whose
+// result has a bit for each argument.
+const maxGroupingArgs = 31
+
+// hasGroupingSets returns true if the given GROUP BY contains ROLLUP, CUBE or
+// GROUPING SETS.
+func hasGroupingSets(groupBy tree.GroupBy) bool {
+	for _, e := range groupBy {
+		if _, ok := e.(*tree.GroupingSet); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// buildGroupingSets builds the grouping columns for an item in a GROUP BY
+// with grouping sets, and returns the grouping sets the item expands to. See
+// buildGrouping for a description of the arguments.
+func (b *Builder) buildGroupingSets(
+	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
+) []opt.ColSet {
+	gs, ok := groupBy.(*tree.GroupingSet)
+	if !ok {
+		// An expression, or a tuple of expressions, is a single grouping set.
+		cols := b.buildGrouping(groupBy, selects, projectionsScope, fromScope, aggInScope)
+		return []opt.ColSet{cols}
+	}
+
+	if gs.Type == tree.GroupingSets {
+		var sets []opt.ColSet
+		for _, e := range gs.Exprs {
+			sets = append(sets, b.buildGroupingSets(e, selects, projectionsScope, fromScope, aggInScope)...)
+			checkGroupingSetsCount(len(sets))
+		}
+		return sets
+	}
+
+	elems := make([]opt.ColSet, len(gs.Exprs))
+	for i, e := range gs.Exprs {
+		elems[i] = b.buildGrouping(e, selects, projectionsScope, fromScope, aggInScope)
+	}
+
+	switch gs.Type {
+	case tree.RollupGroupingSet:
+		// ROLLUP (a, b) expands to (a, b), (a), ().
+		sets := make([]opt.ColSet, len(elems)+1)
+		var set opt.ColSet
+		for i := range elems {
+			set = set.Union(elems[i])
+			sets[len(elems)-1-i] = set
+		}
+		return sets
+
+	case tree.CubeGroupingSet:
+		// CUBE (a, b) expands to (a, b), (a), (b), ().
+		if len(elems) > maxCubeElements {
+			panic(pgerror.Newf(pgcode.ProgramLimitExceeded,
+				"CUBE is limited to %d elements", maxCubeElements))
+		}
+		sets := make([]opt.ColSet, 1<<uint(len(elems)))
+		for i := range sets {
+			// The bits of mask select the elements in the set, with the most
+			// significant bit corresponding to the first element.
+			mask := len(sets) - 1 - i
+			for j := range elems {
+				if mask&(1<<uint(len(elems)-1-j)) != 0 {
+					sets[i].UnionWith(elems[j])
+				}
+			}
+		}
+		return sets
+
+	default:
+		panic(errors.AssertionFailedf("unknown grouping set type %s", gs.Type))
+	}
+}
+
+// crossGroupingSets returns the union of each grouping set in left with each
+// grouping set in right.
+func crossGroupingSets(left, right []opt.ColSet) []opt.ColSet {
+	checkGroupingSetsCount(len(left) * len(right))
+	sets := make([]opt.ColSet, 0, len(left)*len(right))
+	for i := range left {
+		for j := range right {
+			sets = append(sets, left[i].Union(right[j]))
+		}
+	}
+	return sets
+}
+
+func checkGroupingSetsCount(n int) {
+	if n > maxGroupingSets {
+		panic(pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"too many grouping sets present (maximum %d)", maxGroupingSets))
+	}
+}
+
+// buildGroupingSetColumns adds the grouping columns of an aggregation with
+// the given grouping sets to the aggOutScope. Since a grouping column is NULL
+// in the rows for grouping sets which don't include it, each grouping column
+// gets a new column in the aggOutScope, and groupStrs is updated to refer to
+// the new columns. The column which numbers the grouping sets is added to the
+// aggOutScope as well.
+func (b *Builder) buildGroupingSetColumns(sets []opt.ColSet, g *groupby) {
+	groupingCols := g.groupingCols()
+	inCols := colsToColList(groupingCols)
+	outCols := make(opt.ColList, len(groupingCols))
+	for i := range groupingCols {
+		col := &groupingCols[i]
+		outCols[i] = b.synthesizeColumn(
+			g.aggOutScope, string(col.name), col.typ, col.expr, nil, /* scalar */
+		).id
+	}
+	g.groupingSetOutCols = outCols
+	g.groupingSetCol = b.synthesizeColumn(
+		g.aggOutScope, "grouping_set", types.Int, nil /* expr */, nil, /* scalar */
+	).id
+
+	for exprStr, col := range g.groupStrs {
+		idx, _ := inCols.Find(col.id)
+		g.groupStrs[exprStr] = g.aggOutScope.getColumn(outCols[idx])
+	}
+
+	g.groupingSets = make([]opt.ColSet, len(sets))
+	for i := range sets {
+		g.groupingSets[i] = opt.TranslateColSet(sets[i], inCols, outCols)
+	}
+}
+
+// orderedAggregateWithGroupingSetsError returns the error for an aggregation
+// with grouping sets that contains an ordering-sensitive aggregate such as
+// array_agg(x ORDER BY y). Such aggregates are built as window functions over
+// each group, which can't be combined with the grouping sets.
+func (g *groupby) orderedAggregateWithGroupingSetsError() error {
+	for i := range g.aggs {
+		if !g.aggs[i].isCommutative() {
+			return unimplemented.Newf("grouping sets with ordered aggregate",
+				"%s with ORDER BY is not supported with ROLLUP, CUBE or GROUPING SETS",
+				g.aggs[i].def.Name)
+		}
+	}
+	return errors.AssertionFailedf("no ordered aggregate")
+}
+
+// constructGroupingSets constructs the operators which compute the aggregates
+// in aggCols for each of the grouping sets, as described at the top of this
+// file. The pre-projection must already have been constructed in the
+// aggInScope.
+func (b *Builder) constructGroupingSets(g *groupby, aggCols []scopeColumn) memo.RelExpr {
+	md := b.factory.Metadata()
+	inCols := colsToColList(g.groupingCols())
+	outCols := g.groupingSetOutCols
+
+	var emptySets []int
+	for i := range g.groupingSets {
+		if g.groupingSets[i].Empty() {
+			emptySets = append(emptySets, i)
+		}
+	}
+
+	// If there are empty grouping sets, the grouping set number produced by
+	// the aggregation is merged with the one from the outer join below.
+	setCol := g.groupingSetCol
+	if emptySets != nil {
+		setCol = md.AddColumn("grouping_set", types.Int)
+	}
+
+	// Make a copy of each input row for each grouping set.
+	sets := make([]int, len(g.groupingSets))
+	for i := range sets {
+		sets[i] = i
+	}
+	input := b.factory.ConstructInnerJoin(
+		g.aggInScope.expr.(memo.RelExpr),
+		b.constructGroupingSetValues(setCol, sets),
+		memo.TrueFilter,
+		memo.EmptyJoinPrivate,
+	)
+
+	// Replace each grouping column with a column that is NULL in the rows for
+	// grouping sets which don't include it.
+	setVar := b.factory.ConstructVariable(setCol)
+	projections := make(memo.ProjectionsExpr, len(inCols))
+	for i := range inCols {
+		inVar := b.factory.ConstructVariable(inCols[i])
+		whens := make(memo.ScalarListExpr, 0, len(g.groupingSets))
+		for j := range g.groupingSets {
+			if g.groupingSets[j].Contains(outCols[i]) {
+				whens = append(whens, b.factory.ConstructWhen(b.constructGroupingSetNumber(j), inVar))
+			}
+		}
+		elem := inVar
+		if len(whens) < len(g.groupingSets) {
+			orElse := b.factory.ConstructNull(md.ColumnMeta(inCols[i]).Type)
+			elem = b.factory.ConstructCase(setVar, whens, orElse)
+		}
+		projections[i] = memo.ProjectionsItem{
+			Element:    elem,
+			ColPrivate: memo.ColPrivate{Col: outCols[i]},
+		}
+	}
+	input = b.factory.ConstructProject(input, projections, input.Relational().OutputCols)
+
+	groupingColSet := outCols.ToSet()
+	groupingColSet.Add(setCol)
+	if emptySets == nil {
+		return b.constructGroupBy(input, groupingColSet, aggCols, g.aggInScope.ordering)
+	}
+
+	// COUNT produces zero rather than NULL for an empty grouping set over an
+	// empty input, so the aggregation produces it in a new column which is
+	// coalesced with zero below.
+	passthrough := outCols.ToSet()
+	projections = make(memo.ProjectionsExpr, 0, len(aggCols)+1)
+	aggCols = append([]scopeColumn(nil), aggCols...)
+	for i := range aggCols {
+		col := &aggCols[i]
+		if opt.AggregateIsNullOnEmpty(col.scalar.Op()) {
+			passthrough.Add(col.id)
+			continue
+		}
+		id := md.AddColumn(string(col.name), col.typ)
+		projections = append(projections, memo.ProjectionsItem{
+			Element: b.factory.ConstructCoalesce(memo.ScalarListExpr{
+				b.factory.ConstructVariable(id),
+				b.factory.ConstructConstVal(tree.DZero, types.Int),
+			}),
+			ColPrivate: memo.ColPrivate{Col: col.id},
+		})
+		col.id = id
+	}
+	groupBy := b.constructGroupBy(input, groupingColSet, aggCols, g.aggInScope.ordering)
+
+	// Produce a row for each empty grouping set which has no rows in the
+	// aggregation.
+	emptySetCol := md.AddColumn("grouping_set", types.Int)
+	on := memo.FiltersExpr{{Condition: b.factory.ConstructEq(
+		b.factory.ConstructVariable(setCol), b.factory.ConstructVariable(emptySetCol),
+	)}}
+	join := b.factory.ConstructFullJoin(
+		groupBy, b.constructGroupingSetValues(emptySetCol, emptySets), on, memo.EmptyJoinPrivate,
+	)
+	projections = append(projections, memo.ProjectionsItem{
+		Element: b.factory.ConstructCoalesce(memo.ScalarListExpr{
+			b.factory.ConstructVariable(setCol),
+			b.factory.ConstructVariable(emptySetCol),
+		}),
+		ColPrivate: memo.ColPrivate{Col: g.groupingSetCol},
+	})
+	return b.factory.ConstructProject(join, projections, passthrough)
+}
+
+// constructGroupingSetValues constructs a VALUES operator which produces the
+// given grouping set numbers in the given column.
+func (b *Builder) constructGroupingSetValues(col opt.ColumnID, sets []int) memo.RelExpr {
+	typ := types.MakeTuple([]types.T{*types.Int})
+	rows := make(memo.ScalarListExpr, len(sets))
+	for i := range sets {
+		rows[i] = b.factory.ConstructTuple(
+			memo.ScalarListExpr{b.constructGroupingSetNumber(sets[i])}, typ,
+		)
+	}
+	return b.factory.ConstructValues(rows, &memo.ValuesPrivate{
+		Cols: opt.ColList{col},
+		ID:   b.factory.Metadata().NextValuesID(),
+	})
+}
+
+func (b *Builder) constructGroupingSetNumber(set int) opt.ScalarExpr {
+	return b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(set)), types.Int)
+}
+
+// groupingInfo replaces a call to grouping() during semantic analysis. The
+// call is built by buildGroupingFunction once the grouping columns are known.
+type groupingInfo struct {
+	*tree.FuncExpr
+}
+
+// Walk is part of the tree.Expr interface.
+func (gi *groupingInfo) Walk(v tree.Visitor) tree.Expr {
+	return gi
+}
+
+// TypeCheck is part of the tree.Expr interface.
+func (gi *groupingInfo) TypeCheck(
+	ctx *tree.SemaContext, desired *types.T,
+) (tree.TypedExpr, error) {
+	if _, err := gi.FuncExpr.TypeCheck(ctx, desired); err != nil {
+		return nil, err
+	}
+	return gi, nil
+}
+
+// Eval is part of the tree.TypedExpr interface.
+func (gi *groupingInfo) Eval(_ *tree.EvalContext) (tree.Datum, error) {
+	panic(errors.AssertionFailedf("groupingInfo must be replaced before evaluation"))
+}
+
+var _ tree.Expr = &groupingInfo{}
+var _ tree.TypedExpr = &groupingInfo{}
+
+// buildGroupingFunction builds a call to grouping(). Its arguments must be
+// GROUP BY expressions, and its result is a bit mask with a bit for each
+// argument, which is set if the argument is not in the grouping set of the
+// current row. The last argument corresponds to the least significant bit.
+func (b *Builder) buildGroupingFunction(
+	gi *groupingInfo, inScope, outScope *scope, outCol *scopeColumn,
+) opt.ScalarExpr {
+	g := inScope.groupby
+	switch {
+	case inScope.inAgg:
+		panic(pgerror.New(pgcode.Grouping, "aggregate function calls cannot contain grouping()"))
+	case g == nil:
+		panic(pgerror.New(pgcode.Grouping, "grouping() can only be used in a query with GROUP BY"))
+	case g.buildingGroupingCols:
+		panic(pgerror.New(pgcode.Grouping, "grouping() is not allowed in GROUP BY"))
+	case len(gi.Exprs) > maxGroupingArgs:
+		panic(pgerror.Newf(pgcode.TooManyArguments,
+			"grouping() must have fewer than %d arguments", maxGroupingArgs+1))
+	}
+
+	cols := make(opt.ColList, len(gi.Exprs))
+	for i, e := range gi.Exprs {
+		col, ok := g.groupStrs[symbolicExprStr(e.(tree.TypedExpr))]
+		if !ok {
+			panic(pgerror.New(pgcode.Grouping, "arguments to grouping() must be GROUP BY expressions"))
+		}
+		cols[i] = col.id
+	}
+
+	var out opt.ScalarExpr
+	if g.groupingSets == nil {
+		// All the grouping columns are in the only grouping set.
+		out = b.factory.ConstructConstVal(tree.DZero, types.Int)
+	} else {
+		whens := make(memo.ScalarListExpr, len(g.groupingSets))
+		for i := range g.groupingSets {
+			var mask tree.DInt
+			for _, col := range cols {
+				mask <<= 1
+				if !g.groupingSets[i].Contains(col) {
+					mask |= 1
+				}
+			}
+			whens[i] = b.factory.ConstructWhen(
+				b.constructGroupingSetNumber(i),
+				b.factory.ConstructConstVal(tree.NewDInt(mask), types.Int),
+			)
+		}
+		out = b.factory.ConstructCase(
+			b.factory.ConstructVariable(g.groupingSetCol), whens, b.factory.ConstructNull(types.Int),
+		)
+	}
+	return b.finishBuildScalar(gi, out, inScope, outScope, outCol)
+}
@@ -108,6 +108,9 @@ func (b *Builder) buildScalar(
 	case *windowInfo:
 		return b.finishBuildScalarRef(t.col, inScope, outScope, outCol, colRefs)
 
+	case *groupingInfo:
+		return b.buildGroupingFunction(t, inScope, outScope, outCol)
+
 	case *tree.AndExpr:
 		left := b.buildScalar(t.TypedLeft(), inScope, nil, nil, colRefs)
 		right := b.buildScalar(t.TypedRight(), inScope, nil, nil, colRefs)
@@ -640,6 +643,8 @@ func (b *Builder) constructComparison(
 		return b.factory.ConstructJsonSomeExists(left, right)
 	case tree.Overlaps:
 		return b.factory.ConstructOverlaps(left, right)
+	case tree.TSMatches:
+		return b.factory.ConstructTSMatches(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled comparison operator: %s", log.Safe(cmp)))
 }
@@ -811,6 +811,11 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 			break
 		}
 
+		if def.Name == "grouping" {
+			expr = s.replaceGrouping(t)
+			break
+		}
+
 	case *tree.ArrayFlatten:
 		if s.builder.AllowUnsupportedExpr {
 			// TODO(rytaft): Temporary fix for #24171 and #24170.
@@ -925,6 +930,7 @@ func (s *scope) replaceSRF(f *tree.FuncExpr, def *tree.FunctionDefinition) *srf
 // used later by the Builder to build aggregations in the aggregation scope.
 func (s *scope) replaceAggregate(f *tree.FuncExpr, def *tree.FunctionDefinition) tree.Expr {
 	f, def = s.replaceCount(f, def)
+	f = s.replaceOrderedSetAggregate(f, def)
 
 	// We need to save and restore the previous value of the field in
 	// semaCtx in case we are recursively called within a subquery
@@ -970,6 +976,44 @@ func (s *scope) replaceAggregate(f *tree.FuncExpr, def *tree.FunctionDefinition)
 	return s.builder.buildAggregateFunction(f, &private, s)
 }
 
+// replaceOrderedSetAggregate rewrites a call to an ordered-set aggregate so
+// that the expression in its WITHIN GROUP clause becomes the first argument,
+// followed by the direct arguments. For example:
+//
+//   percentile_disc(0.5) WITHIN GROUP (ORDER BY x)
+//
+// is built like percentile_disc(x, 0.5 ORDER BY x). The ordering is kept, so
+// the aggregate receives its input values in sorted order.
+func (s *scope) replaceOrderedSetAggregate(
+	f *tree.FuncExpr, def *tree.FunctionDefinition,
+) *tree.FuncExpr {
+	checkOrderedSetAggregate(f, def)
+	if f.AggType != tree.OrderedSetAgg {
+		return f
+	}
+
+	cpy := *f
+	cpy.Exprs = make(tree.Exprs, 0, len(f.OrderBy)+len(f.Exprs))
+	for _, o := range f.OrderBy {
+		cpy.Exprs = append(cpy.Exprs, o.Expr)
+	}
+	cpy.Exprs = append(cpy.Exprs, f.Exprs...)
+	return &cpy
+}
+
+// replaceGrouping returns a groupingInfo struct that replaces a call to
+// grouping(). The call can't be built until the grouping columns are known,
+// since its result depends on the grouping set of each row produced by the
+// aggregation. See Builder.buildGroupingFunction.
+func (s *scope) replaceGrouping(f *tree.FuncExpr) tree.Expr {
+	expr := f.Walk(s)
+	typedFunc, err := tree.TypeCheck(expr, s.builder.semaCtx, types.Any)
+	if err != nil {
+		panic(err)
+	}
+	return &groupingInfo{FuncExpr: typedFunc.(*tree.FuncExpr)}
+}
+
 func (s *scope) lookupWindowDef(name tree.Name) *tree.WindowDef {
 	for i := range s.windowDefs {
 		if s.windowDefs[i].Name == name {
@@ -1006,6 +1050,12 @@ func (s *scope) replaceWindowFn(f *tree.FuncExpr, def *tree.FunctionDefinition)
 		panic(err)
 	}
 
+	checkOrderedSetAggregate(f, def)
+	if f.AggType == tree.OrderedSetAgg {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"OVER is not supported for ordered-set aggregate %s", def.Name))
+	}
+
 	// We need to save and restore the previous value of the field in
 	// semaCtx in case we are recursively called within a subquery
 	// context.
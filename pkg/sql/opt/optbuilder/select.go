@@ -11,6 +11,8 @@
 package optbuilder
 
 import (
+	"fmt"
+
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
@@ -102,6 +104,9 @@ func (b *Builder) buildDataSource(
 		switch t := ds.(type) {
 		case cat.Table:
 			tabMeta := b.addTable(t, &resName)
+			if indexFlags == nil && b.IndexHints != nil {
+				indexFlags = b.indexHint(t, tn.TableName)
+			}
 			return b.buildScan(tabMeta, nil /* ordinals */, indexFlags, excludeMutations, inScope)
 
 		case cat.Sequence:
@@ -331,6 +336,34 @@ func (b *Builder) renameSource(as tree.AliasClause, scope *scope) {
 	}
 }
 
+// indexHint returns the index flags in IndexHints for the given table, or nil
+// if there are none. Unlike inline index hints, a hint that can't be applied
+// (because the table is virtual or the index doesn't exist) doesn't cause an
+// error; it is recorded in IgnoredHints instead, since the hints are stored
+// separately from the statement and can become stale.
+func (b *Builder) indexHint(tab cat.Table, name tree.Name) *tree.IndexFlags {
+	flags := b.IndexHints[name]
+	if flags == nil {
+		return nil
+	}
+	if tab.IsVirtualTable() {
+		b.IgnoredHints = append(b.IgnoredHints,
+			fmt.Sprintf("hints on %s: virtual tables do not support index hints", tree.ErrString(&name)))
+		return nil
+	}
+	if flags.Index == "" {
+		return flags
+	}
+	for i, n := 0, tab.IndexCount(); i < n; i++ {
+		if tab.Index(i).Name() == tree.Name(flags.Index) {
+			return flags
+		}
+	}
+	b.IgnoredHints = append(b.IgnoredHints,
+		fmt.Sprintf("INDEX(%s@%s): index not found", tree.ErrString(&name), tree.ErrString(&flags.Index)))
+	return nil
+}
+
 // buildScanFromTableRef adds support for numeric references in queries.
 // For example:
 // SELECT * FROM [53 as t]; (table reference)
@@ -41,6 +41,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -172,6 +173,10 @@ type Flags struct {
 	// File specifies the name of the file to import. This field is only used by
 	// the import command.
 	File string
+
+	// Generic enables the rules that optimize the generic plans of prepared
+	// statements, in which placeholders are not replaced by their values.
+	Generic bool
 }
 
 // New constructs a new instance of the OptTester for the given SQL statement.
@@ -320,6 +325,9 @@ func New(catalog cat.Catalog, sql string) *OptTester {
 //  - file: used to set the name of the file to be imported. This is used by
 //    the import command.
 //
+//  - generic: enables the rules that optimize the generic plans of prepared
+//    statements, as with plan_cache_mode=force_generic_plan.
+//
 func (ot *OptTester) RunCommand(tb testing.TB, d *datadriven.TestData) string {
 	// Allow testcases to override the flags.
 	for _, a := range d.CmdArgs {
@@ -339,6 +347,9 @@ func (ot *OptTester) RunCommand(tb testing.TB, d *datadriven.TestData) string {
 	ot.evalCtx.TestingKnobs.OptimizerCostPerturbation = ot.Flags.PerturbCost
 	ot.evalCtx.Locality = ot.Flags.Locality
 	ot.evalCtx.SessionData.SaveTablesPrefix = ot.Flags.SaveTablesPrefix
+	if ot.Flags.Generic {
+		ot.evalCtx.SessionData.PlanCacheMode = sessiondata.PlanCacheModeForceGeneric
+	}
 
 	switch d.Cmd {
 	case "exec-ddl":
@@ -670,6 +681,9 @@ func (f *Flags) Set(arg datadriven.CmdArg) error {
 		}
 		f.File = arg.Vals[0]
 
+	case "generic":
+		f.Generic = true
+
 	default:
 		return fmt.Errorf("unknown argument: %s", arg.Key)
 	}
@@ -119,6 +119,14 @@ const (
 	// up with better way to incorporate latency into the coster.
 	latencyCostFactor = cpuCostFactor
 
+	// remoteLookupCostFactor represents the latency of the round trip needed
+	// to perform a single lookup (e.g. in a lookup join) into an index that may
+	// be located in a different locality. Unlike latencyCostFactor, which is
+	// applied to every row that is scanned, this cost is incurred once per
+	// lookup, and it can dominate the cost of lookup joins in clusters that
+	// span multiple regions.
+	remoteLookupCostFactor = seqIOCostFactor
+
 	// hugeCost is used with expressions we want to avoid; these are expressions
 	// that "violate" a hint like forcing a specific index or join algorithm.
 	// If the final expression has this cost or larger, it means that there was no
@@ -126,6 +134,16 @@ const (
 	hugeCost memo.Cost = 1e100
 )
 
+// CustomPlanOverhead returns the estimated cost of optimizing a custom plan
+// for the statement in the given memo, which is avoided by reusing the generic
+// plan of a prepared statement. Like Postgres, it is estimated as 1000 times
+// the CPU cost of an operator for each table referenced by the statement, plus
+// one.
+func CustomPlanOverhead(mem *memo.Memo) memo.Cost {
+	numTables := len(mem.Metadata().AllTables())
+	return 1000 * cpuCostFactor * memo.Cost(numTables+1)
+}
+
 // Init initializes a new coster structure with the given memo.
 func (c *coster) Init(evalCtx *tree.EvalContext, mem *memo.Memo, perturbation float64) {
 	c.mem = mem
@@ -390,6 +408,7 @@ func (c *coster) computeIndexJoinCost(join *memo.IndexJoinExpr) memo.Cost {
 	// Since the matching rows in the table may not all be in the same range, this
 	// counts as random I/O.
 	perRowCost := cpuCostFactor + randIOCostFactor +
+		c.remoteLookupCost(join.Table, cat.PrimaryIndex) +
 		c.rowScanCost(join.Table, cat.PrimaryIndex, join.Cols.Len())
 	return memo.Cost(leftRowCount) * perRowCost
 }
@@ -400,7 +419,9 @@ func (c *coster) computeLookupJoinCost(join *memo.LookupJoinExpr) memo.Cost {
 	// The rows in the (left) input are used to probe into the (right) table.
 	// Since the matching rows in the table may not all be in the same range, this
 	// counts as random I/O.
-	perLookupCost := memo.Cost(randIOCostFactor)
+	// Each lookup also pays the latency of a round trip to the leaseholder of
+	// the range, which is higher if the index is not located near the gateway.
+	perLookupCost := memo.Cost(randIOCostFactor) + c.remoteLookupCost(join.Table, join.Index)
 	cost := memo.Cost(leftRowCount) * perLookupCost
 
 	// Each lookup might retrieve many rows; add the IO cost of retrieving the
@@ -585,15 +606,7 @@ func (c *coster) rowScanCost(tabID opt.TableID, idxOrd int, numScannedCols int)
 
 	// Adjust cost based on how well the current locality matches the index's
 	// zone constraints.
-	var costFactor memo.Cost = cpuCostFactor
-	if len(c.locality.Tiers) != 0 {
-		// If 0% of locality tiers have matching constraints, then add additional
-		// cost. If 100% of locality tiers have matching constraints, then add no
-		// additional cost. Anything in between is proportional to the number of
-		// matches.
-		adjustment := 1.0 - localityMatchScore(idx.Zone(), c.locality)
-		costFactor += latencyCostFactor * memo.Cost(adjustment)
-	}
+	costFactor := cpuCostFactor + latencyCostFactor*c.localityAdjustment(idx)
 
 	// The number of the columns in the index matter because more columns means
 	// more data to scan. The number of columns we actually return also matters
@@ -602,6 +615,35 @@ func (c *coster) rowScanCost(tabID opt.TableID, idxOrd int, numScannedCols int)
 	return memo.Cost(numCols+numScannedCols) * costFactor
 }
 
+// remoteLookupCost is the additional cost of performing a single lookup into
+// the given index, which reflects the latency of the round trip to the index's
+// location. If the index has no replica constraints or lease preferences, its
+// location is unknown (its ranges can be anywhere in the cluster), so there is
+// no reason to expect it to be remote and no cost is added. Otherwise, the
+// cost uses the same locality adjustment as scans of the index, so that both
+// agree on where the index is located.
+func (c *coster) remoteLookupCost(tabID opt.TableID, idxOrd int) memo.Cost {
+	idx := c.mem.Metadata().Table(tabID).Index(idxOrd)
+	zone := idx.Zone()
+	if zone.ReplicaConstraintsCount() == 0 && zone.LeasePreferenceCount() == 0 {
+		return 0
+	}
+	return remoteLookupCostFactor * c.localityAdjustment(idx)
+}
+
+// localityAdjustment returns a number from 0.0 to 1.0 that describes how far
+// the given index is likely to be from the current node. If 0% of locality
+// tiers have matching constraints, then the adjustment is 1.0. If 100% of
+// locality tiers have matching constraints, then the adjustment is 0.0.
+// Anything in between is proportional to the number of matches. If the
+// locality of the current node is not known, the adjustment is always 0.0.
+func (c *coster) localityAdjustment(idx cat.Index) memo.Cost {
+	if len(c.locality.Tiers) == 0 {
+		return 0
+	}
+	return memo.Cost(1.0 - localityMatchScore(idx.Zone(), c.locality))
+}
+
 // localityMatchScore returns a number from 0.0 to 1.0 that describes how well
 // the current node's locality matches the given zone constraints and
 // leaseholder preferences, with 0.0 indicating 0% and 1.0 indicating 100%. This
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/errors"
@@ -708,6 +709,14 @@ func (c *CustomFuncs) GenerateInvertedIndexScans(
 		// If remaining filter exists, split it into one part that can be pushed
 		// below the IndexJoin, and one part that needs to stay above.
 		remaining = sb.addSelectAfterSplit(remaining, newScanPrivate.Cols)
+
+		// A row can be returned more than once by the scan of a trigram index
+		// if it contains several of the scanned trigrams, or by the scan of a
+		// spatial index if several of its cells are scanned, so the duplicates
+		// must be removed before the index join.
+		if newScanPrivate.MayReturnDuplicates(c.e.mem.Metadata()) {
+			sb.addDistinctOn()
+		}
 		sb.addIndexJoin(scanPrivate.Cols)
 		sb.addSelect(remaining)
 
@@ -715,6 +724,92 @@ func (c *CustomFuncs) GenerateInvertedIndexScans(
 	}
 }
 
+// GenericRulesEnabled returns true if the rules that optimize the generic plans
+// of prepared statements are enabled, which is the case unless the
+// plan_cache_mode session variable forces custom plans.
+func (c *CustomFuncs) GenericRulesEnabled() bool {
+	return c.e.evalCtx.SessionData.PlanCacheMode != sessiondata.PlanCacheModeForceCustom
+}
+
+// HasPlaceholderEqualities returns true if any of the filters is an equality
+// between a column and a placeholder (see GenerateParameterizedJoin).
+func (c *CustomFuncs) HasPlaceholderEqualities(filters memo.FiltersExpr) bool {
+	for i := range filters {
+		if _, _, ok := placeholderEquality(filters[i].Condition); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateParameterizedJoin generates an inner join between a single-row Values
+// expression that produces the values of the placeholders compared to columns
+// by the given filters, and the given Scan. The equalities with placeholders
+// are replaced with equalities with the Values columns, which can be used as
+// the lookup columns of a lookup join. The join is wrapped in a Project that
+// removes the Values columns, and is added to the same group as the original
+// Select operator.
+func (c *CustomFuncs) GenerateParameterizedJoin(
+	grp memo.RelExpr, scan memo.RelExpr, filters memo.FiltersExpr,
+) {
+	md := c.e.mem.Metadata()
+	var cols opt.ColList
+	var elems memo.ScalarListExpr
+	var typs []*types.T
+	on := make(memo.FiltersExpr, len(filters))
+	for i := range filters {
+		on[i] = filters[i]
+		col, placeholder, ok := placeholderEquality(filters[i].Condition)
+		if !ok {
+			continue
+		}
+		typ := placeholder.DataType()
+		valuesCol := md.AddColumn(placeholder.Value.String(), typ)
+		cols = append(cols, valuesCol)
+		elems = append(elems, placeholder)
+		typs = append(typs, typ)
+		on[i] = memo.FiltersItem{
+			Condition: c.e.f.ConstructEq(c.e.f.ConstructVariable(col), c.e.f.ConstructVariable(valuesCol)),
+		}
+	}
+
+	values := c.e.f.ConstructValues(
+		memo.ScalarListExpr{c.e.f.ConstructTuple(elems, types.MakeTuple(typs))},
+		&memo.ValuesPrivate{Cols: cols, ID: md.NextValuesID()},
+	)
+	join := c.e.f.ConstructInnerJoin(values, scan, on, memo.EmptyJoinPrivate)
+	project := memo.ProjectExpr{
+		Input:       join,
+		Projections: memo.EmptyProjectionsExpr,
+		Passthrough: scan.Relational().OutputCols,
+	}
+	c.e.mem.AddProjectToGroup(&project, grp)
+}
+
+// placeholderEquality returns the column and the placeholder of a condition
+// that is an equality between them, and false if the condition is not such an
+// equality. The types of the column and the placeholder must be equivalent, as
+// required by the equality columns of joins.
+func placeholderEquality(
+	condition opt.ScalarExpr,
+) (col opt.ColumnID, placeholder *memo.PlaceholderExpr, ok bool) {
+	eq, ok := condition.(*memo.EqExpr)
+	if !ok {
+		return 0, nil, false
+	}
+	v, ok := eq.Left.(*memo.VariableExpr)
+	if !ok {
+		v, ok = eq.Right.(*memo.VariableExpr)
+		placeholder, _ = eq.Left.(*memo.PlaceholderExpr)
+	} else {
+		placeholder, _ = eq.Right.(*memo.PlaceholderExpr)
+	}
+	if !ok || placeholder == nil || !v.DataType().Equivalent(placeholder.DataType()) {
+		return 0, nil, false
+	}
+	return v.Col, placeholder, true
+}
+
 func (c *CustomFuncs) initIdxConstraintForIndex(
 	filters memo.FiltersExpr, tabID opt.TableID, indexOrd int, isInverted bool,
 ) (ic *idxconstraint.Instance) {
@@ -0,0 +1,139 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package xform
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+)
+
+// IsPlaceholderIndependent returns true if the lowest cost plan of the given
+// fully optimized memo, which is the generic plan of a prepared statement, is
+// the same plan regardless of the values of its placeholders. This is the case
+// if the values of the placeholders are only:
+//
+//  - produced by Values and Project operators, for example as the rows
+//    inserted by an INSERT statement;
+//  - used as the lookup keys of lookup joins into a unique index (see
+//    GenerateParameterizedJoin), which return at most one row per lookup.
+//
+// Any other use of the placeholders, such as in filters, in the ON conditions
+// of joins, in the lookup keys of non-unique indexes, or in a LIMIT, makes the
+// plan placeholder-dependent: a custom plan could use the values to constrain
+// an index scan or to estimate the number of rows more precisely, and could
+// therefore be a different plan.
+func IsPlaceholderIndependent(mem *memo.Memo) bool {
+	c := placeholderChecker{md: mem.Metadata()}
+	return c.check(mem.RootExpr())
+}
+
+// placeholderChecker walks a plan to check whether it depends on the values of
+// its placeholders (see IsPlaceholderIndependent).
+type placeholderChecker struct {
+	md *opt.Metadata
+
+	// derivedCols are the columns whose values are derived from the values of
+	// the placeholders.
+	derivedCols opt.ColSet
+}
+
+// check returns true if the given expression does not depend on the values of
+// the placeholders. The columns it produces that are derived from the values
+// of the placeholders are added to derivedCols.
+func (c *placeholderChecker) check(e opt.Expr) bool {
+	switch t := e.(type) {
+	case *memo.PlaceholderExpr:
+		return false
+
+	case *memo.VariableExpr:
+		return !c.derivedCols.Contains(t.Col)
+
+	case *memo.ValuesExpr:
+		for _, row := range t.Rows {
+			tuple := row.(*memo.TupleExpr)
+			for i, elem := range tuple.Elems {
+				derived, ok := c.derived(elem)
+				if !ok {
+					return false
+				}
+				if derived {
+					c.derivedCols.Add(t.Cols[i])
+				}
+			}
+		}
+		return true
+
+	case *memo.ProjectExpr:
+		if !c.check(t.Input) {
+			return false
+		}
+		for i := range t.Projections {
+			item := &t.Projections[i]
+			derived, ok := c.derived(item.Element)
+			if !ok {
+				return false
+			}
+			if derived {
+				c.derivedCols.Add(item.Col)
+			}
+		}
+		return true
+
+	case *memo.LookupJoinExpr:
+		if !c.check(t.Input) {
+			return false
+		}
+		for _, col := range t.KeyCols {
+			if c.derivedCols.Contains(col) {
+				// The number of rows returned by the lookups into a non-unique
+				// index depends on the values of the keys.
+				idx := c.md.Table(t.Table).Index(t.Index)
+				if len(t.KeyCols) < idx.LaxKeyColumnCount() {
+					return false
+				}
+				break
+			}
+		}
+		return c.check(&t.On)
+	}
+
+	for i, n := 0, e.ChildCount(); i < n; i++ {
+		if !c.check(e.Child(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// derived returns true if the value of the given scalar expression is derived
+// from the values of the placeholders. It returns ok=false if the expression
+// contains a subquery that depends on the values of the placeholders.
+func (c *placeholderChecker) derived(e opt.Expr) (derived, ok bool) {
+	switch t := e.(type) {
+	case *memo.PlaceholderExpr:
+		return true, true
+
+	case *memo.VariableExpr:
+		return c.derivedCols.Contains(t.Col), true
+
+	case memo.RelExpr:
+		return false, c.check(t)
+	}
+
+	for i, n := 0, e.ChildCount(); i < n; i++ {
+		childDerived, ok := c.derived(e.Child(i))
+		if !ok {
+			return false, false
+		}
+		derived = derived || childDerived
+	}
+	return derived, true
+}
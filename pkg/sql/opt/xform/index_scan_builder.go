@@ -45,6 +45,7 @@ type indexScanBuilder struct {
 	scanPrivate      memo.ScanPrivate
 	innerFilters     memo.FiltersExpr
 	outerFilters     memo.FiltersExpr
+	distinct         bool
 	indexJoinPrivate memo.IndexJoinPrivate
 }
 
@@ -74,6 +75,7 @@ func (b *indexScanBuilder) setScan(scanPrivate *memo.ScanPrivate) {
 	b.scanPrivate = *scanPrivate
 	b.innerFilters = nil
 	b.outerFilters = nil
+	b.distinct = false
 	b.indexJoinPrivate = memo.IndexJoinPrivate{}
 }
 
@@ -126,6 +128,16 @@ func (b *indexScanBuilder) addSelectAfterSplit(
 	return b.c.ExtractUnboundConditions(filters, cols)
 }
 
+// addDistinctOn wraps the input expression with a DistinctOn expression that
+// removes the rows with duplicate primary keys. It is needed when the scan can
+// return the same row more than once (see ScanPrivate.MayReturnDuplicates).
+func (b *indexScanBuilder) addDistinctOn() {
+	if b.indexJoinPrivate.Table != 0 {
+		panic(errors.AssertionFailedf("cannot add distinct after an index join has been added"))
+	}
+	b.distinct = true
+}
+
 // addIndexJoin wraps the input expression with an IndexJoin expression that
 // produces the given set of columns by lookup in the primary index.
 func (b *indexScanBuilder) addIndexJoin(cols opt.ColSet) {
@@ -145,7 +157,7 @@ func (b *indexScanBuilder) addIndexJoin(cols opt.ColSet) {
 // expressions that were specified by previous calls to various add methods.
 func (b *indexScanBuilder) build(grp memo.RelExpr) {
 	// 1. Only scan.
-	if len(b.innerFilters) == 0 && b.indexJoinPrivate.Table == 0 {
+	if len(b.innerFilters) == 0 && !b.distinct && b.indexJoinPrivate.Table == 0 {
 		b.mem.AddScanToGroup(&memo.ScanExpr{ScanPrivate: b.scanPrivate}, grp)
 		return
 	}
@@ -153,7 +165,7 @@ func (b *indexScanBuilder) build(grp memo.RelExpr) {
 	// 2. Wrap scan in inner filter if it was added.
 	input := b.f.ConstructScan(&b.scanPrivate)
 	if len(b.innerFilters) != 0 {
-		if b.indexJoinPrivate.Table == 0 {
+		if !b.distinct && b.indexJoinPrivate.Table == 0 {
 			b.mem.AddSelectToGroup(&memo.SelectExpr{Input: input, Filters: b.innerFilters}, grp)
 			return
 		}
@@ -161,7 +173,23 @@ func (b *indexScanBuilder) build(grp memo.RelExpr) {
 		input = b.f.ConstructSelect(input, b.innerFilters)
 	}
 
-	// 3. Wrap input in index join if it was added.
+	// 3. Remove the duplicate rows if a distinct was added.
+	if b.distinct {
+		private := memo.GroupingPrivate{GroupingCols: b.primaryKeyCols()}
+		if b.indexJoinPrivate.Table == 0 {
+			distinctOn := &memo.DistinctOnExpr{
+				Input:           input,
+				Aggregations:    memo.EmptyAggregationsExpr,
+				GroupingPrivate: private,
+			}
+			b.mem.AddDistinctOnToGroup(distinctOn, grp)
+			return
+		}
+
+		input = b.f.ConstructDistinctOn(input, memo.EmptyAggregationsExpr, &private)
+	}
+
+	// 4. Wrap input in index join if it was added.
 	if b.indexJoinPrivate.Table != 0 {
 		if len(b.outerFilters) == 0 {
 			indexJoin := &memo.IndexJoinExpr{Input: input, IndexJoinPrivate: b.indexJoinPrivate}
@@ -172,10 +200,10 @@ func (b *indexScanBuilder) build(grp memo.RelExpr) {
 		input = b.f.ConstructIndexJoin(input, &b.indexJoinPrivate)
 	}
 
-	// 4. Wrap input in outer filter (which must exist at this point).
+	// 5. Wrap input in outer filter (which must exist at this point).
 	if len(b.outerFilters) == 0 {
-		// indexJoinDef == 0: outerFilters == 0 handled by #1 and #2 above.
-		// indexJoinDef != 0: outerFilters == 0 handled by #3 above.
+		// indexJoinDef == 0: outerFilters == 0 handled by #1, #2 and #3 above.
+		// indexJoinDef != 0: outerFilters == 0 handled by #4 above.
 		panic(errors.AssertionFailedf("outer filter cannot be 0 at this point"))
 	}
 	b.mem.AddSelectToGroup(&memo.SelectExpr{Input: input, Filters: b.outerFilters}, grp)
@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils/testcat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/datadriven"
 )
@@ -126,6 +127,45 @@ func TestDetachMemoRace(t *testing.T) {
 	wg.Wait()
 }
 
+func TestIsPlaceholderIndependent(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	catalog := testcat.New()
+	_, err := catalog.ExecuteDDL("CREATE TABLE abc (a INT PRIMARY KEY, b INT, c STRING, INDEX (c))")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		sql      string
+		expected bool
+	}{
+		// Lookups into the primary index return at most one row for any key.
+		{sql: "SELECT b FROM abc WHERE a = $1", expected: true},
+		{sql: "SELECT b + $1 FROM abc WHERE a = $2", expected: true},
+		{sql: "INSERT INTO abc VALUES ($1, $2, $3)", expected: true},
+		{sql: "SELECT a FROM abc WHERE c = $1", expected: false},
+		{sql: "SELECT a FROM abc WHERE b > $1", expected: false},
+		{sql: "SELECT b FROM abc WHERE a = $1 AND b > $2", expected: false},
+		{sql: "SELECT a FROM abc LIMIT $1", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sql, func(t *testing.T) {
+			var o xform.Optimizer
+			evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+			evalCtx.SessionData.PlanCacheMode = sessiondata.PlanCacheModeForceGeneric
+			testutils.BuildQuery(t, &o, catalog, &evalCtx, tc.sql)
+			if _, err := o.Optimize(); err != nil {
+				t.Fatal(err)
+			}
+			if actual := xform.IsPlaceholderIndependent(o.Memo()); actual != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
 // TestCoster files can be run separately like this:
 //   make test PKG=./pkg/sql/opt/xform TESTS="TestCoster/sort"
 //   make test PKG=./pkg/sql/opt/xform TESTS="TestCoster/scan"
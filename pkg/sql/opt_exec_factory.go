@@ -1162,6 +1162,9 @@ func (ef *execFactory) ConstructExplain(
 			stmtType:      stmtType,
 		}, nil
 
+	case tree.ExplainDebug:
+		return nil, errors.New("EXPLAIN ANALYZE (DEBUG) can only be used as a top-level statement")
+
 	case tree.ExplainPlan:
 		if analyzeSet {
 			return nil, errors.New("EXPLAIN ANALYZE only supported with (DISTSQL) option")
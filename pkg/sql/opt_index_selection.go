@@ -16,7 +16,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
-	"github.com/cockroachdb/errors"
 )
 
 func unconstrainedSpans(
@@ -103,15 +102,10 @@ func encodeConstraintKey(
 		}
 
 		if index.Type == sqlbase.IndexDescriptor_INVERTED {
-			keys, err := sqlbase.EncodeInvertedIndexTableKeys(val, key)
+			key, err = sqlbase.EncodeInvertedIndexSpanKey(val, key)
 			if err != nil {
 				return nil, err
 			}
-			if len(keys) > 1 {
-				err := errors.AssertionFailedf("trying to use multiple keys in index lookup")
-				return nil, err
-			}
-			key = keys[0]
 		} else {
 			key, err = sqlbase.EncodeTableKey(key, val, dir)
 			if err != nil {
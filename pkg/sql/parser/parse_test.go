@@ -95,6 +95,7 @@ func TestParse(t *testing.T) {
 		{`CREATE INVERTED INDEX a ON b.c (d)`},
 		{`CREATE INVERTED INDEX a ON b (c) STORING (d)`},
 		{`CREATE INVERTED INDEX a ON b (c) INTERLEAVE IN PARENT d (e)`},
+		{`CREATE INVERTED INDEX a ON b (c gin_trgm_ops)`},
 
 		{`CREATE TABLE a ()`},
 		{`CREATE TEMPORARY TABLE a (b INT8)`},
@@ -114,6 +115,7 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b TIMETZ)`},
 		{`CREATE TABLE a (b UUID)`},
 		{`CREATE TABLE a (b INET)`},
+		{`CREATE TABLE a (b TSVECTOR, c TSQUERY)`},
 		{`CREATE TABLE a (b "char")`},
 		{`CREATE TABLE a (b INT8 NULL)`},
 		{`CREATE TABLE a (b INT8 CONSTRAINT maybe NULL)`},
@@ -400,6 +402,7 @@ func TestParse(t *testing.T) {
 		{`EXPLAIN SHOW barfoo`},
 		{`SHOW database`},
 		{`SHOW timezone`},
+		{`SHOW pg_trgm.similarity_threshold`},
 		{`SHOW "BLAH"`},
 
 		{`SHOW CLUSTER SETTING a`},
@@ -667,6 +670,7 @@ func TestParse(t *testing.T) {
 		{`SELECT 'Deutsch' COLLATE de`},
 		{`SELECT a @> b`},
 		{`SELECT a <@ b`},
+		{`SELECT a @@ b`},
 		{`SELECT a ? b`},
 		{`SELECT a ?| b`},
 		{`SELECT a ?& b`},
@@ -873,6 +877,12 @@ func TestParse(t *testing.T) {
 
 		{`SELECT 1 FROM t GROUP BY a`},
 		{`SELECT 1 FROM t GROUP BY a, b`},
+		{`SELECT a, sum(c) FROM t GROUP BY ROLLUP (a, b)`},
+		{`SELECT a, sum(c) FROM t GROUP BY CUBE (a, (b, c))`},
+		{`SELECT a, sum(c) FROM t GROUP BY GROUPING SETS ((a, b), a, ())`},
+		{`SELECT a, sum(c) FROM t GROUP BY a, GROUPING SETS (b, ROLLUP (c, d))`},
+		{`SELECT a, grouping(a, b) FROM t GROUP BY ROLLUP (a, b)`},
+		{`SELECT sets FROM t`},
 		{`SELECT sum(x ORDER BY y) FROM t`},
 		{`SELECT sum(x ORDER BY y, z) FROM t`},
 
@@ -966,6 +976,9 @@ func TestParse(t *testing.T) {
 
 		{`SELECT avg(1) FILTER (WHERE a > b)`},
 		{`SELECT avg(1) FILTER (WHERE a > b) OVER (ORDER BY c)`},
+		{`SELECT percentile_disc(0.5) WITHIN GROUP (ORDER BY c) FROM t`},
+		{`SELECT percentile_cont(ARRAY[0.5, 0.95]) WITHIN GROUP (ORDER BY c DESC) FROM t`},
+		{`SELECT mode() WITHIN GROUP (ORDER BY c) FILTER (WHERE a > b) FROM t`},
 
 		{`SELECT a FROM t UNION SELECT 1 FROM t`},
 		{`SELECT a FROM t UNION SELECT 1 FROM t UNION SELECT 1 FROM t`},
@@ -1313,6 +1326,8 @@ func TestParse(t *testing.T) {
 		{`EXPLAIN SHOW BACKUP 'bar'`},
 		{`SHOW BACKUP RANGES 'bar'`},
 		{`SHOW BACKUP FILES 'bar'`},
+		{`SHOW BACKUPS IN 'bar'`},
+		{`SHOW BACKUPS IN $1`},
 
 		{`BACKUP TABLE foo TO 'bar' AS OF SYSTEM TIME '1' INCREMENTAL FROM 'baz'`},
 		{`BACKUP TABLE foo TO $1 INCREMENTAL FROM 'bar', $2, 'baz'`},
@@ -1325,6 +1340,18 @@ func TestParse(t *testing.T) {
 		{`BACKUP DATABASE foo TO ($1, $2)`},
 		{`BACKUP DATABASE foo TO ($1, $2) INCREMENTAL FROM 'baz'`},
 
+		{`BACKUP TO 'bar'`},
+		{`EXPLAIN BACKUP TO 'bar'`},
+		{`BACKUP TO 'bar' AS OF SYSTEM TIME '1' INCREMENTAL FROM 'baz'`},
+		{`BACKUP TO ($1, $2) WITH revision_history`},
+
+		{`BACKUP TABLE foo INTO 'bar'`},
+		{`BACKUP DATABASE foo INTO 'bar' AS OF SYSTEM TIME '1' WITH revision_history`},
+		{`BACKUP TABLE foo INTO LATEST IN 'bar'`},
+		{`BACKUP DATABASE foo INTO LATEST IN ($1, $2)`},
+		{`BACKUP INTO 'bar'`},
+		{`BACKUP INTO LATEST IN 'bar' WITH revision_history`},
+
 		{`RESTORE TABLE foo FROM 'bar'`},
 		{`EXPLAIN RESTORE TABLE foo FROM 'bar'`},
 		{`RESTORE TABLE foo FROM $1`},
@@ -1343,6 +1370,16 @@ func TestParse(t *testing.T) {
 		{`RESTORE DATABASE foo FROM ($1, $2), ($3, $4)`},
 		{`RESTORE DATABASE foo FROM ($1, $2), ($3, $4) AS OF SYSTEM TIME '1'`},
 
+		{`RESTORE FROM 'bar'`},
+		{`EXPLAIN RESTORE FROM 'bar'`},
+		{`RESTORE FROM $1, $2, 'bar'`},
+		{`RESTORE FROM ($1, $2), $3 AS OF SYSTEM TIME '1'`},
+
+		{`RESTORE TABLE foo FROM LATEST IN 'bar'`},
+		{`RESTORE DATABASE foo FROM LATEST IN ($1, $2) AS OF SYSTEM TIME '1'`},
+		{`RESTORE FROM LATEST IN 'bar'`},
+		{`RESTORE FROM LATEST IN $1 AS OF SYSTEM TIME '1' WITH skip_missing_foreign_keys`},
+
 		{`BACKUP TABLE foo TO 'bar' WITH key1, key2 = 'value'`},
 		{`RESTORE TABLE foo FROM 'bar' WITH key1, key2 = 'value'`},
 
@@ -1374,6 +1411,8 @@ func TestParse(t *testing.T) {
 		// {`CREATE CHANGEFEED FOR TABLE foo PARTITION bar, baz INTO 'sink'`},
 		// {`CREATE CHANGEFEED FOR DATABASE foo INTO 'sink'`},
 		{`CREATE CHANGEFEED FOR TABLE foo INTO 'sink' WITH bar = 'baz'`},
+		{`EXPERIMENTAL CHANGEFEED AS SELECT a, b + 1 AS c FROM foo WHERE a > 1`},
+		{`CREATE CHANGEFEED INTO 'sink' WITH bar = 'baz' AS SELECT * FROM foo`},
 
 		// Regression for #15926
 		{`SELECT * FROM ((t1 NATURAL JOIN t2 WITH ORDINALITY AS o1)) WITH ORDINALITY AS o2`},
@@ -1422,6 +1461,10 @@ func TestParse2(t *testing.T) {
 			`CREATE INVERTED INDEX a ON b (c)`},
 		{`CREATE UNIQUE INDEX a ON b USING GIN (c)`,
 			`CREATE UNIQUE INVERTED INDEX a ON b (c)`},
+		{`CREATE INDEX a ON b USING GIN (c gin_trgm_ops)`,
+			`CREATE INVERTED INDEX a ON b (c gin_trgm_ops)`},
+		{`CREATE INDEX a ON b USING GIST (c)`,
+			`CREATE INVERTED INDEX a ON b (c)`},
 
 		{`CREATE TABLE a (b BIGSERIAL, c SMALLSERIAL, d SERIAL)`,
 			`CREATE TABLE a (b SERIAL8, c SERIAL2, d SERIAL8)`},
@@ -1474,6 +1517,9 @@ func TestParse2(t *testing.T) {
 		{`SELECT a FROM t WHERE a IS UNKNOWN`, `SELECT a FROM t WHERE a IS NULL`},
 		{`SELECT a FROM t WHERE a IS NOT UNKNOWN`, `SELECT a FROM t WHERE a IS NOT NULL`},
 
+		{`SELECT GROUPING (a,b,c) FROM t GROUP BY rollup(a,b,c)`, `SELECT grouping(a, b, c) FROM t GROUP BY ROLLUP (a, b, c)`},
+		{`SELECT a FROM t GROUP BY grouping sets (a,(b),())`, `SELECT a FROM t GROUP BY GROUPING SETS (a, (b), ())`},
+
 		{`SELECT +1`, `SELECT 1`},
 		{`SELECT - - 5`, `SELECT 5`},
 		{`SELECT - + 5`, `SELECT -5`},
@@ -1835,6 +1881,8 @@ func TestParse2(t *testing.T) {
 
 		{`CREATE CHANGEFEED FOR TABLE foo INTO sink`,
 			`CREATE CHANGEFEED FOR TABLE foo INTO 'sink'`},
+		{`CREATE CHANGEFEED WITH diff AS SELECT a FROM foo`,
+			`EXPERIMENTAL CHANGEFEED WITH diff AS SELECT a FROM foo`},
 
 		{`SHOW CLUSTER SETTING ALL`, `SHOW ALL CLUSTER SETTINGS`},
 
@@ -2163,6 +2211,8 @@ func TestParse2(t *testing.T) {
 		{`SELECT '{}'::JSONB ?& 'a' = false`, `SELECT ('{}'::JSONB ?& 'a') = false`},
 		{`SELECT '{}'::JSONB @> '{}'::JSONB = false`, `SELECT ('{}'::JSONB @> '{}'::JSONB) = false`},
 		{`SELECT '{}'::JSONB <@ '{}'::JSONB = false`, `SELECT ('{}'::JSONB <@ '{}'::JSONB) = false`},
+		// Check that the text search match operator has higher precedence than '='.
+		{`SELECT 'a'::TSVECTOR @@ 'a'::TSQUERY = false`, `SELECT ('a'::TSVECTOR @@ 'a'::TSQUERY) = false`},
 	}
 	for _, d := range testData {
 		t.Run(d.sql, func(t *testing.T) {
@@ -3094,7 +3144,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 
 		{`CREATE INDEX a ON b(c) WHERE d > 0`, 9683, ``},
 		{`CREATE INDEX a ON b USING HASH (c)`, 0, `index using hash`},
-		{`CREATE INDEX a ON b USING GIST (c)`, 0, `index using gist`},
 		{`CREATE INDEX a ON b USING SPGIST (c)`, 0, `index using spgist`},
 		{`CREATE INDEX a ON b USING BRIN (c)`, 0, `index using brin`},
 
@@ -3125,13 +3174,11 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`SELECT a(b) 'c'`, 0, `a(...) SCONST`},
 		{`SELECT (a,b) OVERLAPS (c,d)`, 0, `overlaps`},
 		{`SELECT UNIQUE (SELECT b)`, 0, `UNIQUE predicate`},
-		{`SELECT GROUPING (a,b,c)`, 0, `d_expr grouping`},
 		{`SELECT a(VARIADIC b)`, 0, `variadic`},
 		{`SELECT a(b, c, VARIADIC b)`, 0, `variadic`},
 		{`SELECT CURRENT_TIME`, 26097, `current_time`},
 		{`SELECT CURRENT_TIME()`, 26097, `current_time`},
 		{`SELECT TREAT (a AS INT8)`, 0, `treat`},
-		{`SELECT a(b) WITHIN GROUP (ORDER BY c)`, 0, `within group`},
 
 		{`SELECT a FROM t ORDER BY a NULLS LAST`, 6224, ``},
 		{`SELECT a FROM t ORDER BY a ASC NULLS LAST`, 6224, ``},
@@ -3149,8 +3196,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE TABLE a(b PG_LSN)`, 0, `pg_lsn`},
 		{`CREATE TABLE a(b POINT)`, 21286, `point`},
 		{`CREATE TABLE a(b POLYGON)`, 21286, `polygon`},
-		{`CREATE TABLE a(b TSQUERY)`, 7821, `tsquery`},
-		{`CREATE TABLE a(b TSVECTOR)`, 7821, `tsvector`},
 		{`CREATE TABLE a(b TXID_SNAPSHOT)`, 0, `txid_snapshot`},
 		{`CREATE TABLE a(b XML)`, 0, `xml`},
 
@@ -325,6 +325,10 @@ func (s *scanner) scan(lval *sqlSymType) {
 			s.pos++
 			lval.id = CONTAINS
 			return
+		case '@': // @@
+			s.pos++
+			lval.id = AT_AT
+			return
 		}
 		return
 
@@ -63,6 +63,7 @@ func TestScanner(t *testing.T) {
 		{`$`, []int{'$'}},
 		{`&`, []int{'&'}},
 		{`&&`, []int{AND_AND}},
+		{`@@`, []int{AT_AT}},
 		{`|`, []int{'|'}},
 		{`||`, []int{CONCAT}},
 		{`#`, []int{'#'}},
@@ -498,9 +498,9 @@ func newNameFromStr(s string) *tree.Name {
 // Ordinary key words in alphabetical order.
 %token <str> ABORT ACTION ADD ADMIN AGGREGATE
 %token <str> ALL ALTER ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC
-%token <str> ASYMMETRIC AT AUTHORIZATION AUTOMATIC
+%token <str> ASYMMETRIC AT AT_AT AUTHORIZATION AUTOMATIC
 
-%token <str> BACKUP BEGIN BETWEEN BIGINT BIGSERIAL BIT
+%token <str> BACKUP BACKUPS BEGIN BETWEEN BIGINT BIGSERIAL BIT
 %token <str> BLOB BOOL BOOLEAN BOTH BY BYTEA BYTES
 
 %token <str> CACHE CANCEL CASCADE CASE CAST CHANGEFEED CHAR
@@ -540,7 +540,7 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str> KEY KEYS KV
 
-%token <str> LANGUAGE LAST LATERAL LC_CTYPE LC_COLLATE
+%token <str> LANGUAGE LAST LATERAL LATEST LC_CTYPE LC_COLLATE
 %token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT LIST LOCAL
 %token <str> LOCALTIME LOCALTIMESTAMP LOCKED LOOKUP LOW LSHIFT
 
@@ -566,7 +566,7 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str> SAVEPOINT SCATTER SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
 %token <str> SERIAL SERIAL2 SERIAL4 SERIAL8
-%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETTING SETTINGS
+%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETS SETTING SETTINGS
 %token <str> SHARE SHOW SIMILAR SIMPLE SKIP SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
 %token <str> START STATISTICS STATUS STDIN STRICT STRING STORE STORED STORING SUBSTRING
@@ -818,6 +818,7 @@ func newNameFromStr(s string) *tree.Name {
 
 %type <tree.Operator> subquery_op
 %type <*tree.UnresolvedName> func_name
+%type <str> opt_class
 %type <str> opt_collate
 
 %type <str> database_name index_name opt_index_name column_name insert_column_item statistics_name window_name
@@ -865,6 +866,8 @@ func newNameFromStr(s string) *tree.Name {
 %type <*tree.UpdateExpr> set_clause multiple_set_clause
 %type <tree.ArraySubscripts> array_subscripts
 %type <tree.GroupBy> group_clause
+%type <tree.Exprs> group_by_list
+%type <tree.Expr> group_by_item
 %type <*tree.Limit> select_limit
 %type <tree.TableNames> relation_expr_list
 %type <tree.ReturningClause> returning_clause
@@ -992,7 +995,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <[]*tree.CTE> cte_list
 %type <*tree.CTE> common_table_expr
 
-%type <empty> within_group_clause
+%type <tree.OrderBy> within_group_clause
 %type <tree.Expr> filter_clause
 %type <tree.Exprs> opt_partition_clause
 %type <tree.Window> window_clause window_definition_list
@@ -1032,7 +1035,7 @@ func newNameFromStr(s string) *tree.Name {
 %nonassoc  '<' '>' '=' LESS_EQUALS GREATER_EQUALS NOT_EQUALS
 %nonassoc  '~' BETWEEN IN LIKE ILIKE SIMILAR NOT_REGMATCH REGIMATCH NOT_REGIMATCH NOT_LA
 %nonassoc  ESCAPE              // ESCAPE must be just above LIKE/ILIKE/SIMILAR
-%nonassoc  CONTAINS CONTAINED_BY '?' JSON_SOME_EXISTS JSON_ALL_EXISTS
+%nonassoc  CONTAINS CONTAINED_BY '?' JSON_SOME_EXISTS JSON_ALL_EXISTS AT_AT
 %nonassoc  OVERLAPS
 %left      POSTFIXOP           // dummy for postfix OP rules
 // To support target_elem without AS, we must give IDENT an explicit priority
@@ -1807,12 +1810,17 @@ opt_validate_behavior:
 // %Help: BACKUP - back up data to external storage
 // %Category: CCL
 // %Text:
-// BACKUP <targets...> TO <location...>
+// BACKUP [<targets...>] TO <location...>
 //        [ AS OF SYSTEM TIME <expr> ]
 //        [ INCREMENTAL FROM <location...> ]
 //        [ WITH <option> [= <value>] [, ...] ]
 //
+// BACKUP [<targets...>] INTO [LATEST IN] <collection...>
+//        [ AS OF SYSTEM TIME <expr> ]
+//        [ WITH <option> [= <value>] [, ...] ]
+//
 // Targets:
+//    Empty targets list: backup full cluster.
 //    TABLE <pattern> [, ...]
 //    DATABASE <databasename> [, ...]
 //
@@ -1829,16 +1837,41 @@ backup_stmt:
   {
     $$.val = &tree.Backup{Targets: $2.targetList(), To: $4.partitionedBackup(), IncrementalFrom: $6.exprs(), AsOf: $5.asOfClause(), Options: $7.kvOptions()}
   }
+| BACKUP TO partitioned_backup opt_as_of_clause opt_incremental opt_with_options
+  {
+    $$.val = &tree.Backup{DescriptorCoverage: tree.AllDescriptors, To: $3.partitionedBackup(), IncrementalFrom: $5.exprs(), AsOf: $4.asOfClause(), Options: $6.kvOptions()}
+  }
+| BACKUP targets INTO partitioned_backup opt_as_of_clause opt_with_options
+  {
+    $$.val = &tree.Backup{Targets: $2.targetList(), To: $4.partitionedBackup(), Nested: true, AsOf: $5.asOfClause(), Options: $6.kvOptions()}
+  }
+| BACKUP targets INTO LATEST IN partitioned_backup opt_as_of_clause opt_with_options
+  {
+    $$.val = &tree.Backup{Targets: $2.targetList(), To: $6.partitionedBackup(), Nested: true, AppendToLatest: true, AsOf: $7.asOfClause(), Options: $8.kvOptions()}
+  }
+| BACKUP INTO partitioned_backup opt_as_of_clause opt_with_options
+  {
+    $$.val = &tree.Backup{DescriptorCoverage: tree.AllDescriptors, To: $3.partitionedBackup(), Nested: true, AsOf: $4.asOfClause(), Options: $5.kvOptions()}
+  }
+| BACKUP INTO LATEST IN partitioned_backup opt_as_of_clause opt_with_options
+  {
+    $$.val = &tree.Backup{DescriptorCoverage: tree.AllDescriptors, To: $5.partitionedBackup(), Nested: true, AppendToLatest: true, AsOf: $6.asOfClause(), Options: $7.kvOptions()}
+  }
 | BACKUP error // SHOW HELP: BACKUP
 
 // %Help: RESTORE - restore data from external storage
 // %Category: CCL
 // %Text:
-// RESTORE <targets...> FROM <location...>
+// RESTORE [<targets...>] FROM <location...>
+//         [ AS OF SYSTEM TIME <expr> ]
+//         [ WITH <option> [= <value>] [, ...] ]
+//
+// RESTORE [<targets...>] FROM LATEST IN <collection...>
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
 //
 // Targets:
+//    Empty targets list: restore full cluster.
 //    TABLE <pattern> [, ...]
 //    DATABASE <databasename> [, ...]
 //
@@ -1859,6 +1892,30 @@ restore_stmt:
   {
     $$.val = &tree.Restore{Targets: $2.targetList(), From: $4.partitionedBackups(), AsOf: $5.asOfClause(), Options: $6.kvOptions()}
   }
+| RESTORE FROM partitioned_backup_list opt_with_options
+  {
+    $$.val = &tree.Restore{DescriptorCoverage: tree.AllDescriptors, From: $3.partitionedBackups(), Options: $4.kvOptions()}
+  }
+| RESTORE FROM partitioned_backup_list as_of_clause opt_with_options
+  {
+    $$.val = &tree.Restore{DescriptorCoverage: tree.AllDescriptors, From: $3.partitionedBackups(), AsOf: $4.asOfClause(), Options: $5.kvOptions()}
+  }
+| RESTORE targets FROM LATEST IN partitioned_backup opt_with_options
+  {
+    $$.val = &tree.Restore{Targets: $2.targetList(), From: []tree.PartitionedBackup{$6.partitionedBackup()}, FromLatest: true, Options: $7.kvOptions()}
+  }
+| RESTORE targets FROM LATEST IN partitioned_backup as_of_clause opt_with_options
+  {
+    $$.val = &tree.Restore{Targets: $2.targetList(), From: []tree.PartitionedBackup{$6.partitionedBackup()}, FromLatest: true, AsOf: $7.asOfClause(), Options: $8.kvOptions()}
+  }
+| RESTORE FROM LATEST IN partitioned_backup opt_with_options
+  {
+    $$.val = &tree.Restore{DescriptorCoverage: tree.AllDescriptors, From: []tree.PartitionedBackup{$5.partitionedBackup()}, FromLatest: true, Options: $6.kvOptions()}
+  }
+| RESTORE FROM LATEST IN partitioned_backup as_of_clause opt_with_options
+  {
+    $$.val = &tree.Restore{DescriptorCoverage: tree.AllDescriptors, From: []tree.PartitionedBackup{$5.partitionedBackup()}, FromLatest: true, AsOf: $6.asOfClause(), Options: $7.kvOptions()}
+  }
 | RESTORE error // SHOW HELP: RESTORE
 
 partitioned_backup:
@@ -2403,6 +2460,22 @@ create_changefeed_stmt:
       Options: $5.kvOptions(),
     }
   }
+| CREATE CHANGEFEED opt_changefeed_sink opt_with_options AS select_stmt
+  {
+    $$.val = &tree.CreateChangefeed{
+      SinkURI: $3.expr(),
+      Options: $4.kvOptions(),
+      Select: $6.slct(),
+    }
+  }
+| EXPERIMENTAL CHANGEFEED opt_with_options AS select_stmt
+  {
+    /* SKIP DOC */
+    $$.val = &tree.CreateChangefeed{
+      Options: $3.kvOptions(),
+      Select: $5.slct(),
+    }
+  }
 
 changefeed_targets:
   single_table_pattern_list
@@ -2635,6 +2708,7 @@ table_name_list:
 // EXPLAIN ([PLAN ,] <planoptions...> ) <statement>
 // EXPLAIN [ANALYZE] (DISTSQL) <statement>
 // EXPLAIN ANALYZE [(DISTSQL)] <statement>
+// EXPLAIN ANALYZE (DEBUG) <statement>
 //
 // Explainable statements:
 //     SELECT, CREATE, DROP, ALTER, INSERT, UPSERT, UPDATE, DELETE,
@@ -3370,6 +3444,9 @@ show_session_stmt:
 
 session_var:
   IDENT
+// Variables of extensions, such as pg_trgm.similarity_threshold, are
+// qualified by the name of the extension.
+| IDENT '.' IDENT { $$ = $1 + "." + $3 }
 // Although ALL, SESSION_USER and DATABASE are identifiers for the
 // purpose of SHOW, they lex as separate token types, so they need
 // separate rules.
@@ -3426,10 +3503,19 @@ show_histogram_stmt:
 
 // %Help: SHOW BACKUP - list backup contents
 // %Category: CCL
-// %Text: SHOW BACKUP [SCHEMAS|FILES|RANGES] <location>
+// %Text:
+// SHOW BACKUP [SCHEMAS|FILES|RANGES] <location>
+// SHOW BACKUPS IN <collection>
 // %SeeAlso: WEBDOCS/show-backup.html
 show_backup_stmt:
-  SHOW BACKUP string_or_placeholder
+  SHOW BACKUPS IN string_or_placeholder
+  {
+    $$.val = &tree.ShowBackup{
+      InCollection: true,
+      Path:         $4.expr(),
+    }
+  }
+| SHOW BACKUP string_or_placeholder
   {
     $$.val = &tree.ShowBackup{
       Details: tree.BackupDefaultDetails,
@@ -5163,11 +5249,13 @@ opt_using_gin_btree:
   {
     /* FORCE DOC */
     switch $2 {
-      case "gin":
+      case "gin", "gist":
+        // GiST indexes are supported as inverted indexes, which is how
+        // the spatial indexes of PostGIS are created.
         $$.val = true
       case "btree":
         $$.val = false
-      case "hash", "gist", "spgist", "brin":
+      case "hash", "spgist", "brin":
         return unimplemented(sqllex, "index using " + $2)
       default:
         sqllex.Error("unrecognized access method: " + $2)
@@ -5203,12 +5291,12 @@ index_params:
 // expressions in parens. For backwards-compatibility reasons, we allow an
 // expression that is just a function call to be written without parens.
 index_elem:
-  a_expr opt_asc_desc opt_nulls_order
+  a_expr opt_class opt_asc_desc opt_nulls_order
   {
     /* FORCE DOC */
     e := $1.expr()
-    dir := $2.dir()
-    nullsOrder := $3.nullsOrder()
+    dir := $3.dir()
+    nullsOrder := $4.nullsOrder()
     // We currently only support the opposite of Postgres defaults.
     if nullsOrder != tree.DefaultNullsOrder {
       if dir == tree.Descending && nullsOrder == tree.NullsFirst {
@@ -5219,12 +5307,18 @@ index_elem:
       }
     }
     if colName, ok := e.(*tree.UnresolvedName); ok && colName.NumParts == 1 {
-      $$.val = tree.IndexElem{Column: tree.Name(colName.Parts[0]), Direction: dir, NullsOrder: nullsOrder}
+      $$.val = tree.IndexElem{Column: tree.Name(colName.Parts[0]), OpClass: tree.Name($2), Direction: dir, NullsOrder: nullsOrder}
     } else {
       return unimplementedWithIssueDetail(sqllex, 9682, fmt.Sprintf("%T", e))
     }
   }
 
+// opt_class is the operator class of an index column. Only identifiers are
+// accepted, so that it doesn't conflict with the keywords that can follow.
+opt_class:
+  IDENT { $$ = $1 }
+| /* EMPTY */ { $$ = "" }
+
 opt_collate:
   COLLATE collation_name { $$ = $2 }
 | /* EMPTY */ { $$ = "" }
@@ -6346,7 +6440,7 @@ first_or_next:
 // Each item in the group_clause list is either an expression tree or a
 // GroupingSet node of some type.
 group_clause:
-  GROUP BY expr_list
+  GROUP BY group_by_list
   {
     $$.val = tree.GroupBy($3.exprs())
   }
@@ -6355,6 +6449,31 @@ group_clause:
     $$.val = tree.GroupBy(nil)
   }
 
+group_by_list:
+  group_by_item
+  {
+    $$.val = tree.Exprs{$1.expr()}
+  }
+| group_by_list ',' group_by_item
+  {
+    $$.val = append($1.exprs(), $3.expr())
+  }
+
+group_by_item:
+  a_expr
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.RollupGroupingSet, Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.CubeGroupingSet, Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.GroupingSets, Exprs: $4.exprs()}
+  }
+
 having_clause:
   HAVING a_expr
   {
@@ -7552,6 +7671,10 @@ a_expr:
   {
     $$.val = &tree.ComparisonExpr{Operator: tree.ContainedBy, Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr AT_AT a_expr
+  {
+    $$.val = &tree.ComparisonExpr{Operator: tree.TSMatches, Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr '=' a_expr
   {
     $$.val = &tree.ComparisonExpr{Operator: tree.EQ, Left: $1.expr(), Right: $3.expr()}
@@ -8078,7 +8201,10 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("grouping"), Exprs: $3.exprs()}
+  }
 
 func_application:
   func_name '(' ')'
@@ -8118,6 +8244,18 @@ func_expr:
   func_application within_group_clause filter_clause over_clause
   {
     f := $1.expr().(*tree.FuncExpr)
+    if w := $2.orderBy(); w != nil {
+      if f.OrderBy != nil {
+        sqllex.Error("cannot use multiple ORDER BY clauses with WITHIN GROUP")
+        return 1
+      }
+      if f.Type == tree.DistinctFuncType {
+        sqllex.Error("cannot use DISTINCT with WITHIN GROUP")
+        return 1
+      }
+      f.AggType = tree.OrderedSetAgg
+      f.OrderBy = w
+    }
     f.Filter = $3.expr()
     f.WindowDef = $4.windowDef()
     $$.val = f
@@ -8308,8 +8446,14 @@ special_function:
 
 // Aggregate decoration clauses
 within_group_clause:
-WITHIN GROUP '(' sort_clause ')' { return unimplemented(sqllex, "within group") }
-| /* EMPTY */ {}
+  WITHIN GROUP '(' sort_clause ')'
+  {
+    $$.val = $4.orderBy()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.OrderBy(nil)
+  }
 
 filter_clause:
   FILTER '(' WHERE a_expr ')'
@@ -9361,6 +9505,7 @@ unreserved_keyword:
 | AUTOMATIC
 | AUTHORIZATION
 | BACKUP
+| BACKUPS
 | BEGIN
 | BIGSERIAL
 | BLOB
@@ -9456,6 +9601,7 @@ unreserved_keyword:
 | KV
 | LANGUAGE
 | LAST
+| LATEST
 | LC_COLLATE
 | LC_CTYPE
 | LEASE
@@ -9557,6 +9703,7 @@ unreserved_keyword:
 | SESSION
 | SESSIONS
 | SET
+| SETS
 | SHARE
 | SHOW
 | SIMPLE
@@ -2670,6 +2670,10 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.TupleFamily:       typCategoryPseudo,
 	types.OidFamily:         typCategoryNumeric,
 	types.UuidFamily:        typCategoryUserDefined,
+	types.TSVectorFamily:    typCategoryUserDefined,
+	types.TSQueryFamily:     typCategoryUserDefined,
+	types.GeometryFamily:    typCategoryUserDefined,
+	types.GeographyFamily:   typCategoryUserDefined,
 	types.INetFamily:        typCategoryNetworkAddr,
 	types.UnknownFamily:     typCategoryUnknown,
 }
@@ -22,6 +22,7 @@ import (
 	"unsafe"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/oidext"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -29,9 +30,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/geo"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/errors"
 	"github.com/jackc/pgx/pgtype"
@@ -372,6 +375,26 @@ func DecodeOidDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case oid.T_tsvector:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDTSVector(string(b))
+		case oid.T_tsquery:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDTSQuery(string(b))
+		case oidext.T_geometry:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDGeometry(string(b))
+		case oidext.T_geography:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDGeography(string(b))
 		}
 		if _, ok := types.ArrayOids[id]; ok {
 			// Arrays come in in their string form, so we parse them as such and later
@@ -576,6 +599,30 @@ func DecodeOidDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case oid.T_tsvector:
+			v, err := decodeBinaryTSVector(b)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDTSVector(v), nil
+		case oid.T_tsquery:
+			q, err := decodeBinaryTSQuery(b)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDTSQuery(q), nil
+		case oidext.T_geometry:
+			g, err := geo.GeometryFromEWKB(b)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDGeometry(g), nil
+		case oidext.T_geography:
+			g, err := geo.GeographyFromEWKB(b)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDGeography(g), nil
 		case oid.T_varbit, oid.T_bit:
 			if len(b) < 4 {
 				return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
@@ -800,3 +847,145 @@ const (
 	// AF_NET + 1.
 	PGBinaryIPv6family byte = 3
 )
+
+// readTerminatedString reads a null-terminated string from the front of b,
+// returning the string and the remaining bytes.
+func readTerminatedString(b []byte) (string, []byte, error) {
+	i := bytes.IndexByte(b, 0)
+	if i < 0 {
+		return "", nil, NewInvalidBinaryRepresentationErrorf("unterminated string")
+	}
+	if err := validateStringBytes(b[:i]); err != nil {
+		return "", nil, err
+	}
+	return string(b[:i]), b[i+1:], nil
+}
+
+// decodeBinaryTSVector decodes a tsvector in the Postgres binary format: the
+// number of lexemes, followed by each lexeme as a null-terminated string with
+// its number of positions and its positions. The weight of a position is
+// stored in its two high bits.
+func decodeBinaryTSVector(b []byte) (tsearch.TSVector, error) {
+	if len(b) < 4 {
+		return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
+	}
+	n := int(int32(binary.BigEndian.Uint32(b)))
+	b = b[4:]
+	if n < 0 {
+		return nil, NewInvalidBinaryRepresentationErrorf("invalid number of lexemes: %d", n)
+	}
+	lexemes := make([]tsearch.Lexeme, 0, n)
+	for i := 0; i < n; i++ {
+		word, rest, err := readTerminatedString(b)
+		if err != nil {
+			return nil, err
+		}
+		b = rest
+		if word == "" {
+			return nil, NewInvalidBinaryRepresentationErrorf("invalid tsvector: empty lexeme")
+		}
+		if len(b) < 2 {
+			return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
+		}
+		numPos := int(binary.BigEndian.Uint16(b))
+		b = b[2:]
+		if len(b) < 2*numPos {
+			return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
+		}
+		l := tsearch.Lexeme{Word: word}
+		for j := 0; j < numPos; j++ {
+			wep := binary.BigEndian.Uint16(b)
+			b = b[2:]
+			pos := wep & tsearch.MaxPos
+			if pos == 0 {
+				return nil, NewInvalidBinaryRepresentationErrorf("invalid tsvector: position is zero")
+			}
+			l.Positions = append(l.Positions, tsearch.Position{Pos: pos, Weight: tsearch.Weight(wep >> 14)})
+		}
+		lexemes = append(lexemes, l)
+	}
+	return tsearch.MakeTSVector(lexemes), nil
+}
+
+// decodeBinaryTSQuery decodes a tsquery in the Postgres binary format: the
+// number of nodes, followed by the nodes in prefix order, with the right
+// operand of a binary operator before its left operand.
+func decodeBinaryTSQuery(b []byte) (tsearch.TSQuery, error) {
+	const (
+		itemVal = 1
+		itemOpr = 2
+
+		oprNot = 1
+		oprAnd = 2
+		oprOr  = 3
+	)
+	if len(b) < 4 {
+		return tsearch.TSQuery{}, NewProtocolViolationErrorf("insufficient data: %d", len(b))
+	}
+	n := int(int32(binary.BigEndian.Uint32(b)))
+	b = b[4:]
+	if n == 0 {
+		return tsearch.TSQuery{}, nil
+	}
+	var readNode func() (*tsearch.Node, error)
+	readNode = func() (*tsearch.Node, error) {
+		if n == 0 {
+			return nil, NewInvalidBinaryRepresentationErrorf("malformed tsquery: operand not found")
+		}
+		n--
+		if len(b) < 2 {
+			return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
+		}
+		typ, arg := b[0], b[1]
+		b = b[2:]
+		switch typ {
+		case itemVal:
+			if len(b) < 1 {
+				return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
+			}
+			prefix := b[0] != 0
+			word, rest, err := readTerminatedString(b[1:])
+			if err != nil {
+				return nil, err
+			}
+			b = rest
+			if word == "" {
+				return nil, NewInvalidBinaryRepresentationErrorf("invalid tsquery: empty lexeme")
+			}
+			return &tsearch.Node{Op: tsearch.Term, Lexeme: word, Prefix: prefix, Weights: arg & 0xf}, nil
+		case itemOpr:
+			switch arg {
+			case oprNot:
+				operand, err := readNode()
+				if err != nil {
+					return nil, err
+				}
+				return &tsearch.Node{Op: tsearch.Not, Left: operand}, nil
+			case oprAnd, oprOr:
+				right, err := readNode()
+				if err != nil {
+					return nil, err
+				}
+				left, err := readNode()
+				if err != nil {
+					return nil, err
+				}
+				op := tsearch.And
+				if arg == oprOr {
+					op = tsearch.Or
+				}
+				return &tsearch.Node{Op: op, Left: left, Right: right}, nil
+			}
+			return nil, NewInvalidBinaryRepresentationErrorf("unsupported tsquery operator: %d", arg)
+		}
+		return nil, NewInvalidBinaryRepresentationErrorf("unrecognized tsquery node type: %d", typ)
+	}
+	root, err := readNode()
+	if err != nil {
+		return tsearch.TSQuery{}, err
+	}
+	if n != 0 || len(b) != 0 {
+		return tsearch.TSQuery{}, NewInvalidBinaryRepresentationErrorf("malformed tsquery: extra data")
+	}
+	return tsearch.TSQuery{Root: root}, nil
+}
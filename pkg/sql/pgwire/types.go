@@ -13,6 +13,7 @@ package pgwire
 import (
 	"context"
 	"encoding/binary"
+	"fmt"
 	"math"
 	"math/big"
 	"net"
@@ -34,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -180,6 +182,18 @@ func (b *writeBuffer) writeTextDatum(
 	case *tree.DJSON:
 		b.writeLengthPrefixedString(v.JSON.String())
 
+	case *tree.DTSVector:
+		b.writeLengthPrefixedString(v.TSVector.String())
+
+	case *tree.DTSQuery:
+		b.writeLengthPrefixedString(v.TSQuery.String())
+
+	case *tree.DGeometry:
+		b.writeLengthPrefixedString(fmt.Sprintf("%X", v.EWKB()))
+
+	case *tree.DGeography:
+		b.writeLengthPrefixedString(fmt.Sprintf("%X", v.EWKB()))
+
 	case *tree.DTuple:
 		b.textFormatter.FormatNode(v)
 		b.writeFromFmtCtx(b.textFormatter)
@@ -479,6 +493,43 @@ func (b *writeBuffer) writeBinaryDatum(
 		// Postgres version number, as of writing, `1` is the only valid value.
 		b.writeByte(1)
 		b.writeString(s)
+	case *tree.DTSVector:
+		subWriter := newWriteBuffer(nil /* bytecount */)
+		subWriter.putInt32(int32(len(v.TSVector)))
+		for _, l := range v.TSVector {
+			subWriter.writeTerminatedString(l.Word)
+			subWriter.putInt16(int16(len(l.Positions)))
+			for _, p := range l.Positions {
+				// The weight is stored in the two high bits of the position.
+				subWriter.putInt16(int16(uint16(p.Weight)<<14 | p.Pos))
+			}
+		}
+		b.writeLengthPrefixedBuffer(&subWriter.wrapped)
+	case *tree.DTSQuery:
+		subWriter := newWriteBuffer(nil /* bytecount */)
+		var numNodes int32
+		var count func(n *tsearch.Node)
+		count = func(n *tsearch.Node) {
+			if n != nil {
+				numNodes++
+				count(n.Left)
+				count(n.Right)
+			}
+		}
+		count(v.TSQuery.Root)
+		subWriter.putInt32(numNodes)
+		if v.TSQuery.Root != nil {
+			subWriter.writeBinaryTSQueryNode(v.TSQuery.Root)
+		}
+		b.writeLengthPrefixedBuffer(&subWriter.wrapped)
+	case *tree.DGeometry:
+		ewkb := v.EWKB()
+		b.putInt32(int32(len(ewkb)))
+		b.write(ewkb)
+	case *tree.DGeography:
+		ewkb := v.EWKB()
+		b.putInt32(int32(len(ewkb)))
+		b.write(ewkb)
 	case *tree.DOid:
 		b.putInt32(4)
 		b.putInt32(int32(v.DInt))
@@ -487,6 +538,44 @@ func (b *writeBuffer) writeBinaryDatum(
 	}
 }
 
+// writeBinaryTSQueryNode writes a tsquery node and its operands in the
+// Postgres binary format, which lists the nodes in prefix order with the
+// right operand of a binary operator before its left operand.
+func (b *writeBuffer) writeBinaryTSQueryNode(n *tsearch.Node) {
+	const (
+		itemVal = 1
+		itemOpr = 2
+
+		oprNot = 1
+		oprAnd = 2
+		oprOr  = 3
+	)
+	switch n.Op {
+	case tsearch.Term:
+		b.writeByte(itemVal)
+		b.writeByte(n.Weights)
+		if n.Prefix {
+			b.writeByte(1)
+		} else {
+			b.writeByte(0)
+		}
+		b.writeTerminatedString(n.Lexeme)
+	case tsearch.Not:
+		b.writeByte(itemOpr)
+		b.writeByte(oprNot)
+		b.writeBinaryTSQueryNode(n.Left)
+	default:
+		b.writeByte(itemOpr)
+		if n.Op == tsearch.And {
+			b.writeByte(oprAnd)
+		} else {
+			b.writeByte(oprOr)
+		}
+		b.writeBinaryTSQueryNode(n.Right)
+		b.writeBinaryTSQueryNode(n.Left)
+	}
+}
+
 const (
 	pgTimeFormat              = "15:04:05.999999"
 	pgTimeTZFormat            = pgTimeFormat + "-07:00"
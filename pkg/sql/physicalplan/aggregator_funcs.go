@@ -82,6 +82,13 @@ var passThroughLocalIdxs = []uint32{0}
 
 // DistAggregationTable is DistAggregationInfo look-up table. Functions that
 // don't have an entry in the table are not optimized with a local stage.
+//
+// This is the case for the ordered-set aggregates PERCENTILE_DISC,
+// PERCENTILE_CONT and MODE: their results depend on all the values of a group
+// in the order of their WITHIN GROUP clause, which partial results can't
+// summarize. They still run distributed, but only in a final stage, which
+// receives the sorted rows of each group (hash-partitioned on the group
+// columns, if there are any).
 var DistAggregationTable = map[execinfrapb.AggregatorSpec_Func]DistAggregationInfo{
 	execinfrapb.AggregatorSpec_ANY_NOT_NULL: {
 		LocalStage: []execinfrapb.AggregatorSpec_Func{execinfrapb.AggregatorSpec_ANY_NOT_NULL},
@@ -15,6 +15,8 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -284,6 +286,13 @@ type planTop struct {
 	// avoidBuffering, when set, causes the execution to avoid buffering
 	// results.
 	avoidBuffering bool
+
+	// If we are collecting information for a statement diagnostics bundle,
+	// mem and catalog are the optimizer memo and catalog used to build the plan,
+	// and distSQLDiagram is the diagram of the physical plan of the main query.
+	mem            *memo.Memo
+	catalog        *optCatalog
+	distSQLDiagram execinfrapb.FlowDiagram
 }
 
 // postquery is a query tree that is executed after the main one. It can only
@@ -397,6 +406,14 @@ const (
 	// did not find one.
 	planFlagOptCacheMiss
 
+	// planFlagOptGenericPlanHit is set if the cached generic plan of a prepared
+	// statement was used.
+	planFlagOptGenericPlanHit
+
+	// planFlagOptGenericPlanMiss is set if the generic plan of a prepared
+	// statement was built for this execution.
+	planFlagOptGenericPlanMiss
+
 	// planFlagDistributed is set if the plan is for the DistSQL engine, in
 	// distributed mode.
 	planFlagDistributed
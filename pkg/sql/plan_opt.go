@@ -12,9 +12,12 @@ package sql
 
 import (
 	"context"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/execbuilder"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
@@ -23,7 +26,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmthints"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
 
@@ -31,6 +37,11 @@ var queryCacheEnabled = settings.RegisterBoolSetting(
 	"sql.query_cache.enabled", "enable the query cache", true,
 )
 
+// numCustomPlansBeforeGeneric is the number of custom plans that are built for
+// the first executions of a prepared statement when plan_cache_mode is auto,
+// before its generic plan is considered.
+const numCustomPlansBeforeGeneric = 5
+
 // prepareUsingOptimizer builds a memo for a prepared statement and populates
 // the following stmt.Prepared fields:
 //  - Columns
@@ -175,6 +186,10 @@ func (p *planner) makeOptimizerPlan(ctx context.Context) error {
 	result := plan.(*planTop)
 	result.AST = stmt.AST
 	result.flags = opc.flags
+	if p.collectBundle {
+		result.mem = execMemo
+		result.catalog = &opc.catalog
+	}
 
 	cols := planColumns(result.plan)
 	if stmt.ExpectedTypes != nil {
@@ -207,6 +222,14 @@ type optPlanningCtx struct {
 	useCache bool
 
 	flags planFlags
+
+	// hints contains the plan hints attached to the fingerprint of the
+	// statement, if any.
+	hints *stmthints.Hints
+
+	// ignoredHints describes the hints that could not be applied when
+	// building the statement.
+	ignoredHints []string
 }
 
 // init performs one-time initialization of the planning context; reset() must
@@ -249,6 +272,62 @@ func (opc *optPlanningCtx) reset() {
 		opc.allowMemoReuse = false
 		opc.useCache = false
 	}
+
+	opc.hints = nil
+	opc.ignoredHints = nil
+	if r := p.execCfg.StmtHintsRegistry; r != nil {
+		ast := p.stmt.AST
+		if e, ok := ast.(*tree.Explain); ok {
+			// Apply the hints of the statement being explained, so that EXPLAIN
+			// shows the plan that would be used.
+			ast = e.Statement
+		}
+		opc.hints = r.Lookup(ast)
+	}
+	if opc.hints != nil {
+		telemetry.Inc(sqltelemetry.StatementHintsUseCounter)
+		// Don't store or reuse memos for statements with hints: the hints can
+		// change at any time and are not taken into account when checking
+		// whether a memo is stale.
+		opc.allowMemoReuse = false
+		opc.useCache = false
+		disableHintedRules(&opc.optimizer, opc.hints)
+	}
+}
+
+// disableHintedRules prevents the optimizer from applying the rules disabled
+// by NO_RULE hints. It must be called after the optimizer is (re)initialized.
+func disableHintedRules(o *xform.Optimizer, hints *stmthints.Hints) {
+	if hints == nil || hints.DisabledRules.Empty() {
+		return
+	}
+	disabled := hints.DisabledRules
+	o.NotifyOnMatchedRule(func(rule opt.RuleName) bool {
+		return !disabled.Contains(int(rule))
+	})
+}
+
+// newOptBuilder returns an optbuilder for the statement in the planner, with
+// the hints attached to the statement fingerprint (if any) applied.
+func (opc *optPlanningCtx) newOptBuilder(ctx context.Context) *optbuilder.Builder {
+	p := opc.p
+	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &opc.catalog, opc.optimizer.Factory(), p.stmt.AST)
+	if opc.hints != nil {
+		bld.IndexHints = opc.hints.IndexFlags
+	}
+	return bld
+}
+
+// checkIgnoredHints logs a warning for the hints that could not be applied
+// when building the statement (for example, because they refer to an index
+// that was dropped), and saves them so that EXPLAIN can show them.
+func (opc *optPlanningCtx) checkIgnoredHints(ctx context.Context, bld *optbuilder.Builder) {
+	if len(bld.IgnoredHints) == 0 {
+		return
+	}
+	opc.ignoredHints = bld.IgnoredHints
+	log.Warningf(ctx, "ignoring statement hints %q for %s: %s",
+		opc.hints.Text, opc.p.stmt, strings.Join(bld.IgnoredHints, "; "))
 }
 
 func (opc *optPlanningCtx) log(ctx context.Context, msg string) {
@@ -294,11 +373,12 @@ func (opc *optPlanningCtx) buildReusableMemo(ctx context.Context) (_ *memo.Memo,
 	// that there's even less to do during the EXECUTE phase.
 	//
 	f := opc.optimizer.Factory()
-	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &opc.catalog, f, opc.p.stmt.AST)
+	bld := opc.newOptBuilder(ctx)
 	bld.KeepPlaceholders = true
 	if err := bld.Build(); err != nil {
 		return nil, err
 	}
+	opc.checkIgnoredHints(ctx, bld)
 
 	if bld.DisableMemoReuse {
 		opc.allowMemoReuse = false
@@ -379,10 +459,23 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 			if err != nil {
 				return nil, err
 			}
+			// The generic plan and the costs of the custom plans are stale too.
+			prepared.resetGenericPlan(ctx)
+		}
+		if prepared.Memo.HasPlaceholders() {
+			if memo, ok, err := opc.useGenericMemo(ctx); err != nil || ok {
+				return memo, err
+			}
 		}
 		opc.log(ctx, "reusing cached memo")
 		memo, err := opc.reuseMemo(prepared.Memo)
-		return memo, err
+		if err != nil {
+			return nil, err
+		}
+		if prepared.Memo.HasPlaceholders() {
+			opc.recordCustomPlanCost(memo)
+		}
+		return memo, nil
 	}
 
 	if opc.useCache {
@@ -416,10 +509,11 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 	// We are executing a statement for which there is no reusable memo
 	// available.
 	f := opc.optimizer.Factory()
-	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &opc.catalog, f, opc.p.stmt.AST)
+	bld := opc.newOptBuilder(ctx)
 	if err := bld.Build(); err != nil {
 		return nil, err
 	}
+	opc.checkIgnoredHints(ctx, bld)
 	if _, isCanned := opc.p.stmt.AST.(*tree.CannedOptPlan); !isCanned {
 		if _, err := opc.optimizer.Optimize(); err != nil {
 			return nil, err
@@ -442,3 +536,91 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 
 	return f.Memo(), nil
 }
+
+// useGenericMemo returns the memo of the generic plan of the prepared statement
+// being executed, building it if needed. It returns false if a custom plan must
+// be built instead, according to the plan_cache_mode session variable.
+//
+// The returned memo is fully optimized, and is safe to use during execution of
+// the current statement without modifying it.
+func (opc *optPlanningCtx) useGenericMemo(ctx context.Context) (_ *memo.Memo, ok bool, _ error) {
+	prepared := opc.p.stmt.Prepared
+	mode := opc.p.SessionData().PlanCacheMode
+	switch mode {
+	case sessiondata.PlanCacheModeForceCustom:
+		return nil, false, nil
+	case sessiondata.PlanCacheModeAuto:
+		if len(prepared.customPlanCosts) < numCustomPlansBeforeGeneric {
+			return nil, false, nil
+		}
+	}
+
+	flag := planFlagOptGenericPlanHit
+	if prepared.GenericMemo == nil {
+		genericMemo, err := opc.buildGenericMemo(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := prepared.memAcc.Grow(ctx, genericMemo.MemoryEstimate()); err != nil {
+			return nil, false, err
+		}
+		prepared.GenericMemo = genericMemo
+		flag = planFlagOptGenericPlanMiss
+		opc.log(ctx, "building generic plan")
+	}
+
+	if mode == sessiondata.PlanCacheModeAuto {
+		// Only use the generic plan if it doesn't depend on the values of the
+		// placeholders, so that the custom plans would be the same plan with
+		// the values filled in, and if it is estimated to be as good as the
+		// custom plans, whose costs include the cost of optimizing them.
+		if !xform.IsPlaceholderIndependent(prepared.GenericMemo) {
+			opc.log(ctx, "generic plan depends on the placeholder values")
+			return nil, false, nil
+		}
+		var avgCustomCost memo.Cost
+		for _, c := range prepared.customPlanCosts {
+			avgCustomCost += c
+		}
+		avgCustomCost /= memo.Cost(len(prepared.customPlanCosts))
+		if prepared.GenericMemo.RootExpr().(memo.RelExpr).Cost() > avgCustomCost {
+			opc.log(ctx, "generic plan is more expensive than custom plans")
+			return nil, false, nil
+		}
+	}
+
+	opc.log(ctx, "reusing generic plan")
+	opc.flags.Set(flag)
+	return prepared.GenericMemo, true, nil
+}
+
+// buildGenericMemo builds the generic plan of the statement: a memo that is
+// fully optimized without assigning the values of the placeholders, so that it
+// can be reused for each execution. The values of the placeholders are used
+// during execution, for example as the lookup keys of lookup joins (see the
+// GenerateParameterizedJoin rule).
+func (opc *optPlanningCtx) buildGenericMemo(ctx context.Context) (*memo.Memo, error) {
+	bld := opc.newOptBuilder(ctx)
+	bld.KeepPlaceholders = true
+	if err := bld.Build(); err != nil {
+		return nil, err
+	}
+	opc.checkIgnoredHints(ctx, bld)
+	if _, err := opc.optimizer.Optimize(); err != nil {
+		return nil, err
+	}
+	return opc.optimizer.DetachMemo(), nil
+}
+
+// recordCustomPlanCost records the cost of a custom plan of the prepared
+// statement being executed, which is compared to the cost of its generic plan
+// when plan_cache_mode is auto.
+func (opc *optPlanningCtx) recordCustomPlanCost(customMemo *memo.Memo) {
+	prepared := opc.p.stmt.Prepared
+	if opc.p.SessionData().PlanCacheMode != sessiondata.PlanCacheModeAuto ||
+		len(prepared.customPlanCosts) >= numCustomPlansBeforeGeneric {
+		return
+	}
+	cost := customMemo.RootExpr().(memo.RelExpr).Cost() + xform.CustomPlanOverhead(customMemo)
+	prepared.customPlanCosts = append(prepared.customPlanCosts, cost)
+}
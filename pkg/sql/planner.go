@@ -142,6 +142,16 @@ type planner struct {
 	// See EXECUTE .. DISCARD ROWS.
 	discardRows bool
 
+	// collectBundle is set when we are collecting a diagnostics bundle for a
+	// statement; it triggers saving of extra information like the optimizer
+	// memo and the DistSQL diagram in curPlan.
+	collectBundle bool
+
+	// explainBundle is set (together with collectBundle) when the statement
+	// is run as part of an EXPLAIN ANALYZE (DEBUG); the result of the statement
+	// is then replaced with information about the bundle.
+	explainBundle bool
+
 	// cancelChecker is used by planNodes to check for cancellation of the associated
 	// query.
 	cancelChecker *sqlbase.CancelChecker
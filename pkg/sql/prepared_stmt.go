@@ -50,6 +50,19 @@ type PreparedStatement struct {
 	// if it is used by the optimizer as a starting point.
 	Memo *memo.Memo
 
+	// GenericMemo is the fully optimized memo of the generic plan of the
+	// statement, which is built without the values of the placeholders and can
+	// be reused for each execution. It is only built when Memo has
+	// placeholders and the plan_cache_mode session variable allows generic
+	// plans.
+	GenericMemo *memo.Memo
+
+	// customPlanCosts are the estimated costs of the custom plans built for the
+	// first executions of the statement, including the cost of optimizing them.
+	// They are compared to the cost of the generic plan when plan_cache_mode is
+	// auto.
+	customPlanCosts []memo.Cost
+
 	// refCount keeps track of the number of references to this PreparedStatement.
 	// New references are registered through incRef().
 	// Once refCount hits 0 (through calls to decRef()), the following memAcc is
@@ -80,6 +93,16 @@ func (p *PreparedStatement) MemoryEstimate() int64 {
 	return size
 }
 
+// resetGenericPlan discards the generic plan of the statement and the costs of
+// its custom plans, which are no longer valid once Memo is rebuilt.
+func (p *PreparedStatement) resetGenericPlan(ctx context.Context) {
+	if p.GenericMemo != nil {
+		p.memAcc.Shrink(ctx, p.GenericMemo.MemoryEstimate())
+		p.GenericMemo = nil
+	}
+	p.customPlanCosts = nil
+}
+
 func (p *PreparedStatement) decRef(ctx context.Context) {
 	if p.refCount <= 0 {
 		log.Fatal(ctx, "corrupt PreparedStatement refcount")
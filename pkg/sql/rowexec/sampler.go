@@ -25,7 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
@@ -100,8 +100,11 @@ func newSamplerProcessor(
 		if _, ok := supportedSketchTypes[s.SketchType]; !ok {
 			return nil, errors.Errorf("unsupported sketch type %s", s.SketchType)
 		}
-		if len(s.Columns) != 1 {
-			return nil, unimplemented.NewWithIssue(34422, "multi-column statistics are not supported yet.")
+		if len(s.Columns) == 0 {
+			return nil, errors.Errorf("no columns")
+		}
+		if s.GenerateHistogram && len(s.Columns) != 1 {
+			return nil, errors.Errorf("histograms require one column")
 		}
 	}
 
@@ -279,10 +282,37 @@ func (s *samplerProcessor) mainLoop(ctx context.Context) (earlyExit bool, err er
 
 		var intbuf [8]byte
 		for i := range s.sketches {
-			// TODO(radu): for multi-column sketches, we will need to do this for all
-			// columns.
-			col := s.sketches[i].spec.Columns[0]
 			s.sketches[i].numRows++
+			if len(s.sketches[i].spec.Columns) > 1 {
+				// For multi-column sketches, we insert the concatenation of the key
+				// encodings of all the columns. A row counts as NULL if any of the
+				// columns is NULL. All such rows are inserted as the same value, so
+				// that like in single-column sketches the distinct count includes
+				// exactly one NULL value, which the optimizer subtracts when it
+				// needs the number of non-NULL distinct values.
+				isNull := false
+				for _, col := range s.sketches[i].spec.Columns {
+					if row[col].IsNull() {
+						isNull = true
+						break
+					}
+				}
+				if isNull {
+					s.sketches[i].numNulls++
+					buf = encoding.EncodeNullAscending(buf[:0])
+				} else {
+					buf = buf[:0]
+					for _, col := range s.sketches[i].spec.Columns {
+						buf, err = row[col].Encode(&s.outTypes[col], &da, sqlbase.DatumEncoding_ASCENDING_KEY, buf)
+						if err != nil {
+							return false, err
+						}
+					}
+				}
+				s.sketches[i].sketch.Insert(buf)
+				continue
+			}
+			col := s.sketches[i].spec.Columns[0]
 			isNull := row[col].IsNull()
 			if isNull {
 				s.sketches[i].numNulls++
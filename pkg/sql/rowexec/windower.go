@@ -13,20 +13,16 @@ package rowexec
 import (
 	"context"
 	"fmt"
-	"strings"
 	"unsafe"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/flowinfra"
-	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
-	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
-	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
@@ -35,63 +31,6 @@ import (
 	"github.com/opentracing/opentracing-go"
 )
 
-// GetWindowFunctionInfo returns windowFunc constructor and the return type
-// when given fn is applied to given inputTypes.
-func GetWindowFunctionInfo(
-	fn execinfrapb.WindowerSpec_Func, inputTypes ...types.T,
-) (windowConstructor func(*tree.EvalContext) tree.WindowFunc, returnType *types.T, err error) {
-	if fn.AggregateFunc != nil && *fn.AggregateFunc == execinfrapb.AggregatorSpec_ANY_NOT_NULL {
-		// The ANY_NOT_NULL builtin does not have a fixed return type;
-		// handle it separately.
-		if len(inputTypes) != 1 {
-			return nil, nil, errors.Errorf("any_not_null aggregate needs 1 input")
-		}
-		return builtins.NewAggregateWindowFunc(builtins.NewAnyNotNullAggregate), &inputTypes[0], nil
-	}
-	datumTypes := make([]*types.T, len(inputTypes))
-	for i := range inputTypes {
-		datumTypes[i] = &inputTypes[i]
-	}
-
-	var funcStr string
-	if fn.AggregateFunc != nil {
-		funcStr = fn.AggregateFunc.String()
-	} else if fn.WindowFunc != nil {
-		funcStr = fn.WindowFunc.String()
-	} else {
-		return nil, nil, errors.Errorf(
-			"function is neither an aggregate nor a window function",
-		)
-	}
-	props, builtins := builtins.GetBuiltinProperties(strings.ToLower(funcStr))
-	for _, b := range builtins {
-		typs := b.Types.Types()
-		if len(typs) != len(inputTypes) {
-			continue
-		}
-		match := true
-		for i, t := range typs {
-			if !datumTypes[i].Equivalent(t) {
-				if props.NullableArgs && datumTypes[i].IsAmbiguous() {
-					continue
-				}
-				match = false
-				break
-			}
-		}
-		if match {
-			// Found!
-			constructAgg := func(evalCtx *tree.EvalContext) tree.WindowFunc {
-				return b.WindowFunc(datumTypes, evalCtx)
-			}
-			return constructAgg, b.FixedReturnType(), nil
-		}
-	}
-	return nil, nil, errors.Errorf(
-		"no builtin aggregate/window function for %s on %v", funcStr, inputTypes,
-	)
-}
-
 // windowerState represents the state of the processor.
 type windowerState int
 
@@ -181,7 +120,7 @@ func newWindower(
 		for i, argIdx := range windowFn.ArgsIdxs {
 			argTypes[i] = w.inputTypes[argIdx]
 		}
-		windowConstructor, outputType, err := GetWindowFunctionInfo(windowFn.Func, argTypes...)
+		windowConstructor, outputType, err := execinfrapb.GetWindowFunctionInfo(windowFn.Func, argTypes...)
 		if err != nil {
 			return nil, err
 		}
@@ -504,80 +443,11 @@ func (w *windower) processPartition(
 		}
 
 		if windowFn.frame != nil {
-			var err error
-			if frameRun.Frame, err = windowFn.frame.ConvertToAST(); err != nil {
+			if err := windowFn.frame.InitWindowFrameRun(
+				frameRun, windowFn.ordering, w.inputTypes, &w.datumAlloc,
+			); err != nil {
 				return err
 			}
-			startBound, endBound := windowFn.frame.Bounds.Start, windowFn.frame.Bounds.End
-			if startBound.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING ||
-				startBound.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING {
-				switch windowFn.frame.Mode {
-				case execinfrapb.WindowerSpec_Frame_ROWS:
-					frameRun.StartBoundOffset = tree.NewDInt(tree.DInt(int(startBound.IntOffset)))
-				case execinfrapb.WindowerSpec_Frame_RANGE:
-					datum, rem, err := sqlbase.DecodeTableValue(&w.datumAlloc, &startBound.OffsetType.Type, startBound.TypedOffset)
-					if err != nil {
-						return errors.NewAssertionErrorWithWrappedErrf(err,
-							"error decoding %d bytes", errors.Safe(len(startBound.TypedOffset)))
-					}
-					if len(rem) != 0 {
-						return errors.AssertionFailedf(
-							"%d trailing bytes in encoded value", errors.Safe(len(rem)))
-					}
-					frameRun.StartBoundOffset = datum
-				case execinfrapb.WindowerSpec_Frame_GROUPS:
-					frameRun.StartBoundOffset = tree.NewDInt(tree.DInt(int(startBound.IntOffset)))
-				default:
-					return errors.AssertionFailedf(
-						"unexpected WindowFrameMode: %d", errors.Safe(windowFn.frame.Mode))
-				}
-			}
-			if endBound != nil {
-				if endBound.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING ||
-					endBound.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING {
-					switch windowFn.frame.Mode {
-					case execinfrapb.WindowerSpec_Frame_ROWS:
-						frameRun.EndBoundOffset = tree.NewDInt(tree.DInt(int(endBound.IntOffset)))
-					case execinfrapb.WindowerSpec_Frame_RANGE:
-						datum, rem, err := sqlbase.DecodeTableValue(&w.datumAlloc, &endBound.OffsetType.Type, endBound.TypedOffset)
-						if err != nil {
-							return errors.NewAssertionErrorWithWrappedErrf(err,
-								"error decoding %d bytes", errors.Safe(len(endBound.TypedOffset)))
-						}
-						if len(rem) != 0 {
-							return errors.AssertionFailedf(
-								"%d trailing bytes in encoded value", errors.Safe(len(rem)))
-						}
-						frameRun.EndBoundOffset = datum
-					case execinfrapb.WindowerSpec_Frame_GROUPS:
-						frameRun.EndBoundOffset = tree.NewDInt(tree.DInt(int(endBound.IntOffset)))
-					default:
-						return errors.AssertionFailedf("unexpected WindowFrameMode: %d",
-							errors.Safe(windowFn.frame.Mode))
-					}
-				}
-			}
-			if frameRun.RangeModeWithOffsets() {
-				ordCol := windowFn.ordering.Columns[0]
-				frameRun.OrdColIdx = int(ordCol.ColIdx)
-				// We need this +1 because encoding.Direction has extra value "_"
-				// as zeroth "entry" which its proto equivalent doesn't have.
-				frameRun.OrdDirection = encoding.Direction(ordCol.Direction + 1)
-
-				colTyp := &w.inputTypes[ordCol.ColIdx]
-				// Type of offset depends on the ordering column's type.
-				offsetTyp := colTyp
-				if types.IsDateTimeType(colTyp) {
-					// For datetime related ordering columns, offset must be an Interval.
-					offsetTyp = types.Interval
-				}
-				plusOp, minusOp, found := tree.WindowFrameRangeOps{}.LookupImpl(colTyp, offsetTyp)
-				if !found {
-					return pgerror.Newf(pgcode.Windowing,
-						"given logical offset cannot be combined with ordering column")
-				}
-				frameRun.PlusOp, frameRun.MinusOp = plusOp, minusOp
-			}
 		}
 
 		builtin := w.builtins[windowFnIdx]
@@ -544,9 +544,9 @@ func (z *zigzagJoiner) extractEqDatums(row sqlbase.EncDatumRow, side int) sqlbas
 func (z *zigzagJoiner) produceInvertedIndexKey(
 	info *zigzagJoinerInfo, datums sqlbase.EncDatumRow,
 ) (roachpb.Span, error) {
-	// For inverted indexes, the JSON field (first column in the index) is
+	// For inverted indexes, the inverted column (first column in the index) is
 	// encoded a little differently. We need to explicitly call
-	// EncodeInvertedIndexKeys to generate the prefix. The rest of the
+	// EncodeInvertedIndexSpanKey to generate the prefix. The rest of the
 	// index key containing the remaining neededDatums can be generated
 	// and appended using EncodeColumns.
 	colMap := make(map[sqlbase.ColumnID]int)
@@ -569,27 +569,18 @@ func (z *zigzagJoiner) produceInvertedIndexKey(
 		}
 	}
 
-	keys, err := sqlbase.EncodeInvertedIndexKeys(
-		info.table,
-		info.index,
-		colMap,
-		decodedDatums,
-		info.prefix,
-	)
+	invertedKey, err := sqlbase.EncodeInvertedIndexSpanKey(decodedDatums[0], info.prefix)
 	if err != nil {
 		return roachpb.Span{}, err
 	}
-	if len(keys) != 1 {
-		return roachpb.Span{}, errors.Errorf("%d fixed values passed in for inverted index", len(keys))
-	}
 
-	// Append remaining (non-JSON) datums to the key.
+	// Append remaining (non-inverted) datums to the key.
 	keyBytes, _, err := sqlbase.EncodeColumns(
 		info.index.ExtraColumnIDs[:len(datums)-1],
 		info.indexDirs[1:],
 		colMap,
 		decodedDatums,
-		keys[0],
+		invertedKey,
 	)
 	key := roachpb.Key(keyBytes)
 	return roachpb.Span{Key: key, EndKey: key.PrefixEnd()}, err
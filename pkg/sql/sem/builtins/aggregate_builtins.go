@@ -192,6 +192,39 @@ var aggregates = map[string]builtinDefinition{
 				"Identifies the minimum selected value.")
 		}),
 
+	// The ordered-set aggregates below are called with a WITHIN GROUP clause,
+	// e.g. percentile_disc(0.5) WITHIN GROUP (ORDER BY x). They are planned
+	// with the sorted expression as their first argument, followed by the
+	// direct arguments, and receive their input in sorted order.
+	"mode": collectOverloads(aggProps(), types.Scalar,
+		func(t *types.T) tree.Overload {
+			return makeAggOverload([]*types.T{t}, t, newModeAggregate,
+				"Returns the most frequent value in the ordering of WITHIN GROUP. If several "+
+					"values are equally frequent, the first one in the ordering is returned.")
+		}),
+
+	"percentile_disc": makeBuiltin(aggProps(), makePercentileOverloads(
+		types.Scalar,
+		func(t *types.T) *types.T { return t },
+		newPercentileDiscAggregate,
+		"Returns the first value in the ordering of WITHIN GROUP whose position in the "+
+			"ordering equals or exceeds the given fraction.",
+		"Returns, for each of the given fractions, the first value in the ordering of "+
+			"WITHIN GROUP whose position in the ordering equals or exceeds the fraction.",
+	)...),
+
+	// Like in Postgres, integer and decimal input values are interpolated as
+	// floats.
+	"percentile_cont": makeBuiltin(aggProps(), makePercentileOverloads(
+		[]*types.T{types.Float, types.Interval, types.Int, types.Decimal},
+		PercentileContReturnType,
+		newPercentileContAggregate,
+		"Returns the value corresponding to the given fraction in the ordering of WITHIN "+
+			"GROUP, interpolating between adjacent values if needed.",
+		"Returns, for each of the given fractions, the value corresponding to the fraction "+
+			"in the ordering of WITHIN GROUP, interpolating between adjacent values if needed.",
+	)...),
+
 	"string_agg": makeBuiltin(aggPropsNullableArgs(),
 		makeAggOverload([]*types.T{types.String, types.String}, types.String, newStringConcatAggregate,
 			"Concatenates all selected values using the provided delimiter."),
@@ -392,6 +425,40 @@ func makeAggOverloadWithReturnType(
 	}
 }
 
+// makePercentileOverloads returns the overloads of an ordered-set percentile
+// aggregate over each of the given types. Each type has an overload which
+// takes a single fraction and returns a value of type retType(t), and one
+// which takes an array of fractions and returns an array of values.
+func makePercentileOverloads(
+	typs []*types.T,
+	retType func(*types.T) *types.T,
+	f func([]*types.T, *tree.EvalContext, tree.Datums) tree.AggregateFunc,
+	info, arrayInfo string,
+) []tree.Overload {
+	fractionArray := types.MakeArray(types.Float)
+	overloads := make([]tree.Overload, 0, 2*len(typs))
+	for _, t := range typs {
+		ret := retType(t)
+		overloads = append(overloads, makeAggOverload([]*types.T{t, types.Float}, ret, f, info))
+		if ok, _ := types.IsValidArrayElementType(ret); ok {
+			overloads = append(overloads,
+				makeAggOverload([]*types.T{t, fractionArray}, types.MakeArray(ret), f, arrayInfo))
+		}
+	}
+	return overloads
+}
+
+// PercentileContReturnType returns the type of the values computed by
+// percentile_cont for input values of the given type.
+func PercentileContReturnType(t *types.T) *types.T {
+	switch t.Family() {
+	case types.IntFamily, types.DecimalFamily:
+		return types.Float
+	default:
+		return t
+	}
+}
+
 var _ tree.AggregateFunc = &arrayAggregate{}
 var _ tree.AggregateFunc = &avgAggregate{}
 var _ tree.AggregateFunc = &countAggregate{}
@@ -418,6 +485,8 @@ var _ tree.AggregateFunc = &boolAndAggregate{}
 var _ tree.AggregateFunc = &boolOrAggregate{}
 var _ tree.AggregateFunc = &bytesXorAggregate{}
 var _ tree.AggregateFunc = &intXorAggregate{}
+var _ tree.AggregateFunc = &percentileAggregate{}
+var _ tree.AggregateFunc = &modeAggregate{}
 var _ tree.AggregateFunc = &jsonAggregate{}
 var _ tree.AggregateFunc = &bitAndAggregate{}
 var _ tree.AggregateFunc = &bitOrAggregate{}
@@ -451,6 +520,8 @@ const sizeOfIntXorAggregate = int64(unsafe.Sizeof(intXorAggregate{}))
 const sizeOfJSONAggregate = int64(unsafe.Sizeof(jsonAggregate{}))
 const sizeOfBitAndAggregate = int64(unsafe.Sizeof(bitAndAggregate{}))
 const sizeOfBitOrAggregate = int64(unsafe.Sizeof(bitOrAggregate{}))
+const sizeOfPercentileAggregate = int64(unsafe.Sizeof(percentileAggregate{}))
+const sizeOfModeAggregate = int64(unsafe.Sizeof(modeAggregate{}))
 
 // See NewAnyNotNullAggregate.
 type anyNotNullAggregate struct {
@@ -2235,3 +2306,227 @@ func (a *jsonAggregate) Close(ctx context.Context) {
 func (a *jsonAggregate) Size() int64 {
 	return sizeOfJSONAggregate
 }
+
+// percentileAggregate implements the percentile_disc and percentile_cont
+// ordered-set aggregates. It accumulates the non-NULL input values, which are
+// received in the order of the WITHIN GROUP clause, and picks the values at
+// the requested fractions of that ordering.
+type percentileAggregate struct {
+	typ *types.T
+	// cont is true for percentile_cont, which interpolates between adjacent
+	// values.
+	cont bool
+	// fraction is a DFloat or an array of DFloats. It is passed to the
+	// constructor for aggregations, and to Add for window functions.
+	fraction tree.Datum
+	values   tree.Datums
+	acc      mon.BoundAccount
+}
+
+func newPercentileDiscAggregate(
+	params []*types.T, evalCtx *tree.EvalContext, arguments tree.Datums,
+) tree.AggregateFunc {
+	return newPercentileAggregate(params, evalCtx, arguments, false /* cont */)
+}
+
+func newPercentileContAggregate(
+	params []*types.T, evalCtx *tree.EvalContext, arguments tree.Datums,
+) tree.AggregateFunc {
+	return newPercentileAggregate(params, evalCtx, arguments, true /* cont */)
+}
+
+func newPercentileAggregate(
+	params []*types.T, evalCtx *tree.EvalContext, arguments tree.Datums, cont bool,
+) tree.AggregateFunc {
+	typ := params[0]
+	if cont {
+		typ = PercentileContReturnType(typ)
+	}
+	a := &percentileAggregate{
+		typ:  typ,
+		cont: cont,
+		acc:  evalCtx.Mon.MakeBoundAccount(),
+	}
+	if len(arguments) == 1 {
+		a.fraction = arguments[0]
+	} else if len(arguments) > 1 {
+		panic(fmt.Sprintf("too many arguments passed in, expected < 2, got %d", len(arguments)))
+	}
+	return a
+}
+
+// Add accumulates the passed datum.
+func (a *percentileAggregate) Add(
+	ctx context.Context, datum tree.Datum, others ...tree.Datum,
+) error {
+	// If this is called as part of a window function, the fraction is passed
+	// in via the first element in others.
+	if len(others) == 1 {
+		a.fraction = others[0]
+	} else if len(others) > 1 {
+		panic(fmt.Sprintf("too many other datums passed in, expected < 2, got %d", len(others)))
+	}
+	if datum == tree.DNull {
+		return nil
+	}
+	if a.cont {
+		// Integer and decimal values are interpolated as floats.
+		switch t := datum.(type) {
+		case *tree.DInt:
+			datum = tree.NewDFloat(tree.DFloat(*t))
+		case *tree.DDecimal:
+			f, err := t.Float64()
+			if err != nil {
+				return err
+			}
+			datum = tree.NewDFloat(tree.DFloat(f))
+		}
+	}
+	if err := a.acc.Grow(ctx, int64(datum.Size())); err != nil {
+		return err
+	}
+	a.values = append(a.values, datum)
+	return nil
+}
+
+// Result returns the value at the fraction, or an array of the values at each
+// fraction.
+func (a *percentileAggregate) Result() (tree.Datum, error) {
+	if len(a.values) == 0 || a.fraction == nil || a.fraction == tree.DNull {
+		return tree.DNull, nil
+	}
+	fractions, ok := a.fraction.(*tree.DArray)
+	if !ok {
+		return a.valueAt(float64(tree.MustBeDFloat(a.fraction)))
+	}
+	res := tree.NewDArray(a.typ)
+	for _, f := range fractions.Array {
+		v := tree.Datum(tree.DNull)
+		if f != tree.DNull {
+			var err error
+			if v, err = a.valueAt(float64(tree.MustBeDFloat(f))); err != nil {
+				return nil, err
+			}
+		}
+		if err := res.Append(v); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// valueAt returns the value at the given fraction of the accumulated values.
+func (a *percentileAggregate) valueAt(fraction float64) (tree.Datum, error) {
+	if !(fraction >= 0 && fraction <= 1) {
+		return nil, pgerror.Newf(pgcode.NumericValueOutOfRange,
+			"percentile value %g is not between 0 and 1", fraction)
+	}
+	n := len(a.values)
+	if !a.cont {
+		// Pick the first value whose position (starting at 1) is at least
+		// fraction*n.
+		i := int(math.Ceil(fraction*float64(n))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return a.values[i], nil
+	}
+
+	// Interpolate between the values surrounding position fraction*(n-1)
+	// (starting at 0).
+	pos := fraction * float64(n-1)
+	lo, hi := int(math.Floor(pos)), int(math.Ceil(pos))
+	if lo == hi {
+		return a.values[lo], nil
+	}
+	proportion := pos - float64(lo)
+	switch t := a.values[lo].(type) {
+	case *tree.DFloat:
+		l, h := float64(*t), float64(tree.MustBeDFloat(a.values[hi]))
+		return tree.NewDFloat(tree.DFloat(l + proportion*(h-l))), nil
+	case *tree.DInterval:
+		h := a.values[hi].(*tree.DInterval)
+		diff := h.Duration.Sub(t.Duration).MulFloat(proportion)
+		return &tree.DInterval{Duration: t.Duration.Add(diff)}, nil
+	default:
+		return nil, errors.AssertionFailedf("unexpected type %s for percentile_cont", a.typ)
+	}
+}
+
+// Reset implements tree.AggregateFunc interface.
+func (a *percentileAggregate) Reset(ctx context.Context) {
+	a.values = nil
+	a.acc.Empty(ctx)
+}
+
+// Close allows the aggregate to release the memory it requested during
+// operation.
+func (a *percentileAggregate) Close(ctx context.Context) {
+	a.acc.Close(ctx)
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *percentileAggregate) Size() int64 {
+	return sizeOfPercentileAggregate
+}
+
+// modeAggregate implements the mode ordered-set aggregate. Since its input
+// values are received in sorted order, equal values are adjacent, and only
+// the current run of equal values and the longest run so far need to be
+// tracked.
+type modeAggregate struct {
+	evalCtx   *tree.EvalContext
+	mode      tree.Datum
+	modeCount int
+	cur       tree.Datum
+	curCount  int
+}
+
+func newModeAggregate(
+	_ []*types.T, evalCtx *tree.EvalContext, _ tree.Datums,
+) tree.AggregateFunc {
+	return &modeAggregate{evalCtx: evalCtx}
+}
+
+// Add accumulates the passed datum.
+func (a *modeAggregate) Add(_ context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	if a.cur != nil && a.cur.Compare(a.evalCtx, datum) == 0 {
+		a.curCount++
+		return nil
+	}
+	// Only a strictly longer run replaces the mode, so that the first value in
+	// the ordering wins ties.
+	if a.curCount > a.modeCount {
+		a.mode, a.modeCount = a.cur, a.curCount
+	}
+	a.cur, a.curCount = datum, 1
+	return nil
+}
+
+// Result returns the most frequent value.
+func (a *modeAggregate) Result() (tree.Datum, error) {
+	if a.cur == nil {
+		return tree.DNull, nil
+	}
+	if a.curCount > a.modeCount {
+		return a.cur, nil
+	}
+	return a.mode, nil
+}
+
+// Reset implements tree.AggregateFunc interface.
+func (a *modeAggregate) Reset(context.Context) {
+	a.mode, a.modeCount = nil, 0
+	a.cur, a.curCount = nil, 0
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *modeAggregate) Close(context.Context) {}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *modeAggregate) Size() int64 {
+	return sizeOfModeAggregate
+}
@@ -46,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/geo/geoindex"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
@@ -53,6 +54,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/knz/strtime"
@@ -77,17 +80,20 @@ const maxAllocatedStringSize = 128 * 1024 * 1024
 const errInsufficientArgsFmtString = "unknown signature: %s()"
 
 const (
-	categoryComparison    = "Comparison"
-	categoryCompatibility = "Compatibility"
-	categoryDateAndTime   = "Date and time"
-	categoryIDGeneration  = "ID generation"
-	categorySequences     = "Sequence"
-	categoryMath          = "Math and numeric"
-	categoryString        = "String and byte"
-	categoryArray         = "Array"
-	categorySystemInfo    = "System info"
-	categoryGenerator     = "Set-returning"
-	categoryJSON          = "JSONB"
+	categoryComparison     = "Comparison"
+	categoryCompatibility  = "Compatibility"
+	categoryDateAndTime    = "Date and time"
+	categoryIDGeneration   = "ID generation"
+	categorySequences      = "Sequence"
+	categoryMath           = "Math and numeric"
+	categoryString         = "String and byte"
+	categoryArray          = "Array"
+	categorySystemInfo     = "System info"
+	categoryGenerator      = "Set-returning"
+	categoryJSON           = "JSONB"
+	categoryFullTextSearch = "Full text search"
+	categoryTrigram        = "Trigram"
+	categorySpatial        = "Spatial"
 )
 
 func categorizeType(t *types.T) string {
@@ -1566,6 +1572,27 @@ CockroachDB supports the following flags:
 		},
 	),
 
+	// grouping is replaced by the optimizer in the SELECT list, HAVING and
+	// ORDER BY clauses of a query with GROUP BY, so evaluating it directly is
+	// always an error.
+	"grouping": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categoryCompatibility,
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types:      tree.VariadicType{VarType: types.Any},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				return nil, pgerror.New(pgcode.Grouping,
+					"grouping() can only be used in a query with GROUP BY")
+			},
+			Info: "Returns a bit mask indicating which of the arguments are not included " +
+				"in the grouping set of the current row. The least significant bit " +
+				"corresponds to the last argument. The arguments must be GROUP BY expressions.",
+		},
+	),
+
 	// Timestamp/Date functions.
 
 	"experimental_strftime": makeBuiltin(
@@ -2763,6 +2790,188 @@ may increase either contention or retry errors, or both.`,
 
 	"jsonb_array_length": makeBuiltin(jsonProps(), jsonArrayLengthImpl),
 
+	// Full text search functions.
+	// https://www.postgresql.org/docs/10/static/functions-textsearch.html
+
+	"to_tsvector": makeBuiltin(tsearchProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"document", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return toTSVector(tsearch.DefaultConfig, string(tree.MustBeDString(args[0])))
+			},
+			Info: "Converts a document to a tsvector of lexemes and their positions, " +
+				"using the default text search configuration.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"document", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return toTSVector(string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1])))
+			},
+			Info: "Converts a document to a tsvector of lexemes and their positions, " +
+				"using the given text search configuration.",
+		},
+	),
+
+	"to_tsquery": makeBuiltin(tsearchProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return toTSQuery(tsearch.DefaultConfig, string(tree.MustBeDString(args[0])))
+			},
+			Info: "Converts a search query with the tsquery syntax to a tsquery of normalized " +
+				"lexemes, using the default text search configuration.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"query", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return toTSQuery(string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1])))
+			},
+			Info: "Converts a search query with the tsquery syntax to a tsquery of normalized " +
+				"lexemes, using the given text search configuration.",
+		},
+	),
+
+	"plainto_tsquery": makeBuiltin(tsearchProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"text", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return plainToTSQuery(tsearch.DefaultConfig, string(tree.MustBeDString(args[0])))
+			},
+			Info: "Converts plain text to a tsquery that matches the documents containing all " +
+				"its words, using the default text search configuration.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"text", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return plainToTSQuery(string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1])))
+			},
+			Info: "Converts plain text to a tsquery that matches the documents containing all " +
+				"its words, using the given text search configuration.",
+		},
+	),
+
+	"ts_rank": makeBuiltin(tsearchProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(tsearch.DefaultRankWeights, args[0], args[1], 0 /* normalization */), nil
+			},
+			Info: "Ranks how relevant a document is to a query.",
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"vector", types.TSVector}, {"query", types.TSQuery}, {"normalization", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(tsearch.DefaultRankWeights, args[0], args[1], int(tree.MustBeDInt(args[2]))), nil
+			},
+			Info: "Ranks how relevant a document is to a query. The normalization flags " +
+				"determine how the rank is normalized by the document length.",
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"weights", types.FloatArray}, {"vector", types.TSVector}, {"query", types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				weights, err := tsRankWeights(args[0])
+				if err != nil {
+					return nil, err
+				}
+				return tsRank(weights, args[1], args[2], 0 /* normalization */), nil
+			},
+			Info: "Ranks how relevant a document is to a query, using the given weights " +
+				"for the D, C, B and A weight classes.",
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"weights", types.FloatArray}, {"vector", types.TSVector}, {"query", types.TSQuery},
+				{"normalization", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				weights, err := tsRankWeights(args[0])
+				if err != nil {
+					return nil, err
+				}
+				return tsRank(weights, args[1], args[2], int(tree.MustBeDInt(args[3]))), nil
+			},
+			Info: "Ranks how relevant a document is to a query, using the given weights " +
+				"for the D, C, B and A weight classes. The normalization flags determine " +
+				"how the rank is normalized by the document length.",
+		},
+	),
+
+	"setweight": makeBuiltin(tsearchProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"weight", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				w, err := tsearch.ParseWeight(string(tree.MustBeDString(args[1])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSVector(tree.MustBeDTSVector(args[0]).SetWeight(w)), nil
+			},
+			Info: "Sets the weight (A, B, C or D) of all the positions of a tsvector.",
+		},
+	),
+
+	// Trigram functions.
+	// https://www.postgresql.org/docs/current/pgtrgm.html
+
+	"similarity": makeBuiltin(trigramProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"left", types.String}, {"right", types.String}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				sim := trigram.Similarity(string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1])))
+				return tree.NewDFloat(tree.DFloat(sim)), nil
+			},
+			Info: "Returns a number between 0 and 1 that indicates how similar the two strings " +
+				"are, based on the number of trigrams they share.",
+		},
+	),
+
+	"word_similarity": makeBuiltin(trigramProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"left", types.String}, {"right", types.String}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				sim := trigram.WordSimilarity(string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1])))
+				return tree.NewDFloat(tree.DFloat(sim)), nil
+			},
+			Info: "Returns a number between 0 and 1 that indicates the greatest similarity " +
+				"between the trigrams of `left` and any continuous extent of the ordered " +
+				"trigrams of `right`.",
+		},
+	),
+
+	"show_trgm": makeBuiltin(trigramProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.String}},
+			ReturnType: tree.FixedReturnType(types.StringArray),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arr := tree.NewDArray(types.String)
+				for _, t := range trigram.MakeTrigrams(string(tree.MustBeDString(args[0]))) {
+					if err := arr.Append(tree.NewDString(t)); err != nil {
+						return nil, err
+					}
+				}
+				return arr, nil
+			},
+			Info: "Returns the sorted trigrams of the words of a string.",
+		},
+	),
+
 	// Metadata functions.
 
 	// https://www.postgresql.org/docs/10/static/functions-info.html
@@ -3273,7 +3482,67 @@ may increase either contention or retry errors, or both.`,
 		},
 	),
 
-	// Returns the number of distinct inverted index entries that would be generated for a JSON value.
+	"crdb_internal.request_statement_bundle": makeBuiltin(
+		tree.FunctionProperties{
+			Category: categorySystemInfo,
+			Impure:   true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"stmt_fingerprint", types.String}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if err := checkPrivilegedUser(ctx); err != nil {
+					return nil, err
+				}
+				if ctx.StmtDiagnosticsRequestInserter == nil {
+					return nil, errors.AssertionFailedf("statement diagnostics requests are not supported")
+				}
+				fingerprint := string(tree.MustBeDString(args[0]))
+				if err := ctx.StmtDiagnosticsRequestInserter(ctx.Context, fingerprint); err != nil {
+					return nil, err
+				}
+				return tree.DBoolTrue, nil
+			},
+			Info: "Requests a statement diagnostics bundle to be collected for the next " +
+				"execution of a statement with the given fingerprint (in the form shown by " +
+				"the statements page of the Admin UI, with constants replaced by `_`). " +
+				"The bundle can be downloaded from the Admin UI once it is collected.",
+		},
+	),
+
+	"crdb_internal.set_statement_hints": makeBuiltin(
+		tree.FunctionProperties{
+			Category: categorySystemInfo,
+			Impure:   true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"stmt_fingerprint", types.String}, {"hints", types.String}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if err := checkPrivilegedUser(ctx); err != nil {
+					return nil, err
+				}
+				if ctx.StmtHintsSetter == nil {
+					return nil, errors.AssertionFailedf("statement hints are not supported")
+				}
+				fingerprint := string(tree.MustBeDString(args[0]))
+				hints := string(tree.MustBeDString(args[1]))
+				if err := ctx.StmtHintsSetter(ctx.Context, fingerprint, hints); err != nil {
+					return nil, err
+				}
+				return tree.DBoolTrue, nil
+			},
+			Info: "Attaches plan hints to a statement fingerprint (in the form shown by the " +
+				"statements page of the Admin UI, with constants replaced by `_`); the hints " +
+				"replace any existing hints for the fingerprint and are applied by the optimizer " +
+				"when planning matching statements. Supported hints are `INDEX(<table>@<index>)`, " +
+				"`NO_INDEX_JOIN(<table>)` and `NO_RULE(<exploration rule>)`. " +
+				"Empty hints remove the hints for the fingerprint.",
+		},
+	),
+
+	// Returns the number of distinct inverted index entries that would be
+	// generated for a JSON or tsvector value.
 	"crdb_internal.json_num_index_entries": makeBuiltin(
 		tree.FunctionProperties{
 			Category:     categorySystemInfo,
@@ -3295,6 +3564,57 @@ may increase either contention or retry errors, or both.`,
 			},
 			Info: "This function is used only by CockroachDB's developers for testing purposes.",
 		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arg := args[0]
+				if arg == tree.DNull {
+					return tree.NewDInt(tree.DInt(1)), nil
+				}
+				return tree.NewDInt(tree.DInt(len(tree.MustBeDTSVector(arg).TSVector))), nil
+			},
+			Info: "This function is used only by CockroachDB's developers for testing purposes.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.String}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arg := args[0]
+				if arg == tree.DNull {
+					return tree.NewDInt(tree.DInt(1)), nil
+				}
+				n := len(trigram.MakeTrigrams(string(tree.MustBeDString(arg))))
+				return tree.NewDInt(tree.DInt(n)), nil
+			},
+			Info: "This function is used only by CockroachDB's developers for testing purposes.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.Geometry}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arg := args[0]
+				if arg == tree.DNull {
+					return tree.NewDInt(tree.DInt(1)), nil
+				}
+				n := len(geoindex.GeometryCovering(tree.MustBeDGeometry(arg).Geometry))
+				return tree.NewDInt(tree.DInt(n)), nil
+			},
+			Info: "This function is used only by CockroachDB's developers for testing purposes.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.Geography}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arg := args[0]
+				if arg == tree.DNull {
+					return tree.NewDInt(tree.DInt(1)), nil
+				}
+				n := len(geoindex.GeographyCovering(tree.MustBeDGeography(arg).Geography))
+				return tree.NewDInt(tree.DInt(n)), nil
+			},
+			Info: "This function is used only by CockroachDB's developers for testing purposes.",
+		},
 	),
 
 	"crdb_internal.round_decimal_values": makeBuiltin(
@@ -3814,6 +4134,77 @@ func jsonPropsNullableArgs() tree.FunctionProperties {
 	return d
 }
 
+func tsearchProps() tree.FunctionProperties {
+	return tree.FunctionProperties{
+		Category: categoryFullTextSearch,
+	}
+}
+
+func trigramProps() tree.FunctionProperties {
+	return tree.FunctionProperties{
+		Category: categoryTrigram,
+	}
+}
+
+func toTSVector(config, document string) (tree.Datum, error) {
+	cfg, err := tsearch.GetConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDTSVector(cfg.ToTSVector(document)), nil
+}
+
+func toTSQuery(config, query string) (tree.Datum, error) {
+	cfg, err := tsearch.GetConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	q, err := cfg.ToTSQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDTSQuery(q), nil
+}
+
+func plainToTSQuery(config, text string) (tree.Datum, error) {
+	cfg, err := tsearch.GetConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDTSQuery(cfg.PlainToTSQuery(text)), nil
+}
+
+func tsRank(weights [4]float64, vector, query tree.Datum, normalization int) tree.Datum {
+	v := tree.MustBeDTSVector(vector).TSVector
+	q := tree.MustBeDTSQuery(query).TSQuery
+	return tree.NewDFloat(tree.DFloat(tsearch.Rank(weights, v, q, normalization)))
+}
+
+// tsRankWeights converts the weights argument of ts_rank, an array of the
+// weights of the D, C, B and A weight classes, to the form used by
+// tsearch.Rank.
+func tsRankWeights(arg tree.Datum) ([4]float64, error) {
+	var weights [4]float64
+	arr := tree.MustBeDArray(arg)
+	if arr.Len() < len(weights) {
+		return weights, pgerror.New(pgcode.ArraySubscript, "array of weight is too short")
+	}
+	for i, d := range arr.Array[:len(weights)] {
+		if d == tree.DNull {
+			return weights, pgerror.New(pgcode.NullValueNotAllowed, "array of weight must not contain nulls")
+		}
+		w := float64(*d.(*tree.DFloat))
+		if w > 1 {
+			return weights, pgerror.New(pgcode.InvalidParameterValue, "weight out of range")
+		}
+		if w < 0 {
+			w = tsearch.DefaultRankWeights[i]
+		}
+		weights[i] = w
+	}
+	return weights, nil
+}
+
 var jsonBuildObjectImpl = tree.Overload{
 	Types:      tree.VariadicType{VarType: types.Any},
 	ReturnType: tree.FixedReturnType(types.Jsonb),
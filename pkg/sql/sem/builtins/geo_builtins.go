@@ -0,0 +1,493 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/geo"
+)
+
+// defaultGeoJSONDecimalDigits is the default maximum number of decimal digits
+// of the coordinates returned by st_asgeojson.
+const defaultGeoJSONDecimalDigits = 9
+
+func initGeoBuiltins() {
+	for k, v := range geoBuiltins {
+		if _, exists := builtins[k]; exists {
+			panic("duplicate builtin: " + k)
+		}
+		v.props.Category = categorySpatial
+		builtins[k] = v
+	}
+}
+
+var geoBuiltins = map[string]builtinDefinition{
+	// Constructors.
+
+	"st_geomfromtext": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"str", types.String}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.ParseDGeometry(string(tree.MustBeDString(args[0])))
+			},
+			Info: "Returns the geometry of a WKT or EWKT representation.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"str", types.String}, {"srid", types.Int}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				g, err := tree.ParseDGeometry(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return setGeometrySRID(g.Geometry, args[1])
+			},
+			Info: "Returns the geometry of a WKT representation, with the given SRID.",
+		},
+	),
+
+	"st_geogfromtext": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"str", types.String}},
+			ReturnType: tree.FixedReturnType(types.Geography),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.ParseDGeography(string(tree.MustBeDString(args[0])))
+			},
+			Info: "Returns the geography of a WKT or EWKT representation.",
+		},
+	),
+
+	"st_geomfromgeojson": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"str", types.String}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				g, err := geo.GeometryFromGeoJSON([]byte(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDGeometry(g), nil
+			},
+			Info: "Returns the geometry of a GeoJSON representation, with SRID 4326.",
+		},
+	),
+
+	"st_geogfromgeojson": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"str", types.String}},
+			ReturnType: tree.FixedReturnType(types.Geography),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				g, err := geo.GeographyFromGeoJSON([]byte(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDGeography(g), nil
+			},
+			Info: "Returns the geography of a GeoJSON representation.",
+		},
+	),
+
+	"st_geomfromwkb": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"bytes", types.Bytes}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				g, err := geo.GeometryFromEWKB([]byte(tree.MustBeDBytes(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDGeometry(g), nil
+			},
+			Info: "Returns the geometry of a WKB or EWKB representation.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"bytes", types.Bytes}, {"srid", types.Int}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				g, err := geo.GeometryFromEWKB([]byte(tree.MustBeDBytes(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return setGeometrySRID(g, args[1])
+			},
+			Info: "Returns the geometry of a WKB representation, with the given SRID.",
+		},
+	),
+
+	"st_geogfromwkb": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"bytes", types.Bytes}},
+			ReturnType: tree.FixedReturnType(types.Geography),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				g, err := geo.GeographyFromEWKB([]byte(tree.MustBeDBytes(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDGeography(g), nil
+			},
+			Info: "Returns the geography of a WKB or EWKB representation.",
+		},
+	),
+
+	"st_makepoint": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"x", types.Float}, {"y", types.Float}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c := geo.Coord{
+					X: float64(*args[0].(*tree.DFloat)),
+					Y: float64(*args[1].(*tree.DFloat)),
+				}
+				g, err := geo.MakeGeometry(0, geo.Shape{Type: geo.Point, Parts: [][][]geo.Coord{{{c}}}})
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDGeometry(g), nil
+			},
+			Info: "Returns a point geometry with the given coordinates, and an unknown SRID.",
+		},
+	),
+
+	// Representations.
+
+	"st_astext": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry", types.Geometry}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDString(tree.MustBeDGeometry(args[0]).WKT()), nil
+			},
+			Info: "Returns the WKT representation of a geometry.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geography", types.Geography}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDString(tree.MustBeDGeography(args[0]).WKT()), nil
+			},
+			Info: "Returns the WKT representation of a geography.",
+		},
+	),
+
+	"st_asewkt": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry", types.Geometry}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDString(tree.MustBeDGeometry(args[0]).EWKT()), nil
+			},
+			Info: "Returns the EWKT representation of a geometry, which includes its SRID.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geography", types.Geography}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDString(tree.MustBeDGeography(args[0]).EWKT()), nil
+			},
+			Info: "Returns the EWKT representation of a geography, which includes its SRID.",
+		},
+	),
+
+	"st_asbinary": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry", types.Geometry}},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDBytes(tree.DBytes(tree.MustBeDGeometry(args[0]).WKB())), nil
+			},
+			Info: "Returns the WKB representation of a geometry.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geography", types.Geography}},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDBytes(tree.DBytes(tree.MustBeDGeography(args[0]).WKB())), nil
+			},
+			Info: "Returns the WKB representation of a geography.",
+		},
+	),
+
+	"st_asgeojson": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry", types.Geometry}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				g := tree.MustBeDGeometry(args[0])
+				return tree.NewDString(g.GeoJSON(defaultGeoJSONDecimalDigits)), nil
+			},
+			Info: "Returns the GeoJSON representation of a geometry, with coordinates " +
+				"rounded to 9 decimal digits.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry", types.Geometry}, {"max_decimal_digits", types.Int}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				g := tree.MustBeDGeometry(args[0])
+				digits := int(tree.MustBeDInt(args[1]))
+				if digits < 0 {
+					return nil, pgerror.New(pgcode.InvalidParameterValue,
+						"max_decimal_digits cannot be negative")
+				}
+				return tree.NewDString(g.GeoJSON(digits)), nil
+			},
+			Info: "Returns the GeoJSON representation of a geometry, with coordinates " +
+				"rounded to `max_decimal_digits` decimal digits.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geography", types.Geography}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				g := tree.MustBeDGeography(args[0])
+				return tree.NewDString(g.GeoJSON(defaultGeoJSONDecimalDigits)), nil
+			},
+			Info: "Returns the GeoJSON representation of a geography, with coordinates " +
+				"rounded to 9 decimal digits.",
+		},
+	),
+
+	// Accessors.
+
+	"st_srid": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry", types.Geometry}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDInt(tree.DInt(tree.MustBeDGeometry(args[0]).SRID)), nil
+			},
+			Info: "Returns the spatial reference identifier of a geometry.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geography", types.Geography}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDInt(tree.DInt(tree.MustBeDGeography(args[0]).SRID)), nil
+			},
+			Info: "Returns the spatial reference identifier of a geography.",
+		},
+	),
+
+	"st_setsrid": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry", types.Geometry}, {"srid", types.Int}},
+			ReturnType: tree.FixedReturnType(types.Geometry),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return setGeometrySRID(tree.MustBeDGeometry(args[0]).Geometry, args[1])
+			},
+			Info: "Returns the geometry with its spatial reference identifier replaced by " +
+				"`srid`. The coordinates are not transformed.",
+		},
+	),
+
+	"st_x": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry", types.Geometry}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c, ok, err := pointCoord(tree.MustBeDGeometry(args[0]).Geometry)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					return tree.DNull, nil
+				}
+				return tree.NewDFloat(tree.DFloat(c.X)), nil
+			},
+			Info: "Returns the X coordinate of a point geometry, or NULL if the point is empty.",
+		},
+	),
+
+	"st_y": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry", types.Geometry}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c, ok, err := pointCoord(tree.MustBeDGeometry(args[0]).Geometry)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					return tree.DNull, nil
+				}
+				return tree.NewDFloat(tree.DFloat(c.Y)), nil
+			},
+			Info: "Returns the Y coordinate of a point geometry, or NULL if the point is empty.",
+		},
+	),
+
+	// Measurements.
+
+	"st_distance": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry_a", types.Geometry}, {"geometry_b", types.Geometry}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				a, b := tree.MustBeDGeometry(args[0]), tree.MustBeDGeometry(args[1])
+				d, err := a.Distance(b.Geometry)
+				if err != nil {
+					return nil, err
+				}
+				if math.IsInf(d, 1) {
+					return tree.DNull, nil
+				}
+				return tree.NewDFloat(tree.DFloat(d)), nil
+			},
+			Info: "Returns the shortest distance between two geometries, in the units of " +
+				"their coordinate system, or NULL if either of them is empty.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geography_a", types.Geography}, {"geography_b", types.Geography}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				a, b := tree.MustBeDGeography(args[0]), tree.MustBeDGeography(args[1])
+				d := a.Distance(b.Geography)
+				if math.IsInf(d, 1) {
+					return tree.DNull, nil
+				}
+				return tree.NewDFloat(tree.DFloat(d)), nil
+			},
+			Info: "Returns the shortest distance in meters between two geographies, on a " +
+				"sphere with the mean radius of the Earth, or NULL if either of them is empty.",
+		},
+	),
+
+	// Predicates. These can be accelerated by inverted indexes on the spatial
+	// columns passed as either of their first two arguments.
+
+	"st_dwithin": makeBuiltin(defProps(),
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"geometry_a", types.Geometry},
+				{"geometry_b", types.Geometry},
+				{"distance", types.Float},
+			},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				a, b := tree.MustBeDGeometry(args[0]), tree.MustBeDGeometry(args[1])
+				ok, err := a.DWithin(b.Geometry, float64(*args[2].(*tree.DFloat)))
+				if err != nil {
+					return nil, err
+				}
+				return tree.MakeDBool(tree.DBool(ok)), nil
+			},
+			Info: "Returns true if the distance between two geometries is at most `distance`, " +
+				"in the units of their coordinate system.",
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"geography_a", types.Geography},
+				{"geography_b", types.Geography},
+				{"distance", types.Float},
+			},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				a, b := tree.MustBeDGeography(args[0]), tree.MustBeDGeography(args[1])
+				ok, err := a.DWithin(b.Geography, float64(*args[2].(*tree.DFloat)))
+				if err != nil {
+					return nil, err
+				}
+				return tree.MakeDBool(tree.DBool(ok)), nil
+			},
+			Info: "Returns true if the distance between two geographies is at most `distance` " +
+				"meters.",
+		},
+	),
+
+	"st_intersects": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry_a", types.Geometry}, {"geometry_b", types.Geometry}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				a, b := tree.MustBeDGeometry(args[0]), tree.MustBeDGeometry(args[1])
+				ok, err := a.Intersects(b.Geometry)
+				if err != nil {
+					return nil, err
+				}
+				return tree.MakeDBool(tree.DBool(ok)), nil
+			},
+			Info: "Returns true if two geometries share any point.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geography_a", types.Geography}, {"geography_b", types.Geography}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				a, b := tree.MustBeDGeography(args[0]), tree.MustBeDGeography(args[1])
+				return tree.MakeDBool(tree.DBool(a.Intersects(b.Geography))), nil
+			},
+			Info: "Returns true if two geographies share any point.",
+		},
+	),
+
+	"st_covers": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry_a", types.Geometry}, {"geometry_b", types.Geometry}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				a, b := tree.MustBeDGeometry(args[0]), tree.MustBeDGeometry(args[1])
+				ok, err := a.Covers(b.Geometry)
+				if err != nil {
+					return nil, err
+				}
+				return tree.MakeDBool(tree.DBool(ok)), nil
+			},
+			Info: "Returns true if no point of `geometry_b` is outside of `geometry_a`.",
+		},
+	),
+
+	"st_contains": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"geometry_a", types.Geometry}, {"geometry_b", types.Geometry}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				a, b := tree.MustBeDGeometry(args[0]), tree.MustBeDGeometry(args[1])
+				ok, err := a.Contains(b.Geometry)
+				if err != nil {
+					return nil, err
+				}
+				return tree.MakeDBool(tree.DBool(ok)), nil
+			},
+			Info: "Returns true if no point of `geometry_b` is outside of `geometry_a`, and " +
+				"the interiors of the two geometries intersect.",
+		},
+	),
+}
+
+// setGeometrySRID returns a geometry with the shape of g and the SRID of the
+// given integer datum.
+func setGeometrySRID(g *geo.Geometry, srid tree.Datum) (tree.Datum, error) {
+	s := int64(tree.MustBeDInt(srid))
+	if s < math.MinInt32 || s > math.MaxInt32 {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue, "SRID %d is out of range", s)
+	}
+	res, err := geo.MakeGeometry(int32(s), g.Shape)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDGeometry(res), nil
+}
+
+// pointCoord returns the coordinate of a point geometry, and false if the
+// point is empty.
+func pointCoord(g *geo.Geometry) (geo.Coord, bool, error) {
+	if g.Type != geo.Point {
+		return geo.Coord{}, false, pgerror.Newf(pgcode.InvalidParameterValue,
+			"argument to st_x() and st_y() must be a POINT, found %s", g.Type)
+	}
+	if g.IsEmpty() {
+		return geo.Coord{}, false, nil
+	}
+	return g.Parts[0][0][0], true, nil
+}
@@ -10,13 +10,39 @@
 
 package tree
 
+// DescriptorCoverage specifies whether or not a subset of descriptors were
+// requested or if all the descriptors were requested, so all the descriptors
+// are covered in a given backup.
+type DescriptorCoverage int32
+
+const (
+	// RequestedDescriptors table coverage means that the backup is not
+	// guaranteed to have all of the cluster data. This can be accomplished by
+	// backing up a specific subset of tables/databases. Note that even if all
+	// of the tables and databases have been included in the backup manually, a
+	// backup is not said to have complete table coverage unless it was created
+	// by a `BACKUP TO` command.
+	RequestedDescriptors DescriptorCoverage = iota
+	// AllDescriptors table coverage means that backup is guaranteed to have all
+	// the relevant data in the cluster. These can only be created by running a
+	// full cluster backup with `BACKUP TO`.
+	AllDescriptors
+)
+
 // Backup represents a BACKUP statement.
 type Backup struct {
-	Targets         TargetList
-	To              PartitionedBackup
-	IncrementalFrom Exprs
-	AsOf            AsOfClause
-	Options         KVOptions
+	Targets            TargetList
+	DescriptorCoverage DescriptorCoverage
+	To                 PartitionedBackup
+	IncrementalFrom    Exprs
+	AsOf               AsOfClause
+	Options            KVOptions
+	// Nested is set for BACKUP INTO, which writes the backup to a new
+	// subdirectory of the collection in To.
+	Nested bool
+	// AppendToLatest is set for BACKUP INTO LATEST IN, which appends an
+	// incremental backup to the latest full backup of the collection in To.
+	AppendToLatest bool
 }
 
 var _ Statement = &Backup{}
@@ -24,8 +50,18 @@ var _ Statement = &Backup{}
 // Format implements the NodeFormatter interface.
 func (node *Backup) Format(ctx *FmtCtx) {
 	ctx.WriteString("BACKUP ")
-	ctx.FormatNode(&node.Targets)
-	ctx.WriteString(" TO ")
+	if node.DescriptorCoverage == RequestedDescriptors {
+		ctx.FormatNode(&node.Targets)
+		ctx.WriteString(" ")
+	}
+	if node.Nested {
+		ctx.WriteString("INTO ")
+		if node.AppendToLatest {
+			ctx.WriteString("LATEST IN ")
+		}
+	} else {
+		ctx.WriteString("TO ")
+	}
 	ctx.FormatNode(&node.To)
 	if node.AsOf.Expr != nil {
 		ctx.WriteString(" ")
@@ -43,10 +79,14 @@ func (node *Backup) Format(ctx *FmtCtx) {
 
 // Restore represents a RESTORE statement.
 type Restore struct {
-	Targets TargetList
-	From    []PartitionedBackup
-	AsOf    AsOfClause
-	Options KVOptions
+	Targets            TargetList
+	DescriptorCoverage DescriptorCoverage
+	From               []PartitionedBackup
+	AsOf               AsOfClause
+	Options            KVOptions
+	// FromLatest is set for RESTORE FROM LATEST IN, which restores the backups
+	// of the latest full backup of the collection in From.
+	FromLatest bool
 }
 
 var _ Statement = &Restore{}
@@ -54,8 +94,14 @@ var _ Statement = &Restore{}
 // Format implements the NodeFormatter interface.
 func (node *Restore) Format(ctx *FmtCtx) {
 	ctx.WriteString("RESTORE ")
-	ctx.FormatNode(&node.Targets)
-	ctx.WriteString(" FROM ")
+	if node.DescriptorCoverage == RequestedDescriptors {
+		ctx.FormatNode(&node.Targets)
+		ctx.WriteString(" ")
+	}
+	ctx.WriteString("FROM ")
+	if node.FromLatest {
+		ctx.WriteString("LATEST IN ")
+	}
 	for i := range node.From {
 		if i > 0 {
 			ctx.WriteString(", ")
@@ -462,6 +462,10 @@ var (
 		types.INet,
 		types.Jsonb,
 		types.VarBit,
+		types.TSVector,
+		types.TSQuery,
+		types.Geometry,
+		types.Geography,
 	}
 	// StrValAvailBytes is the set of types convertible to byte array.
 	StrValAvailBytes = []*types.T{types.Bytes, types.Uuid, types.String}
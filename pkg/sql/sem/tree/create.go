@@ -67,7 +67,10 @@ func (node *CreateDatabase) Format(ctx *FmtCtx) {
 
 // IndexElem represents a column with a direction in a CREATE INDEX statement.
 type IndexElem struct {
-	Column     Name
+	Column Name
+	// OpClass is the operator class of the column, which is only supported
+	// for the trigram inverted indexes of string columns (gin_trgm_ops).
+	OpClass    Name
 	Direction  Direction
 	NullsOrder NullsOrder
 }
@@ -75,6 +78,10 @@ type IndexElem struct {
 // Format implements the NodeFormatter interface.
 func (node *IndexElem) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.Column)
+	if node.OpClass != "" {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(&node.OpClass)
+	}
 	if node.Direction != DefaultDirection {
 		ctx.WriteByte(' ')
 		ctx.WriteString(node.Direction.String())
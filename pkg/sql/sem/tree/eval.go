@@ -36,12 +36,15 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/arith"
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/geo"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -1344,6 +1347,18 @@ var BinOps = map[BinaryOperator]binOpOverload{
 				return dd, err
 			},
 		},
+		&BinOp{
+			LeftType:   types.String,
+			RightType:  types.String,
+			ReturnType: types.Bool,
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				// Like the % operator of the pg_trgm extension of Postgres, this
+				// checks whether the strings are at least as similar as the
+				// pg_trgm.similarity_threshold session variable.
+				sim := trigram.Similarity(string(MustBeDString(left)), string(MustBeDString(right)))
+				return MakeDBool(DBool(sim >= ctx.SessionData.SimilarityThreshold)), nil
+			},
+		},
 	},
 
 	Concat: {
@@ -1773,6 +1788,10 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEqFn(types.Int, types.Int),
 		makeEqFn(types.Interval, types.Interval),
 		makeEqFn(types.Jsonb, types.Jsonb),
+		makeEqFn(types.TSVector, types.TSVector),
+		makeEqFn(types.TSQuery, types.TSQuery),
+		makeEqFn(types.Geometry, types.Geometry),
+		makeEqFn(types.Geography, types.Geography),
 		makeEqFn(types.Oid, types.Oid),
 		makeEqFn(types.String, types.String),
 		makeEqFn(types.Time, types.Time),
@@ -1914,6 +1933,10 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeIsFn(types.Int, types.Int),
 		makeIsFn(types.Interval, types.Interval),
 		makeIsFn(types.Jsonb, types.Jsonb),
+		makeIsFn(types.TSVector, types.TSVector),
+		makeIsFn(types.TSQuery, types.TSQuery),
+		makeIsFn(types.Geometry, types.Geometry),
+		makeIsFn(types.Geography, types.Geography),
 		makeIsFn(types.Oid, types.Oid),
 		makeIsFn(types.String, types.String),
 		makeIsFn(types.Time, types.Time),
@@ -1963,6 +1986,10 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(types.Int),
 		makeEvalTupleIn(types.Interval),
 		makeEvalTupleIn(types.Jsonb),
+		makeEvalTupleIn(types.TSVector),
+		makeEvalTupleIn(types.TSQuery),
+		makeEvalTupleIn(types.Geometry),
+		makeEvalTupleIn(types.Geography),
 		makeEvalTupleIn(types.Oid),
 		makeEvalTupleIn(types.String),
 		makeEvalTupleIn(types.Time),
@@ -2168,6 +2195,39 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 			},
 		},
 	},
+
+	TSMatches: {
+		&CmpOp{
+			LeftType:  types.TSVector,
+			RightType: types.TSQuery,
+			Fn: func(_ *EvalContext, left, right Datum) (Datum, error) {
+				q := MustBeDTSQuery(right).TSQuery
+				return MakeDBool(DBool(q.Matches(MustBeDTSVector(left).TSVector))), nil
+			},
+		},
+		&CmpOp{
+			LeftType:  types.TSQuery,
+			RightType: types.TSVector,
+			Fn: func(_ *EvalContext, left, right Datum) (Datum, error) {
+				q := MustBeDTSQuery(left).TSQuery
+				return MakeDBool(DBool(q.Matches(MustBeDTSVector(right).TSVector))), nil
+			},
+		},
+		&CmpOp{
+			LeftType:  types.String,
+			RightType: types.TSQuery,
+			Fn: func(_ *EvalContext, left, right Datum) (Datum, error) {
+				// Like Postgres, text @@ tsquery is equivalent to
+				// to_tsvector(text) @@ tsquery.
+				cfg, err := tsearch.GetConfig(tsearch.DefaultConfig)
+				if err != nil {
+					return nil, err
+				}
+				v := cfg.ToTSVector(string(MustBeDString(left)))
+				return MakeDBool(DBool(MustBeDTSQuery(right).Matches(v))), nil
+			},
+		},
+	},
 })
 
 // This map contains the inverses for operators in the CmpOps map that have
@@ -2557,6 +2617,17 @@ type EvalSessionAccessor interface {
 	GetSessionVar(ctx context.Context, settingName string, missingOk bool) (bool, string, error)
 }
 
+// StmtDiagnosticsRequestInsertFunc is used by builtins to insert a statement
+// diagnostics request for the given statement fingerprint. It is a function
+// (rather than a reference to the registry) to avoid a dependency on the
+// stmtdiagnostics package.
+type StmtDiagnosticsRequestInsertFunc func(ctx context.Context, stmtFingerprint string) error
+
+// StmtHintsSetFunc is used by builtins to attach plan hints to a statement
+// fingerprint; empty hints remove the hints for the fingerprint. It is a
+// function for the same reason as StmtDiagnosticsRequestInsertFunc.
+type StmtHintsSetFunc func(ctx context.Context, stmtFingerprint string, hints string) error
+
 // SessionBoundInternalExecutor is a subset of sqlutil.InternalExecutor used by
 // this sem/tree package which can't even import sqlutil. Executor used through
 // this interface are always "session-bound" - they inherit session variables
@@ -2704,6 +2775,15 @@ type EvalContext struct {
 
 	Sequence SequenceOperators
 
+	// StmtDiagnosticsRequestInserter is used by the
+	// crdb_internal.request_statement_bundle builtin to insert a statement
+	// diagnostics request. It may be nil.
+	StmtDiagnosticsRequestInserter StmtDiagnosticsRequestInsertFunc
+
+	// StmtHintsSetter is used by the crdb_internal.set_statement_hints builtin
+	// to attach plan hints to a statement fingerprint. It may be nil.
+	StmtHintsSetter StmtHintsSetFunc
+
 	// The transaction in which the statement is executing.
 	Txn *client.Txn
 	// A handle to the database.
@@ -3360,6 +3440,14 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 			s = t.String()
 		case *DJSON:
 			s = t.JSON.String()
+		case *DTSVector:
+			s = t.TSVector.String()
+		case *DTSQuery:
+			s = t.TSQuery.String()
+		case *DGeometry:
+			s = fmt.Sprintf("%X", t.EWKB())
+		case *DGeography:
+			s = fmt.Sprintf("%X", t.EWKB())
 		}
 		switch t.Family() {
 		case types.StringFamily:
@@ -3390,6 +3478,10 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 			return NewDBytes(DBytes(t.Contents)), nil
 		case *DUuid:
 			return NewDBytes(DBytes(t.GetBytes())), nil
+		case *DGeometry:
+			return NewDBytes(DBytes(t.EWKB())), nil
+		case *DGeography:
+			return NewDBytes(DBytes(t.EWKB())), nil
 		case *DBytes:
 			return d, nil
 		}
@@ -3550,6 +3642,66 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 		case *DJSON:
 			return v, nil
 		}
+	case types.TSVectorFamily:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDTSVector(string(*v))
+		case *DCollatedString:
+			return ParseDTSVector(v.Contents)
+		case *DTSVector:
+			return v, nil
+		}
+	case types.TSQueryFamily:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDTSQuery(string(*v))
+		case *DCollatedString:
+			return ParseDTSQuery(v.Contents)
+		case *DTSQuery:
+			return v, nil
+		}
+	case types.GeometryFamily:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDGeometry(string(*v))
+		case *DCollatedString:
+			return ParseDGeometry(v.Contents)
+		case *DBytes:
+			g, err := geo.GeometryFromEWKB([]byte(*v))
+			if err != nil {
+				return nil, err
+			}
+			return NewDGeometry(g), nil
+		case *DGeography:
+			g, err := geo.MakeGeometry(v.SRID, v.Shape)
+			if err != nil {
+				return nil, err
+			}
+			return NewDGeometry(g), nil
+		case *DGeometry:
+			return v, nil
+		}
+	case types.GeographyFamily:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDGeography(string(*v))
+		case *DCollatedString:
+			return ParseDGeography(v.Contents)
+		case *DBytes:
+			g, err := geo.GeographyFromEWKB([]byte(*v))
+			if err != nil {
+				return nil, err
+			}
+			return NewDGeography(g), nil
+		case *DGeometry:
+			g, err := geo.MakeGeography(v.SRID, v.Shape)
+			if err != nil {
+				return nil, err
+			}
+			return NewDGeography(g), nil
+		case *DGeography:
+			return v, nil
+		}
 	case types.ArrayFamily:
 		switch v := d.(type) {
 		case *DString:
@@ -4231,6 +4383,26 @@ func (t *DJSON) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DTSVector) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DTSQuery) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DGeometry) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DGeography) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t dNull) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
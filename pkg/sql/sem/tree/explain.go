@@ -88,6 +88,11 @@ const (
 	// ExplainVec shows the physical vectorized plan for a query and whether a
 	// query would be run in "auto" vectorized mode.
 	ExplainVec
+
+	// ExplainDebug generates a statement diagnostics bundle for the query; it
+	// can only be used together with ANALYZE. See sql/explain_bundle.go for
+	// details.
+	ExplainDebug
 )
 
 var explainModeStrings = map[string]ExplainMode{
@@ -95,6 +100,7 @@ var explainModeStrings = map[string]ExplainMode{
 	"distsql": ExplainDistSQL,
 	"opt":     ExplainOpt,
 	"vec":     ExplainVec,
+	"debug":   ExplainDebug,
 }
 
 // ExplainModeName returns the human-readable name of a given ExplainMode.
@@ -151,5 +157,15 @@ func (node *Explain) ParseOptions() (ExplainOptions, error) {
 		}
 		res.Flags.Add(flag)
 	}
+	if res.Mode == ExplainDebug && !res.Flags.Contains(ExplainFlagAnalyze) {
+		return ExplainOptions{}, pgerror.Newf(pgcode.Syntax,
+			"DEBUG flag can only be used with EXPLAIN ANALYZE")
+	}
 	return res, nil
 }
+
+// IsAnalyzeDebug returns true if this is an EXPLAIN ANALYZE (DEBUG) statement.
+func (node *Explain) IsAnalyzeDebug() bool {
+	opts, err := node.ParseOptions()
+	return err == nil && opts.Mode == ExplainDebug
+}
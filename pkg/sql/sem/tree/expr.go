@@ -326,6 +326,7 @@ const (
 	JSONSomeExists
 	JSONAllExists
 	Overlaps
+	TSMatches
 
 	// The following operators will always be used with an associated SubOperator.
 	// If Go had algebraic data types they would be defined in a self-contained
@@ -376,6 +377,7 @@ var comparisonOpName = [...]string{
 	JSONSomeExists:    "?|",
 	JSONAllExists:     "?&",
 	Overlaps:          "&&",
+	TSMatches:         "@@",
 	Any:               "ANY",
 	Some:              "SOME",
 	All:               "ALL",
@@ -1246,6 +1248,10 @@ type FuncExpr struct {
 	// OrderBy is used for aggregations that specify an order:
 	// array_agg(col1 ORDER BY col2)
 	OrderBy OrderBy
+	// AggType is used to specify the kind of aggregation. For ordered-set
+	// aggregates, OrderBy holds the WITHIN GROUP clause:
+	// percentile_disc(0.5) WITHIN GROUP (ORDER BY col1)
+	AggType AggType
 	typeAnnotation
 	fnProps *FunctionProperties
 	fn      *Overload
@@ -1346,6 +1352,18 @@ var funcTypeName = [...]string{
 	AllFuncType:      "ALL",
 }
 
+// AggType specifies the kind of an aggregate function application.
+type AggType int
+
+// FuncExpr.AggType
+const (
+	// GeneralAgg is an ordinary aggregate function application.
+	GeneralAgg AggType = iota
+	// OrderedSetAgg is an ordered-set aggregate function application, which
+	// takes its ordering from a WITHIN GROUP clause.
+	OrderedSetAgg
+)
+
 // Format implements the NodeFormatter interface.
 func (node *FuncExpr) Format(ctx *FmtCtx) {
 	var typ string
@@ -1362,7 +1380,7 @@ func (node *FuncExpr) Format(ctx *FmtCtx) {
 	ctx.WriteByte('(')
 	ctx.WriteString(typ)
 	ctx.FormatNode(&node.Exprs)
-	if len(node.OrderBy) > 0 {
+	if len(node.OrderBy) > 0 && node.AggType != OrderedSetAgg {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(&node.OrderBy)
 	}
@@ -1378,6 +1396,11 @@ func (node *FuncExpr) Format(ctx *FmtCtx) {
 			}
 		}
 	}
+	if node.AggType == OrderedSetAgg {
+		ctx.WriteString(" WITHIN GROUP (")
+		ctx.FormatNode(&node.OrderBy)
+		ctx.WriteByte(')')
+	}
 	if node.Filter != nil {
 		ctx.WriteString(" FILTER (WHERE ")
 		ctx.FormatNode(node.Filter)
@@ -1528,8 +1551,9 @@ var (
 	stringCastTypes = annotateCast(types.String, []*types.T{types.Unknown, types.Bool, types.Int, types.Float, types.Decimal, types.String, types.AnyCollatedString,
 		types.VarBit,
 		types.AnyArray, types.AnyTuple,
-		types.Bytes, types.Timestamp, types.TimestampTZ, types.Interval, types.Uuid, types.Date, types.Time, types.TimeTZ, types.Oid, types.INet, types.Jsonb})
-	bytesCastTypes = annotateCast(types.Bytes, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Bytes, types.Uuid})
+		types.Bytes, types.Timestamp, types.TimestampTZ, types.Interval, types.Uuid, types.Date, types.Time, types.TimeTZ, types.Oid, types.INet, types.Jsonb,
+		types.TSVector, types.TSQuery, types.Geometry, types.Geography})
+	bytesCastTypes = annotateCast(types.Bytes, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Bytes, types.Uuid, types.Geometry, types.Geography})
 	dateCastTypes  = annotateCast(types.Date, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Date, types.Timestamp, types.TimestampTZ, types.Int})
 	timeCastTypes  = annotateCast(types.Time, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Time, types.TimeTZ,
 		types.Timestamp, types.TimestampTZ, types.Interval})
@@ -1541,6 +1565,12 @@ var (
 	inetCastTypes      = annotateCast(types.INet, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.INet})
 	arrayCastTypes     = annotateCast(types.AnyArray, []*types.T{types.Unknown, types.String})
 	jsonCastTypes      = annotateCast(types.Jsonb, []*types.T{types.Unknown, types.String, types.Jsonb})
+	tsVectorCastTypes  = annotateCast(types.TSVector, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.TSVector})
+	tsQueryCastTypes   = annotateCast(types.TSQuery, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.TSQuery})
+	geometryCastTypes  = annotateCast(types.Geometry, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Bytes,
+		types.Geometry, types.Geography})
+	geographyCastTypes = annotateCast(types.Geography, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Bytes,
+		types.Geometry, types.Geography})
 )
 
 // validCastTypes returns a set of types that can be cast into the provided type.
@@ -1572,6 +1602,14 @@ func validCastTypes(t *types.T) []castInfo {
 		return intervalCastTypes
 	case types.JsonFamily:
 		return jsonCastTypes
+	case types.TSVectorFamily:
+		return tsVectorCastTypes
+	case types.TSQueryFamily:
+		return tsQueryCastTypes
+	case types.GeometryFamily:
+		return geometryCastTypes
+	case types.GeographyFamily:
+		return geographyCastTypes
 	case types.UuidFamily:
 		return uuidCastTypes
 	case types.INetFamily:
@@ -1745,6 +1783,10 @@ func (node *DFloat) String() string           { return AsString(node) }
 func (node *DInt) String() string             { return AsString(node) }
 func (node *DInterval) String() string        { return AsString(node) }
 func (node *DJSON) String() string            { return AsString(node) }
+func (node *DTSVector) String() string        { return AsString(node) }
+func (node *DTSQuery) String() string         { return AsString(node) }
+func (node *DGeometry) String() string        { return AsString(node) }
+func (node *DGeography) String() string       { return AsString(node) }
 func (node *DUuid) String() string            { return AsString(node) }
 func (node *DIPAddr) String() string          { return AsString(node) }
 func (node *DString) String() string          { return AsString(node) }
@@ -1758,6 +1800,7 @@ func (node *DOidWrapper) String() string      { return AsString(node) }
 func (node *Exprs) String() string            { return AsString(node) }
 func (node *ArrayFlatten) String() string     { return AsString(node) }
 func (node *FuncExpr) String() string         { return AsString(node) }
+func (node *GroupingSet) String() string      { return AsString(node) }
 func (node *IfExpr) String() string           { return AsString(node) }
 func (node *IfErrExpr) String() string        { return AsString(node) }
 func (node *IndexedVar) String() string       { return AsString(node) }
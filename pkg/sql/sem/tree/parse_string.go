@@ -86,6 +86,14 @@ func parseStringAs(t *types.T, s string, ctx ParseTimeContext) (Datum, error) {
 		return ParseDTimestamp(ctx, s, TimeFamilyPrecisionToRoundDuration(t.Precision()))
 	case types.TimestampTZFamily:
 		return ParseDTimestampTZ(ctx, s, TimeFamilyPrecisionToRoundDuration(t.Precision()))
+	case types.TSQueryFamily:
+		return ParseDTSQuery(s)
+	case types.TSVectorFamily:
+		return ParseDTSVector(s)
+	case types.GeometryFamily:
+		return ParseDGeometry(s)
+	case types.GeographyFamily:
+		return ParseDGeography(s)
 	case types.UuidFamily:
 		return ParseDUuidFromString(s)
 	default:
@@ -12,6 +12,7 @@ package tree
 
 import (
 	"bytes"
+	"fmt"
 	"unicode/utf8"
 
 	"github.com/lib/pq/oid"
@@ -47,6 +48,14 @@ func (d *DTuple) pgwireFormat(ctx *FmtCtx) {
 			var buf bytes.Buffer
 			dv.JSON.Format(&buf)
 			pgwireFormatStringInTuple(&ctx.Buffer, buf.String())
+		case *DTSVector:
+			pgwireFormatStringInTuple(&ctx.Buffer, dv.TSVector.String())
+		case *DTSQuery:
+			pgwireFormatStringInTuple(&ctx.Buffer, dv.TSQuery.String())
+		case *DGeometry:
+			pgwireFormatStringInTuple(&ctx.Buffer, fmt.Sprintf("%X", dv.EWKB()))
+		case *DGeography:
+			pgwireFormatStringInTuple(&ctx.Buffer, fmt.Sprintf("%X", dv.EWKB()))
 		default:
 			s := AsStringWithFlags(v, ctx.flags)
 			pgwireFormatStringInTuple(&ctx.Buffer, s)
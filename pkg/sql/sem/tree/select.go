@@ -616,6 +616,45 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetType represents the type of a GroupingSet.
+type GroupingSetType int
+
+const (
+	// RollupGroupingSet represents ROLLUP (...).
+	RollupGroupingSet GroupingSetType = iota
+	// CubeGroupingSet represents CUBE (...).
+	CubeGroupingSet
+	// GroupingSets represents GROUPING SETS (...).
+	GroupingSets
+)
+
+var groupingSetTypeName = [...]string{
+	RollupGroupingSet: "ROLLUP",
+	CubeGroupingSet:   "CUBE",
+	GroupingSets:      "GROUPING SETS",
+}
+
+func (t GroupingSetType) String() string {
+	return groupingSetTypeName[t]
+}
+
+// GroupingSet represents a ROLLUP, CUBE or GROUPING SETS item in a GROUP BY
+// clause. Each of its Exprs is either an expression or a Tuple of
+// expressions which are grouped on together. The Exprs of GROUPING SETS may
+// additionally be nested GroupingSets.
+type GroupingSet struct {
+	Type  GroupingSetType
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Type.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
@@ -78,10 +78,18 @@ type ShowBackup struct {
 	Path                 Expr
 	Details              BackupDetails
 	ShouldIncludeSchemas bool
+	// InCollection is set for SHOW BACKUPS IN, which lists the backups of the
+	// collection in Path.
+	InCollection bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *ShowBackup) Format(ctx *FmtCtx) {
+	if node.InCollection {
+		ctx.WriteString("SHOW BACKUPS IN ")
+		ctx.FormatNode(node.Path)
+		return
+	}
 	ctx.WriteString("SHOW BACKUP ")
 	if node.Details == BackupRangeDetails {
 		ctx.WriteString("RANGES ")
@@ -77,6 +77,18 @@ func SampleDatum(t *types.T) Datum {
 	case types.JsonFamily:
 		j, _ := ParseDJSON(`{"a": "b"}`)
 		return j
+	case types.TSVectorFamily:
+		v, _ := ParseDTSVector(`'cat':3 'fat':2 'rat':4`)
+		return v
+	case types.TSQueryFamily:
+		q, _ := ParseDTSQuery(`'fat' & 'rat'`)
+		return q
+	case types.GeometryFamily:
+		g, _ := ParseDGeometry(`POINT(1 2)`)
+		return g
+	case types.GeographyFamily:
+		g, _ := ParseDGeography(`POINT(-73.98 40.75)`)
+		return g
 	case types.OidFamily:
 		return NewDOid(DInt(1009))
 	default:
@@ -683,11 +683,12 @@ func (expr *ComparisonExpr) TypeCheck(ctx *SemaContext, desired *types.T) (Typed
 }
 
 var (
-	errStarNotAllowed      = pgerror.New(pgcode.Syntax, "cannot use \"*\" in this context")
-	errInvalidDefaultUsage = pgerror.New(pgcode.Syntax, "DEFAULT can only appear in a VALUES list within INSERT or on the right side of a SET")
-	errInvalidMaxUsage     = pgerror.New(pgcode.Syntax, "MAXVALUE can only appear within a range partition expression")
-	errInvalidMinUsage     = pgerror.New(pgcode.Syntax, "MINVALUE can only appear within a range partition expression")
-	errPrivateFunction     = pgerror.New(pgcode.ReservedName, "function reserved for internal use")
+	errStarNotAllowed          = pgerror.New(pgcode.Syntax, "cannot use \"*\" in this context")
+	errInvalidDefaultUsage     = pgerror.New(pgcode.Syntax, "DEFAULT can only appear in a VALUES list within INSERT or on the right side of a SET")
+	errInvalidMaxUsage         = pgerror.New(pgcode.Syntax, "MAXVALUE can only appear within a range partition expression")
+	errInvalidMinUsage         = pgerror.New(pgcode.Syntax, "MINVALUE can only appear within a range partition expression")
+	errPrivateFunction         = pgerror.New(pgcode.ReservedName, "function reserved for internal use")
+	errInvalidGroupingSetUsage = pgerror.New(pgcode.Syntax, "ROLLUP, CUBE and GROUPING SETS can only appear in a GROUP BY clause")
 )
 
 // NewAggInAggError creates an error for the case when an aggregate function is
@@ -909,6 +910,12 @@ func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr,
 		expr.Filter = typedFilter
 	}
 
+	if expr.AggType == OrderedSetAgg && def.Class != AggregateClass {
+		// Same error message as Postgres.
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"WITHIN GROUP specified, but %s() is not an aggregate function", &expr.Func)
+	}
+
 	if expr.OrderBy != nil {
 		for i := range expr.OrderBy {
 			typedExpr, err := expr.OrderBy[i].Expr.TypeCheck(ctx, types.Any)
@@ -1181,6 +1188,11 @@ func (expr PartitionMaxVal) TypeCheck(_ *SemaContext, desired *types.T) (TypedEx
 	return nil, errInvalidMaxUsage
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSet) TypeCheck(_ *SemaContext, desired *types.T) (TypedExpr, error) {
+	return nil, errInvalidGroupingSetUsage
+}
+
 // TypeCheck implements the Expr interface.
 func (expr *NumVal) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, error) {
 	return typeCheckConstant(expr, ctx, desired)
@@ -1393,6 +1405,22 @@ func (d *DInterval) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { r
 // identity function for Datum.
 func (d *DJSON) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSVector) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSQuery) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DGeometry) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DGeography) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DTuple) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
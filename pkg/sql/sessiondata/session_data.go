@@ -96,6 +96,13 @@ type SessionData struct {
 	SaveTablesPrefix string
 	// TempTablesEnabled indicates whether temporary tables can be created or not.
 	TempTablesEnabled bool
+	// SimilarityThreshold is the trigram similarity at or above which the %
+	// operator considers two strings to be similar.
+	SimilarityThreshold float64
+	// PlanCacheMode indicates whether prepared statements are executed with
+	// custom plans, optimized for the values of their placeholders, or with a
+	// generic plan that is optimized once and reused.
+	PlanCacheMode PlanCacheMode
 }
 
 // DataConversionConfig contains the parameters that influence
@@ -343,3 +350,52 @@ func SerialNormalizationModeFromString(val string) (_ SerialNormalizationMode, o
 		return 0, false
 	}
 }
+
+// PlanCacheMode controls whether the optimizer uses a custom plan or a generic
+// plan to execute a prepared statement.
+type PlanCacheMode int64
+
+const (
+	// PlanCacheModeForceCustom means that a custom plan, optimized for the
+	// values of the placeholders, is built for each execution.
+	PlanCacheModeForceCustom PlanCacheMode = iota
+	// PlanCacheModeForceGeneric means that a generic plan, optimized without the
+	// values of the placeholders, is built once and reused for each execution.
+	PlanCacheModeForceGeneric
+	// PlanCacheModeAuto means that custom plans are built for the first five
+	// executions, and that the generic plan is used afterwards if it doesn't
+	// depend on the values of the placeholders (see
+	// xform.IsPlaceholderIndependent) and if its estimated cost is no higher
+	// than the average estimated cost of those custom plans, where the cost of
+	// each custom plan includes an overhead that accounts for optimizing it
+	// (see xform.CustomPlanOverhead).
+	PlanCacheModeAuto
+)
+
+func (m PlanCacheMode) String() string {
+	switch m {
+	case PlanCacheModeForceCustom:
+		return "force_custom_plan"
+	case PlanCacheModeForceGeneric:
+		return "force_generic_plan"
+	case PlanCacheModeAuto:
+		return "auto"
+	default:
+		return fmt.Sprintf("invalid (%d)", m)
+	}
+}
+
+// PlanCacheModeFromString converts a string into a PlanCacheMode. False is
+// returned if the conversion was unsuccessful.
+func PlanCacheModeFromString(val string) (_ PlanCacheMode, ok bool) {
+	switch strings.ToUpper(val) {
+	case "FORCE_CUSTOM_PLAN":
+		return PlanCacheModeForceCustom, true
+	case "FORCE_GENERIC_PLAN":
+		return PlanCacheModeForceGeneric, true
+	case "AUTO":
+		return PlanCacheModeAuto, true
+	default:
+		return 0, false
+	}
+}
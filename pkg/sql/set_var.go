@@ -199,6 +199,37 @@ func getIntVal(evalCtx *tree.EvalContext, name string, values []tree.TypedExpr)
 	return datumAsInt(evalCtx, name, values[0])
 }
 
+func datumAsFloat(evalCtx *tree.EvalContext, name string, value tree.TypedExpr) (float64, error) {
+	val, err := value.Eval(evalCtx)
+	if err != nil {
+		return 0, err
+	}
+	switch v := val.(type) {
+	case *tree.DFloat:
+		return float64(*v), nil
+	case *tree.DDecimal:
+		return v.Float64()
+	case *tree.DInt:
+		return float64(*v), nil
+	case *tree.DString:
+		if f, err := strconv.ParseFloat(string(*v), 64); err == nil {
+			return f, nil
+		}
+	}
+	err = pgerror.Newf(pgcode.InvalidParameterValue,
+		"parameter %q requires a numeric value", name)
+	err = errors.WithDetailf(err,
+		"%s is a %s", value, errors.Safe(val.ResolvedType()))
+	return 0, err
+}
+
+func getFloatVal(evalCtx *tree.EvalContext, name string, values []tree.TypedExpr) (float64, error) {
+	if len(values) != 1 {
+		return 0, newSingleArgVarError(name)
+	}
+	return datumAsFloat(evalCtx, name, values[0])
+}
+
 func timeZoneVarGetStringVal(
 	_ context.Context, evalCtx *extendedEvalContext, values []tree.TypedExpr,
 ) (string, error) {
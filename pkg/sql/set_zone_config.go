@@ -75,6 +75,7 @@ var supportedZoneConfigOptions = map[tree.Name]struct {
 		loadYAML(&c.LeasePreferences, string(tree.MustBeDString(d)))
 		c.InheritedLeasePreferences = false
 	}},
+	"global_reads": {types.Bool, func(c *config.ZoneConfig, d tree.Datum) { c.GlobalReads = proto.Bool(bool(tree.MustBeDBool(d))) }},
 }
 
 // zoneOptionKeys contains the keys from suportedZoneConfigOptions in
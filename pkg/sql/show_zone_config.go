@@ -200,6 +200,11 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *config.ZoneConfig) (string, e
 		f.Printf("\tnum_replicas = %d", *zone.NumReplicas)
 		useComma = true
 	}
+	if zone.GlobalReads != nil {
+		writeComma(f, useComma)
+		f.Printf("\tglobal_reads = %t", *zone.GlobalReads)
+		useComma = true
+	}
 	if !zone.InheritedConstraints {
 		writeComma(f, useComma)
 		f.Printf("\tconstraints = %s", lex.EscapeSQLString(constraints))
@@ -20,10 +20,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/geo"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -396,6 +398,14 @@ func EncodeTableValue(
 			return nil, err
 		}
 		return encoding.EncodeJSONValue(appendTo, uint32(colID), encoded), nil
+	case *tree.DTSVector:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.TSVector.Encode(scratch)), nil
+	case *tree.DTSQuery:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.TSQuery.String())), nil
+	case *tree.DGeometry:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.EWKB()), nil
+	case *tree.DGeography:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.EWKB()), nil
 	case *tree.DArray:
 		a, err := encodeArray(t, scratch)
 		if err != nil {
@@ -430,6 +440,13 @@ func DecodeTableValue(a *DatumAlloc, valType *types.T, b []byte) (tree.Datum, []
 	return decodeUntaggedDatum(a, valType, b)
 }
 
+// DecodeUntaggedDatum is the exported version of decodeUntaggedDatum. It is
+// used by the vectorized engine to decode the types that it doesn't have a
+// native representation for.
+func DecodeUntaggedDatum(a *DatumAlloc, t *types.T, buf []byte) (tree.Datum, []byte, error) {
+	return decodeUntaggedDatum(a, t, buf)
+}
+
 // decodeUntaggedDatum is used to decode a Datum whose type is known,
 // and which doesn't have a value tag (either due to it having been
 // consumed already or not having one in the first place).
@@ -533,6 +550,43 @@ func decodeUntaggedDatum(a *DatumAlloc, t *types.T, buf []byte) (tree.Datum, []b
 			return nil, b, err
 		}
 		return a.NewDJSON(tree.DJSON{JSON: j}), b, nil
+	case types.TSVectorFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		v, err := tsearch.DecodeTSVector(data)
+		if err != nil {
+			return nil, b, err
+		}
+		return tree.NewDTSVector(v), b, nil
+	case types.TSQueryFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		q, err := tree.ParseDTSQuery(string(data))
+		return q, b, err
+	case types.GeometryFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		g, err := geo.GeometryFromEWKB(data)
+		if err != nil {
+			return nil, b, err
+		}
+		return tree.NewDGeometry(g), b, nil
+	case types.GeographyFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		g, err := geo.GeographyFromEWKB(data)
+		if err != nil {
+			return nil, b, err
+		}
+		return tree.NewDGeography(g), b, nil
 	case types.OidFamily:
 		b, data, err := encoding.DecodeUntaggedIntValue(buf)
 		return a.NewDOid(tree.MakeDOid(tree.DInt(data))), b, err
@@ -672,6 +726,26 @@ func MarshalColumnValue(col *ColumnDescriptor, val tree.Datum) (roachpb.Value, e
 			r.SetBytes(data)
 			return r, nil
 		}
+	case types.TSVectorFamily:
+		if v, ok := val.(*tree.DTSVector); ok {
+			r.SetBytes(v.TSVector.Encode(nil))
+			return r, nil
+		}
+	case types.TSQueryFamily:
+		if v, ok := val.(*tree.DTSQuery); ok {
+			r.SetBytes([]byte(v.TSQuery.String()))
+			return r, nil
+		}
+	case types.GeometryFamily:
+		if v, ok := val.(*tree.DGeometry); ok {
+			r.SetBytes(v.EWKB())
+			return r, nil
+		}
+	case types.GeographyFamily:
+		if v, ok := val.(*tree.DGeography); ok {
+			r.SetBytes(v.EWKB())
+			return r, nil
+		}
 	case types.ArrayFamily:
 		if v, ok := val.(*tree.DArray); ok {
 			if err := checkElementType(v.ParamTyp, col.Type.ArrayContents()); err != nil {
@@ -852,6 +926,42 @@ func UnmarshalColumnValue(a *DatumAlloc, typ *types.T, value roachpb.Value) (tre
 			return nil, err
 		}
 		return tree.NewDJSON(jsonDatum), nil
+	case types.TSVectorFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		vec, err := tsearch.DecodeTSVector(v)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDTSVector(vec), nil
+	case types.TSQueryFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return tree.ParseDTSQuery(string(v))
+	case types.GeometryFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		g, err := geo.GeometryFromEWKB(v)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDGeometry(g), nil
+	case types.GeographyFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		g, err := geo.GeographyFromEWKB(v)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDGeography(g), nil
 	default:
 		return nil, errors.Errorf("unsupported column type: %s", typ.Family())
 	}
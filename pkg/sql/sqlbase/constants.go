@@ -155,5 +155,7 @@ const (
 	PgCatalogStatActivityTableID
 	PgCatalogSecurityLabelTableID
 	PgCatalogSharedSecurityLabelTableID
-	MinVirtualID = PgCatalogSharedSecurityLabelTableID
+	CrdbInternalHotKeysTableID
+	CrdbInternalHotRangesTableID
+	MinVirtualID = CrdbInternalHotRangesTableID
 )
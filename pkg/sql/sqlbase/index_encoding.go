@@ -21,7 +21,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/geo/geoindex"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/errors"
 )
 
@@ -725,10 +728,12 @@ func EncodeInvertedIndexKeys(
 	return EncodeInvertedIndexTableKeys(val, keyPrefix)
 }
 
-// EncodeInvertedIndexTableKeys encodes the paths in a JSON `val` and
-// concatenates it with `inKey`and returns a list of buffers per
-// path. The encoded values is guaranteed to be lexicographically
-// sortable, but not guaranteed to be round-trippable during decoding.
+// EncodeInvertedIndexTableKeys encodes the paths in a JSON `val`, the
+// lexemes in a tsvector `val`, the trigrams of a string `val`, or the covering
+// cells of a geometry or geography `val`, and concatenates it with `inKey`and
+// returns a list of buffers per path, lexeme, trigram or cell. The encoded
+// values is guaranteed to be lexicographically sortable, but not guaranteed to
+// be round-trippable during decoding.
 func EncodeInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, err error) {
 	if val == tree.DNull {
 		return [][]byte{encoding.EncodeNullAscending(inKey)}, nil
@@ -736,8 +741,78 @@ func EncodeInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, e
 	switch t := tree.UnwrapDatum(nil, val).(type) {
 	case *tree.DJSON:
 		return json.EncodeInvertedIndexKeys(inKey, (t.JSON))
+	case *tree.DTSVector:
+		return encodeTSVectorInvertedIndexKeys(inKey, t.TSVector), nil
+	case *tree.DString:
+		return encodeTrigramInvertedIndexKeys(inKey, string(*t)), nil
+	case *tree.DGeometry:
+		return encodeCellInvertedIndexKeys(inKey, geoindex.GeometryCovering(t.Geometry)), nil
+	case *tree.DGeography:
+		return encodeCellInvertedIndexKeys(inKey, geoindex.GeographyCovering(t.Geography)), nil
 	}
-	return nil, errors.AssertionFailedf("trying to apply inverted index to non JSON type")
+	return nil, errors.AssertionFailedf(
+		"trying to apply inverted index to non JSON, tsvector, string or spatial type")
+}
+
+// EncodeInvertedIndexSpanKey encodes a value that constrains a scan of an
+// inverted index, and concatenates it with `inKey`. Unlike the values passed
+// to EncodeInvertedIndexTableKeys, the value must correspond to a single key
+// of the index: a JSON path, a tsvector with a single lexeme, a trigram, or
+// the integer ID of a cell of a spatial index.
+func EncodeInvertedIndexSpanKey(val tree.Datum, inKey []byte) ([]byte, error) {
+	switch t := tree.UnwrapDatum(nil, val).(type) {
+	case *tree.DString:
+		// A trigram is encoded as is, rather than split into the trigrams of
+		// its words.
+		prefix := append([]byte(nil), inKey...)
+		return encoding.EncodeStringAscending(prefix, string(*t)), nil
+	case *tree.DInt:
+		prefix := append([]byte(nil), inKey...)
+		return encoding.EncodeVarintAscending(prefix, int64(*t)), nil
+	}
+	keys, err := EncodeInvertedIndexTableKeys(val, inKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) != 1 {
+		return nil, errors.AssertionFailedf("trying to use %d keys in inverted index lookup", len(keys))
+	}
+	return keys[0], nil
+}
+
+// encodeTSVectorInvertedIndexKeys returns one inverted index key per lexeme
+// of a tsvector. The positions and weights of the lexemes are not indexed, so
+// queries that depend on them must be filtered after the index scan.
+func encodeTSVectorInvertedIndexKeys(inKey []byte, v tsearch.TSVector) [][]byte {
+	keys := make([][]byte, len(v))
+	for i := range v {
+		prefix := append([]byte(nil), inKey...)
+		keys[i] = encoding.EncodeStringAscending(prefix, v[i].Word)
+	}
+	return keys
+}
+
+// encodeTrigramInvertedIndexKeys returns one inverted index key per trigram
+// of a string.
+func encodeTrigramInvertedIndexKeys(inKey []byte, s string) [][]byte {
+	trigrams := trigram.MakeTrigrams(s)
+	keys := make([][]byte, len(trigrams))
+	for i := range trigrams {
+		prefix := append([]byte(nil), inKey...)
+		keys[i] = encoding.EncodeStringAscending(prefix, trigrams[i])
+	}
+	return keys
+}
+
+// encodeCellInvertedIndexKeys returns one inverted index key per cell of the
+// covering of a shape. An empty shape has no cells, and is not indexed.
+func encodeCellInvertedIndexKeys(inKey []byte, covering []geoindex.CellID) [][]byte {
+	keys := make([][]byte, len(covering))
+	for i, c := range covering {
+		prefix := append([]byte(nil), inKey...)
+		keys[i] = encoding.EncodeVarintAscending(prefix, int64(c))
+	}
+	return keys
 }
 
 // EncodeSecondaryIndex encodes key/values for a secondary
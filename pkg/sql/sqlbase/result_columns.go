@@ -119,6 +119,12 @@ var ExplainVecColumns = ResultColumns{
 	{Name: "text", Typ: types.String},
 }
 
+// ExplainAnalyzeDebugColumns are the result columns of an
+// EXPLAIN ANALYZE (DEBUG) statement.
+var ExplainAnalyzeDebugColumns = ResultColumns{
+	{Name: "text", Typ: types.String},
+}
+
 // ShowTraceColumns are the result columns of a SHOW [KV] TRACE statement.
 var ShowTraceColumns = ResultColumns{
 	{Name: "timestamp", Typ: types.TimestampTZ},
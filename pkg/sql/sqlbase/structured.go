@@ -434,11 +434,28 @@ func (desc *IndexDescriptor) allocateName(tableDesc *MutableTableDescriptor) {
 	desc.Name = name
 }
 
+// TrigramOpClass is the name of the operator class of the trigram inverted
+// indexes of string columns.
+const TrigramOpClass = "gin_trgm_ops"
+
 // FillColumns sets the column names and directions in desc.
 func (desc *IndexDescriptor) FillColumns(elems tree.IndexElemList) error {
 	desc.ColumnNames = make([]string, 0, len(elems))
 	desc.ColumnDirections = make([]IndexDescriptor_Direction, 0, len(elems))
 	for _, c := range elems {
+		if c.OpClass != "" {
+			// The only supported operator class is the one of trigram indexes,
+			// which can be specified for compatibility with Postgres.
+			if c.OpClass != TrigramOpClass {
+				return pgerror.Newf(pgcode.UndefinedObject,
+					"operator class %q does not exist", string(c.OpClass))
+			}
+			if desc.Type != IndexDescriptor_INVERTED {
+				return pgerror.Newf(pgcode.UndefinedObject,
+					"operator class %q does not accept access method %q", string(c.OpClass), "btree")
+			}
+			desc.OpClass = string(c.OpClass)
+		}
 		desc.ColumnNames = append(desc.ColumnNames, string(c.Column))
 		switch c.Direction {
 		case tree.Ascending, tree.DefaultDirection:
@@ -500,6 +517,9 @@ func (desc *IndexDescriptor) ColNamesFormat(ctx *tree.FmtCtx) {
 		if desc.Type != IndexDescriptor_INVERTED {
 			ctx.WriteByte(' ')
 			ctx.WriteString(desc.ColumnDirections[i].String())
+		} else if desc.OpClass != "" {
+			ctx.WriteByte(' ')
+			ctx.WriteString(desc.OpClass)
 		}
 	}
 }
@@ -1262,6 +1282,10 @@ func DatumTypeHasCompositeKeyEncoding(typ *types.T) bool {
 func MustBeValueEncoded(semanticType types.Family) bool {
 	return semanticType == types.ArrayFamily ||
 		semanticType == types.JsonFamily ||
+		semanticType == types.TSVectorFamily ||
+		semanticType == types.TSQueryFamily ||
+		semanticType == types.GeometryFamily ||
+		semanticType == types.GeographyFamily ||
 		semanticType == types.TupleFamily
 }
 
@@ -2174,9 +2198,16 @@ func ColumnTypeIsIndexable(t *types.T) bool {
 }
 
 // ColumnTypeIsInvertedIndexable returns whether the type t is valid to be indexed
-// using an inverted index.
+// using an inverted index. An inverted index on a string column stores the
+// trigrams of the strings, and an inverted index on a spatial column stores
+// the cells that cover the shapes.
 func ColumnTypeIsInvertedIndexable(t *types.T) bool {
-	return t.Family() == types.JsonFamily
+	switch t.Family() {
+	case types.JsonFamily, types.TSVectorFamily, types.StringFamily,
+		types.GeometryFamily, types.GeographyFamily:
+		return true
+	}
+	return false
 }
 
 func notIndexableError(cols []ColumnDescriptor, inverted bool) error {
@@ -2226,17 +2257,19 @@ func checkColumnsValidForIndex(tableDesc *MutableTableDescriptor, indexColNames
 }
 
 func checkColumnsValidForInvertedIndex(
-	tableDesc *MutableTableDescriptor, indexColNames []string,
+	tableDesc *MutableTableDescriptor, idx *IndexDescriptor,
 ) error {
-	if len((indexColNames)) > 1 {
+	if len((idx.ColumnNames)) > 1 {
 		return errors.New("indexing more than one column with an inverted index is not supported")
 	}
-	invalidColumns := make([]ColumnDescriptor, 0, len(indexColNames))
-	for _, indexCol := range indexColNames {
+	invalidColumns := make([]ColumnDescriptor, 0, len(idx.ColumnNames))
+	for _, indexCol := range idx.ColumnNames {
 		for _, col := range tableDesc.AllNonDropColumns() {
 			if col.Name == indexCol {
 				if !ColumnTypeIsInvertedIndexable(&col.Type) {
 					invalidColumns = append(invalidColumns, col)
+				} else if err := checkInvertedIndexOpClass(&col, idx.OpClass); err != nil {
+					return err
 				}
 			}
 		}
@@ -2247,6 +2280,27 @@ func checkColumnsValidForInvertedIndex(
 	return nil
 }
 
+// checkInvertedIndexOpClass checks that the operator class of an inverted index
+// fits the type of its column. Like in Postgres, string columns don't have a
+// default operator class, so their trigram indexes have to be requested
+// explicitly, and the other types don't accept the trigram operator class.
+func checkInvertedIndexOpClass(col *ColumnDescriptor, opClass string) error {
+	if col.Type.Family() == types.StringFamily {
+		if opClass != TrigramOpClass {
+			return errors.WithHintf(pgerror.Newf(pgcode.UndefinedObject,
+				"data type %s has no default operator class for access method %q",
+				col.Type.Name(), "gin"),
+				"You must specify an operator class for the index, e.g. %s.", TrigramOpClass)
+		}
+		return nil
+	}
+	if opClass != "" {
+		return pgerror.Newf(pgcode.DatatypeMismatch,
+			"operator class %q does not accept data type %s", opClass, col.Type.Name())
+	}
+	return nil
+}
+
 // AddColumn adds a column to the table.
 func (desc *MutableTableDescriptor) AddColumn(col *ColumnDescriptor) {
 	desc.Columns = append(desc.Columns, *col)
@@ -2280,7 +2334,7 @@ func (desc *MutableTableDescriptor) AddIndex(idx IndexDescriptor, primary bool)
 		}
 
 	} else {
-		if err := checkColumnsValidForInvertedIndex(desc, idx.ColumnNames); err != nil {
+		if err := checkColumnsValidForInvertedIndex(desc, &idx); err != nil {
 			return err
 		}
 		desc.Indexes = append(desc.Indexes, idx)
@@ -3044,7 +3098,7 @@ func (desc *MutableTableDescriptor) AddIndexMutation(
 			return err
 		}
 	case IndexDescriptor_INVERTED:
-		if err := checkColumnsValidForInvertedIndex(desc, idx.ColumnNames); err != nil {
+		if err := checkColumnsValidForInvertedIndex(desc, idx); err != nil {
 			return err
 		}
 	}
@@ -225,6 +225,53 @@ CREATE TABLE system.comments (
    comment   STRING NOT NULL, -- the comment
    PRIMARY KEY (type, object_id, sub_id)
 );`
+
+	// statement_bundle_chunks stores the chunks of the statement diagnostics
+	// bundles; a bundle is split into chunks to avoid large KV values.
+	StatementBundleChunksTableSchema = `
+CREATE TABLE system.statement_bundle_chunks (
+   id          INT8 PRIMARY KEY DEFAULT unique_rowid(),
+   description STRING,
+   data        BYTES NOT NULL,
+   FAMILY "primary" (id, description, data)
+);`
+
+	// statement_diagnostics_requests stores the requests for statement
+	// diagnostics bundles for the next execution of a statement fingerprint.
+	StatementDiagnosticsRequestsTableSchema = `
+CREATE TABLE system.statement_diagnostics_requests (
+   id                       INT8 DEFAULT unique_rowid() PRIMARY KEY NOT NULL,
+   completed                BOOL NOT NULL DEFAULT FALSE,
+   statement_fingerprint    STRING NOT NULL,
+   statement_diagnostics_id INT8,
+   requested_at             TIMESTAMPTZ NOT NULL,
+   INDEX completed_idx (completed, id) STORING (statement_fingerprint),
+   FAMILY "primary" (id, completed, statement_fingerprint, statement_diagnostics_id, requested_at)
+);`
+
+	// statement_diagnostics stores the collected statement diagnostics
+	// bundles. The bundle itself is stored in statement_bundle_chunks.
+	StatementDiagnosticsTableSchema = `
+CREATE TABLE system.statement_diagnostics (
+   id                    INT8 DEFAULT unique_rowid() PRIMARY KEY NOT NULL,
+   statement_fingerprint STRING NOT NULL,
+   statement             STRING NOT NULL,
+   collected_at          TIMESTAMPTZ NOT NULL,
+   trace                 JSONB,
+   bundle_chunks         INT8 ARRAY,
+   error                 STRING,
+   FAMILY "primary" (id, statement_fingerprint, statement, collected_at, trace, bundle_chunks, error)
+);`
+
+	// statement_hints stores the plan hints that the optimizer applies to the
+	// statements matching a statement fingerprint.
+	StatementHintsTableSchema = `
+CREATE TABLE system.statement_hints (
+   fingerprint STRING NOT NULL PRIMARY KEY,
+   hints       STRING NOT NULL,
+   created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+   FAMILY "primary" (fingerprint, hints, created_at)
+);`
 )
 
 func pk(name string) IndexDescriptor {
@@ -269,6 +316,10 @@ var SystemAllowedPrivileges = map[ID]privilege.List{
 	keys.ReplicationCriticalLocalitiesTableID: privilege.ReadWriteData,
 	keys.ReplicationStatsTableID:              privilege.ReadWriteData,
 	keys.ReportsMetaTableID:                   privilege.ReadWriteData,
+	keys.StatementBundleChunksTableID:         privilege.ReadWriteData,
+	keys.StatementDiagnosticsRequestsTableID:  privilege.ReadWriteData,
+	keys.StatementDiagnosticsTableID:          privilege.ReadWriteData,
+	keys.StatementHintsTableID:                privilege.ReadWriteData,
 }
 
 // Helpers used to make some of the TableDescriptor literals below more concise.
@@ -581,7 +632,8 @@ var (
 		NextMutationID: 1,
 	}
 
-	nowString = "now():::TIMESTAMP"
+	nowString   = "now():::TIMESTAMP"
+	nowTZString = "now():::TIMESTAMPTZ"
 
 	// JobsTable is the descriptor for the jobs table.
 	JobsTable = TableDescriptor{
@@ -1052,6 +1104,146 @@ var (
 		FormatVersion:  InterleavedFormatVersion,
 		NextMutationID: 1,
 	}
+
+	// StatementBundleChunksTable is the descriptor for the
+	// statement_bundle_chunks table.
+	StatementBundleChunksTable = TableDescriptor{
+		Name:     "statement_bundle_chunks",
+		ID:       keys.StatementBundleChunksTableID,
+		ParentID: keys.SystemDatabaseID,
+		Version:  1,
+		Columns: []ColumnDescriptor{
+			{Name: "id", ID: 1, Type: *types.Int, DefaultExpr: &uniqueRowIDString},
+			{Name: "description", ID: 2, Type: *types.String, Nullable: true},
+			{Name: "data", ID: 3, Type: *types.Bytes},
+		},
+		NextColumnID: 4,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name:        "primary",
+				ID:          0,
+				ColumnNames: []string{"id", "description", "data"},
+				ColumnIDs:   []ColumnID{1, 2, 3},
+			},
+		},
+		NextFamilyID:   1,
+		PrimaryIndex:   pk("id"),
+		NextIndexID:    2,
+		Privileges:     NewCustomSuperuserPrivilegeDescriptor(SystemAllowedPrivileges[keys.StatementBundleChunksTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
+
+	// StatementDiagnosticsRequestsTable is the descriptor for the
+	// statement_diagnostics_requests table.
+	StatementDiagnosticsRequestsTable = TableDescriptor{
+		Name:     "statement_diagnostics_requests",
+		ID:       keys.StatementDiagnosticsRequestsTableID,
+		ParentID: keys.SystemDatabaseID,
+		Version:  1,
+		Columns: []ColumnDescriptor{
+			{Name: "id", ID: 1, Type: *types.Int, DefaultExpr: &uniqueRowIDString},
+			{Name: "completed", ID: 2, Type: *types.Bool, DefaultExpr: &falseBoolString},
+			{Name: "statement_fingerprint", ID: 3, Type: *types.String},
+			{Name: "statement_diagnostics_id", ID: 4, Type: *types.Int, Nullable: true},
+			{Name: "requested_at", ID: 5, Type: *types.TimestampTZ},
+		},
+		NextColumnID: 6,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name: "primary",
+				ID:   0,
+				ColumnNames: []string{
+					"id", "completed", "statement_fingerprint", "statement_diagnostics_id", "requested_at",
+				},
+				ColumnIDs: []ColumnID{1, 2, 3, 4, 5},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: pk("id"),
+		// Index for the polling query.
+		Indexes: []IndexDescriptor{
+			{
+				Name:             "completed_idx",
+				ID:               2,
+				Unique:           false,
+				ColumnNames:      []string{"completed", "id"},
+				ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC, IndexDescriptor_ASC},
+				ColumnIDs:        []ColumnID{2, 1},
+				StoreColumnNames: []string{"statement_fingerprint"},
+				StoreColumnIDs:   []ColumnID{3},
+			},
+		},
+		NextIndexID: 3,
+		Privileges: NewCustomSuperuserPrivilegeDescriptor(
+			SystemAllowedPrivileges[keys.StatementDiagnosticsRequestsTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
+
+	// StatementDiagnosticsTable is the descriptor for the
+	// statement_diagnostics table.
+	StatementDiagnosticsTable = TableDescriptor{
+		Name:     "statement_diagnostics",
+		ID:       keys.StatementDiagnosticsTableID,
+		ParentID: keys.SystemDatabaseID,
+		Version:  1,
+		Columns: []ColumnDescriptor{
+			{Name: "id", ID: 1, Type: *types.Int, DefaultExpr: &uniqueRowIDString},
+			{Name: "statement_fingerprint", ID: 2, Type: *types.String},
+			{Name: "statement", ID: 3, Type: *types.String},
+			{Name: "collected_at", ID: 4, Type: *types.TimestampTZ},
+			{Name: "trace", ID: 5, Type: *types.Jsonb, Nullable: true},
+			{Name: "bundle_chunks", ID: 6, Type: *types.IntArray, Nullable: true},
+			{Name: "error", ID: 7, Type: *types.String, Nullable: true},
+		},
+		NextColumnID: 8,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name: "primary",
+				ID:   0,
+				ColumnNames: []string{
+					"id", "statement_fingerprint", "statement",
+					"collected_at", "trace", "bundle_chunks", "error",
+				},
+				ColumnIDs: []ColumnID{1, 2, 3, 4, 5, 6, 7},
+			},
+		},
+		NextFamilyID:   1,
+		PrimaryIndex:   pk("id"),
+		NextIndexID:    2,
+		Privileges:     NewCustomSuperuserPrivilegeDescriptor(SystemAllowedPrivileges[keys.StatementDiagnosticsTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
+
+	// StatementHintsTable is the descriptor for the statement_hints table.
+	StatementHintsTable = TableDescriptor{
+		Name:     "statement_hints",
+		ID:       keys.StatementHintsTableID,
+		ParentID: keys.SystemDatabaseID,
+		Version:  1,
+		Columns: []ColumnDescriptor{
+			{Name: "fingerprint", ID: 1, Type: *types.String},
+			{Name: "hints", ID: 2, Type: *types.String},
+			{Name: "created_at", ID: 3, Type: *types.TimestampTZ, DefaultExpr: &nowTZString},
+		},
+		NextColumnID: 4,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name:        "primary",
+				ID:          0,
+				ColumnNames: []string{"fingerprint", "hints", "created_at"},
+				ColumnIDs:   []ColumnID{1, 2, 3},
+			},
+		},
+		NextFamilyID:   1,
+		PrimaryIndex:   pk("fingerprint"),
+		NextIndexID:    2,
+		Privileges:     NewCustomSuperuserPrivilegeDescriptor(SystemAllowedPrivileges[keys.StatementHintsTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
 )
 
 // Create a kv pair for the zone config for the given key and config value.
@@ -1101,6 +1293,12 @@ func addSystemDescriptorsToSchema(target *MetadataSchema) {
 	target.AddDescriptor(keys.SystemDatabaseID, &ReplicationConstraintStatsTable)
 	target.AddDescriptor(keys.SystemDatabaseID, &ReplicationStatsTable)
 	target.AddDescriptor(keys.SystemDatabaseID, &ReplicationCriticalLocalitiesTable)
+
+	// Tables introduced in 20.1.
+	target.AddDescriptor(keys.SystemDatabaseID, &StatementBundleChunksTable)
+	target.AddDescriptor(keys.SystemDatabaseID, &StatementDiagnosticsRequestsTable)
+	target.AddDescriptor(keys.SystemDatabaseID, &StatementDiagnosticsTable)
+	target.AddDescriptor(keys.SystemDatabaseID, &StatementHintsTable)
 }
 
 // addSystemDatabaseToSchema populates the supplied MetadataSchema with the
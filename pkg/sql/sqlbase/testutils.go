@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/geo"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -37,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/lib/pq/oid"
@@ -192,6 +194,22 @@ func RandDatumWithNullChance(rng *rand.Rand, typ *types.T, nullChance int) tree.
 			return nil
 		}
 		return &tree.DJSON{JSON: j}
+	case types.TSVectorFamily:
+		return tree.NewDTSVector(randTSVector(rng))
+	case types.TSQueryFamily:
+		return tree.NewDTSQuery(randTSQuery(rng))
+	case types.GeometryFamily:
+		g, err := geo.MakeGeometry(0, randPoint(rng, 1000, 1000))
+		if err != nil {
+			panic(err)
+		}
+		return tree.NewDGeometry(g)
+	case types.GeographyFamily:
+		g, err := geo.MakeGeography(geo.DefaultGeographySRID, randPoint(rng, 180, 90))
+		if err != nil {
+			panic(err)
+		}
+		return tree.NewDGeography(g)
 	case types.TupleFamily:
 		tuple := tree.DTuple{D: make(tree.Datums, len(typ.TupleContents()))}
 		for i := range typ.TupleContents() {
@@ -305,6 +323,22 @@ func RandDatumSimple(rng *rand.Rand, typ *types.T) tree.Datum {
 		})
 	case types.JsonFamily:
 		datum = tree.NewDJSON(randJSONSimple(rng))
+	case types.TSVectorFamily:
+		datum = tree.NewDTSVector(tsearch.MakeTSVector([]tsearch.Lexeme{{Word: randStringSimple(rng)}}))
+	case types.TSQueryFamily:
+		datum = tree.NewDTSQuery(tsearch.TSQuery{Root: &tsearch.Node{Op: tsearch.Term, Lexeme: randStringSimple(rng)}})
+	case types.GeometryFamily:
+		g, _ := geo.MakeGeometry(0, geo.Shape{
+			Type:  geo.Point,
+			Parts: [][][]geo.Coord{{{{X: float64(rng.Intn(simpleRange)), Y: 0}}}},
+		})
+		datum = tree.NewDGeometry(g)
+	case types.GeographyFamily:
+		g, _ := geo.MakeGeography(geo.DefaultGeographySRID, geo.Shape{
+			Type:  geo.Point,
+			Parts: [][][]geo.Coord{{{{X: float64(rng.Intn(simpleRange)), Y: 0}}}},
+		})
+		datum = tree.NewDGeography(g)
 	case types.OidFamily:
 		datum = tree.NewDOid(tree.DInt(rng.Intn(simpleRange)))
 	case types.StringFamily:
@@ -327,6 +361,64 @@ func randStringSimple(rng *rand.Rand) string {
 	return string('A' + rng.Intn(simpleRange))
 }
 
+// randTSVector returns a random tsvector with up to 10 lexemes, each with up to
+// 3 positions.
+func randTSVector(rng *rand.Rand) tsearch.TSVector {
+	lexemes := make([]tsearch.Lexeme, rng.Intn(10))
+	for i := range lexemes {
+		lexemes[i].Word = randTSLexeme(rng)
+		for j := rng.Intn(4); j > 0; j-- {
+			lexemes[i].Positions = append(lexemes[i].Positions, tsearch.Position{
+				Pos:    uint16(1 + rng.Intn(tsearch.MaxPos)),
+				Weight: tsearch.Weight(rng.Intn(4)),
+			})
+		}
+	}
+	return tsearch.MakeTSVector(lexemes)
+}
+
+// randPoint returns a random point with coordinates between -maxX and maxX,
+// and -maxY and maxY.
+func randPoint(rng *rand.Rand, maxX, maxY float64) geo.Shape {
+	c := geo.Coord{X: (2*rng.Float64() - 1) * maxX, Y: (2*rng.Float64() - 1) * maxY}
+	return geo.Shape{Type: geo.Point, Parts: [][][]geo.Coord{{{c}}}}
+}
+
+// randTSQuery returns a random tsquery with up to 3 levels of operators.
+func randTSQuery(rng *rand.Rand) tsearch.TSQuery {
+	var gen func(depth int) *tsearch.Node
+	gen = func(depth int) *tsearch.Node {
+		if depth == 0 || rng.Intn(3) == 0 {
+			return &tsearch.Node{
+				Op:      tsearch.Term,
+				Lexeme:  randTSLexeme(rng),
+				Prefix:  rng.Intn(4) == 0,
+				Weights: uint8(rng.Intn(16)),
+			}
+		}
+		switch rng.Intn(3) {
+		case 0:
+			return &tsearch.Node{Op: tsearch.Not, Left: gen(depth - 1)}
+		case 1:
+			return &tsearch.Node{Op: tsearch.And, Left: gen(depth - 1), Right: gen(depth - 1)}
+		default:
+			return &tsearch.Node{Op: tsearch.Or, Left: gen(depth - 1), Right: gen(depth - 1)}
+		}
+	}
+	return tsearch.TSQuery{Root: gen(3)}
+}
+
+// randTSLexeme returns a random non-empty lexeme, which may contain characters
+// that must be quoted.
+func randTSLexeme(rng *rand.Rand) string {
+	const chars = "abcdefg '\\:&"
+	b := make([]byte, 1+rng.Intn(5))
+	for i := range b {
+		b[i] = chars[rng.Intn(len(chars))]
+	}
+	return string(b)
+}
+
 func randJSONSimple(rng *rand.Rand) json.JSON {
 	switch rng.Intn(10) {
 	case 0:
@@ -44,6 +44,10 @@ var LookupJoinHintUseCounter = telemetry.GetCounterOnce("sql.plan.hints.lookup-j
 // hint.
 var IndexHintUseCounter = telemetry.GetCounterOnce("sql.plan.hints.index")
 
+// StatementHintsUseCounter is to be incremented whenever a query is planned
+// with the plan hints attached to its statement fingerprint.
+var StatementHintsUseCounter = telemetry.GetCounterOnce("sql.plan.hints.statement")
+
 // InterleavedTableJoinCounter is to be incremented whenever an InterleavedTableJoin is planned.
 var InterleavedTableJoinCounter = telemetry.GetCounterOnce("sql.plan.interleaved-table-join")
 
@@ -57,6 +61,10 @@ var ExplainDistSQLUseCounter = telemetry.GetCounterOnce("sql.plan.explain-distsq
 // ExplainAnalyzeUseCounter is to be incremented whenever EXPLAIN ANALYZE is run.
 var ExplainAnalyzeUseCounter = telemetry.GetCounterOnce("sql.plan.explain-analyze")
 
+// ExplainAnalyzeDebugUseCounter is to be incremented whenever
+// EXPLAIN ANALYZE (DEBUG) is run.
+var ExplainAnalyzeDebugUseCounter = telemetry.GetCounterOnce("sql.plan.explain-analyze-debug")
+
 // ExplainOptUseCounter is to be incremented whenever EXPLAIN (OPT) is run.
 var ExplainOptUseCounter = telemetry.GetCounterOnce("sql.plan.explain-opt")
 
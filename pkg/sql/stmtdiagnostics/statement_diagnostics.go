@@ -0,0 +1,343 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package stmtdiagnostics manages statement diagnostics bundles: requests for
+// collecting a bundle on the next execution of a statement fingerprint, and
+// the storage of the collected bundles in the system tables.
+package stmtdiagnostics
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+var pollingInterval = settings.RegisterNonNegativeDurationSetting(
+	"sql.stmt_diagnostics.poll_interval",
+	"rate at which the stmtdiagnostics.Registry polls for requests, set to zero to disable",
+	10*time.Second)
+
+// bundleChunkSize is the maximum size of a chunk of a bundle stored in
+// system.statement_bundle_chunks.
+const bundleChunkSize = 128 * 1024
+
+// RequestID is the ID of a diagnostics request, as stored in the id column of
+// system.statement_diagnostics_requests.
+type RequestID int64
+
+// CollectedInstanceID is the ID of a collected diagnostics bundle, as stored
+// in the id column of system.statement_diagnostics.
+type CollectedInstanceID int64
+
+// Registry maintains a view on the statement fingerprints on which data is to
+// be collected (i.e. system.statement_diagnostics_requests) and provides
+// utilities for checking a query against this list and satisfying the
+// requests.
+type Registry struct {
+	mu struct {
+		syncutil.Mutex
+		// requestFingerprints contains the requests that have not been
+		// satisfied yet, keyed by request ID.
+		requestFingerprints map[RequestID]string
+		// ongoing contains the requests for which a bundle is currently being
+		// collected on this node.
+		ongoing map[RequestID]struct{}
+	}
+	st *cluster.Settings
+	ie sqlutil.InternalExecutor
+	db *client.DB
+}
+
+// NewRegistry constructs a new Registry.
+func NewRegistry(ie sqlutil.InternalExecutor, db *client.DB, st *cluster.Settings) *Registry {
+	r := &Registry{
+		ie: ie,
+		db: db,
+		st: st,
+	}
+	r.mu.requestFingerprints = make(map[RequestID]string)
+	r.mu.ongoing = make(map[RequestID]struct{})
+	return r
+}
+
+// Start starts the polling loop for the registry, which periodically refreshes
+// the set of outstanding requests from the system table so that requests
+// inserted on other nodes are picked up.
+func (r *Registry) Start(ctx context.Context, stopper *stop.Stopper) {
+	ctx, _ = stopper.WithCancelOnQuiesce(ctx)
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			interval := pollingInterval.Get(&r.st.SV)
+			if interval == 0 {
+				// Polling is disabled; check the setting again later.
+				interval = time.Minute
+			}
+			timer.Reset(interval)
+			select {
+			case <-stopper.ShouldQuiesce():
+				return
+			case <-timer.C:
+				timer.Read = true
+			}
+			if pollingInterval.Get(&r.st.SV) == 0 || !r.enabled(ctx) {
+				continue
+			}
+			if err := r.pollRequests(ctx); err != nil {
+				log.Warningf(ctx, "error polling for statement diagnostics requests: %s", err)
+			}
+		}
+	})
+}
+
+// enabled returns whether the system tables used by the registry exist.
+func (r *Registry) enabled(ctx context.Context) bool {
+	return cluster.Version.IsActive(ctx, r.st, cluster.VersionStatementDiagnosticsSystemTables)
+}
+
+// InsertRequest adds an entry to system.statement_diagnostics_requests for
+// tracing a query with the given fingerprint. Once this returns, calling
+// ShouldCollectDiagnostics() on the current node will return true for the
+// given fingerprint.
+func (r *Registry) InsertRequest(ctx context.Context, fingerprint string) (RequestID, error) {
+	if !r.enabled(ctx) {
+		return 0, errors.New(
+			"statement diagnostics can only be requested after the cluster version upgrade is finalized")
+	}
+	var reqID RequestID
+	err := r.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		// Check if there's already a pending request for this fingerprint.
+		row, err := r.ie.QueryRow(ctx, "stmt-diag-check-pending", txn,
+			`SELECT count(1) FROM system.statement_diagnostics_requests
+			 WHERE completed = false AND statement_fingerprint = $1`,
+			fingerprint)
+		if err != nil {
+			return err
+		}
+		if count := int(*row[0].(*tree.DInt)); count != 0 {
+			return errors.Newf("a pending request for the requested fingerprint already exists")
+		}
+
+		row, err = r.ie.QueryRow(ctx, "stmt-diag-insert-request", txn,
+			`INSERT INTO system.statement_diagnostics_requests (statement_fingerprint, requested_at)
+			 VALUES ($1, now()) RETURNING id`,
+			fingerprint)
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			return errors.New("failed to insert statement diagnostics request")
+		}
+		reqID = RequestID(*row[0].(*tree.DInt))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Manually insert the request in the (local) registry. This lets this node
+	// pick up the request quickly if the right query comes around, without
+	// waiting for the poller.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.requestFingerprints[reqID] = fingerprint
+	return reqID, nil
+}
+
+// ShouldCollectDiagnostics checks whether any data should be collected for
+// the given query. If data is to be collected, the returned ID is the ID of
+// the request being satisfied; the request is marked as ongoing so that other
+// executions of the same fingerprint on this node don't also collect it. The
+// caller must call RemoveOngoing once it is done.
+func (r *Registry) ShouldCollectDiagnostics(ast tree.Statement) (bool, RequestID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Return quickly if we have no requests to trace; this avoids computing the
+	// fingerprint of every statement.
+	if len(r.mu.requestFingerprints) == 0 {
+		return false, 0
+	}
+
+	fingerprint := tree.AsStringWithFlags(ast, tree.FmtHideConstants)
+	for id, f := range r.mu.requestFingerprints {
+		if f == fingerprint {
+			if _, ok := r.mu.ongoing[id]; ok {
+				continue
+			}
+			r.mu.ongoing[id] = struct{}{}
+			return true, id
+		}
+	}
+	return false, 0
+}
+
+// RemoveOngoing marks the collection for the given request as finished on this
+// node. If the collection did not succeed, the request becomes eligible again.
+func (r *Registry) RemoveOngoing(requestID RequestID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.mu.ongoing, requestID)
+}
+
+// InsertStatementDiagnostics inserts a collected bundle into
+// system.statement_diagnostics (with the bundle itself stored in
+// system.statement_bundle_chunks). If requestID is not zero, the corresponding
+// request is also marked as completed; if the request was already completed by
+// another node, nothing is inserted and a zero ID is returned.
+//
+// collectionErr, if set, is the error encountered while building the bundle;
+// it is recorded in place of (or in addition to) the bundle.
+func (r *Registry) InsertStatementDiagnostics(
+	ctx context.Context,
+	requestID RequestID,
+	stmtFingerprint string,
+	stmt string,
+	traceJSON string,
+	bundle []byte,
+	collectionErr error,
+) (CollectedInstanceID, error) {
+	if !r.enabled(ctx) {
+		return 0, errors.New(
+			"statement diagnostics can only be collected after the cluster version upgrade is finalized")
+	}
+	var diagID CollectedInstanceID
+	err := r.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		if requestID != 0 {
+			row, err := r.ie.QueryRow(ctx, "stmt-diag-check-completed", txn,
+				"SELECT count(1) FROM system.statement_diagnostics_requests WHERE id = $1 AND completed = false",
+				requestID)
+			if err != nil {
+				return err
+			}
+			if count := int(*row[0].(*tree.DInt)); count == 0 {
+				// Someone else already marked the request as completed. We've
+				// traced for nothing.
+				diagID = 0
+				return nil
+			}
+		}
+
+		// Insert the bundle into system.statement_bundle_chunks.
+		bundleChunksVal := tree.NewDArray(types.Int)
+		for len(bundle) > 0 {
+			chunk := bundle
+			if len(chunk) > bundleChunkSize {
+				chunk = chunk[:bundleChunkSize]
+			}
+			bundle = bundle[len(chunk):]
+
+			row, err := r.ie.QueryRow(
+				ctx, "stmt-bundle-chunks-insert", txn,
+				"INSERT INTO system.statement_bundle_chunks(description, data) VALUES ($1, $2) RETURNING id",
+				"statement diagnostics bundle",
+				tree.NewDBytes(tree.DBytes(chunk)),
+			)
+			if err != nil {
+				return err
+			}
+			chunkID := row[0].(*tree.DInt)
+			if err := bundleChunksVal.Append(chunkID); err != nil {
+				return err
+			}
+		}
+
+		errorVal := tree.DNull
+		if collectionErr != nil {
+			errorVal = tree.NewDString(collectionErr.Error())
+		}
+		traceVal := tree.DNull
+		if traceJSON != "" {
+			var err error
+			traceVal, err = tree.ParseDJSON(traceJSON)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Insert the collection metadata into system.statement_diagnostics.
+		row, err := r.ie.QueryRow(
+			ctx, "stmt-diag-insert", txn,
+			`INSERT INTO system.statement_diagnostics
+			 (statement_fingerprint, statement, collected_at, trace, bundle_chunks, error)
+			 VALUES ($1, $2, now(), $3, $4, $5) RETURNING id`,
+			stmtFingerprint, stmt, traceVal, bundleChunksVal, errorVal,
+		)
+		if err != nil {
+			return err
+		}
+		diagID = CollectedInstanceID(*row[0].(*tree.DInt))
+
+		if requestID != 0 {
+			// Mark the request from system.statement_diagnostics_request as
+			// completed.
+			_, err := r.ie.Exec(ctx, "stmt-diag-mark-completed", txn,
+				"UPDATE system.statement_diagnostics_requests "+
+					"SET completed = true, statement_diagnostics_id = $1 WHERE id = $2",
+				diagID, requestID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if requestID != 0 {
+		// Remove the request from the local registry; the poller would also
+		// remove it eventually.
+		r.mu.Lock()
+		delete(r.mu.requestFingerprints, requestID)
+		r.mu.Unlock()
+	}
+	return diagID, nil
+}
+
+// pollRequests reads the pending rows from system.statement_diagnostics_requests
+// and updates r.mu.requestFingerprints accordingly.
+func (r *Registry) pollRequests(ctx context.Context) error {
+	rows, err := r.ie.Query(ctx, "stmt-diag-poll", nil, /* txn */
+		`SELECT id, statement_fingerprint FROM system.statement_diagnostics_requests
+		 WHERE completed = false`)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make(map[RequestID]struct{}, len(rows))
+	for _, row := range rows {
+		id := RequestID(*row[0].(*tree.DInt))
+		fprint := string(*row[1].(*tree.DString))
+		ids[id] = struct{}{}
+		r.mu.requestFingerprints[id] = fprint
+	}
+	// Remove the requests that were completed (possibly by other nodes).
+	for id := range r.mu.requestFingerprints {
+		if _, ok := ids[id]; !ok {
+			delete(r.mu.requestFingerprints, id)
+		}
+	}
+	return nil
+}
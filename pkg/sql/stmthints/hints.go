@@ -0,0 +1,134 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stmthints
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
+)
+
+// Hints is a set of plan hints attached to a statement fingerprint. Hints are
+// specified as a list of directives separated by commas or spaces:
+//
+//   INDEX(<table>@<index>)  - scan the table using the given index, as if
+//                             the table were written as <table>@<index>.
+//   NO_INDEX_JOIN(<table>)  - don't use index joins for the table, as if it
+//                             were written as <table>@{NO_INDEX_JOIN}.
+//   NO_RULE(<rule>)         - disable the given exploration rule of the
+//                             optimizer (e.g. GenerateMergeJoins).
+//
+// Table names are unqualified and match all the references to tables with
+// that name that don't already have an inline index hint.
+type Hints struct {
+	// Text is the textual representation of the hints, as stored in
+	// system.statement_hints.
+	Text string
+
+	// IndexFlags contains the index flags to apply to table references, keyed
+	// by table name.
+	IndexFlags map[tree.Name]*tree.IndexFlags
+
+	// DisabledRules is the set of optimizer rules (opt.RuleName) that must not
+	// be applied.
+	DisabledRules util.FastIntSet
+}
+
+var hintRegexp = regexp.MustCompile(`([A-Za-z_]+)\s*\(\s*([^()]*?)\s*\)`)
+
+// Parse parses the textual representation of a set of hints.
+func Parse(text string) (*Hints, error) {
+	h := &Hints{Text: strings.TrimSpace(text)}
+	matches := hintRegexp.FindAllStringSubmatchIndex(h.Text, -1)
+	if len(matches) == 0 {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue, "no hints specified in %q", text)
+	}
+	prevEnd := 0
+	for _, m := range matches {
+		if sep := h.Text[prevEnd:m[0]]; strings.Trim(sep, ", \t\n") != "" {
+			return nil, pgerror.Newf(pgcode.Syntax, "invalid hint syntax at %q", sep)
+		}
+		prevEnd = m[1]
+		name, arg := h.Text[m[2]:m[3]], h.Text[m[4]:m[5]]
+		if err := h.add(strings.ToUpper(name), arg); err != nil {
+			return nil, err
+		}
+	}
+	if sep := h.Text[prevEnd:]; strings.Trim(sep, ", \t\n") != "" {
+		return nil, pgerror.Newf(pgcode.Syntax, "invalid hint syntax at %q", sep)
+	}
+	for name, flags := range h.IndexFlags {
+		if flags.ForceIndex() && flags.NoIndexJoin {
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"INDEX and NO_INDEX_JOIN hints cannot both be specified for table %s", name)
+		}
+	}
+	return h, nil
+}
+
+func (h *Hints) add(name, arg string) error {
+	switch name {
+	case "INDEX":
+		parts := strings.Split(arg, "@")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return pgerror.New(pgcode.Syntax, "INDEX hint must be of the form INDEX(<table>@<index>)")
+		}
+		h.indexFlags(parts[0]).Index = tree.UnrestrictedName(parts[1])
+
+	case "NO_INDEX_JOIN":
+		if arg == "" || strings.Contains(arg, "@") {
+			return pgerror.New(pgcode.Syntax, "NO_INDEX_JOIN hint must be of the form NO_INDEX_JOIN(<table>)")
+		}
+		h.indexFlags(arg).NoIndexJoin = true
+
+	case "NO_RULE":
+		rule, err := ruleFromString(arg)
+		if err != nil {
+			return err
+		}
+		if !rule.IsExplore() {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"only exploration rules can be disabled, %s is a normalization rule", arg)
+		}
+		h.DisabledRules.Add(int(rule))
+
+	default:
+		return pgerror.Newf(pgcode.InvalidParameterValue, "unknown hint %s", name)
+	}
+	return nil
+}
+
+func (h *Hints) indexFlags(table string) *tree.IndexFlags {
+	if h.IndexFlags == nil {
+		h.IndexFlags = make(map[tree.Name]*tree.IndexFlags)
+	}
+	name := tree.Name(table)
+	flags, ok := h.IndexFlags[name]
+	if !ok {
+		flags = &tree.IndexFlags{}
+		h.IndexFlags[name] = flags
+	}
+	return flags
+}
+
+func ruleFromString(str string) (opt.RuleName, error) {
+	for i := opt.RuleName(1); i < opt.NumRuleNames; i++ {
+		if i.String() == str {
+			return i, nil
+		}
+	}
+	return opt.InvalidRuleName, pgerror.Newf(pgcode.InvalidParameterValue, "unknown optimizer rule %s", str)
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stmthints
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestParse(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	h, err := Parse(" INDEX(t@t_b_idx), no_index_join(u) NO_RULE(GenerateMergeJoins) ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Text != "INDEX(t@t_b_idx), no_index_join(u) NO_RULE(GenerateMergeJoins)" {
+		t.Errorf("unexpected text %q", h.Text)
+	}
+	if f := h.IndexFlags["t"]; f == nil || f.Index != "t_b_idx" || f.NoIndexJoin {
+		t.Errorf("unexpected flags for t: %+v", f)
+	}
+	if f := h.IndexFlags["u"]; f == nil || f.ForceIndex() || !f.NoIndexJoin {
+		t.Errorf("unexpected flags for u: %+v", f)
+	}
+	if h.DisabledRules.Len() != 1 || !h.DisabledRules.Contains(int(opt.GenerateMergeJoins)) {
+		t.Errorf("unexpected disabled rules: %s", h.DisabledRules)
+	}
+	if _, ok := h.IndexFlags[tree.Name("v")]; ok {
+		t.Errorf("unexpected flags for v")
+	}
+
+	for _, tc := range []struct {
+		text string
+		err  string
+	}{
+		{``, `no hints specified`},
+		{`INDEX(t)`, `INDEX hint must be of the form`},
+		{`INDEX(t@i) garbage`, `invalid hint syntax at " garbage"`},
+		{`garbage INDEX(t@i)`, `invalid hint syntax at "garbage "`},
+		{`NO_INDEX_JOIN(t@i)`, `NO_INDEX_JOIN hint must be of the form`},
+		{`INDEX(t@i), NO_INDEX_JOIN(t)`, `cannot both be specified for table t`},
+		{`NO_RULE(Foo)`, `unknown optimizer rule Foo`},
+		{`NO_RULE(EliminateProject)`, `only exploration rules can be disabled`},
+		{`FOO(bar)`, `unknown hint FOO`},
+	} {
+		t.Run(tc.text, func(t *testing.T) {
+			_, err := Parse(tc.text)
+			if !testutils.IsError(err, tc.err) {
+				t.Errorf("expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
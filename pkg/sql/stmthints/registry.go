@@ -0,0 +1,195 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package stmthints manages the plan hints attached to statement fingerprints
+// (stored in system.statement_hints). The optimizer applies the hints to the
+// statements matching a fingerprint, which allows operators to fix a bad plan
+// without changing the application SQL.
+//
+// Only the hints described in Hints are supported. A fingerprint can't be
+// pinned to a stored memo or plan: the hints steer the optimizer, which
+// still plans each execution of the statement.
+package stmthints
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+var pollingInterval = settings.RegisterNonNegativeDurationSetting(
+	"sql.stmt_hints.poll_interval",
+	"rate at which the stmthints.Registry polls for changes to the statement hints, set to zero to disable",
+	10*time.Second)
+
+// Registry maintains an in-memory view of system.statement_hints, refreshed
+// periodically, and allows looking up the hints for a statement.
+type Registry struct {
+	mu struct {
+		syncutil.RWMutex
+		// hints contains the parsed hints, keyed by statement fingerprint.
+		hints map[string]*Hints
+	}
+	st *cluster.Settings
+	ie sqlutil.InternalExecutor
+	db *client.DB
+}
+
+// NewRegistry constructs a new Registry.
+func NewRegistry(ie sqlutil.InternalExecutor, db *client.DB, st *cluster.Settings) *Registry {
+	r := &Registry{
+		ie: ie,
+		db: db,
+		st: st,
+	}
+	r.mu.hints = make(map[string]*Hints)
+	return r
+}
+
+// Start starts the polling loop for the registry, which loads the hints from
+// the system table right away and then periodically refreshes them so that
+// changes made on other nodes are picked up.
+func (r *Registry) Start(ctx context.Context, stopper *stop.Stopper) {
+	ctx, _ = stopper.WithCancelOnQuiesce(ctx)
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			if pollingInterval.Get(&r.st.SV) != 0 && r.enabled(ctx) {
+				if err := r.refresh(ctx); err != nil {
+					log.Warningf(ctx, "error polling for statement hints: %s", err)
+				}
+			}
+			interval := pollingInterval.Get(&r.st.SV)
+			if interval == 0 {
+				// Polling is disabled; check the setting again later.
+				interval = time.Minute
+			}
+			timer.Reset(interval)
+			select {
+			case <-stopper.ShouldQuiesce():
+				return
+			case <-timer.C:
+				timer.Read = true
+			}
+		}
+	})
+}
+
+// enabled returns whether the system table used by the registry exists.
+func (r *Registry) enabled(ctx context.Context) bool {
+	return cluster.Version.IsActive(ctx, r.st, cluster.VersionStatementHintsSystemTable)
+}
+
+// Lookup returns the hints for the given statement, or nil if there are no
+// hints for its fingerprint.
+func (r *Registry) Lookup(ast tree.Statement) *Hints {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Return quickly if there are no hints; this avoids computing the
+	// fingerprint of every statement.
+	if len(r.mu.hints) == 0 {
+		return nil
+	}
+	return r.mu.hints[tree.AsStringWithFlags(ast, tree.FmtHideConstants)]
+}
+
+// SetHints attaches the given hints to a statement fingerprint, replacing any
+// existing hints for it. If hintsText is empty, the hints for the fingerprint
+// are removed. The fingerprint is normalized the way Lookup fingerprints
+// statements, so it can be given with any constants, case and spacing. The
+// change takes effect immediately on this node and within the polling interval
+// on the other nodes.
+func (r *Registry) SetHints(ctx context.Context, fingerprint string, hintsText string) error {
+	if !r.enabled(ctx) {
+		return errors.New(
+			"statement hints can only be used after the cluster version upgrade is finalized")
+	}
+	fingerprint, err := normalizeFingerprint(fingerprint)
+	if err != nil {
+		return err
+	}
+	var hints *Hints
+	if hintsText != "" {
+		if hints, err = Parse(hintsText); err != nil {
+			return err
+		}
+		if _, err := r.ie.Exec(ctx, "stmt-hints-upsert", nil, /* txn */
+			"UPSERT INTO system.statement_hints (fingerprint, hints, created_at) VALUES ($1, $2, now())",
+			fingerprint, hints.Text,
+		); err != nil {
+			return err
+		}
+	} else {
+		if _, err := r.ie.Exec(ctx, "stmt-hints-delete", nil, /* txn */
+			"DELETE FROM system.statement_hints WHERE fingerprint = $1", fingerprint,
+		); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if hints != nil {
+		r.mu.hints[fingerprint] = hints
+	} else {
+		delete(r.mu.hints, fingerprint)
+	}
+	return nil
+}
+
+// normalizeFingerprint parses a statement fingerprint and formats it with the
+// constants hidden, which is how Lookup fingerprints statements.
+func normalizeFingerprint(fingerprint string) (string, error) {
+	stmt, err := parser.ParseOne(fingerprint)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid statement fingerprint %q", fingerprint)
+	}
+	return tree.AsStringWithFlags(stmt.AST, tree.FmtHideConstants), nil
+}
+
+// refresh reads system.statement_hints and replaces r.mu.hints accordingly.
+// Rows with hints that can't be parsed are skipped.
+func (r *Registry) refresh(ctx context.Context) error {
+	rows, err := r.ie.Query(ctx, "stmt-hints-poll", nil, /* txn */
+		"SELECT fingerprint, hints FROM system.statement_hints")
+	if err != nil {
+		return err
+	}
+
+	newHints := make(map[string]*Hints, len(rows))
+	for _, row := range rows {
+		fingerprint := string(*row[0].(*tree.DString))
+		text := string(*row[1].(*tree.DString))
+		hints, err := Parse(text)
+		if err != nil {
+			log.Warningf(ctx, "ignoring invalid hints %q for statement %q: %v", text, fingerprint, err)
+			continue
+		}
+		newHints[fingerprint] = hints
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.hints = newHints
+	return nil
+}
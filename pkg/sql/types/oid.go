@@ -11,6 +11,7 @@
 package types
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/sql/oidext"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -85,10 +86,15 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_timetz:       TimeTZ,
 	oid.T_timestamp:    Timestamp,
 	oid.T_timestamptz:  TimestampTZ,
+	oid.T_tsquery:      TSQuery,
+	oid.T_tsvector:     TSVector,
 	oid.T_unknown:      Unknown,
 	oid.T_uuid:         Uuid,
 	oid.T_varbit:       VarBit,
 	oid.T_varchar:      VarChar,
+
+	oidext.T_geometry:  Geometry,
+	oidext.T_geography: Geography,
 }
 
 // oidToArrayOid maps scalar type Oids to their corresponding array type Oid.
@@ -124,9 +130,14 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oid.T_timetz:       oid.T__timetz,
 	oid.T_timestamp:    oid.T__timestamp,
 	oid.T_timestamptz:  oid.T__timestamptz,
+	oid.T_tsquery:      oid.T__tsquery,
+	oid.T_tsvector:     oid.T__tsvector,
 	oid.T_uuid:         oid.T__uuid,
 	oid.T_varbit:       oid.T__varbit,
 	oid.T_varchar:      oid.T__varchar,
+
+	oidext.T_geometry:  oidext.T__geometry,
+	oidext.T_geography: oidext.T__geography,
 }
 
 // familyToOid maps each type family to a default OID value that is used when
@@ -154,6 +165,10 @@ var familyToOid = map[Family]oid.Oid{
 	JsonFamily:           oid.T_jsonb,
 	TupleFamily:          oid.T_record,
 	BitFamily:            oid.T_bit,
+	TSVectorFamily:       oid.T_tsvector,
+	TSQueryFamily:        oid.T_tsquery,
+	GeometryFamily:       oidext.T_geometry,
+	GeographyFamily:      oidext.T_geography,
 	AnyFamily:            oid.T_anyelement,
 }
 
@@ -16,6 +16,7 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/oidext"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/errors"
@@ -308,6 +309,39 @@ var (
 	INet = &T{InternalType: InternalType{
 		Family: INetFamily, Oid: oid.T_inet, Locale: &emptyLocale}}
 
+	// TSVector is the type of a full text search document, which is a sorted
+	// list of normalized lexemes along with their positions. For example:
+	//
+	//   'cat':3 'fat':2 'rat':4
+	//
+	TSVector = &T{InternalType: InternalType{
+		Family: TSVectorFamily, Oid: oid.T_tsvector, Locale: &emptyLocale}}
+
+	// TSQuery is the type of a full text search query, which combines lexemes
+	// with boolean operators. For example:
+	//
+	//   'fat' & ( 'rat' | 'cat' )
+	//
+	TSQuery = &T{InternalType: InternalType{
+		Family: TSQueryFamily, Oid: oid.T_tsquery, Locale: &emptyLocale}}
+
+	// Geometry is the type of a spatial shape on a planar coordinate system,
+	// along with its spatial reference identifier (SRID). For example:
+	//
+	//   POINT(1 2)
+	//   SRID=3857;POLYGON((0 0, 1 0, 1 1, 0 1, 0 0))
+	//
+	Geometry = &T{InternalType: InternalType{
+		Family: GeometryFamily, Oid: oidext.T_geometry, Locale: &emptyLocale}}
+
+	// Geography is the type of a spatial shape on the surface of the Earth,
+	// with longitude and latitude coordinates in degrees. For example:
+	//
+	//   POINT(-73.98 40.75)
+	//
+	Geography = &T{InternalType: InternalType{
+		Family: GeographyFamily, Oid: oidext.T_geography, Locale: &emptyLocale}}
+
 	// Scalar contains all types that meet this criteria:
 	//
 	//   1. Scalar type (no ArrayFamily or TupleFamily types).
@@ -477,7 +511,7 @@ func MakeScalar(family Family, o oid.Oid, precision, width int32, locale string)
 	if family != t.Family() {
 		if family != CollatedStringFamily || StringFamily != t.Family() {
 			panic(errors.AssertionFailedf(
-				"oid %s does not match %s", oid.TypeName[o], family))
+				"oid %d does not match %s", o, family))
 		}
 	}
 	if family == ArrayFamily || family == TupleFamily {
@@ -948,6 +982,14 @@ func (t *T) Name() string {
 		return "timestamptz"
 	case TimeTZFamily:
 		return "timetz"
+	case TSQueryFamily:
+		return "tsquery"
+	case TSVectorFamily:
+		return "tsvector"
+	case GeometryFamily:
+		return "geometry"
+	case GeographyFamily:
+		return "geography"
 	case TupleFamily:
 		// Tuple types are currently anonymous, with no name.
 		return ""
@@ -972,7 +1014,7 @@ func (t *T) Name() string {
 //   int4[]       _int4
 //
 func (t *T) PGName() string {
-	name, ok := oid.TypeName[t.Oid()]
+	name, ok := oidext.TypeName(t.Oid())
 	if ok {
 		return strings.ToLower(name)
 	}
@@ -1155,6 +1197,14 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 			return "timestamp with time zone"
 		}
 		return fmt.Sprintf("timestamp(%d) with time zone", typmod)
+	case TSQueryFamily:
+		return "tsquery"
+	case TSVectorFamily:
+		return "tsvector"
+	case GeometryFamily:
+		return "geometry"
+	case GeographyFamily:
+		return "geography"
 	case TupleFamily:
 		return "record"
 	case UnknownFamily:
@@ -1784,6 +1834,10 @@ func IsValidArrayElementType(t *T) (valid bool, issueNum int) {
 	switch t.Family() {
 	case JsonFamily:
 		return false, 23468
+	case TSVectorFamily, TSQueryFamily:
+		return false, 7821
+	case GeometryFamily, GeographyFamily:
+		return false, 19953
 	default:
 		return true, 0
 	}
@@ -1893,7 +1947,8 @@ var typNameLiterals map[string]*T
 func init() {
 	typNameLiterals = make(map[string]*T)
 	for o, t := range OidToType {
-		name := strings.ToLower(oid.TypeName[o])
+		name, _ := oidext.TypeName(o)
+		name = strings.ToLower(name)
 		if _, ok := typNameLiterals[name]; !ok {
 			typNameLiterals[name] = t
 		}
@@ -1931,8 +1986,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"pg_lsn":        -1,
 	"point":         21286,
 	"polygon":       21286,
-	"tsquery":       7821,
-	"tsvector":      7821,
 	"txid_snapshot": -1,
 	"xml":           -1,
 }
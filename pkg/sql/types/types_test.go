@@ -16,6 +16,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/oidext"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/lib/pq/oid"
 )
@@ -141,6 +142,16 @@ func TestTypes(t *testing.T) {
 			Family: FloatFamily, Width: 32, Oid: oid.T_float4, Locale: &emptyLocale}}},
 		{Float4, MakeScalar(FloatFamily, oid.T_float4, 0, 32, emptyLocale)},
 
+		// GEOGRAPHY
+		{Geography, &T{InternalType: InternalType{
+			Family: GeographyFamily, Oid: oidext.T_geography, Locale: &emptyLocale}}},
+		{Geography, MakeScalar(GeographyFamily, oidext.T_geography, 0, 0, emptyLocale)},
+
+		// GEOMETRY
+		{Geometry, &T{InternalType: InternalType{
+			Family: GeometryFamily, Oid: oidext.T_geometry, Locale: &emptyLocale}}},
+		{Geometry, MakeScalar(GeometryFamily, oidext.T_geometry, 0, 0, emptyLocale)},
+
 		// INET
 		{INet, &T{InternalType: InternalType{
 			Family: INetFamily, Oid: oid.T_inet, Locale: &emptyLocale}}},
@@ -262,6 +273,16 @@ func TestTypes(t *testing.T) {
 			Family: TimestampTZFamily, Oid: oid.T_timestamptz, Precision: 6, TimePrecisionIsSet: true, Locale: &emptyLocale}}},
 		{MakeTimestampTZ(6), MakeScalar(TimestampTZFamily, oid.T_timestamptz, 6, 0, emptyLocale)},
 
+		// TSQUERY
+		{TSQuery, &T{InternalType: InternalType{
+			Family: TSQueryFamily, Oid: oid.T_tsquery, Locale: &emptyLocale}}},
+		{TSQuery, MakeScalar(TSQueryFamily, oid.T_tsquery, 0, 0, emptyLocale)},
+
+		// TSVECTOR
+		{TSVector, &T{InternalType: InternalType{
+			Family: TSVectorFamily, Oid: oid.T_tsvector, Locale: &emptyLocale}}},
+		{TSVector, MakeScalar(TSVectorFamily, oid.T_tsvector, 0, 0, emptyLocale)},
+
 		// TUPLE
 		{MakeTuple(nil), EmptyTuple},
 		{MakeTuple([]T{*Any}), AnyTuple},
@@ -563,6 +563,48 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// See https://www.postgresql.org/docs/current/pgtrgm.html#PGTRGM-GUC
+	`pg_trgm.similarity_threshold`: {
+		GetStringVal: makeFloatGetStringValFn(`pg_trgm.similarity_threshold`),
+		Set: func(
+			_ context.Context, m *sessionDataMutator, s string,
+		) error {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return wrapSetVarError("pg_trgm.similarity_threshold", s, "%v", err)
+			}
+			if f < 0 || f > 1 {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					`%s is outside the valid range for parameter "pg_trgm.similarity_threshold" (0 .. 1)`, s)
+			}
+			m.SetSimilarityThreshold(f)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return strconv.FormatFloat(evalCtx.SessionData.SimilarityThreshold, 'g', -1, 64)
+		},
+		GlobalDefault: func(sv *settings.Values) string { return "0.3" },
+	},
+
+	// See https://www.postgresql.org/docs/12/runtime-config-query.html#GUC-PLAN-CACHE-MODE
+	`plan_cache_mode`: {
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			mode, ok := sessiondata.PlanCacheModeFromString(s)
+			if !ok {
+				return newVarValueError(`plan_cache_mode`, s,
+					"auto", "force_generic_plan", "force_custom_plan")
+			}
+			m.SetPlanCacheMode(mode)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return evalCtx.SessionData.PlanCacheMode.String()
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return sessiondata.PlanCacheModeAuto.String()
+		},
+	},
+
 	// CockroachDB extension.
 	// TODO(dan): This should also work with SET.
 	`results_buffer_size`: {
@@ -949,6 +991,16 @@ func makeIntGetStringValFn(name string) getStringValFn {
 	}
 }
 
+func makeFloatGetStringValFn(name string) getStringValFn {
+	return func(ctx context.Context, evalCtx *extendedEvalContext, values []tree.TypedExpr) (string, error) {
+		f, err := getFloatVal(&evalCtx.EvalContext, name, values)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	}
+}
+
 // IsSessionVariableConfigurable returns true iff there is a session
 // variable with the given name and it is settable by a client
 // (e.g. in pgwire).
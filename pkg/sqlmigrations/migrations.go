@@ -255,6 +255,24 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 			return nil
 		},
 	},
+	{
+		// Introduced in v20.1.
+		name:                "create statement_diagnostics_requests, statement_diagnostics and statement_bundle_chunks tables",
+		workFn:              createStatementDiagnosticsTables,
+		includedInBootstrap: cluster.VersionByKey(cluster.VersionStatementDiagnosticsSystemTables),
+		newDescriptorIDs: staticIDs(
+			keys.StatementBundleChunksTableID,
+			keys.StatementDiagnosticsRequestsTableID,
+			keys.StatementDiagnosticsTableID,
+		),
+	},
+	{
+		// Introduced in v20.1.
+		name:                "create system.statement_hints table",
+		workFn:              createStatementHintsTable,
+		includedInBootstrap: cluster.VersionByKey(cluster.VersionStatementHintsSystemTable),
+		newDescriptorIDs:    staticIDs(keys.StatementHintsTableID),
+	},
 }
 
 func staticIDs(ids ...sqlbase.ID) func(ctx context.Context, db db) ([]sqlbase.ID, error) {
@@ -623,6 +641,23 @@ func createReportsMetaTable(ctx context.Context, r runner) error {
 	return createSystemTable(ctx, r, sqlbase.ReportsMetaTable)
 }
 
+func createStatementDiagnosticsTables(ctx context.Context, r runner) error {
+	for _, desc := range []sqlbase.TableDescriptor{
+		sqlbase.StatementBundleChunksTable,
+		sqlbase.StatementDiagnosticsRequestsTable,
+		sqlbase.StatementDiagnosticsTable,
+	} {
+		if err := createSystemTable(ctx, r, desc); err != nil {
+			return errors.Wrapf(err, "failed to create %s", desc.Name)
+		}
+	}
+	return nil
+}
+
+func createStatementHintsTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.StatementHintsTable)
+}
+
 func runStmtAsRootWithRetry(
 	ctx context.Context, r runner, opName string, stmt string, qargs ...interface{},
 ) error {
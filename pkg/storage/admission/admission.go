@@ -0,0 +1,328 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package admission implements admission control for KV work on a store.
+//
+// When the storage engine falls behind on compactions, L0 files and pending
+// compaction bytes pile up. Left unchecked, writes keep flowing until the
+// engine stalls them outright, at which point node liveness heartbeats fail
+// and leases are lost across the cluster. The Controller in this package
+// watches the engine's metrics and, once the engine is overloaded, queues
+// incoming writes by priority: background work (bulk ingestion) is throttled
+// first and only trickles in once the overload becomes severe, user work is
+// rate limited once the overload becomes severe, and
+// system work (node liveness, range descriptors, the system config span) is
+// never queued.
+package admission
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// Priority is the admission priority of a unit of KV work. Work with a
+// lower Priority value is admitted before work with a higher one.
+type Priority int
+
+const (
+	// SystemPriority is used for work required to keep the cluster healthy,
+	// such as node liveness heartbeats. It is never queued.
+	SystemPriority Priority = iota
+	// UserPriority is used for foreground SQL and KV traffic.
+	UserPriority
+	// BackgroundPriority is used for bulk work, such as AddSSTable requests
+	// issued by IMPORT and RESTORE, that can tolerate being delayed.
+	BackgroundPriority
+
+	numPriorities
+)
+
+func (p Priority) String() string {
+	switch p {
+	case SystemPriority:
+		return "system"
+	case UserPriority:
+		return "user"
+	case BackgroundPriority:
+		return "background"
+	default:
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+}
+
+// OverloadLevel describes how far behind the storage engine is on
+// compactions.
+type OverloadLevel int
+
+const (
+	// NotOverloaded means that all work is admitted immediately.
+	NotOverloaded OverloadLevel = iota
+	// Overloaded means that background work is rate limited.
+	Overloaded
+	// SeverelyOverloaded means that background work is admitted at a minimal
+	// rate and user work is rate limited.
+	SeverelyOverloaded
+)
+
+// pollInterval is the interval at which the Controller refreshes its view
+// of the engine's metrics and hands out admission tokens.
+var pollInterval = 250 * time.Millisecond
+
+// StatsFunc returns the current storage engine metrics.
+type StatsFunc func() (*engine.Stats, error)
+
+// Controller queues write requests to a store by priority while the store's
+// storage engine is overloaded. The zero value is not usable; use
+// NewController.
+type Controller struct {
+	st      *cluster.Settings
+	statsFn StatsFunc
+	metrics Metrics
+
+	mu struct {
+		syncutil.Mutex
+		level OverloadLevel
+		// lastRefill is the time at which tokens were last added.
+		lastRefill time.Time
+		// tokens holds, for each priority, the number of requests that can be
+		// admitted right away while the engine is overloaded.
+		tokens [numPriorities]float64
+		// queues holds, for each priority, the *waiters blocked on admission
+		// in FIFO order.
+		queues [numPriorities]list.List
+		// stopped is set once the stopper quiesces, after which all work is
+		// admitted.
+		stopped bool
+	}
+}
+
+// waiter is a request blocked on admission. Its channel is closed (with
+// Controller.mu held) when the request is admitted.
+type waiter struct {
+	ch    chan struct{}
+	start time.Time
+}
+
+// NewController returns a Controller that uses statsFn to determine whether
+// the storage engine is overloaded.
+func NewController(
+	st *cluster.Settings, statsFn StatsFunc, histogramWindowInterval time.Duration,
+) *Controller {
+	c := &Controller{
+		st:      st,
+		statsFn: statsFn,
+		metrics: makeMetrics(histogramWindowInterval),
+	}
+	for i := range c.mu.queues {
+		c.mu.queues[i].Init()
+	}
+	return c
+}
+
+// Metrics returns the Controller's metrics.
+func (c *Controller) Metrics() *Metrics {
+	return &c.metrics
+}
+
+// Level returns the overload level observed at the last poll of the
+// engine's metrics.
+func (c *Controller) Level() OverloadLevel {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mu.level
+}
+
+// Start runs a worker that periodically polls the storage engine's metrics
+// and admits queued requests as the overload allows.
+func (c *Controller) Start(ctx context.Context, stopper *stop.Stopper) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.tick(ctx, timeutil.Now())
+			case <-stopper.ShouldQuiesce():
+				c.stop()
+				return
+			}
+		}
+	})
+}
+
+// Admit blocks until a request of the given priority may proceed, or until
+// the context is canceled, in which case the context's error is returned.
+func (c *Controller) Admit(ctx context.Context, pri Priority) error {
+	tm := c.metrics.tier(pri)
+	if pri == SystemPriority || !enabled.Get(&c.st.SV) {
+		tm.Admitted.Inc(1)
+		return nil
+	}
+
+	c.mu.Lock()
+	// Requests only bypass the queue if nobody of the same priority is
+	// already waiting, to preserve FIFO order within a priority.
+	if c.mu.queues[pri].Len() == 0 && c.tryAdmitLocked(pri) {
+		c.mu.Unlock()
+		tm.Admitted.Inc(1)
+		return nil
+	}
+	w := &waiter{ch: make(chan struct{}), start: timeutil.Now()}
+	e := c.mu.queues[pri].PushBack(w)
+	tm.Queued.Inc(1)
+	c.mu.Unlock()
+
+	log.VEventf(ctx, 2, "queued %s request for admission", pri)
+	select {
+	case <-w.ch:
+	case <-ctx.Done():
+		c.mu.Lock()
+		select {
+		case <-w.ch:
+			// We were admitted concurrently with the cancellation. Treat the
+			// request as admitted; the caller will notice the canceled context
+			// soon enough.
+			c.mu.Unlock()
+		default:
+			c.mu.queues[pri].Remove(e)
+			c.mu.Unlock()
+			tm.Queued.Dec(1)
+			tm.Canceled.Inc(1)
+			return ctx.Err()
+		}
+	}
+	waited := timeutil.Since(w.start)
+	tm.WaitTime.RecordValue(waited.Nanoseconds())
+	tm.Admitted.Inc(1)
+	log.VEventf(ctx, 2, "admitted %s request after %s", pri, waited)
+	return nil
+}
+
+// tryAdmitLocked returns whether a request of the given priority can be
+// admitted at the current overload level, consuming a token if necessary.
+func (c *Controller) tryAdmitLocked(pri Priority) bool {
+	if c.mu.stopped || c.mu.level == NotOverloaded {
+		return true
+	}
+	if pri == UserPriority && c.mu.level == Overloaded {
+		return true
+	}
+	if c.mu.tokens[pri] >= 1 {
+		c.mu.tokens[pri]--
+		return true
+	}
+	return false
+}
+
+// tick refreshes the overload level from the engine's metrics, replenishes
+// the admission tokens and admits as many queued requests as possible.
+func (c *Controller) tick(ctx context.Context, now time.Time) {
+	var level OverloadLevel
+	if stats, err := c.statsFn(); err != nil {
+		log.Warningf(ctx, "failed to read engine stats: %+v", err)
+		// Keep the previous overload level.
+		level = c.Level()
+	} else {
+		level = computeOverloadLevel(c.st, stats)
+	}
+	c.metrics.OverloadLevel.Update(int64(level))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if level != c.mu.level {
+		log.Infof(ctx, "storage engine overload level changed from %d to %d", c.mu.level, level)
+		c.mu.level = level
+		// Tokens only accrue while the overload level stays the same.
+		c.mu.tokens = [numPriorities]float64{}
+		c.mu.lastRefill = now
+	}
+	c.refillLocked(now)
+	for pri := UserPriority; pri < numPriorities; pri++ {
+		q := &c.mu.queues[pri]
+		for q.Len() > 0 && c.tryAdmitLocked(pri) {
+			c.admitLocked(pri, q.Front())
+		}
+	}
+}
+
+// refillLocked adds the tokens accrued since the last refill at the rates
+// configured for the current overload level. Each priority can accrue at
+// most one second's worth of tokens (and at least one token).
+func (c *Controller) refillLocked(now time.Time) {
+	var elapsed float64
+	if !c.mu.lastRefill.IsZero() {
+		elapsed = now.Sub(c.mu.lastRefill).Seconds()
+	}
+	c.mu.lastRefill = now
+
+	var rates [numPriorities]float64
+	switch c.mu.level {
+	case Overloaded:
+		rates[BackgroundPriority] = overloadedBackgroundRate.Get(&c.st.SV)
+	case SeverelyOverloaded:
+		rates[UserPriority] = overloadedUserRate.Get(&c.st.SV)
+		rates[BackgroundPriority] = severelyOverloadedBackgroundRate.Get(&c.st.SV)
+	}
+	for pri := range rates {
+		if rates[pri] == 0 {
+			c.mu.tokens[pri] = 0
+			continue
+		}
+		c.mu.tokens[pri] = math.Min(c.mu.tokens[pri]+rates[pri]*elapsed, math.Max(rates[pri], 1))
+	}
+}
+
+func (c *Controller) admitLocked(pri Priority, e *list.Element) {
+	w := c.mu.queues[pri].Remove(e).(*waiter)
+	c.metrics.tier(pri).Queued.Dec(1)
+	close(w.ch)
+}
+
+// stop admits all queued requests and disables queueing.
+func (c *Controller) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.stopped = true
+	for pri := range c.mu.queues {
+		q := &c.mu.queues[pri]
+		for q.Len() > 0 {
+			c.admitLocked(Priority(pri), q.Front())
+		}
+	}
+}
+
+// computeOverloadLevel determines the overload level from the engine's L0
+// file count and pending compaction bytes.
+func computeOverloadLevel(st *cluster.Settings, stats *engine.Stats) OverloadLevel {
+	l0Score := float64(stats.L0FileCount) / float64(l0FileCountThreshold.Get(&st.SV))
+	score := l0Score
+	if compactionLimit := pendingCompactionThreshold.Get(&st.SV); compactionLimit > 0 {
+		compactionScore := float64(stats.PendingCompactionBytesEstimate) / float64(compactionLimit)
+		score = math.Max(score, compactionScore)
+	}
+	switch {
+	case score > 2:
+		return SeverelyOverloaded
+	case score > 1:
+		return Overloaded
+	default:
+		return NotOverloaded
+	}
+}
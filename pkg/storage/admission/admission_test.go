@@ -0,0 +1,226 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+func TestComputeOverloadLevel(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	st := cluster.MakeTestingClusterSettings()
+	l0FileCountThreshold.Override(&st.SV, 10)
+	pendingCompactionThreshold.Override(&st.SV, 1<<30)
+
+	testCases := []struct {
+		l0Files       int64
+		pendingBytes  int64
+		expectedLevel OverloadLevel
+	}{
+		{0, 0, NotOverloaded},
+		{10, 0, NotOverloaded},
+		{11, 0, Overloaded},
+		{20, 0, Overloaded},
+		{21, 0, SeverelyOverloaded},
+		{0, 1 << 30, NotOverloaded},
+		{0, 1<<30 + 1, Overloaded},
+		{0, 3 << 30, SeverelyOverloaded},
+		{15, 3 << 30, SeverelyOverloaded},
+	}
+	for _, tc := range testCases {
+		stats := &engine.Stats{L0FileCount: tc.l0Files, PendingCompactionBytesEstimate: tc.pendingBytes}
+		if level := computeOverloadLevel(st, stats); level != tc.expectedLevel {
+			t.Errorf("%d L0 files, %d pending bytes: expected level %d, got %d",
+				tc.l0Files, tc.pendingBytes, tc.expectedLevel, level)
+		}
+	}
+}
+
+// testStats is a StatsFunc whose result can be changed by the test.
+type testStats struct {
+	syncutil.Mutex
+	stats engine.Stats
+}
+
+func (ts *testStats) set(l0Files int64) {
+	ts.Lock()
+	defer ts.Unlock()
+	ts.stats.L0FileCount = l0Files
+}
+
+func (ts *testStats) get() (*engine.Stats, error) {
+	ts.Lock()
+	defer ts.Unlock()
+	stats := ts.stats
+	return &stats, nil
+}
+
+// admitAsync calls Admit in a goroutine and returns a channel that receives
+// its result.
+func admitAsync(ctx context.Context, c *Controller, pri Priority) chan error {
+	ch := make(chan error, 1)
+	go func() {
+		ch <- c.Admit(ctx, pri)
+	}()
+	return ch
+}
+
+func waitForQueued(t *testing.T, tm *TierMetrics, n int64) {
+	t.Helper()
+	deadline := timeutil.Now().Add(10 * time.Second)
+	for tm.Queued.Value() != n {
+		if timeutil.Now().After(deadline) {
+			t.Fatalf("expected %d queued requests, found %d", n, tm.Queued.Value())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func expectAdmitted(t *testing.T, ch chan error) {
+	t.Helper()
+	select {
+	case err := <-ch:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("request was not admitted")
+	}
+}
+
+func expectBlocked(t *testing.T, ch chan error) {
+	t.Helper()
+	select {
+	case err := <-ch:
+		t.Fatalf("request unexpectedly finished with %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestControllerQueuesByPriority(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	l0FileCountThreshold.Override(&st.SV, 10)
+	overloadedUserRate.Override(&st.SV, 2)
+	overloadedBackgroundRate.Override(&st.SV, 1)
+	severelyOverloadedBackgroundRate.Override(&st.SV, 0.5)
+
+	var stats testStats
+	c := NewController(st, stats.get, time.Minute)
+	now := timeutil.Unix(0, 0)
+	tick := func(d time.Duration) {
+		now = now.Add(d)
+		c.tick(ctx, now)
+	}
+
+	// When the engine is healthy, everything is admitted immediately.
+	tick(0)
+	for pri := SystemPriority; pri < numPriorities; pri++ {
+		if err := c.Admit(ctx, pri); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// When the engine is overloaded, background work is queued and admitted
+	// at the background rate while user and system work isn't queued.
+	stats.set(15)
+	tick(0)
+	if level := c.Level(); level != Overloaded {
+		t.Fatalf("expected overload level %d, got %d", Overloaded, level)
+	}
+	bg1 := admitAsync(ctx, c, BackgroundPriority)
+	waitForQueued(t, &c.metrics.Background, 1)
+	bg2 := admitAsync(ctx, c, BackgroundPriority)
+	waitForQueued(t, &c.metrics.Background, 2)
+	for _, pri := range []Priority{SystemPriority, UserPriority} {
+		if err := c.Admit(ctx, pri); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tick(time.Second)
+	expectAdmitted(t, bg1)
+	expectBlocked(t, bg2)
+
+	// When the engine is severely overloaded, background work is admitted at
+	// a minimal rate and user work is rate limited.
+	stats.set(25)
+	tick(time.Second)
+	expectBlocked(t, bg2)
+	user1 := admitAsync(ctx, c, UserPriority)
+	waitForQueued(t, &c.metrics.User, 1)
+	user2 := admitAsync(ctx, c, UserPriority)
+	waitForQueued(t, &c.metrics.User, 2)
+	user3 := admitAsync(ctx, c, UserPriority)
+	waitForQueued(t, &c.metrics.User, 3)
+	if err := c.Admit(ctx, SystemPriority); err != nil {
+		t.Fatal(err)
+	}
+	tick(time.Second)
+	expectAdmitted(t, user1)
+	expectAdmitted(t, user2)
+	expectBlocked(t, user3)
+	expectBlocked(t, bg2)
+
+	// Canceled requests leave the queue.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	user4 := admitAsync(cancelCtx, c, UserPriority)
+	waitForQueued(t, &c.metrics.User, 2)
+	cancel()
+	if err := <-user4; err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	waitForQueued(t, &c.metrics.User, 1)
+	if canceled := c.metrics.User.Canceled.Count(); canceled != 1 {
+		t.Fatalf("expected 1 canceled request, found %d", canceled)
+	}
+
+	// Background work trickles in rather than being starved.
+	tick(time.Second)
+	expectAdmitted(t, bg2)
+	expectAdmitted(t, user3)
+	bg3 := admitAsync(ctx, c, BackgroundPriority)
+	waitForQueued(t, &c.metrics.Background, 1)
+	expectBlocked(t, bg3)
+
+	// Once the overload clears, all queued work is admitted.
+	stats.set(0)
+	tick(time.Second)
+	expectAdmitted(t, bg3)
+	waitForQueued(t, &c.metrics.User, 0)
+	waitForQueued(t, &c.metrics.Background, 0)
+}
+
+func TestControllerDisabled(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	enabled.Override(&st.SV, false)
+
+	var stats testStats
+	stats.set(1000)
+	c := NewController(st, stats.get, time.Minute)
+	c.tick(ctx, timeutil.Now())
+	if err := c.Admit(ctx, BackgroundPriority); err != nil {
+		t.Fatal(err)
+	}
+}
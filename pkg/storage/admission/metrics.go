@@ -0,0 +1,107 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+// Metrics holds all metrics relating to an admission Controller.
+type Metrics struct {
+	OverloadLevel *metric.Gauge
+	System        TierMetrics
+	User          TierMetrics
+	Background    TierMetrics
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (Metrics) MetricStruct() {}
+
+var _ metric.Struct = Metrics{}
+
+// TierMetrics holds the queueing metrics for a single admission priority.
+type TierMetrics struct {
+	Admitted *metric.Counter
+	Canceled *metric.Counter
+	Queued   *metric.Gauge
+	WaitTime *metric.Histogram
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (TierMetrics) MetricStruct() {}
+
+var _ metric.Struct = TierMetrics{}
+
+var metaOverloadLevel = metric.Metadata{
+	Name:        "admission.overload_level",
+	Help:        "Storage engine overload level (0 = normal, 1 = overloaded, 2 = severely overloaded)",
+	Measurement: "Overload Level",
+	Unit:        metric.Unit_COUNT,
+}
+
+func makeTierMetrics(pri Priority, histogramWindowInterval time.Duration) TierMetrics {
+	return TierMetrics{
+		Admitted: metric.NewCounter(metric.Metadata{
+			Name:        fmt.Sprintf("admission.%s.admitted", pri),
+			Help:        fmt.Sprintf("Number of %s write requests admitted", pri),
+			Measurement: "Requests",
+			Unit:        metric.Unit_COUNT,
+		}),
+		Canceled: metric.NewCounter(metric.Metadata{
+			Name:        fmt.Sprintf("admission.%s.canceled", pri),
+			Help:        fmt.Sprintf("Number of %s write requests canceled while queued for admission", pri),
+			Measurement: "Requests",
+			Unit:        metric.Unit_COUNT,
+		}),
+		Queued: metric.NewGauge(metric.Metadata{
+			Name:        fmt.Sprintf("admission.%s.queued", pri),
+			Help:        fmt.Sprintf("Number of %s write requests waiting for admission", pri),
+			Measurement: "Requests",
+			Unit:        metric.Unit_COUNT,
+		}),
+		WaitTime: metric.NewHistogram(
+			metric.Metadata{
+				Name:        fmt.Sprintf("admission.%s.wait_time", pri),
+				Help:        fmt.Sprintf("Histogram of durations %s write requests spent queued for admission", pri),
+				Measurement: "Wait Time",
+				Unit:        metric.Unit_NANOSECONDS,
+			},
+			histogramWindowInterval,
+			time.Minute.Nanoseconds(),
+			1,
+		),
+	}
+}
+
+func makeMetrics(histogramWindowInterval time.Duration) Metrics {
+	return Metrics{
+		OverloadLevel: metric.NewGauge(metaOverloadLevel),
+		System:        makeTierMetrics(SystemPriority, histogramWindowInterval),
+		User:          makeTierMetrics(UserPriority, histogramWindowInterval),
+		Background:    makeTierMetrics(BackgroundPriority, histogramWindowInterval),
+	}
+}
+
+func (m *Metrics) tier(pri Priority) *TierMetrics {
+	switch pri {
+	case SystemPriority:
+		return &m.System
+	case UserPriority:
+		return &m.User
+	case BackgroundPriority:
+		return &m.Background
+	default:
+		panic(fmt.Sprintf("unknown admission priority %d", pri))
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/pkg/errors"
+)
+
+var enabled = settings.RegisterBoolSetting(
+	"kv.admission.enabled",
+	"when true, write requests are queued by priority while the storage engine is overloaded",
+	true,
+)
+
+// l0FileCountThreshold is the number of files in L0 at which the engine is
+// considered overloaded. At twice this number it is considered severely
+// overloaded.
+var l0FileCountThreshold = settings.RegisterPositiveIntSetting(
+	"kv.admission.l0_file_count_threshold",
+	"number of L0 files above which background writes are queued; user writes are rate limited above twice this value",
+	20,
+)
+
+// pendingCompactionThreshold is the estimate of pending compaction bytes at
+// which the engine is considered overloaded. At twice this estimate it is
+// considered severely overloaded.
+var pendingCompactionThreshold = settings.RegisterByteSizeSetting(
+	"kv.admission.pending_compaction_threshold",
+	"pending compaction estimate above which background writes are queued; user writes are rate limited above twice this value",
+	8<<30, /* 8 GiB */
+)
+
+// overloadedUserRate is the rate at which user writes are admitted while
+// the engine is severely overloaded.
+var overloadedUserRate = settings.RegisterNonNegativeFloatSetting(
+	"kv.admission.overloaded_user_rate",
+	"number of user write requests per second a store admits while its storage engine is severely overloaded",
+	1000,
+)
+
+// overloadedBackgroundRate is the rate at which background writes (bulk
+// ingestions and range clears) are admitted while the engine is overloaded
+// but not yet severely overloaded.
+var overloadedBackgroundRate = settings.RegisterNonNegativeFloatSetting(
+	"kv.admission.overloaded_background_rate",
+	"number of background write requests per second a store admits while its storage engine is overloaded",
+	1,
+)
+
+// severelyOverloadedBackgroundRate is the rate at which background writes
+// are admitted while the engine is severely overloaded. It is kept positive
+// so that bulk work trickles in instead of blocking until its context is
+// canceled.
+var severelyOverloadedBackgroundRate = settings.RegisterValidatedFloatSetting(
+	"kv.admission.severely_overloaded_background_rate",
+	"number of background write requests per second a store admits while its storage engine is severely overloaded",
+	0.1,
+	func(v float64) error {
+		if v <= 0 {
+			return errors.Errorf("cannot set kv.admission.severely_overloaded_background_rate to a non-positive value: %f", v)
+		}
+		return nil
+	},
+)
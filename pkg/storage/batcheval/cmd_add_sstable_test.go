@@ -13,11 +13,13 @@ package batcheval_test
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"regexp"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
@@ -29,8 +31,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/kr/pretty"
 )
@@ -98,6 +103,89 @@ func TestDBAddSSTable(t *testing.T) {
 	})
 }
 
+// TestDBAddSSTableBackpressure checks how the layers which delay AddSSTable
+// requests interact: requests queue in the store's concurrency limiter, are
+// then held by the store's ingestion budget for no longer than its maximum
+// wait, and the remaining delay is returned to the client as backpressure.
+func TestDBAddSSTableBackpressure(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, sqlDB, db := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	store, err := s.GetStores().(*storage.Stores).GetStore(s.GetFirstStoreID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter := func(name string) int64 {
+		var count int64
+		store.Registry().Each(func(n string, v interface{}) {
+			if n == name {
+				count = v.(*metric.Counter).Count()
+			}
+		})
+		return count
+	}
+
+	// Every SST below is larger than the byte bucket, so each request exceeds
+	// the budget by about a second or more.
+	const maxWait = 50 * time.Millisecond
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	tdb.Exec(t, `SET CLUSTER SETTING kv.bulk_io_write.concurrent_addsstable_requests = 1`)
+	tdb.Exec(t, `SET CLUSTER SETTING kv.bulk_ingest.budget.max_rate = '1KiB'`)
+	tdb.Exec(t, `SET CLUSTER SETTING kv.bulk_ingest.budget.max_wait = $1`, maxWait.String())
+
+	const numRequests = 4
+	value := roachpb.MakeValueFromBytes(bytes.Repeat([]byte("x"), 2<<10))
+	backoffs := make([]time.Duration, numRequests)
+	g := ctxgroup.WithContext(ctx)
+	for i := 0; i < numRequests; i++ {
+		i := i
+		g.GoCtx(func(ctx context.Context) error {
+			key := roachpb.Key(fmt.Sprintf("backpressure-%d", i))
+			data, err := singleKVSSTable(
+				engine.MVCCKey{Key: key, Timestamp: hlc.Timestamp{WallTime: 1}}, value.RawBytes,
+			)
+			if err != nil {
+				return err
+			}
+			backoffs[i], err = db.AddSSTable(
+				ctx, key, key.Next(), data, false /* disallowShadowing */, nil /* stats */, false, /* ingestAsWrites */
+			)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// Every request went over the budget, and its client was asked to back
+	// off for the delay the store didn't impose itself.
+	for i, backoff := range backoffs {
+		if backoff < 500*time.Millisecond {
+			t.Errorf("request %d: expected a backoff of at least 500ms, got %s", i, backoff)
+		}
+	}
+	if n := counter("addsstable.budget.backpressure"); n != numRequests {
+		t.Errorf("expected %d backpressured requests, got %d", numRequests, n)
+	}
+	// The time spent queueing in the concurrency limiter doesn't count against
+	// the budget's maximum wait, so the store held each request for about that
+	// long on behalf of the budget, while the total delay includes the time
+	// spent in the limiter as well.
+	budgetDelay := time.Duration(counter("addsstable.budget.delay.bytes") +
+		counter("addsstable.budget.delay.compactions"))
+	if budgetDelay < numRequests*maxWait || budgetDelay > numRequests*time.Second {
+		t.Errorf("expected the budget to delay %d requests by about %s each, got %s in total",
+			numRequests, maxWait, budgetDelay)
+	}
+	if totalDelay := time.Duration(
+		store.Metrics().AddSSTableProposalTotalDelay.Count(),
+	); totalDelay < budgetDelay {
+		t.Errorf("expected total delay %s to include budget delay %s", totalDelay, budgetDelay)
+	}
+}
+
 // if store != nil, assume it is on-disk and check ingestion semantics.
 func runTestDBAddSSTable(ctx context.Context, t *testing.T, db *client.DB, store *storage.Store) {
 	{
@@ -108,13 +196,13 @@ func runTestDBAddSSTable(ctx context.Context, t *testing.T, db *client.DB, store
 		}
 
 		// Key is before the range in the request span.
-		if err := db.AddSSTable(
+		if _, err := db.AddSSTable(
 			ctx, "d", "e", data, false /* disallowShadowing */, nil /* stats */, false, /* ingestAsWrites */
 		); !testutils.IsError(err, "not in request range") {
 			t.Fatalf("expected request range error got: %+v", err)
 		}
 		// Key is after the range in the request span.
-		if err := db.AddSSTable(
+		if _, err := db.AddSSTable(
 			ctx, "a", "b", data, false /* disallowShadowing */, nil /* stats */, false, /* ingestAsWrites */
 		); !testutils.IsError(err, "not in request range") {
 			t.Fatalf("expected request range error got: %+v", err)
@@ -123,7 +211,7 @@ func runTestDBAddSSTable(ctx context.Context, t *testing.T, db *client.DB, store
 		// Do an initial ingest.
 		ingestCtx, collect, cancel := tracing.ContextWithRecordingSpan(ctx, "test-recording")
 		defer cancel()
-		if err := db.AddSSTable(
+		if _, err := db.AddSSTable(
 			ingestCtx, "b", "c", data, false /* disallowShadowing */, nil /* stats */, false, /* ingestAsWrites */
 		); err != nil {
 			t.Fatalf("%+v", err)
@@ -166,7 +254,7 @@ func runTestDBAddSSTable(ctx context.Context, t *testing.T, db *client.DB, store
 			t.Fatalf("%+v", err)
 		}
 
-		if err := db.AddSSTable(
+		if _, err := db.AddSSTable(
 			ctx, "b", "c", data, false /* disallowShadowing */, nil /* stats */, false, /* ingestAsWrites */
 		); err != nil {
 			t.Fatalf("%+v", err)
@@ -203,7 +291,7 @@ func runTestDBAddSSTable(ctx context.Context, t *testing.T, db *client.DB, store
 			ingestCtx, collect, cancel := tracing.ContextWithRecordingSpan(ctx, "test-recording")
 			defer cancel()
 
-			if err := db.AddSSTable(
+			if _, err := db.AddSSTable(
 				ingestCtx, "b", "c", data, false /* disallowShadowing */, nil /* stats */, false, /* ingestAsWrites */
 			); err != nil {
 				t.Fatalf("%+v", err)
@@ -258,7 +346,7 @@ func runTestDBAddSSTable(ctx context.Context, t *testing.T, db *client.DB, store
 			ingestCtx, collect, cancel := tracing.ContextWithRecordingSpan(ctx, "test-recording")
 			defer cancel()
 
-			if err := db.AddSSTable(
+			if _, err := db.AddSSTable(
 				ingestCtx, "b", "c", data, false /* disallowShadowing */, nil /* stats */, true, /* ingestAsWrites */
 			); err != nil {
 				t.Fatalf("%+v", err)
@@ -298,7 +386,7 @@ func runTestDBAddSSTable(ctx context.Context, t *testing.T, db *client.DB, store
 			t.Fatalf("%+v", err)
 		}
 
-		if err := db.AddSSTable(
+		if _, err := db.AddSSTable(
 			ctx, "b", "c", data, false /* disallowShadowing */, nil /* stats */, false, /* ingestAsWrites */
 		); !testutils.IsError(err, "invalid checksum") {
 			t.Fatalf("expected 'invalid checksum' error got: %+v", err)
@@ -50,6 +50,9 @@ type EvalContext interface {
 	EvalKnobs() storagebase.BatchEvalTestingKnobs
 
 	Engine() engine.Engine
+	// RaftEngine returns the engine holding the Raft log, which is the same as
+	// Engine() unless the Raft log is kept in a separate engine.
+	RaftEngine() engine.Engine
 	Clock() *hlc.Clock
 	DB() *client.DB
 	AbortSpan() *abortspan.AbortSpan
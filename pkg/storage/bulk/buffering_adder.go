@@ -247,13 +247,14 @@ func (b *BufferingAdder) Flush(ctx context.Context) error {
 	}
 	if log.V(4) {
 		log.Infof(ctx,
-			"bulk adder %s has ingested %s, spent %v sorting and %v flushing (%v sending, %v splitting). Flushed %d times due to buffer (%s) size. Flushed chunked as %d files (%d after split-retries), %d due to ranges, %d due to sst size.",
+			"bulk adder %s has ingested %s, spent %v sorting and %v flushing (%v sending, %v splitting, %v backing off). Flushed %d times due to buffer (%s) size. Flushed chunked as %d files (%d after split-retries), %d due to ranges, %d due to sst size.",
 			b.name,
 			sz(b.sink.totalRows.DataSize),
 			b.flushCounts.totalSort,
 			b.flushCounts.totalFlush,
 			b.sink.flushCounts.sendWait,
 			b.sink.flushCounts.splitWait,
+			b.sink.flushCounts.backpressureWait,
 			b.flushCounts.bufferSize,
 			sz(b.memAcc.Used()),
 			b.sink.flushCounts.total, b.sink.flushCounts.files,
@@ -81,9 +81,13 @@ type SSTBatcher struct {
 		sstSize int
 		files   int // a single flush might create multiple files.
 
-		sendWait  time.Duration
-		splitWait time.Duration
+		sendWait         time.Duration
+		splitWait        time.Duration
+		backpressureWait time.Duration
 	}
+	// backpressureUntil is the time until which the stores asked the batcher to
+	// hold off on sending further SSTs.
+	backpressureUntil time.Time
 	// Tracking for if we have "filled" a range in case we want to split/scatter.
 	flushedToCurrentRange int64
 	lastFlushKey          []byte
@@ -289,12 +293,20 @@ func (b *SSTBatcher) doFlush(ctx context.Context, reason int, nextKey roachpb.Ke
 		b.ms.LastUpdateNanos = timeutil.Now().UnixNano()
 	}
 
+	if err := b.waitForBackpressure(ctx); err != nil {
+		return err
+	}
+
 	beforeSend := timeutil.Now()
-	files, err := AddSSTable(ctx, b.db, start, end, b.sstFile.Data(), b.disallowShadowing, b.ms, b.settings)
+	files, backpressure, err := AddSSTable(ctx, b.db, start, end, b.sstFile.Data(), b.disallowShadowing, b.ms, b.settings)
 	if err != nil {
 		return err
 	}
 	b.flushCounts.sendWait += timeutil.Since(beforeSend)
+	if backpressure > 0 {
+		log.VEventf(ctx, 2, "store asked to hold off on ingestion for %v", backpressure)
+		b.backpressureUntil = timeutil.Now().Add(backpressure)
+	}
 
 	b.flushCounts.files += files
 	if b.flushKey != nil {
@@ -334,6 +346,26 @@ func (b *SSTBatcher) doFlush(ctx context.Context, reason int, nextKey roachpb.Ke
 	return nil
 }
 
+// waitForBackpressure waits until the time the stores asked the batcher to
+// hold off on sending further SSTs has passed.
+func (b *SSTBatcher) waitForBackpressure(ctx context.Context) error {
+	wait := timeutil.Until(b.backpressureUntil)
+	if wait <= 0 {
+		return nil
+	}
+	var timer timeutil.Timer
+	defer timer.Stop()
+	timer.Reset(wait)
+	select {
+	case <-timer.C:
+		timer.Read = true
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	b.flushCounts.backpressureWait += wait
+	return nil
+}
+
 // Close closes the underlying SST builder.
 func (b *SSTBatcher) Close() {
 	b.sstWriter.Close()
@@ -348,7 +380,7 @@ func (b *SSTBatcher) GetSummary() roachpb.BulkOpSummary {
 type SSTSender interface {
 	AddSSTable(
 		ctx context.Context, begin, end interface{}, data []byte, disallowShadowing bool, stats *enginepb.MVCCStats, ingestAsWrites bool,
-	) error
+	) (backpressure time.Duration, _ error)
 	SplitAndScatter(ctx context.Context, key roachpb.Key, expirationTime hlc.Timestamp) error
 }
 
@@ -361,7 +393,9 @@ type sstSpan struct {
 
 // AddSSTable retries db.AddSSTable if retryable errors occur, including if the
 // SST spans a split, in which case it is iterated and split into two SSTs, one
-// for each side of the split in the error, and each are retried.
+// for each side of the split in the error, and each are retried. It returns
+// the number of SSTs added and the longest time for which the stores asked
+// the caller to hold off on further ingestions.
 func AddSSTable(
 	ctx context.Context,
 	db SSTSender,
@@ -370,12 +404,13 @@ func AddSSTable(
 	disallowShadowing bool,
 	ms enginepb.MVCCStats,
 	settings *cluster.Settings,
-) (int, error) {
+) (int, time.Duration, error) {
 	var files int
+	var backpressure time.Duration
 	now := timeutil.Now()
 	iter, err := engine.NewMemSSTIterator(sstBytes, true)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	defer iter.Close()
 
@@ -383,7 +418,7 @@ func AddSSTable(
 	if (ms == enginepb.MVCCStats{}) {
 		stats, err = engine.ComputeStatsGo(iter, start, end, now.UnixNano())
 		if err != nil {
-			return 0, errors.Wrapf(err, "computing stats for SST [%s, %s)", start, end)
+			return 0, 0, errors.Wrapf(err, "computing stats for SST [%s, %s)", start, end)
 		}
 	} else {
 		stats = ms
@@ -415,8 +450,12 @@ func AddSSTable(
 					ingestAsWriteBatch = true
 				}
 				// This will fail if the range has split but we'll check for that below.
-				err = db.AddSSTable(ctx, item.start, item.end, item.sstBytes, item.disallowShadowing, &item.stats, ingestAsWriteBatch)
+				var itemBackpressure time.Duration
+				itemBackpressure, err = db.AddSSTable(ctx, item.start, item.end, item.sstBytes, item.disallowShadowing, &item.stats, ingestAsWriteBatch)
 				if err == nil {
+					if itemBackpressure > backpressure {
+						backpressure = itemBackpressure
+					}
 					log.VEventf(ctx, 3, "adding %s AddSSTable [%s,%s) took %v", sz(len(item.sstBytes)), item.start, item.end, timeutil.Since(before))
 					return nil
 				}
@@ -450,7 +489,7 @@ func AddSSTable(
 			}
 			return errors.Wrapf(err, "addsstable [%s,%s)", item.start, item.end)
 		}(); err != nil {
-			return files, err
+			return files, backpressure, err
 		}
 		files++
 		// explicitly deallocate SST. This will not deallocate the
@@ -458,7 +497,7 @@ func AddSSTable(
 		item.sstBytes = nil
 	}
 	log.VEventf(ctx, 3, "AddSSTable [%v, %v) added %d files and took %v", start, end, files, timeutil.Since(now))
-	return files, nil
+	return files, backpressure, nil
 }
 
 // createSplitSSTable is a helper for splitting up SSTs. The iterator
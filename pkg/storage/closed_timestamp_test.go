@@ -368,6 +368,83 @@ func TestClosedTimestampCantServeForNonTransactionalReadRequest(t *testing.T) {
 	verifyNotLeaseHolderErrors(t, baQueryTxn, repls, 2)
 }
 
+// TestClosedTimestampGlobalReads verifies that all replicas of a range whose
+// zone config enables global reads can serve reads at present time while the
+// leaseholder publishes the range as such, and only then. Routing such reads
+// to followers in the DistSender is tested in followerreadsccl.
+func TestClosedTimestampGlobalReads(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	if util.RaceEnabled {
+		t.Skip("skipping under race")
+	}
+
+	ctx := context.Background()
+	tc, db0, desc, repls := setupTestClusterForClosedTimestampTesting(ctx, t, testingTargetDuration)
+	defer tc.Stopper().Stop(ctx)
+
+	if _, err := db0.Exec(`ALTER TABLE cttest.kv CONFIGURE ZONE USING global_reads = true`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db0.Exec(`INSERT INTO cttest.kv VALUES(1, $1)`, "foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Regular closed timestamps trail present time by the target duration, so
+	// followers can only serve reads at present time once the global reads
+	// closed timestamp published by the leaseholder has reached them.
+	testutils.SucceedsSoon(t, func() error {
+		baRead := makeReadBatchRequestForDesc(desc, tc.Server(0).Clock().Now())
+		for _, repl := range repls {
+			resp, pErr := repl.Send(ctx, baRead)
+			if pErr != nil {
+				return pErr.GoError()
+			}
+			if rows := resp.Responses[0].GetInner().(*roachpb.ScanResponse).Rows; len(rows) != 1 {
+				return fmt.Errorf("expected 1 row, but got %d", len(rows))
+			}
+		}
+		return nil
+	})
+
+	// Every replica flags its responses for the DistSender, the leaseholder
+	// based on its zone config and the followers based on what the leaseholder
+	// published.
+	baRead := makeReadBatchRequestForDesc(desc, tc.Server(0).Clock().Now())
+	for _, repl := range repls {
+		resp, pErr := repl.Send(ctx, baRead)
+		if pErr != nil {
+			t.Fatal(pErr)
+		}
+		if !resp.GlobalReads {
+			t.Fatalf("expected response from %s to be flagged as global reads", repl)
+		}
+	}
+
+	// Once global reads are disabled, the leaseholder stops publishing the
+	// range as such, and the followers go back to serving only reads below
+	// the regular closed timestamp, regardless of their own zone configs.
+	if _, err := db0.Exec(`ALTER TABLE cttest.kv CONFIGURE ZONE USING global_reads = false`); err != nil {
+		t.Fatal(err)
+	}
+	testutils.SucceedsSoon(t, func() error {
+		baRead := makeReadBatchRequestForDesc(desc, tc.Server(0).Clock().Now())
+		var notLeaseholderErrs int
+		for _, repl := range repls {
+			if _, pErr := repl.Send(ctx, baRead); pErr != nil {
+				if _, ok := pErr.GetDetail().(*roachpb.NotLeaseHolderError); !ok {
+					return pErr.GoError()
+				}
+				notLeaseholderErrs++
+			}
+		}
+		if notLeaseholderErrs != len(repls)-1 {
+			return errors.Errorf("expected %d NotLeaseHolderErrors; found %d", len(repls)-1, notLeaseholderErrs)
+		}
+		return nil
+	})
+}
+
 func verifyNotLeaseHolderErrors(
 	t *testing.T, ba roachpb.BatchRequest, repls []*storage.Replica, expectedNLEs int,
 ) {
@@ -142,6 +142,11 @@ type Provider interface {
 	Notifyee
 	Start()
 	MaxClosed(roachpb.NodeID, roachpb.RangeID, ctpb.Epoch, ctpb.LAI) hlc.Timestamp
+	// MaxClosedGlobalReads is like MaxClosed, but returns the closed timestamp
+	// that the origin node published for its ranges configured for global reads
+	// (see ctpb.Entry.GlobalReadsClosedTimestamp). It returns the zero timestamp
+	// if the origin node did not list the range as such.
+	MaxClosedGlobalReads(roachpb.NodeID, roachpb.RangeID, ctpb.Epoch, ctpb.LAI) hlc.Timestamp
 }
 
 // A ClientRegistry is the client component of the follower reads subsystem. It
@@ -184,6 +189,16 @@ type LiveClockFn func(roachpb.NodeID) (liveNow hlc.Timestamp, liveEpoch ctpb.Epo
 // with the Tracker, so that updates for them are emitted soon thereafter.
 type RefreshFn func(...roachpb.RangeID)
 
+// GlobalReadsFn is called by the Producer whenever it emits an update. It
+// returns the ranges for which the closed timestamp globalReadsClosed can be
+// published along with the update closing the timestamp closed, i.e. the
+// local leaseholders configured for global reads which pushed all of their
+// writes not covered by the update above globalReadsClosed. The function is
+// expected to make sure that subsequent writes to these ranges are evaluated
+// above globalReadsClosed, even if they cease to be configured for global
+// reads. See GlobalReadsLead.
+type GlobalReadsFn func(closed, globalReadsClosed hlc.Timestamp) []roachpb.RangeID
+
 // A Dialer opens closed timestamp connections to receive updates from remote
 // nodes.
 type Dialer interface {
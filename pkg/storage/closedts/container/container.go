@@ -35,6 +35,12 @@ type Config struct {
 	Clock    closedts.LiveClockFn
 	Refresh  closedts.RefreshFn
 	Dialer   closedts.Dialer
+	// GlobalReads determines the local ranges whose closed timestamps are
+	// published as configured for global reads.
+	GlobalReads closedts.GlobalReadsFn
+	// MaxOffset is the maximum clock offset of the cluster, used to compute
+	// the closed timestamps of ranges configured for global reads.
+	MaxOffset time.Duration
 }
 
 // A Container is a full closed timestamp subsystem along with the Config it was
@@ -119,12 +125,14 @@ func (c *Container) Start(nodeID roachpb.NodeID) {
 	tracker := minprop.NewTracker()
 
 	pConf := provider.Config{
-		NodeID:   nodeID,
-		Settings: cfg.Settings,
-		Stopper:  cfg.Stopper,
-		Storage:  storage,
-		Clock:    cfg.Clock,
-		Close:    closedts.AsCloseFn(tracker),
+		NodeID:      nodeID,
+		Settings:    cfg.Settings,
+		Stopper:     cfg.Stopper,
+		Storage:     storage,
+		Clock:       cfg.Clock,
+		Close:       closedts.AsCloseFn(tracker),
+		GlobalReads: cfg.GlobalReads,
+		MaxOffset:   cfg.MaxOffset,
 	}
 
 	provider := provider.NewProvider(&pConf)
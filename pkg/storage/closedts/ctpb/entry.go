@@ -47,7 +47,22 @@ func (e Entry) String() string {
 	if len(sl) == 0 {
 		sl = []string{"(empty)"}
 	}
-	return fmt.Sprintf("CT: %s @ Epoch %d\nFull: %t\nMLAI: %s\n", e.ClosedTimestamp, e.Epoch, e.Full, strings.Join(sl, ", "))
+	s := fmt.Sprintf("CT: %s @ Epoch %d\nFull: %t\nMLAI: %s\n", e.ClosedTimestamp, e.Epoch, e.Full, strings.Join(sl, ", "))
+	if len(e.GlobalReadsRanges) > 0 {
+		s += fmt.Sprintf("Global reads: %s for %v\n", e.GlobalReadsClosedTimestamp, e.GlobalReadsRanges)
+	}
+	return s
+}
+
+// IsGlobalReadsRange returns whether the given range is one of the Entry's
+// GlobalReadsRanges.
+func (e Entry) IsGlobalReadsRange(rangeID roachpb.RangeID) bool {
+	for _, id := range e.GlobalReadsRanges {
+		if id == rangeID {
+			return true
+		}
+	}
+	return false
 }
 
 func (r Reaction) String() string {
@@ -37,6 +37,12 @@ type Config struct {
 	Storage  closedts.Storage
 	Clock    closedts.LiveClockFn
 	Close    closedts.CloseFn
+	// GlobalReads, if set, determines the ranges that are published along
+	// with each update as configured for global reads.
+	GlobalReads closedts.GlobalReadsFn
+	// MaxOffset is the maximum clock offset of the cluster. See
+	// closedts.GlobalReadsLead.
+	MaxOffset time.Duration
 }
 
 type subscriber struct {
@@ -124,12 +130,28 @@ func (p *Provider) runCloser(ctx context.Context) {
 	}
 	closedts.TargetDuration.SetOnChange(&p.cfg.Settings.SV, confChanged)
 
+	// The state used to compute the closed timestamp published for ranges
+	// configured for global reads. Writes to these ranges are pushed by
+	// closedts.GlobalReadsLead above the clock reading they observe after
+	// being tracked. The writes that the update from an advancing Close does
+	// not cover were tracked after the previous advancing Close, so they were
+	// pushed above the clock reading taken before that Close plus the lead.
+	var lastClosed, lastCloseNow, globalReadsClosed hlc.Timestamp
+	// Whether the previous update listed any ranges configured for global
+	// reads. If so, updates are emitted at closedts.GlobalReadsCloseInterval.
+	var haveGlobalReads bool
+
 	var t timeutil.Timer
 	defer t.Stop()
 	for {
 		closeFraction := closedts.CloseFraction.Get(&p.cfg.Settings.SV)
 		targetDuration := float64(closedts.TargetDuration.Get(&p.cfg.Settings.SV))
-		t.Reset(time.Duration(closeFraction * targetDuration))
+		interval := time.Duration(closeFraction * targetDuration)
+		if globalInterval := closedts.GlobalReadsCloseInterval.Get(&p.cfg.Settings.SV); haveGlobalReads &&
+			globalInterval > 0 && globalInterval < interval {
+			interval = globalInterval
+		}
+		t.Reset(interval)
 
 		select {
 		case <-p.cfg.Stopper.ShouldQuiesce():
@@ -143,7 +165,8 @@ func (p *Provider) runCloser(ctx context.Context) {
 			continue
 		}
 
-		next, liveAtEpoch, err := p.cfg.Clock(p.cfg.NodeID)
+		now, liveAtEpoch, err := p.cfg.Clock(p.cfg.NodeID)
+		next := now
 		next.WallTime -= int64(targetDuration)
 		if err != nil {
 			if p.everyClockLog.ShouldLog() {
@@ -172,6 +195,21 @@ func (p *Provider) runCloser(ctx context.Context) {
 				ClosedTimestamp: closed,
 				MLAI:            m,
 			}
+			if lastClosed.Less(closed) {
+				if !lastCloseNow.IsEmpty() {
+					lead := closedts.GlobalReadsLead(&p.cfg.Settings.SV, p.cfg.MaxOffset)
+					globalReadsClosed = lastCloseNow.Add(lead.Nanoseconds(), 0)
+				}
+				lastClosed, lastCloseNow = closed, now
+			}
+			haveGlobalReads = false
+			if p.cfg.GlobalReads != nil && !globalReadsClosed.IsEmpty() {
+				entry.GlobalReadsRanges = p.cfg.GlobalReads(closed, globalReadsClosed)
+				if len(entry.GlobalReadsRanges) > 0 {
+					entry.GlobalReadsClosedTimestamp = globalReadsClosed
+					haveGlobalReads = true
+				}
+			}
 
 			// Simulate a subscription to the local node, so that the new information
 			// is added to the storage (and thus becomes available to future subscribers
@@ -337,12 +375,31 @@ func (p *Provider) Subscribe(ctx context.Context, ch chan<- ctpb.Entry) {
 // MaxClosed implements closedts.Provider.
 func (p *Provider) MaxClosed(
 	nodeID roachpb.NodeID, rangeID roachpb.RangeID, epoch ctpb.Epoch, lai ctpb.LAI,
+) hlc.Timestamp {
+	return p.maxClosed(nodeID, rangeID, epoch, lai, false /* globalReads */)
+}
+
+// MaxClosedGlobalReads implements closedts.Provider.
+func (p *Provider) MaxClosedGlobalReads(
+	nodeID roachpb.NodeID, rangeID roachpb.RangeID, epoch ctpb.Epoch, lai ctpb.LAI,
+) hlc.Timestamp {
+	return p.maxClosed(nodeID, rangeID, epoch, lai, true /* globalReads */)
+}
+
+func (p *Provider) maxClosed(
+	nodeID roachpb.NodeID, rangeID roachpb.RangeID, epoch ctpb.Epoch, lai ctpb.LAI, globalReads bool,
 ) hlc.Timestamp {
 	var maxTS hlc.Timestamp
 	p.cfg.Storage.VisitDescending(nodeID, func(entry ctpb.Entry) (done bool) {
 		if mlai, found := entry.MLAI[rangeID]; found {
 			if entry.Epoch == epoch && mlai <= lai {
 				maxTS = entry.ClosedTimestamp
+				if globalReads {
+					maxTS = hlc.Timestamp{}
+					if entry.IsGlobalReadsRange(rangeID) {
+						maxTS = entry.GlobalReadsClosedTimestamp
+					}
+				}
 				return true
 			}
 		}
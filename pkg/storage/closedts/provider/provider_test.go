@@ -286,3 +286,86 @@ func TestProviderSubscribeConcurrent(t *testing.T) {
 	stopper.Stop(context.Background())
 	wg.Wait()
 }
+
+// TestProviderGlobalReads verifies that the Provider publishes a closed
+// timestamp for ranges configured for global reads that is derived from the
+// clock reading taken before the previous Close that advanced the closed
+// timestamp, and only for the ranges that GlobalReadsFn returns.
+func TestProviderGlobalReads(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	st := cluster.MakeTestingClusterSettings()
+	closedts.TargetDuration.Override(&st.SV, time.Millisecond)
+	closedts.CloseFraction.Override(&st.SV, 1.0)
+	const maxOffset = 500 * time.Millisecond
+	lead := closedts.GlobalReadsLead(&st.SV, maxOffset).Nanoseconds()
+
+	clock := providertestutils.NewTestClock(stopper)
+	closeCh := make(chan hlc.Timestamp)
+	type globalReadsCall struct{ closed, globalReadsClosed hlc.Timestamp }
+	globalReadsCh := make(chan globalReadsCall, 1)
+	cfg := &provider.Config{
+		NodeID:   1,
+		Settings: st,
+		Stopper:  stopper,
+		Storage:  &providertestutils.TestStorage{},
+		Clock:    clock.LiveNow,
+		Close: func(next hlc.Timestamp, expCurEpoch ctpb.Epoch) (hlc.Timestamp, map[roachpb.RangeID]ctpb.LAI, bool) {
+			return <-closeCh, map[roachpb.RangeID]ctpb.LAI{1: 1, 2: 1}, true
+		},
+		GlobalReads: func(closed, globalReadsClosed hlc.Timestamp) []roachpb.RangeID {
+			globalReadsCh <- globalReadsCall{closed, globalReadsClosed}
+			return []roachpb.RangeID{1}
+		},
+		MaxOffset: maxOffset,
+	}
+
+	p := provider.NewProvider(cfg)
+	p.Start()
+
+	ts := func(sec int64) hlc.Timestamp { return hlc.Timestamp{WallTime: sec * 1e9} }
+	step := func(now, closed hlc.Timestamp) {
+		clock.Tick(now, ctpb.Epoch(1), nil)
+		closeCh <- closed
+	}
+	expectGlobalReads := func(closed, globalReadsClosed hlc.Timestamp) {
+		t.Helper()
+		exp := globalReadsCall{closed, globalReadsClosed}
+		if act := <-globalReadsCh; act != exp {
+			t.Fatalf("expected GlobalReadsFn to be called with %+v, got %+v", exp, act)
+		}
+	}
+
+	// Nothing is published before the second Close that advances the closed
+	// timestamp, since writes tracked before the first one aren't pushed
+	// above any clock reading the Provider knows about.
+	step(ts(10), ts(1))
+	// Writes not covered by the closed timestamp were pushed above the clock
+	// reading taken before the previous advancing Close.
+	step(ts(11), ts(2))
+	expectGlobalReads(ts(2), ts(10).Add(lead, 0))
+	// A Close that doesn't advance the closed timestamp doesn't change what
+	// is published, nor does it count as the previous advancing Close.
+	step(ts(12), ts(2))
+	expectGlobalReads(ts(2), ts(10).Add(lead, 0))
+	step(ts(13), ts(3))
+	expectGlobalReads(ts(3), ts(11).Add(lead, 0))
+
+	testutils.SucceedsSoon(t, func() error {
+		if maxClosed := p.MaxClosed(1, 2, 1, 1); maxClosed != ts(3) {
+			return errors.Errorf("expected closed timestamp %s for r2, got %s", ts(3), maxClosed)
+		}
+		return nil
+	})
+	if exp, act := ts(11).Add(lead, 0), p.MaxClosedGlobalReads(1, 1, 1, 1); exp != act {
+		t.Fatalf("expected global reads closed timestamp %s for r1, got %s", exp, act)
+	}
+	// r2 wasn't returned by GlobalReadsFn.
+	if act := p.MaxClosedGlobalReads(1, 2, 1, 1); !act.IsEmpty() {
+		t.Fatalf("expected no global reads closed timestamp for r2, got %s", act)
+	}
+}
@@ -36,3 +36,49 @@ var CloseFraction = settings.RegisterValidatedFloatSetting(
 		}
 		return nil
 	})
+
+// GlobalReadsPropagationSlack is the additional duration by which the closed
+// timestamps of ranges configured for global reads lead present time, to
+// account for delays in propagating closed timestamp updates to followers.
+var GlobalReadsPropagationSlack = settings.RegisterNonNegativeDurationSetting(
+	"kv.closed_timestamp.global_reads_propagation_slack",
+	"additional duration by which writes to ranges with global_reads enabled are pushed into the future to absorb closed timestamp propagation delays",
+	250*time.Millisecond,
+)
+
+// GlobalReadsCloseInterval is the interval at which a node that holds the
+// lease for ranges configured for global reads publishes closed timestamps.
+// It replaces the interval derived from TargetDuration and CloseFraction if it
+// is shorter.
+var GlobalReadsCloseInterval = settings.RegisterNonNegativeDurationSetting(
+	"kv.closed_timestamp.global_reads_close_interval",
+	"interval at which closed timestamp updates are emitted by nodes holding leases for ranges with global_reads enabled",
+	100*time.Millisecond,
+)
+
+// GlobalReadsLead returns the duration by which writes to ranges configured
+// for global reads (see config.ZoneConfig.GlobalReads) are pushed into the
+// future, which is also the duration by which the closed timestamps published
+// for them lead present time.
+//
+// A closed timestamp published for such a range is derived from a clock
+// reading taken at most two close intervals earlier, and reaches followers
+// after some propagation delay. Readers evaluate at a timestamp that may lead
+// the leaseholder's clock by the maximum clock offset and need to observe
+// writes up to the same offset above that timestamp. The lead covers all of
+// these, so that followers can serve reads at present time without contacting
+// the leaseholder. In exchange, the commit of a transaction that wrote to such
+// a range, or a non-transactional write to it, is not acknowledged until the
+// clock has passed its timestamp, so that the latency of a writing transaction
+// is close to the lead.
+//
+// The lead does not depend on TargetDuration, which only determines how far
+// the closed timestamps of all other ranges trail present time.
+//
+// Changing the settings this is based on while writes are in flight can
+// shrink the lead below closed timestamps that were already published. Such
+// changes should be followed by a pause of at least the old lead before
+// relying on follower reads of global ranges.
+func GlobalReadsLead(sv *settings.Values, maxOffset time.Duration) time.Duration {
+	return 2*maxOffset + 2*GlobalReadsCloseInterval.Get(sv) + GlobalReadsPropagationSlack.Get(sv)
+}
@@ -192,6 +192,10 @@ func merge(e, ee ctpb.Entry) ctpb.Entry {
 	// Use the larger of both timestamps with the union of the MLAIs, preferring larger
 	// ones on conflict.
 	re.ClosedTimestamp.Forward(ee.ClosedTimestamp)
+	// The global reads ranges aren't incremental, so the newer Entry's (i.e.
+	// ee's) replace those of the older one along with their closed timestamp.
+	re.GlobalReadsClosedTimestamp = ee.GlobalReadsClosedTimestamp
+	re.GlobalReadsRanges = ee.GlobalReadsRanges
 	for rangeID, mlai := range ee.MLAI {
 		if cur, found := re.MLAI[rangeID]; !found || cur < mlai {
 			re.MLAI[rangeID] = mlai
@@ -13,6 +13,7 @@ package storage
 import (
 	"fmt"
 	"math/rand"
+	"reflect"
 	"testing"
 	"time"
 
@@ -375,6 +376,45 @@ func TestZeroValueGetsStored(t *testing.T) {
 	}
 }
 
+// TestGlobalReadsRangesReplaced verifies that the global reads ranges of an
+// Entry, along with their closed timestamp, replace those of the preceding
+// Entries instead of being merged with them like the MLAIs.
+func TestGlobalReadsRangesReplaced(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ms := NewMultiStorage(func() SingleStorage {
+		return NewMemStorage(10*time.Second, 2)
+	})
+	ms.Add(1, ctpb.Entry{
+		Epoch:                      1,
+		Full:                       true,
+		ClosedTimestamp:            hlc.Timestamp{WallTime: 1e9},
+		MLAI:                       map[roachpb.RangeID]ctpb.LAI{1: 1, 2: 1},
+		GlobalReadsClosedTimestamp: hlc.Timestamp{WallTime: 5e9},
+		GlobalReadsRanges:          []roachpb.RangeID{1, 2},
+	})
+	ms.Add(1, ctpb.Entry{
+		Epoch:                      1,
+		ClosedTimestamp:            hlc.Timestamp{WallTime: 2e9},
+		MLAI:                       map[roachpb.RangeID]ctpb.LAI{1: 2},
+		GlobalReadsClosedTimestamp: hlc.Timestamp{WallTime: 6e9},
+		GlobalReadsRanges:          []roachpb.RangeID{2},
+	})
+	var entry ctpb.Entry
+	ms.VisitDescending(1, func(e ctpb.Entry) (done bool) {
+		entry = e
+		return true
+	})
+	if exp := (map[roachpb.RangeID]ctpb.LAI{1: 2, 2: 1}); !reflect.DeepEqual(exp, entry.MLAI) {
+		t.Errorf("expected MLAI %v, got %v", exp, entry.MLAI)
+	}
+	if entry.IsGlobalReadsRange(1) || !entry.IsGlobalReadsRange(2) {
+		t.Errorf("expected only r2 to be a global reads range, got %v", entry.GlobalReadsRanges)
+	}
+	if exp := (hlc.Timestamp{WallTime: 6e9}); entry.GlobalReadsClosedTimestamp != exp {
+		t.Errorf("expected global reads closed timestamp %s, got %s", exp, entry.GlobalReadsClosedTimestamp)
+	}
+}
+
 // TestConcurrent runs a very basic sanity check against a Storage, verifiying
 // that the bucketed Entries don't regress in obvious ways.
 func TestConcurrent(t *testing.T) {
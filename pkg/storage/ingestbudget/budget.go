@@ -0,0 +1,246 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package ingestbudget implements a per-store budget for SST ingestion.
+//
+// Bulk operations such as IMPORT and RESTORE ingest SSTs through AddSSTable
+// as fast as the stores accept them, which can saturate the disk and leave
+// the storage engine with a compaction debt that starves foreground traffic.
+// A Budget holds two token buckets: one refilled at a configurable rate with
+// the bytes a store may ingest, and one holding the bytes that can be
+// ingested before the engine's pending compactions exceed a configurable
+// limit, refilled as the engine's compactions catch up. Each ingestion draws
+// its size from both buckets and is delayed until both have tokens left.
+//
+// The store only holds on to a request for a bounded amount of time. Any
+// additional delay is returned to the client, which is expected to back off
+// accordingly before sending its next request.
+package ingestbudget
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// refreshInterval is the interval at which the Budget refreshes its view of
+// the engine's pending compactions.
+var refreshInterval = time.Second
+
+// StatsFunc returns the current storage engine metrics.
+type StatsFunc func() (*engine.Stats, error)
+
+// Budget delays SST ingestions into a store to keep them within the rate and
+// pending compaction limits configured for the store. The zero value is not
+// usable; use NewBudget.
+type Budget struct {
+	st      *cluster.Settings
+	statsFn StatsFunc
+	metrics Metrics
+
+	mu struct {
+		syncutil.Mutex
+		// byteTokens is the number of bytes that can be ingested right away.
+		// It goes negative when ingestions are admitted ahead of the rate, and
+		// later ingestions are delayed until it is positive again.
+		byteTokens float64
+		// lastRefill is the time at which byteTokens were last added.
+		lastRefill time.Time
+		// compactionTokens is the number of bytes that can be ingested before
+		// the engine's pending compactions exceed the limit, as of the last
+		// refresh less the bytes ingested since.
+		compactionTokens int64
+		// lastRefresh is the time at which compactionTokens were last
+		// recomputed from the engine's metrics.
+		lastRefresh time.Time
+	}
+}
+
+// NewBudget returns a Budget that uses statsFn to determine the storage
+// engine's pending compactions.
+func NewBudget(st *cluster.Settings, statsFn StatsFunc) *Budget {
+	return &Budget{
+		st:      st,
+		statsFn: statsFn,
+		metrics: makeMetrics(),
+	}
+}
+
+// Metrics returns the Budget's metrics.
+func (b *Budget) Metrics() *Metrics {
+	return &b.metrics
+}
+
+// Wait blocks until an ingestion of the given size fits in the budget, or
+// until the maximum delay for a single request has elapsed, in which case the
+// ingestion proceeds and the remaining delay is returned for the client to
+// back off by. If the context is canceled while waiting, its error is
+// returned, the tokens drawn for the ingestion are returned to the budget and
+// the ingestion must not proceed.
+func (b *Budget) Wait(ctx context.Context, size int64) (backoff time.Duration, _ error) {
+	if !enabled.Get(&b.st.SV) {
+		return 0, nil
+	}
+	start := timeutil.Now()
+	limit := maxWait.Get(&b.st.SV)
+	b.mu.Lock()
+	bytesDelay := b.reserveBytesLocked(start, size)
+	b.mu.Unlock()
+
+	var timer timeutil.Timer
+	defer timer.Stop()
+	// compactionsReserved is set once size has been drawn from the compaction
+	// bucket, after which further iterations only wait for the byte bucket.
+	var compactionsReserved bool
+	for {
+		now := timeutil.Now()
+		waited := now.Sub(start)
+		exhausted := waited >= limit
+		var compactionsDelay time.Duration
+		if !compactionsReserved {
+			b.mu.Lock()
+			compactionsDelay = b.reserveCompactionsLocked(ctx, now, size, exhausted)
+			b.mu.Unlock()
+			compactionsReserved = compactionsDelay <= 0 || exhausted
+		}
+
+		delay := bytesDelay - waited
+		if compactionsDelay > 0 && exhausted {
+			// The engine's compactions are too far behind for the store to tell
+			// when the ingestion would have fit, so ask the client to back off
+			// for as long as the store was willing to wait.
+			compactionsDelay = limit
+		}
+		if compactionsDelay > delay {
+			delay = compactionsDelay
+		}
+		if delay <= 0 || exhausted {
+			b.recordDelay(waited, bytesDelay)
+			if delay > 0 {
+				log.VEventf(ctx, 2, "ingestion budget exhausted, asking client to back off for %s", delay)
+				b.metrics.Backpressure.Inc(1)
+				b.metrics.BackpressureTime.Inc(delay.Nanoseconds())
+				return delay, nil
+			}
+			return 0, nil
+		}
+
+		if remaining := limit - waited; delay > remaining {
+			delay = remaining
+		}
+		log.VEventf(ctx, 2, "delaying ingestion of %d bytes by %s", size, delay)
+		timer.Reset(delay)
+		select {
+		case <-timer.C:
+			timer.Read = true
+		case <-ctx.Done():
+			b.recordDelay(timeutil.Since(start), bytesDelay)
+			b.mu.Lock()
+			b.refundLocked(size, compactionsReserved)
+			b.mu.Unlock()
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// Refund returns the tokens drawn by a successful Wait for an ingestion of the
+// given size which won't proceed after all, e.g. because its context was
+// canceled while waiting for other limits.
+func (b *Budget) Refund(size int64) {
+	if !enabled.Get(&b.st.SV) {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refundLocked(size, true /* compactionsReserved */)
+}
+
+// refundLocked returns the tokens drawn for an ingestion of the given size
+// which won't proceed after all.
+func (b *Budget) refundLocked(size int64, compactionsReserved bool) {
+	if !b.mu.lastRefill.IsZero() {
+		b.mu.byteTokens += float64(size)
+	}
+	if compactionsReserved && !b.mu.lastRefresh.IsZero() {
+		b.mu.compactionTokens += size
+	}
+}
+
+// recordDelay attributes the time waited first to the byte bucket and then
+// to the compaction bucket.
+func (b *Budget) recordDelay(waited, bytesDelay time.Duration) {
+	bytesWaited := waited
+	if bytesDelay < bytesWaited {
+		bytesWaited = bytesDelay
+	}
+	if bytesWaited < 0 {
+		bytesWaited = 0
+	}
+	b.metrics.BytesDelay.Inc(bytesWaited.Nanoseconds())
+	b.metrics.CompactionsDelay.Inc((waited - bytesWaited).Nanoseconds())
+}
+
+// reserveBytesLocked draws size bytes from the byte bucket and returns the
+// time until the bucket is no longer in debt.
+func (b *Budget) reserveBytesLocked(now time.Time, size int64) time.Duration {
+	rate := float64(maxRate.Get(&b.st.SV))
+	if rate <= 0 {
+		b.mu.lastRefill = time.Time{}
+		return 0
+	}
+	if b.mu.lastRefill.IsZero() {
+		b.mu.byteTokens = rate
+	} else {
+		elapsed := now.Sub(b.mu.lastRefill).Seconds()
+		b.mu.byteTokens = math.Min(b.mu.byteTokens+rate*elapsed, rate)
+	}
+	b.mu.lastRefill = now
+	b.mu.byteTokens -= float64(size)
+	if b.mu.byteTokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.mu.byteTokens / rate * float64(time.Second))
+}
+
+// reserveCompactionsLocked draws size bytes from the compaction bucket if it
+// isn't empty, or if force is set. It returns the time until the bucket is
+// next refreshed if it was empty.
+func (b *Budget) reserveCompactionsLocked(
+	ctx context.Context, now time.Time, size int64, force bool,
+) time.Duration {
+	limit := pendingCompactionLimit.Get(&b.st.SV)
+	if limit <= 0 {
+		b.mu.lastRefresh = time.Time{}
+		return 0
+	}
+	if b.mu.lastRefresh.IsZero() || now.Sub(b.mu.lastRefresh) >= refreshInterval {
+		if stats, err := b.statsFn(); err != nil {
+			log.Warningf(ctx, "failed to read engine stats: %+v", err)
+		} else {
+			b.mu.compactionTokens = limit - stats.PendingCompactionBytesEstimate
+		}
+		b.mu.lastRefresh = now
+	}
+	var delay time.Duration
+	if b.mu.compactionTokens <= 0 {
+		delay = b.mu.lastRefresh.Add(refreshInterval).Sub(now)
+		if !force {
+			return delay
+		}
+	}
+	b.mu.compactionTokens -= size
+	return delay
+}
@@ -0,0 +1,219 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ingestbudget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// testStats is a StatsFunc whose result can be changed by the test.
+type testStats struct {
+	syncutil.Mutex
+	stats engine.Stats
+}
+
+func (ts *testStats) set(pendingBytes int64) {
+	ts.Lock()
+	defer ts.Unlock()
+	ts.stats.PendingCompactionBytesEstimate = pendingBytes
+}
+
+func (ts *testStats) get() (*engine.Stats, error) {
+	ts.Lock()
+	defer ts.Unlock()
+	stats := ts.stats
+	return &stats, nil
+}
+
+func TestReserveBytes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	st := cluster.MakeTestingClusterSettings()
+	maxRate.Override(&st.SV, 100)
+	var ts testStats
+	b := NewBudget(st, ts.get)
+
+	now := timeutil.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// The bucket starts out full.
+	if delay := b.reserveBytesLocked(now, 100); delay != 0 {
+		t.Fatalf("expected no delay, got %s", delay)
+	}
+	// The bucket is now empty, so ingesting 50 more bytes means waiting for
+	// half a second.
+	if delay := b.reserveBytesLocked(now, 50); delay != 500*time.Millisecond {
+		t.Fatalf("expected 500ms delay, got %s", delay)
+	}
+	// A second later, the debt has been paid off and there are 50 tokens left.
+	now = now.Add(time.Second)
+	if delay := b.reserveBytesLocked(now, 50); delay != 0 {
+		t.Fatalf("expected no delay, got %s", delay)
+	}
+	// The bucket doesn't accrue more than one second's worth of tokens.
+	now = now.Add(time.Hour)
+	if delay := b.reserveBytesLocked(now, 200); delay != time.Second {
+		t.Fatalf("expected 1s delay, got %s", delay)
+	}
+
+	// A rate of zero disables the limit.
+	maxRate.Override(&st.SV, 0)
+	if delay := b.reserveBytesLocked(now, 1<<30); delay != 0 {
+		t.Fatalf("expected no delay, got %s", delay)
+	}
+}
+
+func TestReserveCompactions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	pendingCompactionLimit.Override(&st.SV, 1000)
+	var ts testStats
+	ts.set(800)
+	b := NewBudget(st, ts.get)
+
+	now := timeutil.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// There's room for 200 more bytes, but the bucket may go into debt.
+	if delay := b.reserveCompactionsLocked(ctx, now, 300, false /* force */); delay != 0 {
+		t.Fatalf("expected no delay, got %s", delay)
+	}
+	// The bucket is empty until the next refresh.
+	if delay := b.reserveCompactionsLocked(ctx, now, 100, false /* force */); delay != refreshInterval {
+		t.Fatalf("expected %s delay, got %s", refreshInterval, delay)
+	}
+	// A forced reservation goes through anyway.
+	if delay := b.reserveCompactionsLocked(ctx, now, 100, true /* force */); delay != refreshInterval {
+		t.Fatalf("expected %s delay, got %s", refreshInterval, delay)
+	}
+	if b.mu.compactionTokens != -200 {
+		t.Fatalf("expected -200 tokens, got %d", b.mu.compactionTokens)
+	}
+	// Once the compactions catch up, the bucket is refilled at the next
+	// refresh.
+	ts.set(0)
+	now = now.Add(refreshInterval / 2)
+	if delay := b.reserveCompactionsLocked(ctx, now, 100, false /* force */); delay != refreshInterval/2 {
+		t.Fatalf("expected %s delay, got %s", refreshInterval/2, delay)
+	}
+	now = now.Add(refreshInterval / 2)
+	if delay := b.reserveCompactionsLocked(ctx, now, 100, false /* force */); delay != 0 {
+		t.Fatalf("expected no delay, got %s", delay)
+	}
+	if b.mu.compactionTokens != 900 {
+		t.Fatalf("expected 900 tokens, got %d", b.mu.compactionTokens)
+	}
+}
+
+func TestWaitBackpressure(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	maxRate.Override(&st.SV, 1000)
+	pendingCompactionLimit.Override(&st.SV, 0)
+	var ts testStats
+	b := NewBudget(st, ts.get)
+
+	// Without any delay imposed by the store, a request that exhausts the
+	// budget leaves its client to back off by the full delay.
+	maxWait.Override(&st.SV, 0)
+	if backoff, err := b.Wait(ctx, 1000); err != nil || backoff != 0 {
+		t.Fatalf("expected no backoff, got %s, %v", backoff, err)
+	}
+	backoff, err := b.Wait(ctx, 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backoff < 9*time.Second || backoff > 10*time.Second {
+		t.Fatalf("expected backoff of about 10s, got %s", backoff)
+	}
+	if n := b.Metrics().Backpressure.Count(); n != 1 {
+		t.Fatalf("expected 1 backpressured request, got %d", n)
+	}
+
+	// A canceled request returns the context's error and refunds its tokens.
+	maxWait.Override(&st.SV, time.Hour)
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	b.mu.Lock()
+	tokens := b.mu.byteTokens
+	b.mu.Unlock()
+	if _, err := b.Wait(cancelCtx, 1); err != context.Canceled {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+	b.mu.Lock()
+	refunded := b.mu.byteTokens
+	b.mu.Unlock()
+	// The bucket was refilled for the time elapsed since the last reservation,
+	// but the canceled request mustn't have drawn from it.
+	if refunded < tokens {
+		t.Fatalf("expected canceled request's tokens to be refunded, had %f, got %f", tokens, refunded)
+	}
+
+	// A request that doesn't proceed after waiting can refund its tokens.
+	maxWait.Override(&st.SV, 0)
+	b.mu.Lock()
+	tokens = b.mu.byteTokens
+	b.mu.Unlock()
+	if _, err := b.Wait(ctx, 5000); err != nil {
+		t.Fatal(err)
+	}
+	b.Refund(5000)
+	b.mu.Lock()
+	refunded = b.mu.byteTokens
+	b.mu.Unlock()
+	if refunded < tokens {
+		t.Fatalf("expected refunded tokens, had %f, got %f", tokens, refunded)
+	}
+
+	// The budget can be disabled.
+	enabled.Override(&st.SV, false)
+	if backoff, err := b.Wait(ctx, 1<<30); err != nil || backoff != 0 {
+		t.Fatalf("expected no backoff, got %s, %v", backoff, err)
+	}
+}
+
+func TestWaitReservesCompactionsOnce(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	maxRate.Override(&st.SV, 1000)
+	pendingCompactionLimit.Override(&st.SV, 1<<20)
+	maxWait.Override(&st.SV, time.Hour)
+	var ts testStats
+	b := NewBudget(st, ts.get)
+
+	// Drain the byte bucket, so that the next request waits for 100ms while
+	// the compaction bucket has room for it.
+	if backoff, err := b.Wait(ctx, 1000); err != nil || backoff != 0 {
+		t.Fatalf("expected no backoff, got %s, %v", backoff, err)
+	}
+	if backoff, err := b.Wait(ctx, 100); err != nil || backoff != 0 {
+		t.Fatalf("expected no backoff, got %s, %v", backoff, err)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if expected := int64(1<<20 - 1100); b.mu.compactionTokens != expected {
+		t.Fatalf("expected %d tokens, got %d", expected, b.mu.compactionTokens)
+	}
+}
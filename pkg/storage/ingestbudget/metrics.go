@@ -0,0 +1,62 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ingestbudget
+
+import "github.com/cockroachdb/cockroach/pkg/util/metric"
+
+// Metrics holds all metrics relating to an ingestion Budget.
+type Metrics struct {
+	BytesDelay       *metric.Counter
+	CompactionsDelay *metric.Counter
+	Backpressure     *metric.Counter
+	BackpressureTime *metric.Counter
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (Metrics) MetricStruct() {}
+
+var _ metric.Struct = Metrics{}
+
+var (
+	metaBytesDelay = metric.Metadata{
+		Name:        "addsstable.budget.delay.bytes",
+		Help:        "Amount by which AddSSTable requests were delayed by the store's ingestion rate limit",
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaCompactionsDelay = metric.Metadata{
+		Name:        "addsstable.budget.delay.compactions",
+		Help:        "Amount by which AddSSTable requests were delayed by the store's pending compaction limit",
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaBackpressure = metric.Metadata{
+		Name:        "addsstable.budget.backpressure",
+		Help:        "Number of AddSSTable requests whose clients were asked to back off",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaBackpressureTime = metric.Metadata{
+		Name:        "addsstable.budget.backpressure_time",
+		Help:        "Amount by which clients of AddSSTable requests were asked to back off",
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+)
+
+func makeMetrics() Metrics {
+	return Metrics{
+		BytesDelay:       metric.NewCounter(metaBytesDelay),
+		CompactionsDelay: metric.NewCounter(metaCompactionsDelay),
+		Backpressure:     metric.NewCounter(metaBackpressure),
+		BackpressureTime: metric.NewCounter(metaBackpressureTime),
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ingestbudget
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+)
+
+var enabled = settings.RegisterBoolSetting(
+	"kv.bulk_ingest.budget.enabled",
+	"when true, AddSSTable requests are delayed to keep each store within its ingestion budget",
+	true,
+)
+
+// maxRate is the rate at which the byte bucket is refilled. It also bounds
+// the bucket's capacity, which allows for one second's worth of burst.
+var maxRate = settings.RegisterByteSizeSetting(
+	"kv.bulk_ingest.budget.max_rate",
+	"the rate limit (bytes/sec) for SSTs ingested by a store; 0 disables the limit",
+	128<<20, /* 128 MiB */
+)
+
+// pendingCompactionLimit is the estimate of pending compaction bytes which
+// ingestions are allowed to build up to.
+var pendingCompactionLimit = settings.RegisterByteSizeSetting(
+	"kv.bulk_ingest.budget.pending_compaction_limit",
+	"pending compaction estimate above which a store delays SST ingestion; 0 disables the limit",
+	4<<30, /* 4 GiB */
+)
+
+// maxWait bounds the time for which the store delays a single request. Any
+// additional delay is returned to the client as backpressure.
+var maxWait = settings.RegisterNonNegativeDurationSetting(
+	"kv.bulk_ingest.budget.max_wait",
+	"maximum time a store delays a single AddSSTable request before asking the client to back off instead",
+	5*time.Second,
+)
@@ -312,6 +312,17 @@ type Replica struct {
 		minLeaseProposedTS hlc.Timestamp
 		// A pointer to the zone config for this replica.
 		zone *config.ZoneConfig
+		// The state used to publish closed timestamps leading present time while
+		// the replica holds the lease and the zone config enables global reads.
+		// See forwardWriteForGlobalReads and publishGlobalReadsClosed.
+		globalReads struct {
+			// The highest minimum proposal timestamp of the writes that were
+			// evaluated while the zone config did not enable global reads.
+			lastUnpushedWrite hlc.Timestamp
+			// The highest closed timestamp published for the range as
+			// configured for global reads.
+			closed hlc.Timestamp
+		}
 		// proposalBuf buffers Raft commands as they are passed to the Raft
 		// replication subsystem. The buffer is populated by requests after
 		// evaluation and is consumed by the Raft processing thread. Once
@@ -674,8 +685,10 @@ func (r *Replica) GetMaxBytes() int64 {
 // SetZoneConfig sets the replica's zone config.
 func (r *Replica) SetZoneConfig(zone *config.ZoneConfig) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 	r.mu.zone = zone
+	globalReads := r.isGlobalReadsRangeRLocked()
+	r.mu.Unlock()
+	r.store.updateReplicaWithGlobalReads(r.RangeID, globalReads)
 }
 
 // IsFirstRange returns true if this is the first range.
@@ -757,6 +770,12 @@ func (r *Replica) Engine() engine.Engine {
 	return r.store.Engine()
 }
 
+// RaftEngine returns the Engine holding the Replica's Raft log. It is the same
+// as Engine() unless the Raft log is kept in a separate engine.
+func (r *Replica) RaftEngine() engine.Engine {
+	return r.store.RaftEngine()
+}
+
 // AbortSpan returns the Replica's AbortSpan.
 func (r *Replica) AbortSpan() *abortspan.AbortSpan {
 	// Despite its name, the AbortSpan doesn't hold on-disk data in
@@ -872,6 +891,31 @@ func (r *Replica) GetSplitQPS() float64 {
 	return r.loadBasedSplitter.LastQPS(timeutil.Now())
 }
 
+// GetHotKeys returns the keys most frequently accessed in the Replica over the
+// last few seconds, as sampled by the load-based splitter, with their estimated
+// share of the given range QPS. Unlike GetSplitQPS, it does not depend on the
+// load based splitting cluster setting or the split threshold. The returned
+// keys are addressed and within the range's bounds.
+func (r *Replica) GetHotKeys(now time.Time, qps float64) []HotKeyInfo {
+	counts, total := r.loadBasedSplitter.HotKeys(now)
+	if total == 0 {
+		return nil
+	}
+	desc := r.Desc()
+	hotKeys := make([]HotKeyInfo, 0, len(counts))
+	for _, kc := range counts {
+		// Report range-local keys as the key they are anchored at, and skip keys
+		// that were sampled before a split moved them to another range.
+		key, err := keys.Addr(kc.Key)
+		if err != nil || !desc.ContainsKey(key) {
+			continue
+		}
+		fraction := float64(kc.Count) / float64(total)
+		hotKeys = append(hotKeys, HotKeyInfo{Key: key.AsRawKey(), QPS: fraction * qps, Fraction: fraction})
+	}
+	return hotKeys
+}
+
 // ContainsKey returns whether this range contains the specified key.
 //
 // TODO(bdarnell): This is not the same as RangeDescriptor.ContainsKey.
@@ -1350,14 +1394,18 @@ func (r *Replica) beginCmds(
 		log.Event(ctx, "operation accepts inconsistent results")
 	}
 
-	// Handle load-based splitting.
+	// Handle load-based splitting. The keys accessed by the batch are sampled
+	// for GetHotKeys even while it is disabled.
+	boundarySpan := func() roachpb.Span {
+		return spans.BoundarySpan(spanset.SpanGlobal)
+	}
 	if r.SplitByLoadEnabled() {
-		shouldInitSplit := r.loadBasedSplitter.Record(timeutil.Now(), len(ba.Requests), func() roachpb.Span {
-			return spans.BoundarySpan(spanset.SpanGlobal)
-		})
+		shouldInitSplit := r.loadBasedSplitter.Record(timeutil.Now(), len(ba.Requests), boundarySpan)
 		if shouldInitSplit {
 			r.store.splitQueue.MaybeAddAsync(ctx, r, r.store.Clock().Now())
 		}
+	} else {
+		r.loadBasedSplitter.RecordHotKeys(timeutil.Now(), boundarySpan)
 	}
 
 	ec := endCmds{
@@ -1501,6 +1549,12 @@ func (r *Replica) limitTxnMaxTimestamp(
 	if ba.Txn == nil {
 		return
 	}
+	// Writes to global reads ranges are performed at future timestamps, so
+	// observing the leaseholder's clock doesn't bound the timestamps of values
+	// that were written before the observation.
+	if r.isGlobalReadsRange() {
+		return
+	}
 	// For calls that read data within a txn, we keep track of timestamps
 	// observed from the various participating nodes' HLC clocks. If we have
 	// a timestamp on file for this Node which is smaller than MaxTimestamp,
@@ -249,9 +249,22 @@ func (r *Replica) handleTruncatedStateResult(
 	ctx context.Context, t *roachpb.RaftTruncatedState,
 ) (raftLogDelta int64) {
 	r.mu.Lock()
+	prev := r.mu.state.TruncatedState
 	r.mu.state.TruncatedState = t
 	r.mu.Unlock()
 
+	// If the Raft log is kept in a separate engine, the truncated entries
+	// weren't cleared along with the application of the truncation. Clear them
+	// now that the new truncated state is durable.
+	if r.store.separateRaftLog() && prev != nil {
+		if err := r.store.clearSeparateRaftLogEntries(
+			ctx, &r.raftMu.stateLoader.RangeIDPrefixBuf, prev.Index+1, t.Index, false, /* sync */
+		); err != nil {
+			// Leftover entries are removed when the store restarts.
+			log.Errorf(ctx, "while clearing truncated Raft entries: %+v", err)
+		}
+	}
+
 	// Clear any entries in the Raft log entry cache for this range up
 	// to and including the most recently truncated index.
 	r.store.raftEntryCache.Clear(r.RangeID, t.Index+1)
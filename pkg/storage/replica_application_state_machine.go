@@ -379,6 +379,10 @@ type replicaAppBatch struct {
 	// changeRemovesReplica tracks whether the command in the batch (there must
 	// be only one) removes this replica from the range.
 	changeRemovesReplica bool
+	// clearsSeparateRaftLog tracks whether any command in the batch causes
+	// entries to be cleared from a Raft log kept in a separate engine, which
+	// happens after the batch commits. If so, the batch is synced.
+	clearsSeparateRaftLog bool
 
 	// Statistics.
 	entries      int
@@ -638,6 +642,10 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 		// be careful.
 		const clearRangeIDLocalOnly = true
 		const mustClearRange = false
+		if b.r.store.separateRaftLog() {
+			// The RHS's Raft log is cleared by postDestroyRaftMuLocked.
+			b.clearsSeparateRaftLog = true
+		}
 		if err := rhsRepl.preDestroyRaftMuLocked(
 			ctx, b.batch, b.batch, mergedTombstoneReplicaID, clearRangeIDLocalOnly, mustClearRange,
 		); err != nil {
@@ -646,8 +654,16 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 	}
 
 	if res.State != nil && res.State.TruncatedState != nil {
+		var raftLogWriter engine.Writer = b.batch
+		if b.r.store.separateRaftLog() {
+			// The entries are cleared from the Raft log engine once the batch
+			// is durably committed. See handleTruncatedStateResult.
+			raftLogWriter = nil
+			b.clearsSeparateRaftLog = true
+		}
 		if apply, err := handleTruncatedStateBelowRaft(
 			ctx, b.state.TruncatedState, res.State.TruncatedState, b.r.raftMu.stateLoader, b.batch,
+			raftLogWriter,
 		); err != nil {
 			return wrapWithNonDeterministicFailure(err, "unable to handle truncated state")
 		} else if !apply {
@@ -819,8 +835,9 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 	// applied again upon startup. However, if we're removing the replica's data
 	// then we sync this batch as it is not safe to call postDestroyRaftMuLocked
 	// before ensuring that the replica's data has been synchronously removed.
-	// See handleChangeReplicasResult().
-	sync := b.changeRemovesReplica
+	// See handleChangeReplicasResult(). Similarly, entries of a Raft log kept
+	// in a separate engine are only cleared once the batch has been synced.
+	sync := b.changeRemovesReplica || b.clearsSeparateRaftLog
 	if err := b.batch.Commit(sync); err != nil {
 		return wrapWithNonDeterministicFailure(err, "unable to commit Raft entry batch")
 	}
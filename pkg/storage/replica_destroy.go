@@ -110,6 +110,14 @@ func (r *Replica) postDestroyRaftMuLocked(ctx context.Context, ms enginepb.MVCCS
 		})
 	}
 
+	// A Raft log kept in a separate engine isn't cleared along with the rest
+	// of the replica's data.
+	if r.store.separateRaftLog() {
+		if err := r.store.clearSeparateRaftLog(ctx, r.RangeID); err != nil {
+			return err
+		}
+	}
+
 	// NB: we need the nil check below because it's possible that we're GC'ing a
 	// Replica without a replicaID, in which case it does not have a sideloaded
 	// storage.
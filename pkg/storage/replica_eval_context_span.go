@@ -94,6 +94,11 @@ func (rec *SpanSetReplicaEvalContext) Engine() engine.Engine {
 	return rec.i.Engine()
 }
 
+// RaftEngine returns the engine holding the Raft log.
+func (rec *SpanSetReplicaEvalContext) RaftEngine() engine.Engine {
+	return rec.i.RaftEngine()
+}
+
 // GetFirstIndex returns the first index.
 func (rec *SpanSetReplicaEvalContext) GetFirstIndex() (uint64, error) {
 	return rec.i.GetFirstIndex()
@@ -12,13 +12,16 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage/closedts"
 	"github.com/cockroachdb/cockroach/pkg/storage/closedts/ctpb"
 	ctstorage "github.com/cockroachdb/cockroach/pkg/storage/closedts/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
 // FollowerReadsEnabled controls whether replicas attempt to serve follower
@@ -33,10 +36,11 @@ var FollowerReadsEnabled = settings.RegisterBoolSetting(
 
 // canServeFollowerRead tests, when a range lease could not be
 // acquired, whether the read only batch can be served as a follower
-// read despite the error.
+// read despite the error. If so, it also returns whether the lease
+// holder published the range as configured for global reads.
 func (r *Replica) canServeFollowerRead(
 	ctx context.Context, ba *roachpb.BatchRequest, pErr *roachpb.Error,
-) *roachpb.Error {
+) (globalReads bool, _ *roachpb.Error) {
 	// There's no known reason that a non-VOTER_FULL replica couldn't serve follower
 	// reads (or RangeFeed), but as of the time of writing, these are expected
 	// to be short-lived, so it's not worth working out the edge-cases. Revisit if
@@ -44,11 +48,11 @@ func (r *Replica) canServeFollowerRead(
 	// to be able to serve follower reads.
 	repDesc, err := r.GetReplicaDescriptor()
 	if err != nil {
-		return roachpb.NewError(err)
+		return false, roachpb.NewError(err)
 	}
 	if typ := repDesc.GetType(); typ != roachpb.VOTER_FULL {
 		log.Eventf(ctx, "%s replicas cannot serve follower reads", typ)
-		return pErr
+		return false, pErr
 	}
 
 	canServeFollowerRead := false
@@ -63,7 +67,10 @@ func (r *Replica) canServeFollowerRead(
 			ts.Forward(ba.Txn.MaxTimestamp)
 		}
 
-		canServeFollowerRead = !r.maxClosed(ctx).Less(ts)
+		maxClosed, globalReadsClosed := r.maxClosedAndGlobalReadsClosed(ctx)
+		maxClosed.Forward(globalReadsClosed)
+		globalReads = !globalReadsClosed.IsEmpty()
+		canServeFollowerRead = !maxClosed.Less(ts)
 		if !canServeFollowerRead {
 			// We can't actually serve the read based on the closed timestamp.
 			// Signal the clients that we want an update so that future requests can succeed.
@@ -83,7 +90,7 @@ func (r *Replica) canServeFollowerRead(
 
 	if !canServeFollowerRead {
 		// We couldn't do anything with the error, propagate it.
-		return pErr
+		return false, pErr
 	}
 
 	// This replica can serve this read!
@@ -91,7 +98,7 @@ func (r *Replica) canServeFollowerRead(
 	// TODO(tschottdorf): once a read for a timestamp T has been served, the replica may
 	// serve reads for that and smaller timestamps forever.
 	log.Event(ctx, "serving via follower read")
-	return nil
+	return globalReads, nil
 }
 
 // maxClosed returns the maximum closed timestamp for this range.
@@ -101,15 +108,147 @@ func (r *Replica) canServeFollowerRead(
 // start time of the current lease because leasePostApply bumps the timestamp
 // cache forward to at least the new lease start time. Using this combination
 // allows the closed timestamp mechanism to be robust to lease transfers.
+//
+// If the lease holder published the range as configured for global reads, the
+// closed timestamp it published for such ranges is used as well, which leads
+// present time because the lease holder pushes all writes to them above it.
+// The zone config of this replica does not matter: only the lease holder
+// knows whether its writes were pushed.
 func (r *Replica) maxClosed(ctx context.Context) hlc.Timestamp {
+	maxClosed, globalReadsClosed := r.maxClosedAndGlobalReadsClosed(ctx)
+	maxClosed.Forward(globalReadsClosed)
+	return maxClosed
+}
+
+// maxClosedAndGlobalReadsClosed returns the maximum closed timestamp for this
+// range without the one published for it as configured for global reads, and
+// the latter separately. The latter is zero unless the lease holder published
+// the range as such.
+func (r *Replica) maxClosedAndGlobalReadsClosed(
+	ctx context.Context,
+) (maxClosed, globalReadsClosed hlc.Timestamp) {
 	r.mu.RLock()
 	lai := r.mu.state.LeaseAppliedIndex
 	lease := *r.mu.state.Lease
 	initialMaxClosed := r.mu.initialMaxClosed
 	r.mu.RUnlock()
-	maxClosed := r.store.cfg.ClosedTimestamp.Provider.MaxClosed(
+	maxClosed = r.store.cfg.ClosedTimestamp.Provider.MaxClosed(
 		lease.Replica.NodeID, r.RangeID, ctpb.Epoch(lease.Epoch), ctpb.LAI(lai))
 	maxClosed.Forward(lease.Start)
 	maxClosed.Forward(initialMaxClosed)
-	return maxClosed
+	globalReadsClosed = r.store.cfg.ClosedTimestamp.Provider.MaxClosedGlobalReads(
+		lease.Replica.NodeID, r.RangeID, ctpb.Epoch(lease.Epoch), ctpb.LAI(lai))
+	return maxClosed, globalReadsClosed
+}
+
+// isGlobalReadsRange returns whether the range's zone config asks for reads
+// to be served consistently by all replicas without blocking on writes.
+func (r *Replica) isGlobalReadsRange() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.isGlobalReadsRangeRLocked()
+}
+
+func (r *Replica) isGlobalReadsRangeRLocked() bool {
+	return r.mu.zone.GlobalReads != nil && *r.mu.zone.GlobalReads
+}
+
+// forwardWriteForGlobalReads forwards the minimum timestamp of a write so that
+// it does not invalidate the closed timestamps published for the range as
+// configured for global reads.
+//
+// If the zone config enables global reads, the write is pushed the lead past
+// the present, which lets publishGlobalReadsClosed include the range once the
+// regular closed timestamp covers all earlier writes. Otherwise, the write is
+// recorded so that the range is not published until the regular closed
+// timestamp covers it, and it is forwarded above the closed timestamp that was
+// published before global reads were disabled.
+//
+// The closed timestamps published under a previous lease don't need to be
+// considered here, regardless of whether this replica's zone config agrees
+// with the one of the previous lease holder: every lease starts above them
+// (see publishGlobalReadsClosed), and the timestamp cache forwards all writes
+// past the start of the lease.
+//
+// Writes that end up at a future timestamp must not be acknowledged before it
+// has become present. See commitWait.
+func (r *Replica) forwardWriteForGlobalReads(minTS hlc.Timestamp) hlc.Timestamp {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.isGlobalReadsRangeRLocked() {
+		lead := r.globalReadsLead().Nanoseconds()
+		minTS.Forward(r.store.Clock().Now().Add(lead, 0).Next())
+		return minTS
+	}
+	r.mu.globalReads.lastUnpushedWrite.Forward(minTS)
+	if !r.mu.globalReads.closed.IsEmpty() {
+		minTS.Forward(r.mu.globalReads.closed.Next())
+	}
+	return minTS
+}
+
+// publishGlobalReadsClosed returns whether the closed timestamp update
+// closing the timestamp closed can publish globalReadsClosed for the range as
+// configured for global reads. This is the case if the zone config enables
+// global reads, all writes that were not pushed by the lead are covered by the
+// update, and the replica holds a lease that is still valid at
+// globalReadsClosed. If so, the replica makes sure that all subsequent writes
+// are evaluated above globalReadsClosed. See closedts.GlobalReadsFn.
+//
+// Requiring the lease to be valid at globalReadsClosed fences off the writes
+// of later lease holders, which may not consider the range configured for
+// global reads: a lease acquired after this one has expired starts above
+// globalReadsClosed, and a lease transferred away by this replica is made to
+// start above it as well (see pendingLeaseRequest.InitOrJoinRequest). Both the
+// followers and the new lease holder treat the start of a lease as closed.
+func (r *Replica) publishGlobalReadsClosed(closed, globalReadsClosed hlc.Timestamp) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.isGlobalReadsRangeRLocked() || closed.Less(r.mu.globalReads.lastUnpushedWrite) ||
+		!r.ownsValidLeaseRLocked(globalReadsClosed) {
+		return false
+	}
+	r.mu.globalReads.closed.Forward(globalReadsClosed)
+	return true
+}
+
+// globalReadsLead returns the duration by which writes to global reads ranges
+// are pushed into the future. See closedts.GlobalReadsLead.
+func (r *Replica) globalReadsLead() time.Duration {
+	return closedts.GlobalReadsLead(&r.store.cfg.Settings.SV, r.store.Clock().MaxOffset())
+}
+
+// commitWait blocks until the local clock has passed the timestamp at which a
+// batch committed its writes. Writes to ranges configured for global reads are
+// pushed into the future, and so are the transactions that perform them and
+// the writes that follow a lease transfer of such a range. Acknowledging them
+// before their timestamp has become present would allow a causally dependent
+// operation to be assigned a lower timestamp, or the client's clock to be
+// pushed ahead of everyone else's.
+//
+// Only the response that makes the writes visible needs to wait, i.e. that of
+// a non-transactional write or of a transaction's commit: intents aren't
+// visible before the commit, whose timestamp is at least that of each of them.
+func (r *Replica) commitWait(ctx context.Context, br *roachpb.BatchResponse) *roachpb.Error {
+	ts := br.Timestamp
+	if br.Txn != nil {
+		ts.Forward(br.Txn.WriteTimestamp)
+	}
+	wait := ts.GoTime().Sub(r.store.Clock().PhysicalTime())
+	if wait <= 0 {
+		return nil
+	}
+	log.VEventf(ctx, 2, "waiting %s for write at %s to become present", wait, ts)
+	t := timeutil.NewTimer()
+	defer t.Stop()
+	t.Reset(wait)
+	select {
+	case <-t.C:
+		t.Read = true
+		return nil
+	case <-ctx.Done():
+		return roachpb.NewError(roachpb.NewAmbiguousResultError(ctx.Err().Error()))
+	case <-r.store.stopper.ShouldQuiesce():
+		return roachpb.NewError(roachpb.NewAmbiguousResultError("server shutdown"))
+	}
 }
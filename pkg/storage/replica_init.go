@@ -132,7 +132,7 @@ func (r *Replica) initRaftMuLockedReplicaMuLocked(
 
 	r.rangeStr.store(0, r.mu.state.Desc)
 
-	r.mu.lastIndex, err = r.mu.stateLoader.LoadLastIndex(ctx, r.store.Engine())
+	r.mu.lastIndex, err = r.mu.stateLoader.LoadLastIndex(ctx, r.store.Engine(), r.store.RaftEngine())
 	if err != nil {
 		return err
 	}
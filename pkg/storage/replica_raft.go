@@ -651,10 +651,22 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 	// which passes the reads through to the underlying DB.
 	batch := r.store.Engine().NewWriteOnlyBatch()
 	defer batch.Close()
+	// The log entries are written to raftBatch, which is batch itself unless
+	// the Raft log is kept in a separate engine.
+	raftBatch := batch
+	if r.store.separateRaftLog() {
+		raftBatch = r.store.RaftEngine().NewWriteOnlyBatch()
+		defer raftBatch.Close()
+	}
 
 	// We know that all of the writes from here forward will be to distinct keys.
 	writer := batch.Distinct()
+	raftWriter := writer
+	if raftBatch != batch {
+		raftWriter = raftBatch.Distinct()
+	}
 	prevLastIndex := lastIndex
+	var hardStateMustSync bool
 	if len(rd.Entries) > 0 {
 		// All of the entries are appended to distinct keys, returning a new
 		// last index.
@@ -665,7 +677,7 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 		}
 		raftLogSize += sideLoadedEntriesSize
 		if lastIndex, lastTerm, raftLogSize, err = r.append(
-			ctx, writer, lastIndex, lastTerm, raftLogSize, thinEntries,
+			ctx, raftWriter, lastIndex, lastTerm, raftLogSize, thinEntries,
 		); err != nil {
 			const expl = "during append"
 			return stats, expl, errors.Wrap(err, expl)
@@ -681,14 +693,28 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 		// Ready. If we persist the HardState but happen to lose the Entries,
 		// assertions can be tripped.
 		//
-		// We have both in the same batch, so there's no problem. If that ever
-		// changes, we must write and sync the Entries before the HardState.
+		// We have both in the same batch, so there's no problem, unless the
+		// Raft log is kept in a separate engine. In that case, the Entries are
+		// committed and synced before the HardState below.
+		if raftBatch != batch {
+			prevHardState, err := r.raftMu.stateLoader.LoadHardState(ctx, r.store.Engine())
+			if err != nil {
+				const expl = "during loadHardState"
+				return stats, expl, errors.Wrap(err, expl)
+			}
+			// Only changes to the term and vote need to be synced. The commit
+			// index can be recovered after a restart (see above).
+			hardStateMustSync = raft.MustSync(rd.HardState, prevHardState, 0 /* entsnum */)
+		}
 		if err := r.raftMu.stateLoader.SetHardState(ctx, writer, rd.HardState); err != nil {
 			const expl = "during setHardState"
 			return stats, expl, errors.Wrap(err, expl)
 		}
 	}
 	writer.Close()
+	if raftWriter != writer {
+		raftWriter.Close()
+	}
 	// Synchronously commit the batch with the Raft log entries and Raft hard
 	// state as we're promising not to lose this data.
 	//
@@ -703,7 +729,15 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 	// were not persisted to disk, it wouldn't be a problem because raft does not
 	// infer the that entries are persisted on the node that sends a snapshot.
 	commitStart := timeutil.Now()
-	if err := batch.Commit(rd.MustSync && !disableSyncRaftLog.Get(&r.store.cfg.Settings.SV)); err != nil {
+	sync := rd.MustSync && !disableSyncRaftLog.Get(&r.store.cfg.Settings.SV)
+	if raftBatch != batch {
+		if err := raftBatch.Commit(sync); err != nil {
+			const expl = "while committing raft log batch"
+			return stats, expl, errors.Wrap(err, expl)
+		}
+		sync = sync && hardStateMustSync
+	}
+	if err := batch.Commit(sync); err != nil {
 		const expl = "while committing batch"
 		return stats, expl, errors.Wrap(err, expl)
 	}
@@ -1659,11 +1693,16 @@ func (r *Replica) acquireMergeLock(
 // the associated RaftLogDelta. It is usually expected to be true, but may not
 // be for the first truncation after on a replica that recently received a
 // snapshot.
+//
+// The truncated entries are cleared through raftLogWriter, which is usually
+// the batch itself. If the Raft log is kept in a separate engine, it is nil and
+// the caller must clear the entries once the batch has been durably committed.
 func handleTruncatedStateBelowRaft(
 	ctx context.Context,
 	oldTruncatedState, newTruncatedState *roachpb.RaftTruncatedState,
 	loader stateloader.StateLoader,
 	batch engine.ReadWriter,
+	raftLogWriter engine.Writer,
 ) (_apply bool, _ error) {
 	// If this is a log truncation, load the resulting unreplicated or legacy
 	// replicated truncated state (in that order). If the migration is happening
@@ -1691,11 +1730,10 @@ func handleTruncatedStateBelowRaft(
 	// perform well here because the tombstones could be "collapsed",
 	// but it is hardly worth the risk at this point.
 	prefixBuf := &loader.RangeIDPrefixBuf
-	for idx := oldTruncatedState.Index + 1; idx <= newTruncatedState.Index; idx++ {
-		// NB: RangeIDPrefixBufs have sufficient capacity (32 bytes) to
-		// avoid allocating when constructing Raft log keys (16 bytes).
-		unsafeKey := prefixBuf.RaftLogKey(idx)
-		if err := batch.Clear(engine.MakeMVCCMetadataKey(unsafeKey)); err != nil {
+	if raftLogWriter != nil {
+		if err := clearRaftLogEntries(
+			prefixBuf, raftLogWriter, oldTruncatedState.Index+1, newTruncatedState.Index,
+		); err != nil {
 			return false, errors.Wrapf(err, "unable to clear truncated Raft entries for %+v", newTruncatedState)
 		}
 	}
@@ -1730,6 +1768,21 @@ func handleTruncatedStateBelowRaft(
 	return true, nil
 }
 
+// clearRaftLogEntries clears the Raft log entries with indexes in [lo, hi].
+func clearRaftLogEntries(
+	prefixBuf *keys.RangeIDPrefixBuf, writer engine.Writer, lo, hi uint64,
+) error {
+	for idx := lo; idx <= hi; idx++ {
+		// NB: RangeIDPrefixBufs have sufficient capacity (32 bytes) to
+		// avoid allocating when constructing Raft log keys (16 bytes).
+		unsafeKey := prefixBuf.RaftLogKey(idx)
+		if err := writer.Clear(engine.MakeMVCCMetadataKey(unsafeKey)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ComputeRaftLogSize computes the size (in bytes) of the Raft log from the
 // storage engine. This will iterate over the Raft log and sideloaded files, so
 // depending on the size of these it can be mildly to extremely expensive and
@@ -73,13 +73,13 @@ func (r *replicaRaftStorage) InitialState() (raftpb.HardState, raftpb.ConfState,
 // and this method will always return at least one entry even if it exceeds
 // maxBytes. Sideloaded proposals count towards maxBytes with their payloads inlined.
 func (r *replicaRaftStorage) Entries(lo, hi, maxBytes uint64) ([]raftpb.Entry, error) {
-	readonly := r.store.Engine().NewReadOnly()
-	defer readonly.Close()
+	readonly, raftReadonly := r.store.newReadOnly()
+	defer closeReadOnly(readonly, raftReadonly)
 	ctx := r.AnnotateCtx(context.TODO())
 	if r.raftMu.sideloaded == nil {
 		return nil, errors.New("sideloaded storage is uninitialized")
 	}
-	return entries(ctx, r.mu.stateLoader, readonly, r.RangeID, r.store.raftEntryCache,
+	return entries(ctx, r.mu.stateLoader, readonly, raftReadonly, r.RangeID, r.store.raftEntryCache,
 		r.raftMu.sideloaded, lo, hi, maxBytes)
 }
 
@@ -92,10 +92,13 @@ func (r *Replica) raftEntriesLocked(lo, hi, maxBytes uint64) ([]raftpb.Entry, er
 // `sideloaded` can be supplied as nil, in which case sideloaded entries will
 // not be inlined, the raft entry cache will not be populated with *any* of the
 // loaded entries, and maxBytes will not be applied to the payloads.
+//
+// The entries are read from raftReader, which differs from e if the Raft log
+// is kept in a separate engine.
 func entries(
 	ctx context.Context,
 	rsl stateloader.StateLoader,
-	e engine.Reader,
+	e, raftReader engine.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	sideloaded SideloadStorage,
@@ -165,7 +168,7 @@ func entries(
 		return exceededMaxBytes, nil
 	}
 
-	if err := iterateEntries(ctx, e, rangeID, expectedIndex, hi, scanFunc); err != nil {
+	if err := iterateEntries(ctx, raftReader, rangeID, expectedIndex, hi, scanFunc); err != nil {
 		return nil, err
 	}
 	// Cache the fetched entries, if we may.
@@ -191,7 +194,7 @@ func entries(
 		}
 
 		// Was the missing index after the last index?
-		lastIndex, err := rsl.LoadLastIndex(ctx, e)
+		lastIndex, err := rsl.LoadLastIndex(ctx, e, raftReader)
 		if err != nil {
 			return nil, err
 		}
@@ -250,10 +253,10 @@ func (r *replicaRaftStorage) Term(i uint64) (uint64, error) {
 	if e, ok := r.store.raftEntryCache.Get(r.RangeID, i); ok {
 		return e.Term, nil
 	}
-	readonly := r.store.Engine().NewReadOnly()
-	defer readonly.Close()
+	readonly, raftReadonly := r.store.newReadOnly()
+	defer closeReadOnly(readonly, raftReadonly)
 	ctx := r.AnnotateCtx(context.TODO())
-	return term(ctx, r.mu.stateLoader, readonly, r.RangeID, r.store.raftEntryCache, i)
+	return term(ctx, r.mu.stateLoader, readonly, raftReadonly, r.RangeID, r.store.raftEntryCache, i)
 }
 
 // raftTermLocked requires that r.mu is locked for reading.
@@ -264,14 +267,14 @@ func (r *Replica) raftTermRLocked(i uint64) (uint64, error) {
 func term(
 	ctx context.Context,
 	rsl stateloader.StateLoader,
-	eng engine.Reader,
+	eng, raftReader engine.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	i uint64,
 ) (uint64, error) {
 	// entries() accepts a `nil` sideloaded storage and will skip inlining of
 	// sideloaded entries. We only need the term, so this is what we do.
-	ents, err := entries(ctx, rsl, eng, rangeID, eCache, nil /* sideloaded */, i, i+1, math.MaxUint64 /* maxBytes */)
+	ents, err := entries(ctx, rsl, eng, raftReader, rangeID, eCache, nil /* sideloaded */, i, i+1, math.MaxUint64 /* maxBytes */)
 	if err == raft.ErrCompacted {
 		ts, _, err := rsl.LoadRaftTruncatedState(ctx, eng)
 		if err != nil {
@@ -386,6 +389,10 @@ func (r *Replica) GetSnapshot(
 	// the corresponding Raft command not applied yet).
 	r.raftMu.Lock()
 	snap := r.store.engine.NewSnapshot()
+	raftSnap := snap
+	if r.store.separateRaftLog() {
+		raftSnap = r.store.raftEngine.NewSnapshot()
+	}
 	r.mu.Lock()
 	appliedIndex := r.mu.state.RaftAppliedIndex
 	// Cleared when OutgoingSnapshot closes.
@@ -401,7 +408,7 @@ func (r *Replica) GetSnapshot(
 	defer func() {
 		if err != nil {
 			release()
-			snap.Close()
+			closeReadOnly(snap, raftSnap)
 		}
 	}()
 
@@ -428,7 +435,7 @@ func (r *Replica) GetSnapshot(
 	// create a new state loader.
 	snapData, err := snapshot(
 		ctx, snapUUID, stateloader.Make(rangeID), snapType,
-		snap, rangeID, r.store.raftEntryCache, withSideloaded, startKey,
+		snap, raftSnap, rangeID, r.store.raftEntryCache, withSideloaded, startKey,
 	)
 	if err != nil {
 		log.Errorf(ctx, "error generating snapshot: %+v", err)
@@ -447,6 +454,10 @@ type OutgoingSnapshot struct {
 	RaftSnap raftpb.Snapshot
 	// The RocksDB snapshot that will be streamed from.
 	EngineSnap engine.Reader
+	// The snapshot of the engine holding the Raft log, from which the log
+	// entries are streamed. Same as EngineSnap unless the Raft log is kept in
+	// a separate engine.
+	RaftLogSnap engine.Reader
 	// The complete range iterator for the snapshot to stream.
 	Iter *rditer.ReplicaDataIterator
 	// The replica state within the snapshot.
@@ -468,7 +479,7 @@ func (s *OutgoingSnapshot) String() string {
 // Close releases the resources associated with the snapshot.
 func (s *OutgoingSnapshot) Close() {
 	s.Iter.Close()
-	s.EngineSnap.Close()
+	closeReadOnly(s.EngineSnap, s.RaftLogSnap)
 	if s.onClose != nil {
 		s.onClose()
 	}
@@ -506,7 +517,7 @@ func snapshot(
 	snapUUID uuid.UUID,
 	rsl stateloader.StateLoader,
 	snapType SnapshotRequest_Type,
-	snap engine.Reader,
+	snap, raftSnap engine.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	withSideloaded func(func(SideloadStorage) error) error,
@@ -532,7 +543,7 @@ func snapshot(
 		return OutgoingSnapshot{}, err
 	}
 
-	term, err := term(ctx, rsl, snap, rangeID, eCache, appliedIndex)
+	term, err := term(ctx, rsl, snap, raftSnap, rangeID, eCache, appliedIndex)
 	if err != nil {
 		return OutgoingSnapshot{}, errors.Errorf("failed to fetch term of %d: %s", appliedIndex, err)
 	}
@@ -559,6 +570,7 @@ func snapshot(
 		RaftEntryCache: eCache,
 		WithSideloaded: withSideloaded,
 		EngineSnap:     snap,
+		RaftLogSnap:    raftSnap,
 		Iter:           iter,
 		State:          state,
 		SnapUUID:       snapUUID,
@@ -933,7 +945,31 @@ func (r *Replica) applySnapshot(
 			return err
 		}
 	}
+	// If the Raft log is kept in a separate engine, it isn't replaced by the
+	// ingestion below. Any entries past the snapshot index (which raft is
+	// discarding) must not survive a crash following the ingestion, so they're
+	// removed first. Entries up to the snapshot index are either replaced by
+	// the snapshot's log entries or fall below its truncated state; they are
+	// dealt with once the snapshot has been ingested.
+	var clearedRaftLog bool
+	if r.store.separateRaftLog() {
+		r.mu.RLock()
+		lastIndex := r.mu.lastIndex
+		r.mu.RUnlock()
+		if lastIndex > snap.Metadata.Index {
+			if err := r.store.clearSeparateRaftLogEntries(
+				ctx, &r.raftMu.stateLoader.RangeIDPrefixBuf, snap.Metadata.Index+1, lastIndex, true, /* sync */
+			); err != nil {
+				return errors.Wrapf(err, "while clearing Raft log entries past the snapshot")
+			}
+			clearedRaftLog = true
+		}
+	}
 	if err := r.store.engine.IngestExternalFiles(ctx, inSnap.SSSS.SSTs()); err != nil {
+		if clearedRaftLog {
+			// The in-memory Raft state no longer matches the Raft log.
+			log.Fatalf(ctx, "while ingesting %s: %+v", inSnap.SSSS.SSTs(), err)
+		}
 		return errors.Wrapf(err, "while ingesting %s", inSnap.SSSS.SSTs())
 	}
 	stats.ingestion = timeutil.Now()
@@ -942,6 +978,14 @@ func (r *Replica) applySnapshot(
 	// has not yet been updated. Any errors past this point must therefore be
 	// treated as fatal.
 
+	// The snapshot's log entries were ingested into the store's engine. Move
+	// them to the Raft log engine, if the Raft log is kept separately.
+	if r.store.separateRaftLog() {
+		if err := r.store.moveRaftLogsToRaftEngine(ctx, r.RangeID); err != nil {
+			log.Fatalf(ctx, "failed to move Raft log while applying snapshot: %+v", err)
+		}
+	}
+
 	if err := r.clearSubsumedReplicaInMemoryData(ctx, subsumedRepls, mergedTombstoneReplicaID); err != nil {
 		log.Fatalf(ctx, "failed to clear in-memory data of subsumed replicas while applying snapshot: %+v", err)
 	}
@@ -218,10 +218,18 @@ func (p *pendingLeaseRequest) InitOrJoinRequest(
 		Replica:    nextLeaseHolder,
 		ProposedTS: &now,
 	}
+	if transfer {
+		// The closed timestamps we published for the range as configured for
+		// global reads may lead the present. Starting the new lease above them
+		// makes sure that the new lease holder doesn't evaluate writes below
+		// them, even if it doesn't consider the range configured for global
+		// reads. See publishGlobalReadsClosed.
+		reqLease.Start.Forward(p.repl.mu.globalReads.closed)
+	}
 
 	if p.repl.requiresExpiringLeaseRLocked() {
 		reqLease.Expiration = &hlc.Timestamp{}
-		*reqLease.Expiration = status.Timestamp.Add(int64(p.repl.store.cfg.RangeLeaseActiveDuration()), 0)
+		*reqLease.Expiration = reqLease.Start.Add(int64(p.repl.store.cfg.RangeLeaseActiveDuration()), 0)
 	} else {
 		// Get the liveness for the next lease holder and set the epoch in the lease request.
 		liveness, err := p.repl.store.cfg.NodeLiveness.GetLiveness(nextLeaseHolder.NodeID)
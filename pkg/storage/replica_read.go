@@ -32,12 +32,16 @@ func (r *Replica) executeReadOnlyBatch(
 	// If the read is not inconsistent, the read requires the range lease or
 	// permission to serve via follower reads.
 	var status storagepb.LeaseStatus
+	var globalReads bool
 	if ba.ReadConsistency.RequiresReadLease() {
 		if status, pErr = r.redirectOnOrAcquireLease(ctx); pErr != nil {
-			if nErr := r.canServeFollowerRead(ctx, ba, pErr); nErr != nil {
+			var nErr *roachpb.Error
+			if globalReads, nErr = r.canServeFollowerRead(ctx, ba, pErr); nErr != nil {
 				return nil, nErr
 			}
 			r.store.metrics.FollowerReadsCount.Inc(1)
+		} else {
+			globalReads = r.isGlobalReadsRange()
 		}
 	}
 	r.limitTxnMaxTimestamp(ctx, ba, status)
@@ -121,6 +125,7 @@ func (r *Replica) executeReadOnlyBatch(
 		log.VErrEvent(ctx, 3, pErr.String())
 	} else {
 		log.Event(ctx, "read completed")
+		br.GlobalReads = globalReads
 	}
 	return br, pErr
 }
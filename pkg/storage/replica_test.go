@@ -58,6 +58,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/logtags"
@@ -2814,6 +2815,161 @@ func TestReplicaUseTSCache(t *testing.T) {
 	}
 }
 
+// TestReplicaGlobalReadsWrite verifies that writes to a range configured for
+// global reads are pushed into the future by the global reads lead and that
+// they aren't committed until that lead has passed. It runs with the
+// default settings, under which the lead, and so the write latency, is
+// bounded by the maximum clock offset rather than the closed timestamp target
+// duration.
+func TestReplicaGlobalReadsWrite(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.manualClock = hlc.NewManualClock(123)
+	cfg := TestStoreConfig(hlc.NewClock(tc.manualClock.UnixNano, base.DefaultMaxClockOffset))
+	tc.StartWithStoreConfig(t, stopper, cfg)
+
+	// Leave a second for the propagation of closed timestamps to followers.
+	maxLead := 2*tc.Clock().MaxOffset() + time.Second
+	lead := tc.repl.globalReadsLead()
+	if lead > maxLead {
+		t.Fatalf("expected lead to be at most %s; got %s", maxLead, lead)
+	}
+
+	zone := protoutil.Clone(tc.store.cfg.DefaultZoneConfig).(*config.ZoneConfig)
+	zone.GlobalReads = proto.Bool(true)
+	tc.repl.SetZoneConfig(zone)
+
+	pArgs := putArgs([]byte("a"), []byte("value"))
+	var ba roachpb.BatchRequest
+	ba.Add(&pArgs)
+	start := timeutil.Now()
+	now := tc.Clock().Now()
+	br, pErr := tc.Sender().Send(context.Background(), ba)
+	if pErr != nil {
+		t.Fatal(pErr)
+	}
+	if minTS := now.Add(lead.Nanoseconds(), 0); br.Timestamp.Less(minTS) {
+		t.Errorf("expected write timestamp to be pushed to at least %s; got %s", minTS, br.Timestamp)
+	}
+	if !br.GlobalReads {
+		t.Errorf("expected response to be flagged as global reads")
+	}
+	// The manual clock doesn't move, so the write is acknowledged once the
+	// full lead has elapsed, but not much later.
+	elapsed := timeutil.Since(start)
+	if elapsed < lead {
+		t.Errorf("expected write to wait for at least %s; waited %s", lead, elapsed)
+	}
+	if maxLatency := lead + time.Second; elapsed > maxLatency {
+		t.Errorf("expected write to wait for at most %s; waited %s", maxLatency, elapsed)
+	}
+
+	// A transactional write is pushed as well, but only the commit waits for
+	// its timestamp to become present.
+	key := roachpb.Key("b")
+	txn := newTransaction("test", key, 1, tc.Clock())
+	tpArgs := putArgs(key, []byte("value"))
+	assignSeqNumsForReqs(txn, &tpArgs)
+	ba = roachpb.BatchRequest{}
+	ba.Txn = txn
+	ba.Add(&tpArgs)
+	start = timeutil.Now()
+	br, pErr = tc.Sender().Send(context.Background(), ba)
+	if pErr != nil {
+		t.Fatal(pErr)
+	}
+	if elapsed := timeutil.Since(start); elapsed >= lead {
+		t.Errorf("expected intent write not to wait for the lead; waited %s", elapsed)
+	}
+	txn.Update(br.Txn)
+	if minTS := now.Add(lead.Nanoseconds(), 0); txn.WriteTimestamp.Less(minTS) {
+		t.Errorf("expected txn to be pushed to at least %s; got %s", minTS, txn.WriteTimestamp)
+	}
+	et, etH := endTxnArgs(txn, true /* commit */)
+	et.NoRefreshSpans = true
+	assignSeqNumsForReqs(txn, &et)
+	start = timeutil.Now()
+	if _, pErr := tc.SendWrappedWith(etH, &et); pErr != nil {
+		t.Fatal(pErr)
+	}
+	if elapsed := timeutil.Since(start); elapsed < lead {
+		t.Errorf("expected commit to wait for at least %s; waited %s", lead, elapsed)
+	}
+}
+
+// TestReplicaGlobalReadsPublish verifies that a range is only published as
+// configured for global reads once the regular closed timestamp covers the
+// writes that weren't pushed by the lead and while the lease remains valid
+// through the published closed timestamp, and that writes following a
+// publication are evaluated above the published closed timestamp even once
+// global reads are disabled.
+func TestReplicaGlobalReadsPublish(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+
+	put := func(key string) *roachpb.BatchResponse {
+		t.Helper()
+		pArgs := putArgs([]byte(key), []byte("value"))
+		var ba roachpb.BatchRequest
+		ba.Add(&pArgs)
+		br, pErr := tc.Sender().Send(context.Background(), ba)
+		if pErr != nil {
+			t.Fatal(pErr)
+		}
+		return br
+	}
+	setGlobalReads := func(globalReads bool) {
+		zone := protoutil.Clone(tc.store.cfg.DefaultZoneConfig).(*config.ZoneConfig)
+		zone.GlobalReads = proto.Bool(globalReads)
+		tc.repl.SetZoneConfig(zone)
+	}
+
+	// A write that isn't pushed holds back the publication until the closed
+	// timestamp covers it.
+	put("a")
+	setGlobalReads(true)
+	tc.repl.mu.RLock()
+	lastUnpushedWrite := tc.repl.mu.globalReads.lastUnpushedWrite
+	tc.repl.mu.RUnlock()
+	globalReadsClosed := tc.Clock().Now().Add((100 * time.Millisecond).Nanoseconds(), 0)
+	if rangeIDs := tc.store.GlobalReadsRanges(
+		lastUnpushedWrite.Prev(), globalReadsClosed,
+	); len(rangeIDs) != 0 {
+		t.Fatalf("expected no ranges to be published; got %v", rangeIDs)
+	}
+	// Nothing is published beyond the expiration of the lease, since a later
+	// lease holder could evaluate writes below it.
+	if rangeIDs := tc.store.GlobalReadsRanges(
+		lastUnpushedWrite, tc.Clock().Now().Add(time.Hour.Nanoseconds(), 0),
+	); len(rangeIDs) != 0 {
+		t.Fatalf("expected no ranges to be published; got %v", rangeIDs)
+	}
+	if rangeIDs := tc.store.GlobalReadsRanges(
+		lastUnpushedWrite, globalReadsClosed,
+	); len(rangeIDs) != 1 || rangeIDs[0] != tc.repl.RangeID {
+		t.Fatalf("expected r%d to be published; got %v", tc.repl.RangeID, rangeIDs)
+	}
+
+	// Once global reads are disabled, the range isn't published anymore, but
+	// writes are still evaluated above what was published.
+	setGlobalReads(false)
+	if rangeIDs := tc.store.GlobalReadsRanges(
+		lastUnpushedWrite, globalReadsClosed,
+	); len(rangeIDs) != 0 {
+		t.Fatalf("expected no ranges to be published; got %v", rangeIDs)
+	}
+	if br := put("b"); !globalReadsClosed.Less(br.Timestamp) {
+		t.Fatalf("expected write above %s; got %s", globalReadsClosed, br.Timestamp)
+	} else if br.GlobalReads {
+		t.Fatalf("expected response not to be flagged as global reads")
+	}
+}
+
 // TestReplicaTSCacheForwardsIntentTS verifies that the timestamp cache affects
 // the timestamps at which intents are written. That is, if a transactional
 // write is forwarded by the timestamp cache due to a more recent read, the
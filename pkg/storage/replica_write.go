@@ -119,6 +119,13 @@ func (r *Replica) executeWriteBatch(
 	minTS, untrack := r.store.cfg.ClosedTimestamp.Tracker.Track(ctx)
 	defer untrack(ctx, 0, 0, 0) // covers all error returns below
 
+	// Writes to ranges configured for global reads are pushed into the future
+	// so that the range's closed timestamp can lead present time, and aren't
+	// acknowledged until that future has arrived. See closedts.GlobalReadsLead.
+	if !ba.IsLeaseRequest() {
+		minTS = r.forwardWriteForGlobalReads(minTS)
+	}
+
 	// Examine the read and write timestamp caches for preceding
 	// commands which require this command to move its timestamp
 	// forward. Or, in the case of a transactional write, the txn
@@ -194,6 +201,14 @@ func (r *Replica) executeWriteBatch(
 					log.Warning(ctx, err)
 				}
 			}
+			if propResult.Err == nil {
+				if !ba.IsLeaseRequest() && (ba.Txn == nil || isCommit(ba)) {
+					if pErr := r.commitWait(ctx, propResult.Reply); pErr != nil {
+						return nil, pErr
+					}
+				}
+				propResult.Reply.GlobalReads = r.isGlobalReadsRange()
+			}
 			return propResult.Reply, propResult.Err
 		case <-slowTimer.C:
 			slowTimer.Read = true
@@ -436,6 +451,13 @@ func (r *Replica) evaluateWriteBatchWithLocalRetries(
 	return
 }
 
+// isCommit returns whether the batch contains an EndTransaction request that
+// commits its transaction.
+func isCommit(ba *roachpb.BatchRequest) bool {
+	arg, ok := ba.GetArg(roachpb.EndTransaction)
+	return ok && arg.(*roachpb.EndTransactionRequest).Commit
+}
+
 // isOnePhaseCommit returns true iff the BatchRequest contains all writes in the
 // transaction and ends with an EndTransaction. One phase commits are disallowed
 // if any of the following conditions are true:
@@ -21,6 +21,10 @@ import (
 
 const minSplitSuggestionInterval = time.Minute
 
+// hotKeyWindow is the duration over which the Decider samples hot keys before
+// starting over, so that HotKeys reflects the recent load on the range.
+const hotKeyWindow = 10 * time.Second
+
 // A Decider collects measurements about the activity (measured in qps) on a
 // Replica and, assuming that qps thresholds are exceeded, tries to determine
 // a split key that would approximately result in halving the load on each of
@@ -35,6 +39,11 @@ const minSplitSuggestionInterval = time.Minute
 // to carry out a split. When the split is initiated, it can obtain the suggested
 // split point from MaybeSplitKey (which may have disappeared either due to a drop
 // in qps or a change in the workload).
+//
+// Independently of the qps threshold, the Decider also tracks the keys that are
+// accessed most frequently, which are available from HotKeys. These help
+// determine which key or key prefix is responsible for the load on a hot range,
+// and whether splitting the range would help at all.
 type Decider struct {
 	intn         func(n int) int // supplied to Init
 	qpsThreshold func() float64  // supplied to Init
@@ -47,6 +56,10 @@ type Decider struct {
 		count               int64     // number of requests recorded since last rollover
 		splitFinder         *Finder   // populated when engaged or decided
 		lastSplitSuggestion time.Time // last stipulation to client to carry out split
+
+		hotKeys      *hotKeyTracker // keys sampled since hotKeysStart
+		prevHotKeys  *hotKeyTracker // keys sampled in the preceding window, if any
+		hotKeysStart time.Time      // start of the current hot key window
 	}
 }
 
@@ -60,9 +73,9 @@ func Init(lbs *Decider, intn func(n int) int, qpsThreshold func() float64) {
 }
 
 // Record notifies the Decider that 'n' operations are being carried out which
-// operate on the span returned by the supplied method. The closure will only
-// be called when necessary, that is, when the Decider is considering a split
-// and is sampling key spans to determine a suitable split point.
+// operate on the span returned by the supplied method. The closure is called
+// unless n is zero, to sample the span's key for HotKeys and, when the Decider
+// is considering a split, to determine a suitable split point.
 //
 // If the returned boolean is true, a split key is available (though it may
 // disappear as more keys are sampled) and should be initiated by the caller,
@@ -105,10 +118,14 @@ func (d *Decider) recordLocked(now time.Time, n int, span func() roachpb.Span) b
 		}
 	}
 
-	if d.mu.splitFinder != nil && n != 0 {
-		s := span()
+	if n == 0 {
+		return false
+	}
+	s := span()
+	d.recordHotKeyLocked(now, s.Key)
+	if d.mu.splitFinder != nil {
 		if s.Key != nil {
-			d.mu.splitFinder.Record(span(), d.intn)
+			d.mu.splitFinder.Record(s, d.intn)
 		}
 		if now.Sub(d.mu.lastSplitSuggestion) > minSplitSuggestionInterval && d.mu.splitFinder.Ready(now) && d.mu.splitFinder.Key() != nil {
 			d.mu.lastSplitSuggestion = now
@@ -118,6 +135,42 @@ func (d *Decider) recordLocked(now time.Time, n int, span func() roachpb.Span) b
 	return false
 }
 
+// RecordHotKeys is like Record, but only samples the key of the span returned
+// by the supplied method for HotKeys, without measuring qps or considering a
+// split. It is used while load-based splitting is disabled.
+func (d *Decider) RecordHotKeys(now time.Time, span func() roachpb.Span) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.recordHotKeyLocked(now, span().Key)
+}
+
+func (d *Decider) recordHotKeyLocked(now time.Time, key roachpb.Key) {
+	d.rolloverHotKeysLocked(now)
+	if key == nil {
+		return
+	}
+	if d.mu.hotKeys == nil {
+		d.mu.hotKeys = &hotKeyTracker{}
+	}
+	d.mu.hotKeys.record(key)
+}
+
+// rolloverHotKeysLocked starts a new hot key window if the current one has
+// ended. The keys sampled in the ending window are kept for HotKeys unless the
+// window ended so long ago that they no longer reflect the recent load.
+func (d *Decider) rolloverHotKeysLocked(now time.Time) {
+	elapsed := now.Sub(d.mu.hotKeysStart)
+	if elapsed < hotKeyWindow {
+		return
+	}
+	d.mu.prevHotKeys = nil
+	if elapsed < 2*hotKeyWindow {
+		d.mu.prevHotKeys = d.mu.hotKeys
+	}
+	d.mu.hotKeys = nil
+	d.mu.hotKeysStart = now
+}
+
 // LastQPS returns the most recent QPS measurement.
 func (d *Decider) LastQPS(now time.Time) float64 {
 	d.mu.Lock()
@@ -146,10 +199,32 @@ func (d *Decider) MaybeSplitKey(now time.Time) roachpb.Key {
 	return key
 }
 
+// HotKeys returns the keys most frequently accessed by the operations
+// recorded in the last complete hot key window, or in the current one if the
+// last one didn't sample anything, sorted by decreasing count, along with the
+// total number of operations sampled.
+//
+// It is legal to call HotKeys at any time.
+func (d *Decider) HotKeys(now time.Time) ([]KeyCount, int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rolloverHotKeysLocked(now)
+	t := d.mu.prevHotKeys
+	if t == nil {
+		t = d.mu.hotKeys
+	}
+	if t == nil {
+		return nil, 0
+	}
+	return t.top(), t.total
+}
+
 // Reset deactivates any current attempt at determining a split key.
 func (d *Decider) Reset() {
 	d.mu.Lock()
 	d.mu.splitFinder = nil
+	d.mu.hotKeys = nil
+	d.mu.prevHotKeys = nil
 	d.mu.count = 0
 	d.mu.Unlock()
 }
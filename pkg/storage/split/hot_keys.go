@@ -0,0 +1,80 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package split
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+// hotKeySampleSize is the number of distinct keys tracked by a hotKeyTracker.
+const hotKeySampleSize = 16
+
+// KeyCount is a key along with an estimate of the number of sampled requests
+// that touched it.
+type KeyCount struct {
+	Key   roachpb.Key
+	Count int64
+}
+
+// hotKeyTracker estimates the most frequently accessed keys of a range using
+// the Space-Saving algorithm (Metwally et al., "Efficient Computation of
+// Frequent and Top-k Elements in Data Streams", 2005).
+//
+// A fixed number of counters is maintained. A key that isn't already tracked
+// replaces the key with the smallest count and inherits that count plus one.
+// Counts are therefore overestimates, but any key whose true count exceeds
+// total/hotKeySampleSize is guaranteed to be tracked.
+type hotKeyTracker struct {
+	total    int64
+	counters []KeyCount
+}
+
+// record notes a request touching the given key. As with the Finder, only
+// the start key of a request's span is taken into account.
+func (t *hotKeyTracker) record(key roachpb.Key) {
+	if t == nil {
+		return
+	}
+	t.total++
+	minIdx := -1
+	for i := range t.counters {
+		if t.counters[i].Key.Equal(key) {
+			t.counters[i].Count++
+			return
+		}
+		if minIdx == -1 || t.counters[i].Count < t.counters[minIdx].Count {
+			minIdx = i
+		}
+	}
+	if len(t.counters) < hotKeySampleSize {
+		t.counters = append(t.counters, KeyCount{Key: key, Count: 1})
+		return
+	}
+	t.counters[minIdx] = KeyCount{Key: key, Count: t.counters[minIdx].Count + 1}
+}
+
+// top returns the tracked keys sorted by decreasing count.
+func (t *hotKeyTracker) top() []KeyCount {
+	if t == nil {
+		return nil
+	}
+	res := make([]KeyCount, len(t.counters))
+	copy(res, t.counters)
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].Key.Compare(res[j].Key) < 0
+	})
+	return res
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package split
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHotKeyTracker(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng := rand.New(rand.NewSource(1))
+	var tr hotKeyTracker
+	// One key receives a third of the requests while the remainder is spread
+	// across many more keys than the tracker can hold.
+	for i := 0; i < 30000; i++ {
+		if i%3 == 0 {
+			tr.record(roachpb.Key("hot"))
+			continue
+		}
+		tr.record(roachpb.Key(fmt.Sprintf("cold%03d", rng.Intn(1000))))
+	}
+
+	top := tr.top()
+	assert.Len(t, top, hotKeySampleSize)
+	assert.EqualValues(t, 30000, tr.total)
+	assert.Equal(t, roachpb.Key("hot"), top[0].Key)
+	// The count of a tracked key is an overestimate by at most the smallest
+	// count of any tracked key.
+	assert.True(t, top[0].Count >= 10000, "count %d", top[0].Count)
+	assert.True(t, top[0].Count <= 10000+top[len(top)-1].Count, "count %d", top[0].Count)
+	for i := 1; i < len(top); i++ {
+		assert.True(t, top[i-1].Count >= top[i].Count)
+	}
+}
+
+func TestDeciderHotKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	intn := rand.New(rand.NewSource(11)).Intn
+	var d Decider
+	Init(&d, intn, func() float64 { return 10.0 })
+
+	ms := func(i int) time.Time {
+		ts, err := time.Parse(time.RFC3339, "2000-01-01T00:00:00Z")
+		assert.NoError(t, err)
+		return ts.Add(time.Duration(i) * time.Millisecond)
+	}
+	op := func(s string) func() roachpb.Span {
+		return func() roachpb.Span { return roachpb.Span{Key: roachpb.Key(s)} }
+	}
+
+	// Keys are sampled regardless of whether the qps exceeds the threshold.
+	d.Record(ms(0), 1, op("a"))
+	d.Record(ms(1000), 5, op("a"))
+	keys, total := d.HotKeys(ms(1000))
+	assert.EqualValues(t, 2, total)
+	assert.Equal(t, []KeyCount{{Key: roachpb.Key("a"), Count: 2}}, keys)
+
+	d.Record(ms(2000), 20, op("a"))
+	for i := 0; i < 10; i++ {
+		d.Record(ms(2100+i), 1, op("b"))
+		d.Record(ms(2200+i), 1, op("b"))
+		d.Record(ms(2300+i), 1, op("c"))
+	}
+	// Keys can also be sampled without considering a split.
+	d.RecordHotKeys(ms(3000), op("d"))
+	expKeys := []KeyCount{
+		{Key: roachpb.Key("b"), Count: 20},
+		{Key: roachpb.Key("c"), Count: 10},
+		{Key: roachpb.Key("a"), Count: 3},
+		{Key: roachpb.Key("d"), Count: 1},
+	}
+	keys, total = d.HotKeys(ms(3000))
+	assert.EqualValues(t, 34, total)
+	assert.Equal(t, expKeys, keys)
+
+	// Once the window is over, its keys are returned until the next one is.
+	d.Record(ms(12000), 1, op("e"))
+	keys, total = d.HotKeys(ms(12000))
+	assert.EqualValues(t, 34, total)
+	assert.Equal(t, expKeys, keys)
+
+	// Keys sampled more than a window ago are discarded.
+	keys, total = d.HotKeys(ms(40000))
+	assert.Empty(t, keys)
+	assert.Zero(t, total)
+
+	// Resetting the Decider discards the samples.
+	d.Record(ms(40000), 1, op("f"))
+	d.Reset()
+	keys, total = d.HotKeys(ms(40000))
+	assert.Empty(t, keys)
+	assert.Zero(t, total)
+}
@@ -479,10 +479,13 @@ func (rsl StateLoader) LoadLegacyTxnSpanGCThreshold(
 
 // The rest is not technically part of ReplicaState.
 
-// LoadLastIndex loads the last index.
-func (rsl StateLoader) LoadLastIndex(ctx context.Context, reader engine.Reader) (uint64, error) {
+// LoadLastIndex loads the last index. The Raft log is read from raftReader,
+// which differs from reader if the Raft log is kept in a separate engine.
+func (rsl StateLoader) LoadLastIndex(
+	ctx context.Context, reader, raftReader engine.Reader,
+) (uint64, error) {
 	prefix := rsl.RaftLogPrefix()
-	iter := reader.NewIterator(engine.IterOptions{LowerBound: prefix})
+	iter := raftReader.NewIterator(engine.IterOptions{LowerBound: prefix})
 	defer iter.Close()
 
 	var lastIndex uint64
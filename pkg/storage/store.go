@@ -37,6 +37,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/storage/admission"
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/storage/closedts/container"
 	"github.com/cockroachdb/cockroach/pkg/storage/closedts/ctpb"
@@ -45,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/idalloc"
+	"github.com/cockroachdb/cockroach/pkg/storage/ingestbudget"
 	"github.com/cockroachdb/cockroach/pkg/storage/intentresolver"
 	"github.com/cockroachdb/cockroach/pkg/storage/raftentry"
 	"github.com/cockroachdb/cockroach/pkg/storage/tscache"
@@ -363,6 +365,7 @@ type Store struct {
 	cfg                StoreConfig
 	db                 *client.DB
 	engine             engine.Engine        // The underlying key-value store
+	raftEngine         engine.Engine        // Holds the Raft log; usually the same as engine
 	compactor          *compactor.Compactor // Schedules compaction of the engine
 	tsCache            tscache.Cache        // Most recent timestamps for keys / key ranges
 	allocator          Allocator            // Makes allocation decisions
@@ -384,6 +387,8 @@ type Store struct {
 	recoveryMgr        txnrecovery.Manager
 	raftEntryCache     *raftentry.Cache
 	limiters           batcheval.Limiters
+	admission          *admission.Controller // Queues writes while the engine is overloaded
+	ingestBudget       *ingestbudget.Budget  // Paces AddSSTable requests
 	txnWaitMetrics     *txnwait.Metrics
 	sss                SSTSnapshotStorage
 
@@ -552,6 +557,13 @@ type Store struct {
 		m map[roachpb.RangeID]struct{}
 	}
 
+	// The subset of replicas whose zone config enables global reads. See
+	// GlobalReadsRanges.
+	globalReadsReplicas struct {
+		syncutil.Mutex
+		m map[roachpb.RangeID]struct{}
+	}
+
 	// replicaQueues is a map of per-Replica incoming request queues. These
 	// queues might more naturally belong in Replica, but are kept separate to
 	// avoid reworking the locking in getOrCreateReplica which requires
@@ -760,6 +772,18 @@ func (sc *StoreConfig) LeaseExpiration() int64 {
 // NewStore returns a new instance of a store.
 func NewStore(
 	ctx context.Context, cfg StoreConfig, eng engine.Engine, nodeDesc *roachpb.NodeDescriptor,
+) *Store {
+	return NewStoreWithRaftEngine(ctx, cfg, eng, eng, nodeDesc)
+}
+
+// NewStoreWithRaftEngine returns a new instance of a store which keeps its
+// Raft log in raftEng. If raftEng is not eng, the Raft log is kept separately
+// from the rest of the store's data.
+func NewStoreWithRaftEngine(
+	ctx context.Context,
+	cfg StoreConfig,
+	eng, raftEng engine.Engine,
+	nodeDesc *roachpb.NodeDescriptor,
 ) *Store {
 	// TODO(tschottdorf): find better place to set these defaults.
 	cfg.SetDefaults()
@@ -768,11 +792,12 @@ func NewStore(
 		log.Fatalf(ctx, "invalid store configuration: %+v", &cfg)
 	}
 	s := &Store{
-		cfg:      cfg,
-		db:       cfg.DB, // TODO(tschottdorf): remove redundancy.
-		engine:   eng,
-		nodeDesc: nodeDesc,
-		metrics:  newStoreMetrics(cfg.HistogramWindowInterval),
+		cfg:        cfg,
+		db:         cfg.DB, // TODO(tschottdorf): remove redundancy.
+		engine:     eng,
+		raftEngine: raftEng,
+		nodeDesc:   nodeDesc,
+		metrics:    newStoreMetrics(cfg.HistogramWindowInterval),
 	}
 	if cfg.RPCContext != nil {
 		s.allocator = MakeAllocator(cfg.StorePool, cfg.RPCContext.RemoteClocks.Latency)
@@ -808,6 +833,10 @@ func NewStore(
 	s.rangefeedReplicas.m = map[roachpb.RangeID]struct{}{}
 	s.rangefeedReplicas.Unlock()
 
+	s.globalReadsReplicas.Lock()
+	s.globalReadsReplicas.m = map[roachpb.RangeID]struct{}{}
+	s.globalReadsReplicas.Unlock()
+
 	s.tsCache = tscache.New(cfg.Clock, cfg.TimestampCachePageSize)
 	s.metrics.registry.AddMetricStruct(s.tsCache.Metrics())
 
@@ -826,6 +855,12 @@ func NewStore(
 	)
 	s.metrics.registry.AddMetricStruct(s.compactor.Metrics)
 
+	s.admission = admission.NewController(cfg.Settings, s.engine.GetStats, cfg.HistogramWindowInterval)
+	s.metrics.registry.AddMetricStruct(s.admission.Metrics())
+
+	s.ingestBudget = ingestbudget.NewBudget(cfg.Settings, s.engine.GetStats)
+	s.metrics.registry.AddMetricStruct(s.ingestBudget.Metrics())
+
 	s.snapshotApplySem = make(chan struct{}, cfg.concurrentSnapshotApplyLimit)
 
 	s.renewableLeasesSignal = make(chan struct{})
@@ -1312,6 +1347,12 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 	now := s.cfg.Clock.Now()
 	s.startedAt = now.WallTime
 
+	// Bring the Raft log engine in line with the store's engine before any
+	// replica reads its Raft log.
+	if err := s.reconcileRaftLogEngine(ctx); err != nil {
+		return err
+	}
+
 	// Iterate over all range descriptors, ignoring uncommitted versions
 	// (consistent=false). Uncommitted intents which have been abandoned
 	// due to a split crashing halfway will simply be resolved on the
@@ -1428,6 +1469,9 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 		s.storeRebalancer.Start(ctx, s.stopper)
 	}
 
+	// Start watching the storage engine for overload.
+	s.admission.Start(s.AnnotateCtx(context.Background()), s.stopper)
+
 	// Start the storage engine compactor.
 	if envutil.EnvOrDefaultBool("COCKROACH_ENABLE_COMPACTOR", true) {
 		s.compactor.Start(s.AnnotateCtx(context.Background()), s.stopper)
@@ -1656,6 +1700,41 @@ func (s *Store) removeReplicaWithRangefeed(rangeID roachpb.RangeID) {
 	s.rangefeedReplicas.Unlock()
 }
 
+func (s *Store) updateReplicaWithGlobalReads(rangeID roachpb.RangeID, globalReads bool) {
+	s.globalReadsReplicas.Lock()
+	if globalReads {
+		s.globalReadsReplicas.m[rangeID] = struct{}{}
+	} else {
+		delete(s.globalReadsReplicas.m, rangeID)
+	}
+	s.globalReadsReplicas.Unlock()
+}
+
+// GlobalReadsRanges returns the ranges for which the store holds the lease,
+// whose zone config enables global reads and which can have the closed
+// timestamp globalReadsClosed published along with the closed timestamp
+// update closing the timestamp closed. See closedts.GlobalReadsFn.
+func (s *Store) GlobalReadsRanges(closed, globalReadsClosed hlc.Timestamp) []roachpb.RangeID {
+	var replIDs []roachpb.RangeID
+	s.globalReadsReplicas.Lock()
+	for replID := range s.globalReadsReplicas.m {
+		replIDs = append(replIDs, replID)
+	}
+	s.globalReadsReplicas.Unlock()
+
+	rangeIDs := replIDs[:0]
+	for _, replID := range replIDs {
+		repl, err := s.GetReplica(replID)
+		if err != nil {
+			continue
+		}
+		if repl.publishGlobalReadsClosed(closed, globalReadsClosed) {
+			rangeIDs = append(rangeIDs, replID)
+		}
+	}
+	return rangeIDs
+}
+
 // systemGossipUpdate is a callback for gossip updates to
 // the system config which affect range split boundaries.
 func (s *Store) systemGossipUpdate(sysCfg *config.SystemConfig) {
@@ -2023,6 +2102,10 @@ func (s *Store) Clock() *hlc.Clock { return s.cfg.Clock }
 // Engine accessor.
 func (s *Store) Engine() engine.Engine { return s.engine }
 
+// RaftEngine returns the engine holding the store's Raft log. It is the same
+// as Engine() unless the Raft log is kept in a separate engine.
+func (s *Store) RaftEngine() engine.Engine { return s.raftEngine }
+
 // DB accessor.
 func (s *Store) DB() *client.DB { return s.cfg.DB }
 
@@ -2378,6 +2461,23 @@ func (s *Store) ClusterNodeCount() int {
 type HotReplicaInfo struct {
 	Desc *roachpb.RangeDescriptor
 	QPS  float64
+	// HotKeys are the most frequently accessed keys in the range over the last
+	// few seconds, sorted by decreasing QPS.
+	HotKeys []HotKeyInfo
+	// LoadSplitKey is the key at which load-based splitting would split the
+	// range, if it found one. A hot range without a split key is unlikely to
+	// benefit from being split, for instance because its load is dominated by
+	// a single key.
+	LoadSplitKey roachpb.Key
+}
+
+// HotKeyInfo contains a key and an estimate of its QPS.
+type HotKeyInfo struct {
+	Key roachpb.Key
+	QPS float64
+	// Fraction is the estimated fraction of the range's requests that
+	// accessed the key.
+	Fraction float64
 }
 
 // HottestReplicas returns the hottest replicas on a store, sorted by their
@@ -2388,9 +2488,13 @@ type HotReplicaInfo struct {
 func (s *Store) HottestReplicas() []HotReplicaInfo {
 	topQPS := s.replRankings.topQPS()
 	hotRepls := make([]HotReplicaInfo, len(topQPS))
+	now := timeutil.Now()
 	for i := range topQPS {
-		hotRepls[i].Desc = topQPS[i].repl.Desc()
+		repl := topQPS[i].repl
+		hotRepls[i].Desc = repl.Desc()
 		hotRepls[i].QPS = topQPS[i].qps
+		hotRepls[i].HotKeys = repl.GetHotKeys(now, topQPS[i].qps)
+		hotRepls[i].LoadSplitKey = repl.loadBasedSplitter.MaybeSplitKey(now)
 	}
 	return hotRepls
 }
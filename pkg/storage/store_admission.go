@@ -0,0 +1,96 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"bytes"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/admission"
+)
+
+// admissionPriority returns the priority with which the provided batch is
+// queued for admission while the storage engine is overloaded.
+//
+// Each request is classified by the key it addresses, and the batch is
+// admitted with the lowest priority among them, so that user work can't get
+// past admission control by adding a system key to its batch. Requests on the
+// system keyspace (node liveness, range descriptors, the system tables) are
+// needed to keep the cluster healthy and are never queued. So are the
+// transaction records of the transactions that update range descriptors,
+// such as splits and merges, and the requests that keep leases and
+// transactions alive on any range. Bulk ingestion and range clears are background
+// work, throttled before anything else. Everything else, including the
+// transaction records of user transactions, is user work.
+func admissionPriority(ba *roachpb.BatchRequest) admission.Priority {
+	// The record of a transaction anchored at a range descriptor is addressed
+	// by the same key as the record of a transaction anchored at the start of
+	// the range, so it can only be told apart through the transaction.
+	descTxn := ba.Txn != nil && isRangeDescriptorKey(ba.Txn.Key)
+	pri := admission.SystemPriority
+	for _, union := range ba.Requests {
+		if reqPri := requestAdmissionPriority(union.GetInner(), descTxn); reqPri > pri {
+			pri = reqPri
+		}
+	}
+	return pri
+}
+
+// requestAdmissionPriority returns the admission priority of a single request.
+// descTxn is set if the request belongs to a transaction anchored at a range
+// descriptor.
+func requestAdmissionPriority(req roachpb.Request, descTxn bool) admission.Priority {
+	switch req.Method() {
+	case roachpb.AddSSTable, roachpb.ClearRange, roachpb.RevertRange:
+		return admission.BackgroundPriority
+	case roachpb.RequestLease, roachpb.TransferLease, roachpb.HeartbeatTxn, roachpb.PushTxn:
+		// Delaying lease acquisitions and transfers would leave the range
+		// unavailable, and delaying transaction heartbeats and pushes would
+		// abort transactions and hold up their waiters, both of which only make
+		// the overload worse.
+		return admission.SystemPriority
+	}
+	rawKey := req.Header().Key
+	if bytes.HasPrefix(rawKey, keys.LocalRangePrefix) {
+		// Range descriptors hold the ranges' own bookkeeping, and so do the
+		// records of the transactions that update them. Other range-local keys,
+		// such as the records of other transactions, are classified by the key
+		// they are anchored at below.
+		if _, suffix, _, err := keys.DecodeRangeKey(rawKey); err == nil {
+			if bytes.Equal(suffix, keys.LocalRangeDescriptorSuffix) {
+				return admission.SystemPriority
+			}
+			if descTxn && bytes.Equal(suffix, keys.LocalTransactionSuffix) {
+				return admission.SystemPriority
+			}
+		}
+	}
+	key, err := keys.Addr(rawKey)
+	if err != nil {
+		// Range-ID local keys aren't addressable and only hold replicated
+		// range state.
+		return admission.SystemPriority
+	}
+	if key.Less(roachpb.RKey(keys.UserTableDataMin)) {
+		return admission.SystemPriority
+	}
+	return admission.UserPriority
+}
+
+// isRangeDescriptorKey returns whether the key is a range descriptor key.
+func isRangeDescriptorKey(key roachpb.Key) bool {
+	if !bytes.HasPrefix(key, keys.LocalRangePrefix) {
+		return false
+	}
+	_, suffix, _, err := keys.DecodeRangeKey(key)
+	return err == nil && bytes.Equal(suffix, keys.LocalRangeDescriptorSuffix)
+}
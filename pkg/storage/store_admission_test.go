@@ -0,0 +1,133 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/admission"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/pkg/errors"
+)
+
+func TestAdmissionPriority(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	userKey := roachpb.Key(keys.MakeTablePrefix(keys.MinUserDescID))
+	userEndKey := userKey.PrefixEnd()
+	put := func(key roachpb.Key) roachpb.Request {
+		return &roachpb.PutRequest{RequestHeader: roachpb.RequestHeader{Key: key}}
+	}
+	addSST := &roachpb.AddSSTableRequest{
+		RequestHeader: roachpb.RequestHeader{Key: userKey, EndKey: userEndKey},
+	}
+	clearRange := &roachpb.ClearRangeRequest{
+		RequestHeader: roachpb.RequestHeader{Key: userKey, EndKey: userEndKey},
+	}
+	userHeader := roachpb.RequestHeader{Key: userKey}
+
+	descTxn := &roachpb.Transaction{}
+	descTxn.Key = keys.RangeDescriptorKey(roachpb.RKey(userKey))
+	userTxn := &roachpb.Transaction{}
+	userTxn.Key = userKey
+
+	testCases := []struct {
+		name     string
+		txn      *roachpb.Transaction
+		reqs     []roachpb.Request
+		expected admission.Priority
+	}{
+		{"user put", nil, []roachpb.Request{put(userKey)}, admission.UserPriority},
+		{"liveness put", nil, []roachpb.Request{put(keys.NodeLivenessKey(1))}, admission.SystemPriority},
+		{"descriptor put", nil, []roachpb.Request{put(keys.RangeDescriptorKey(roachpb.RKey(userKey)))}, admission.SystemPriority},
+		{"user txn record put", nil, []roachpb.Request{put(keys.TransactionKey(userKey, uuid.MakeV4()))}, admission.UserPriority},
+		{"system txn record put", nil, []roachpb.Request{put(keys.TransactionKey(keys.NodeLivenessKey(1), uuid.MakeV4()))}, admission.SystemPriority},
+		{"range id local put", nil, []roachpb.Request{put(keys.RangeLeaseKey(1))}, admission.SystemPriority},
+		{"system table put", nil, []roachpb.Request{put(keys.MakeTablePrefix(keys.JobsTableID))}, admission.SystemPriority},
+		{"addsstable", nil, []roachpb.Request{addSST}, admission.BackgroundPriority},
+		{"clear range", nil, []roachpb.Request{clearRange}, admission.BackgroundPriority},
+		{"mixed user and system", nil, []roachpb.Request{put(userKey), put(keys.NodeLivenessKey(1))}, admission.UserPriority},
+		{"mixed system and user", nil, []roachpb.Request{put(keys.NodeLivenessKey(1)), put(userKey)}, admission.UserPriority},
+		{"split txn record put", descTxn, []roachpb.Request{put(keys.TransactionKey(descTxn.Key, uuid.MakeV4()))}, admission.SystemPriority},
+		{"user txn record put in user txn", userTxn, []roachpb.Request{put(keys.TransactionKey(userKey, uuid.MakeV4()))}, admission.UserPriority},
+		{"user put in split txn", descTxn, []roachpb.Request{put(userKey)}, admission.UserPriority},
+		{"lease request", nil, []roachpb.Request{&roachpb.RequestLeaseRequest{RequestHeader: userHeader}}, admission.SystemPriority},
+		{"lease transfer", nil, []roachpb.Request{&roachpb.TransferLeaseRequest{RequestHeader: userHeader}}, admission.SystemPriority},
+		{"heartbeat txn", userTxn, []roachpb.Request{&roachpb.HeartbeatTxnRequest{RequestHeader: userHeader}}, admission.SystemPriority},
+		{"push txn", nil, []roachpb.Request{&roachpb.PushTxnRequest{RequestHeader: userHeader}}, admission.SystemPriority},
+		{"mixed system", nil, []roachpb.Request{put(keys.NodeLivenessKey(1)), put(keys.MakeTablePrefix(keys.JobsTableID))}, admission.SystemPriority},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ba roachpb.BatchRequest
+			ba.Txn = tc.txn
+			ba.Add(tc.reqs...)
+			if pri := admissionPriority(&ba); pri != tc.expected {
+				t.Fatalf("expected priority %s, got %s", tc.expected, pri)
+			}
+		})
+	}
+}
+
+// TestAdmitLeaseRequestWhileOverloaded verifies that lease requests on user
+// ranges aren't throttled along with user writes while the storage engine is
+// severely overloaded.
+func TestAdmitLeaseRequestWhileOverloaded(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	st := cluster.MakeTestingClusterSettings()
+	// Don't admit any user writes while the engine is severely overloaded.
+	userRate, _ := settings.Lookup("kv.admission.overloaded_user_rate")
+	userRate.(*settings.FloatSetting).Override(&st.SV, 0)
+	c := admission.NewController(st, func() (*engine.Stats, error) {
+		return &engine.Stats{L0FileCount: 1000}, nil
+	}, time.Minute)
+	c.Start(ctx, stopper)
+	testutils.SucceedsSoon(t, func() error {
+		if level := c.Level(); level != admission.SeverelyOverloaded {
+			return errors.Errorf("expected overload level %d, got %d", admission.SeverelyOverloaded, level)
+		}
+		return nil
+	})
+
+	userKey := roachpb.Key(keys.MakeTablePrefix(keys.MinUserDescID))
+	var put roachpb.BatchRequest
+	put.Add(&roachpb.PutRequest{RequestHeader: roachpb.RequestHeader{Key: userKey}})
+	putCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := c.Admit(putCtx, admissionPriority(&put)); err != context.DeadlineExceeded {
+		t.Fatalf("expected the user write to be throttled, got %v", err)
+	}
+
+	var lease roachpb.BatchRequest
+	lease.Add(&roachpb.RequestLeaseRequest{RequestHeader: roachpb.RequestHeader{Key: userKey}})
+	if !lease.IsLeaseRequest() {
+		t.Fatal("expected a lease request")
+	}
+	leaseCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := c.Admit(leaseCtx, admissionPriority(&lease)); err != nil {
+		t.Fatalf("expected the lease request to be admitted, got %v", err)
+	}
+}
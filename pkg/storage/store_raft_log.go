@@ -0,0 +1,369 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/pkg/errors"
+)
+
+// A store usually keeps its Raft log in its engine, alongside the rest of its
+// replicas' data. It can instead be configured to keep the Raft log in a
+// separate engine (see base.StoreSpec.RaftLogPath), for example on a faster
+// device, so that the synchronous log appends don't contend with the rest of
+// the store's writes.
+//
+// Writes to the two engines aren't atomic with respect to each other, so they
+// are ordered to keep the engines consistent across crashes:
+//
+// - Log entries are committed and synced to the Raft log engine before the
+//   HardState (which may refer to them) is committed to the store's engine.
+// - A log truncation is synced to the store's engine before the truncated
+//   entries are cleared from the Raft log engine. The log of a removed replica
+//   is cleared once the removal is synced. Entries which survive a crash in
+//   between are removed when the store starts.
+// - A snapshot ingests its log entries into the store's engine, from which
+//   they are moved to the Raft log engine. Entries past the snapshot index are
+//   cleared from the Raft log engine before the ingestion. Log entries found
+//   in the store's engine are authoritative and are moved to the Raft log
+//   engine when the store starts, which also migrates the Raft log of a store
+//   that didn't previously keep it separately.
+
+// raftLogMoveBatchSize is the size above which a batch of log entries moved
+// to the Raft log engine is committed.
+const raftLogMoveBatchSize = 32 << 20 // 32 MiB
+
+// separateRaftLog returns whether the store keeps its Raft log in a separate
+// engine.
+func (s *Store) separateRaftLog() bool {
+	return s.raftEngine != s.engine
+}
+
+// newReadOnly returns read-only views of the store's engine and of the engine
+// holding its Raft log. They must be closed using closeReadOnly.
+func (s *Store) newReadOnly() (readOnly, raftReadOnly engine.ReadWriter) {
+	readOnly = s.engine.NewReadOnly()
+	if !s.separateRaftLog() {
+		return readOnly, readOnly
+	}
+	return readOnly, s.raftEngine.NewReadOnly()
+}
+
+// closeReadOnly closes reader and, unless it is the same reader, raftReader.
+func closeReadOnly(reader, raftReader engine.Reader) {
+	reader.Close()
+	if raftReader != reader {
+		raftReader.Close()
+	}
+}
+
+// reconcileRaftLogEngine is called when the store starts, before any of its
+// replicas are loaded. If the store keeps its Raft log in a separate engine,
+// it verifies that the Raft log engine belongs to the store, moves the log
+// entries found in the store's engine to the Raft log engine and removes stale
+// entries from it. Otherwise, it verifies that the store's Raft log wasn't
+// moved to a separate engine previously.
+func (s *Store) reconcileRaftLogEngine(ctx context.Context) error {
+	separated, err := RaftLogSeparated(ctx, s.engine)
+	if err != nil {
+		return err
+	}
+	if !s.separateRaftLog() {
+		if separated {
+			return errors.Errorf("%s keeps its Raft log in a separate engine, which was not provided; "+
+				"use 'cockroach debug merge-raft-log' to move it back into the store", s)
+		}
+		return nil
+	}
+
+	// The Raft log engine records the identity of its store, so that a
+	// misconfigured raft-log-path can't silently replace the Raft log of a store
+	// with an empty one, or with that of another store.
+	raftIdent, found, err := readRaftEngineIdent(ctx, s.raftEngine)
+	if err != nil {
+		return err
+	}
+	if found && raftIdent != *s.Ident {
+		return errors.Errorf("%s: the Raft log engine belongs to [n%d,s%d] of cluster %s",
+			s, raftIdent.NodeID, raftIdent.StoreID, raftIdent.ClusterID)
+	}
+	if !found {
+		if separated {
+			return errors.Errorf("%s keeps its Raft log in a separate engine, "+
+				"but the Raft log engine provided is empty; check the store's raft-log-path", s)
+		}
+		batch := s.raftEngine.NewBatch()
+		defer batch.Close()
+		if err := engine.MVCCPutProto(
+			ctx, batch, nil /* ms */, keys.StoreIdentKey(), hlc.Timestamp{}, nil /* txn */, s.Ident,
+		); err != nil {
+			return err
+		}
+		if err := batch.Commit(true /* sync */); err != nil {
+			return err
+		}
+	}
+	if !separated {
+		// Record that the Raft log is kept separately before moving any of it,
+		// so that the store can't be started without the Raft log engine once
+		// the migration has begun.
+		var v roachpb.Value
+		v.SetBool(true)
+		batch := s.engine.NewBatch()
+		defer batch.Close()
+		if err := engine.MVCCPut(
+			ctx, batch, nil /* ms */, keys.StoreRaftLogEngineKey(), hlc.Timestamp{}, v, nil, /* txn */
+		); err != nil {
+			return err
+		}
+		if err := batch.Commit(true /* sync */); err != nil {
+			return err
+		}
+	}
+
+	rangeIDs, err := rangeIDsWithRaftLog(s.engine)
+	if err != nil {
+		return err
+	}
+	if len(rangeIDs) > 0 {
+		log.Infof(ctx, "moving the Raft log of %d ranges to the Raft log engine", len(rangeIDs))
+		if err := s.moveRaftLogsToRaftEngine(ctx, rangeIDs...); err != nil {
+			return errors.Wrap(err, "moving Raft log to the Raft log engine")
+		}
+	}
+	return removeStaleRaftLogEntries(ctx, s.engine, s.raftEngine)
+}
+
+// RaftLogSeparated returns whether the store using the given engine keeps its
+// Raft log in a separate engine.
+func RaftLogSeparated(ctx context.Context, eng engine.Reader) (bool, error) {
+	val, _, err := engine.MVCCGet(
+		ctx, eng, keys.StoreRaftLogEngineKey(), hlc.Timestamp{}, engine.MVCCGetOptions{},
+	)
+	return val != nil, err
+}
+
+// readRaftEngineIdent reads the identity of the store that a Raft log engine
+// belongs to, if it has been recorded.
+func readRaftEngineIdent(
+	ctx context.Context, raftEng engine.Reader,
+) (roachpb.StoreIdent, bool, error) {
+	var ident roachpb.StoreIdent
+	found, err := engine.MVCCGetProto(
+		ctx, raftEng, keys.StoreIdentKey(), hlc.Timestamp{}, &ident, engine.MVCCGetOptions{},
+	)
+	return ident, found, err
+}
+
+// MergeRaftLogEngine moves the Raft log of a store which keeps it in a
+// separate engine back into the store's engine, after which the store can be
+// started without the Raft log engine. Neither engine may be in use by a
+// running store. It is safe to retry after a failure.
+func MergeRaftLogEngine(ctx context.Context, eng, raftEng engine.Engine) error {
+	separated, err := RaftLogSeparated(ctx, eng)
+	if err != nil {
+		return err
+	}
+	if !separated {
+		return errors.New("the store does not keep its Raft log in a separate engine")
+	}
+	ident, err := ReadStoreIdent(ctx, eng)
+	if err != nil {
+		return err
+	}
+	raftIdent, found, err := readRaftEngineIdent(ctx, raftEng)
+	if err != nil {
+		return err
+	}
+	if !found || raftIdent != ident {
+		return errors.Errorf("the Raft log engine does not belong to store [n%d,s%d]",
+			ident.NodeID, ident.StoreID)
+	}
+
+	if err := removeStaleRaftLogEntries(ctx, eng, raftEng); err != nil {
+		return err
+	}
+	rangeIDs, err := rangeIDsWithRaftLog(raftEng)
+	if err != nil {
+		return err
+	}
+	if err := moveRaftLogs(raftEng, eng, rangeIDs...); err != nil {
+		return errors.Wrap(err, "moving Raft log to the store's engine")
+	}
+	batch := eng.NewBatch()
+	defer batch.Close()
+	if err := engine.MVCCDelete(
+		ctx, batch, nil /* ms */, keys.StoreRaftLogEngineKey(), hlc.Timestamp{}, nil, /* txn */
+	); err != nil {
+		return err
+	}
+	return batch.Commit(true /* sync */)
+}
+
+// rangeIDsWithRaftLog returns the IDs of the ranges which have Raft log
+// entries in the given reader.
+func rangeIDsWithRaftLog(reader engine.Reader) ([]roachpb.RangeID, error) {
+	start := keys.LocalRangeIDPrefix.AsRawKey()
+	iter := reader.NewIterator(engine.IterOptions{UpperBound: start.PrefixEnd()})
+	defer iter.Close()
+
+	var rangeIDs []roachpb.RangeID
+	iter.SeekGE(engine.MakeMVCCMetadataKey(start))
+	for {
+		if ok, err := iter.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			return rangeIDs, nil
+		}
+		rangeID, _, _, _, err := keys.DecodeRangeIDKey(iter.UnsafeKey().Key)
+		if err != nil {
+			return nil, err
+		}
+		prefix := keys.RaftLogPrefix(rangeID)
+		iter.SeekGE(engine.MakeMVCCMetadataKey(prefix))
+		if ok, err := iter.Valid(); err != nil {
+			return nil, err
+		} else if ok && bytes.HasPrefix(iter.UnsafeKey().Key, prefix) {
+			rangeIDs = append(rangeIDs, rangeID)
+		}
+		// Skip to the next range.
+		iter.SeekGE(engine.MakeMVCCMetadataKey(keys.MakeRangeIDPrefix(rangeID + 1)))
+	}
+}
+
+// moveRaftLogsToRaftEngine moves the Raft log entries of the given ranges from
+// the store's engine to its Raft log engine, replacing any entries the Raft log
+// engine holds for these ranges.
+func (s *Store) moveRaftLogsToRaftEngine(ctx context.Context, rangeIDs ...roachpb.RangeID) error {
+	return moveRaftLogs(s.engine, s.raftEngine, rangeIDs...)
+}
+
+// moveRaftLogs moves the Raft log entries of the given ranges from one engine
+// to another, replacing any entries the destination holds for these ranges.
+func moveRaftLogs(from, to engine.Engine, rangeIDs ...roachpb.RangeID) error {
+	toBatch := to.NewWriteOnlyBatch()
+	fromBatch := from.NewWriteOnlyBatch()
+	defer func() {
+		toBatch.Close()
+		fromBatch.Close()
+	}()
+	flush := func() error {
+		// The entries must be durable in the destination before they are
+		// removed from the source.
+		if err := toBatch.Commit(true /* sync */); err != nil {
+			return err
+		}
+		if err := fromBatch.Commit(true /* sync */); err != nil {
+			return err
+		}
+		toBatch.Close()
+		fromBatch.Close()
+		toBatch = to.NewWriteOnlyBatch()
+		fromBatch = from.NewWriteOnlyBatch()
+		return nil
+	}
+
+	for _, rangeID := range rangeIDs {
+		prefix := keys.RaftLogPrefix(rangeID)
+		end := prefix.PrefixEnd()
+		if err := engine.ClearRangeWithHeuristic(to, toBatch, prefix, end); err != nil {
+			return err
+		}
+		if err := func() error {
+			iter := from.NewIterator(engine.IterOptions{UpperBound: end})
+			defer iter.Close()
+			for iter.SeekGE(engine.MakeMVCCMetadataKey(prefix)); ; iter.Next() {
+				if ok, err := iter.Valid(); err != nil || !ok {
+					return err
+				}
+				if err := toBatch.Put(iter.UnsafeKey(), iter.UnsafeValue()); err != nil {
+					return err
+				}
+				if err := fromBatch.Clear(iter.UnsafeKey()); err != nil {
+					return err
+				}
+			}
+		}(); err != nil {
+			return err
+		}
+		if toBatch.Len() >= raftLogMoveBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// removeStaleRaftLogEntries removes the entries of a store's Raft log engine
+// that were left behind by a crash: entries at or below their replica's
+// truncated index, and entries of replicas that no longer exist.
+func removeStaleRaftLogEntries(ctx context.Context, eng, raftEng engine.Engine) error {
+	rangeIDs, err := rangeIDsWithRaftLog(raftEng)
+	if err != nil {
+		return err
+	}
+	batch := raftEng.NewWriteOnlyBatch()
+	defer batch.Close()
+	for _, rangeID := range rangeIDs {
+		ts, _, err := stateloader.Make(rangeID).LoadRaftTruncatedState(ctx, eng)
+		if err != nil {
+			return err
+		}
+		prefix := keys.RaftLogPrefix(rangeID)
+		end := prefix.PrefixEnd()
+		if ts.Index != 0 {
+			end = keys.RaftLogKey(rangeID, ts.Index+1)
+		}
+		// Replicas without a truncated state have been removed (or were never
+		// initialized, in which case they have no Raft log), so their entire
+		// Raft log is removed.
+		if err := engine.ClearRangeWithHeuristic(raftEng, batch, prefix, end); err != nil {
+			return err
+		}
+	}
+	return batch.Commit(false /* sync */)
+}
+
+// clearSeparateRaftLogEntries clears the Raft log entries with indexes in
+// [lo, hi] from the Raft log engine, which must be separate from the store's
+// engine.
+func (s *Store) clearSeparateRaftLogEntries(
+	ctx context.Context, prefixBuf *keys.RangeIDPrefixBuf, lo, hi uint64, sync bool,
+) error {
+	batch := s.raftEngine.NewWriteOnlyBatch()
+	defer batch.Close()
+	if err := clearRaftLogEntries(prefixBuf, batch, lo, hi); err != nil {
+		return err
+	}
+	return batch.Commit(sync)
+}
+
+// clearSeparateRaftLog clears the Raft log of the given range from the Raft
+// log engine, which must be separate from the store's engine.
+func (s *Store) clearSeparateRaftLog(ctx context.Context, rangeID roachpb.RangeID) error {
+	batch := s.raftEngine.NewWriteOnlyBatch()
+	defer batch.Close()
+	prefix := keys.RaftLogPrefix(rangeID)
+	if err := engine.ClearRangeWithHeuristic(s.raftEngine, batch, prefix, prefix.PrefixEnd()); err != nil {
+		return err
+	}
+	// Entries left behind by a crash are removed when the store restarts.
+	return batch.Commit(false /* sync */)
+}
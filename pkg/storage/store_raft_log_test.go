@@ -0,0 +1,135 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStoreReconcileRaftLogEngine verifies that a store which keeps its Raft
+// log in a separate engine moves the log entries found in its engine to the
+// Raft log engine when it starts, removes stale entries from the Raft log
+// engine, and can't subsequently be started without its Raft log engine unless
+// the Raft log is moved back into the store's engine.
+func TestStoreReconcileRaftLogEngine(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	eng := engine.NewDefaultInMem()
+	stopper.AddCloser(eng)
+	raftEng := engine.NewDefaultInMem()
+	stopper.AddCloser(raftEng)
+
+	putEntries := func(w engine.Writer, rangeID roachpb.RangeID, lo, hi uint64) {
+		for idx := lo; idx <= hi; idx++ {
+			key := engine.MakeMVCCMetadataKey(keys.RaftLogKey(rangeID, idx))
+			require.NoError(t, w.Put(key, []byte("entry")))
+		}
+	}
+	entries := func(r engine.Reader, rangeID roachpb.RangeID) []uint64 {
+		var indexes []uint64
+		prefix := keys.RaftLogPrefix(rangeID)
+		require.NoError(t, r.Iterate(prefix, prefix.PrefixEnd(), func(kv engine.MVCCKeyValue) (bool, error) {
+			_, idx, err := encoding.DecodeUint64Ascending(kv.Key.Key[len(prefix):])
+			indexes = append(indexes, idx)
+			return false, err
+		}))
+		return indexes
+	}
+
+	// Range 1 has its log in the store's engine, as it would before the store
+	// was configured with a separate Raft log engine. The Raft log engine holds
+	// a conflicting log for it, which is replaced.
+	putEntries(eng, 1, 11, 13)
+	putEntries(raftEng, 1, 11, 20)
+	require.NoError(t, stateloader.Make(1).SetRaftTruncatedState(
+		ctx, eng, &roachpb.RaftTruncatedState{Index: 10, Term: 5},
+	))
+	// Range 2 has entries in the Raft log engine which were truncated.
+	putEntries(raftEng, 2, 5, 15)
+	require.NoError(t, stateloader.Make(2).SetRaftTruncatedState(
+		ctx, eng, &roachpb.RaftTruncatedState{Index: 10, Term: 5},
+	))
+	// Range 3 was removed, but its log was left behind in the Raft log engine.
+	putEntries(raftEng, 3, 1, 5)
+
+	ident := roachpb.StoreIdent{ClusterID: uuid.MakeV4(), NodeID: 1, StoreID: 1}
+	require.NoError(t, engine.MVCCPutProto(
+		ctx, eng, nil /* ms */, keys.StoreIdentKey(), hlc.Timestamp{}, nil /* txn */, &ident,
+	))
+	cfg := TestStoreConfig(hlc.NewClock(hlc.UnixNano, time.Nanosecond))
+	newStore := func(raftEng engine.Engine) *Store {
+		s := NewStoreWithRaftEngine(ctx, cfg, eng, raftEng, &roachpb.NodeDescriptor{NodeID: 1})
+		s.Ident = &ident
+		return s
+	}
+	s := newStore(raftEng)
+	require.NoError(t, s.reconcileRaftLogEngine(ctx))
+
+	for _, rangeID := range []roachpb.RangeID{1, 2, 3} {
+		require.Empty(t, entries(eng, rangeID))
+	}
+	require.Equal(t, []uint64{11, 12, 13}, entries(raftEng, 1))
+	require.Equal(t, []uint64{11, 12, 13, 14, 15}, entries(raftEng, 2))
+	require.Empty(t, entries(raftEng, 3))
+
+	// Reconciling again is a no-op.
+	require.NoError(t, s.reconcileRaftLogEngine(ctx))
+	require.Equal(t, []uint64{11, 12, 13}, entries(raftEng, 1))
+
+	// The store's engine records that the Raft log is kept separately.
+	s = newStore(eng)
+	require.Error(t, s.reconcileRaftLogEngine(ctx))
+
+	// An empty Raft log engine, for example because raft-log-path was changed,
+	// isn't accepted in place of the one holding the Raft log.
+	emptyEng := engine.NewDefaultInMem()
+	stopper.AddCloser(emptyEng)
+	s = newStore(emptyEng)
+	require.Error(t, s.reconcileRaftLogEngine(ctx))
+	require.Empty(t, entries(emptyEng, 1))
+
+	// Nor is the Raft log engine of another store.
+	otherEng := engine.NewDefaultInMem()
+	stopper.AddCloser(otherEng)
+	otherIdent := roachpb.StoreIdent{ClusterID: ident.ClusterID, NodeID: 2, StoreID: 2}
+	require.NoError(t, engine.MVCCPutProto(
+		ctx, otherEng, nil /* ms */, keys.StoreIdentKey(), hlc.Timestamp{}, nil /* txn */, &otherIdent,
+	))
+	s = newStore(otherEng)
+	require.Error(t, s.reconcileRaftLogEngine(ctx))
+
+	// Moving the Raft log back into the store's engine allows the store to be
+	// started without the Raft log engine again.
+	require.NoError(t, MergeRaftLogEngine(ctx, eng, raftEng))
+	require.Equal(t, []uint64{11, 12, 13}, entries(eng, 1))
+	require.Equal(t, []uint64{11, 12, 13, 14, 15}, entries(eng, 2))
+	require.Empty(t, entries(raftEng, 1))
+	require.Empty(t, entries(raftEng, 2))
+	s = newStore(eng)
+	require.NoError(t, s.reconcileRaftLogEngine(ctx))
+}
@@ -266,6 +266,9 @@ func (s *Store) unlinkReplicaByRangeIDLocked(rangeID roachpb.RangeID) {
 	s.unquiescedReplicas.Lock()
 	delete(s.unquiescedReplicas.m, rangeID)
 	s.unquiescedReplicas.Unlock()
+	s.globalReadsReplicas.Lock()
+	delete(s.globalReadsReplicas.m, rangeID)
+	s.globalReadsReplicas.Unlock()
 	delete(s.mu.uninitReplicas, rangeID)
 	s.replicaQueues.Delete(int64(rangeID))
 	s.mu.replicas.Delete(int64(rangeID))
@@ -55,11 +55,38 @@ func (s *Store) Send(
 		}
 	}
 
-	// Limit the number of concurrent AddSSTable requests, since they're expensive
-	// and block all other writes to the same span.
+	// Queue writes behind higher-priority work while the storage engine is
+	// overloaded. Reads don't add to the engine's compaction debt, so they are
+	// never queued.
+	if !ba.IsReadOnly() {
+		if err := s.admission.Admit(ctx, admissionPriority(&ba)); err != nil {
+			return nil, roachpb.NewError(err)
+		}
+	}
+
 	if ba.IsSingleAddSSTableRequest() {
 		before := timeutil.Now()
+		// Keep the store within its ingestion budget. Past the delay the store
+		// is willing to impose, the client is asked to back off instead. This
+		// happens before acquiring the limiter below, so that a delayed request
+		// doesn't hold up others that fit in the budget.
+		size := int64(len(ba.Requests[0].GetAddSstable().Data))
+		backoff, err := s.ingestBudget.Wait(ctx, size)
+		if err != nil {
+			return nil, roachpb.NewError(err)
+		}
+		if backoff > 0 {
+			defer func() {
+				if pErr == nil {
+					br.Responses[0].GetAddSstable().BackpressureDelayNanos = backoff.Nanoseconds()
+				}
+			}()
+		}
+
+		// Limit the number of concurrent AddSSTable requests, since they're
+		// expensive and block all other writes to the same span.
 		if err := s.limiters.ConcurrentAddSSTableRequests.Begin(ctx); err != nil {
+			s.ingestBudget.Refund(size)
 			return nil, roachpb.NewError(err)
 		}
 		defer s.limiters.ConcurrentAddSSTableRequests.Finish()
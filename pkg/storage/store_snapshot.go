@@ -422,7 +422,7 @@ func (kvSS *kvBatchSnapshotStrategy) Send(
 
 	rangeID := header.State.Desc.RangeID
 
-	if err := iterateEntries(ctx, snap.EngineSnap, rangeID, firstIndex, endIndex, scanFunc); err != nil {
+	if err := iterateEntries(ctx, snap.RaftLogSnap, rangeID, firstIndex, endIndex, scanFunc); err != nil {
 		return err
 	}
 
@@ -147,7 +147,11 @@ func splitPostApply(
 		// the splitPostApply so that it refers to a LAI that is equal to the index at
 		// which this lease was applied. If it were to refer to a LAI after the split
 		// then the value of initialMaxClosed might be unsafe.
-		initialMaxClosed := r.maxClosed(ctx)
+		//
+		// The closed timestamp published for the LHS as configured for global
+		// reads is not inherited: the RHS may have a different zone config, and
+		// its lease holder would not push its writes above it.
+		initialMaxClosed, _ := r.maxClosedAndGlobalReadsClosed(ctx)
 		r.mu.Lock()
 		rightRng.mu.Lock()
 		// Copy the minLeaseProposedTS from the LHS.
@@ -1423,6 +1423,16 @@ var charts = []sectionDescription{
 				},
 				AxisLabel: "Plane Cache Accesses",
 			},
+			{
+				Title: "Generic Plans",
+				Metrics: []string{
+					"sql.optimizer.generic_plan.hits",
+					"sql.optimizer.generic_plan.hits.internal",
+					"sql.optimizer.generic_plan.misses",
+					"sql.optimizer.generic_plan.misses.internal",
+				},
+				AxisLabel: "Generic Plan Accesses",
+			},
 		},
 	},
 	{
@@ -1828,6 +1838,20 @@ var charts = []sectionDescription{
 				Metrics: []string{
 					"addsstable.delay.total",
 					"addsstable.delay.enginebackpressure",
+					"addsstable.budget.delay.bytes",
+					"addsstable.budget.delay.compactions",
+				},
+			},
+			{
+				Title: "Ingestion Backpressure",
+				Metrics: []string{
+					"addsstable.budget.backpressure",
+				},
+			},
+			{
+				Title: "Ingestion Backpressure Time",
+				Metrics: []string{
+					"addsstable.budget.backpressure_time",
 				},
 			},
 		},
@@ -1861,6 +1885,47 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{StorageLayer, "Storage", "Admission"}},
+		Charts: []chartDescription{
+			{
+				Title:   "Overload Level",
+				Metrics: []string{"admission.overload_level"},
+			},
+			{
+				Title: "Admitted",
+				Metrics: []string{
+					"admission.system.admitted",
+					"admission.user.admitted",
+					"admission.background.admitted",
+				},
+			},
+			{
+				Title: "Canceled",
+				Metrics: []string{
+					"admission.system.canceled",
+					"admission.user.canceled",
+					"admission.background.canceled",
+				},
+			},
+			{
+				Title: "Queued",
+				Metrics: []string{
+					"admission.system.queued",
+					"admission.user.queued",
+					"admission.background.queued",
+				},
+			},
+			{
+				Title: "Wait Time",
+				Metrics: []string{
+					"admission.system.wait_time",
+					"admission.user.wait_time",
+					"admission.background.wait_time",
+				},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{StorageLayer, "Storage", "KV"}},
 		Charts: []chartDescription{
@@ -279,6 +279,18 @@ export default function Debug() {
             note="/_status/hotranges?node_id=[node_id]"
           />
         </DebugTableRow>
+        <DebugTableRow title="Hot Keys">
+          <DebugTableLink
+            name="All Nodes"
+            url="/_status/hotkeys"
+            note="/_status/hotkeys"
+          />
+          <DebugTableLink
+            name="Single node's keys"
+            url="/_status/hotkeys?node_id=local"
+            note="/_status/hotkeys?node_id=[node_id]"
+          />
+        </DebugTableRow>
         <DebugTableRow title="Single Node Specific">
           <DebugTableLink
             name="Stores"
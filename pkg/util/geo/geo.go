@@ -0,0 +1,333 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package geo contains the spatial GEOMETRY and GEOGRAPHY values, their
+// textual (WKT, EWKT and GeoJSON) and binary (WKB and EWKB) representations,
+// and the spatial predicates and measurements supported on them.
+//
+// A GEOMETRY is a shape on a planar coordinate system, and all of its
+// measurements are made in the units of that coordinate system. A GEOGRAPHY is
+// a shape on the surface of the Earth, with coordinates given as longitude and
+// latitude in degrees, and its measurements are made in meters on a sphere
+// with the mean radius of the Earth.
+package geo
+
+import (
+	"encoding/hex"
+	"math"
+	"strings"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// DefaultGeographySRID is the spatial reference identifier of WGS 84, the
+// reference system of longitude and latitude coordinates. It is the only SRID
+// supported for geographies, and the SRID of shapes parsed from GeoJSON.
+const DefaultGeographySRID = 4326
+
+// ShapeType is the type of a spatial shape. The values match the type codes
+// of the WKB format.
+type ShapeType uint32
+
+// The shape types that are supported. Geometry collections and shapes with Z
+// or M coordinates are not supported.
+const (
+	Point ShapeType = 1 + iota
+	LineString
+	Polygon
+	MultiPoint
+	MultiLineString
+	MultiPolygon
+)
+
+var shapeTypeNames = [...]string{
+	Point:           "POINT",
+	LineString:      "LINESTRING",
+	Polygon:         "POLYGON",
+	MultiPoint:      "MULTIPOINT",
+	MultiLineString: "MULTILINESTRING",
+	MultiPolygon:    "MULTIPOLYGON",
+}
+
+// String returns the WKT name of the shape type.
+func (t ShapeType) String() string {
+	if t < Point || t > MultiPolygon {
+		return "UNKNOWN"
+	}
+	return shapeTypeNames[t]
+}
+
+// geoJSONName returns the name of the shape type in GeoJSON.
+func (t ShapeType) geoJSONName() string {
+	switch t {
+	case Point:
+		return "Point"
+	case LineString:
+		return "LineString"
+	case Polygon:
+		return "Polygon"
+	case MultiPoint:
+		return "MultiPoint"
+	case MultiLineString:
+		return "MultiLineString"
+	default:
+		return "MultiPolygon"
+	}
+}
+
+// isMulti returns true if the shape type is a collection of simple shapes.
+func (t ShapeType) isMulti() bool {
+	return t >= MultiPoint
+}
+
+// simple returns the type of the simple shapes of a collection type, or the
+// type itself if it is not a collection.
+func (t ShapeType) simple() ShapeType {
+	if t.isMulti() {
+		return t - 3
+	}
+	return t
+}
+
+// Coord is a coordinate of a shape. For geographies, X is the longitude and Y
+// is the latitude, both in degrees.
+type Coord struct {
+	X, Y float64
+}
+
+// Shape is a spatial shape. It is made of parts, which are the simple shapes
+// (points, linestrings or polygons) of the shape, and each part is a list of
+// rings:
+//
+//   - the part of a point has a single ring with a single coordinate.
+//   - the part of a linestring has a single ring with the vertices of the line.
+//   - the part of a polygon has the exterior ring of the polygon followed by
+//     its holes. Rings are closed: the first and last coordinates are equal.
+//
+// A POINT, LINESTRING or POLYGON has a single part, while a MULTIPOINT,
+// MULTILINESTRING or MULTIPOLYGON has any number of parts. An empty shape has
+// no parts.
+type Shape struct {
+	Type  ShapeType
+	Parts [][][]Coord
+}
+
+// IsEmpty returns true if the shape has no parts.
+func (s *Shape) IsEmpty() bool {
+	return len(s.Parts) == 0
+}
+
+// Size returns the approximate size in bytes of the shape.
+func (s *Shape) Size() uintptr {
+	size := unsafe.Sizeof(*s)
+	for _, part := range s.Parts {
+		size += unsafe.Sizeof(part)
+		for _, ring := range part {
+			size += unsafe.Sizeof(ring) + uintptr(len(ring))*unsafe.Sizeof(Coord{})
+		}
+	}
+	return size
+}
+
+// validate checks that the parts of the shape are well-formed for its type.
+func (s *Shape) validate() error {
+	if s.Type < Point || s.Type > MultiPolygon {
+		return pgerror.Newf(pgcode.InvalidParameterValue, "unsupported shape type %d", s.Type)
+	}
+	if !s.Type.isMulti() && len(s.Parts) > 1 {
+		return pgerror.Newf(pgcode.InvalidParameterValue, "%s must have a single part", s.Type)
+	}
+	for _, part := range s.Parts {
+		switch s.Type.simple() {
+		case Point:
+			if len(part) != 1 || len(part[0]) != 1 {
+				return pgerror.New(pgcode.InvalidParameterValue, "point must have a single coordinate")
+			}
+		case LineString:
+			if len(part) != 1 {
+				return pgerror.New(pgcode.InvalidParameterValue, "linestring must have a single ring")
+			}
+			if len(part[0]) < 2 {
+				return pgerror.New(pgcode.InvalidParameterValue, "linestring must have at least 2 points")
+			}
+		case Polygon:
+			if len(part) == 0 {
+				return pgerror.New(pgcode.InvalidParameterValue, "polygon must have at least one ring")
+			}
+			for _, ring := range part {
+				if len(ring) < 4 {
+					return pgerror.New(pgcode.InvalidParameterValue, "polygon ring must have at least 4 points")
+				}
+				if ring[0] != ring[len(ring)-1] {
+					return pgerror.New(pgcode.InvalidParameterValue, "polygon ring must be closed")
+				}
+			}
+		}
+		for _, ring := range part {
+			for _, c := range ring {
+				if math.IsNaN(c.X) || math.IsNaN(c.Y) || math.IsInf(c.X, 0) || math.IsInf(c.Y, 0) {
+					return pgerror.New(pgcode.InvalidParameterValue, "coordinates must be finite numbers")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Geometry is a spatial shape on a planar coordinate system, identified by
+// its spatial reference identifier (SRID). An SRID of 0 means that the
+// coordinate system is unknown.
+type Geometry struct {
+	SRID int32
+	Shape
+}
+
+// MakeGeometry returns a geometry with the given SRID and shape.
+func MakeGeometry(srid int32, shape Shape) (*Geometry, error) {
+	if err := shape.validate(); err != nil {
+		return nil, err
+	}
+	return &Geometry{SRID: srid, Shape: shape}, nil
+}
+
+// ParseGeometry parses a geometry from its (E)WKT, hex-encoded (E)WKB or
+// GeoJSON representation.
+func ParseGeometry(s string) (*Geometry, error) {
+	srid, shape, err := parseShape(s)
+	if err != nil {
+		return nil, err
+	}
+	return MakeGeometry(srid, shape)
+}
+
+// Geography is a spatial shape on the surface of the Earth. The X and Y
+// coordinates of the shape are the longitude and latitude in degrees.
+type Geography struct {
+	SRID int32
+	Shape
+}
+
+// MakeGeography returns a geography with the given SRID and shape. An SRID of
+// 0 is replaced with DefaultGeographySRID.
+func MakeGeography(srid int32, shape Shape) (*Geography, error) {
+	if srid == 0 {
+		srid = DefaultGeographySRID
+	}
+	if srid != DefaultGeographySRID {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"SRID %d is not supported for geography; only SRID %d is supported", srid, DefaultGeographySRID)
+	}
+	if err := shape.validate(); err != nil {
+		return nil, err
+	}
+	for _, part := range shape.Parts {
+		for _, ring := range part {
+			for _, c := range ring {
+				if c.X < -180 || c.X > 180 || c.Y < -90 || c.Y > 90 {
+					return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+						"longitude %g or latitude %g is out of range for geography", c.X, c.Y)
+				}
+			}
+		}
+	}
+	return &Geography{SRID: srid, Shape: shape}, nil
+}
+
+// ParseGeography parses a geography from its (E)WKT, hex-encoded (E)WKB or
+// GeoJSON representation.
+func ParseGeography(s string) (*Geography, error) {
+	srid, shape, err := parseShape(s)
+	if err != nil {
+		return nil, err
+	}
+	return MakeGeography(srid, shape)
+}
+
+// parseShape parses a shape and its SRID from any of the supported
+// representations.
+func parseShape(s string) (int32, Shape, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return 0, Shape{}, pgerror.New(pgcode.InvalidParameterValue, "empty spatial value")
+	case s[0] == '{':
+		shape, err := parseGeoJSON([]byte(s))
+		return DefaultGeographySRID, shape, err
+	case isHex(s):
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return 0, Shape{}, pgerror.Newf(pgcode.InvalidParameterValue, "invalid hex-encoded WKB: %v", err)
+		}
+		return decodeEWKB(b)
+	default:
+		return parseEWKT(s)
+	}
+}
+
+// isHex returns true if s is a non-empty hexadecimal string of even length.
+// No WKT string is hexadecimal, since every shape type name contains letters
+// outside of A-F.
+func isHex(s string) bool {
+	if len(s)%2 != 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9') && !('a' <= c && c <= 'f') && !('A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// forEachCoord calls fn on every coordinate of the shape.
+func (s *Shape) forEachCoord(fn func(Coord)) {
+	for _, part := range s.Parts {
+		for _, ring := range part {
+			for _, c := range ring {
+				fn(c)
+			}
+		}
+	}
+}
+
+// BoundingBox is an axis-aligned rectangle.
+type BoundingBox struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// BoundingBox returns the smallest rectangle that contains the coordinates of
+// the shape. The shape must not be empty.
+func (s *Shape) BoundingBox() BoundingBox {
+	b := BoundingBox{
+		MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1),
+	}
+	s.forEachCoord(func(c Coord) {
+		b.MinX = math.Min(b.MinX, c.X)
+		b.MinY = math.Min(b.MinY, c.Y)
+		b.MaxX = math.Max(b.MaxX, c.X)
+		b.MaxY = math.Max(b.MaxY, c.Y)
+	})
+	return b
+}
+
+// Expand returns the rectangle grown by d in every direction.
+func (b BoundingBox) Expand(d float64) BoundingBox {
+	return BoundingBox{MinX: b.MinX - d, MinY: b.MinY - d, MaxX: b.MaxX + d, MaxY: b.MaxY + d}
+}
+
+// errMixedSRIDs is returned by the operations on two geometries with
+// different SRIDs.
+func errMixedSRIDs(a, b int32) error {
+	return pgerror.Newf(pgcode.InvalidParameterValue,
+		"operation on mixed SRIDs: %d and %d", a, b)
+}
@@ -0,0 +1,343 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geo
+
+import (
+	"encoding/hex"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+)
+
+func TestParseGeometry(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{input: `POINT(1 2)`, expected: `POINT(1 2)`},
+		{input: ` point ( -1.5  2e3 ) `, expected: `POINT(-1.5 2000)`},
+		{input: `SRID=3857;POINT(1 2)`, expected: `SRID=3857;POINT(1 2)`},
+		{input: `POINT EMPTY`, expected: `POINT EMPTY`},
+		{input: `LINESTRING(0 0, 1 1, 2 0)`, expected: `LINESTRING(0 0,1 1,2 0)`},
+		{input: `POLYGON((0 0, 4 0, 4 4, 0 4, 0 0), (1 1, 2 1, 2 2, 1 1))`,
+			expected: `POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 1))`},
+		{input: `MULTIPOINT(0 0, 1 1)`, expected: `MULTIPOINT(0 0,1 1)`},
+		{input: `MULTIPOINT((0 0), (1 1))`, expected: `MULTIPOINT(0 0,1 1)`},
+		{input: `MULTILINESTRING((0 0, 1 1), (2 2, 3 3))`, expected: `MULTILINESTRING((0 0,1 1),(2 2,3 3))`},
+		{input: `MULTIPOLYGON(((0 0, 1 0, 1 1, 0 0)), ((5 5, 6 5, 6 6, 5 5)))`,
+			expected: `MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((5 5,6 5,6 6,5 5)))`},
+		{input: `0101000000000000000000F03F0000000000000040`, expected: `POINT(1 2)`},
+		{input: `0101000020E6100000000000000000F03F0000000000000040`, expected: `SRID=4326;POINT(1 2)`},
+		{input: `{"type":"LineString","coordinates":[[0,0],[1,2]]}`, expected: `SRID=4326;LINESTRING(0 0,1 2)`},
+		{input: `POINT(0.1 0.30000000000000004)`, expected: `POINT(0.1 0.3)`},
+		{input: ``, err: `empty spatial value`},
+		{input: `POINT(1)`, err: `invalid WKT`},
+		{input: `POINT(1 2 3)`, err: `two dimensions`},
+		{input: `POINT Z (1 2 3)`, err: `Z coordinates are not supported`},
+		{input: `LINESTRING(0 0)`, err: `at least 2 points`},
+		{input: `POLYGON((0 0, 1 0, 1 1, 0 1))`, err: `must be closed`},
+		{input: `POLYGON((0 0, 1 0, 0 0))`, err: `at least 4 points`},
+		{input: `GEOMETRYCOLLECTION(POINT(1 2))`, err: `GEOMETRYCOLLECTION is not supported`},
+		{input: `POINT(1 2) x`, err: `invalid WKT`},
+		{input: `SRID=x;POINT(1 2)`, err: `invalid SRID`},
+		{input: `0101000000000000000000F03F`, err: `WKB is too short`},
+		{input: `{"type":"Feature"}`, err: `unsupported GeoJSON type`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			g, err := ParseGeometry(tc.input)
+			if !testutils.IsError(err, tc.err) {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if s := g.EWKT(); s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+
+			// The EWKB and hex-encoded EWKB must round-trip exactly, while the
+			// EWKT may lose precision.
+			g2, err := GeometryFromEWKB(g.EWKB())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(g, g2) {
+				t.Fatalf("expected %v, got %v", g, g2)
+			}
+			if g2, err = ParseGeometry(hex.EncodeToString(g.EWKB())); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(g, g2) {
+				t.Fatalf("expected %v, got %v", g, g2)
+			}
+			if g2, err = ParseGeometry(g.EWKT()); err != nil {
+				t.Fatal(err)
+			}
+			if s := g2.EWKT(); s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+		})
+	}
+}
+
+func TestParseGeography(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{input: `POINT(-73.98 40.75)`, expected: `SRID=4326;POINT(-73.98 40.75)`},
+		{input: `SRID=4326;POINT(1 2)`, expected: `SRID=4326;POINT(1 2)`},
+		{input: `SRID=3857;POINT(1 2)`, err: `SRID 3857 is not supported for geography`},
+		{input: `POINT(181 0)`, err: `out of range for geography`},
+		{input: `POINT(0 -91)`, err: `out of range for geography`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			g, err := ParseGeography(tc.input)
+			if !testutils.IsError(err, tc.err) {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if s := g.EWKT(); s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+		})
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	testCases := []struct {
+		wkt      string
+		expected string
+	}{
+		{`POINT(1.123456789123 2)`, `{"type":"Point","coordinates":[1.123456789,2]}`},
+		{`POINT EMPTY`, `{"type":"Point","coordinates":[]}`},
+		{`LINESTRING(0 0,1 1)`, `{"type":"LineString","coordinates":[[0,0],[1,1]]}`},
+		{`POLYGON((0 0,1 0,1 1,0 0))`, `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`},
+		{`MULTIPOINT(0 0,1 1)`, `{"type":"MultiPoint","coordinates":[[0,0],[1,1]]}`},
+		{`MULTILINESTRING((0 0,1 1))`, `{"type":"MultiLineString","coordinates":[[[0,0],[1,1]]]}`},
+		{`MULTIPOLYGON(((0 0,1 0,1 1,0 0)))`, `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]]]}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.wkt, func(t *testing.T) {
+			g, err := ParseGeometry(tc.wkt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := g.GeoJSON(9 /* maxDecimalDigits */)
+			if s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+			if g.IsEmpty() {
+				return
+			}
+			g2, err := GeometryFromGeoJSON([]byte(s))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g2.SRID != DefaultGeographySRID || g2.Shape.WKT() != parseTestShape(t, s).WKT() {
+				t.Fatalf("GeoJSON didn't round-trip: %s", g2.EWKT())
+			}
+		})
+	}
+}
+
+// parseTestShape parses a shape, failing the test on error.
+func parseTestShape(t *testing.T, s string) *Shape {
+	_, shape, err := parseShape(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &shape
+}
+
+func TestGeometryPredicates(t *testing.T) {
+	testCases := []struct {
+		a, b       string
+		distance   float64
+		intersects bool
+		covers     bool
+		contains   bool
+	}{
+		// Points.
+		{a: `POINT(0 0)`, b: `POINT(3 4)`, distance: 5},
+		{a: `POINT(1 1)`, b: `POINT(1 1)`, intersects: true, covers: true, contains: true},
+		// Point and line.
+		{a: `LINESTRING(0 0, 2 0)`, b: `POINT(1 1)`, distance: 1},
+		{a: `LINESTRING(0 0, 2 0)`, b: `POINT(1 0)`, intersects: true, covers: true, contains: true},
+		{a: `LINESTRING(0 0, 2 0)`, b: `POINT(2 0)`, intersects: true, covers: true},
+		// Point and polygon.
+		{a: `POLYGON((0 0, 4 0, 4 4, 0 4, 0 0))`, b: `POINT(2 2)`, intersects: true, covers: true, contains: true},
+		{a: `POLYGON((0 0, 4 0, 4 4, 0 4, 0 0))`, b: `POINT(4 2)`, intersects: true, covers: true},
+		{a: `POLYGON((0 0, 4 0, 4 4, 0 4, 0 0))`, b: `POINT(7 8)`, distance: 5},
+		{a: `POLYGON((0 0, 4 0, 4 4, 0 4, 0 0), (1 1, 3 1, 3 3, 1 3, 1 1))`, b: `POINT(2 2)`, distance: 1},
+		// Lines.
+		{a: `LINESTRING(0 0, 2 2)`, b: `LINESTRING(0 2, 2 0)`, intersects: true},
+		{a: `LINESTRING(0 0, 1 0)`, b: `LINESTRING(0 1, 1 1)`, distance: 1},
+		{a: `LINESTRING(0 0, 4 0)`, b: `LINESTRING(1 0, 2 0)`, intersects: true, covers: true, contains: true},
+		// Line and polygon.
+		{a: `POLYGON((0 0, 4 0, 4 4, 0 4, 0 0))`, b: `LINESTRING(1 1, 2 2)`, intersects: true, covers: true, contains: true},
+		{a: `POLYGON((0 0, 4 0, 4 4, 0 4, 0 0))`, b: `LINESTRING(1 1, 5 5)`, intersects: true},
+		{a: `POLYGON((0 0, 4 0, 4 4, 0 4, 0 0))`, b: `LINESTRING(0 0, 4 0)`, intersects: true, covers: true},
+		{a: `POLYGON((0 0, 4 0, 4 4, 0 4, 0 0))`, b: `LINESTRING(6 0, 6 4)`, distance: 2},
+		// A concave polygon doesn't cover a line between two of its vertices.
+		{a: `POLYGON((0 0, 4 0, 4 4, 2 1, 0 4, 0 0))`, b: `LINESTRING(0 3, 4 3)`, intersects: true},
+		// Polygons.
+		{a: `POLYGON((0 0, 4 0, 4 4, 0 4, 0 0))`, b: `POLYGON((1 1, 2 1, 2 2, 1 1))`, intersects: true, covers: true, contains: true},
+		{a: `POLYGON((0 0, 4 0, 4 4, 0 4, 0 0))`, b: `POLYGON((0 0, 4 0, 4 4, 0 4, 0 0))`, intersects: true, covers: true, contains: true},
+		{a: `POLYGON((0 0, 1 0, 1 1, 0 1, 0 0))`, b: `POLYGON((1 0, 2 0, 2 1, 1 1, 1 0))`, intersects: true},
+		{a: `POLYGON((0 0, 1 0, 1 1, 0 1, 0 0))`, b: `POLYGON((3 0, 4 0, 4 1, 3 1, 3 0))`, distance: 2},
+		{a: `POLYGON((0 0, 1 0, 1 1, 0 1, 0 0))`, b: `POLYGON((-1 -1, 2 -1, 2 2, -1 2, -1 -1))`, intersects: true},
+		{a: `POLYGON((0 0, 4 0, 4 4, 0 4, 0 0), (1 1, 3 1, 3 3, 1 3, 1 1))`, b: `POLYGON((0.5 0.5, 3.5 0.5, 3.5 3.5, 0.5 0.5))`,
+			intersects: true},
+		// Collections.
+		{a: `MULTIPOINT(0 0, 10 10)`, b: `POINT(10 10)`, intersects: true, covers: true, contains: true},
+		{a: `POINT(10 10)`, b: `MULTIPOINT(0 0, 10 10)`, intersects: true},
+		{a: `MULTIPOLYGON(((0 0, 1 0, 1 1, 0 0)), ((5 5, 6 5, 6 6, 5 5)))`, b: `MULTIPOINT(0.9 0.1, 5.9 5.1)`,
+			intersects: true, covers: true, contains: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.a+" "+tc.b, func(t *testing.T) {
+			a, err := ParseGeometry(tc.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := ParseGeometry(tc.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, swap := range []bool{false, true} {
+				x, y := a, b
+				if swap {
+					x, y = b, a
+				}
+				d, err := x.Distance(y)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if math.Abs(d-tc.distance) > 1e-9 {
+					t.Errorf("expected distance %g, got %g", tc.distance, d)
+				}
+				if i, _ := x.Intersects(y); i != tc.intersects {
+					t.Errorf("expected intersects %t, got %t", tc.intersects, i)
+				}
+				if i, _ := x.DWithin(y, tc.distance); !i {
+					t.Errorf("expected to be within %g", tc.distance)
+				}
+			}
+			if c, _ := a.Covers(b); c != tc.covers {
+				t.Errorf("expected covers %t, got %t", tc.covers, c)
+			}
+			if c, _ := a.Contains(b); c != tc.contains {
+				t.Errorf("expected contains %t, got %t", tc.contains, c)
+			}
+		})
+	}
+}
+
+func TestGeometryMixedSRIDs(t *testing.T) {
+	a, err := ParseGeometry(`SRID=4326;POINT(1 2)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseGeometry(`POINT(1 2)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Intersects(b); !testutils.IsError(err, `operation on mixed SRIDs`) {
+		t.Fatalf("expected mixed SRIDs error, got %v", err)
+	}
+}
+
+func TestGeographyPredicates(t *testing.T) {
+	testCases := []struct {
+		a, b       string
+		distance   float64
+		intersects bool
+	}{
+		// One degree of latitude.
+		{a: `POINT(0 0)`, b: `POINT(0 1)`, distance: 111195.08},
+		// From New York to London.
+		{a: `POINT(-74.006 40.7128)`, b: `POINT(-0.1278 51.5074)`, distance: 5570229.87},
+		// A great circle arc between two points of the same latitude passes
+		// closer to the pole than its endpoints.
+		{a: `LINESTRING(-90 45, 90 45)`, b: `POINT(0 90)`, distance: 0, intersects: true},
+		{a: `LINESTRING(-10 0, 10 0)`, b: `POINT(0 1)`, distance: 111195.08},
+		{a: `LINESTRING(-10 -1, 10 1)`, b: `LINESTRING(-10 1, 10 -1)`, intersects: true},
+		// Arcs crossing the antimeridian.
+		{a: `LINESTRING(179 -1, -179 1)`, b: `LINESTRING(179 1, -179 -1)`, intersects: true},
+		{a: `POLYGON((0 0, 2 0, 2 2, 0 2, 0 0))`, b: `POINT(1 1)`, intersects: true},
+		// The closest point of the polygon is on the arc of its northern edge,
+		// north of its vertices.
+		{a: `POLYGON((0 0, 2 0, 2 2, 0 2, 0 0))`, b: `POINT(1 3)`, distance: 111161.23},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.a+" "+tc.b, func(t *testing.T) {
+			a, err := ParseGeography(tc.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := ParseGeography(tc.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, swap := range []bool{false, true} {
+				x, y := a, b
+				if swap {
+					x, y = b, a
+				}
+				if d := x.Distance(y); math.Abs(d-tc.distance) > 1 {
+					t.Errorf("expected distance %g, got %g", tc.distance, d)
+				}
+				if i := x.Intersects(y); i != tc.intersects {
+					t.Errorf("expected intersects %t, got %t", tc.intersects, i)
+				}
+			}
+		})
+	}
+}
+
+func TestGeographyBoundingBox(t *testing.T) {
+	testCases := []struct {
+		wkt      string
+		expected BoundingBox
+	}{
+		{`POINT(1 2)`, BoundingBox{MinX: 1, MinY: 2, MaxX: 1, MaxY: 2}},
+		{`LINESTRING(-10 0, 10 0)`, BoundingBox{MinX: -10, MinY: 0, MaxX: 10, MaxY: 0}},
+		{`LINESTRING(-90 45, 90 45)`, BoundingBox{MinX: -90, MinY: 45, MaxX: 90, MaxY: 90}},
+		{`LINESTRING(-90 -45, 90 -45)`, BoundingBox{MinX: -90, MinY: -90, MaxX: 90, MaxY: -45}},
+		{`LINESTRING(170 0, -170 0)`, BoundingBox{MinX: -180, MinY: 0, MaxX: 180, MaxY: 0}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.wkt, func(t *testing.T) {
+			g, err := ParseGeography(tc.wkt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := g.BoundingBox()
+			for _, v := range [][2]float64{
+				{b.MinX, tc.expected.MinX}, {b.MinY, tc.expected.MinY},
+				{b.MaxX, tc.expected.MaxX}, {b.MaxY, tc.expected.MaxY},
+			} {
+				if math.Abs(v[0]-v[1]) > 1e-9 {
+					t.Fatalf("expected %+v, got %+v", tc.expected, b)
+				}
+			}
+		})
+	}
+}
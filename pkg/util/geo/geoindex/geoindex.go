@@ -0,0 +1,225 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package geoindex computes the keys of the inverted indexes of spatial
+// columns.
+//
+// The indexed space is a rectangle that is recursively divided in a quadtree
+// of cells: the cell of level 0 is the whole space, and each cell of level L
+// is divided in 4 cells of level L+1. Each shape is indexed by a covering of
+// up to 4 cells of the same level that contain its bounding box. Two shapes
+// can only intersect if a cell of the covering of one of them is equal to, or
+// an ancestor or a descendant of, a cell of the covering of the other.
+//
+// Cells are identified by integers such that the descendants of a cell form a
+// contiguous range around it, so that the indexed shapes that can intersect a
+// query shape are found by scanning the ranges of the descendants of the cells
+// of its covering, along with the keys of their ancestors. Shapes that are not
+// entirely inside of the indexed space are indexed by the cell of level 0.
+package geoindex
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/util/geo"
+)
+
+// MaxLevel is the level of the smallest cells.
+const MaxLevel = 30
+
+// CellID identifies a cell of the quadtree. The position of a cell of level L
+// among the cells of its level, which is the interleaving of the bits of its
+// column and row, is followed by a 1 bit and by 2*(MaxLevel-L) 0 bits.
+type CellID uint64
+
+// Root is the cell of level 0, which contains the whole space.
+const Root = CellID(1) << (2 * MaxLevel)
+
+// makeCellID returns the cell of the given level at column x and row y.
+func makeCellID(level int, x, y uint64) CellID {
+	var pos uint64
+	for i := level - 1; i >= 0; i-- {
+		pos = pos<<2 | ((x>>uint(i))&1)<<1 | (y>>uint(i))&1
+	}
+	return CellID((pos<<1 | 1) << uint(2*(MaxLevel-level)))
+}
+
+// lsb returns the lowest bit of the cell ID that is set.
+func (c CellID) lsb() CellID {
+	return c & -c
+}
+
+// Level returns the level of the cell.
+func (c CellID) Level() int {
+	level := MaxLevel
+	for l := c.lsb(); l > 1; l >>= 2 {
+		level--
+	}
+	return level
+}
+
+// Parent returns the cell of the previous level that contains the cell. The
+// cell must not be Root.
+func (c CellID) Parent() CellID {
+	lsb := c.lsb() << 2
+	return c&-lsb | lsb
+}
+
+// RangeMin returns the smallest cell ID of the descendants of the cell.
+func (c CellID) RangeMin() CellID {
+	return c - (c.lsb() - 1)
+}
+
+// RangeMax returns the largest cell ID of the descendants of the cell.
+func (c CellID) RangeMax() CellID {
+	return c + (c.lsb() - 1)
+}
+
+// Config is the rectangle of the indexed space.
+type Config struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// GeometryConfig is the indexed space of geometries. Its bounds are fixed, so
+// geometries far from the origin are indexed by the root cell.
+var GeometryConfig = Config{MinX: -(1 << 25), MinY: -(1 << 25), MaxX: 1 << 25, MaxY: 1 << 25}
+
+// GeographyConfig is the indexed space of geographies, in longitude and
+// latitude.
+var GeographyConfig = Config{MinX: -180, MinY: -90, MaxX: 180, MaxY: 90}
+
+// Covering returns the cells of the deepest level such that at most 2 by 2
+// cells of that level cover the bounding box, sorted by ID.
+func (cfg *Config) Covering(b geo.BoundingBox) []CellID {
+	if !(b.MinX >= cfg.MinX && b.MaxX <= cfg.MaxX && b.MinY >= cfg.MinY && b.MaxY <= cfg.MaxY) {
+		return []CellID{Root}
+	}
+	for level := MaxLevel; ; level-- {
+		n := uint64(1) << uint(level)
+		x0, x1 := cfg.column(b.MinX, n), cfg.column(b.MaxX, n)
+		y0, y1 := cfg.row(b.MinY, n), cfg.row(b.MaxY, n)
+		if x1-x0 > 1 || y1-y0 > 1 {
+			continue
+		}
+		var cells []CellID
+		for x := x0; x <= x1; x++ {
+			for y := y0; y <= y1; y++ {
+				cells = append(cells, makeCellID(level, x, y))
+			}
+		}
+		sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+		return cells
+	}
+}
+
+// column returns the column of the coordinate x among n columns.
+func (cfg *Config) column(x float64, n uint64) uint64 {
+	return cellIndex((x-cfg.MinX)/(cfg.MaxX-cfg.MinX), n)
+}
+
+// row returns the row of the coordinate y among n rows.
+func (cfg *Config) row(y float64, n uint64) uint64 {
+	return cellIndex((y-cfg.MinY)/(cfg.MaxY-cfg.MinY), n)
+}
+
+func cellIndex(f float64, n uint64) uint64 {
+	i := uint64(math.Max(0, math.Floor(f*float64(n))))
+	if i >= n {
+		i = n - 1
+	}
+	return i
+}
+
+// GeometryCovering returns the cells that index a geometry. An empty geometry
+// has no cells.
+func GeometryCovering(g *geo.Geometry) []CellID {
+	if g.IsEmpty() {
+		return nil
+	}
+	return GeometryConfig.Covering(g.BoundingBox())
+}
+
+// GeometryDWithinCovering returns the cells that contain the points within
+// distance d of a geometry.
+func GeometryDWithinCovering(g *geo.Geometry, d float64) []CellID {
+	if g.IsEmpty() {
+		return nil
+	}
+	return GeometryConfig.Covering(g.BoundingBox().Expand(d))
+}
+
+// GeographyCovering returns the cells that index a geography. An empty
+// geography has no cells.
+func GeographyCovering(g *geo.Geography) []CellID {
+	if g.IsEmpty() {
+		return nil
+	}
+	return GeographyConfig.Covering(g.BoundingBox())
+}
+
+// GeographyDWithinCovering returns the cells that contain the points within
+// d meters of a geography.
+func GeographyDWithinCovering(g *geo.Geography, d float64) []CellID {
+	if g.IsEmpty() {
+		return nil
+	}
+	b := g.BoundingBox()
+	// The points within an angle a of a point at latitude lat are within a
+	// latitude of a, and a longitude of asin(sin(a) / cos(lat)).
+	a := d / geo.EarthRadius
+	dLat := a * 180 / math.Pi
+	b.MinY, b.MaxY = b.MinY-dLat, b.MaxY+dLat
+	maxLat := math.Max(math.Abs(b.MinY), math.Abs(b.MaxY))
+	if sinLng := math.Sin(a) / math.Cos(maxLat*math.Pi/180); a < math.Pi/2 && maxLat < 90 && sinLng < 1 {
+		dLng := math.Asin(sinLng) * 180 / math.Pi
+		b.MinX, b.MaxX = b.MinX-dLng, b.MaxX+dLng
+	} else {
+		b.MinX, b.MaxX = -180, 180
+	}
+	if b.MinX < -180 || b.MaxX > 180 {
+		// The points across the antimeridian span all longitudes.
+		b.MinX, b.MaxX = -180, 180
+	}
+	b.MinY, b.MaxY = math.Max(b.MinY, -90), math.Min(b.MaxY, 90)
+	return GeographyConfig.Covering(b)
+}
+
+// Span is a range of cell IDs, including Start and End.
+type Span struct {
+	Start, End CellID
+}
+
+// QuerySpans returns the spans of the cells of the indexed shapes that can
+// intersect a shape with the given covering: the cells of the covering along
+// with their ancestors and descendants. The spans are sorted, and are neither
+// overlapping nor adjacent.
+func QuerySpans(covering []CellID) []Span {
+	var spans []Span
+	for _, c := range covering {
+		spans = append(spans, Span{Start: c.RangeMin(), End: c.RangeMax()})
+		for a := c; a != Root; {
+			a = a.Parent()
+			spans = append(spans, Span{Start: a, End: a})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+	var merged []Span
+	for _, s := range spans {
+		if n := len(merged); n > 0 && s.Start <= merged[n-1].End+1 {
+			if s.End > merged[n-1].End {
+				merged[n-1].End = s.End
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
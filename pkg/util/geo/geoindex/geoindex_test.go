@@ -0,0 +1,143 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geoindex
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/geo"
+)
+
+func TestCellID(t *testing.T) {
+	if l := Root.Level(); l != 0 {
+		t.Fatalf("expected root level 0, got %d", l)
+	}
+	if Root.RangeMin() != 1 || Root.RangeMax() != 2*Root-1 {
+		t.Fatalf("unexpected root range [%d, %d]", Root.RangeMin(), Root.RangeMax())
+	}
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 1000; i++ {
+		level := 1 + rng.Intn(MaxLevel)
+		x, y := uint64(rng.Int63n(1<<uint(level))), uint64(rng.Int63n(1<<uint(level)))
+		c := makeCellID(level, x, y)
+		if l := c.Level(); l != level {
+			t.Fatalf("expected level %d, got %d", level, l)
+		}
+		p := c.Parent()
+		if expected := makeCellID(level-1, x/2, y/2); p != expected {
+			t.Fatalf("expected parent %d of %d, got %d", expected, c, p)
+		}
+		if c < p.RangeMin() || c > p.RangeMax() {
+			t.Fatalf("cell %d is not in the range of its parent [%d, %d]", c, p.RangeMin(), p.RangeMax())
+		}
+	}
+}
+
+func TestCovering(t *testing.T) {
+	cfg := Config{MinX: 0, MinY: 0, MaxX: 4, MaxY: 4}
+	testCases := []struct {
+		box      geo.BoundingBox
+		expected string
+	}{
+		// A point is covered by a single cell of the deepest level.
+		{geo.BoundingBox{MinX: 1, MinY: 1, MaxX: 1, MaxY: 1}, "[30]"},
+		// The box spans 2 by 2 cells of level 2, but 3 by 3 cells of level 3.
+		{geo.BoundingBox{MinX: 0.5, MinY: 0.5, MaxX: 1.5, MaxY: 1.5}, "[2 2 2 2]"},
+		// The box spans 2 by 1 cells of level 1.
+		{geo.BoundingBox{MinX: 1, MinY: 0, MaxX: 3, MaxY: 1}, "[1 1]"},
+		{geo.BoundingBox{MinX: 0, MinY: 0, MaxX: 4, MaxY: 4}, "[1 1 1 1]"},
+		// Boxes outside of the space are covered by the root cell.
+		{geo.BoundingBox{MinX: -1, MinY: 0, MaxX: 1, MaxY: 1}, "[0]"},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%+v", tc.box), func(t *testing.T) {
+			cells := cfg.Covering(tc.box)
+			levels := make([]int, len(cells))
+			for i, c := range cells {
+				levels[i] = c.Level()
+			}
+			if s := fmt.Sprint(levels); s != tc.expected {
+				t.Fatalf("expected levels %s, got %s", tc.expected, s)
+			}
+		})
+	}
+}
+
+func TestQuerySpans(t *testing.T) {
+	// The shapes whose coverings share a cell, or have cells that are
+	// ancestors of each other, must be found by the spans of each other.
+	shapes := []string{
+		`POINT(1 1)`,
+		`POINT(1.5 1.5)`,
+		`LINESTRING(0 0, 3 3)`,
+		`POLYGON((-10 -10, 10 -10, 10 10, -10 10, -10 -10))`,
+		`POINT(1e9 1e9)`,
+	}
+	for _, a := range shapes {
+		for _, b := range shapes {
+			ga, err := geo.ParseGeometry(a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gb, err := geo.ParseGeometry(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			intersects, err := ga.Intersects(gb)
+			if err != nil {
+				t.Fatal(err)
+			}
+			spans := QuerySpans(GeometryCovering(ga))
+			found := false
+			for _, c := range GeometryCovering(gb) {
+				for _, s := range spans {
+					if s.Start <= c && c <= s.End {
+						found = true
+					}
+				}
+			}
+			if intersects && !found {
+				t.Errorf("%s intersects %s, but isn't found by its spans", a, b)
+			}
+		}
+	}
+}
+
+func TestGeographyDWithinCovering(t *testing.T) {
+	g, err := geo.ParseGeography(`POINT(0 60)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Points about 100km to the east and to the north of the point must be
+	// within the covering of 110km around it.
+	spans := QuerySpans(GeographyDWithinCovering(g, 110000))
+	for _, wkt := range []string{`POINT(1.79 60)`, `POINT(0 60.9)`, `POINT(0 59.1)`} {
+		p, err := geo.ParseGeography(wkt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := g.Distance(p); d > 110000 {
+			t.Fatalf("%s is %g meters away", wkt, d)
+		}
+		c := GeographyCovering(p)[0]
+		found := false
+		for _, s := range spans {
+			if s.Start <= c && c <= s.End {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s isn't found by the spans", wkt)
+		}
+	}
+}
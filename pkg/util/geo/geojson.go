@@ -0,0 +1,235 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geo
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// GeoJSON returns the GeoJSON representation of the shape, with coordinates
+// rounded to at most maxDecimalDigits decimal digits. For example:
+//
+//	{"type":"Point","coordinates":[1,2]}
+func (s *Shape) GeoJSON(maxDecimalDigits int) string {
+	var b strings.Builder
+	b.WriteString(`{"type":"`)
+	b.WriteString(s.Type.geoJSONName())
+	b.WriteString(`","coordinates":`)
+	f := func(c Coord) {
+		b.WriteByte('[')
+		b.WriteString(formatGeoJSONFloat(c.X, maxDecimalDigits))
+		b.WriteByte(',')
+		b.WriteString(formatGeoJSONFloat(c.Y, maxDecimalDigits))
+		b.WriteByte(']')
+	}
+	ring := func(ring []Coord) {
+		b.WriteByte('[')
+		for i, c := range ring {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			f(c)
+		}
+		b.WriteByte(']')
+	}
+	rings := func(rings [][]Coord) {
+		b.WriteByte('[')
+		for i, r := range rings {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			ring(r)
+		}
+		b.WriteByte(']')
+	}
+	switch {
+	case s.IsEmpty():
+		b.WriteString("[]")
+	case s.Type == Point:
+		f(s.Parts[0][0][0])
+	case s.Type == LineString:
+		ring(s.Parts[0][0])
+	case s.Type == Polygon:
+		rings(s.Parts[0])
+	default:
+		b.WriteByte('[')
+		for i, part := range s.Parts {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			switch s.Type {
+			case MultiPoint:
+				f(part[0][0])
+			case MultiLineString:
+				ring(part[0])
+			default:
+				rings(part)
+			}
+		}
+		b.WriteByte(']')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatGeoJSONFloat(f float64, maxDecimalDigits int) string {
+	p := math.Pow(10, float64(maxDecimalDigits))
+	if r := math.Round(f*p) / p; !math.IsInf(r, 0) && !math.IsNaN(r) {
+		f = r
+	}
+	return formatFloat(f)
+}
+
+// geoJSONShape is the JSON structure of a GeoJSON geometry object. Features
+// and geometry collections are not supported.
+type geoJSONShape struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// GeometryFromGeoJSON parses a geometry from its GeoJSON representation. The
+// SRID of the geometry is DefaultGeographySRID.
+func GeometryFromGeoJSON(b []byte) (*Geometry, error) {
+	shape, err := parseGeoJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	return MakeGeometry(DefaultGeographySRID, shape)
+}
+
+// GeographyFromGeoJSON parses a geography from its GeoJSON representation.
+func GeographyFromGeoJSON(b []byte) (*Geography, error) {
+	shape, err := parseGeoJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	return MakeGeography(DefaultGeographySRID, shape)
+}
+
+func parseGeoJSON(b []byte) (Shape, error) {
+	var g geoJSONShape
+	if err := json.Unmarshal(b, &g); err != nil {
+		return Shape{}, pgerror.Newf(pgcode.InvalidParameterValue, "invalid GeoJSON: %v", err)
+	}
+	var shape Shape
+	for t := Point; t <= MultiPolygon; t++ {
+		if g.Type == t.geoJSONName() {
+			shape.Type = t
+		}
+	}
+	if shape.Type == 0 {
+		return Shape{}, pgerror.Newf(pgcode.InvalidParameterValue,
+			"unsupported GeoJSON type %q", g.Type)
+	}
+	if len(g.Coordinates) == 0 {
+		return Shape{}, pgerror.New(pgcode.InvalidParameterValue, "GeoJSON has no coordinates")
+	}
+
+	var err error
+	switch shape.Type {
+	case Point:
+		var c []float64
+		if err = json.Unmarshal(g.Coordinates, &c); err == nil && len(c) > 0 {
+			var coord Coord
+			if coord, err = geoJSONCoord(c); err == nil {
+				shape.Parts = [][][]Coord{{{coord}}}
+			}
+		}
+	case LineString:
+		var cs [][]float64
+		if err = json.Unmarshal(g.Coordinates, &cs); err == nil && len(cs) > 0 {
+			var ring []Coord
+			if ring, err = geoJSONRing(cs); err == nil {
+				shape.Parts = [][][]Coord{{ring}}
+			}
+		}
+	case Polygon:
+		var rs [][][]float64
+		if err = json.Unmarshal(g.Coordinates, &rs); err == nil && len(rs) > 0 {
+			var rings [][]Coord
+			if rings, err = geoJSONRings(rs); err == nil {
+				shape.Parts = [][][]Coord{rings}
+			}
+		}
+	case MultiPoint:
+		var cs [][]float64
+		if err = json.Unmarshal(g.Coordinates, &cs); err == nil {
+			for _, c := range cs {
+				var coord Coord
+				if coord, err = geoJSONCoord(c); err != nil {
+					break
+				}
+				shape.Parts = append(shape.Parts, [][]Coord{{coord}})
+			}
+		}
+	case MultiLineString:
+		var rs [][][]float64
+		if err = json.Unmarshal(g.Coordinates, &rs); err == nil {
+			for _, r := range rs {
+				var ring []Coord
+				if ring, err = geoJSONRing(r); err != nil {
+					break
+				}
+				shape.Parts = append(shape.Parts, [][]Coord{ring})
+			}
+		}
+	case MultiPolygon:
+		var ps [][][][]float64
+		if err = json.Unmarshal(g.Coordinates, &ps); err == nil {
+			for _, p := range ps {
+				var rings [][]Coord
+				if rings, err = geoJSONRings(p); err != nil {
+					break
+				}
+				shape.Parts = append(shape.Parts, rings)
+			}
+		}
+	}
+	if err != nil {
+		return Shape{}, pgerror.Newf(pgcode.InvalidParameterValue, "invalid GeoJSON coordinates: %v", err)
+	}
+	return shape, nil
+}
+
+func geoJSONCoord(c []float64) (Coord, error) {
+	if len(c) != 2 {
+		return Coord{}, pgerror.New(pgcode.FeatureNotSupported,
+			"only coordinates with two dimensions are supported")
+	}
+	return Coord{X: c[0], Y: c[1]}, nil
+}
+
+func geoJSONRing(cs [][]float64) ([]Coord, error) {
+	ring := make([]Coord, len(cs))
+	for i, c := range cs {
+		var err error
+		if ring[i], err = geoJSONCoord(c); err != nil {
+			return nil, err
+		}
+	}
+	return ring, nil
+}
+
+func geoJSONRings(rs [][][]float64) ([][]Coord, error) {
+	rings := make([][]Coord, len(rs))
+	for i, r := range rs {
+		var err error
+		if rings[i], err = geoJSONRing(r); err != nil {
+			return nil, err
+		}
+	}
+	return rings, nil
+}
@@ -0,0 +1,263 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geo
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// plane is the space of geometries, where edges are straight lines.
+type plane struct{}
+
+var _ space = plane{}
+
+func (plane) edgesIntersect(a, b, c, d Coord) bool {
+	o1, o2 := orientation(a, b, c), orientation(a, b, d)
+	o3, o4 := orientation(c, d, a), orientation(c, d, b)
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true
+	}
+	// Otherwise the edges can only intersect if an endpoint of one of them is
+	// on the other.
+	return (o1 == 0 && inBox(c, a, b)) || (o2 == 0 && inBox(d, a, b)) ||
+		(o3 == 0 && inBox(a, c, d)) || (o4 == 0 && inBox(b, c, d))
+}
+
+func (sp plane) edgeDistance(a, b, c, d Coord) float64 {
+	if sp.edgesIntersect(a, b, c, d) {
+		return 0
+	}
+	return math.Min(
+		math.Min(pointEdgeDistance(a, c, d), pointEdgeDistance(b, c, d)),
+		math.Min(pointEdgeDistance(c, a, b), pointEdgeDistance(d, a, b)),
+	)
+}
+
+// pointEdgeDistance returns the distance between the coordinate p and the
+// edge ab.
+func pointEdgeDistance(p, a, b Coord) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	if dx != 0 || dy != 0 {
+		t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / (dx*dx + dy*dy)
+		t = math.Max(0, math.Min(1, t))
+		a = Coord{X: a.X + t*dx, Y: a.Y + t*dy}
+	}
+	return math.Hypot(p.X-a.X, p.Y-a.Y)
+}
+
+// checkSRIDs returns an error if the two geometries have different SRIDs.
+func checkSRIDs(a, b *Geometry) error {
+	if a.SRID != b.SRID {
+		return errMixedSRIDs(a.SRID, b.SRID)
+	}
+	return nil
+}
+
+// Distance returns the shortest distance between the two geometries, in the
+// units of their coordinate system. It returns +Inf if either of them is
+// empty.
+func (g *Geometry) Distance(o *Geometry) (float64, error) {
+	if err := checkSRIDs(g, o); err != nil {
+		return 0, err
+	}
+	return shapesDistance(plane{}, &g.Shape, &o.Shape), nil
+}
+
+// DWithin returns true if the distance between the two geometries is at most
+// d.
+func (g *Geometry) DWithin(o *Geometry, d float64) (bool, error) {
+	if d < 0 {
+		return false, pgerror.New(pgcode.InvalidParameterValue, "tolerance cannot be negative")
+	}
+	dist, err := g.Distance(o)
+	if err != nil {
+		return false, err
+	}
+	return dist <= d, nil
+}
+
+// Intersects returns true if the two geometries share a point.
+func (g *Geometry) Intersects(o *Geometry) (bool, error) {
+	if err := checkSRIDs(g, o); err != nil {
+		return false, err
+	}
+	return shapesIntersect(plane{}, &g.Shape, &o.Shape), nil
+}
+
+// Covers returns true if no point of the geometry o is outside of the
+// geometry g. Empty geometries neither cover nor are covered by any other
+// geometry.
+func (g *Geometry) Covers(o *Geometry) (bool, error) {
+	if err := checkSRIDs(g, o); err != nil {
+		return false, err
+	}
+	return shapeCovers(&g.Shape, &o.Shape), nil
+}
+
+// Contains returns true if the geometry g covers the geometry o, and the
+// interiors of the two geometries intersect. Unlike Covers, it is false if o
+// lies entirely on the boundary of g, for example if o is a point on the
+// boundary of a polygon.
+func (g *Geometry) Contains(o *Geometry) (bool, error) {
+	if err := checkSRIDs(g, o); err != nil {
+		return false, err
+	}
+	if !shapeCovers(&g.Shape, &o.Shape) {
+		return false, nil
+	}
+	if o.Type.simple() == Polygon {
+		// The interior of a covered polygon is in the interior of the
+		// polygon that covers it.
+		return true, nil
+	}
+	parts := g.parts()
+	found := false
+	o.forEachSample(func(c Coord) bool {
+		for i := range parts {
+			if parts[i].interiorContains(c) {
+				found = true
+				return true
+			}
+		}
+		return false
+	})
+	return found, nil
+}
+
+// shapeCovers returns true if every simple shape of b is covered by one of the
+// simple shapes of a.
+func shapeCovers(a, b *Shape) bool {
+	if a.IsEmpty() || b.IsEmpty() {
+		return false
+	}
+	aParts := a.parts()
+	for _, q := range b.parts() {
+		covered := false
+		for i := range aParts {
+			if aParts[i].covers(&q) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// forEachSample calls fn on the vertices of the shape and the midpoints of
+// its edges, which are the coordinates checked to decide whether the shape is
+// covered by another one. It stops as soon as fn returns true.
+func (s *Shape) forEachSample(fn func(Coord) bool) {
+	for _, p := range s.parts() {
+		if p.forEachEdge(func(a, b Coord) bool {
+			return fn(a) || fn(b) || fn(midpoint(a, b))
+		}) {
+			return
+		}
+	}
+}
+
+func midpoint(a, b Coord) Coord {
+	return Coord{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+}
+
+// covers returns true if no point of the simple shape q is outside of the
+// simple shape p. Lines are considered to be covered if their vertices and
+// the midpoints of their edges are covered.
+func (p *part) covers(q *part) bool {
+	switch p.typ {
+	case Point:
+		c := p.rings[0][0]
+		return !q.forEachEdge(func(a, b Coord) bool {
+			return a != c || b != c
+		})
+
+	case LineString:
+		if q.typ == Polygon {
+			return false
+		}
+		line := p.rings[0]
+		return !q.forEachEdge(func(a, b Coord) bool {
+			for _, c := range []Coord{a, b, midpoint(a, b)} {
+				if !onLine(c, line) {
+					return true
+				}
+			}
+			return false
+		})
+
+	default:
+		// Every edge of q must be inside of the polygon, and must not cross
+		// its boundary.
+		if q.forEachEdge(func(a, b Coord) bool {
+			if locateInPolygon(a, p.rings) == outside || locateInPolygon(b, p.rings) == outside ||
+				locateInPolygon(midpoint(a, b), p.rings) == outside {
+				return true
+			}
+			return p.forEachEdge(func(c, d Coord) bool {
+				return properlyCross(a, b, c, d)
+			})
+		}) {
+			return false
+		}
+		if q.typ == Polygon {
+			// The polygon q must not contain a hole of p.
+			for _, ring := range p.rings[1:] {
+				for _, c := range ring {
+					if locateInPolygon(c, q.rings) == inside {
+						return false
+					}
+				}
+			}
+		}
+		return true
+	}
+}
+
+// interiorContains returns true if the coordinate is in the interior of the
+// simple shape: the point itself, a point of a line other than the endpoints
+// of an open line, or a point of a polygon not on its boundary.
+func (p *part) interiorContains(c Coord) bool {
+	switch p.typ {
+	case Point:
+		return c == p.rings[0][0]
+	case LineString:
+		line := p.rings[0]
+		if line[0] != line[len(line)-1] && (c == line[0] || c == line[len(line)-1]) {
+			return false
+		}
+		return onLine(c, line)
+	default:
+		return locateInPolygon(c, p.rings) == inside
+	}
+}
+
+// onLine returns true if the coordinate is on one of the edges of the line.
+func onLine(c Coord, line []Coord) bool {
+	for i := 1; i < len(line); i++ {
+		if orientation(line[i-1], line[i], c) == 0 && inBox(c, line[i-1], line[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// properlyCross returns true if the edges ab and cd cross at a single point
+// that is not an endpoint of either of them.
+func properlyCross(a, b, c, d Coord) bool {
+	return orientation(a, b, c)*orientation(a, b, d) < 0 &&
+		orientation(c, d, a)*orientation(c, d, b) < 0
+}
@@ -0,0 +1,190 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geo
+
+import "math"
+
+// space abstracts the measurements of edges in a coordinate space, so that
+// the predicates on shapes can be shared between geometries (on a plane) and
+// geographies (on a sphere). An edge whose endpoints are equal is a point.
+type space interface {
+	// edgesIntersect returns true if the edges ab and cd share a point.
+	edgesIntersect(a, b, c, d Coord) bool
+	// edgeDistance returns the shortest distance between the edges ab and cd.
+	edgeDistance(a, b, c, d Coord) float64
+}
+
+// part is a simple shape: a point, a linestring or a polygon.
+type part struct {
+	typ   ShapeType
+	rings [][]Coord
+}
+
+// parts returns the simple shapes of the shape.
+func (s *Shape) parts() []part {
+	parts := make([]part, len(s.Parts))
+	for i := range s.Parts {
+		parts[i] = part{typ: s.Type.simple(), rings: s.Parts[i]}
+	}
+	return parts
+}
+
+// forEachEdge calls fn on each edge of the rings of the part, and stops as
+// soon as fn returns true. The single coordinate of a point is an edge whose
+// endpoints are equal. Returns true if fn returned true.
+func (p *part) forEachEdge(fn func(a, b Coord) bool) bool {
+	for _, ring := range p.rings {
+		if len(ring) == 1 {
+			if fn(ring[0], ring[0]) {
+				return true
+			}
+			continue
+		}
+		for i := 1; i < len(ring); i++ {
+			if fn(ring[i-1], ring[i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// location is the position of a coordinate relative to a polygon.
+type location int
+
+const (
+	outside location = iota
+	onBoundary
+	inside
+)
+
+// locateInRing returns the location of a coordinate relative to the area
+// enclosed by a closed ring. The edges of the ring are straight lines in the
+// coordinate space.
+func locateInRing(c Coord, ring []Coord) location {
+	in := false
+	for i := 1; i < len(ring); i++ {
+		a, b := ring[i-1], ring[i]
+		if orientation(a, b, c) == 0 && inBox(c, a, b) {
+			return onBoundary
+		}
+		if (a.Y > c.Y) != (b.Y > c.Y) {
+			x := a.X + (c.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+			if c.X < x {
+				in = !in
+			}
+		}
+	}
+	if in {
+		return inside
+	}
+	return outside
+}
+
+// locateInPolygon returns the location of a coordinate relative to a polygon,
+// given its exterior ring followed by its holes.
+func locateInPolygon(c Coord, rings [][]Coord) location {
+	loc := locateInRing(c, rings[0])
+	if loc != inside {
+		return loc
+	}
+	for _, hole := range rings[1:] {
+		switch locateInRing(c, hole) {
+		case inside:
+			return outside
+		case onBoundary:
+			return onBoundary
+		}
+	}
+	return inside
+}
+
+// orientation returns 1 if the coordinate c is to the left of the directed
+// line ab, -1 if it is to the right, and 0 if the three are collinear.
+func orientation(a, b, c Coord) int {
+	v := (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// inBox returns true if the coordinate c is in the rectangle with opposite
+// corners a and b.
+func inBox(c, a, b Coord) bool {
+	return math.Min(a.X, b.X) <= c.X && c.X <= math.Max(a.X, b.X) &&
+		math.Min(a.Y, b.Y) <= c.Y && c.Y <= math.Max(a.Y, b.Y)
+}
+
+// partsIntersect returns true if the two simple shapes share a point. This is
+// the case if their boundaries intersect, or if one of them is inside of a
+// polygon.
+func partsIntersect(sp space, p, q *part) bool {
+	if p.forEachEdge(func(a, b Coord) bool {
+		return q.forEachEdge(func(c, d Coord) bool {
+			return sp.edgesIntersect(a, b, c, d)
+		})
+	}) {
+		return true
+	}
+	if p.typ == Polygon && locateInPolygon(q.rings[0][0], p.rings) != outside {
+		return true
+	}
+	return q.typ == Polygon && locateInPolygon(p.rings[0][0], q.rings) != outside
+}
+
+// partsDistance returns the shortest distance between the two simple shapes.
+func partsDistance(sp space, p, q *part) float64 {
+	if partsIntersect(sp, p, q) {
+		return 0
+	}
+	// If the shapes don't intersect, the closest points are on their edges.
+	d := math.Inf(1)
+	p.forEachEdge(func(a, b Coord) bool {
+		q.forEachEdge(func(c, e Coord) bool {
+			d = math.Min(d, sp.edgeDistance(a, b, c, e))
+			return false
+		})
+		return d == 0
+	})
+	return d
+}
+
+// shapesIntersect returns true if the two shapes share a point.
+func shapesIntersect(sp space, a, b *Shape) bool {
+	bParts := b.parts()
+	for _, p := range a.parts() {
+		for i := range bParts {
+			if partsIntersect(sp, &p, &bParts[i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// shapesDistance returns the shortest distance between the two shapes, or
+// +Inf if either of them is empty.
+func shapesDistance(sp space, a, b *Shape) float64 {
+	d := math.Inf(1)
+	bParts := b.parts()
+	for _, p := range a.parts() {
+		for i := range bParts {
+			if d = math.Min(d, partsDistance(sp, &p, &bParts[i])); d == 0 {
+				return 0
+			}
+		}
+	}
+	return d
+}
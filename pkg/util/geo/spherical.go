@@ -0,0 +1,200 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geo
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// EarthRadius is the mean radius of the Earth in meters, which is the radius
+// of the sphere on which the measurements of geographies are made.
+const EarthRadius = 6371008.8
+
+// sphereEpsilon is the angle, in radians, under which two points of the
+// sphere are considered to be equal. It is about 6 micrometers on the surface
+// of the Earth.
+const sphereEpsilon = 1e-12
+
+// sphere is the space of geographies, where edges are the shortest arcs of
+// great circles between their endpoints, and distances are angles in
+// radians.
+//
+// Whether a coordinate is inside of a polygon is still decided by treating the
+// edges of the polygon as straight lines in longitude and latitude, which is
+// accurate for polygons that are small relative to the Earth and that don't
+// cross the antimeridian.
+type sphere struct{}
+
+var _ space = sphere{}
+
+// point3 is a point of the unit sphere in cartesian coordinates.
+type point3 [3]float64
+
+func toPoint3(c Coord) point3 {
+	lng, lat := c.X*math.Pi/180, c.Y*math.Pi/180
+	return point3{math.Cos(lat) * math.Cos(lng), math.Cos(lat) * math.Sin(lng), math.Sin(lat)}
+}
+
+func (p point3) dot(q point3) float64 {
+	return p[0]*q[0] + p[1]*q[1] + p[2]*q[2]
+}
+
+func (p point3) cross(q point3) point3 {
+	return point3{p[1]*q[2] - p[2]*q[1], p[2]*q[0] - p[0]*q[2], p[0]*q[1] - p[1]*q[0]}
+}
+
+func (p point3) scale(f float64) point3 {
+	return point3{p[0] * f, p[1] * f, p[2] * f}
+}
+
+func (p point3) norm() float64 {
+	return math.Sqrt(p.dot(p))
+}
+
+// angle returns the angle between the two points, which is their distance on
+// the unit sphere.
+func angle(p, q point3) float64 {
+	return math.Atan2(p.cross(q).norm(), p.dot(q))
+}
+
+// onArc returns true if the point p, which must be on the great circle with
+// normal n of the arc ab, is between a and b.
+func onArc(p, a, b, n point3) bool {
+	return a.cross(p).dot(n) >= 0 && p.cross(b).dot(n) >= 0
+}
+
+// pointArcDistance returns the angle between the point p and the closest
+// point of the arc ab.
+func pointArcDistance(p, a, b point3) float64 {
+	n := a.cross(b)
+	if l := n.norm(); l > sphereEpsilon {
+		n = n.scale(1 / l)
+		if onArc(p, a, b, n) {
+			// The closest point is the projection of p on the great circle.
+			return math.Abs(math.Asin(math.Max(-1, math.Min(1, p.dot(n)))))
+		}
+	}
+	return math.Min(angle(p, a), angle(p, b))
+}
+
+func (sphere) edgesIntersect(a, b, c, d Coord) bool {
+	pa, pb, pc, pd := toPoint3(a), toPoint3(b), toPoint3(c), toPoint3(d)
+	if pointArcDistance(pa, pc, pd) <= sphereEpsilon || pointArcDistance(pb, pc, pd) <= sphereEpsilon ||
+		pointArcDistance(pc, pa, pb) <= sphereEpsilon || pointArcDistance(pd, pa, pb) <= sphereEpsilon {
+		return true
+	}
+	// Two arcs that don't share an endpoint can only cross at one of the two
+	// intersections of their great circles.
+	n1, n2 := pa.cross(pb), pc.cross(pd)
+	t := n1.cross(n2)
+	l := t.norm()
+	if l <= sphereEpsilon {
+		// The arcs are on the same great circle, or one of them is a point.
+		return false
+	}
+	t = t.scale(1 / l)
+	for _, p := range []point3{t, t.scale(-1)} {
+		if onArc(p, pa, pb, n1) && onArc(p, pc, pd, n2) {
+			return true
+		}
+	}
+	return false
+}
+
+func (sp sphere) edgeDistance(a, b, c, d Coord) float64 {
+	if sp.edgesIntersect(a, b, c, d) {
+		return 0
+	}
+	pa, pb, pc, pd := toPoint3(a), toPoint3(b), toPoint3(c), toPoint3(d)
+	return math.Min(
+		math.Min(pointArcDistance(pa, pc, pd), pointArcDistance(pb, pc, pd)),
+		math.Min(pointArcDistance(pc, pa, pb), pointArcDistance(pd, pa, pb)),
+	)
+}
+
+// Distance returns the shortest distance in meters between the two
+// geographies. It returns +Inf if either of them is empty.
+func (g *Geography) Distance(o *Geography) float64 {
+	return shapesDistance(sphere{}, &g.Shape, &o.Shape) * EarthRadius
+}
+
+// DWithin returns true if the distance in meters between the two geographies
+// is at most d.
+func (g *Geography) DWithin(o *Geography, d float64) (bool, error) {
+	if d < 0 {
+		return false, pgerror.New(pgcode.InvalidParameterValue, "tolerance cannot be negative")
+	}
+	return g.Distance(o) <= d, nil
+}
+
+// Intersects returns true if the two geographies share a point.
+func (g *Geography) Intersects(o *Geography) bool {
+	return shapesIntersect(sphere{}, &g.Shape, &o.Shape)
+}
+
+// BoundingBox returns the smallest rectangle in longitude and latitude that
+// contains the geography. Unlike the bounding box of its coordinates, it
+// contains the points of its edges that are closer to the poles than their
+// endpoints. If an edge crosses the antimeridian, the rectangle spans all
+// longitudes. The geography must not be empty.
+func (g *Geography) BoundingBox() BoundingBox {
+	box := g.Shape.BoundingBox()
+	for _, p := range g.parts() {
+		p.forEachEdge(func(a, b Coord) bool {
+			if a == b {
+				return false
+			}
+			if math.Abs(a.X-b.X) > 180 {
+				box.MinX, box.MaxX = -180, 180
+			}
+			lo, hi := arcLatitudeRange(toPoint3(a), toPoint3(b))
+			box.MinY = math.Min(box.MinY, lo)
+			box.MaxY = math.Max(box.MaxY, hi)
+			return false
+		})
+	}
+	return box
+}
+
+// arcLatitudeRange returns the minimum and maximum latitude in degrees of the
+// points of the arc ab.
+func arcLatitudeRange(a, b point3) (lo, hi float64) {
+	lo = math.Min(latitude(a), latitude(b))
+	hi = math.Max(latitude(a), latitude(b))
+	n := a.cross(b)
+	l := n.norm()
+	if l <= sphereEpsilon {
+		return lo, hi
+	}
+	n = n.scale(1 / l)
+	// The northernmost point of the great circle is the projection of the
+	// north pole on its plane, and the southernmost point is its opposite.
+	north := point3{0, 0, 1}
+	p := north.cross(n).cross(n).scale(-1)
+	if pl := p.norm(); pl > sphereEpsilon {
+		p = p.scale(1 / pl)
+		if onArc(p, a, b, n) {
+			hi = math.Max(hi, latitude(p))
+		}
+		if q := p.scale(-1); onArc(q, a, b, n) {
+			lo = math.Min(lo, latitude(q))
+		}
+	}
+	return lo, hi
+}
+
+// latitude returns the latitude in degrees of a point of the unit sphere.
+func latitude(p point3) float64 {
+	return math.Asin(math.Max(-1, math.Min(1, p[2]))) * 180 / math.Pi
+}
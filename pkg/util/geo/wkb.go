@@ -0,0 +1,306 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geo
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// The flags of the type code of an EWKB shape.
+const (
+	ewkbZFlag    = 0x80000000
+	ewkbMFlag    = 0x40000000
+	ewkbSRIDFlag = 0x20000000
+)
+
+// wkbEmptyCoord is the coordinate of an empty point, which is the quiet NaN
+// used by PostGIS.
+var wkbEmptyCoord = math.Float64frombits(0x7FF8000000000000)
+
+// The byte orders of WKB.
+const (
+	wkbBigEndian    = 0
+	wkbLittleEndian = 1
+)
+
+// WKB returns the well-known binary representation of the shape, in little
+// endian byte order.
+func (s *Shape) WKB() []byte {
+	return appendEWKB(nil, 0 /* srid */, s)
+}
+
+// EWKB returns the extended well-known binary representation of the
+// geometry, which includes the SRID if it is known. It is used as the storage
+// format of geometries.
+func (g *Geometry) EWKB() []byte {
+	return appendEWKB(nil, g.SRID, &g.Shape)
+}
+
+// EWKB returns the extended well-known binary representation of the
+// geography, which includes the SRID. It is used as the storage format of
+// geographies.
+func (g *Geography) EWKB() []byte {
+	return appendEWKB(nil, g.SRID, &g.Shape)
+}
+
+// GeometryFromEWKB decodes a geometry from its (E)WKB representation.
+func GeometryFromEWKB(b []byte) (*Geometry, error) {
+	srid, shape, err := decodeEWKB(b)
+	if err != nil {
+		return nil, err
+	}
+	return MakeGeometry(srid, shape)
+}
+
+// GeographyFromEWKB decodes a geography from its (E)WKB representation.
+func GeographyFromEWKB(b []byte) (*Geography, error) {
+	srid, shape, err := decodeEWKB(b)
+	if err != nil {
+		return nil, err
+	}
+	return MakeGeography(srid, shape)
+}
+
+func appendEWKB(b []byte, srid int32, s *Shape) []byte {
+	typ := uint32(s.Type)
+	if srid != 0 {
+		typ |= ewkbSRIDFlag
+	}
+	b = append(b, wkbLittleEndian)
+	b = appendUint32(b, typ)
+	if srid != 0 {
+		b = appendUint32(b, uint32(srid))
+	}
+	switch s.Type {
+	case Point:
+		if s.IsEmpty() {
+			// An empty point is represented with NaN coordinates.
+			return appendCoord(b, Coord{X: wkbEmptyCoord, Y: wkbEmptyCoord})
+		}
+		return appendCoord(b, s.Parts[0][0][0])
+	case LineString:
+		if s.IsEmpty() {
+			return appendUint32(b, 0)
+		}
+		return appendRing(b, s.Parts[0][0])
+	case Polygon:
+		if s.IsEmpty() {
+			return appendUint32(b, 0)
+		}
+		return appendRings(b, s.Parts[0])
+	}
+	b = appendUint32(b, uint32(len(s.Parts)))
+	for _, part := range s.Parts {
+		sub := Shape{Type: s.Type.simple(), Parts: [][][]Coord{part}}
+		b = appendEWKB(b, 0 /* srid */, &sub)
+	}
+	return b
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendCoord(b []byte, c Coord) []byte {
+	var buf [16]byte
+	binary.LittleEndian.PutUint64(buf[:8], math.Float64bits(c.X))
+	binary.LittleEndian.PutUint64(buf[8:], math.Float64bits(c.Y))
+	return append(b, buf[:]...)
+}
+
+func appendRing(b []byte, ring []Coord) []byte {
+	b = appendUint32(b, uint32(len(ring)))
+	for _, c := range ring {
+		b = appendCoord(b, c)
+	}
+	return b
+}
+
+func appendRings(b []byte, rings [][]Coord) []byte {
+	b = appendUint32(b, uint32(len(rings)))
+	for _, ring := range rings {
+		b = appendRing(b, ring)
+	}
+	return b
+}
+
+// decodeEWKB decodes a shape and its SRID from its WKB or EWKB
+// representation.
+func decodeEWKB(b []byte) (int32, Shape, error) {
+	d := wkbDecoder{buf: b}
+	srid, shape, err := d.decodeShape(true /* top */)
+	if err != nil {
+		return 0, Shape{}, err
+	}
+	if len(d.buf) != 0 {
+		return 0, Shape{}, pgerror.New(pgcode.InvalidParameterValue, "unexpected bytes after WKB")
+	}
+	return srid, shape, nil
+}
+
+type wkbDecoder struct {
+	buf   []byte
+	order binary.ByteOrder
+}
+
+var errWKBTooShort = pgerror.New(pgcode.InvalidParameterValue, "WKB is too short")
+
+func (d *wkbDecoder) uint32() (uint32, error) {
+	if len(d.buf) < 4 {
+		return 0, errWKBTooShort
+	}
+	v := d.order.Uint32(d.buf)
+	d.buf = d.buf[4:]
+	return v, nil
+}
+
+// count decodes a number of elements, each of which takes at least minSize
+// bytes.
+func (d *wkbDecoder) count(minSize int) (int, error) {
+	n, err := d.uint32()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(n)*uint64(minSize) > uint64(len(d.buf)) {
+		return 0, errWKBTooShort
+	}
+	return int(n), nil
+}
+
+func (d *wkbDecoder) coord() (Coord, error) {
+	if len(d.buf) < 16 {
+		return Coord{}, errWKBTooShort
+	}
+	c := Coord{
+		X: math.Float64frombits(d.order.Uint64(d.buf)),
+		Y: math.Float64frombits(d.order.Uint64(d.buf[8:])),
+	}
+	d.buf = d.buf[16:]
+	return c, nil
+}
+
+func (d *wkbDecoder) ring() ([]Coord, error) {
+	n, err := d.count(16)
+	if err != nil {
+		return nil, err
+	}
+	ring := make([]Coord, n)
+	for i := range ring {
+		if ring[i], err = d.coord(); err != nil {
+			return nil, err
+		}
+	}
+	return ring, nil
+}
+
+func (d *wkbDecoder) rings() ([][]Coord, error) {
+	n, err := d.count(4)
+	if err != nil {
+		return nil, err
+	}
+	rings := make([][]Coord, n)
+	for i := range rings {
+		if rings[i], err = d.ring(); err != nil {
+			return nil, err
+		}
+	}
+	return rings, nil
+}
+
+// decodeShape decodes a shape along with its byte order and type. Only the
+// top-level shape may have an SRID.
+func (d *wkbDecoder) decodeShape(top bool) (int32, Shape, error) {
+	if len(d.buf) == 0 {
+		return 0, Shape{}, errWKBTooShort
+	}
+	switch d.buf[0] {
+	case wkbBigEndian:
+		d.order = binary.BigEndian
+	case wkbLittleEndian:
+		d.order = binary.LittleEndian
+	default:
+		return 0, Shape{}, pgerror.Newf(pgcode.InvalidParameterValue, "invalid WKB byte order %d", d.buf[0])
+	}
+	d.buf = d.buf[1:]
+	typ, err := d.uint32()
+	if err != nil {
+		return 0, Shape{}, err
+	}
+	if typ&(ewkbZFlag|ewkbMFlag) != 0 || typ&0xffff > 1000 {
+		return 0, Shape{}, pgerror.New(pgcode.FeatureNotSupported,
+			"only coordinates with two dimensions are supported")
+	}
+	var srid int32
+	if typ&ewkbSRIDFlag != 0 {
+		if !top {
+			return 0, Shape{}, pgerror.New(pgcode.InvalidParameterValue, "nested WKB shape has an SRID")
+		}
+		v, err := d.uint32()
+		if err != nil {
+			return 0, Shape{}, err
+		}
+		srid = int32(v)
+	}
+	shape := Shape{Type: ShapeType(typ &^ ewkbSRIDFlag)}
+	switch shape.Type {
+	case Point:
+		c, err := d.coord()
+		if err != nil {
+			return 0, Shape{}, err
+		}
+		if !math.IsNaN(c.X) || !math.IsNaN(c.Y) {
+			shape.Parts = [][][]Coord{{{c}}}
+		}
+	case LineString:
+		ring, err := d.ring()
+		if err != nil {
+			return 0, Shape{}, err
+		}
+		if len(ring) > 0 {
+			shape.Parts = [][][]Coord{{ring}}
+		}
+	case Polygon:
+		rings, err := d.rings()
+		if err != nil {
+			return 0, Shape{}, err
+		}
+		if len(rings) > 0 {
+			shape.Parts = [][][]Coord{rings}
+		}
+	case MultiPoint, MultiLineString, MultiPolygon:
+		n, err := d.count(9 /* byte order, type and at least 4 more bytes */)
+		if err != nil {
+			return 0, Shape{}, err
+		}
+		for i := 0; i < n; i++ {
+			_, sub, err := d.decodeShape(false /* top */)
+			if err != nil {
+				return 0, Shape{}, err
+			}
+			if sub.Type != shape.Type.simple() {
+				return 0, Shape{}, pgerror.Newf(pgcode.InvalidParameterValue,
+					"%s cannot contain %s", shape.Type, sub.Type)
+			}
+			shape.Parts = append(shape.Parts, sub.Parts...)
+		}
+	case 7:
+		return 0, Shape{}, pgerror.New(pgcode.FeatureNotSupported, "GEOMETRYCOLLECTION is not supported")
+	default:
+		return 0, Shape{}, pgerror.Newf(pgcode.InvalidParameterValue, "unsupported WKB type %d", typ)
+	}
+	return srid, shape, nil
+}
@@ -0,0 +1,382 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geo
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// WKT returns the well-known text representation of the shape, for example:
+//
+//	POLYGON((0 0,1 0,1 1,0 0))
+func (s *Shape) WKT() string {
+	var b strings.Builder
+	s.appendWKT(&b)
+	return b.String()
+}
+
+// EWKT returns the extended well-known text representation of the geometry,
+// which prefixes the WKT with the SRID if it is known.
+func (g *Geometry) EWKT() string {
+	return ewkt(g.SRID, &g.Shape)
+}
+
+// EWKT returns the extended well-known text representation of the
+// geography, which prefixes the WKT with the SRID.
+func (g *Geography) EWKT() string {
+	return ewkt(g.SRID, &g.Shape)
+}
+
+func ewkt(srid int32, s *Shape) string {
+	var b strings.Builder
+	if srid != 0 {
+		b.WriteString("SRID=")
+		b.WriteString(strconv.Itoa(int(srid)))
+		b.WriteByte(';')
+	}
+	s.appendWKT(&b)
+	return b.String()
+}
+
+func (s *Shape) appendWKT(b *strings.Builder) {
+	b.WriteString(s.Type.String())
+	if s.IsEmpty() {
+		b.WriteString(" EMPTY")
+		return
+	}
+	switch s.Type {
+	case Point, LineString:
+		appendWKTRing(b, s.Parts[0][0])
+	case Polygon:
+		appendWKTRings(b, s.Parts[0])
+	case MultiPoint:
+		b.WriteByte('(')
+		for i, part := range s.Parts {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			appendWKTCoord(b, part[0][0])
+		}
+		b.WriteByte(')')
+	case MultiLineString:
+		b.WriteByte('(')
+		for i, part := range s.Parts {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			appendWKTRing(b, part[0])
+		}
+		b.WriteByte(')')
+	case MultiPolygon:
+		b.WriteByte('(')
+		for i, part := range s.Parts {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			appendWKTRings(b, part)
+		}
+		b.WriteByte(')')
+	}
+}
+
+func appendWKTRings(b *strings.Builder, rings [][]Coord) {
+	b.WriteByte('(')
+	for i, ring := range rings {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		appendWKTRing(b, ring)
+	}
+	b.WriteByte(')')
+}
+
+func appendWKTRing(b *strings.Builder, ring []Coord) {
+	b.WriteByte('(')
+	for i, c := range ring {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		appendWKTCoord(b, c)
+	}
+	b.WriteByte(')')
+}
+
+func appendWKTCoord(b *strings.Builder, c Coord) {
+	b.WriteString(formatFloat(c.X))
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(c.Y))
+}
+
+// formatFloat formats a coordinate with at most 15 significant digits, like
+// PostGIS, so that values such as 0.1+0.2 are printed as 0.3. The exponent
+// notation is never used.
+func formatFloat(f float64) string {
+	f, _ = strconv.ParseFloat(strconv.FormatFloat(f, 'g', 15, 64), 64)
+	if f == 0 {
+		// Normalize -0.
+		f = 0
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// parseEWKT parses the extended well-known text representation of a shape,
+// which is the WKT optionally prefixed with "SRID=<srid>;".
+func parseEWKT(s string) (int32, Shape, error) {
+	var srid int32
+	if len(s) >= 5 && strings.EqualFold(s[:5], "SRID=") {
+		semi := strings.IndexByte(s, ';')
+		if semi < 0 {
+			return 0, Shape{}, pgerror.Newf(pgcode.InvalidParameterValue, "missing ; after SRID in %q", s)
+		}
+		v, err := strconv.ParseInt(strings.TrimSpace(s[5:semi]), 10, 32)
+		if err != nil {
+			return 0, Shape{}, pgerror.Newf(pgcode.InvalidParameterValue, "invalid SRID in %q", s)
+		}
+		srid = int32(v)
+		s = s[semi+1:]
+	}
+	shape, err := parseWKT(s)
+	return srid, shape, err
+}
+
+// parseWKT parses the well-known text representation of a shape.
+func parseWKT(s string) (Shape, error) {
+	p := wktParser{input: s}
+	shape, err := p.parseShape()
+	if err != nil {
+		return Shape{}, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.input) {
+		return Shape{}, p.errorf()
+	}
+	return shape, nil
+}
+
+type wktParser struct {
+	input string
+	pos   int
+}
+
+func (p *wktParser) errorf() error {
+	return pgerror.Newf(pgcode.InvalidParameterValue,
+		"invalid WKT at position %d: %q", p.pos, p.input)
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.input) {
+		switch p.input[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the next non-space character, or 0 at the end of the input.
+func (p *wktParser) peek() byte {
+	p.skipSpace()
+	if p.pos == len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+// consume consumes the next non-space character if it is c.
+func (p *wktParser) consume(c byte) bool {
+	if p.peek() != c {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *wktParser) expect(c byte) error {
+	if !p.consume(c) {
+		return p.errorf()
+	}
+	return nil
+}
+
+// word returns the next word of letters, in upper case.
+func (p *wktParser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') {
+			break
+		}
+		p.pos++
+	}
+	return strings.ToUpper(p.input[start:p.pos])
+}
+
+func (p *wktParser) parseShape() (Shape, error) {
+	name := p.word()
+	var shape Shape
+	for t := Point; t <= MultiPolygon; t++ {
+		if name == t.String() {
+			shape.Type = t
+		}
+	}
+	if shape.Type == 0 {
+		if name == "GEOMETRYCOLLECTION" {
+			return Shape{}, pgerror.New(pgcode.FeatureNotSupported, "GEOMETRYCOLLECTION is not supported")
+		}
+		return Shape{}, p.errorf()
+	}
+	switch w := p.word(); w {
+	case "":
+	case "EMPTY":
+		return shape, nil
+	case "Z", "M", "ZM":
+		return Shape{}, pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s coordinates are not supported", w)
+	default:
+		return Shape{}, p.errorf()
+	}
+
+	var err error
+	switch shape.Type {
+	case Point:
+		var c Coord
+		if err := p.expect('('); err != nil {
+			return Shape{}, err
+		}
+		if c, err = p.parseCoord(); err != nil {
+			return Shape{}, err
+		}
+		if err := p.expect(')'); err != nil {
+			return Shape{}, err
+		}
+		shape.Parts = [][][]Coord{{{c}}}
+	case LineString:
+		var ring []Coord
+		if ring, err = p.parseRing(); err != nil {
+			return Shape{}, err
+		}
+		shape.Parts = [][][]Coord{{ring}}
+	case Polygon:
+		var rings [][]Coord
+		if rings, err = p.parseRings(); err != nil {
+			return Shape{}, err
+		}
+		shape.Parts = [][][]Coord{rings}
+	case MultiPoint:
+		err = p.parseList(func() error {
+			// The coordinates of the points may or may not be parenthesized.
+			paren := p.consume('(')
+			c, err := p.parseCoord()
+			if err != nil {
+				return err
+			}
+			if paren {
+				if err := p.expect(')'); err != nil {
+					return err
+				}
+			}
+			shape.Parts = append(shape.Parts, [][]Coord{{c}})
+			return nil
+		})
+	case MultiLineString:
+		err = p.parseList(func() error {
+			ring, err := p.parseRing()
+			shape.Parts = append(shape.Parts, [][]Coord{ring})
+			return err
+		})
+	case MultiPolygon:
+		err = p.parseList(func() error {
+			rings, err := p.parseRings()
+			shape.Parts = append(shape.Parts, rings)
+			return err
+		})
+	}
+	if err != nil {
+		return Shape{}, err
+	}
+	return shape, nil
+}
+
+// parseList parses a parenthesized, comma-separated list of elements.
+func (p *wktParser) parseList(elem func() error) error {
+	if err := p.expect('('); err != nil {
+		return err
+	}
+	for {
+		if err := elem(); err != nil {
+			return err
+		}
+		if !p.consume(',') {
+			break
+		}
+	}
+	return p.expect(')')
+}
+
+func (p *wktParser) parseRings() ([][]Coord, error) {
+	var rings [][]Coord
+	err := p.parseList(func() error {
+		ring, err := p.parseRing()
+		rings = append(rings, ring)
+		return err
+	})
+	return rings, err
+}
+
+func (p *wktParser) parseRing() ([]Coord, error) {
+	var ring []Coord
+	err := p.parseList(func() error {
+		c, err := p.parseCoord()
+		ring = append(ring, c)
+		return err
+	})
+	return ring, err
+}
+
+func (p *wktParser) parseCoord() (Coord, error) {
+	x, err := p.parseNumber()
+	if err != nil {
+		return Coord{}, err
+	}
+	y, err := p.parseNumber()
+	if err != nil {
+		return Coord{}, err
+	}
+	if c := p.peek(); c != ',' && c != ')' {
+		return Coord{}, pgerror.New(pgcode.FeatureNotSupported,
+			"only coordinates with two dimensions are supported")
+	}
+	return Coord{X: x, Y: y}, nil
+}
+
+func (p *wktParser) parseNumber() (float64, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if !('0' <= c && c <= '9') && c != '.' && c != '-' && c != '+' && c != 'e' && c != 'E' {
+			break
+		}
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf()
+	}
+	return f, nil
+}
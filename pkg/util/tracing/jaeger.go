@@ -0,0 +1,174 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The types below mirror the JSON format used by the Jaeger UI for importing
+// traces (the same format that the Jaeger query service returns). We don't use
+// the Jaeger client libraries for this; the format is simple enough.
+
+type jaegerKeyValue struct {
+	Key   string `json:"key"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type jaegerReference struct {
+	RefType string `json:"refType"`
+	TraceID string `json:"traceID"`
+	SpanID  string `json:"spanID"`
+}
+
+type jaegerLog struct {
+	// Timestamp in microseconds since the Unix epoch.
+	Timestamp int64            `json:"timestamp"`
+	Fields    []jaegerKeyValue `json:"fields"`
+}
+
+type jaegerSpan struct {
+	TraceID       string            `json:"traceID"`
+	SpanID        string            `json:"spanID"`
+	OperationName string            `json:"operationName"`
+	References    []jaegerReference `json:"references"`
+	// StartTime is in microseconds since the Unix epoch.
+	StartTime int64 `json:"startTime"`
+	// Duration is in microseconds.
+	Duration  int64            `json:"duration"`
+	Tags      []jaegerKeyValue `json:"tags"`
+	Logs      []jaegerLog      `json:"logs"`
+	ProcessID string           `json:"processID"`
+}
+
+type jaegerProcess struct {
+	ServiceName string           `json:"serviceName"`
+	Tags        []jaegerKeyValue `json:"tags"`
+}
+
+type jaegerTrace struct {
+	TraceID   string                   `json:"traceID"`
+	Spans     []jaegerSpan             `json:"spans"`
+	Processes map[string]jaegerProcess `json:"processes"`
+}
+
+type jaegerTraceCollection struct {
+	Data []jaegerTrace `json:"data"`
+}
+
+// jaegerNodeTag is the span tag that identifies the node on which a span was
+// recorded. Spans are grouped into Jaeger "processes" by this tag.
+const jaegerNodeTag = "node"
+
+// ToJaegerJSON returns the trace as JSON that can be imported into Jaeger for
+// visualization. The description is used as the service name of the spans
+// that aren't associated with a node.
+func (r Recording) ToJaegerJSON(description string) (string, error) {
+	if len(r) == 0 {
+		return "", nil
+	}
+
+	cpy := make(Recording, len(r))
+	copy(cpy, r)
+	r = cpy
+	// Sort the spans by start time so that the output is deterministic and the
+	// root span comes first.
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].StartTime.Before(r[j].StartTime)
+	})
+
+	tagsToJaegerTags := func(tags map[string]string) []jaegerKeyValue {
+		res := make([]jaegerKeyValue, 0, len(tags))
+		for k, v := range tags {
+			res = append(res, jaegerKeyValue{Key: k, Type: "STRING", Value: v})
+		}
+		sort.Slice(res, func(i, j int) bool {
+			return res[i].Key < res[j].Key
+		})
+		return res
+	}
+
+	idToHex := func(id uint64) string {
+		return fmt.Sprintf("%016x", id)
+	}
+
+	processes := make(map[string]jaegerProcess)
+	processID := func(sp *RecordedSpan) string {
+		node, hasNode := sp.Tags[jaegerNodeTag]
+		if !hasNode {
+			node = "?"
+		}
+		pid := "p" + strings.Replace(node, " ", "_", -1)
+		if _, ok := processes[pid]; !ok {
+			service := description
+			if hasNode {
+				service = fmt.Sprintf("node %s", node)
+			}
+			processes[pid] = jaegerProcess{
+				ServiceName: service,
+				Tags:        []jaegerKeyValue{},
+			}
+		}
+		return pid
+	}
+
+	traceID := idToHex(r[0].TraceID)
+	spans := make([]jaegerSpan, 0, len(r))
+	for i := range r {
+		sp := &r[i]
+		s := jaegerSpan{
+			TraceID:       traceID,
+			SpanID:        idToHex(sp.SpanID),
+			OperationName: sp.Operation,
+			References:    []jaegerReference{},
+			StartTime:     sp.StartTime.UnixNano() / 1000,
+			Duration:      sp.Duration.Nanoseconds() / 1000,
+			Tags:          tagsToJaegerTags(sp.Tags),
+			Logs:          make([]jaegerLog, 0, len(sp.Logs)),
+			ProcessID:     processID(sp),
+		}
+		if sp.ParentSpanID != 0 {
+			s.References = append(s.References, jaegerReference{
+				RefType: "CHILD_OF",
+				TraceID: traceID,
+				SpanID:  idToHex(sp.ParentSpanID),
+			})
+		}
+		for _, l := range sp.Logs {
+			jl := jaegerLog{
+				Timestamp: l.Time.UnixNano() / 1000,
+				Fields:    make([]jaegerKeyValue, len(l.Fields)),
+			}
+			for j, f := range l.Fields {
+				jl.Fields[j] = jaegerKeyValue{Key: f.Key, Type: "STRING", Value: f.Value}
+			}
+			s.Logs = append(s.Logs, jl)
+		}
+		spans = append(spans, s)
+	}
+
+	data := jaegerTraceCollection{
+		Data: []jaegerTrace{{
+			TraceID:   traceID,
+			Spans:     spans,
+			Processes: processes,
+		}},
+	}
+	j, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(j), nil
+}
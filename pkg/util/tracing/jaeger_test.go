@@ -0,0 +1,65 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tracing
+
+import (
+	"encoding/json"
+	"testing"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+func TestRecordingToJaegerJSON(t *testing.T) {
+	tr := NewTracer()
+	root := tr.StartSpan("root", Recordable)
+	StartRecording(root, SnowballRecording)
+	root.SetTag("node", "1")
+	root.LogKV("x", 1)
+	child := tr.StartSpan("child", opentracing.ChildOf(root.Context()))
+	child.LogKV("y", 2)
+	child.Finish()
+	root.Finish()
+
+	rec := GetRecording(root)
+	js, err := rec.ToJaegerJSON("test statement")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var res jaegerTraceCollection
+	if err := json.Unmarshal([]byte(js), &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Data) != 1 {
+		t.Fatalf("expected a single trace, got %d", len(res.Data))
+	}
+	trace := res.Data[0]
+	if len(trace.Spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(trace.Spans))
+	}
+	rootSpan, childSpan := trace.Spans[0], trace.Spans[1]
+	if rootSpan.OperationName != "root" || childSpan.OperationName != "child" {
+		t.Fatalf("unexpected operations: %s, %s", rootSpan.OperationName, childSpan.OperationName)
+	}
+	if len(rootSpan.References) != 0 {
+		t.Errorf("expected no references for root span, got %v", rootSpan.References)
+	}
+	if len(childSpan.References) != 1 || childSpan.References[0].SpanID != rootSpan.SpanID ||
+		childSpan.References[0].RefType != "CHILD_OF" {
+		t.Errorf("expected child span to reference root span, got %v", childSpan.References)
+	}
+	if len(rootSpan.Logs) != 1 || len(childSpan.Logs) != 1 {
+		t.Errorf("expected one log per span, got %v and %v", rootSpan.Logs, childSpan.Logs)
+	}
+	if p, ok := trace.Processes[rootSpan.ProcessID]; !ok || p.ServiceName != "node 1" {
+		t.Errorf("unexpected process for root span: %v", trace.Processes)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package trigram
+
+import "regexp/syntax"
+
+// RegexpTrigrams returns the trigrams that every string matching a regular
+// expression must contain, in sorted order. Only the literals of the
+// top-level concatenation of the expression are considered, so for example
+// no trigrams are returned for an alternation. Since trigrams are lowercased,
+// the trigrams are also valid for a case-insensitive match.
+func RegexpTrigrams(pattern string) ([]string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	re = re.Simplify()
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+	var trigrams []string
+	for i, sub := range subs {
+		if sub.Op != syntax.OpLiteral {
+			continue
+		}
+		// A regular expression can match anywhere in a string, so a literal is
+		// only known to be at the beginning or at the end of the string if it
+		// is anchored.
+		atStart := i > 0 && (subs[i-1].Op == syntax.OpBeginText || subs[i-1].Op == syntax.OpBeginLine)
+		atEnd := i < len(subs)-1 && (subs[i+1].Op == syntax.OpEndText || subs[i+1].Op == syntax.OpEndLine)
+		trigrams = appendLiteralTrigrams(trigrams, sub.Rune, atStart, atEnd)
+	}
+	return unique(trigrams), nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package trigram implements the trigram operations of the pg_trgm extension
+// of Postgres, which are used to measure the similarity of strings and to
+// index strings so that LIKE and regular expression searches can be
+// accelerated.
+//
+// A trigram is a group of three consecutive characters of a word. Strings are
+// split into words made of letters and digits, and each word is lowercased and
+// padded with two spaces at the beginning and one at the end before its
+// trigrams are extracted. For example, the trigrams of "cat" are "  c", " ca",
+// "cat" and "at ".
+package trigram
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// isWordChar returns whether a character is part of a word.
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// words splits a string into lowercased words.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !isWordChar(r)
+	})
+}
+
+// appendWordTrigrams appends the trigrams of a lowercased word to trigrams.
+// The word is padded on the left and on the right, depending on whether its
+// beginning and its end are known to be word boundaries.
+func appendWordTrigrams(trigrams []string, word []rune, padLeft, padRight bool) []string {
+	padded := make([]rune, 0, len(word)+3)
+	if padLeft {
+		padded = append(padded, ' ', ' ')
+	}
+	padded = append(padded, word...)
+	if padRight {
+		padded = append(padded, ' ')
+	}
+	for i := 0; i+3 <= len(padded); i++ {
+		trigrams = append(trigrams, string(padded[i:i+3]))
+	}
+	return trigrams
+}
+
+// unique sorts trigrams and removes the duplicates.
+func unique(trigrams []string) []string {
+	if len(trigrams) == 0 {
+		return nil
+	}
+	sort.Strings(trigrams)
+	res := trigrams[:1]
+	for _, t := range trigrams[1:] {
+		if t != res[len(res)-1] {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// MakeTrigrams returns the sorted trigrams of the words of a string, without
+// duplicates.
+func MakeTrigrams(s string) []string {
+	return unique(makeOrderedTrigrams(s))
+}
+
+// makeOrderedTrigrams returns the trigrams of the words of a string in the
+// order in which they appear in the string, including duplicates.
+func makeOrderedTrigrams(s string) []string {
+	var trigrams []string
+	for _, w := range words(s) {
+		trigrams = appendWordTrigrams(trigrams, []rune(w), true /* padLeft */, true /* padRight */)
+	}
+	return trigrams
+}
+
+// countCommon returns the number of trigrams that are in both sorted lists.
+func countCommon(a, b []string) int {
+	count := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			count++
+			i++
+			j++
+		}
+	}
+	return count
+}
+
+// Similarity returns a number between 0 and 1 that indicates how similar two
+// strings are, which is the number of trigrams the strings share divided by
+// the number of distinct trigrams of both strings.
+func Similarity(a, b string) float64 {
+	ta, tb := MakeTrigrams(a), MakeTrigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	common := countCommon(ta, tb)
+	return float64(common) / float64(len(ta)+len(tb)-common)
+}
+
+// WordSimilarity returns a number between 0 and 1 that indicates the greatest
+// similarity between the trigrams of a and any continuous extent of the
+// ordered trigrams of b. Unlike Similarity, it doesn't penalize the words of b
+// that are not similar to a, so it is suited to search for a word in a longer
+// text.
+func WordSimilarity(a, b string) float64 {
+	ta, tb := MakeTrigrams(a), makeOrderedTrigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	inA := make(map[string]bool, len(ta))
+	for _, t := range ta {
+		inA[t] = true
+	}
+	var res float64
+	seen := make(map[string]bool, len(tb))
+	for i := range tb {
+		// Grow the extent that starts at tb[i] one trigram at a time, and keep
+		// track of the number of its distinct trigrams and of how many of them
+		// are shared with a.
+		for k := range seen {
+			delete(seen, k)
+		}
+		common, distinct := 0, 0
+		for _, t := range tb[i:] {
+			if seen[t] {
+				continue
+			}
+			seen[t] = true
+			distinct++
+			if inA[t] {
+				common++
+			}
+			if sim := float64(common) / float64(len(ta)+distinct-common); sim > res {
+				res = sim
+			}
+		}
+	}
+	return res
+}
+
+// LikeTrigrams returns the trigrams that every string matching a LIKE pattern
+// must contain, in sorted order. The percent sign and the underscore are
+// wildcards, and a backslash escapes the next character. Since trigrams are
+// lowercased, the trigrams are also valid for an ILIKE pattern.
+func LikeTrigrams(pattern string) []string {
+	var trigrams []string
+	var lit []rune
+	atStart := true
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+			lit = append(lit, r)
+		case r == '\\':
+			escaped = true
+		case r == '%' || r == '_':
+			trigrams = appendLiteralTrigrams(trigrams, lit, atStart, false /* atEnd */)
+			lit = lit[:0]
+			atStart = false
+		default:
+			lit = append(lit, r)
+		}
+	}
+	trigrams = appendLiteralTrigrams(trigrams, lit, atStart, true /* atEnd */)
+	return unique(trigrams)
+}
+
+// appendLiteralTrigrams appends the trigrams of the words of a literal part of
+// a pattern to trigrams. atStart and atEnd indicate whether the literal is
+// known to be at the beginning or at the end of the matching strings; if it
+// isn't, the characters before and after it are unknown, so the first and the
+// last words are only padded if they are followed by a character that doesn't
+// belong to a word.
+func appendLiteralTrigrams(trigrams []string, lit []rune, atStart, atEnd bool) []string {
+	for i := 0; i < len(lit); {
+		if !isWordChar(lit[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(lit) && isWordChar(lit[j]) {
+			j++
+		}
+		word := []rune(strings.ToLower(string(lit[i:j])))
+		trigrams = appendWordTrigrams(trigrams, word, i > 0 || atStart, j < len(lit) || atEnd)
+		i = j
+	}
+	return trigrams
+}
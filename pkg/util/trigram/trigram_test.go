@@ -0,0 +1,155 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package trigram
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMakeTrigrams(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []string
+	}{
+		{``, nil},
+		{`--`, nil},
+		{`a`, []string{`  a`, ` a `}},
+		{`cat`, []string{`  c`, ` ca`, `at `, `cat`}},
+		{`Cat cat`, []string{`  c`, ` ca`, `at `, `cat`}},
+		{`a-b`, []string{`  a`, `  b`, ` a `, ` b `}},
+		{`héé`, []string{`  h`, ` hé`, `héé`, `éé `}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			if res := MakeTrigrams(tc.input); !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("expected %q, got %q", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	testCases := []struct {
+		a, b    string
+		sim     float64
+		wordSim float64
+	}{
+		{a: `word`, b: `two words`, sim: 4.0 / 11, wordSim: 0.8},
+		{a: `word`, b: `word`, sim: 1, wordSim: 1},
+		{a: `word`, b: `WORD!`, sim: 1, wordSim: 1},
+		{a: `word`, b: ``, sim: 0, wordSim: 0},
+		{a: ``, b: `word`, sim: 0, wordSim: 0},
+		{a: `cat`, b: `dog`, sim: 0, wordSim: 0},
+		{a: `cat`, b: `the fat cat`, sim: 4.0 / 11, wordSim: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s,%s", tc.a, tc.b), func(t *testing.T) {
+			if res := Similarity(tc.a, tc.b); math.Abs(res-tc.sim) > 1e-9 {
+				t.Errorf("expected similarity %f, got %f", tc.sim, res)
+			}
+			if res := Similarity(tc.b, tc.a); math.Abs(res-tc.sim) > 1e-9 {
+				t.Errorf("expected symmetric similarity %f, got %f", tc.sim, res)
+			}
+			if res := WordSimilarity(tc.a, tc.b); math.Abs(res-tc.wordSim) > 1e-9 {
+				t.Errorf("expected word similarity %f, got %f", tc.wordSim, res)
+			}
+		})
+	}
+}
+
+func TestLikeTrigrams(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{`%`, nil},
+		{`%ca%`, nil},
+		{`%cat%`, []string{`cat`}},
+		{`cat%`, []string{`  c`, ` ca`, `cat`}},
+		{`%cat`, []string{`at `, `cat`}},
+		{`cat`, []string{`  c`, ` ca`, `at `, `cat`}},
+		{`%Foo bar%`, []string{`  b`, ` ba`, `bar`, `foo`, `oo `}},
+		{`a_c`, []string{`  a`}},
+		{`a\%bc`, []string{`  a`, `  b`, ` a `, ` bc`, `bc `}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			if res := LikeTrigrams(tc.pattern); !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("expected %q, got %q", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestRegexpTrigrams(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{`cat`, []string{`cat`}},
+		{`^cat`, []string{`  c`, ` ca`, `cat`}},
+		{`cat$`, []string{`at `, `cat`}},
+		{`(?i)CAT`, []string{`cat`}},
+		{`cat|dog`, nil},
+		{`ab[cd]efg`, []string{`efg`}},
+		{`fat cats?`, []string{`  c`, ` ca`, `at `, `cat`, `fat`}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			res, err := RegexpTrigrams(tc.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("expected %q, got %q", tc.expected, res)
+			}
+		})
+	}
+
+	if _, err := RegexpTrigrams(`(cat`); err == nil {
+		t.Error("expected an error for an invalid regular expression")
+	}
+}
+
+// TestTrigramsMatch verifies that the trigrams of LIKE patterns and regular
+// expressions are contained in the trigrams of the strings they match.
+func TestTrigramsMatch(t *testing.T) {
+	testCases := []struct {
+		s       string
+		like    string
+		regexps []string
+	}{
+		{s: `the fat cat`, like: `%fat cat`, regexps: []string{`fat cat$`, `at c`, `^the`}},
+		{s: `the fat cat`, like: `the%`, regexps: []string{`t.e`, `(?i)FAT`}},
+		{s: `a Fat-Cat`, like: `a Fat_Cat`, regexps: []string{`Fat-`, `-Cat$`}},
+	}
+	contains := func(all, some []string) bool {
+		return countCommon(all, some) == len(some)
+	}
+	for _, tc := range testCases {
+		all := MakeTrigrams(tc.s)
+		if like := LikeTrigrams(tc.like); !contains(all, like) {
+			t.Errorf("trigrams %q of %s not contained in trigrams %q of %s", like, tc.like, all, tc.s)
+		}
+		for _, pattern := range tc.regexps {
+			re, err := RegexpTrigrams(pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !contains(all, re) {
+				t.Errorf("trigrams %q of %s not contained in trigrams %q of %s", re, pattern, all, tc.s)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// DefaultConfig is the text search configuration used by the functions that
+// don't take an explicit configuration.
+const DefaultConfig = "simple"
+
+// Config is a text search configuration, which determines how a document or
+// a query is split into words and how the words are normalized to lexemes.
+type Config struct {
+	name string
+}
+
+// GetConfig returns the text search configuration with the given name. The
+// name can be qualified with the pg_catalog schema. Currently only the
+// "simple" configuration, which lowercases words and doesn't use stop words
+// or stemming, is supported.
+func GetConfig(name string) (*Config, error) {
+	name = strings.TrimPrefix(strings.ToLower(name), "pg_catalog.")
+	if name != "simple" {
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"text search configuration %q does not exist", name)
+	}
+	return &Config{name: name}, nil
+}
+
+// tokenize splits the text into words. A word is a sequence of letters and
+// digits; all other characters are separators.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// normalize converts a word to a lexeme. It returns false if the word must be
+// ignored.
+func (c *Config) normalize(word string) (string, bool) {
+	word = strings.ToLower(word)
+	if len(word) > MaxLexemeLen {
+		// Like Postgres, silently ignore words that are too long to be indexed.
+		return "", false
+	}
+	return word, true
+}
+
+// ToTSVector converts a document to a vector of normalized lexemes, along
+// with their positions in the document.
+func (c *Config) ToTSVector(document string) TSVector {
+	var lexemes []Lexeme
+	for i, word := range tokenize(document) {
+		lexeme, ok := c.normalize(word)
+		if !ok {
+			continue
+		}
+		pos := i + 1
+		if pos > MaxPos {
+			pos = MaxPos
+		}
+		lexemes = append(lexemes, Lexeme{Word: lexeme, Positions: []Position{{Pos: uint16(pos)}}})
+	}
+	return MakeTSVector(lexemes)
+}
+
+// normalizeTerm converts a query term to the lexemes it consists of.
+func (c *Config) normalizeTerm(term string) []string {
+	var res []string
+	for _, word := range tokenize(term) {
+		if lexeme, ok := c.normalize(word); ok {
+			res = append(res, lexeme)
+		}
+	}
+	return res
+}
+
+// ToTSQuery converts a search query with the tsquery syntax to a query of
+// normalized lexemes. Terms that consist of multiple words are replaced with
+// the AND of the lexemes of the words.
+func (c *Config) ToTSQuery(query string) (TSQuery, error) {
+	return parseTSQuery(query, c.normalizeTerm)
+}
+
+// PlainToTSQuery converts plain text to a query that matches the documents
+// that contain all the lexemes of the text. Operators and punctuation in the
+// text are ignored.
+func (c *Config) PlainToTSQuery(text string) TSQuery {
+	var root *Node
+	for _, lexeme := range c.normalizeTerm(text) {
+		root = combine(And, root, &Node{Op: Term, Lexeme: lexeme})
+	}
+	return TSQuery{Root: root}
+}
@@ -0,0 +1,174 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultRankWeights are the default weights of the D, C, B and A weight
+// classes used by Rank.
+var DefaultRankWeights = [4]float64{0.1, 0.2, 0.4, 1.0}
+
+// Rank normalization flags, which can be combined with a bitwise OR.
+const (
+	// RankNormLogLength divides the rank by 1 + the logarithm of the document
+	// length.
+	RankNormLogLength = 1
+	// RankNormLength divides the rank by the document length.
+	RankNormLength = 2
+	// RankNormUniq divides the rank by the number of unique words in the
+	// document.
+	RankNormUniq = 8
+	// RankNormLogUniq divides the rank by 1 + the logarithm of the number of
+	// unique words in the document.
+	RankNormLogUniq = 16
+	// RankNormRankPlusOne divides the rank by itself + 1.
+	RankNormRankPlusOne = 32
+)
+
+// Rank computes how relevant a document is to a query, based on how often
+// the lexemes of the query appear in the document and on the weights of
+// their positions. It follows the ts_rank algorithm of Postgres: for a query
+// with a top-level AND, the rank also accounts for how close the lexemes are
+// to each other.
+func Rank(weights [4]float64, v TSVector, q TSQuery, normalization int) float64 {
+	terms := uniqueTerms(q)
+	if len(terms) == 0 || len(v) == 0 {
+		return 0
+	}
+	var res float64
+	if q.Root.Op == And && len(terms) > 1 {
+		res = rankAnd(weights, v, terms)
+	} else {
+		res = rankOr(weights, v, terms)
+	}
+
+	if normalization&RankNormLogLength != 0 {
+		res /= math.Log(float64(v.Length())+1) / math.Log(2)
+	}
+	if normalization&RankNormLength != 0 {
+		if l := v.Length(); l > 0 {
+			res /= float64(l)
+		}
+	}
+	if normalization&RankNormUniq != 0 {
+		res /= float64(len(v))
+	}
+	if normalization&RankNormLogUniq != 0 {
+		res /= math.Log(float64(len(v))+1) / math.Log(2)
+	}
+	if normalization&RankNormRankPlusOne != 0 {
+		res /= res + 1
+	}
+	return res
+}
+
+// uniqueTerms returns the Term nodes of the query with distinct lexemes.
+func uniqueTerms(q TSQuery) []*Node {
+	terms := q.Terms()
+	sort.SliceStable(terms, func(i, j int) bool { return terms[i].Lexeme < terms[j].Lexeme })
+	res := terms[:0]
+	for i, t := range terms {
+		if i == 0 || t.Lexeme != terms[i-1].Lexeme {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// termPositions returns the positions of the lexemes that match a term, or
+// nil if there are none. Lexemes without positions are treated as if they
+// had a single position with the default weight.
+func termPositions(v TSVector, t *Node) []Position {
+	var res []Position
+	for _, l := range t.lexemes(v) {
+		if len(l.Positions) == 0 {
+			res = append(res, Position{Pos: MaxPos, Weight: WeightD})
+		} else {
+			res = append(res, l.Positions...)
+		}
+	}
+	return res
+}
+
+// rankOr computes the rank of a document for a query whose terms are
+// considered independently. Each occurrence of a lexeme contributes to the
+// rank according to its weight, with diminishing returns.
+func rankOr(weights [4]float64, v TSVector, terms []*Node) float64 {
+	var res float64
+	for _, t := range terms {
+		positions := termPositions(v, t)
+		if len(positions) == 0 {
+			continue
+		}
+		var resj, wjm float64
+		wjm = -1
+		jm := 0
+		for j, p := range positions {
+			w := weights[p.Weight]
+			resj += w / float64((j+1)*(j+1))
+			if w > wjm {
+				wjm = w
+				jm = j
+			}
+		}
+		// The limit of sum(1/i^2) for i=1..inf is pi^2/6.
+		res += (wjm + resj - wjm/float64((jm+1)*(jm+1))) / 1.64493406685
+	}
+	return res / float64(len(terms))
+}
+
+// rankAnd computes the rank of a document for a query whose terms must all
+// appear in the document. The rank is higher when the lexemes are close to
+// each other.
+func rankAnd(weights [4]float64, v TSVector, terms []*Node) float64 {
+	positions := make([][]Position, len(terms))
+	for i, t := range terms {
+		positions[i] = termPositions(v, t)
+	}
+	res := -1.0
+	for i := range terms {
+		for k := 0; k < i; k++ {
+			for _, l := range positions[i] {
+				for _, p := range positions[k] {
+					dist := int(l.Pos) - int(p.Pos)
+					if dist < 0 {
+						dist = -dist
+					}
+					if dist == 0 {
+						continue
+					}
+					curw := math.Sqrt(weights[l.Weight] * weights[p.Weight] * wordDistance(dist))
+					if res < 0 {
+						res = curw
+					} else {
+						res = 1.0 - (1.0-res)*(1.0-curw)
+					}
+				}
+			}
+		}
+	}
+	if res < 0 {
+		res = 1e-20
+	}
+	return res
+}
+
+// wordDistance returns a factor that decreases with the distance between two
+// lexemes.
+func wordDistance(dist int) float64 {
+	if dist > 100 {
+		return 1e-30
+	}
+	return 1.0 / (1.005 + 0.05*math.Exp(float64(dist)/1.5-2))
+}
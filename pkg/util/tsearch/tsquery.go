@@ -0,0 +1,367 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// Operator is the operator of a tsquery node.
+type Operator uint8
+
+// Tsquery operators, in increasing order of precedence.
+const (
+	// Or matches documents that match either operand (a | b).
+	Or Operator = iota + 1
+	// And matches documents that match both operands (a & b).
+	And
+	// Not matches documents that don't match its operand (!a).
+	Not
+	// Term matches documents that contain a lexeme.
+	Term
+)
+
+// Node is a node of a tsquery expression tree.
+type Node struct {
+	Op Operator
+
+	// Lexeme is the lexeme matched by a Term node.
+	Lexeme string
+	// Prefix is set if a Term node matches all the lexemes that start with
+	// Lexeme (written as 'lexeme':*).
+	Prefix bool
+	// Weights is the set of weights (as a bitmask of 1<<Weight) that the
+	// positions of a lexeme matched by a Term node must have. Zero means that
+	// any weight matches.
+	Weights uint8
+
+	// Left is the operand of a Not node and the left operand of And and Or
+	// nodes.
+	Left *Node
+	// Right is the right operand of And and Or nodes.
+	Right *Node
+}
+
+// TSQuery is a text search query. A query with a nil root is empty and
+// doesn't match any document.
+type TSQuery struct {
+	Root *Node
+}
+
+// Matches returns whether the given document matches the query.
+func (q TSQuery) Matches(v TSVector) bool {
+	if q.Root == nil {
+		return false
+	}
+	return q.Root.matches(v)
+}
+
+func (n *Node) matches(v TSVector) bool {
+	switch n.Op {
+	case And:
+		return n.Left.matches(v) && n.Right.matches(v)
+	case Or:
+		return n.Left.matches(v) || n.Right.matches(v)
+	case Not:
+		return !n.Left.matches(v)
+	}
+	for _, l := range n.lexemes(v) {
+		if n.matchesWeights(&l) {
+			return true
+		}
+	}
+	return false
+}
+
+// lexemes returns the lexemes of the vector that match a Term node, ignoring
+// weights.
+func (n *Node) lexemes(v TSVector) []Lexeme {
+	if n.Prefix {
+		return v.findPrefix(n.Lexeme)
+	}
+	if l, ok := v.Find(n.Lexeme); ok {
+		return []Lexeme{*l}
+	}
+	return nil
+}
+
+// matchesWeights returns whether the lexeme has a position with one of the
+// weights required by a Term node. Lexemes without positions match all
+// weights.
+func (n *Node) matchesWeights(l *Lexeme) bool {
+	if n.Weights == 0 || len(l.Positions) == 0 {
+		return true
+	}
+	for _, p := range l.Positions {
+		if n.Weights&(1<<p.Weight) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Terms returns the Term nodes of the query, in order of appearance.
+func (q TSQuery) Terms() []*Node {
+	var res []*Node
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if n == nil {
+			return
+		}
+		if n.Op == Term {
+			res = append(res, n)
+			return
+		}
+		walk(n.Left)
+		walk(n.Right)
+	}
+	walk(q.Root)
+	return res
+}
+
+// Compare compares two queries by their textual representation, returning
+// -1, 0 or +1.
+func (q TSQuery) Compare(other TSQuery) int {
+	return strings.Compare(q.String(), other.String())
+}
+
+// Size returns the approximate size of the query in memory, in bytes.
+func (q TSQuery) Size() uintptr {
+	var sz uintptr
+	for _, t := range q.Terms() {
+		sz += unsafe.Sizeof(Node{}) + uintptr(len(t.Lexeme))
+	}
+	return sz
+}
+
+// String returns the textual representation of the query, e.g.
+// 'fat' & ( 'rat' | 'cat' ).
+func (q TSQuery) String() string {
+	var buf bytes.Buffer
+	if q.Root != nil {
+		q.Root.format(&buf)
+	}
+	return buf.String()
+}
+
+func (n *Node) format(buf *bytes.Buffer) {
+	switch n.Op {
+	case Term:
+		writeQuotedLexeme(buf, n.Lexeme)
+		if n.Prefix || n.Weights != 0 {
+			buf.WriteByte(':')
+			if n.Prefix {
+				buf.WriteByte('*')
+			}
+			for w := WeightA; ; w-- {
+				if n.Weights&(1<<w) != 0 {
+					buf.WriteString(w.String())
+				}
+				if w == WeightD {
+					break
+				}
+			}
+		}
+
+	case Not:
+		buf.WriteByte('!')
+		n.Left.formatOperand(buf, Not)
+
+	default:
+		n.Left.formatOperand(buf, n.Op)
+		if n.Op == And {
+			buf.WriteString(" & ")
+		} else {
+			buf.WriteString(" | ")
+		}
+		n.Right.formatOperand(buf, n.Op)
+	}
+}
+
+// formatOperand formats an operand of the given operator, adding parentheses
+// if the operand has lower precedence.
+func (n *Node) formatOperand(buf *bytes.Buffer, parent Operator) {
+	if n.Op < parent {
+		buf.WriteString("( ")
+		n.format(buf)
+		buf.WriteString(" )")
+		return
+	}
+	n.format(buf)
+}
+
+// ParseTSQuery parses the textual representation of a query. Terms are
+// combined with the & (AND), | (OR) and ! (NOT) operators and parentheses.
+// Each term is a lexeme, optionally quoted with single quotes, which can be
+// followed by a colon and a * (to match the lexeme as a prefix) and/or a set
+// of weight letters:
+//
+//   'fat' & ( rat:AB | cat:* ) & !dog
+//
+// Note that ParseTSQuery does not normalize the lexemes; use ToTSQuery to
+// convert a search query to a tsquery.
+func ParseTSQuery(s string) (TSQuery, error) {
+	return parseTSQuery(s, nil /* normalize */)
+}
+
+// parseTSQuery parses a query; each lexeme is transformed with normalize if
+// it is not nil. normalize can return zero lexemes (e.g. for a stop word), in
+// which case the term is removed from the query, or multiple lexemes, which
+// are combined with the & operator.
+func parseTSQuery(s string, normalize func(word string) []string) (TSQuery, error) {
+	p := tsQueryParser{tsParser: tsParser{input: s}, normalize: normalize}
+	p.skipSpace()
+	if p.eof() {
+		// An empty query is valid and matches nothing.
+		return TSQuery{}, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return TSQuery{}, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return TSQuery{}, p.syntaxError("tsquery")
+	}
+	return TSQuery{Root: root}, nil
+}
+
+type tsQueryParser struct {
+	tsParser
+	normalize func(word string) []string
+}
+
+func (p *tsQueryParser) parseOr() (*Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.eof() || p.peek() != '|' {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = combine(Or, left, right)
+	}
+}
+
+func (p *tsQueryParser) parseAnd() (*Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return left, nil
+		}
+		switch p.peek() {
+		case '&':
+		case '<':
+			return nil, unimplemented.NewWithIssue(7821, "tsquery phrase operators are not supported")
+		default:
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = combine(And, left, right)
+	}
+}
+
+func (p *tsQueryParser) parseUnary() (*Node, error) {
+	p.skipSpace()
+	if p.eof() {
+		return nil, p.syntaxError("tsquery")
+	}
+	switch p.peek() {
+	case '!':
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil || operand == nil {
+			return nil, err
+		}
+		return &Node{Op: Not, Left: operand}, nil
+
+	case '(':
+		p.next()
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.eof() || p.next() != ')' {
+			return nil, p.syntaxError("tsquery")
+		}
+		return n, nil
+
+	case '&', '|', ')', ':', '<':
+		return nil, p.syntaxError("tsquery")
+	}
+	return p.parseTerm()
+}
+
+func (p *tsQueryParser) parseTerm() (*Node, error) {
+	word, err := p.lexeme(func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("&|!():<", r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var prefix bool
+	var weights uint8
+	if !p.eof() && p.peek() == ':' {
+		p.next()
+		for !p.eof() {
+			if p.peek() == '*' {
+				prefix = true
+			} else if w, ok := weightFromRune(p.peek()); ok {
+				weights |= 1 << w
+			} else {
+				break
+			}
+			p.next()
+		}
+	}
+	words := []string{word}
+	if p.normalize != nil {
+		words = p.normalize(word)
+	}
+	var res *Node
+	for _, w := range words {
+		res = combine(And, res, &Node{Op: Term, Lexeme: w, Prefix: prefix, Weights: weights})
+	}
+	return res, nil
+}
+
+// combine returns a node that applies a binary operator to two operands,
+// either of which can be nil when it was removed during normalization.
+func combine(op Operator, left, right *Node) *Node {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	return &Node{Op: op, Left: left, Right: right}
+}
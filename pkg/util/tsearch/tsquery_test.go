@@ -0,0 +1,169 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+)
+
+func TestParseTSQuery(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{input: ``, expected: ``},
+		{input: `fat`, expected: `'fat'`},
+		{input: `fat & rat`, expected: `'fat' & 'rat'`},
+		{input: `fat&rat|cat`, expected: `'fat' & 'rat' | 'cat'`},
+		{input: `fat & (rat | cat)`, expected: `'fat' & ( 'rat' | 'cat' )`},
+		{input: `!fat & !(rat | cat)`, expected: `!'fat' & !( 'rat' | 'cat' )`},
+		{input: `!!fat`, expected: `!!'fat'`},
+		{input: `super:* & 'cat''s':ab`, expected: `'super':* & 'cat''s':AB`},
+		{input: `a:*Dc`, expected: `'a':*CD`},
+		{input: `fat rat`, err: `syntax error in tsquery`},
+		{input: `fat &`, err: `syntax error in tsquery`},
+		{input: `(fat`, err: `syntax error in tsquery`},
+		{input: `fat)`, err: `syntax error in tsquery`},
+		{input: `fat <-> rat`, err: `tsquery phrase operators are not supported`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.input)
+			if !testutils.IsError(err, tc.err) {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if s := q.String(); s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+
+			// The textual representation must round-trip.
+			q2, err := ParseTSQuery(q.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q.Compare(q2) != 0 {
+				t.Fatalf("%s doesn't round-trip, got %s", q, q2)
+			}
+		})
+	}
+}
+
+func TestTSQueryMatches(t *testing.T) {
+	v, err := ParseTSVector(`fat:1A rat:2 cats:3C stripped`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		query    string
+		expected bool
+	}{
+		{``, false},
+		{`fat`, true},
+		{`dog`, false},
+		{`fat & rat`, true},
+		{`fat & dog`, false},
+		{`fat | dog`, true},
+		{`!dog`, true},
+		{`!fat`, false},
+		{`fat & !(rat | dog)`, false},
+		{`cat`, false},
+		{`cat:*`, true},
+		{`fat:A`, true},
+		{`fat:BC`, false},
+		{`cat:*C`, true},
+		{`stripped:A`, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res := q.Matches(v); res != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestToTSQuery(t *testing.T) {
+	cfg, err := GetConfig("simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		query    string
+		expected string
+		plain    string
+	}{
+		{`Fat & Rats`, `'fat' & 'rats'`, `'fat' & 'rats'`},
+		{`fat-rats | cat:*`, `'fat' & 'rats' | 'cat':*`, `'fat' & 'rats' & 'cat'`},
+		{`'--' & cat`, `'cat'`, `'cat'`},
+		{`!'--'`, ``, ``},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := cfg.ToTSQuery(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := q.String(); s != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, s)
+			}
+			if s := cfg.PlainToTSQuery(tc.query).String(); s != tc.plain {
+				t.Errorf("expected plain query %s, got %s", tc.plain, s)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	cfg, err := GetConfig("simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rank := func(doc, query string) float64 {
+		q, err := cfg.ToTSQuery(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return Rank(DefaultRankWeights, cfg.ToTSVector(doc), q, 0 /* normalization */)
+	}
+
+	if r := rank("the fat cat", "dog"); r != 0 {
+		t.Errorf("expected 0 for a document that doesn't match, got %f", r)
+	}
+	if r1, r2 := rank("the fat cat", "cat"), rank("the fat cat and the cat", "cat"); r1 >= r2 {
+		t.Errorf("expected more occurrences to rank higher: %f >= %f", r1, r2)
+	}
+	if r1, r2 := rank("fat and some other words before the cat", "fat & cat"),
+		rank("the fat cat", "fat & cat"); r1 >= r2 {
+		t.Errorf("expected closer lexemes to rank higher: %f >= %f", r1, r2)
+	}
+
+	v := cfg.ToTSVector("the fat cat")
+	q, err := cfg.ToTSQuery("cat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r1, r2 := Rank(DefaultRankWeights, v, q, 0), Rank(DefaultRankWeights, v.SetWeight(WeightA), q, 0); r1 >= r2 {
+		t.Errorf("expected higher weights to rank higher: %f >= %f", r1, r2)
+	}
+	if r1, r2 := Rank(DefaultRankWeights, v, q, 0), Rank(DefaultRankWeights, v, q, RankNormLength); r2 != r1/3 {
+		t.Errorf("expected rank normalized by length to be %f, got %f", r1/3, r2)
+	}
+}
@@ -0,0 +1,479 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package tsearch implements the full text search types tsvector and tsquery,
+// along with the functions that create, match and rank them. The textual
+// representations of both types follow PostgreSQL.
+package tsearch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// MaxLexemeLen is the maximum length of a lexeme, in bytes.
+	MaxLexemeLen = 2047
+
+	// MaxPos is the maximum position of a lexeme in a document. Larger
+	// positions are silently clamped to this value.
+	MaxPos = 16383
+)
+
+// Weight is the weight of a lexeme position. Weights are used to mark
+// lexemes coming from different parts of a document (e.g. the title or the
+// body) and influence the rank of the document.
+type Weight uint8
+
+// Weights, in increasing order of importance. D is the default weight and is
+// omitted from the textual representation.
+const (
+	WeightD Weight = iota
+	WeightC
+	WeightB
+	WeightA
+)
+
+// String returns the single-letter name of the weight.
+func (w Weight) String() string {
+	return string("DCBA"[w&3])
+}
+
+func weightFromRune(r rune) (Weight, bool) {
+	switch unicode.ToUpper(r) {
+	case 'A':
+		return WeightA, true
+	case 'B':
+		return WeightB, true
+	case 'C':
+		return WeightC, true
+	case 'D':
+		return WeightD, true
+	}
+	return 0, false
+}
+
+// ParseWeight parses the single-letter name of a weight (A, B, C or D, in
+// upper or lower case).
+func ParseWeight(s string) (Weight, error) {
+	if len(s) == 1 {
+		if w, ok := weightFromRune(rune(s[0])); ok {
+			return w, nil
+		}
+	}
+	return 0, pgerror.Newf(pgcode.InvalidParameterValue, "unrecognized weight: %q", s)
+}
+
+// Position is the position of a lexeme in a document, along with its weight.
+type Position struct {
+	Pos    uint16
+	Weight Weight
+}
+
+// Lexeme is a normalized word of a document, along with the positions at
+// which it appears. A lexeme of a stripped tsvector has no positions.
+type Lexeme struct {
+	Word      string
+	Positions []Position
+}
+
+// TSVector is a document represented as a sorted list of distinct lexemes.
+type TSVector []Lexeme
+
+// Find returns the lexeme with the given word, if it is part of the vector.
+func (v TSVector) Find(word string) (*Lexeme, bool) {
+	i := sort.Search(len(v), func(i int) bool { return v[i].Word >= word })
+	if i < len(v) && v[i].Word == word {
+		return &v[i], true
+	}
+	return nil, false
+}
+
+// findPrefix returns the lexemes that start with the given prefix.
+func (v TSVector) findPrefix(prefix string) []Lexeme {
+	i := sort.Search(len(v), func(i int) bool { return v[i].Word >= prefix })
+	j := i
+	for j < len(v) && strings.HasPrefix(v[j].Word, prefix) {
+		j++
+	}
+	return v[i:j]
+}
+
+// Length returns the number of positions in the document, counting each
+// lexeme without positions once.
+func (v TSVector) Length() int {
+	n := 0
+	for i := range v {
+		if len(v[i].Positions) == 0 {
+			n++
+		} else {
+			n += len(v[i].Positions)
+		}
+	}
+	return n
+}
+
+// Compare compares two vectors, returning -1, 0 or +1. Vectors are ordered by
+// their lexemes, then by the positions of the lexemes.
+func (v TSVector) Compare(other TSVector) int {
+	for i := 0; i < len(v) && i < len(other); i++ {
+		if c := strings.Compare(v[i].Word, other[i].Word); c != 0 {
+			return c
+		}
+		a, b := v[i].Positions, other[i].Positions
+		for j := 0; j < len(a) && j < len(b); j++ {
+			if a[j] != b[j] {
+				if a[j].Pos != b[j].Pos {
+					return compareInts(int(a[j].Pos), int(b[j].Pos))
+				}
+				return compareInts(int(a[j].Weight), int(b[j].Weight))
+			}
+		}
+		if c := compareInts(len(a), len(b)); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(v), len(other))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Size returns the approximate size of the vector in memory, in bytes.
+func (v TSVector) Size() uintptr {
+	sz := uintptr(len(v)) * unsafe.Sizeof(Lexeme{})
+	for i := range v {
+		sz += uintptr(len(v[i].Word)) + uintptr(len(v[i].Positions))*unsafe.Sizeof(Position{})
+	}
+	return sz
+}
+
+// String returns the textual representation of the vector, e.g.
+// 'fat':2 'rat':3A.
+func (v TSVector) String() string {
+	var buf bytes.Buffer
+	for i := range v {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		writeQuotedLexeme(&buf, v[i].Word)
+		for j, p := range v[i].Positions {
+			if j == 0 {
+				buf.WriteByte(':')
+			} else {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.Itoa(int(p.Pos)))
+			if p.Weight != WeightD {
+				buf.WriteString(p.Weight.String())
+			}
+		}
+	}
+	return buf.String()
+}
+
+// writeQuotedLexeme writes the lexeme surrounded by single quotes, doubling
+// any quotes and backslashes it contains.
+func writeQuotedLexeme(buf *bytes.Buffer, word string) {
+	buf.WriteByte('\'')
+	for _, r := range word {
+		switch r {
+		case '\'', '\\':
+			buf.WriteRune(r)
+		}
+		buf.WriteRune(r)
+	}
+	buf.WriteByte('\'')
+}
+
+// MakeTSVector builds a vector from a list of lexemes that is not necessarily
+// sorted and that can contain duplicates. The positions of duplicate lexemes
+// are merged.
+func MakeTSVector(lexemes []Lexeme) TSVector {
+	if len(lexemes) == 0 {
+		return TSVector{}
+	}
+	sort.SliceStable(lexemes, func(i, j int) bool { return lexemes[i].Word < lexemes[j].Word })
+	res := lexemes[:1]
+	for _, l := range lexemes[1:] {
+		last := &res[len(res)-1]
+		if l.Word == last.Word {
+			last.Positions = append(last.Positions, l.Positions...)
+			continue
+		}
+		res = append(res, l)
+	}
+	for i := range res {
+		res[i].Positions = normalizePositions(res[i].Positions)
+	}
+	return TSVector(res)
+}
+
+// normalizePositions sorts the positions and removes duplicates, keeping the
+// highest weight of a duplicated position.
+func normalizePositions(positions []Position) []Position {
+	if len(positions) < 2 {
+		return positions
+	}
+	sort.SliceStable(positions, func(i, j int) bool { return positions[i].Pos < positions[j].Pos })
+	res := positions[:1]
+	for _, p := range positions[1:] {
+		last := &res[len(res)-1]
+		if p.Pos == last.Pos {
+			if p.Weight > last.Weight {
+				last.Weight = p.Weight
+			}
+			continue
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
+// ParseTSVector parses the textual representation of a vector. Lexemes are
+// separated by whitespace and can be quoted with single quotes; each lexeme
+// can be followed by a colon and a comma-separated list of positions, each
+// optionally followed by a weight letter:
+//
+//   a fat:2,4 'cat''s':5A
+//
+// Note that ParseTSVector does not normalize the lexemes; use ToTSVector to
+// convert a document to a vector.
+func ParseTSVector(s string) (TSVector, error) {
+	p := tsParser{input: s}
+	var lexemes []Lexeme
+	for {
+		p.skipSpace()
+		if p.eof() {
+			break
+		}
+		word, err := p.lexeme(func(r rune) bool { return unicode.IsSpace(r) || r == ':' })
+		if err != nil {
+			return nil, err
+		}
+		l := Lexeme{Word: word}
+		if !p.eof() && p.peek() == ':' {
+			p.next()
+			if l.Positions, err = p.positions(); err != nil {
+				return nil, err
+			}
+		}
+		if !p.eof() && !unicode.IsSpace(p.peek()) {
+			return nil, p.syntaxError("tsvector")
+		}
+		lexemes = append(lexemes, l)
+	}
+	return MakeTSVector(lexemes), nil
+}
+
+// tsParser is a simple scanner over the textual representation of tsvector
+// and tsquery values.
+type tsParser struct {
+	input string
+	pos   int
+}
+
+func (p *tsParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *tsParser) peek() rune {
+	r, _ := utf8.DecodeRuneInString(p.input[p.pos:])
+	return r
+}
+
+func (p *tsParser) next() rune {
+	r, sz := utf8.DecodeRuneInString(p.input[p.pos:])
+	p.pos += sz
+	return r
+}
+
+func (p *tsParser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.next()
+	}
+}
+
+func (p *tsParser) syntaxError(typ string) error {
+	return pgerror.Newf(pgcode.Syntax, "syntax error in %s: %q", typ, p.input)
+}
+
+// lexeme scans a quoted or unquoted lexeme. An unquoted lexeme ends at the
+// first rune for which isEnd returns true. Backslashes escape the following
+// rune in both forms, and quoted lexemes can also contain doubled quotes.
+func (p *tsParser) lexeme(isEnd func(r rune) bool) (string, error) {
+	var buf strings.Builder
+	if p.peek() == '\'' {
+		p.next()
+		for {
+			if p.eof() {
+				return "", pgerror.Newf(pgcode.Syntax, "unterminated quoted string in %q", p.input)
+			}
+			r := p.next()
+			if r == '\\' && !p.eof() {
+				r = p.next()
+			} else if r == '\'' {
+				if p.eof() || p.peek() != '\'' {
+					break
+				}
+				p.next()
+			}
+			buf.WriteRune(r)
+		}
+	} else {
+		for !p.eof() && !isEnd(p.peek()) {
+			r := p.next()
+			if r == '\\' && !p.eof() {
+				r = p.next()
+			}
+			buf.WriteRune(r)
+		}
+	}
+	word := buf.String()
+	if word == "" {
+		return "", pgerror.Newf(pgcode.Syntax, "syntax error: empty lexeme in %q", p.input)
+	}
+	if len(word) > MaxLexemeLen {
+		return "", pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"word is too long (%d bytes, max %d bytes)", len(word), MaxLexemeLen)
+	}
+	return word, nil
+}
+
+// positions scans a comma-separated list of positions, e.g. 1,3A,5.
+func (p *tsParser) positions() ([]Position, error) {
+	var res []Position
+	for {
+		start := p.pos
+		for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+			p.next()
+		}
+		if start == p.pos {
+			return nil, p.syntaxError("tsvector")
+		}
+		n, err := strconv.Atoi(p.input[start:p.pos])
+		if err != nil || n == 0 {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"wrong position info in tsvector: %q", p.input)
+		}
+		if n > MaxPos {
+			n = MaxPos
+		}
+		pos := Position{Pos: uint16(n)}
+		if !p.eof() {
+			if w, ok := weightFromRune(p.peek()); ok {
+				p.next()
+				pos.Weight = w
+			}
+		}
+		res = append(res, pos)
+		if p.eof() || p.peek() != ',' {
+			return res, nil
+		}
+		p.next()
+	}
+}
+
+// Encode appends the binary encoding of the vector to the given buffer.
+func (v TSVector) Encode(appendTo []byte) []byte {
+	appendTo = appendUvarint(appendTo, uint64(len(v)))
+	for i := range v {
+		appendTo = appendUvarint(appendTo, uint64(len(v[i].Word)))
+		appendTo = append(appendTo, v[i].Word...)
+		appendTo = appendUvarint(appendTo, uint64(len(v[i].Positions)))
+		for _, p := range v[i].Positions {
+			appendTo = appendUvarint(appendTo, uint64(p.Pos)<<2|uint64(p.Weight))
+		}
+	}
+	return appendTo
+}
+
+func appendUvarint(appendTo []byte, n uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	sz := binary.PutUvarint(buf[:], n)
+	return append(appendTo, buf[:sz]...)
+}
+
+// DecodeTSVector decodes a vector encoded with Encode.
+func DecodeTSVector(b []byte) (TSVector, error) {
+	errCorrupt := errors.AssertionFailedf("corrupt tsvector encoding")
+	readUvarint := func() (uint64, bool) {
+		n, sz := binary.Uvarint(b)
+		if sz <= 0 {
+			return 0, false
+		}
+		b = b[sz:]
+		return n, true
+	}
+	n, ok := readUvarint()
+	if !ok || n > uint64(len(b)) {
+		return nil, errCorrupt
+	}
+	v := make(TSVector, n)
+	for i := range v {
+		wordLen, ok := readUvarint()
+		if !ok || wordLen > uint64(len(b)) {
+			return nil, errCorrupt
+		}
+		v[i].Word = string(b[:wordLen])
+		b = b[wordLen:]
+		numPos, ok := readUvarint()
+		if !ok || numPos > uint64(len(b)) {
+			return nil, errCorrupt
+		}
+		if numPos > 0 {
+			v[i].Positions = make([]Position, numPos)
+		}
+		for j := range v[i].Positions {
+			p, ok := readUvarint()
+			if !ok {
+				return nil, errCorrupt
+			}
+			v[i].Positions[j] = Position{Pos: uint16(p >> 2), Weight: Weight(p & 3)}
+		}
+	}
+	if len(b) != 0 {
+		return nil, errCorrupt
+	}
+	return v, nil
+}
+
+// SetWeight returns a copy of the vector with the weight of all positions
+// set to the given weight.
+func (v TSVector) SetWeight(w Weight) TSVector {
+	res := make(TSVector, len(v))
+	for i := range v {
+		res[i].Word = v[i].Word
+		if len(v[i].Positions) > 0 {
+			res[i].Positions = make([]Position, len(v[i].Positions))
+			for j, p := range v[i].Positions {
+				res[i].Positions[j] = Position{Pos: p.Pos, Weight: w}
+			}
+		}
+	}
+	return res
+}
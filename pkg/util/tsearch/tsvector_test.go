@@ -0,0 +1,126 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+)
+
+func TestParseTSVector(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{input: ``, expected: ``},
+		{input: `a`, expected: `'a'`},
+		{input: `  fat cat  sat  `, expected: `'cat' 'fat' 'sat'`},
+		{input: `fat:2 cat:3,1 fat:1`, expected: `'cat':1,3 'fat':1,2`},
+		{input: `a:1A,2b,3c,4D,5`, expected: `'a':1A,2B,3C,4,5`},
+		{input: `a:1 a:1A`, expected: `'a':1A`},
+		{input: `'with space':1 'it''s' 'back\\slash'`, expected: `'back\\slash' 'it''s' 'with space':1`},
+		{input: `a:99999`, expected: `'a':16383`},
+		{input: `a\:b`, expected: `'a:b'`},
+		{input: `a:`, err: `syntax error in tsvector`},
+		{input: `a:x`, err: `syntax error in tsvector`},
+		{input: `a:0`, err: `wrong position info in tsvector`},
+		{input: `a:1x`, err: `syntax error in tsvector`},
+		{input: `'a`, err: `unterminated quoted string`},
+		{input: `''`, err: `empty lexeme`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := ParseTSVector(tc.input)
+			if !testutils.IsError(err, tc.err) {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if s := v.String(); s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+
+			// The textual representation must round-trip.
+			v2, err := ParseTSVector(v.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.Compare(v2) != 0 {
+				t.Fatalf("%s doesn't round-trip, got %s", v, v2)
+			}
+
+			// So must the encoding.
+			v3, err := DecodeTSVector(v.Encode(nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.Compare(v3) != 0 {
+				t.Fatalf("%s doesn't round-trip through the encoding, got %s", v, v3)
+			}
+		})
+	}
+}
+
+func TestTSVectorCompare(t *testing.T) {
+	ordered := []string{``, `a`, `a b`, `a:1`, `a:1,2`, `a:1A`, `a:2`, `b`}
+	for i := range ordered {
+		for j := range ordered {
+			a, err := ParseTSVector(ordered[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := ParseTSVector(ordered[j])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c, expected := a.Compare(b), compareInts(i, j); c != expected {
+				t.Errorf("comparing %s and %s: expected %d, got %d", a, b, expected, c)
+			}
+		}
+	}
+}
+
+func TestToTSVector(t *testing.T) {
+	cfg, err := GetConfig("pg_catalog.Simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := cfg.ToTSVector("The fat cats -- and the FAT rats, 2 of them.")
+	const expected = `'2':8 'and':4 'cats':3 'fat':2,6 'of':9 'rats':7 'the':1,5 'them':10`
+	if s := v.String(); s != expected {
+		t.Errorf("expected %s, got %s", expected, s)
+	}
+
+	if _, err := GetConfig("english"); !testutils.IsError(err, `text search configuration "english" does not exist`) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestParseWeight(t *testing.T) {
+	for _, s := range []string{"A", "b", "C", "d"} {
+		w, err := ParseWeight(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.String() != strings.ToUpper(s) {
+			t.Errorf("expected weight %s, got %s", strings.ToUpper(s), w)
+		}
+	}
+	for _, s := range []string{"", "E", "AB"} {
+		if _, err := ParseWeight(s); !testutils.IsError(err, "unrecognized weight") {
+			t.Errorf("expected error for %q, got %v", s, err)
+		}
+	}
+}